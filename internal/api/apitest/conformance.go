@@ -0,0 +1,133 @@
+package apitest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Factory returns an authenticated Client backed by a fresh, empty data store.
+// Implementations should register any teardown with t.Cleanup.
+type Factory func(t *testing.T) api.Client
+
+// RunClientTests exercises the behavior every api.Client implementation must provide.
+func RunClientTests(t *testing.T, newClient Factory) {
+	t.Run("RouteLifecycle", func(t *testing.T) {
+		client := newClient(t)
+		ctx := context.Background()
+
+		route := &models.RouteObject{
+			Route:  "192.0.2.0/24",
+			Origin: "AS64500",
+			Descr:  []string{"conformance"},
+			MntBy:  []string{"MAINT-TEST"},
+			Source: "RADB",
+		}
+
+		if err := client.CreateRoute(ctx, route); err != nil {
+			t.Fatalf("CreateRoute() failed: %v", err)
+		}
+
+		got, err := client.GetRoute(ctx, route.Route, route.Origin)
+		if err != nil {
+			t.Fatalf("GetRoute() failed: %v", err)
+		}
+		if got.ID() != route.ID() {
+			t.Errorf("Expected route %s, got %s", route.ID(), got.ID())
+		}
+
+		list, err := client.ListRoutes(ctx, map[string]string{"origin": "AS64500"})
+		if err != nil {
+			t.Fatalf("ListRoutes() failed: %v", err)
+		}
+		if list.Count != 1 {
+			t.Errorf("Expected 1 route, got %d", list.Count)
+		}
+
+		got.Descr = []string{"updated"}
+		if err := client.UpdateRoute(ctx, got); err != nil {
+			t.Fatalf("UpdateRoute() failed: %v", err)
+		}
+
+		updated, err := client.GetRoute(ctx, route.Route, route.Origin)
+		if err != nil {
+			t.Fatalf("GetRoute() after update failed: %v", err)
+		}
+		if len(updated.Descr) != 1 || updated.Descr[0] != "updated" {
+			t.Errorf("Update not applied, descr = %v", updated.Descr)
+		}
+
+		if err := client.DeleteRoute(ctx, route.Route, route.Origin); err != nil {
+			t.Fatalf("DeleteRoute() failed: %v", err)
+		}
+
+		if _, err := client.GetRoute(ctx, route.Route, route.Origin); !errors.Is(err, api.ErrNotFound) {
+			t.Errorf("GetRoute() of a deleted route = %v, want api.ErrNotFound", err)
+		}
+	})
+
+	t.Run("RejectInvalidRoute", func(t *testing.T) {
+		client := newClient(t)
+
+		route := &models.RouteObject{
+			Route:  "192.0.2.1/24",
+			Origin: "AS64500",
+			MntBy:  []string{"MAINT-TEST"},
+			Source: "RADB",
+		}
+
+		if err := client.CreateRoute(context.Background(), route); err == nil {
+			t.Error("Expected error creating a route with host bits set")
+		}
+	})
+
+	t.Run("ContactLifecycle", func(t *testing.T) {
+		client := newClient(t)
+		ctx := context.Background()
+
+		contact := &models.Contact{
+			Name:  "Conformance Test",
+			Email: "noc@example.com",
+			Role:  models.ContactRoleTech,
+		}
+
+		if err := client.CreateContact(ctx, contact); err != nil {
+			t.Fatalf("CreateContact() failed: %v", err)
+		}
+		if contact.ID == "" {
+			t.Fatal("CreateContact() should assign an ID")
+		}
+
+		list, err := client.ListContacts(ctx)
+		if err != nil {
+			t.Fatalf("ListContacts() failed: %v", err)
+		}
+		if list.Count != 1 {
+			t.Errorf("Expected 1 contact, got %d", list.Count)
+		}
+
+		contact.Email = "ops@example.com"
+		if err := client.UpdateContact(ctx, contact); err != nil {
+			t.Fatalf("UpdateContact() failed: %v", err)
+		}
+
+		got, err := client.GetContact(ctx, contact.ID)
+		if err != nil {
+			t.Fatalf("GetContact() failed: %v", err)
+		}
+		if got.Email != "ops@example.com" {
+			t.Errorf("Update not applied, email = %s", got.Email)
+		}
+
+		if err := client.DeleteContact(ctx, contact.ID); err != nil {
+			t.Fatalf("DeleteContact() failed: %v", err)
+		}
+
+		if _, err := client.GetContact(ctx, contact.ID); !errors.Is(err, api.ErrNotFound) {
+			t.Errorf("GetContact() of a deleted contact = %v, want api.ErrNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,317 @@
+// Package apitest provides an in-memory RADb API server and a conformance
+// suite for api.Client implementations.
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Server is an httptest server emulating the subset of the RADb REST API
+// used by the client. All state is kept in memory.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	routes      map[string]models.RouteObject
+	contacts    map[string]models.Contact
+	nextContact int
+
+	// Username and Password, when set, are required as HTTP Basic Auth on every request.
+	Username string
+	Password string
+}
+
+// NewServer starts a new in-memory RADb API server.
+// Callers must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		routes:   make(map[string]models.RouteObject),
+		contacts: make(map[string]models.Contact),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddRoute seeds a route object.
+func (s *Server) AddRoute(route models.RouteObject) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[route.ID()] = route
+}
+
+// AddContact seeds a contact.
+func (s *Server) AddContact(contact models.Contact) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contacts[contact.ID] = contact
+}
+
+// handle dispatches a request based on its path segments.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if s.Username != "" || s.Password != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.Username || password != s.Password {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	segments := splitPath(r.URL.EscapedPath())
+	if len(segments) < 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch segments[1] {
+	case "route":
+		s.handleRoute(w, r, segments[2:])
+	case "contact":
+		s.handleContact(w, r, segments[2:])
+	case "validate":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"valid": true, "asn": r.URL.Query().Get("asn")})
+	case "search":
+		s.handleSearch(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleRoute serves /{source}/route and /{source}/route/{prefix}/{asn}.
+func (s *Server) handleRoute(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, paginate(w, r, s.filterRoutes(r.URL.Query())))
+		case http.MethodPost:
+			var route models.RouteObject
+			if err := json.NewDecoder(r.Body).Decode(&route); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if _, exists := s.routes[route.ID()]; exists {
+				http.Error(w, "route already exists", http.StatusConflict)
+				return
+			}
+			s.routes[route.ID()] = route
+			writeJSON(w, http.StatusCreated, route)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if len(rest) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+
+	id := fmt.Sprintf("%s-%s", rest[0], rest[1])
+	route, exists := s.routes[id]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, route)
+	case http.MethodPut:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		var updated models.RouteObject
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.routes[id] = updated
+		writeJSON(w, http.StatusOK, updated)
+	case http.MethodDelete:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		delete(s.routes, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// filterRoutes returns routes matching the query filters, sorted by ID.
+func (s *Server) filterRoutes(query url.Values) []models.RouteObject {
+	routes := make([]models.RouteObject, 0, len(s.routes))
+	for _, route := range s.routes {
+		if prefix := query.Get("prefix"); prefix != "" && route.Route != prefix {
+			continue
+		}
+		if origin := query.Get("origin"); origin != "" && route.Origin != origin {
+			continue
+		}
+		if mntBy := query.Get("mnt-by"); mntBy != "" && !containsString(route.MntBy, mntBy) {
+			continue
+		}
+		routes = append(routes, route)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].ID() < routes[j].ID()
+	})
+
+	return routes
+}
+
+// handleContact serves /{source}/contact and /{source}/contact/{id}.
+func (s *Server) handleContact(w http.ResponseWriter, r *http.Request, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			contacts := make([]models.Contact, 0, len(s.contacts))
+			for _, contact := range s.contacts {
+				contacts = append(contacts, contact)
+			}
+			sort.Slice(contacts, func(i, j int) bool {
+				return contacts[i].ID < contacts[j].ID
+			})
+			writeJSON(w, http.StatusOK, paginate(w, r, contacts))
+		case http.MethodPost:
+			var contact models.Contact
+			if err := json.NewDecoder(r.Body).Decode(&contact); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if contact.ID == "" {
+				s.nextContact++
+				contact.ID = fmt.Sprintf("CONTACT-%d", s.nextContact)
+			}
+			s.contacts[contact.ID] = contact
+			writeJSON(w, http.StatusCreated, contact)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if len(rest) != 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	id := rest[0]
+	contact, exists := s.contacts[id]
+	if !exists {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, contact)
+	case http.MethodPut:
+		var updated models.Contact
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		updated.ID = id
+		s.contacts[id] = updated
+		writeJSON(w, http.StatusOK, updated)
+	case http.MethodDelete:
+		delete(s.contacts, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSearch serves /{source}/search with a substring match over routes.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("query-string")
+
+	results := make([]map[string]interface{}, 0)
+	for _, route := range s.routes {
+		if strings.Contains(route.Route, query) || route.Origin == query || containsString(route.MntBy, query) {
+			results = append(results, map[string]interface{}{
+				"route":  route.Route,
+				"origin": route.Origin,
+			})
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+		"count":   len(results),
+		"query":   query,
+	})
+}
+
+// paginate applies offset and limit query parameters to a slice. When a
+// limit leaves items unreturned, a Link header with rel="next" points at the
+// following page.
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) []T {
+	query := r.URL.Query()
+
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit < len(items) {
+		items = items[:limit]
+
+		next := *r.URL
+		query.Set("offset", strconv.Itoa(offset+limit))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI()))
+	}
+
+	return items
+}
+
+// splitPath splits an escaped URL path into unescaped segments.
+func splitPath(escaped string) []string {
+	var segments []string
+	for _, part := range strings.Split(strings.Trim(escaped, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		unescaped, err := url.PathUnescape(part)
+		if err != nil {
+			unescaped = part
+		}
+		segments = append(segments, unescaped)
+	}
+	return segments
+}
+
+// writeJSON writes a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// containsString reports whether a slice contains a string.
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
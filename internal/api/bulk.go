@@ -6,25 +6,25 @@ import (
 	"sync"
 
 	"github.com/bss/radb-client/internal/models"
-	"github.com/bss/radb-client/pkg/ratelimit"
 )
 
 // BulkResult contains the results of a bulk operation.
 type BulkResult struct {
-	Total     int           `json:"total"`
-	Succeeded int           `json:"succeeded"`
-	Failed    int           `json:"failed"`
-	Errors    []BulkError   `json:"errors,omitempty"`
+	Total     int         `json:"total"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Errors    []BulkError `json:"errors,omitempty"`
 }
 
 // BulkError represents an error from a bulk operation.
 type BulkError struct {
-	Index   int    `json:"index"`
-	ID      string `json:"id"`
-	Error   string `json:"error"`
+	Index int    `json:"index"`
+	ID    string `json:"id"`
+	Error string `json:"error"`
 }
 
-// BatchCreateRoutes creates multiple routes in parallel with rate limiting.
+// BatchCreateRoutes creates multiple routes in parallel, paced by the
+// client's rate limit for writes.
 func (c *HTTPClient) BatchCreateRoutes(ctx context.Context, routes []*models.RouteObject, workers int) (*BulkResult, error) {
 	c.logger.Infof("Starting batch create for %d routes with %d workers", len(routes), workers)
 
@@ -37,9 +37,6 @@ func (c *HTTPClient) BatchCreateRoutes(ctx context.Context, routes []*models.Rou
 		Errors: make([]BulkError, 0),
 	}
 
-	// Create rate limiter
-	limiter := ratelimit.New(60) // 60 requests per minute
-
 	// Create worker pool
 	jobs := make(chan workJob, len(routes))
 	results := make(chan workResult, len(routes))
@@ -52,16 +49,6 @@ func (c *HTTPClient) BatchCreateRoutes(ctx context.Context, routes []*models.Rou
 		go func() {
 			defer wg.Done()
 			for job := range jobs {
-				// Wait for rate limiter
-				if err := limiter.Wait(ctx); err != nil {
-					results <- workResult{
-						Index: job.Index,
-						ID:    job.ID,
-						Error: err,
-					}
-					continue
-				}
-
 				// Execute create
 				err := c.CreateRoute(ctx, job.Route)
 				results <- workResult{
@@ -92,27 +79,14 @@ func (c *HTTPClient) BatchCreateRoutes(ctx context.Context, routes []*models.Rou
 	}()
 
 	// Collect results
-	var mu sync.Mutex
-	for res := range results {
-		mu.Lock()
-		if res.Error != nil {
-			result.Failed++
-			result.Errors = append(result.Errors, BulkError{
-				Index: res.Index,
-				ID:    res.ID,
-				Error: res.Error.Error(),
-			})
-		} else {
-			result.Succeeded++
-		}
-		mu.Unlock()
-	}
+	c.collectResults("create routes", result, results)
 
 	c.logger.Infof("Batch create completed: %d succeeded, %d failed", result.Succeeded, result.Failed)
 	return result, nil
 }
 
-// BatchUpdateRoutes updates multiple routes in parallel with rate limiting.
+// BatchUpdateRoutes updates multiple routes in parallel, paced by the
+// client's rate limit for writes.
 func (c *HTTPClient) BatchUpdateRoutes(ctx context.Context, routes []*models.RouteObject, workers int) (*BulkResult, error) {
 	c.logger.Infof("Starting batch update for %d routes with %d workers", len(routes), workers)
 
@@ -125,7 +99,6 @@ func (c *HTTPClient) BatchUpdateRoutes(ctx context.Context, routes []*models.Rou
 		Errors: make([]BulkError, 0),
 	}
 
-	limiter := ratelimit.New(60)
 	jobs := make(chan workJob, len(routes))
 	results := make(chan workResult, len(routes))
 
@@ -136,11 +109,6 @@ func (c *HTTPClient) BatchUpdateRoutes(ctx context.Context, routes []*models.Rou
 		go func() {
 			defer wg.Done()
 			for job := range jobs {
-				if err := limiter.Wait(ctx); err != nil {
-					results <- workResult{Index: job.Index, ID: job.ID, Error: err}
-					continue
-				}
-
 				err := c.UpdateRoute(ctx, job.Route)
 				results <- workResult{Index: job.Index, ID: job.ID, Error: err}
 			}
@@ -159,27 +127,14 @@ func (c *HTTPClient) BatchUpdateRoutes(ctx context.Context, routes []*models.Rou
 		close(results)
 	}()
 
-	var mu sync.Mutex
-	for res := range results {
-		mu.Lock()
-		if res.Error != nil {
-			result.Failed++
-			result.Errors = append(result.Errors, BulkError{
-				Index: res.Index,
-				ID:    res.ID,
-				Error: res.Error.Error(),
-			})
-		} else {
-			result.Succeeded++
-		}
-		mu.Unlock()
-	}
+	c.collectResults("update routes", result, results)
 
 	c.logger.Infof("Batch update completed: %d succeeded, %d failed", result.Succeeded, result.Failed)
 	return result, nil
 }
 
-// BatchDeleteRoutes deletes multiple routes in parallel with rate limiting.
+// BatchDeleteRoutes deletes multiple routes in parallel, paced by the
+// client's rate limit for writes.
 func (c *HTTPClient) BatchDeleteRoutes(ctx context.Context, routes []RouteIdentifier, workers int) (*BulkResult, error) {
 	c.logger.Infof("Starting batch delete for %d routes with %d workers", len(routes), workers)
 
@@ -192,7 +147,6 @@ func (c *HTTPClient) BatchDeleteRoutes(ctx context.Context, routes []RouteIdenti
 		Errors: make([]BulkError, 0),
 	}
 
-	limiter := ratelimit.New(60)
 	jobs := make(chan deleteJob, len(routes))
 	results := make(chan workResult, len(routes))
 
@@ -203,11 +157,6 @@ func (c *HTTPClient) BatchDeleteRoutes(ctx context.Context, routes []RouteIdenti
 		go func() {
 			defer wg.Done()
 			for job := range jobs {
-				if err := limiter.Wait(ctx); err != nil {
-					results <- workResult{Index: job.Index, ID: job.ID, Error: err}
-					continue
-				}
-
 				err := c.DeleteRoute(ctx, job.Prefix, job.ASN)
 				results <- workResult{Index: job.Index, ID: job.ID, Error: err}
 			}
@@ -231,26 +180,296 @@ func (c *HTTPClient) BatchDeleteRoutes(ctx context.Context, routes []RouteIdenti
 		close(results)
 	}()
 
-	var mu sync.Mutex
-	for res := range results {
-		mu.Lock()
-		if res.Error != nil {
-			result.Failed++
-			result.Errors = append(result.Errors, BulkError{
-				Index: res.Index,
-				ID:    res.ID,
-				Error: res.Error.Error(),
-			})
-		} else {
-			result.Succeeded++
+	c.collectResults("delete routes", result, results)
+
+	c.logger.Infof("Batch delete completed: %d succeeded, %d failed", result.Succeeded, result.Failed)
+	return result, nil
+}
+
+// BatchCreateContacts creates multiple contacts in parallel, paced by the
+// client's rate limit for writes.
+func (c *HTTPClient) BatchCreateContacts(ctx context.Context, contacts []*models.Contact, workers int) (*BulkResult, error) {
+	c.logger.Infof("Starting batch create for %d contacts with %d workers", len(contacts), workers)
+
+	if workers <= 0 {
+		workers = 5
+	}
+
+	result := &BulkResult{
+		Total:  len(contacts),
+		Errors: make([]BulkError, 0),
+	}
+
+	jobs := make(chan workJob, len(contacts))
+	results := make(chan workResult, len(contacts))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				err := c.CreateContact(ctx, job.Contact)
+				results <- workResult{Index: job.Index, ID: job.ID, Error: err}
+			}
+		}()
+	}
+
+	go func() {
+		for i, contact := range contacts {
+			jobs <- workJob{Index: i, ID: contactLabel(contact), Contact: contact}
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	c.collectResults("create contacts", result, results)
+
+	c.logger.Infof("Batch create completed: %d succeeded, %d failed", result.Succeeded, result.Failed)
+	return result, nil
+}
+
+// BatchUpdateContacts updates multiple contacts in parallel, paced by the
+// client's rate limit for writes.
+func (c *HTTPClient) BatchUpdateContacts(ctx context.Context, contacts []*models.Contact, workers int) (*BulkResult, error) {
+	c.logger.Infof("Starting batch update for %d contacts with %d workers", len(contacts), workers)
+
+	if workers <= 0 {
+		workers = 5
+	}
+
+	result := &BulkResult{
+		Total:  len(contacts),
+		Errors: make([]BulkError, 0),
+	}
+
+	jobs := make(chan workJob, len(contacts))
+	results := make(chan workResult, len(contacts))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				err := c.UpdateContact(ctx, job.Contact)
+				results <- workResult{Index: job.Index, ID: job.ID, Error: err}
+			}
+		}()
+	}
+
+	go func() {
+		for i, contact := range contacts {
+			jobs <- workJob{Index: i, ID: contactLabel(contact), Contact: contact}
 		}
-		mu.Unlock()
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	c.collectResults("update contacts", result, results)
+
+	c.logger.Infof("Batch update completed: %d succeeded, %d failed", result.Succeeded, result.Failed)
+	return result, nil
+}
+
+// BatchDeleteContacts deletes multiple contacts in parallel, paced by the
+// client's rate limit for writes.
+func (c *HTTPClient) BatchDeleteContacts(ctx context.Context, ids []string, workers int) (*BulkResult, error) {
+	c.logger.Infof("Starting batch delete for %d contacts with %d workers", len(ids), workers)
+
+	if workers <= 0 {
+		workers = 5
 	}
 
+	result := &BulkResult{
+		Total:  len(ids),
+		Errors: make([]BulkError, 0),
+	}
+
+	jobs := make(chan workJob, len(ids))
+	results := make(chan workResult, len(ids))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				err := c.DeleteContact(ctx, job.ID)
+				results <- workResult{Index: job.Index, ID: job.ID, Error: err}
+			}
+		}()
+	}
+
+	go func() {
+		for i, id := range ids {
+			jobs <- workJob{Index: i, ID: id}
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	c.collectResults("delete contacts", result, results)
+
 	c.logger.Infof("Batch delete completed: %d succeeded, %d failed", result.Succeeded, result.Failed)
 	return result, nil
 }
 
+// contactLabel identifies a contact in bulk results. New contacts have no ID
+// until the server assigns one, so their email is used instead.
+func contactLabel(contact *models.Contact) string {
+	if contact.ID != "" {
+		return contact.ID
+	}
+	return contact.Email
+}
+
+// CreateRoutes creates routes through the client's batch API when it has one,
+// and one at a time otherwise.
+func CreateRoutes(ctx context.Context, client Client, routes []*models.RouteObject, workers int) (*BulkResult, error) {
+	if batch, ok := client.(BatchClient); ok {
+		return batch.BatchCreateRoutes(ctx, routes, workers)
+	}
+
+	result := &BulkResult{
+		Total:  len(routes),
+		Errors: make([]BulkError, 0),
+	}
+	for i, route := range routes {
+		if err := client.CreateRoute(ctx, route); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, BulkError{Index: i, ID: route.ID(), Error: err.Error()})
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+// UpdateRoutes updates routes through the client's batch API when it has one,
+// and one at a time otherwise.
+func UpdateRoutes(ctx context.Context, client Client, routes []*models.RouteObject, workers int) (*BulkResult, error) {
+	if batch, ok := client.(BatchClient); ok {
+		return batch.BatchUpdateRoutes(ctx, routes, workers)
+	}
+
+	result := &BulkResult{
+		Total:  len(routes),
+		Errors: make([]BulkError, 0),
+	}
+	for i, route := range routes {
+		if err := client.UpdateRoute(ctx, route); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, BulkError{Index: i, ID: route.ID(), Error: err.Error()})
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+// DeleteRoutes deletes routes through the client's batch API when it has one,
+// and one at a time otherwise.
+func DeleteRoutes(ctx context.Context, client Client, routes []RouteIdentifier, workers int) (*BulkResult, error) {
+	if batch, ok := client.(BatchClient); ok {
+		return batch.BatchDeleteRoutes(ctx, routes, workers)
+	}
+
+	result := &BulkResult{
+		Total:  len(routes),
+		Errors: make([]BulkError, 0),
+	}
+	for i, route := range routes {
+		if err := client.DeleteRoute(ctx, route.Prefix, route.ASN); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, BulkError{Index: i, ID: fmt.Sprintf("%s-%s", route.Prefix, route.ASN), Error: err.Error()})
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+// CreateContacts creates contacts through the client's batch API when it
+// has one, and one at a time otherwise.
+func CreateContacts(ctx context.Context, client Client, contacts []*models.Contact, workers int) (*BulkResult, error) {
+	if batch, ok := client.(ContactBatchClient); ok {
+		return batch.BatchCreateContacts(ctx, contacts, workers)
+	}
+	return eachContact(contacts, func(contact *models.Contact) error {
+		return client.CreateContact(ctx, contact)
+	}), nil
+}
+
+// UpdateContacts updates contacts through the client's batch API when it
+// has one, and one at a time otherwise.
+func UpdateContacts(ctx context.Context, client Client, contacts []*models.Contact, workers int) (*BulkResult, error) {
+	if batch, ok := client.(ContactBatchClient); ok {
+		return batch.BatchUpdateContacts(ctx, contacts, workers)
+	}
+	return eachContact(contacts, func(contact *models.Contact) error {
+		return client.UpdateContact(ctx, contact)
+	}), nil
+}
+
+// DeleteContacts deletes contacts through the client's batch API when it
+// has one, and one at a time otherwise.
+func DeleteContacts(ctx context.Context, client Client, ids []string, workers int) (*BulkResult, error) {
+	if batch, ok := client.(ContactBatchClient); ok {
+		return batch.BatchDeleteContacts(ctx, ids, workers)
+	}
+
+	result := &BulkResult{
+		Total:  len(ids),
+		Errors: make([]BulkError, 0),
+	}
+	for i, id := range ids {
+		if err := client.DeleteContact(ctx, id); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, BulkError{Index: i, ID: id, Error: err.Error()})
+			continue
+		}
+		result.Succeeded++
+	}
+
+	return result, nil
+}
+
+// eachContact applies fn to each contact in turn, collecting the results.
+func eachContact(contacts []*models.Contact, fn func(*models.Contact) error) *BulkResult {
+	result := &BulkResult{
+		Total:  len(contacts),
+		Errors: make([]BulkError, 0),
+	}
+	for i, contact := range contacts {
+		if err := fn(contact); err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, BulkError{Index: i, ID: contactLabel(contact), Error: err.Error()})
+			continue
+		}
+		result.Succeeded++
+	}
+	return result
+}
+
 // RouteIdentifier identifies a route for deletion.
 type RouteIdentifier struct {
 	Prefix string
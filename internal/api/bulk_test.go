@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/api/apitest"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBatchContacts(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := api.NewHTTPClient(server.URL, "RADB", 5, logger)
+	if err := client.Login(context.Background(), "user", "password"); err != nil {
+		t.Fatalf("Login() failed: %v", err)
+	}
+	ctx := context.Background()
+
+	contacts := []*models.Contact{
+		{Name: "Alice Example", Email: "alice@example.com", Role: models.ContactRoleTech},
+		{Name: "Bob Example", Email: "not-an-email", Role: models.ContactRoleAdmin},
+	}
+
+	result, err := client.BatchCreateContacts(ctx, contacts, 2)
+	if err != nil {
+		t.Fatalf("BatchCreateContacts() failed: %v", err)
+	}
+	if result.Total != 2 || result.Succeeded != 1 || result.Failed != 1 {
+		t.Fatalf("BatchCreateContacts() = %+v, want 1 succeeded and 1 failed", result)
+	}
+	if result.Errors[0].ID != "not-an-email" {
+		t.Errorf("failed contact reported as %q, want its email", result.Errors[0].ID)
+	}
+	if contacts[0].ID == "" {
+		t.Fatal("created contact was not assigned an ID")
+	}
+
+	contacts[0].Phone = "+1 555 0100"
+	result, err = client.BatchUpdateContacts(ctx, contacts[:1], 2)
+	if err != nil || result.Succeeded != 1 {
+		t.Fatalf("BatchUpdateContacts() = %+v, %v", result, err)
+	}
+	updated, err := client.GetContact(ctx, contacts[0].ID)
+	if err != nil {
+		t.Fatalf("GetContact() failed: %v", err)
+	}
+	if updated.Phone != "+1 555 0100" {
+		t.Errorf("Phone = %q after batch update", updated.Phone)
+	}
+
+	result, err = client.BatchDeleteContacts(ctx, []string{contacts[0].ID, "CONTACT-MISSING"}, 2)
+	if err != nil {
+		t.Fatalf("BatchDeleteContacts() failed: %v", err)
+	}
+	if result.Succeeded != 1 || result.Failed != 1 || result.Errors[0].ID != "CONTACT-MISSING" {
+		t.Errorf("BatchDeleteContacts() = %+v, want only the missing contact to fail", result)
+	}
+}
+
+func TestBatchUsesClientRateLimit(t *testing.T) {
+	server := apitest.NewServer()
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := api.NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.Login(context.Background(), "user", "password")
+	client.SetEndpointRateLimit(api.EndpointWrite, 1, 2)
+
+	// The write budget allows a burst of two, so the third create cannot
+	// start before the deadline however many workers there are
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	routes := []*models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+		{Route: "198.51.100.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+		{Route: "203.0.113.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+	}
+	result, err := client.BatchCreateRoutes(ctx, routes, 3)
+	if err != nil {
+		t.Fatalf("BatchCreateRoutes() failed: %v", err)
+	}
+	if result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("BatchCreateRoutes() = %+v, want 2 within the write budget", result)
+	}
+}
+
+func TestDeleteRoutesFallback(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := api.NewMemoryClient("RADB", logger)
+	if _, ok := interface{}(client).(api.BatchClient); ok {
+		t.Fatal("MemoryClient has a batch API; the fallback would not be exercised")
+	}
+	ctx := context.Background()
+
+	for _, prefix := range []string{"192.0.2.0/24", "198.51.100.0/24"} {
+		route := &models.RouteObject{Route: prefix, Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"}
+		if err := client.CreateRoute(ctx, route); err != nil {
+			t.Fatalf("CreateRoute() failed: %v", err)
+		}
+	}
+
+	result, err := api.DeleteRoutes(ctx, client, []api.RouteIdentifier{
+		{Prefix: "192.0.2.0/24", ASN: "AS64500"},
+		{Prefix: "203.0.113.0/24", ASN: "AS64500"},
+		{Prefix: "198.51.100.0/24", ASN: "64500"},
+	}, 4)
+	if err != nil {
+		t.Fatalf("DeleteRoutes() failed: %v", err)
+	}
+	if result.Total != 3 || result.Succeeded != 2 || result.Failed != 1 {
+		t.Fatalf("DeleteRoutes() = %+v, want 2 succeeded and 1 failed", result)
+	}
+	if e := result.Errors[0]; e.Index != 1 || e.ID != "203.0.113.0/24-AS64500" || e.Error == "" {
+		t.Errorf("error = %+v, want the missing route at index 1", e)
+	}
+
+	remaining, err := client.ListRoutes(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if remaining.Count != 0 {
+		t.Errorf("%d routes remain after DeleteRoutes(), want none", remaining.Count)
+	}
+}
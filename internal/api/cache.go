@@ -0,0 +1,319 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long a cached response is served without asking
+// the API.
+const DefaultCacheTTL = 15 * time.Minute
+
+// CachingClient is implemented by clients that can cache GET responses.
+type CachingClient interface {
+	// SetResponseCache answers route and contact reads from cache while
+	// entries are fresh. A nil cache disables caching.
+	SetResponseCache(cache *ResponseCache)
+}
+
+// Ensure HTTPClient implements CachingClient.
+var _ CachingClient = (*HTTPClient)(nil)
+
+// ResponseCache keeps successful route and contact GET responses on disk,
+// one file per request path. Fresh entries answer reads without a request;
+// expired entries are kept and served when the API fails or returns a
+// server error, so reads survive an API outage.
+type ResponseCache struct {
+	dir string
+	ttl time.Duration
+	mu  sync.Mutex
+}
+
+// cachedResponse is a stored GET response.
+type cachedResponse struct {
+	Path     string      `json:"path"` // Relative to the client's base URL
+	StoredAt time.Time   `json:"stored_at"`
+	Status   int         `json:"status"`
+	Headers  http.Header `json:"headers,omitempty"`
+	Body     string      `json:"body"`
+}
+
+type cacheRefreshKey struct{}
+
+// WithCacheRefresh returns a context whose reads bypass fresh cache entries
+// and store the API's response, for warming the cache.
+func WithCacheRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheRefreshKey{}, true)
+}
+
+// NewResponseCache creates a response cache in dir. A ttl of zero or less
+// uses DefaultCacheTTL.
+func NewResponseCache(dir string, ttl time.Duration) (*ResponseCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create response cache directory: %w", err)
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &ResponseCache{dir: dir, ttl: ttl}, nil
+}
+
+// Len returns the number of cached responses.
+func (rc *ResponseCache) Len() (int, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	names, err := rc.entries()
+	return len(names), err
+}
+
+// Clear removes every cached response and returns how many were removed.
+func (rc *ResponseCache) Clear() (int, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	names, err := rc.entries()
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		if err := os.Remove(filepath.Join(rc.dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return 0, fmt.Errorf("failed to remove cached response: %w", err)
+		}
+	}
+	return len(names), nil
+}
+
+// entries returns the file names of the cached responses. Callers hold mu.
+func (rc *ResponseCache) entries() ([]string, error) {
+	dirEntries, err := os.ReadDir(rc.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response cache: %w", err)
+	}
+
+	var names []string
+	for _, entry := range dirEntries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// get returns the cached response for path and whether it is still fresh,
+// or nil if there is none.
+func (rc *ResponseCache) get(path string) (*cachedResponse, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	data, err := os.ReadFile(rc.file(path))
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Path != path {
+		return nil, false
+	}
+	return &cached, time.Since(cached.StoredAt) < rc.ttl
+}
+
+// put stores a response for path.
+func (rc *ResponseCache) put(cached *cachedResponse) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached response: %w", err)
+	}
+
+	// The cache directory is shared with other processes, such as a daemon,
+	// so each write goes through a temporary file of its own
+	path := rc.file(cached.Path)
+	tmp, err := os.CreateTemp(rc.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save cached response: %w", err)
+	}
+	return nil
+}
+
+// invalidate removes the cached responses a write to path makes stale: the
+// object itself and every listing of its collection.
+func (rc *ResponseCache) invalidate(path string) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	names, err := rc.entries()
+	if err != nil {
+		return err
+	}
+
+	object := requestPath(path)
+	collection := collectionPath(object)
+	for _, name := range names {
+		file := filepath.Join(rc.dir, name)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var cached cachedResponse
+		if err := json.Unmarshal(data, &cached); err != nil {
+			continue
+		}
+		if cached := requestPath(cached.Path); cached == object || cached == collection {
+			if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to remove cached response: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// file returns the cache file for a request path.
+func (rc *ResponseCache) file(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// requestPath strips the query from a request path.
+func requestPath(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// collectionPath returns the collection of an object path, such as
+// /RADB/route for /RADB/route/192.0.2.0%2F24/AS64500.
+func collectionPath(path string) string {
+	parts := strings.SplitN(path, "/", 4)
+	if len(parts) < 3 {
+		return path
+	}
+	return strings.Join(parts[:3], "/")
+}
+
+// SetResponseCache enables or disables the response cache.
+func (c *HTTPClient) SetResponseCache(cache *ResponseCache) {
+	c.cache = cache
+}
+
+// cacheable reports whether a request's response may be cached: route and
+// contact lookups and listings.
+func (c *HTTPClient) cacheable(method, path string) bool {
+	if c.cache == nil || method != http.MethodGet {
+		return false
+	}
+	class := endpointClass(method, path)
+	return class == EndpointRead || class == EndpointList
+}
+
+// fromCache answers a cacheable request from a fresh cache entry.
+func (c *HTTPClient) fromCache(ctx context.Context, method, path string) (*http.Response, bool) {
+	if !c.cacheable(method, path) {
+		return nil, false
+	}
+	if refresh, _ := ctx.Value(cacheRefreshKey{}).(bool); refresh {
+		return nil, false
+	}
+
+	cached, fresh := c.cache.get(path)
+	if cached == nil || !fresh {
+		return nil, false
+	}
+	c.Tracef("cache: GET %s answered from cache stored at %s", path, cached.StoredAt.Format(time.RFC3339))
+	return cached.response(), true
+}
+
+// staleResponse answers a cacheable request whose API call failed from an
+// expired cache entry, if there is one.
+func (c *HTTPClient) staleResponse(method, path string, cause string) (*http.Response, bool) {
+	if !c.cacheable(method, path) {
+		return nil, false
+	}
+
+	cached, _ := c.cache.get(path)
+	if cached == nil {
+		return nil, false
+	}
+	c.logger.Warnf("API request GET %s failed (%s); using the cached response from %s", path, cause, cached.StoredAt.Format(time.RFC3339))
+	return cached.response(), true
+}
+
+// updateCache stores a successful cacheable response, replacing its body
+// with a rereadable copy, and invalidates the entries a successful write
+// makes stale. Cache failures are logged; an error is returned only when
+// the response body cannot be read.
+func (c *HTTPClient) updateCache(method, path string, resp *http.Response) error {
+	if c.cache == nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil
+	}
+
+	if !c.cacheable(method, path) {
+		if endpointClass(method, path) == EndpointWrite {
+			if err := c.cache.invalidate(path); err != nil {
+				c.logger.Warnf("Failed to invalidate cached responses for %s: %v", path, err)
+			}
+		}
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	cached := &cachedResponse{
+		Path:     path,
+		StoredAt: time.Now(),
+		Status:   resp.StatusCode,
+		Headers:  sanitizeHeaders(resp.Header),
+		Body:     string(body),
+	}
+	if err := c.cache.put(cached); err != nil {
+		c.logger.Warnf("Failed to cache response for %s: %v", path, err)
+	}
+	return nil
+}
+
+// response rebuilds the stored response.
+func (r *cachedResponse) response() *http.Response {
+	headers := r.Headers.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s (cached)", r.Status, http.StatusText(r.Status)),
+		StatusCode: r.Status,
+		Header:     headers,
+		Body:       io.NopCloser(strings.NewReader(r.Body)),
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestResponseCache(t *testing.T) {
+	var requests atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"})
+	}))
+	defer server.Close()
+
+	// newCachingClient returns an unthrottled client without retries that
+	// caches responses for ttl
+	newCachingClient := func(t *testing.T, dir string, ttl time.Duration) *HTTPClient {
+		client := newPagedClient(t, server.URL)
+		client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+		client.SetRateLimit(6000, 100)
+		cache, err := NewResponseCache(dir, ttl)
+		if err != nil {
+			t.Fatalf("NewResponseCache() failed: %v", err)
+		}
+		client.SetResponseCache(cache)
+		return client
+	}
+
+	ctx := context.Background()
+
+	t.Run("FreshHitSkipsRequest", func(t *testing.T) {
+		requests.Store(0)
+		client := newCachingClient(t, t.TempDir(), time.Hour)
+
+		for i := 0; i < 2; i++ {
+			if _, err := client.GetRoute(ctx, "192.0.2.0/24", "AS64500"); err != nil {
+				t.Fatalf("GetRoute() failed: %v", err)
+			}
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("Expected the second read to come from cache, got %d requests", got)
+		}
+
+		if _, err := client.GetRoute(WithCacheRefresh(ctx), "192.0.2.0/24", "AS64500"); err != nil {
+			t.Fatalf("GetRoute() failed: %v", err)
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("Expected a refresh to reach the API, got %d requests", got)
+		}
+	})
+
+	t.Run("WriteInvalidates", func(t *testing.T) {
+		requests.Store(0)
+		client := newCachingClient(t, t.TempDir(), time.Hour)
+
+		if _, err := client.GetRoute(ctx, "192.0.2.0/24", "AS64500"); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.DeleteRoute(ctx, "192.0.2.0/24", "AS64500"); err != nil {
+			t.Fatalf("DeleteRoute() failed: %v", err)
+		}
+		if _, err := client.GetRoute(ctx, "192.0.2.0/24", "AS64500"); err != nil {
+			t.Fatal(err)
+		}
+		if got := requests.Load(); got != 3 {
+			t.Errorf("Expected the read after a delete to reach the API, got %d requests", got)
+		}
+	})
+
+	t.Run("StaleServedOnFailure", func(t *testing.T) {
+		dir := t.TempDir()
+		client := newCachingClient(t, dir, time.Nanosecond)
+
+		if _, err := client.GetRoute(ctx, "192.0.2.0/24", "AS64500"); err != nil {
+			t.Fatal(err)
+		}
+
+		failing.Store(true)
+		defer failing.Store(false)
+
+		route, err := client.GetRoute(ctx, "192.0.2.0/24", "AS64500")
+		if err != nil {
+			t.Fatalf("Expected the stale response to be served, got %v", err)
+		}
+		if route.Route != "192.0.2.0/24" {
+			t.Errorf("Stale route = %s, want 192.0.2.0/24", route.Route)
+		}
+
+		if _, err := client.GetRoute(ctx, "198.51.100.0/24", "AS64500"); err == nil {
+			t.Error("Expected an uncached read to fail")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ResponseCapture is a sanitized API response that failed to parse or
+// validate, saved for attaching to bug reports.
+type ResponseCapture struct {
+	CapturedAt time.Time   `json:"captured_at"`
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Status     int         `json:"status"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+	Error      string      `json:"error"`
+}
+
+// SetCaptureDir saves a sanitized copy of every response that fails to
+// parse or validate to dir. An empty dir disables capturing.
+func (c *HTTPClient) SetCaptureDir(dir string) {
+	c.captureDir = dir
+}
+
+// decodeResponse reads a JSON response body into out. When capturing is
+// enabled, check (if not nil) is run on the decoded value, and responses
+// that fail to decode or check are captured. Check failures are not returned.
+func (c *HTTPClient) decodeResponse(resp *http.Response, what string, out interface{}, check func() error) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", what, err)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		c.captureResponse(resp, body, err)
+		return fmt.Errorf("failed to decode %s response: %w", what, err)
+	}
+
+	if c.captureDir != "" && check != nil {
+		if err := check(); err != nil {
+			c.logger.Debugf("Invalid %s in response: %v", what, err)
+			c.captureResponse(resp, body, err)
+		}
+	}
+
+	return nil
+}
+
+// captureResponse saves a sanitized copy of a response body when capturing
+// is enabled. Failures to save are logged, never returned.
+func (c *HTTPClient) captureResponse(resp *http.Response, body []byte, cause error) {
+	if c.captureDir == "" {
+		return
+	}
+
+	capture := ResponseCapture{
+		CapturedAt: time.Now(),
+		Status:     resp.StatusCode,
+		Headers:    sanitizeHeaders(resp.Header),
+		Body:       sanitizeBody(body),
+		Error:      cause.Error(),
+	}
+	if resp.Request != nil {
+		capture.Method = resp.Request.Method
+		capture.URL = strings.TrimPrefix(resp.Request.URL.String(), strings.TrimSuffix(c.baseURL, "/"))
+	}
+
+	data, err := json.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		c.logger.Warnf("Failed to capture response: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(c.captureDir, 0700); err != nil {
+		c.logger.Warnf("Failed to create capture directory: %v", err)
+		return
+	}
+
+	path := filepath.Join(c.captureDir, fmt.Sprintf("response-%d.json", capture.CapturedAt.UnixNano()))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		c.logger.Warnf("Failed to capture response: %v", err)
+		return
+	}
+
+	c.logger.Infof("Captured response that failed with %q to %s", cause, path)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCaptureFailedResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/RADB/route/192.0.2.0"):
+			w.Write([]byte(`{"route": "192.0.2.0/24", "origin": "AS64500", "password": "hunter2"`))
+		case strings.HasPrefix(r.URL.Path, "/RADB/route/198.51.100.0"):
+			w.Write([]byte(`{"route": "198.51.100.0/24", "origin": "AS64500"}`))
+		default:
+			w.Write([]byte(`[{"route": "203.0.113.0/24", "origin": "AS64500", "mnt_by": ["MAINT-A"], "source": "RADB"}]`))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := newPagedClient(t, server.URL)
+	client.SetCaptureDir(dir)
+	ctx := context.Background()
+
+	if _, err := client.GetRoute(ctx, "192.0.2.0/24", "AS64500"); err == nil {
+		t.Error("GetRoute() decoded a truncated response")
+	}
+	// Invalid objects are captured but still returned
+	if _, err := client.GetRoute(ctx, "198.51.100.0/24", "AS64500"); err != nil {
+		t.Errorf("GetRoute() of an invalid route failed: %v", err)
+	}
+	// Valid responses are not captured
+	if _, err := client.ListRoutes(ctx, nil); err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "response-*.json"))
+	if err != nil || len(files) != 2 {
+		t.Fatalf("captured %v (%v), want 2 files", files, err)
+	}
+
+	var causes []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var capture ResponseCapture
+		if err := json.Unmarshal(data, &capture); err != nil {
+			t.Fatalf("capture %s is not valid JSON: %v", file, err)
+		}
+		if capture.Method != "GET" || !strings.HasPrefix(capture.URL, "/RADB/route/") || capture.Status != http.StatusOK {
+			t.Errorf("capture = %+v, want a GET of a route", capture)
+		}
+		if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "Basic ") {
+			t.Errorf("capture %s contains credentials", file)
+		}
+		causes = append(causes, capture.Error)
+	}
+	if !strings.Contains(strings.Join(causes, "\n"), "mnt-by") {
+		t.Errorf("capture errors = %q, want a validation failure", causes)
+	}
+}
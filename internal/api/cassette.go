@@ -0,0 +1,343 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces sensitive values in recorded cassettes.
+const redacted = "REDACTED"
+
+// sensitiveHeaders are redacted from recorded requests and responses.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// sensitiveFields are JSON object keys whose values are redacted from recorded bodies.
+var sensitiveFields = []string{"password", "passwd", "secret", "token", "api_key", "apikey"}
+
+// sensitivePattern matches sensitive string fields in bodies that are not valid JSON.
+var sensitivePattern = regexp.MustCompile(`(?i)("(?:` + strings.Join(sensitiveFields, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// Cassette is a recording of API interactions that can be replayed in place
+// of the network.
+type Cassette struct {
+	RecordedAt   time.Time     `json:"recorded_at"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a sanitized API request. URL is relative to the
+// client's base URL so a cassette replays against any server.
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a sanitized, uncompressed API response.
+type RecordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads a cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to path atomically.
+func (c *Cassette) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	return nil
+}
+
+// RecordCassette records every API interaction, sanitized, to a cassette
+// file at path. The file is rewritten after each interaction so a recording
+// survives a crash.
+func (c *HTTPClient) RecordCassette(path string) error {
+	recorder := &cassetteRecorder{
+		next:     c.transport,
+		baseURL:  c.baseURL,
+		path:     path,
+		cassette: &Cassette{RecordedAt: time.Now(), Interactions: make([]Interaction, 0)},
+	}
+	if err := recorder.cassette.Save(path); err != nil {
+		return err
+	}
+
+	c.httpClient.Transport = recorder
+	c.logger.Infof("Recording API interactions to %s", path)
+	return nil
+}
+
+// ReplayCassette answers requests from a cassette file instead of the
+// network. Requests without a matching recording fail. Rate limiting is
+// disabled, as there is no server to protect.
+func (c *HTTPClient) ReplayCassette(path string) error {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return err
+	}
+
+	c.httpClient.Transport = &cassettePlayer{
+		baseURL:  c.baseURL,
+		path:     path,
+		cassette: cassette,
+		used:     make([]bool, len(cassette.Interactions)),
+	}
+	c.disableRateLimits()
+
+	c.logger.Infof("Replaying %d API interactions from %s", len(cassette.Interactions), path)
+	return nil
+}
+
+// cassetteRecorder is a transport that records interactions as they pass through.
+type cassetteRecorder struct {
+	next    http.RoundTripper
+	baseURL string
+	path    string
+
+	mu       sync.Mutex
+	cassette *Cassette
+}
+
+// RoundTrip performs the request and records it with its response.
+func (r *cassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req, r.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for recording: %w", err)
+	}
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		if body, err = gunzip(body); err != nil {
+			return nil, fmt.Errorf("failed to decompress response for recording: %w", err)
+		}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = int64(len(body))
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := sanitizeHeaders(resp.Header)
+	headers.Del("Content-Length")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: *recorded,
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    sanitizeBody(body),
+		},
+	})
+	if err := r.cassette.Save(r.path); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// cassettePlayer is a transport that answers requests from a cassette.
+type cassettePlayer struct {
+	baseURL string
+	path    string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// RoundTrip returns the first unused recorded response whose request matches.
+func (p *cassettePlayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req, p.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, interaction := range p.cassette.Interactions {
+		want := interaction.Request
+		if p.used[i] || want.Method != recorded.Method || want.URL != recorded.URL || want.Body != recorded.Body {
+			continue
+		}
+		p.used[i] = true
+
+		resp := interaction.Response
+		header := resp.Headers.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+			StatusCode:    resp.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(resp.Body)),
+			ContentLength: int64(len(resp.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no unused recording of %s %s", p.path, recorded.Method, recorded.URL)
+}
+
+// recordRequest captures a sanitized copy of a request, restoring its body
+// so it can still be sent.
+func recordRequest(req *http.Request, baseURL string) (*RecordedRequest, error) {
+	recorded := &RecordedRequest{
+		Method:  req.Method,
+		URL:     strings.TrimPrefix(req.URL.String(), strings.TrimSuffix(baseURL, "/")),
+		Headers: sanitizeHeaders(req.Header),
+	}
+	recorded.Headers.Del("Content-Encoding")
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request for recording: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		if body, err = gunzip(body); err != nil {
+			return nil, fmt.Errorf("failed to decompress request for recording: %w", err)
+		}
+	}
+	recorded.Body = sanitizeBody(body)
+
+	return recorded, nil
+}
+
+// sanitizeHeaders returns a copy of header with credentials redacted.
+func sanitizeHeaders(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range sensitiveHeaders {
+		if clean.Get(name) != "" {
+			clean.Set(name, redacted)
+		}
+	}
+	return clean
+}
+
+// sanitizeBody redacts sensitive fields from a JSON body. Bodies that are
+// not valid JSON have sensitive string fields redacted by pattern.
+func sanitizeBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return sensitivePattern.ReplaceAllString(string(body), `$1"`+redacted+`"`)
+	}
+	if !redactFields(value) {
+		return string(body)
+	}
+
+	clean, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(clean)
+}
+
+// redactFields replaces sensitive object fields in a decoded JSON value and
+// reports whether anything was replaced.
+func redactFields(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key) {
+				v[key] = redacted
+				changed = true
+			} else if redactFields(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactFields(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// isSensitiveField reports whether a JSON key names a credential.
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range sensitiveFields {
+		if key == field {
+			return true
+		}
+	}
+	return false
+}
+
+// gunzip decompresses a gzip payload.
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
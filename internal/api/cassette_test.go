@@ -0,0 +1,77 @@
+package api_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/api/apitest"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := apitest.NewServer()
+	server.AddRoute(models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"})
+
+	recorder := api.NewHTTPClient(server.URL, "RADB", 5, logger)
+	recorder.Login(ctx, "user", "hunter2")
+	if err := recorder.RecordCassette(path); err != nil {
+		t.Fatalf("RecordCassette() failed: %v", err)
+	}
+
+	contact := &models.Contact{Name: "NOC", Email: "noc@example.com", Role: models.ContactRoleTech}
+	if err := recorder.CreateContact(ctx, contact); err != nil {
+		t.Fatalf("CreateContact() failed: %v", err)
+	}
+	recorded, err := recorder.ListRoutes(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") || strings.Contains(string(data), "Basic ") {
+		t.Error("cassette contains credentials")
+	}
+
+	// Replay against a server that no longer exists
+	player := api.NewHTTPClient("http://127.0.0.1:1", "RADB", 5, logger)
+	player.SetRetryPolicy(api.RetryPolicy{MaxAttempts: 1})
+	player.Login(ctx, "replay", "")
+	if err := player.ReplayCassette(path); err != nil {
+		t.Fatalf("ReplayCassette() failed: %v", err)
+	}
+
+	replayed := &models.Contact{Name: "NOC", Email: "noc@example.com", Role: models.ContactRoleTech}
+	if err := player.CreateContact(ctx, replayed); err != nil {
+		t.Fatalf("replayed CreateContact() failed: %v", err)
+	}
+	if replayed.ID != contact.ID {
+		t.Errorf("replayed contact ID = %q, want %q", replayed.ID, contact.ID)
+	}
+
+	routes, err := player.ListRoutes(ctx, nil)
+	if err != nil {
+		t.Fatalf("replayed ListRoutes() failed: %v", err)
+	}
+	if routes.Count != recorded.Count || routes.Routes[0].ID() != recorded.Routes[0].ID() {
+		t.Errorf("replayed routes = %+v, want %+v", routes.Routes, recorded.Routes)
+	}
+
+	// Each recording is used once
+	if _, err := player.ListRoutes(ctx, nil); err == nil {
+		t.Error("ListRoutes() succeeded without an unused recording")
+	}
+}
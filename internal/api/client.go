@@ -3,13 +3,24 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/ratelimit"
+	"github.com/bss/radb-client/pkg/tracing"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // HTTPClient implements the Client interface using HTTP Basic Auth.
@@ -18,40 +29,102 @@ type HTTPClient struct {
 	source     string
 	timeout    time.Duration
 	httpClient *http.Client
+	transport  *http.Transport
 	logger     *logrus.Logger
 
 	// Authentication state
-	username string
-	password string
+	authMu        sync.RWMutex
+	username      string
+	password      string
 	authenticated bool
+	credSource    CredentialSource
+	signer        *RequestSigner // Replaces Basic Auth when set
 
-	// Rate limiting
-	rateLimiter *time.Ticker
+	// Rate limiting: endpoint classes without their own limiter share rateLimiter
+	rateLimiter      *ratelimit.AdaptiveLimiter
+	endpointLimiters map[EndpointClass]*ratelimit.AdaptiveLimiter
+	jobWeights       map[string]float64
+
+	// Retry behavior for transient failures
+	retry RetryPolicy
+
+	// Listing pagination
+	pageSize   int
+	maxResults int
+
+	// Compress large request bodies
+	compressRequests bool
+
+	// Response body limits in bytes, 0 for none
+	maxResponseSize int64
+	maxObjectSize   int64
+
+	// Directory for responses that fail to parse or validate
+	captureDir string
+
+	// Cached route and contact reads, nil when disabled
+	cache *ResponseCache
+
+	// Request instrumentation, nil when disabled
+	metrics *Metrics
+
+	// HTTP trace output, nil when disabled
+	trace *traceWriter
+
+	// Bulk operation progress callback
+	progress ProgressFunc
+
+	// Dry-run mode: withheld writes are passed to dryRun
+	dryRunMu sync.Mutex
+	dryRun   func(PlannedWrite)
+
+	// Sandbox mode: writes outside it are refused, nil when disabled
+	sandbox *Sandbox
+
+	// Recent request outcomes, reported by Status
+	statusMu sync.Mutex
+	status   models.ClientStatus
 }
 
+// CredentialSource supplies the current credentials for the client.
+// It is consulted when the API rejects a request with 401 Unauthorized,
+// so a password rotated in the keyring is picked up without a restart.
+type CredentialSource func(ctx context.Context) (username, password string, err error)
+
 // NewHTTPClient creates a new HTTP API client.
+// Proxy settings default to the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
 func NewHTTPClient(baseURL, source string, timeout int, logger *logrus.Logger) *HTTPClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
 	return &HTTPClient{
 		baseURL: baseURL,
 		source:  source,
 		timeout: time.Duration(timeout) * time.Second,
 		httpClient: &http.Client{
-			Timeout: time.Duration(timeout) * time.Second,
+			Timeout:   time.Duration(timeout) * time.Second,
+			Transport: transport,
 		},
+		transport:   transport,
 		logger:      logger,
-		rateLimiter: time.NewTicker(time.Second), // Simple rate limiting
+		rateLimiter: ratelimit.NewAdaptiveWithBurst(60, 1),
+		retry:       DefaultRetryPolicy(),
+
+		maxResponseSize: DefaultMaxResponseSize,
+		maxObjectSize:   DefaultMaxObjectSize,
 	}
 }
 
 // Login authenticates with the RADb API.
 func (c *HTTPClient) Login(ctx context.Context, username, password string) error {
 	// Store credentials
+	c.authMu.Lock()
 	c.username = username
 	c.password = password
 	c.authenticated = true
+	c.authMu.Unlock()
 
-	c.logger.Debugf("Credentials stored for %s (length: %d)", username, len(password))
-	c.logger.Debug("Credentials will be validated on first API request")
+	c.Tracef("auth: credentials set for %s; they are validated on the first API request", username)
 
 	// Note: We don't test auth here because most RADb API endpoints either:
 	// 1. Don't require auth (like ASN validation)
@@ -66,71 +139,247 @@ func (c *HTTPClient) Login(ctx context.Context, username, password string) error
 
 // Logout clears authentication state.
 func (c *HTTPClient) Logout(ctx context.Context) error {
+	c.authMu.Lock()
 	c.username = ""
 	c.password = ""
 	c.authenticated = false
+	c.authMu.Unlock()
 	c.logger.Info("Logged out")
 	return nil
 }
 
 // IsAuthenticated returns whether the client is authenticated.
 func (c *HTTPClient) IsAuthenticated() bool {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
 	return c.authenticated
 }
 
-// doRequest performs an HTTP request with retries and error handling.
-func (c *HTTPClient) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
-	// Rate limiting
-	select {
-	case <-c.rateLimiter.C:
-	case <-ctx.Done():
-		return nil, ctx.Err()
+// SetCredentialSource registers a source used to reload credentials when a
+// request is rejected with 401 Unauthorized.
+func (c *HTTPClient) SetCredentialSource(src CredentialSource) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	c.credSource = src
+}
+
+// credentials returns the current credentials and authentication state.
+func (c *HTTPClient) credentials() (username, password string, ok bool) {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+	return c.username, c.password, c.authenticated
+}
+
+// reauthenticate reloads credentials from the configured source.
+// It returns true if new credentials were loaded and the request should be retried.
+func (c *HTTPClient) reauthenticate(ctx context.Context) bool {
+	c.authMu.RLock()
+	src := c.credSource
+	c.authMu.RUnlock()
+
+	if src == nil {
+		return false
 	}
 
-	var bodyReader io.Reader
+	username, password, err := src(ctx)
+	if err != nil {
+		c.logger.Warnf("Failed to reload credentials after 401: %v", err)
+		return false
+	}
+
+	c.authMu.Lock()
+	c.username = username
+	c.password = password
+	c.authenticated = true
+	c.authMu.Unlock()
+
+	c.logger.Infof("Reloaded credentials for %s after 401 Unauthorized", username)
+	return true
+}
+
+// doRequest performs an HTTP request with retries and error handling.
+// A 401 response triggers a single credential reload and retry before it is returned.
+func (c *HTTPClient) doRequest(ctx context.Context, method, path string, body interface{}) (resp *http.Response, err error) {
+	endpoint := endpointLabel(path)
+	ctx, span := tracing.StartKind(ctx, method+" "+endpoint, tracing.KindClient,
+		tracing.String("http.request.method", method),
+		tracing.String("http.route", endpoint))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+			if isFailureStatus(resp.StatusCode) {
+				span.RecordError(errors.New(resp.Status))
+			}
+		}
+		span.EndErr(err)
+	}()
+
+	var payload *requestBody
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		bodyReader = bytes.NewReader(jsonData)
+		payload, err = c.encodeBody(jsonData)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	if resp, ok := c.withholdWrite(method, path, body); ok {
+		return resp, nil
+	}
+
+	if resp, ok := c.fromCache(ctx, method, path); ok {
+		return resp, nil
+	}
+
+	resp, err = c.sendWithRetries(ctx, method, path, payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		if ctx.Err() == nil {
+			if stale, ok := c.staleResponse(method, path, err.Error()); ok {
+				return stale, nil
+			}
+		}
+		return nil, err
 	}
 
-	// Set headers
-	if c.authenticated {
-		req.SetBasicAuth(c.username, c.password)
-		c.logger.Debugf("Set BasicAuth for request (user: %s)", c.username)
+	if resp.StatusCode == http.StatusUnauthorized && c.reauthenticate(ctx) {
+		resp.Body.Close()
+		c.logger.Debugf("Retrying %s %s with reloaded credentials", method, path)
+		resp, err = c.sendWithRetries(ctx, method, path, payload)
+		if err != nil {
+			return nil, err
+		}
 	}
-	req.Header.Set("Accept", "application/json")
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+
+	if resp.StatusCode >= 500 {
+		if stale, ok := c.staleResponse(method, path, resp.Status); ok {
+			resp.Body.Close()
+			return stale, nil
+		}
+	}
+
+	if err := decompressResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	c.limitBody(resp)
+
+	if err := c.updateCache(method, path, resp); err != nil {
+		return nil, err
 	}
 
-	// Execute request with retries
-	var resp *http.Response
-	maxRetries := 3
-	for i := 0; i < maxRetries; i++ {
-		resp, err = c.httpClient.Do(req)
-		if err == nil && resp.StatusCode < 500 {
-			break
+	return resp, nil
+}
+
+// sendWithRetries builds and executes a request, retrying transient failures
+// according to the client's retry policy. The request is rebuilt on every
+// attempt so the body is replayed in full.
+func (c *HTTPClient) sendWithRetries(ctx context.Context, method, path string, body *requestBody) (*http.Response, error) {
+	policy := c.retry
+	maxAttempts := max(policy.MaxAttempts, 1)
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		// Every attempt, retries included, counts against the rate limit
+		waitStart := time.Now()
+		if err := c.waitRateLimit(ctx, method, path); err != nil {
+			return nil, err
+		}
+		c.metrics.observeRateLimitWait(time.Since(waitStart))
+
+		req, err := c.newRequest(ctx, method, path, body)
+		if err != nil {
+			return nil, err
 		}
 
-		if i < maxRetries-1 {
-			c.logger.Warnf("Request failed (attempt %d/%d): %v", i+1, maxRetries, err)
-			time.Sleep(time.Duration(i+1) * time.Second)
+		sent := time.Now()
+		resp, err := c.httpClient.Do(req)
+		if ctx.Err() == nil {
+			c.observe(method, path, resp, err)
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			c.metrics.observeRequest(method, path, status, time.Since(sent))
+			c.adaptRateLimit(method, path, resp)
 		}
+		if ctx.Err() != nil || !shouldRetry(method, resp, err) || attempt >= maxAttempts {
+			if err != nil {
+				return nil, fmt.Errorf("request failed after %d attempts: %w", attempt, err)
+			}
+			return resp, nil
+		}
+
+		delay := policy.Backoff(attempt)
+		if after, ok := retryAfter(resp); ok {
+			delay = max(delay, after)
+		}
+
+		if policy.MaxElapsed > 0 && time.Since(start)+delay > policy.MaxElapsed {
+			c.logger.Warnf("Giving up on %s %s: retry budget of %s exhausted", method, path, policy.MaxElapsed)
+			if err != nil {
+				return nil, fmt.Errorf("request failed after %d attempts: %w", attempt, err)
+			}
+			return resp, nil
+		}
+
+		if err != nil {
+			c.logger.Warnf("Request failed (attempt %d/%d): %v; retrying in %s", attempt, maxAttempts, err, delay.Round(time.Millisecond))
+		} else {
+			c.logger.Warnf("Request failed (attempt %d/%d): %s; retrying in %s", attempt, maxAttempts, resp.Status, delay.Round(time.Millisecond))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		c.metrics.observeRetry(method, path)
+	}
+}
+
+// newRequest creates an HTTP request with authentication and content headers set.
+func (c *HTTPClient) newRequest(ctx context.Context, method, path string, body *requestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body.data)
 	}
 
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries, err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	return resp, nil
+	// Set headers
+	c.authMu.RLock()
+	signer := c.signer
+	c.authMu.RUnlock()
+	if signer == nil {
+		if username, password, ok := c.credentials(); ok {
+			req.SetBasicAuth(username, password)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	tracing.Inject(ctx, req.Header)
+	var data []byte
+	if body != nil {
+		data = body.data
+		req.Header.Set("Content-Type", "application/json")
+		if body.encoding != "" {
+			req.Header.Set("Content-Encoding", body.encoding)
+		}
+	}
+	if signer != nil {
+		signer.Sign(req, data)
+	}
+
+	return req, nil
 }
 
 // Actual implementations are in routes.go, contacts.go, and search.go
@@ -140,6 +389,11 @@ func (c *HTTPClient) SetBaseURL(url string) {
 	c.baseURL = url
 }
 
+// SetRetryPolicy updates how transient failures are retried.
+func (c *HTTPClient) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
 // SetSource updates the source.
 func (c *HTTPClient) SetSource(source string) {
 	c.source = source
@@ -150,3 +404,81 @@ func (c *HTTPClient) SetTimeout(seconds int) {
 	c.timeout = time.Duration(seconds) * time.Second
 	c.httpClient.Timeout = c.timeout
 }
+
+// SetProxy routes requests through an explicit proxy, overriding the environment.
+// Supported schemes are http, https, and socks5. Hosts matching an entry in
+// noProxy (same syntax as NO_PROXY) are contacted directly. An empty proxyURL
+// restores the environment-based default.
+func (c *HTTPClient) SetProxy(proxyURL string, noProxy []string) error {
+	if proxyURL == "" {
+		c.transport.Proxy = http.ProxyFromEnvironment
+		return nil
+	}
+
+	parsed, err := url.Parse(proxyURL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q (use http, https, or socks5)", parsed.Scheme)
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    strings.Join(noProxy, ","),
+	}).ProxyFunc()
+
+	c.transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+
+	c.logger.Debugf("Using proxy %s://%s", parsed.Scheme, parsed.Host)
+	return nil
+}
+
+// SetTLS configures the TLS settings used for API connections.
+// caFile adds a PEM bundle to the system trust store, certFile and keyFile
+// enable mutual TLS, and minVersion ("1.2" or "1.3") raises the minimum
+// protocol version. Empty arguments keep the defaults.
+func (c *HTTPClient) SetTLS(caFile, certFile, keyFile, minVersion string) error {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	switch minVersion {
+	case "", "1.2":
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return fmt.Errorf("unsupported TLS version %q (use 1.2 or 1.3)", minVersion)
+	}
+
+	if caFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	c.transport.TLSClientConfig = tlsConfig
+	return nil
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestReauthenticateOn401(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, password, ok := r.BasicAuth()
+		if !ok || password != "rotated" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.Login(context.Background(), "user", "stale")
+
+	reloads := 0
+	client.SetCredentialSource(func(ctx context.Context) (string, string, error) {
+		reloads++
+		return "user", "rotated", nil
+	})
+
+	routes, err := client.ListRoutes(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if routes.Count != 0 {
+		t.Errorf("Expected 0 routes, got %d", routes.Count)
+	}
+	if reloads != 1 {
+		t.Errorf("Expected 1 credential reload, got %d", reloads)
+	}
+}
+
+func TestReauthenticateWithoutSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.Login(context.Background(), "user", "stale")
+
+	if _, err := client.ListRoutes(context.Background(), nil); err == nil {
+		t.Error("Expected error when credentials are rejected")
+	}
+}
+
+func TestSetProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient("http://api.radb.example", "RADB", 5, logger)
+	if err := client.SetProxy(proxy.URL, []string{"internal.example"}); err != nil {
+		t.Fatalf("SetProxy() failed: %v", err)
+	}
+	client.Login(context.Background(), "user", "secret")
+
+	if _, err := client.ListRoutes(context.Background(), nil); err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if proxied != "http://api.radb.example/RADB/route" {
+		t.Errorf("Expected request via proxy, proxy saw %q", proxied)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://internal.example/RADB/route", nil)
+	proxyURL, err := client.transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy() failed: %v", err)
+	}
+	if proxyURL != nil {
+		t.Errorf("Expected no_proxy host to bypass proxy, got %v", proxyURL)
+	}
+}
+
+func TestSetProxyRejectsUnsupportedScheme(t *testing.T) {
+	client := NewHTTPClient("http://api.radb.example", "RADB", 5, logrus.New())
+	if err := client.SetProxy("ftp://proxy.example.com", nil); err == nil {
+		t.Error("Expected error for unsupported proxy scheme")
+	}
+}
+
+func TestSetTLSCustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// Without the CA bundle the self-signed test certificate is rejected
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.Login(context.Background(), "user", "secret")
+	if _, err := client.ListRoutes(context.Background(), nil); err == nil {
+		t.Fatal("Expected certificate verification failure")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.SetTLS(caFile, "", "", "1.2"); err != nil {
+		t.Fatalf("SetTLS() failed: %v", err)
+	}
+	if _, err := client.ListRoutes(context.Background(), nil); err != nil {
+		t.Errorf("ListRoutes() with custom CA failed: %v", err)
+	}
+}
+
+func TestSetTLSInvalidInput(t *testing.T) {
+	client := NewHTTPClient("https://api.radb.example", "RADB", 5, logrus.New())
+
+	if err := client.SetTLS("", "", "", "1.1"); err == nil {
+		t.Error("Expected error for unsupported TLS version")
+	}
+	if err := client.SetTLS(filepath.Join(t.TempDir(), "missing.pem"), "", "", ""); err == nil {
+		t.Error("Expected error for missing CA bundle")
+	}
+	if err := client.SetTLS("", "client.pem", "", ""); err == nil {
+		t.Error("Expected error for client certificate without key")
+	}
+}
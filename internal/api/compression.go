@@ -0,0 +1,81 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// minCompressSize is the smallest request body worth compressing.
+const minCompressSize = 1024
+
+// requestBody is an encoded request payload, replayed on every attempt.
+type requestBody struct {
+	data     []byte
+	encoding string // Content-Encoding, empty for identity
+}
+
+// SetRequestCompression enables gzip compression of request bodies larger
+// than 1 KiB. Only enable it for servers that accept Content-Encoding: gzip.
+func (c *HTTPClient) SetRequestCompression(enabled bool) {
+	c.compressRequests = enabled
+}
+
+// encodeBody wraps a JSON payload, compressing it when enabled and large enough.
+func (c *HTTPClient) encodeBody(jsonData []byte) (*requestBody, error) {
+	if !c.compressRequests || len(jsonData) < minCompressSize {
+		return &requestBody{data: jsonData}, nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(jsonData); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	c.logger.Debugf("Compressed request body from %d to %d bytes", len(jsonData), buf.Len())
+	return &requestBody{data: buf.Bytes(), encoding: "gzip"}, nil
+}
+
+// decompressResponse transparently decodes a gzip-encoded response body.
+// Because requests set Accept-Encoding explicitly, the transport leaves
+// decoding to us.
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err == io.EOF {
+		// Empty body, e.g. 204 No Content
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decompress response: %w", err)
+	}
+
+	resp.Body = &gzipBody{Reader: gz, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody closes both the gzip reader and the underlying response body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the gzip reader and the underlying body.
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestGzipResponseDecoded(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		json.NewEncoder(gz).Encode([]models.RouteObject{
+			{Route: "192.0.2.0/24", Origin: "AS64500"},
+			{Route: "198.51.100.0/24", Origin: "AS64500"},
+		})
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+
+	routes, err := client.ListRoutes(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListRoutes failed: %v", err)
+	}
+	if acceptEncoding != "gzip" {
+		t.Errorf("Accept-Encoding = %q, want gzip", acceptEncoding)
+	}
+	if routes.Count != 2 {
+		t.Errorf("got %d routes, want 2", routes.Count)
+	}
+}
+
+func TestRequestCompression(t *testing.T) {
+	descr := strings.Repeat("x", 2*minCompressSize)
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		descr    string
+		wantGzip bool
+	}{
+		{name: "disabled", enabled: false, descr: descr, wantGzip: false},
+		{name: "small body", enabled: true, descr: "short", wantGzip: false},
+		{name: "large body", enabled: true, descr: descr, wantGzip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var encoding string
+			var received models.RouteObject
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding = r.Header.Get("Content-Encoding")
+
+				var body io.Reader = r.Body
+				if encoding == "gzip" {
+					gz, err := gzip.NewReader(r.Body)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+					body = gz
+				}
+				data, _ := io.ReadAll(body)
+				json.Unmarshal(data, &received)
+
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer server.Close()
+
+			client := newPagedClient(t, server.URL)
+			client.SetRequestCompression(tt.enabled)
+
+			route := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{tt.descr}, MntBy: []string{"MAINT-EXAMPLE"}, Source: "RADB"}
+			if err := client.CreateRoute(context.Background(), route); err != nil {
+				t.Fatalf("CreateRoute failed: %v", err)
+			}
+
+			if got := encoding == "gzip"; got != tt.wantGzip {
+				t.Errorf("Content-Encoding = %q, want gzip %v", encoding, tt.wantGzip)
+			}
+			if len(received.Descr) != 1 || received.Descr[0] != tt.descr {
+				t.Errorf("server did not receive the original body")
+			}
+		})
+	}
+}
+
+func TestDecompressResponseEmptyBody(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:   io.NopCloser(bytes.NewReader(nil)),
+	}
+	if err := decompressResponse(resp); err != nil {
+		t.Fatalf("decompressResponse failed: %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/api/apitest"
+	"github.com/sirupsen/logrus"
+)
+
+func TestHTTPClientConformance(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	apitest.RunClientTests(t, func(t *testing.T) api.Client {
+		server := apitest.NewServer()
+		t.Cleanup(server.Close)
+
+		client := api.NewHTTPClient(server.URL, "RADB", 5, logger)
+		if err := client.Login(context.Background(), "user", "password"); err != nil {
+			t.Fatalf("Login() failed: %v", err)
+		}
+		return client
+	})
+}
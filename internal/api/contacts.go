@@ -12,40 +12,47 @@ import (
 	"github.com/bss/radb-client/pkg/validator"
 )
 
-// ListContacts retrieves all contacts with optional role-based filtering.
+// ListContacts retrieves all contacts, following server pagination until the
+// last page or the configured result cap. A list cut short by the cap is
+// marked Truncated.
 func (c *HTTPClient) ListContacts(ctx context.Context) (*models.ContactList, error) {
 	c.logger.Debug("ListContacts called")
 
-	if !c.authenticated {
-		return nil, fmt.Errorf("not authenticated: please login first")
-	}
-
-	path := fmt.Sprintf("/%s/contact", c.source)
-	resp, err := c.doRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list contacts: %w", err)
-	}
-	defer resp.Body.Close()
+	var contacts []models.Contact
+	truncated := false
+	cursor := ""
+	for {
+		page, next, err := c.ListContactsPage(ctx, c.pageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, page.Contacts...)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("list contacts failed with status %d: %s", resp.StatusCode, string(body))
+		if c.maxResults > 0 && len(contacts) >= c.maxResults {
+			truncated = len(contacts) > c.maxResults || next != ""
+			contacts = contacts[:c.maxResults]
+			break
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
 	}
 
-	var contacts []models.Contact
-	if err := json.NewDecoder(resp.Body).Decode(&contacts); err != nil {
-		return nil, fmt.Errorf("failed to decode contacts response: %w", err)
+	list := models.NewContactList(contacts)
+	list.Truncated = truncated
+	if truncated {
+		c.logger.Warnf("Contact listing truncated at %d results (api.max_results)", c.maxResults)
 	}
-
 	c.logger.Infof("Retrieved %d contacts", len(contacts))
-	return models.NewContactList(contacts), nil
+	return list, nil
 }
 
 // GetContact retrieves a specific contact by ID.
 func (c *HTTPClient) GetContact(ctx context.Context, id string) (*models.Contact, error) {
 	c.logger.Debugf("GetContact called for %s", id)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return nil, fmt.Errorf("not authenticated: please login first")
 	}
 
@@ -61,7 +68,7 @@ func (c *HTTPClient) GetContact(ctx context.Context, id string) (*models.Contact
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("contact not found: %s", id)
+		return nil, fmt.Errorf("contact %w: %s", ErrNotFound, id)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -70,8 +77,8 @@ func (c *HTTPClient) GetContact(ctx context.Context, id string) (*models.Contact
 	}
 
 	var contact models.Contact
-	if err := json.NewDecoder(resp.Body).Decode(&contact); err != nil {
-		return nil, fmt.Errorf("failed to decode contact response: %w", err)
+	if err := c.decodeResponse(resp, "contact", &contact, contact.Validate); err != nil {
+		return nil, err
 	}
 
 	c.logger.Infof("Retrieved contact %s", contact.ID)
@@ -82,9 +89,12 @@ func (c *HTTPClient) GetContact(ctx context.Context, id string) (*models.Contact
 func (c *HTTPClient) CreateContact(ctx context.Context, contact *models.Contact) error {
 	c.logger.Debugf("CreateContact called for %s", contact.ID)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return fmt.Errorf("not authenticated: please login first")
 	}
+	if err := c.sandbox.checkContact(); err != nil {
+		return err
+	}
 
 	// Validate the contact
 	if err := contact.Validate(); err != nil {
@@ -124,13 +134,16 @@ func (c *HTTPClient) CreateContact(ctx context.Context, contact *models.Contact)
 func (c *HTTPClient) UpdateContact(ctx context.Context, contact *models.Contact) error {
 	c.logger.Debugf("UpdateContact called for %s", contact.ID)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return fmt.Errorf("not authenticated: please login first")
 	}
 
 	if contact.ID == "" {
 		return fmt.Errorf("contact ID is required for update")
 	}
+	if err := c.sandbox.checkContact(); err != nil {
+		return err
+	}
 
 	// Validate the contact
 	if err := contact.Validate(); err != nil {
@@ -150,7 +163,7 @@ func (c *HTTPClient) UpdateContact(ctx context.Context, contact *models.Contact)
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("contact not found: %s", contact.ID)
+		return fmt.Errorf("contact %w: %s", ErrNotFound, contact.ID)
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
@@ -166,13 +179,16 @@ func (c *HTTPClient) UpdateContact(ctx context.Context, contact *models.Contact)
 func (c *HTTPClient) DeleteContact(ctx context.Context, id string) error {
 	c.logger.Debugf("DeleteContact called for %s", id)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return fmt.Errorf("not authenticated: please login first")
 	}
 
 	if id == "" {
 		return fmt.Errorf("contact ID is required")
 	}
+	if err := c.sandbox.checkContact(); err != nil {
+		return err
+	}
 
 	path := fmt.Sprintf("/%s/contact/%s", c.source, url.PathEscape(id))
 	resp, err := c.doRequest(ctx, "DELETE", path, nil)
@@ -182,7 +198,7 @@ func (c *HTTPClient) DeleteContact(ctx context.Context, id string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("contact not found: %s", id)
+		return fmt.Errorf("contact %w: %s", ErrNotFound, id)
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strings"
+)
+
+// PlannedWrite describes a write request withheld in dry-run mode.
+type PlannedWrite struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Object interface{} `json:"object,omitempty"` // Request payload before encoding, nil for deletes
+}
+
+// DryRunClient is implemented by clients that can validate writes without sending them.
+type DryRunClient interface {
+	// SetDryRun withholds write requests after local validation, passing each
+	// to report instead. Reads are still performed. A nil report disables
+	// dry-run mode.
+	SetDryRun(report func(PlannedWrite))
+}
+
+// Ensure HTTPClient implements DryRunClient.
+var _ DryRunClient = (*HTTPClient)(nil)
+
+// SetDryRun enables or disables dry-run mode.
+// Batch operations run through the same path, so they honor it too.
+func (c *HTTPClient) SetDryRun(report func(PlannedWrite)) {
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+	c.dryRun = report
+}
+
+// withholdWrite reports a write request in dry-run mode and returns the
+// response that stands in for it. ok is false if the request should be sent.
+func (c *HTTPClient) withholdWrite(method, path string, body interface{}) (resp *http.Response, ok bool) {
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return nil, false
+	}
+
+	// Held for the whole report so output from parallel batch workers does not interleave
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+	if c.dryRun == nil {
+		return nil, false
+	}
+
+	c.logger.Debugf("Dry run: withholding %s %s", method, path)
+	c.dryRun(PlannedWrite{Method: method, URL: c.baseURL + path, Object: body})
+
+	return &http.Response{
+		Status:     "200 OK (dry run)",
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, true
+}
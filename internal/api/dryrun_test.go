@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestDryRunWithholdsWrites(t *testing.T) {
+	var writes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writes.Add(1)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+
+	var planned []PlannedWrite
+	client.SetDryRun(func(write PlannedWrite) {
+		planned = append(planned, write)
+	})
+
+	ctx := context.Background()
+	route := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-EXAMPLE"}, Source: "RADB"}
+	if err := client.CreateRoute(ctx, route); err != nil {
+		t.Fatalf("CreateRoute failed: %v", err)
+	}
+	if err := client.DeleteRoute(ctx, "192.0.2.0/24", "AS64500"); err != nil {
+		t.Fatalf("DeleteRoute failed: %v", err)
+	}
+
+	// Local validation still runs
+	if err := client.CreateRoute(ctx, &models.RouteObject{Route: "not-a-prefix", Origin: "AS64500", MntBy: []string{"MAINT-EXAMPLE"}, Source: "RADB"}); err == nil {
+		t.Error("CreateRoute accepted an invalid prefix in dry-run mode")
+	}
+
+	if n := writes.Load(); n != 0 {
+		t.Errorf("server received %d writes in dry-run mode", n)
+	}
+	if len(planned) != 2 {
+		t.Fatalf("got %d planned writes, want 2", len(planned))
+	}
+	if planned[0].Method != "POST" || planned[0].URL != server.URL+"/RADB/route" || planned[0].Object != route {
+		t.Errorf("planned create = %+v", planned[0])
+	}
+	if planned[1].Method != "DELETE" || planned[1].Object != nil {
+		t.Errorf("planned delete = %+v", planned[1])
+	}
+
+	// Batch operations go through the same path
+	result, err := client.BatchCreateRoutes(ctx, []*models.RouteObject{route}, 1)
+	if err != nil || result.Succeeded != 1 {
+		t.Fatalf("BatchCreateRoutes = %+v, %v", result, err)
+	}
+	if len(planned) != 3 || writes.Load() != 0 {
+		t.Errorf("batch create was not withheld: %d planned, %d sent", len(planned), writes.Load())
+	}
+
+	// Disabling dry-run sends writes again
+	client.SetDryRun(nil)
+	if err := client.CreateRoute(ctx, route); err != nil {
+		t.Fatalf("CreateRoute failed: %v", err)
+	}
+	if n := writes.Load(); n != 1 {
+		t.Errorf("server received %d writes after disabling dry-run, want 1", n)
+	}
+}
@@ -3,6 +3,7 @@ package api
 
 import (
 	"context"
+	"errors"
 
 	"github.com/bss/radb-client/internal/models"
 )
@@ -37,3 +38,27 @@ type Client interface {
 	SetSource(source string)
 	SetTimeout(seconds int)
 }
+
+// ErrNotFound is wrapped by the errors returned for objects that do not exist.
+var ErrNotFound = errors.New("not found")
+
+// BatchClient is implemented by clients that support parallel bulk operations.
+type BatchClient interface {
+	BatchCreateRoutes(ctx context.Context, routes []*models.RouteObject, workers int) (*BulkResult, error)
+	BatchUpdateRoutes(ctx context.Context, routes []*models.RouteObject, workers int) (*BulkResult, error)
+	BatchDeleteRoutes(ctx context.Context, routes []RouteIdentifier, workers int) (*BulkResult, error)
+}
+
+// ContactBatchClient is implemented by clients that support parallel bulk contact operations.
+type ContactBatchClient interface {
+	BatchCreateContacts(ctx context.Context, contacts []*models.Contact, workers int) (*BulkResult, error)
+	BatchUpdateContacts(ctx context.Context, contacts []*models.Contact, workers int) (*BulkResult, error)
+	BatchDeleteContacts(ctx context.Context, ids []string, workers int) (*BulkResult, error)
+}
+
+// Ensure HTTPClient implements Client, BatchClient, and ContactBatchClient.
+var (
+	_ Client             = (*HTTPClient)(nil)
+	_ BatchClient        = (*HTTPClient)(nil)
+	_ ContactBatchClient = (*HTTPClient)(nil)
+)
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxResponseSize caps a decoded response body unless
+// SetResponseLimits chooses otherwise.
+const DefaultMaxResponseSize = 64 << 20
+
+// DefaultMaxObjectSize caps a single route decoded from a streamed listing
+// unless SetResponseLimits chooses otherwise.
+const DefaultMaxObjectSize = 1 << 20
+
+// ErrResponseTooLarge is returned when reading a response body that exceeds
+// the client's response limits.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// SetResponseLimits caps how many bytes a response body may decode to and,
+// for listings read with OpenRoutesPage, how many bytes a single streamed
+// route may take instead. A malformed or unexpectedly huge response then
+// fails with ErrResponseTooLarge rather than exhausting memory. Zero
+// disables a limit.
+func (c *HTTPClient) SetResponseLimits(maxResponse, maxObject int64) {
+	c.maxResponseSize = maxResponse
+	c.maxObjectSize = maxObject
+}
+
+// limitBody wraps the body of a response so reading more than the response
+// limit fails.
+func (c *HTTPClient) limitBody(resp *http.Response) {
+	if c.maxResponseSize <= 0 && c.maxObjectSize <= 0 {
+		return
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, limit: c.maxResponseSize}
+}
+
+// limitedBody fails reads past limit bytes with ErrResponseTooLarge. In
+// streaming mode the limit counts from the start of the object being
+// decoded, so it bounds each object rather than the whole response.
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	read      int64 // Bytes read so far
+	mark      int64 // Offset the limit counts from
+	streaming bool
+}
+
+// stream switches the body to streaming mode with a per-object limit.
+func (b *limitedBody) stream(limit int64) {
+	b.limit = limit
+	b.mark = b.read
+	b.streaming = true
+}
+
+// next starts counting the next streamed object at offset, the decoder's
+// position in the body. Bytes read ahead of offset count toward the object.
+func (b *limitedBody) next(offset int64) {
+	b.mark = offset
+}
+
+// Read reads from the body. A read once more than limit bytes have been
+// read from the mark means the response, or the object being decoded, is
+// larger than the limit.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.limit > 0 {
+		remaining := b.mark + b.limit + 1 - b.read
+		if remaining <= 0 {
+			return 0, b.tooLarge()
+		}
+		if int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *limitedBody) tooLarge() error {
+	if b.streaming {
+		return fmt.Errorf("%w: a single object exceeded %d bytes (api.max_object_size); the listing is likely malformed", ErrResponseTooLarge, b.limit)
+	}
+	return fmt.Errorf("%w: the response exceeded %d bytes (api.max_response_size); use 'route list --stream' or batch operations for large result sets, or raise the limit", ErrResponseTooLarge, b.limit)
+}
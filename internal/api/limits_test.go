@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// routeListingServer serves count routes, each with a description of descr
+// bytes, in a single listing page.
+func routeListingServer(t *testing.T, count, descr int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var routes []string
+		for i := 0; i < count; i++ {
+			routes = append(routes, fmt.Sprintf(`{"route":"10.0.%d.0/24","origin":"AS64500","descr":[%q]}`, i, strings.Repeat("x", descr)))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"count":%d,"results":[%s]}`, count, strings.Join(routes, ","))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResponseSizeLimit(t *testing.T) {
+	server := routeListingServer(t, 100, 100)
+	client := newPagedClient(t, server.URL)
+
+	client.SetResponseLimits(4096, 0)
+	_, err := client.ListRoutes(context.Background(), nil)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("ListRoutes() error = %v, want ErrResponseTooLarge", err)
+	}
+	if !strings.Contains(err.Error(), "--stream") {
+		t.Errorf("error %q does not point to streaming", err)
+	}
+
+	client.SetResponseLimits(1<<20, 0)
+	routes, err := client.ListRoutes(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() under the limit failed: %v", err)
+	}
+	if routes.Count != 100 {
+		t.Errorf("ListRoutes() returned %d routes, want 100", routes.Count)
+	}
+}
+
+func TestStreamedListingIgnoresResponseLimit(t *testing.T) {
+	server := routeListingServer(t, 100, 100)
+	client := newPagedClient(t, server.URL)
+	client.SetResponseLimits(4096, 1024)
+
+	stream := client.StreamRoutes(context.Background(), nil, 10)
+	defer stream.Close()
+
+	count := 0
+	for stream.Next() {
+		count++
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if count != 100 {
+		t.Errorf("streamed %d routes, want 100", count)
+	}
+}
+
+func TestStreamedListingObjectLimit(t *testing.T) {
+	server := routeListingServer(t, 3, 8192)
+	client := newPagedClient(t, server.URL)
+	client.SetResponseLimits(0, 1024)
+
+	reader, err := client.OpenRoutesPage(context.Background(), nil, 0, "")
+	if err != nil {
+		t.Fatalf("OpenRoutesPage() failed: %v", err)
+	}
+	defer reader.Close()
+
+	for reader.Next() {
+	}
+	if !errors.Is(reader.Err(), ErrResponseTooLarge) {
+		t.Errorf("Err() = %v, want ErrResponseTooLarge", reader.Err())
+	}
+}
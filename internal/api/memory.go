@@ -0,0 +1,389 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/validator"
+	"github.com/sirupsen/logrus"
+)
+
+// Fixture is the file format loaded by MemoryClient.LoadFixture.
+type Fixture struct {
+	Routes   []models.RouteObject `json:"routes"`
+	Contacts []models.Contact     `json:"contacts"`
+}
+
+// MemoryClient is an in-memory Client for offline use and testing. It needs
+// no network or credentials; writes change only the in-memory data.
+type MemoryClient struct {
+	mu          sync.RWMutex
+	routes      map[string]models.RouteObject
+	contacts    map[string]models.Contact
+	nextContact int
+	source      string
+	sandbox     *Sandbox
+	logger      *logrus.Logger
+}
+
+// Ensure MemoryClient implements Client.
+var _ Client = (*MemoryClient)(nil)
+
+// NewMemoryClient creates an empty in-memory client.
+func NewMemoryClient(source string, logger *logrus.Logger) *MemoryClient {
+	return &MemoryClient{
+		routes:   make(map[string]models.RouteObject),
+		contacts: make(map[string]models.Contact),
+		source:   source,
+		logger:   logger,
+	}
+}
+
+// LoadFixture adds the routes and contacts from a JSON fixture file.
+func (c *MemoryClient) LoadFixture(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, route := range fixture.Routes {
+		c.routes[route.ID()] = route
+	}
+	for _, contact := range fixture.Contacts {
+		c.contacts[contact.ID] = contact
+	}
+
+	c.logger.Debugf("Loaded %d routes and %d contacts from %s", len(fixture.Routes), len(fixture.Contacts), path)
+	return nil
+}
+
+// LoadSnapshot adds the routes and contacts recorded in a snapshot.
+func (c *MemoryClient) LoadSnapshot(snapshot *models.Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if snapshot.Routes != nil {
+		for _, route := range snapshot.Routes.Routes {
+			c.routes[route.ID()] = route
+		}
+	}
+	if snapshot.Contacts != nil {
+		for _, contact := range snapshot.Contacts.Contacts {
+			c.contacts[contact.ID] = contact
+		}
+	}
+
+	c.logger.Debugf("Loaded snapshot %s", snapshot.ID)
+}
+
+// Login always succeeds; the in-memory client needs no credentials.
+func (c *MemoryClient) Login(ctx context.Context, username, password string) error {
+	return nil
+}
+
+// Logout is a no-op.
+func (c *MemoryClient) Logout(ctx context.Context) error {
+	return nil
+}
+
+// IsAuthenticated always returns true.
+func (c *MemoryClient) IsAuthenticated() bool {
+	return true
+}
+
+// ListRoutes returns the routes matching the filters, sorted by ID.
+func (c *MemoryClient) ListRoutes(ctx context.Context, filters map[string]string) (*models.RouteList, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	routes := make([]models.RouteObject, 0, len(c.routes))
+	for _, route := range c.routes {
+		if route.MatchesFilters(filters) {
+			routes = append(routes, route)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].ID() < routes[j].ID()
+	})
+
+	return models.NewRouteList(routes), nil
+}
+
+// GetRoute returns a route by prefix and origin ASN.
+func (c *MemoryClient) GetRoute(ctx context.Context, prefix, asn string) (*models.RouteObject, error) {
+	if !strings.HasPrefix(asn, "AS") {
+		asn = "AS" + asn
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	route, ok := c.routes[fmt.Sprintf("%s-%s", prefix, asn)]
+	if !ok {
+		return nil, fmt.Errorf("route %w: %s %s", ErrNotFound, prefix, asn)
+	}
+	return &route, nil
+}
+
+// CreateRoute validates and stores a new route.
+func (c *MemoryClient) CreateRoute(ctx context.Context, route *models.RouteObject) error {
+	if route.Source == "" {
+		route.Source = c.source
+	}
+	if err := c.sandbox.checkRoute(route); err != nil {
+		return err
+	}
+	if err := validateRoute(route); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.routes[route.ID()]; exists {
+		return fmt.Errorf("route already exists: %s", route.ID())
+	}
+	c.routes[route.ID()] = *route
+	return nil
+}
+
+// UpdateRoute replaces an existing route.
+func (c *MemoryClient) UpdateRoute(ctx context.Context, route *models.RouteObject) error {
+	if err := c.sandbox.checkRoute(route); err != nil {
+		return err
+	}
+	if err := validateRoute(route); err != nil {
+		return err
+	}
+	if err := c.sandbox.checkExistingRoute(ctx, c, route.Route, route.Origin); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.routes[route.ID()]; !exists {
+		return fmt.Errorf("route %w: %s", ErrNotFound, route.ID())
+	}
+	c.routes[route.ID()] = *route
+	return nil
+}
+
+// DeleteRoute removes a route.
+func (c *MemoryClient) DeleteRoute(ctx context.Context, prefix, asn string) error {
+	if !strings.HasPrefix(asn, "AS") {
+		asn = "AS" + asn
+	}
+	if err := c.sandbox.checkExistingRoute(ctx, c, prefix, asn); err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s-%s", prefix, asn)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.routes[id]; !exists {
+		return fmt.Errorf("route %w: %s %s", ErrNotFound, prefix, asn)
+	}
+	delete(c.routes, id)
+	return nil
+}
+
+// ListContacts returns all contacts, sorted by ID.
+func (c *MemoryClient) ListContacts(ctx context.Context) (*models.ContactList, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	contacts := make([]models.Contact, 0, len(c.contacts))
+	for _, contact := range c.contacts {
+		contacts = append(contacts, contact)
+	}
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].ID < contacts[j].ID
+	})
+
+	return models.NewContactList(contacts), nil
+}
+
+// GetContact returns a contact by ID.
+func (c *MemoryClient) GetContact(ctx context.Context, id string) (*models.Contact, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	contact, ok := c.contacts[id]
+	if !ok {
+		return nil, fmt.Errorf("contact %w: %s", ErrNotFound, id)
+	}
+	return &contact, nil
+}
+
+// CreateContact validates and stores a new contact, assigning an ID if it has none.
+func (c *MemoryClient) CreateContact(ctx context.Context, contact *models.Contact) error {
+	if err := c.sandbox.checkContact(); err != nil {
+		return err
+	}
+	if err := validateContact(contact); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if contact.ID == "" {
+		for {
+			c.nextContact++
+			contact.ID = fmt.Sprintf("CONTACT-%d", c.nextContact)
+			if _, exists := c.contacts[contact.ID]; !exists {
+				break
+			}
+		}
+	} else if _, exists := c.contacts[contact.ID]; exists {
+		return fmt.Errorf("contact already exists: %s", contact.ID)
+	}
+	c.contacts[contact.ID] = *contact
+	return nil
+}
+
+// UpdateContact replaces an existing contact.
+func (c *MemoryClient) UpdateContact(ctx context.Context, contact *models.Contact) error {
+	if err := c.sandbox.checkContact(); err != nil {
+		return err
+	}
+	if err := validateContact(contact); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.contacts[contact.ID]; !exists {
+		return fmt.Errorf("contact %w: %s", ErrNotFound, contact.ID)
+	}
+	c.contacts[contact.ID] = *contact
+	return nil
+}
+
+// DeleteContact removes a contact.
+func (c *MemoryClient) DeleteContact(ctx context.Context, id string) error {
+	if err := c.sandbox.checkContact(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.contacts[id]; !exists {
+		return fmt.Errorf("contact %w: %s", ErrNotFound, id)
+	}
+	delete(c.contacts, id)
+	return nil
+}
+
+// Search matches routes by prefix, origin, or maintainer and contacts by
+// name or email.
+func (c *MemoryClient) Search(ctx context.Context, query string, objectType string) (interface{}, error) {
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results := make([]map[string]interface{}, 0)
+	if objectType == "" || objectType == "route" || objectType == "route6" {
+		for _, route := range c.routes {
+			if strings.Contains(route.Route, query) || strings.EqualFold(route.Origin, query) || containsFold(route.MntBy, query) {
+				results = append(results, map[string]interface{}{
+					"type":   "route",
+					"route":  route.Route,
+					"origin": route.Origin,
+				})
+			}
+		}
+	}
+	if objectType == "" || objectType == "contact" {
+		for _, contact := range c.contacts {
+			if strings.Contains(strings.ToLower(contact.Name), strings.ToLower(query)) || strings.EqualFold(contact.Email, query) {
+				results = append(results, map[string]interface{}{
+					"type":  "contact",
+					"id":    contact.ID,
+					"name":  contact.Name,
+					"email": contact.Email,
+				})
+			}
+		}
+	}
+
+	return &SearchResult{
+		Results: results,
+		Count:   len(results),
+		Query:   query,
+		Type:    objectType,
+	}, nil
+}
+
+// ValidateASN checks the ASN format only.
+func (c *MemoryClient) ValidateASN(ctx context.Context, asn string) (bool, error) {
+	if err := validator.ValidateASN(asn); err != nil {
+		return false, fmt.Errorf("invalid ASN format: %w", err)
+	}
+	return true, nil
+}
+
+// SetBaseURL is a no-op.
+func (c *MemoryClient) SetBaseURL(url string) {}
+
+// SetSource sets the source assigned to new routes.
+func (c *MemoryClient) SetSource(source string) {
+	c.source = source
+}
+
+// SetTimeout is a no-op.
+func (c *MemoryClient) SetTimeout(seconds int) {}
+
+// validateRoute applies the same checks as the HTTP client before a write.
+func validateRoute(route *models.RouteObject) error {
+	if err := route.Validate(); err != nil {
+		return fmt.Errorf("route validation failed: %w", err)
+	}
+	if err := validator.ValidatePrefix(route.Route); err != nil {
+		return fmt.Errorf("invalid prefix %s: %w", route.Route, err)
+	}
+	if err := validator.ValidateASN(route.Origin); err != nil {
+		return fmt.Errorf("invalid origin ASN %s: %w", route.Origin, err)
+	}
+	return nil
+}
+
+// validateContact applies the same checks as the HTTP client before a write.
+func validateContact(contact *models.Contact) error {
+	if err := contact.Validate(); err != nil {
+		return fmt.Errorf("contact validation failed: %w", err)
+	}
+	if err := validator.ValidateEmail(contact.Email); err != nil {
+		return fmt.Errorf("invalid email %s: %w", contact.Email, err)
+	}
+	return nil
+}
+
+// containsFold reports whether values contains target, ignoring case.
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
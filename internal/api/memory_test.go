@@ -0,0 +1,78 @@
+package api_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/api/apitest"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestMemoryClientConformance(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	apitest.RunClientTests(t, func(t *testing.T) api.Client {
+		return api.NewMemoryClient("RADB", logger)
+	})
+}
+
+func TestMemoryClientLoad(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	data := `{
+  "routes": [
+    {"route": "192.0.2.0/24", "origin": "AS64500", "mnt_by": ["MAINT-A"], "source": "RADB"},
+    {"route": "198.51.100.0/24", "origin": "AS64501", "mnt_by": ["MAINT-B"], "source": "RADB"}
+  ],
+  "contacts": [
+    {"id": "CONTACT-1", "name": "NOC", "email": "noc@example.com", "role": "tech"}
+  ]
+}`
+	if err := os.WriteFile(fixture, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := api.NewMemoryClient("RADB", logger)
+	if err := client.LoadFixture(fixture); err != nil {
+		t.Fatalf("LoadFixture() failed: %v", err)
+	}
+
+	snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "")
+	snapshot.Routes = models.NewRouteList([]models.RouteObject{
+		{Route: "203.0.113.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"},
+	})
+	client.LoadSnapshot(snapshot)
+
+	routes, err := client.ListRoutes(ctx, map[string]string{"mnt-by": "maint-a"})
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if routes.Count != 2 || routes.Routes[0].Route != "192.0.2.0/24" || routes.Routes[1].Route != "203.0.113.0/24" {
+		t.Errorf("ListRoutes(mnt-by=MAINT-A) = %+v, want the fixture and snapshot routes in order", routes.Routes)
+	}
+
+	// New contacts must not collide with IDs loaded from the fixture
+	contact := &models.Contact{Name: "Ops", Email: "ops@example.com", Role: models.ContactRoleAdmin}
+	if err := client.CreateContact(ctx, contact); err != nil {
+		t.Fatalf("CreateContact() failed: %v", err)
+	}
+	if contact.ID == "CONTACT-1" {
+		t.Errorf("CreateContact() reused existing ID %s", contact.ID)
+	}
+
+	result, err := client.Search(ctx, "AS64501", "route")
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if search := result.(*api.SearchResult); search.Count != 1 {
+		t.Errorf("Search(AS64501) = %d results, want 1", search.Count)
+	}
+}
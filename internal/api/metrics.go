@@ -0,0 +1,111 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/pkg/metrics"
+)
+
+// Metrics instruments API requests. All methods are safe on a nil receiver,
+// so an uninstrumented client pays nothing.
+type Metrics struct {
+	requests      *metrics.Counter
+	duration      *metrics.Histogram
+	retries       *metrics.Counter
+	rateLimitWait *metrics.Histogram
+	rate          *metrics.Gauge
+}
+
+// NewMetrics registers the API client metrics in reg.
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		requests: reg.NewCounter("radb_api_requests_total",
+			"API requests sent, by method, endpoint, and status code (\"error\" when no response was received).",
+			"method", "endpoint", "status"),
+		duration: reg.NewHistogram("radb_api_request_duration_seconds",
+			"Latency of API requests, including time to receive headers.",
+			nil, "method", "endpoint"),
+		retries: reg.NewCounter("radb_api_retries_total",
+			"API requests retried after a transient failure, by method and endpoint.",
+			"method", "endpoint"),
+		rateLimitWait: reg.NewHistogram("radb_api_rate_limit_wait_seconds",
+			"Time requests waited for the client rate limiter.",
+			[]float64{0.001, 0.01, 0.1, 0.5, 1, 2, 5, 10, 30}),
+		rate: reg.NewGauge("radb_api_rate_limit_requests_per_minute",
+			"Effective client rate limit, by endpoint class, after adapting to 429 responses.",
+			"class"),
+	}
+}
+
+// SetMetrics instruments the client's requests. A nil m disables instrumentation.
+func (c *HTTPClient) SetMetrics(m *Metrics) {
+	c.metrics = m
+	c.observeRates()
+}
+
+// observeRequest records one request attempt.
+func (m *Metrics) observeRequest(method, path string, status int, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+
+	endpoint := endpointLabel(path)
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	m.requests.Inc(method, endpoint, code)
+	m.duration.Observe(elapsed.Seconds(), method, endpoint)
+}
+
+// observeRetry records a retried request.
+func (m *Metrics) observeRetry(method, path string) {
+	if m == nil {
+		return
+	}
+	m.retries.Inc(method, endpointLabel(path))
+}
+
+// observeRateLimitWait records time spent waiting for the rate limiter.
+func (m *Metrics) observeRateLimitWait(waited time.Duration) {
+	if m == nil {
+		return
+	}
+	m.rateLimitWait.Observe(waited.Seconds())
+}
+
+// observeRate records the effective rate limit of an endpoint class.
+func (m *Metrics) observeRate(class EndpointClass, requestsPerMinute int) {
+	if m == nil {
+		return
+	}
+	m.rate.Set(float64(requestsPerMinute), string(class))
+}
+
+// endpointLabel reduces a request path to a low-cardinality label by dropping
+// the source, query, and object identifiers: "/RADB/route/192.0.2.0%2F24/AS64500"
+// becomes "/route/{id}".
+func endpointLabel(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 {
+		return "/" + strings.Join(segments, "/")
+	}
+
+	// segments[0] is the source
+	label := "/" + segments[1]
+	rest := segments[2:]
+	if segments[1] == "validate" && len(rest) > 0 {
+		label += "/" + rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		label += "/{id}"
+	}
+	return label
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bss/radb-client/pkg/metrics"
+)
+
+func TestEndpointLabel(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/RADB/route", "/route"},
+		{"/RADB/route?offset=100&limit=50", "/route"},
+		{"/RADB/route/192.0.2.0%2F24/AS64500", "/route/{id}"},
+		{"/RADB/contact/CONTACT-1", "/contact/{id}"},
+		{"/radb/search?query-string=AS64500", "/search"},
+		{"/RADB/validate/asn?asn=AS64500", "/validate/asn"},
+		{"/", "/"},
+	}
+
+	for _, tt := range tests {
+		if got := endpointLabel(tt.path); got != tt.want {
+			t.Errorf("endpointLabel(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestClientMetrics(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	reg := metrics.NewRegistry()
+	m := NewMetrics(reg)
+	client := newPagedClient(t, server.URL)
+	client.SetRetryPolicy(fastRetryPolicy(3))
+	client.SetMetrics(m)
+
+	if _, err := client.ListRoutes(context.Background(), nil); err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+
+	if got := m.requests.Value("GET", "/route", "503"); got != 1 {
+		t.Errorf("503 requests = %v, want 1", got)
+	}
+	if got := m.requests.Value("GET", "/route", "200"); got != 1 {
+		t.Errorf("200 requests = %v, want 1", got)
+	}
+	if got := m.retries.Value("GET", "/route"); got != 1 {
+		t.Errorf("retries = %v, want 1", got)
+	}
+	if got := m.duration.Count("GET", "/route"); got != 2 {
+		t.Errorf("latency observations = %d, want 2", got)
+	}
+	if got := m.rateLimitWait.Count(); got != 2 {
+		t.Errorf("rate limit waits = %d, want one per attempt", got)
+	}
+
+	var out strings.Builder
+	reg.WriteText(&out)
+	if !strings.Contains(out.String(), `radb_api_requests_total{method="GET",endpoint="/route",status="200"} 1`) {
+		t.Errorf("exposition missing request counter:\n%s", out.String())
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// PagedClient is implemented by clients that can fetch listings one page at a time.
+// The cursor returned with each page is opaque; an empty cursor means there
+// are no further pages.
+type PagedClient interface {
+	ListRoutesPage(ctx context.Context, filters map[string]string, pageSize int, cursor string) (*models.RouteList, string, error)
+	ListContactsPage(ctx context.Context, pageSize int, cursor string) (*models.ContactList, string, error)
+}
+
+// Ensure HTTPClient implements PagedClient.
+var _ PagedClient = (*HTTPClient)(nil)
+
+// pageEnvelope is the paginated response form: {"results": [...], "next_token": "..."}.
+type pageEnvelope struct {
+	Results   json.RawMessage `json:"results"`
+	NextToken string          `json:"next_token"`
+}
+
+// SetPagination configures listing behavior. pageSize is the number of
+// objects requested per page (0 lets the server decide) and maxResults caps
+// the total returned by ListRoutes and ListContacts (0 means no cap).
+func (c *HTTPClient) SetPagination(pageSize, maxResults int) {
+	c.pageSize = pageSize
+	c.maxResults = maxResults
+}
+
+// ListRoutesPage retrieves a single page of routes. Pass an empty cursor for
+// the first page and the returned cursor for each following page.
+func (c *HTTPClient) ListRoutesPage(ctx context.Context, filters map[string]string, pageSize int, cursor string) (*models.RouteList, string, error) {
+	if !c.IsAuthenticated() {
+		return nil, "", fmt.Errorf("not authenticated: please login first")
+	}
+
+	path := cursor
+	if path == "" {
+		params := url.Values{}
+		for key, value := range filters {
+			params.Add(key, value)
+		}
+		if pageSize > 0 {
+			params.Set("limit", strconv.Itoa(pageSize))
+		}
+
+		path = fmt.Sprintf("/%s/route", c.source)
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+	}
+
+	var routes []models.RouteObject
+	next, err := c.fetchPage(ctx, path, "routes", &routes, func() error {
+		for i := range routes {
+			if err := routes[i].Validate(); err != nil {
+				return fmt.Errorf("route %s: %w", routes[i].ID(), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return models.NewRouteList(routes), next, nil
+}
+
+// ListContactsPage retrieves a single page of contacts. Pass an empty cursor
+// for the first page and the returned cursor for each following page.
+func (c *HTTPClient) ListContactsPage(ctx context.Context, pageSize int, cursor string) (*models.ContactList, string, error) {
+	if !c.IsAuthenticated() {
+		return nil, "", fmt.Errorf("not authenticated: please login first")
+	}
+
+	path := cursor
+	if path == "" {
+		path = fmt.Sprintf("/%s/contact", c.source)
+		if pageSize > 0 {
+			path += "?limit=" + strconv.Itoa(pageSize)
+		}
+	}
+
+	var contacts []models.Contact
+	next, err := c.fetchPage(ctx, path, "contacts", &contacts, func() error {
+		for i := range contacts {
+			if err := contacts[i].Validate(); err != nil {
+				return fmt.Errorf("contact %s: %w", contacts[i].ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return models.NewContactList(contacts), next, nil
+}
+
+// fetchPage performs a GET for one page, decodes its items into out, and
+// returns the cursor for the next page. Both bare JSON arrays and the
+// {"results", "next_token"} envelope are accepted; a Link header with
+// rel="next" takes precedence over next_token. Pages that fail to decode,
+// or fail check when capturing is enabled, are captured.
+func (c *HTTPClient) fetchPage(ctx context.Context, path, what string, out interface{}, check func() error) (string, error) {
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("list %s failed with status %d: %s", what, resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s response: %w", what, err)
+	}
+
+	items := body
+	var nextToken string
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		var envelope pageEnvelope
+		if err := json.Unmarshal(trimmed, &envelope); err != nil {
+			c.captureResponse(resp, body, err)
+			return "", fmt.Errorf("failed to decode %s response: %w", what, err)
+		}
+		items = envelope.Results
+		nextToken = envelope.NextToken
+	}
+
+	if len(items) > 0 {
+		if err := json.Unmarshal(items, out); err != nil {
+			c.captureResponse(resp, body, err)
+			return "", fmt.Errorf("failed to decode %s response: %w", what, err)
+		}
+	}
+
+	if c.captureDir != "" && check != nil {
+		if err := check(); err != nil {
+			c.logger.Debugf("Invalid %s in response: %v", what, err)
+			c.captureResponse(resp, body, err)
+		}
+	}
+
+	if link := nextLink(resp.Header); link != "" {
+		return c.cursorFromLink(resp.Request.URL, link)
+	}
+
+	if nextToken != "" {
+		return withQueryParam(path, "next_token", nextToken), nil
+	}
+
+	return "", nil
+}
+
+// cursorFromLink resolves a next-page link against the request URL and
+// converts it to a path under the base URL. Links pointing elsewhere are
+// rejected so credentials are never sent to another host.
+func (c *HTTPClient) cursorFromLink(requestURL *url.URL, link string) (string, error) {
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid pagination link %q: %w", link, err)
+	}
+
+	next := requestURL.ResolveReference(ref).String()
+	base := strings.TrimSuffix(c.baseURL, "/")
+	if !strings.HasPrefix(next, base+"/") {
+		return "", fmt.Errorf("pagination link %q is outside the API base URL", link)
+	}
+
+	return strings.TrimPrefix(next, base), nil
+}
+
+// nextLink extracts the rel="next" target from Link headers.
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, part := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+			if !ok {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.EqualFold(key, "rel") && strings.EqualFold(strings.Trim(val, `"`), "next") {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// withQueryParam returns path with the query parameter key set to value.
+func withQueryParam(path, key, value string) string {
+	base, rawQuery, _ := strings.Cut(path, "?")
+	params, _ := url.ParseQuery(rawQuery)
+	params.Set(key, value)
+	return base + "?" + params.Encode()
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// newPagedServer serves n routes, three per page, linking pages by offset
+// or by next_token depending on useToken.
+func newPagedServer(t *testing.T, n int, useToken bool) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if token := r.URL.Query().Get("next_token"); token != "" {
+			start, _ = strconv.Atoi(token)
+		}
+		end := min(start+3, n)
+
+		routes := make([]models.RouteObject, 0, end-start)
+		for i := start; i < end; i++ {
+			routes = append(routes, models.RouteObject{Route: fmt.Sprintf("192.0.2.%d/32", i), Origin: "AS64500"})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if useToken {
+			next := ""
+			if end < n {
+				next = strconv.Itoa(end)
+			}
+			writeTestJSON(w, map[string]interface{}{"results": routes, "next_token": next})
+			return
+		}
+
+		if end < n {
+			w.Header().Set("Link", fmt.Sprintf(`</RADB/route?offset=%d>; rel="next"`, end))
+		}
+		writeTestJSON(w, routes)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func writeTestJSON(w http.ResponseWriter, v interface{}) {
+	fmt.Fprint(w, mustJSON(v))
+}
+
+func mustJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func newPagedClient(t *testing.T, baseURL string) *HTTPClient {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient(baseURL, "RADB", 5, logger)
+	client.Login(context.Background(), "user", "secret")
+	return client
+}
+
+func TestListRoutesFollowsLinkHeader(t *testing.T) {
+	server := newPagedServer(t, 7, false)
+	client := newPagedClient(t, server.URL)
+
+	routes, err := client.ListRoutes(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if routes.Count != 7 {
+		t.Errorf("Expected 7 routes across pages, got %d", routes.Count)
+	}
+}
+
+func TestListRoutesFollowsNextToken(t *testing.T) {
+	server := newPagedServer(t, 5, true)
+	client := newPagedClient(t, server.URL)
+	client.SetPagination(3, 4)
+
+	routes, err := client.ListRoutes(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if routes.Count != 4 {
+		t.Errorf("Expected max results to cap listing at 4, got %d", routes.Count)
+	}
+	if !routes.Truncated {
+		t.Error("Expected a listing cut short by max results to be marked truncated")
+	}
+}
+
+func TestListRoutesMaxResults(t *testing.T) {
+	tests := []struct {
+		name          string
+		routes        int
+		maxResults    int
+		wantCount     int
+		wantTruncated bool
+	}{
+		{"cap reached mid-page", 7, 5, 5, true},
+		{"cap reached at page end", 7, 6, 6, true},
+		{"cap equals total", 6, 6, 6, false},
+		{"cap above total", 4, 10, 4, false},
+		{"no cap", 7, 0, 7, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newPagedServer(t, tt.routes, false)
+			client := newPagedClient(t, server.URL)
+			client.SetPagination(0, tt.maxResults)
+
+			routes, err := client.ListRoutes(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("ListRoutes() failed: %v", err)
+			}
+			if routes.Count != tt.wantCount || routes.Truncated != tt.wantTruncated {
+				t.Errorf("got %d routes, truncated %v; want %d, truncated %v",
+					routes.Count, routes.Truncated, tt.wantCount, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestListRoutesAppliesPrefixSet(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeTestJSON(w, []models.RouteObject{
+			{Route: "192.0.2.0/24", Origin: "AS64500"},
+			{Route: "192.0.2.128/25", Origin: "AS64500"},
+			{Route: "198.51.100.0/24", Origin: "AS64500"},
+			{Route: "203.0.113.0/24", Origin: "AS64500"},
+		})
+	}))
+	t.Cleanup(server.Close)
+	client := newPagedClient(t, server.URL)
+
+	filters := map[string]string{models.FilterPrefixes: "192.0.2.128/25, 198.51.100.0/23", "origin": "AS64500"}
+	routes, err := client.ListRoutes(context.Background(), filters)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if query.Has(models.FilterPrefixes) || query.Get("origin") != "AS64500" {
+		t.Errorf("query = %v, want the origin filter without the prefix set", query)
+	}
+	if routes.Count != 2 || routes.Routes[0].Route != "192.0.2.128/25" || routes.Routes[1].Route != "198.51.100.0/24" {
+		t.Errorf("routes = %+v, want the two within the prefix set", routes.Routes)
+	}
+}
+
+func TestRouteStreamUsesCursor(t *testing.T) {
+	server := newPagedServer(t, 5, false)
+	client := newPagedClient(t, server.URL)
+
+	stream := client.StreamRoutes(context.Background(), nil, 3)
+	defer stream.Close()
+
+	count := 0
+	for stream.Next() {
+		count++
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("Expected 5 streamed routes, got %d", count)
+	}
+}
+
+func TestCursorFromLink(t *testing.T) {
+	client := NewHTTPClient("https://api.radb.example/api", "RADB", 5, logrus.New())
+	requestURL, _ := url.Parse("https://api.radb.example/api/RADB/route?limit=3")
+
+	tests := []struct {
+		link    string
+		want    string
+		wantErr bool
+	}{
+		{"https://api.radb.example/api/RADB/route?page=2", "/RADB/route?page=2", false},
+		{"/api/RADB/route?page=2", "/RADB/route?page=2", false},
+		{"route?page=2", "/RADB/route?page=2", false},
+		{"https://evil.example/api/RADB/route?page=2", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := client.cursorFromLink(requestURL, tt.link)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("cursorFromLink(%q) error = %v, wantErr %v", tt.link, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("cursorFromLink(%q) = %q, want %q", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `</RADB/route?offset=0>; rel="prev", </RADB/route?offset=6>; rel="next"`)
+
+	if got := nextLink(header); got != "/RADB/route?offset=6" {
+		t.Errorf("nextLink() = %q, want %q", got, "/RADB/route?offset=6")
+	}
+	if got := nextLink(http.Header{}); got != "" {
+		t.Errorf("nextLink() without header = %q, want empty", got)
+	}
+}
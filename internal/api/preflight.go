@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Authority is the outcome of probing write authority for a maintainer.
+type Authority string
+
+const (
+	AuthorityGranted Authority = "granted" // Writes are expected to succeed
+	AuthorityDenied  Authority = "denied"  // Writes will be rejected
+	AuthorityUnknown Authority = "unknown" // The server gave no answer either way
+)
+
+// MaintainerAuthority reports whether the client may write objects
+// maintained by a mntner.
+type MaintainerAuthority struct {
+	Maintainer string    `json:"maintainer"`
+	Method     string    `json:"method"`
+	Authority  Authority `json:"authority"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+// AuthorityProber is implemented by clients that can cheaply check write
+// authority before a bulk operation, so items that would be rejected for a
+// missing mntner password or key are reported up front.
+type AuthorityProber interface {
+	ProbeMaintainer(ctx context.Context, mntner, method string) (MaintainerAuthority, error)
+}
+
+// Ensure HTTPClient implements AuthorityProber.
+var _ AuthorityProber = (*HTTPClient)(nil)
+
+// ProbeMaintainer sends an OPTIONS request for the mntner object. An Allow
+// header only lists the methods the mntner resource supports, not whether
+// the authenticated user holds the mntner's password or key, so a successful
+// probe yields AuthorityUnknown: it shows only that the maintainer exists and
+// the credentials were accepted. Authority is reported as denied when the
+// credentials are rejected (401 or 403) or the maintainer does not exist.
+// Servers that do not answer OPTIONS also yield AuthorityUnknown.
+func (c *HTTPClient) ProbeMaintainer(ctx context.Context, mntner, method string) (MaintainerAuthority, error) {
+	result := MaintainerAuthority{Maintainer: mntner, Method: method, Authority: AuthorityUnknown}
+
+	if !c.IsAuthenticated() {
+		return result, fmt.Errorf("not authenticated: please login first")
+	}
+
+	path := fmt.Sprintf("/%s/mntner/%s", c.source, url.PathEscape(mntner))
+	resp, err := c.doRequest(ctx, http.MethodOptions, path, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to probe maintainer %s: %w", mntner, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		result.Reason = "maintainer exists; the server does not report write authority"
+	case http.StatusUnauthorized, http.StatusForbidden:
+		result.Authority = AuthorityDenied
+		result.Reason = fmt.Sprintf("status %d", resp.StatusCode)
+	case http.StatusNotFound:
+		result.Authority = AuthorityDenied
+		result.Reason = "maintainer does not exist"
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		result.Reason = "server does not support authority probes"
+	default:
+		return result, fmt.Errorf("probe maintainer %s failed with status %d", mntner, resp.StatusCode)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProbeMaintainer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			// Login probe
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`[]`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/RADB/mntner/MAINT-OWN":
+			w.Header().Set("Allow", "GET, POST, PUT, DELETE")
+		case "/RADB/mntner/MAINT-READONLY":
+			w.Header().Set("Allow", "GET, HEAD")
+		case "/RADB/mntner/MAINT-PLAIN": // No Allow header
+		case "/RADB/mntner/MAINT-OTHER":
+			w.WriteHeader(http.StatusForbidden)
+			return
+		case "/RADB/mntner/MAINT-GONE":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+	client.disableRateLimits()
+
+	tests := []struct {
+		mntner string
+		method string
+		want   Authority
+	}{
+		// Allow lists what the resource supports, not what the user may write
+		{"MAINT-OWN", http.MethodPut, AuthorityUnknown},
+		{"MAINT-OWN", http.MethodDelete, AuthorityUnknown},
+		{"MAINT-READONLY", http.MethodPost, AuthorityUnknown},
+		{"MAINT-PLAIN", http.MethodPut, AuthorityUnknown},
+		{"MAINT-OTHER", http.MethodPut, AuthorityDenied},
+		{"MAINT-GONE", http.MethodPut, AuthorityDenied},
+		{"MAINT-LEGACY", http.MethodPut, AuthorityUnknown},
+	}
+
+	for _, tt := range tests {
+		result, err := client.ProbeMaintainer(context.Background(), tt.mntner, tt.method)
+		if err != nil {
+			t.Fatalf("ProbeMaintainer(%s, %s) failed: %v", tt.mntner, tt.method, err)
+		}
+		if result.Authority != tt.want {
+			t.Errorf("ProbeMaintainer(%s, %s) = %s (%s), want %s", tt.mntner, tt.method, result.Authority, result.Reason, tt.want)
+		}
+	}
+
+	// Probes are reads, so dry-run mode does not withhold them
+	client.SetDryRun(func(PlannedWrite) { t.Error("dry run withheld an OPTIONS probe") })
+	if result, _ := client.ProbeMaintainer(context.Background(), "MAINT-OTHER", http.MethodPut); result.Authority != AuthorityDenied {
+		t.Errorf("ProbeMaintainer() in dry-run mode = %s, want denied", result.Authority)
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"time"
+)
+
+// BulkProgress reports the state of a running bulk operation.
+type BulkProgress struct {
+	Operation string        // e.g. "create routes"
+	Total     int           // Items in the operation
+	Completed int           // Items finished, successfully or not
+	Failed    int           // Items that failed
+	Elapsed   time.Duration // Time since the operation started
+	ETA       time.Duration // Estimated time remaining
+	Index     int           // Index of the item that just finished
+	ItemID    string        // ID of the item that just finished
+	ItemErr   error         // Error of the item that just finished, nil on success
+}
+
+// ProgressFunc receives progress updates from bulk operations. It is called
+// from the goroutine running the operation once per finished item.
+type ProgressFunc func(BulkProgress)
+
+// ProgressReporter is implemented by clients that report bulk operation progress.
+type ProgressReporter interface {
+	SetProgressFunc(fn ProgressFunc)
+}
+
+// Ensure HTTPClient implements ProgressReporter.
+var _ ProgressReporter = (*HTTPClient)(nil)
+
+// SetProgressFunc registers a callback for bulk operation progress.
+// A nil callback disables reporting.
+func (c *HTTPClient) SetProgressFunc(fn ProgressFunc) {
+	c.progress = fn
+}
+
+// collectResults gathers worker results into result, reporting progress
+// after each item.
+func (c *HTTPClient) collectResults(operation string, result *BulkResult, results <-chan workResult) {
+	start := time.Now()
+	requestsPerMinute := c.limiterFor(EndpointWrite).GetCurrentRate()
+
+	for res := range results {
+		if res.Error != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, BulkError{
+				Index: res.Index,
+				ID:    res.ID,
+				Error: res.Error.Error(),
+			})
+		} else {
+			result.Succeeded++
+		}
+
+		if c.progress != nil {
+			completed := result.Succeeded + result.Failed
+			elapsed := time.Since(start)
+			c.progress(BulkProgress{
+				Operation: operation,
+				Total:     result.Total,
+				Completed: completed,
+				Failed:    result.Failed,
+				Elapsed:   elapsed,
+				ETA:       estimateRemaining(result.Total-completed, completed, elapsed, requestsPerMinute),
+				Index:     res.Index,
+				ItemID:    res.ID,
+				ItemErr:   res.Error,
+			})
+		}
+	}
+}
+
+// estimateRemaining estimates the time to finish the remaining items from
+// the observed throughput, but never less than the rate limit allows.
+func estimateRemaining(remaining, completed int, elapsed time.Duration, requestsPerMinute int) time.Duration {
+	if remaining <= 0 {
+		return 0
+	}
+
+	eta := time.Duration(remaining) * time.Minute / time.Duration(max(requestsPerMinute, 1))
+	if completed > 0 {
+		observed := elapsed / time.Duration(completed) * time.Duration(remaining)
+		eta = max(eta, observed)
+	}
+	return eta
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBulkProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/RADB/contact/MISSING" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+
+	var updates []BulkProgress
+	client.SetProgressFunc(func(p BulkProgress) {
+		updates = append(updates, p)
+	})
+
+	result, err := client.BatchDeleteContacts(context.Background(), []string{"CONTACT-1", "MISSING"}, 2)
+	if err != nil {
+		t.Fatalf("BatchDeleteContacts failed: %v", err)
+	}
+	if result.Failed != 1 {
+		t.Fatalf("result = %+v, want one failure", result)
+	}
+
+	if len(updates) != 2 {
+		t.Fatalf("got %d progress updates, want 2", len(updates))
+	}
+	last := updates[1]
+	if last.Operation != "delete contacts" || last.Total != 2 || last.Completed != 2 || last.Failed != 1 || last.ETA != 0 {
+		t.Errorf("final progress = %+v", last)
+	}
+	if updates[0].Completed != 1 || updates[0].ETA <= 0 {
+		t.Errorf("first progress = %+v, want one done and a positive ETA", updates[0])
+	}
+	for _, p := range updates {
+		wantIndex, wantFailed := 0, false
+		if p.ItemID == "MISSING" {
+			wantIndex, wantFailed = 1, true
+		}
+		if p.Index != wantIndex || (p.ItemErr != nil) != wantFailed {
+			t.Errorf("progress for %s: index %d, error %v", p.ItemID, p.Index, p.ItemErr)
+		}
+	}
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining int
+		completed int
+		elapsed   time.Duration
+		want      time.Duration
+	}{
+		{"done", 0, 10, 10 * time.Second, 0},
+		{"nothing finished yet", 30, 0, 0, 30 * time.Second},
+		{"faster than rate limit", 10, 10, time.Second, 10 * time.Second},
+		{"slower than rate limit", 10, 10, 50 * time.Second, 50 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateRemaining(tt.remaining, tt.completed, tt.elapsed, 60); got != tt.want {
+				t.Errorf("estimateRemaining() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/bss/radb-client/pkg/ratelimit"
+)
+
+// EndpointClass groups API endpoints that share a rate limit.
+type EndpointClass string
+
+const (
+	EndpointRead   EndpointClass = "read"   // Single-object lookups and validation
+	EndpointList   EndpointClass = "list"   // Collection listings
+	EndpointSearch EndpointClass = "search" // Search queries
+	EndpointWrite  EndpointClass = "write"  // Creates, updates, and deletes
+)
+
+// EndpointClasses lists every endpoint class.
+var EndpointClasses = []EndpointClass{EndpointRead, EndpointList, EndpointSearch, EndpointWrite}
+
+// replayRequestsPerMinute is the rate used when replaying a cassette, where
+// there is no server to protect.
+const replayRequestsPerMinute = 60000
+
+// SetRateLimit sets the request rate shared by every endpoint class that has
+// no limit of its own. A burst of zero or less allows 10% of the rate.
+// The effective rate adapts to the API: it drops when the API answers 429
+// and recovers, up to the configured rate, as requests succeed.
+func (c *HTTPClient) SetRateLimit(requestsPerMinute, burst int) {
+	c.rateLimiter = c.newLimiter(requestsPerMinute, burst)
+	c.observeRates()
+}
+
+// SetEndpointRateLimit gives an endpoint class its own request rate, used
+// instead of the shared one, so for example writes can be throttled harder
+// than reads.
+func (c *HTTPClient) SetEndpointRateLimit(class EndpointClass, requestsPerMinute, burst int) {
+	if c.endpointLimiters == nil {
+		c.endpointLimiters = make(map[EndpointClass]*ratelimit.AdaptiveLimiter)
+	}
+	c.endpointLimiters[class] = c.newLimiter(requestsPerMinute, burst)
+	c.observeRates()
+}
+
+// SetJobWeights sets how requests waiting for a rate limit are shared
+// between jobs, such as the daemon's scheduled check and serve-mode webhooks.
+// Waiting requests are admitted by weighted fair queuing, so a large job
+// cannot starve a small one; a job of weight 2 gets twice the requests of a
+// job of weight 1 while both are waiting. Jobs without a weight have weight 1.
+// Requests name their job with ratelimit.WithJob.
+func (c *HTTPClient) SetJobWeights(weights map[string]float64) {
+	c.jobWeights = weights
+	c.rateLimiter.SetJobWeights(weights)
+	for _, limiter := range c.endpointLimiters {
+		limiter.SetJobWeights(weights)
+	}
+}
+
+// newLimiter creates a limiter that shares its rate between jobs by the
+// client's job weights.
+func (c *HTTPClient) newLimiter(requestsPerMinute, burst int) *ratelimit.AdaptiveLimiter {
+	limiter := ratelimit.NewAdaptiveWithBurst(requestsPerMinute, burst)
+	limiter.SetJobWeights(c.jobWeights)
+	return limiter
+}
+
+// waitRateLimit blocks until the rate limit for the request's endpoint class
+// permits it.
+func (c *HTTPClient) waitRateLimit(ctx context.Context, method, path string) error {
+	return c.limiterFor(endpointClass(method, path)).Wait(ctx)
+}
+
+// adaptRateLimit adjusts the rate of the request's endpoint class to the
+// response: a 429 halves it, and a run of other responses raises it again.
+// Server errors and failed requests say nothing about the rate and are ignored.
+func (c *HTTPClient) adaptRateLimit(method, path string, resp *http.Response) {
+	if resp == nil || resp.StatusCode >= 500 {
+		return
+	}
+
+	class := endpointClass(method, path)
+	limiter := c.limiterFor(class)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		limiter.RecordRateLimit()
+		c.logger.Warnf("Rate limited by the API; slowing %s requests to %d/min", class, limiter.GetCurrentRate())
+	} else {
+		limiter.RecordSuccess()
+	}
+	c.metrics.observeRate(class, limiter.GetCurrentRate())
+}
+
+// EffectiveRates returns the current request rate of every endpoint class.
+func (c *HTTPClient) EffectiveRates() map[EndpointClass]int {
+	rates := make(map[EndpointClass]int, len(EndpointClasses))
+	for _, class := range EndpointClasses {
+		rates[class] = c.limiterFor(class).GetCurrentRate()
+	}
+	return rates
+}
+
+// observeRates records the current rate of every endpoint class in metrics.
+func (c *HTTPClient) observeRates() {
+	for class, rate := range c.EffectiveRates() {
+		c.metrics.observeRate(class, rate)
+	}
+}
+
+// limiterFor returns the limiter for an endpoint class.
+func (c *HTTPClient) limiterFor(class EndpointClass) *ratelimit.AdaptiveLimiter {
+	if limiter, ok := c.endpointLimiters[class]; ok {
+		return limiter
+	}
+	return c.rateLimiter
+}
+
+// disableRateLimits lifts every rate limit to replayRequestsPerMinute.
+func (c *HTTPClient) disableRateLimits() {
+	c.rateLimiter = c.newLimiter(replayRequestsPerMinute, 1)
+	for class := range c.endpointLimiters {
+		c.endpointLimiters[class] = c.newLimiter(replayRequestsPerMinute, 1)
+	}
+	c.observeRates()
+}
+
+// endpointClass classifies a request for rate limiting.
+func endpointClass(method, path string) EndpointClass {
+	switch method {
+	case http.MethodGet:
+	case http.MethodHead, http.MethodOptions:
+		return EndpointRead
+	default:
+		return EndpointWrite
+	}
+
+	label := endpointLabel(path)
+	switch {
+	case label == "/search":
+		return EndpointSearch
+	case strings.HasPrefix(label, "/validate/") || strings.HasSuffix(label, "/{id}"):
+		return EndpointRead
+	default:
+		return EndpointList
+	}
+}
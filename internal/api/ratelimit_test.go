@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+func TestEndpointClass(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   EndpointClass
+	}{
+		{"GET", "/RADB/route", EndpointList},
+		{"GET", "/RADB/route?offset=100&limit=50", EndpointList},
+		{"GET", "/RADB/route/192.0.2.0%2F24/AS64500", EndpointRead},
+		{"GET", "/RADB/contact/CONTACT-1", EndpointRead},
+		{"GET", "/RADB/validate/asn?asn=AS64500", EndpointRead},
+		{"GET", "/radb/search?query-string=AS64500", EndpointSearch},
+		{"POST", "/RADB/route", EndpointWrite},
+		{"PUT", "/RADB/route/192.0.2.0%2F24/AS64500", EndpointWrite},
+		{"DELETE", "/RADB/contact/CONTACT-1", EndpointWrite},
+	}
+
+	for _, tt := range tests {
+		if got := endpointClass(tt.method, tt.path); got != tt.want {
+			t.Errorf("endpointClass(%s %s) = %s, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointRateLimits(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient("http://127.0.0.1:1", "RADB", 5, logger)
+	client.SetRateLimit(6000, 10)
+	client.SetEndpointRateLimit(EndpointWrite, 1, 1)
+
+	// Reads use the shared limit and are not held back by writes
+	if err := client.waitRateLimit(context.Background(), "POST", "/RADB/route"); err != nil {
+		t.Fatalf("first write waited: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		err := client.waitRateLimit(ctx, "GET", "/RADB/route")
+		cancel()
+		if err != nil {
+			t.Fatalf("read %d waited: %v", i+1, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.waitRateLimit(ctx, "PUT", "/RADB/route/192.0.2.0%2F24/AS64500"); err == nil {
+		t.Error("second write was not held to its own limit")
+	}
+
+	if got := client.Status().RequestsPerMinute; got != 6000 {
+		t.Errorf("Status().RequestsPerMinute = %d, want the shared 6000", got)
+	}
+}
+
+func TestAdaptiveRateLimit(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	var limited atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if limited.Load() {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"route": "192.0.2.0/24", "origin": "AS64500"}`))
+	}))
+	defer server.Close()
+
+	reg := metrics.NewRegistry()
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.SetMetrics(NewMetrics(reg))
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	client.Login(context.Background(), "user", "secret")
+	client.SetRateLimit(6000, 100)
+
+	limited.Store(true)
+	client.GetRoute(context.Background(), "192.0.2.0/24", "AS64500")
+	status := client.Status()
+	if status.RequestsPerMinute != 3000 || status.ConfiguredRequestsPerMinute != 6000 {
+		t.Fatalf("after a 429 rate = %d of %d, want 3000 of 6000", status.RequestsPerMinute, status.ConfiguredRequestsPerMinute)
+	}
+
+	var out strings.Builder
+	reg.WriteText(&out)
+	if !strings.Contains(out.String(), `radb_api_rate_limit_requests_per_minute{class="read"} 3000`) {
+		t.Errorf("metrics do not report the reduced read rate:\n%s", out.String())
+	}
+
+	limited.Store(false)
+	for i := 0; i < 10; i++ {
+		if _, err := client.GetRoute(context.Background(), "192.0.2.0/24", "AS64500"); err != nil {
+			t.Fatalf("GetRoute() failed: %v", err)
+		}
+	}
+	if got := client.Status().RequestsPerMinute; got <= 3000 {
+		t.Errorf("rate after successes = %d, want it to recover above 3000", got)
+	}
+
+	for i := 0; i < 200; i++ {
+		if _, err := client.GetRoute(context.Background(), "192.0.2.0/24", "AS64500"); err != nil {
+			t.Fatalf("GetRoute() failed: %v", err)
+		}
+	}
+	if got := client.Status().RequestsPerMinute; got != 6000 {
+		t.Errorf("rate after many successes = %d, want it capped at the configured 6000", got)
+	}
+}
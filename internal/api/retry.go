@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+
+	// InitialDelay is the base delay before the first retry
+	InitialDelay time.Duration
+
+	// Multiplier scales the delay after each attempt
+	Multiplier float64
+
+	// MaxDelay caps a single backoff delay
+	MaxDelay time.Duration
+
+	// MaxElapsed bounds the total time spent retrying (0 means no limit)
+	MaxElapsed time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: time.Second,
+		Multiplier:   2,
+		MaxDelay:     30 * time.Second,
+		MaxElapsed:   2 * time.Minute,
+	}
+}
+
+// Backoff returns the delay before retry number attempt (starting at 1).
+// Delays grow exponentially and are jittered over [d/2, d] so that clients
+// retrying together do not stay in lockstep.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	half := delay / 2
+	return time.Duration(half + rand.Float64()*half)
+}
+
+// shouldRetry classifies the outcome of an attempt. Non-idempotent methods
+// are only retried when the server cannot have processed the request.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		return isRetryableError(method, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusRequestTimeout, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	default:
+		return false
+	}
+}
+
+// isRetryableError reports whether a transport error is transient.
+func isRetryableError(method string, err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Certificate problems will not fix themselves
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthority) || errors.As(err, &hostnameErr) {
+		return false
+	}
+
+	// A failed dial means the request never reached the server
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return isIdempotent(method)
+}
+
+// isIdempotent reports whether a request may be safely repeated.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		return max(time.Until(when), 0), true
+	}
+
+	return 0, false
+}
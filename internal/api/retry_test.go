@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+func fastRetryPolicy(attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  attempts,
+		InitialDelay: time.Millisecond,
+		Multiplier:   2,
+		MaxDelay:     10 * time.Millisecond,
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialDelay: 100 * time.Millisecond,
+		Multiplier:   2,
+		MaxDelay:     time.Second,
+	}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{10, time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay := policy.Backoff(tt.attempt)
+			if delay < tt.max/2 || delay > tt.max {
+				t.Errorf("Backoff(%d) = %s, want within [%s, %s]", tt.attempt, delay, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+		err    error
+		want   bool
+	}{
+		{"GET 503", http.MethodGet, http.StatusServiceUnavailable, nil, true},
+		{"GET 502", http.MethodGet, http.StatusBadGateway, nil, true},
+		{"GET 404", http.MethodGet, http.StatusNotFound, nil, false},
+		{"GET 501", http.MethodGet, http.StatusNotImplemented, nil, false},
+		{"POST 429", http.MethodPost, http.StatusTooManyRequests, nil, true},
+		{"POST 500", http.MethodPost, http.StatusInternalServerError, nil, false},
+		{"canceled", http.MethodGet, 0, context.Canceled, false},
+		{"GET transport error", http.MethodGet, 0, errors.New("connection reset"), true},
+		{"POST transport error", http.MethodPost, 0, errors.New("connection reset"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			if got := shouldRetry(tt.method, resp, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"route":"192.0.2.0/24"}` {
+			t.Errorf("Attempt %d got body %q", attempts.Load()+1, body)
+		}
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.SetRetryPolicy(fastRetryPolicy(3))
+
+	resp, err := client.doRequest(context.Background(), http.MethodPost, "/RADB/route", map[string]string{"route": "192.0.2.0/24"})
+	if err != nil {
+		t.Fatalf("doRequest() failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected 201, got %d", resp.StatusCode)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestRetryStopsOnTerminalStatus(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.SetRetryPolicy(fastRetryPolicy(5))
+
+	resp, err := client.doRequest(context.Background(), http.MethodPost, "/RADB/route", nil)
+	if err != nil {
+		t.Fatalf("doRequest() failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts.Load() != 1 {
+		t.Errorf("Expected POST 500 not to be retried, got %d attempts", attempts.Load())
+	}
+}
+
+func TestRetryRespectsMaxElapsed(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	policy := fastRetryPolicy(5)
+	policy.MaxElapsed = time.Second
+
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.SetRetryPolicy(policy)
+
+	resp, err := client.doRequest(context.Background(), http.MethodGet, "/RADB/route", nil)
+	if err != nil {
+		t.Fatalf("doRequest() failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts.Load() != 1 {
+		t.Errorf("Expected Retry-After beyond budget to stop retries, got %d attempts", attempts.Load())
+	}
+}
+
+func TestRetryWaitsForRateLimit(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	reg := metrics.NewRegistry()
+	m := NewMetrics(reg)
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.SetRateLimit(6000, 10)
+	client.SetRetryPolicy(fastRetryPolicy(5))
+	client.SetMetrics(m)
+
+	resp, err := client.doRequest(context.Background(), http.MethodPost, "/RADB/route", nil)
+	if err != nil {
+		t.Fatalf("doRequest() failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts.Load() != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", attempts.Load())
+	}
+	if got := m.rateLimitWait.Count(); got != 3 {
+		t.Errorf("rate limit waits = %d, want one per attempt", got)
+	}
+
+	// A retry after a 429 is held by the limiter like any other request
+	attempts.Store(0)
+	client.SetEndpointRateLimit(EndpointWrite, 1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := client.doRequest(ctx, http.MethodPost, "/RADB/route", nil); err == nil {
+		t.Error("doRequest() succeeded, want the retry held past the deadline")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("Expected the retry to wait for the write limit, got %d attempts", attempts.Load())
+	}
+}
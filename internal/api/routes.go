@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,56 +12,69 @@ import (
 	"github.com/bss/radb-client/pkg/validator"
 )
 
-// ListRoutes retrieves all routes matching the given filters with pagination support.
-// Filters can include: prefix, origin (ASN), mnt-by, etc.
+// ListRoutes retrieves all routes matching the given filters, following
+// server pagination until the last page or the configured result cap. A
+// list cut short by the cap is marked Truncated. Filters can include: prefix, prefixes (a prefix set, applied to the
+// results), origin (ASN), mnt-by, etc.
 func (c *HTTPClient) ListRoutes(ctx context.Context, filters map[string]string) (*models.RouteList, error) {
 	c.logger.Debug("ListRoutes called")
 
-	if !c.authenticated {
-		return nil, fmt.Errorf("not authenticated: please login first")
-	}
-
-	// Build query parameters
-	params := url.Values{}
-	for key, value := range filters {
-		params.Add(key, value)
-	}
-
-	path := fmt.Sprintf("/%s/route", c.source)
-	if len(params) > 0 {
-		path += "?" + params.Encode()
-	}
-
-	resp, err := c.doRequest(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list routes: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("list routes failed with status %d: %s", resp.StatusCode, string(body))
+	// The API has no prefix set filter, so it is applied to the results
+	prefixSet := filters[models.FilterPrefixes]
+	if prefixSet != "" {
+		apiFilters := make(map[string]string, len(filters))
+		for key, value := range filters {
+			if key != models.FilterPrefixes {
+				apiFilters[key] = value
+			}
+		}
+		filters = apiFilters
 	}
 
 	var routes []models.RouteObject
-	if err := json.NewDecoder(resp.Body).Decode(&routes); err != nil {
-		return nil, fmt.Errorf("failed to decode routes response: %w", err)
+	truncated := false
+	cursor := ""
+	for {
+		page, next, err := c.ListRoutesPage(ctx, filters, c.pageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range page.Routes {
+			if prefixSet == "" || route.WithinPrefixes(prefixSet) {
+				routes = append(routes, route)
+			}
+		}
+
+		if c.maxResults > 0 && len(routes) >= c.maxResults {
+			truncated = len(routes) > c.maxResults || next != ""
+			routes = routes[:c.maxResults]
+			break
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	list := models.NewRouteList(routes)
+	list.Truncated = truncated
+	if truncated {
+		c.logger.Warnf("Route listing truncated at %d results (api.max_results)", c.maxResults)
 	}
-
 	c.logger.Infof("Retrieved %d routes", len(routes))
-	return models.NewRouteList(routes), nil
+	return list, nil
 }
 
 // GetRoute retrieves a specific route object by prefix and origin ASN.
 func (c *HTTPClient) GetRoute(ctx context.Context, prefix, asn string) (*models.RouteObject, error) {
 	c.logger.Debugf("GetRoute called for %s AS%s", prefix, asn)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return nil, fmt.Errorf("not authenticated: please login first")
 	}
 
-	// Validate inputs
-	if err := validator.ValidatePrefix(prefix); err != nil {
+	// Validate inputs; lookups accept any format the API can match
+	if err := validator.ValidateLookupPrefix(prefix); err != nil {
 		return nil, fmt.Errorf("invalid prefix: %w", err)
 	}
 	if err := validator.ValidateASN(asn); err != nil {
@@ -84,7 +96,7 @@ func (c *HTTPClient) GetRoute(ctx context.Context, prefix, asn string) (*models.
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("route not found: %s %s", prefix, asn)
+		return nil, fmt.Errorf("route %w: %s %s", ErrNotFound, prefix, asn)
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -93,8 +105,8 @@ func (c *HTTPClient) GetRoute(ctx context.Context, prefix, asn string) (*models.
 	}
 
 	var route models.RouteObject
-	if err := json.NewDecoder(resp.Body).Decode(&route); err != nil {
-		return nil, fmt.Errorf("failed to decode route response: %w", err)
+	if err := c.decodeResponse(resp, "route", &route, route.Validate); err != nil {
+		return nil, err
 	}
 
 	c.logger.Infof("Retrieved route %s", route.ID())
@@ -105,10 +117,14 @@ func (c *HTTPClient) GetRoute(ctx context.Context, prefix, asn string) (*models.
 func (c *HTTPClient) CreateRoute(ctx context.Context, route *models.RouteObject) error {
 	c.logger.Debugf("CreateRoute called for %s", route.ID())
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return fmt.Errorf("not authenticated: please login first")
 	}
 
+	if err := c.sandbox.checkRoute(route); err != nil {
+		return err
+	}
+
 	// Validate the route object
 	if err := route.Validate(); err != nil {
 		return fmt.Errorf("route validation failed: %w", err)
@@ -147,7 +163,7 @@ func (c *HTTPClient) CreateRoute(ctx context.Context, route *models.RouteObject)
 func (c *HTTPClient) UpdateRoute(ctx context.Context, route *models.RouteObject) error {
 	c.logger.Debugf("UpdateRoute called for %s", route.ID())
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return fmt.Errorf("not authenticated: please login first")
 	}
 
@@ -163,6 +179,12 @@ func (c *HTTPClient) UpdateRoute(ctx context.Context, route *models.RouteObject)
 	if err := validator.ValidateASN(route.Origin); err != nil {
 		return fmt.Errorf("invalid origin ASN %s: %w", route.Origin, err)
 	}
+	if err := c.sandbox.checkRoute(route); err != nil {
+		return err
+	}
+	if err := c.sandbox.checkExistingRoute(ctx, c, route.Route, route.Origin); err != nil {
+		return err
+	}
 
 	// Ensure ASN has AS prefix
 	asn := route.Origin
@@ -178,7 +200,7 @@ func (c *HTTPClient) UpdateRoute(ctx context.Context, route *models.RouteObject)
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("route not found: %s", route.ID())
+		return fmt.Errorf("route %w: %s", ErrNotFound, route.ID())
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
@@ -194,7 +216,7 @@ func (c *HTTPClient) UpdateRoute(ctx context.Context, route *models.RouteObject)
 func (c *HTTPClient) DeleteRoute(ctx context.Context, prefix, asn string) error {
 	c.logger.Debugf("DeleteRoute called for %s AS%s", prefix, asn)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return fmt.Errorf("not authenticated: please login first")
 	}
 
@@ -205,6 +227,9 @@ func (c *HTTPClient) DeleteRoute(ctx context.Context, prefix, asn string) error
 	if err := validator.ValidateASN(asn); err != nil {
 		return fmt.Errorf("invalid ASN: %w", err)
 	}
+	if err := c.sandbox.checkExistingRoute(ctx, c, prefix, asn); err != nil {
+		return err
+	}
 
 	// Ensure ASN has AS prefix
 	if !strings.HasPrefix(asn, "AS") {
@@ -219,7 +244,7 @@ func (c *HTTPClient) DeleteRoute(ctx context.Context, prefix, asn string) error
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("route not found: %s %s", prefix, asn)
+		return fmt.Errorf("route %w: %s %s", ErrNotFound, prefix, asn)
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
@@ -1,6 +1,10 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/bss/radb-client/internal/models"
@@ -82,3 +86,33 @@ func TestRouteID(t *testing.T) {
 		t.Errorf("Expected ID %s, got %s", expected, id)
 	}
 }
+
+func TestRouteLookupValidation(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+	ctx := context.Background()
+
+	// Lookups accept addresses and host bits; the server decides what matches
+	for _, prefix := range []string{"192.0.2.1", "192.0.2.1/24", "2001:db8::1"} {
+		if _, err := client.GetRoute(ctx, prefix, "AS64500"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetRoute(%q) error = %v, want ErrNotFound from the server", prefix, err)
+		}
+	}
+	if len(paths) != 3 {
+		t.Errorf("sent %d lookups, want 3", len(paths))
+	}
+
+	// Writes still require a CIDR network address
+	if err := client.DeleteRoute(ctx, "192.0.2.1", "AS64500"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteRoute(bare address) error = %v, want a validation error", err)
+	}
+	if len(paths) != 3 {
+		t.Error("DeleteRoute sent a request for an invalid prefix")
+	}
+}
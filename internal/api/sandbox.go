@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// ErrSandbox is returned for writes that sandbox mode refuses.
+var ErrSandbox = errors.New("refused in sandbox mode")
+
+// DefaultSandboxPrefixes are the documentation prefixes (RFC 5737 and
+// RFC 3849), which are never routed on the Internet.
+var DefaultSandboxPrefixes = []string{"192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24", "2001:db8::/32"}
+
+// DefaultSandboxASNs are the documentation (RFC 5398) and private
+// (RFC 6996) AS number ranges.
+var DefaultSandboxASNs = []string{"64496-64511", "64512-65534", "65536-65551", "4200000000-4294967294"}
+
+// ASNRange is an inclusive range of AS numbers.
+type ASNRange struct {
+	First uint32
+	Last  uint32
+}
+
+// Contains reports whether asn is in the range.
+func (r ASNRange) Contains(asn uint32) bool {
+	return asn >= r.First && asn <= r.Last
+}
+
+// ParseASNRange parses a single AS number, such as "AS64512", or a range
+// such as "64512-65534".
+func ParseASNRange(s string) (ASNRange, error) {
+	first, last, isRange := strings.Cut(s, "-")
+	if !isRange {
+		last = first
+	}
+	lo, err := parseASNumber(first)
+	if err != nil {
+		return ASNRange{}, fmt.Errorf("invalid AS number range %q: %w", s, err)
+	}
+	hi, err := parseASNumber(last)
+	if err != nil {
+		return ASNRange{}, fmt.Errorf("invalid AS number range %q: %w", s, err)
+	}
+	if hi < lo {
+		return ASNRange{}, fmt.Errorf("invalid AS number range %q: it ends before it starts", s)
+	}
+	return ASNRange{First: lo, Last: hi}, nil
+}
+
+// parseASNumber parses an AS number with or without the AS prefix.
+func parseASNumber(s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.ToUpper(s), "AS")
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("not an AS number: %s", s)
+	}
+	return uint32(n), nil
+}
+
+// Sandbox restricts writes to a practice maintainer and to prefixes and
+// origins that cannot affect production routing, so new operators can try
+// the full workflow safely.
+type Sandbox struct {
+	Maintainer string
+	Prefixes   []netip.Prefix
+	ASNs       []ASNRange
+}
+
+// NewSandbox creates a sandbox for the maintainer. Empty prefixes and asns
+// select DefaultSandboxPrefixes and DefaultSandboxASNs.
+func NewSandbox(maintainer string, prefixes, asns []string) (*Sandbox, error) {
+	if maintainer == "" {
+		return nil, errors.New("sandbox maintainer is required")
+	}
+	if len(prefixes) == 0 {
+		prefixes = DefaultSandboxPrefixes
+	}
+	if len(asns) == 0 {
+		asns = DefaultSandboxASNs
+	}
+
+	sandbox := &Sandbox{Maintainer: strings.ToUpper(maintainer)}
+	for _, p := range prefixes {
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sandbox prefix %q: %w", p, err)
+		}
+		sandbox.Prefixes = append(sandbox.Prefixes, prefix.Masked())
+	}
+	for _, a := range asns {
+		r, err := ParseASNRange(a)
+		if err != nil {
+			return nil, err
+		}
+		sandbox.ASNs = append(sandbox.ASNs, r)
+	}
+	return sandbox, nil
+}
+
+// SandboxClient is implemented by clients that can restrict writes to a sandbox.
+type SandboxClient interface {
+	// SetSandbox refuses writes outside the sandbox. A nil sandbox lifts
+	// the restriction.
+	SetSandbox(sandbox *Sandbox)
+}
+
+// Ensure both clients implement SandboxClient.
+var (
+	_ SandboxClient = (*HTTPClient)(nil)
+	_ SandboxClient = (*MemoryClient)(nil)
+)
+
+// SetSandbox enables or disables sandbox mode.
+func (c *HTTPClient) SetSandbox(sandbox *Sandbox) {
+	c.sandbox = sandbox
+}
+
+// SetSandbox enables or disables sandbox mode.
+func (c *MemoryClient) SetSandbox(sandbox *Sandbox) {
+	c.sandbox = sandbox
+}
+
+// checkRoute refuses a route outside the sandbox. A route without a
+// maintainer is given the sandbox maintainer. A nil sandbox allows every
+// route.
+func (s *Sandbox) checkRoute(route *models.RouteObject) error {
+	if s == nil {
+		return nil
+	}
+	if len(route.MntBy) == 0 {
+		route.MntBy = []string{s.Maintainer}
+	}
+	if err := s.checkMaintainers(route.ID(), route.MntBy); err != nil {
+		return err
+	}
+	return s.checkRange(route.Route, route.Origin)
+}
+
+// checkRange refuses a prefix or origin outside the sandbox ranges.
+func (s *Sandbox) checkRange(prefix, origin string) error {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return fmt.Errorf("invalid prefix %s: %w", prefix, err)
+	}
+	if !slices.ContainsFunc(s.Prefixes, func(allowed netip.Prefix) bool {
+		return allowed.Bits() <= p.Bits() && allowed.Contains(p.Addr())
+	}) {
+		return fmt.Errorf("%w: %s is not within a sandbox prefix", ErrSandbox, prefix)
+	}
+
+	asn, err := parseASNumber(origin)
+	if err != nil {
+		return fmt.Errorf("invalid origin %s: %w", origin, err)
+	}
+	if !slices.ContainsFunc(s.ASNs, func(r ASNRange) bool { return r.Contains(asn) }) {
+		return fmt.Errorf("%w: AS%d is not a sandbox AS number", ErrSandbox, asn)
+	}
+	return nil
+}
+
+// checkMaintainers refuses an object maintained by anyone other than the
+// sandbox maintainer.
+func (s *Sandbox) checkMaintainers(id string, maintainers []string) error {
+	for _, mnt := range maintainers {
+		if !strings.EqualFold(mnt, s.Maintainer) {
+			return fmt.Errorf("%w: %s is maintained by %s, not the sandbox maintainer %s", ErrSandbox, id, mnt, s.Maintainer)
+		}
+	}
+	return nil
+}
+
+// checkExistingRoute refuses to change or delete a route outside the
+// sandbox, including one in a sandbox range that a production maintainer
+// holds. A route that does not exist is left for the write to report.
+func (s *Sandbox) checkExistingRoute(ctx context.Context, client Client, prefix, asn string) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.checkRange(prefix, asn); err != nil {
+		return err
+	}
+
+	existing, err := client.GetRoute(ctx, prefix, asn)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check the route's maintainers: %w", err)
+	}
+	return s.checkMaintainers(existing.ID(), existing.MntBy)
+}
+
+// checkContact refuses every contact write: contacts belong to the account
+// rather than a maintainer, so no contact is sandboxed.
+func (s *Sandbox) checkContact() error {
+	if s == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: contacts belong to the production account", ErrSandbox)
+}
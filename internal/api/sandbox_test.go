@@ -0,0 +1,124 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseASNRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    api.ASNRange
+		wantErr bool
+	}{
+		{"AS64512", api.ASNRange{First: 64512, Last: 64512}, false},
+		{"64512-65534", api.ASNRange{First: 64512, Last: 65534}, false},
+		{"4200000000-4294967294", api.ASNRange{First: 4200000000, Last: 4294967294}, false},
+		{"65534-64512", api.ASNRange{}, true},
+		{"ASX", api.ASNRange{}, true},
+		{"4294967296", api.ASNRange{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := api.ParseASNRange(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseASNRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseASNRange(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	if _, err := api.NewSandbox("", nil, nil); err == nil {
+		t.Error("NewSandbox() accepted an empty maintainer")
+	}
+	sandbox, err := api.NewSandbox("maint-training", nil, nil)
+	if err != nil {
+		t.Fatalf("NewSandbox() failed: %v", err)
+	}
+
+	// A production maintainer holds a route inside a documentation prefix
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	data := `{
+  "routes": [
+    {"route": "198.51.100.0/24", "origin": "AS64500", "mnt_by": ["MAINT-PROD"], "source": "RADB"}
+  ],
+  "contacts": [
+    {"id": "CONTACT-1", "name": "NOC", "email": "noc@example.com", "role": "tech"}
+  ]
+}`
+	if err := os.WriteFile(fixture, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	client := api.NewMemoryClient("RADB", logger)
+	if err := client.LoadFixture(fixture); err != nil {
+		t.Fatalf("LoadFixture() failed: %v", err)
+	}
+	client.SetSandbox(sandbox)
+
+	route := &models.RouteObject{Route: "192.0.2.0/25", Origin: "AS64512"}
+	if err := client.CreateRoute(ctx, route); err != nil {
+		t.Fatalf("CreateRoute() in the sandbox failed: %v", err)
+	}
+	if len(route.MntBy) != 1 || route.MntBy[0] != "MAINT-TRAINING" {
+		t.Errorf("CreateRoute() mnt-by = %v, want the sandbox maintainer", route.MntBy)
+	}
+	if err := client.DeleteRoute(ctx, "192.0.2.0/25", "AS64512"); err != nil {
+		t.Errorf("DeleteRoute() in the sandbox failed: %v", err)
+	}
+
+	refused := []struct {
+		name  string
+		write func() error
+	}{
+		{"production prefix", func() error {
+			return client.CreateRoute(ctx, &models.RouteObject{Route: "8.8.8.0/24", Origin: "AS64512"})
+		}},
+		{"covering prefix", func() error {
+			return client.CreateRoute(ctx, &models.RouteObject{Route: "192.0.0.0/16", Origin: "AS64512"})
+		}},
+		{"production origin", func() error {
+			return client.CreateRoute(ctx, &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS15169"})
+		}},
+		{"production maintainer", func() error {
+			return client.CreateRoute(ctx, &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64512", MntBy: []string{"MAINT-PROD"}})
+		}},
+		{"update of a production route", func() error {
+			return client.UpdateRoute(ctx, &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TRAINING"}, Source: "RADB"})
+		}},
+		{"delete of a production route", func() error {
+			return client.DeleteRoute(ctx, "198.51.100.0/24", "AS64500")
+		}},
+		{"contact delete", func() error {
+			return client.DeleteContact(ctx, "CONTACT-1")
+		}},
+	}
+	for _, tt := range refused {
+		if err := tt.write(); !errors.Is(err, api.ErrSandbox) {
+			t.Errorf("%s: error = %v, want ErrSandbox", tt.name, err)
+		}
+	}
+	if _, err := client.GetRoute(ctx, "198.51.100.0/24", "AS64500"); err != nil {
+		t.Errorf("production route was changed: %v", err)
+	}
+
+	// Lifting the sandbox allows the writes again
+	client.SetSandbox(nil)
+	if err := client.DeleteContact(ctx, "CONTACT-1"); err != nil {
+		t.Errorf("DeleteContact() without a sandbox failed: %v", err)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
+	"github.com/bss/radb-client/internal/models"
 	"github.com/bss/radb-client/pkg/validator"
 )
 
@@ -25,7 +27,7 @@ type SearchResult struct {
 func (c *HTTPClient) Search(ctx context.Context, query string, objectType string) (interface{}, error) {
 	c.logger.Debugf("Search called with query=%s type=%s", query, objectType)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return nil, fmt.Errorf("not authenticated: please login first")
 	}
 
@@ -41,7 +43,7 @@ func (c *HTTPClient) Search(ctx context.Context, query string, objectType string
 	}
 
 	// Use lowercase source name in path
-	sourceLower := "radb"  // API requires lowercase
+	sourceLower := "radb" // API requires lowercase
 	path := fmt.Sprintf("/%s/search?%s", sourceLower, params.Encode())
 	resp, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
@@ -54,16 +56,15 @@ func (c *HTTPClient) Search(ctx context.Context, query string, objectType string
 		return nil, fmt.Errorf("search failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	// Read response body for debugging
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-	c.logger.Debugf("[DEBUG] Response body (first 500 chars): %s", string(body[:min(500, len(body))]))
+	c.Tracef("search: response body (first 500 chars): %s", string(body[:min(500, len(body))]))
 
 	var result SearchResult
 	if err := json.Unmarshal(body, &result); err != nil {
-		c.logger.Debugf("[DEBUG] JSON decode failed, body might be RPSL format")
+		c.Tracef("search: JSON decode failed, treating the response as RPSL: %v", err)
 		// Return raw text as a simple result
 		return map[string]interface{}{
 			"raw_response": string(body),
@@ -75,12 +76,211 @@ func (c *HTTPClient) Search(ctx context.Context, query string, objectType string
 	return &result, nil
 }
 
+// SearchDiff compares a search's results with a baseline result set.
+type SearchDiff struct {
+	Query     string                   `json:"query"`
+	Added     []map[string]interface{} `json:"added"`
+	Removed   []map[string]interface{} `json:"removed"`
+	Unchanged int                      `json:"unchanged"`
+}
+
+// HasChanges reports whether any result was added or removed.
+func (d *SearchDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// DiffSearchResults reports the results of current missing from baseline
+// (added) and those of baseline missing from current (removed). Results
+// are matched by SearchResultKey.
+func DiffSearchResults(baseline, current *SearchResult) *SearchDiff {
+	diff := &SearchDiff{
+		Query:   current.Query,
+		Added:   make([]map[string]interface{}, 0),
+		Removed: make([]map[string]interface{}, 0),
+	}
+
+	before := make(map[string]bool, len(baseline.Results))
+	for _, result := range baseline.Results {
+		before[SearchResultKey(result)] = true
+	}
+
+	after := make(map[string]bool, len(current.Results))
+	for _, result := range current.Results {
+		key := SearchResultKey(result)
+		after[key] = true
+		if before[key] {
+			diff.Unchanged++
+		} else {
+			diff.Added = append(diff.Added, result)
+		}
+	}
+
+	for _, result := range baseline.Results {
+		if !after[SearchResultKey(result)] {
+			diff.Removed = append(diff.Removed, result)
+		}
+	}
+
+	return diff
+}
+
+// SearchResultKey identifies a search result across runs: routes by URN,
+// contacts by ID, and other objects by their type and primary key, or by
+// their full content when neither is present.
+func SearchResultKey(result map[string]interface{}) string {
+	str := func(key string) string {
+		value, _ := result[key].(string)
+		return value
+	}
+
+	if route, origin := str("route"), str("origin"); route != "" && origin != "" {
+		return models.RouteURN(route, strings.ToUpper(origin))
+	}
+	if route, origin := str("route6"), str("origin"); route != "" && origin != "" {
+		return models.RouteURN(route, strings.ToUpper(origin))
+	}
+	if str("type") == "contact" && str("id") != "" {
+		return models.ContactURN(str("id"))
+	}
+	for _, key := range []string{"primary-key", "primary_key", "key", "id"} {
+		if value := str(key); value != "" {
+			return str("type") + ":" + value
+		}
+	}
+
+	// Maps marshal with sorted keys, so equal results encode identically
+	data, _ := json.Marshal(result)
+	return string(data)
+}
+
+// ASSetMembers looks up an as-set with Search and returns its members
+// attribute, from JSON results or RPSL text. It wraps ErrNotFound when no
+// as-set by that name exists.
+func ASSetMembers(ctx context.Context, client Client, name string) ([]string, error) {
+	attrs, err := ObjectAttributes(ctx, client, "as-set", name)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []string
+	for _, value := range attrs["members"] {
+		members = append(members, splitMembers(value)...)
+	}
+	return members, nil
+}
+
+// ObjectAttributes looks up an RPSL object of the given type, such as as-set
+// or aut-num, with Search and returns its attributes by lowercase name, from
+// JSON results or RPSL text. It wraps ErrNotFound when no such object exists.
+func ObjectAttributes(ctx context.Context, client Client, objectType, name string) (map[string][]string, error) {
+	results, err := client.Search(ctx, name, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch results := results.(type) {
+	case *SearchResult:
+		for _, result := range results.Results {
+			key, _ := result[objectType].(string)
+			if key == "" {
+				key, _ = result["primary-key"].(string)
+			}
+			if !strings.EqualFold(key, name) {
+				continue
+			}
+
+			attrs := make(map[string][]string, len(result))
+			for attr, value := range result {
+				attr = strings.ToLower(attr)
+				switch value := value.(type) {
+				case string:
+					attrs[attr] = append(attrs[attr], value)
+				case []interface{}:
+					for _, v := range value {
+						if v, ok := v.(string); ok {
+							attrs[attr] = append(attrs[attr], v)
+						}
+					}
+				}
+			}
+			return attrs, nil
+		}
+	case map[string]interface{}:
+		if text, ok := results["raw_response"].(string); ok {
+			for _, attrs := range ParseRPSL(text) {
+				if values := attrs[objectType]; len(values) > 0 && strings.EqualFold(values[0], name) {
+					return attrs, nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%s %s: %w", objectType, name, ErrNotFound)
+}
+
+// ParseRPSL parses RPSL text into objects, each mapping lowercase attribute
+// names to their values in order. Objects are separated by blank lines,
+// comments are dropped, and continuation lines are joined to the value they
+// continue with a space.
+func ParseRPSL(text string) []map[string][]string {
+	var (
+		objects []map[string][]string
+		current map[string][]string
+		attr    string
+	)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			current = nil
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' || line[0] == '+' {
+			// Continuation of the previous attribute
+			if current == nil || attr == "" {
+				continue
+			}
+			if value := strings.TrimSpace(strings.TrimPrefix(line, "+")); value != "" {
+				values := current[attr]
+				values[len(values)-1] = strings.TrimSpace(values[len(values)-1] + " " + value)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if current == nil {
+			current = make(map[string][]string)
+			objects = append(objects, current)
+		}
+		attr = strings.ToLower(strings.TrimSpace(key))
+		current[attr] = append(current[attr], strings.TrimSpace(value))
+	}
+	return objects
+}
+
+// splitMembers splits a comma-separated members value.
+func splitMembers(value string) []string {
+	var members []string
+	for _, member := range strings.Split(value, ",") {
+		if member = strings.TrimSpace(member); member != "" {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
 // ValidateASN validates an ASN with the RADb API.
 // It checks if the ASN exists and returns true if it's valid.
 func (c *HTTPClient) ValidateASN(ctx context.Context, asn string) (bool, error) {
 	c.logger.Debugf("ValidateASN called for %s", asn)
 
-	if !c.authenticated {
+	if !c.IsAuthenticated() {
 		return false, fmt.Errorf("not authenticated: please login first")
 	}
 
@@ -114,8 +314,8 @@ func (c *HTTPClient) ValidateASN(ctx context.Context, asn string) (bool, error)
 		ASN   string `json:"asn"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&validationResult); err != nil {
-		return false, fmt.Errorf("failed to decode validation response: %w", err)
+	if err := c.decodeResponse(resp, "validation", &validationResult, nil); err != nil {
+		return false, err
 	}
 
 	c.logger.Infof("ASN %s validation result: %v", asn, validationResult.Valid)
@@ -124,7 +324,7 @@ func (c *HTTPClient) ValidateASN(ctx context.Context, asn string) (bool, error)
 
 // SearchRoutesByPrefix searches for routes matching a specific prefix.
 func (c *HTTPClient) SearchRoutesByPrefix(ctx context.Context, prefix string) (interface{}, error) {
-	if err := validator.ValidatePrefix(prefix); err != nil {
+	if err := validator.ValidateLookupPrefix(prefix); err != nil {
 		return nil, fmt.Errorf("invalid prefix: %w", err)
 	}
 	return c.Search(ctx, prefix, "route")
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiffSearchResults(t *testing.T) {
+	baseline := &SearchResult{
+		Query: "AS64500",
+		Results: []map[string]interface{}{
+			{"type": "route", "route": "192.0.2.0/24", "origin": "AS64500"},
+			{"type": "route", "route": "198.51.100.0/24", "origin": "as64500"},
+			{"type": "mntner", "primary-key": "MAINT-X"},
+		},
+	}
+	current := &SearchResult{
+		Query: "AS64500",
+		Results: []map[string]interface{}{
+			// Origin case and extra fields do not make a result new
+			{"type": "route", "route": "198.51.100.0/24", "origin": "AS64500", "descr": "moved"},
+			{"type": "route", "route": "203.0.113.0/24", "origin": "AS64500"},
+			{"type": "mntner", "primary-key": "MAINT-X"},
+		},
+	}
+
+	diff := DiffSearchResults(baseline, current)
+
+	if diff.Unchanged != 2 {
+		t.Errorf("Unchanged = %d, want 2", diff.Unchanged)
+	}
+	if len(diff.Added) != 1 || diff.Added[0]["route"] != "203.0.113.0/24" {
+		t.Errorf("Added = %v, want 203.0.113.0/24", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0]["route"] != "192.0.2.0/24" {
+		t.Errorf("Removed = %v, want 192.0.2.0/24", diff.Removed)
+	}
+	if !diff.HasChanges() {
+		t.Error("HasChanges() = false, want true")
+	}
+}
+
+func TestSearchResultKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		result map[string]interface{}
+		want   string
+	}{
+		{"route", map[string]interface{}{"route": "192.0.2.0/24", "origin": "as64500"}, "radb:route:192.0.2.0/24:AS64500"},
+		{"route6", map[string]interface{}{"route6": "2001:db8::/32", "origin": "AS64500"}, "radb:route:2001:db8::/32:AS64500"},
+		{"contact", map[string]interface{}{"type": "contact", "id": "C1"}, "radb:contact:C1"},
+		{"primary key", map[string]interface{}{"type": "as-set", "primary-key": "AS-EXAMPLE"}, "as-set:AS-EXAMPLE"},
+		{"content", map[string]interface{}{"b": 1, "a": "x"}, `{"a":"x","b":1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchResultKey(tt.result); got != tt.want {
+				t.Errorf("SearchResultKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// searchStub answers Search with fixed results.
+type searchStub struct {
+	*MemoryClient
+	results interface{}
+}
+
+func (s *searchStub) Search(ctx context.Context, query string, objectType string) (interface{}, error) {
+	return s.results, nil
+}
+
+func TestASSetMembers(t *testing.T) {
+	ctx := context.Background()
+	want := []string{"AS64500", "AS-NESTED", "AS64501"}
+
+	tests := []struct {
+		name    string
+		results interface{}
+	}{
+		{
+			name: "json list",
+			results: &SearchResult{Results: []map[string]interface{}{
+				{"type": "as-set", "as-set": "AS-OTHER", "members": "AS64999"},
+				{"type": "as-set", "as-set": "as-example", "members": []interface{}{"AS64500, AS-NESTED", "AS64501"}},
+			}},
+		},
+		{
+			name: "rpsl",
+			results: map[string]interface{}{
+				"format": "rpsl",
+				"raw_response": "as-set: AS-OTHER\nmembers: AS64999\n\n" +
+					"as-set:  AS-EXAMPLE\ndescr:   Example\nmembers: AS64500, # customers\n         AS-NESTED\nmembers: AS64501\nsource:  RADB\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &searchStub{MemoryClient: NewMemoryClient("RADB", nil), results: tt.results}
+			members, err := ASSetMembers(ctx, client, "AS-EXAMPLE")
+			if err != nil {
+				t.Fatalf("ASSetMembers() failed: %v", err)
+			}
+			if !reflect.DeepEqual(members, want) {
+				t.Errorf("ASSetMembers() = %v, want %v", members, want)
+			}
+		})
+	}
+
+	client := &searchStub{MemoryClient: NewMemoryClient("RADB", nil), results: &SearchResult{}}
+	if _, err := ASSetMembers(ctx, client, "AS-MISSING"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ASSetMembers() of a missing set = %v, want ErrNotFound", err)
+	}
+}
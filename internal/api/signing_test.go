@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRequestSigning(t *testing.T) {
+	key := []byte("gateway-secret")
+	verifier := NewRequestSigner("mirror-1", key, SigningHeaders{Signature: "X-Gateway-Signature"})
+
+	var verified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := verifier.Signature(r.Method, r.URL.RequestURI(), r.Header.Get("X-Signature-Timestamp"), body)
+
+		switch {
+		case r.Header.Get("Authorization") != "":
+			t.Errorf("%s %s sent Basic Auth alongside a signature", r.Method, r.URL)
+		case r.Header.Get("X-Signature-Key-Id") != "mirror-1":
+			t.Errorf("key ID header = %q, want mirror-1", r.Header.Get("X-Signature-Key-Id"))
+		case r.Header.Get("X-Gateway-Signature") != want:
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		verified++
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.SetRequestSigner(NewRequestSigner("mirror-1", key, SigningHeaders{Signature: "X-Gateway-Signature"}))
+	if !client.IsAuthenticated() {
+		t.Fatal("a client with a signer is not authenticated")
+	}
+
+	if _, err := client.ListRoutes(context.Background(), map[string]string{"origin": "AS64500"}); err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	route := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"}
+	if err := client.CreateRoute(context.Background(), route); err != nil {
+		t.Fatalf("CreateRoute() failed: %v", err)
+	}
+	if verified != 2 {
+		t.Errorf("%d requests verified, want 2", verified)
+	}
+
+	// A signature from another key is rejected
+	client.SetRequestSigner(NewRequestSigner("mirror-1", []byte("wrong"), SigningHeaders{Signature: "X-Gateway-Signature"}))
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	if _, err := client.ListRoutes(context.Background(), nil); err == nil {
+		t.Error("ListRoutes() succeeded with the wrong signing key")
+	}
+}
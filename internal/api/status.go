@@ -0,0 +1,117 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// StatusReporter is implemented by clients that track their own health.
+type StatusReporter interface {
+	Status() models.ClientStatus
+}
+
+// Ensure HTTPClient implements StatusReporter.
+var _ StatusReporter = (*HTTPClient)(nil)
+
+// Status returns a snapshot of the client's recent request history and
+// the last request budget reported by the server.
+func (c *HTTPClient) Status() models.ClientStatus {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	status := c.status
+	status.RequestsPerMinute = c.rateLimiter.GetCurrentRate()
+	status.ConfiguredRequestsPerMinute = c.rateLimiter.BaseRate()
+	if len(c.endpointLimiters) > 0 {
+		status.EndpointRequestsPerMinute = make(map[string]int, len(c.endpointLimiters))
+		for class, limiter := range c.endpointLimiters {
+			status.EndpointRequestsPerMinute[string(class)] = limiter.GetCurrentRate()
+		}
+	}
+	if status.RateLimit != nil {
+		rateLimit := *status.RateLimit
+		status.RateLimit = &rateLimit
+	}
+	return status
+}
+
+// observe records the outcome of a single HTTP request.
+func (c *HTTPClient) observe(method, path string, resp *http.Response, err error) {
+	now := time.Now()
+
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	c.status.Requests++
+	c.status.LastRequestAt = &now
+
+	if resp != nil {
+		if rateLimit := parseRateLimit(resp.Header, now); rateLimit != nil {
+			c.status.RateLimit = rateLimit
+		}
+	}
+
+	switch {
+	case err != nil:
+		c.status.LastError = method + " " + path + ": " + err.Error()
+	case isFailureStatus(resp.StatusCode):
+		c.status.LastError = method + " " + path + ": " + resp.Status
+		if resp.StatusCode == http.StatusUnauthorized {
+			c.status.CredentialsRejected = true
+		}
+	default:
+		c.status.ConsecutiveFailures = 0
+		c.status.CredentialsRejected = false
+		return
+	}
+	c.status.LastErrorAt = &now
+	c.status.ConsecutiveFailures++
+}
+
+// isFailureStatus reports whether a status code indicates a problem with the
+// API or the client rather than an expected outcome such as 404 Not Found.
+func isFailureStatus(code int) bool {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return true
+	}
+	return code >= 500
+}
+
+// maxResetDelta is the largest reset value read as seconds from now; larger
+// values are Unix timestamps.
+const maxResetDelta = 365 * 24 * 60 * 60
+
+// parseRateLimit reads a request budget from X-RateLimit-* or the IETF
+// RateLimit-* headers. Reset may be seconds from now or a Unix timestamp.
+func parseRateLimit(header http.Header, now time.Time) *models.RateLimitStatus {
+	get := func(name string) string {
+		if v := header.Get("X-RateLimit-" + name); v != "" {
+			return v
+		}
+		return header.Get("RateLimit-" + name)
+	}
+
+	remaining, err := strconv.Atoi(get("Remaining"))
+	if err != nil {
+		return nil
+	}
+
+	status := &models.RateLimitStatus{Remaining: remaining}
+	if limit, err := strconv.Atoi(get("Limit")); err == nil {
+		status.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(get("Reset"), 10, 64); err == nil {
+		var at time.Time
+		if reset > maxResetDelta {
+			at = time.Unix(reset, 0)
+		} else {
+			at = now.Add(time.Duration(reset) * time.Second)
+		}
+		status.Reset = &at
+	}
+	return status
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusRecordsRateLimitAndErrors(t *testing.T) {
+	failStatus := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		if failStatus != 0 {
+			w.WriteHeader(failStatus)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+
+	if _, err := client.ListRoutes(context.Background(), nil); err != nil {
+		t.Fatalf("ListRoutes failed: %v", err)
+	}
+
+	status := client.Status()
+	if status.Requests != 1 || status.LastRequestAt == nil {
+		t.Errorf("Requests = %d, LastRequestAt = %v; want one recorded request", status.Requests, status.LastRequestAt)
+	}
+	if status.RequestsPerMinute != 60 {
+		t.Errorf("RequestsPerMinute = %d, want 60", status.RequestsPerMinute)
+	}
+	if status.RateLimit == nil || status.RateLimit.Limit != 100 || status.RateLimit.Remaining != 42 {
+		t.Fatalf("RateLimit = %+v, want 42 of 100", status.RateLimit)
+	}
+	if until := time.Until(*status.RateLimit.Reset); until <= 0 || until > 30*time.Second {
+		t.Errorf("Reset in %s, want within 30s", until)
+	}
+	if status.LastError != "" {
+		t.Errorf("LastError = %q, want none", status.LastError)
+	}
+
+	failStatus = http.StatusForbidden
+	client.ListRoutes(context.Background(), nil)
+
+	status = client.Status()
+	if !strings.Contains(status.LastError, "403") || status.LastErrorAt == nil {
+		t.Errorf("LastError = %q, want the 403 response", status.LastError)
+	}
+	if status.ConsecutiveFailures != 1 {
+		t.Errorf("ConsecutiveFailures = %d, want 1", status.ConsecutiveFailures)
+	}
+	if status.CredentialsRejected {
+		t.Error("CredentialsRejected set by a 403 response")
+	}
+
+	// A 401 marks the credentials rejected until a request succeeds
+	failStatus = http.StatusUnauthorized
+	client.ListRoutes(context.Background(), nil)
+	if !client.Status().CredentialsRejected {
+		t.Error("CredentialsRejected not set by a 401 response")
+	}
+	failStatus = 0
+	if _, err := client.ListRoutes(context.Background(), nil); err != nil {
+		t.Fatalf("ListRoutes failed: %v", err)
+	}
+	if client.Status().CredentialsRejected {
+		t.Error("CredentialsRejected still set after a successful request")
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+
+	header := http.Header{}
+	if got := parseRateLimit(header, now); got != nil {
+		t.Errorf("parseRateLimit() = %+v without headers, want nil", got)
+	}
+
+	header.Set("RateLimit-Remaining", "5")
+	header.Set("RateLimit-Reset", "1700000600")
+	got := parseRateLimit(header, now)
+	if got == nil || got.Remaining != 5 {
+		t.Fatalf("parseRateLimit() = %+v, want 5 remaining", got)
+	}
+	if got.Reset == nil || !got.Reset.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("Reset = %v, want Unix timestamp %v", got.Reset, now.Add(10*time.Minute))
+	}
+}
@@ -2,32 +2,68 @@ package api
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/bss/radb-client/internal/models"
 )
 
 // RouteStream provides an iterator for streaming routes in batches.
+// Clients implementing RouteStreamingClient are decoded route by route, so
+// at most one batch is held in memory whatever the page size; clients
+// implementing PagedClient are read with server cursors; others fall back to
+// offset and limit filters.
+//
+// Token checkpoints the stream's position and Seek restores it, so a long
+// export can pick up where an interrupted run stopped.
 type RouteStream struct {
-	client    *HTTPClient
-	ctx       context.Context
-	batchSize int
-	filters   map[string]string
-	offset    int
-	buffer    []models.RouteObject
-	bufferPos int
-	done      bool
-	err       error
+	client       Client
+	ctx          context.Context
+	batchSize    int
+	filters      map[string]string
+	offset       int
+	cursor       string
+	skip         int // Routes to discard from the next page, after a Seek
+	reader       *RoutePageReader
+	readerCursor string // Cursor the open reader's page was requested with
+	readerPos    int    // Routes read so far from the open reader's page
+	buffer       []models.RouteObject
+	bufferPos    int
+	batchCursor  string // Cursor of the page the buffered batch came from
+	batchPos     int    // Position of the batch's first route in that page
+	done         bool
+	err          error
+}
+
+// routeBatch is one batch of routes and where it starts in the listing.
+// A batch never spans pages.
+type routeBatch struct {
+	routes []models.RouteObject
+	cursor string
+	start  int
+	more   bool
+}
+
+// streamToken is the decoded form of a RouteStream checkpoint.
+type streamToken struct {
+	Cursor   string `json:"cursor,omitempty"`
+	Position int    `json:"position"`
 }
 
 // StreamRoutes creates a new route stream for memory-efficient processing.
 func (c *HTTPClient) StreamRoutes(ctx context.Context, filters map[string]string, batchSize int) *RouteStream {
+	return NewRouteStream(ctx, c, filters, batchSize)
+}
+
+// NewRouteStream creates a route stream over any Client implementation.
+func NewRouteStream(ctx context.Context, client Client, filters map[string]string, batchSize int) *RouteStream {
 	if batchSize <= 0 {
 		batchSize = 100
 	}
 
 	return &RouteStream{
-		client:    c,
+		client:    client,
 		ctx:       ctx,
 		batchSize: batchSize,
 		filters:   filters,
@@ -36,21 +72,106 @@ func (c *HTTPClient) StreamRoutes(ctx context.Context, filters map[string]string
 }
 
 // Next advances to the next route and returns true if a route is available.
-// Returns false when there are no more routes or an error occurred.
+// Returns false when there are no more routes or an error occurred. The
+// context is checked before each batch is fetched, so a cancelled stream
+// stops at a batch boundary with the context's error.
 func (s *RouteStream) Next() bool {
-	if s.done {
-		return false
-	}
-
 	// If we have routes in the buffer, return the next one
 	if s.bufferPos < len(s.buffer) {
 		s.bufferPos++
 		return true
 	}
 
-	// Need to fetch the next batch
-	s.bufferPos = 0
+	for !s.done {
+		if err := s.ctx.Err(); err != nil {
+			s.err = err
+			s.done = true
+			return false
+		}
+
+		batch, err := s.fetch()
+		if err != nil {
+			s.err = err
+			s.done = true
+			return false
+		}
+		s.done = !batch.more
+
+		// A page can be empty, or emptied by a Seek, with more to follow
+		if len(batch.routes) == 0 {
+			continue
+		}
+
+		s.buffer = batch.routes
+		s.batchCursor = batch.cursor
+		s.batchPos = batch.start
+		s.bufferPos = 1 // Move to first item
+		return true
+	}
+
+	return false
+}
+
+// Token returns a checkpoint of the stream's position. Seeking a new stream
+// with the same client and filters to the token resumes after the route
+// last returned by Next.
+func (s *RouteStream) Token() string {
+	data, _ := json.Marshal(streamToken{Cursor: s.batchCursor, Position: s.batchPos + s.bufferPos})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Seek moves the stream to a position returned by Token and clears any
+// error, so Next continues from there.
+func (s *RouteStream) Seek(token string) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return fmt.Errorf("invalid stream token: %w", err)
+	}
+	var pos streamToken
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return fmt.Errorf("invalid stream token: %w", err)
+	}
+	if pos.Position < 0 {
+		return fmt.Errorf("invalid stream token: negative position %d", pos.Position)
+	}
+
+	if s.reader != nil {
+		s.reader.Close()
+		s.reader = nil
+	}
+
+	// Cursor-based reads skip into the token's page; offset-based reads
+	// start at the position, which is then an absolute offset
+	s.cursor = pos.Cursor
+	s.skip = pos.Position
+	s.offset = pos.Position
+	s.batchCursor = pos.Cursor
+	s.batchPos = pos.Position
 	s.buffer = s.buffer[:0]
+	s.bufferPos = 0
+	s.done = false
+	s.err = nil
+	return nil
+}
+
+// fetch retrieves the next batch.
+func (s *RouteStream) fetch() (routeBatch, error) {
+	if streaming, ok := s.client.(RouteStreamingClient); ok {
+		return s.decode(streaming)
+	}
+
+	if paged, ok := s.client.(PagedClient); ok {
+		routeList, next, err := paged.ListRoutesPage(s.ctx, s.filters, s.batchSize, s.cursor)
+		if err != nil {
+			return routeBatch{}, err
+		}
+
+		start := min(s.skip, len(routeList.Routes))
+		batch := routeBatch{routes: routeList.Routes[start:], cursor: s.cursor, start: start, more: next != ""}
+		s.cursor = next
+		s.skip = 0
+		return batch, nil
+	}
 
 	// Add pagination to filters
 	filters := make(map[string]string)
@@ -60,31 +181,57 @@ func (s *RouteStream) Next() bool {
 	filters["offset"] = fmt.Sprintf("%d", s.offset)
 	filters["limit"] = fmt.Sprintf("%d", s.batchSize)
 
-	// Fetch next batch
 	routeList, err := s.client.ListRoutes(s.ctx, filters)
 	if err != nil {
-		s.err = err
-		s.done = true
-		return false
+		return routeBatch{}, err
 	}
 
-	// Check if we got any routes
-	if len(routeList.Routes) == 0 {
-		s.done = true
-		return false
+	// If we got fewer routes than requested, we're done after this batch
+	batch := routeBatch{routes: routeList.Routes, start: s.offset, more: len(routeList.Routes) >= s.batchSize}
+	s.offset += len(routeList.Routes)
+	return batch, nil
+}
+
+// decode reads up to one batch of routes from the open page, opening the
+// next page once the current one has run out.
+func (s *RouteStream) decode(client RouteStreamingClient) (routeBatch, error) {
+	if s.reader == nil {
+		reader, err := client.OpenRoutesPage(s.ctx, s.filters, s.batchSize, s.cursor)
+		if err != nil {
+			return routeBatch{}, err
+		}
+		s.reader = reader
+		s.readerCursor = s.cursor
+		s.readerPos = 0
 	}
 
-	// Update buffer and offset
-	s.buffer = routeList.Routes
-	s.offset += len(routeList.Routes)
+	batch := routeBatch{routes: make([]models.RouteObject, 0, s.batchSize), cursor: s.readerCursor}
+	for len(batch.routes) < s.batchSize && s.reader.Next() {
+		s.readerPos++
+		if s.skip > 0 {
+			s.skip--
+			continue
+		}
+		batch.routes = append(batch.routes, s.reader.Route())
+	}
+	batch.start = s.readerPos - len(batch.routes)
 
-	// If we got fewer routes than requested, we're done after this batch
-	if len(routeList.Routes) < s.batchSize {
-		s.done = true
+	if len(batch.routes) == s.batchSize {
+		batch.more = true
+		return batch, nil
 	}
 
-	s.bufferPos = 1 // Move to first item
-	return true
+	// The page has run out
+	err := s.reader.Err()
+	s.cursor = s.reader.Cursor()
+	s.reader.Close()
+	s.reader = nil
+	s.skip = 0
+	if err != nil {
+		return routeBatch{}, err
+	}
+	batch.more = s.cursor != ""
+	return batch, nil
 }
 
 // Route returns the current route. Only valid after Next() returns true.
@@ -104,15 +251,22 @@ func (s *RouteStream) Err() error {
 func (s *RouteStream) Close() error {
 	s.done = true
 	s.buffer = nil
+	if s.reader != nil {
+		err := s.reader.Close()
+		s.reader = nil
+		return err
+	}
 	return nil
 }
 
 // ContactStream provides an iterator for streaming contacts in batches.
+// Clients implementing PagedClient are read page by page; others are loaded
+// in a single batch.
 type ContactStream struct {
-	client    *HTTPClient
+	client    Client
 	ctx       context.Context
 	batchSize int
-	offset    int
+	cursor    string
 	buffer    []models.Contact
 	bufferPos int
 	done      bool
@@ -121,12 +275,17 @@ type ContactStream struct {
 
 // StreamContacts creates a new contact stream for memory-efficient processing.
 func (c *HTTPClient) StreamContacts(ctx context.Context, batchSize int) *ContactStream {
+	return NewContactStream(ctx, c, batchSize)
+}
+
+// NewContactStream creates a contact stream over any Client implementation.
+func NewContactStream(ctx context.Context, client Client, batchSize int) *ContactStream {
 	if batchSize <= 0 {
 		batchSize = 100
 	}
 
 	return &ContactStream{
-		client:    c,
+		client:    client,
 		ctx:       ctx,
 		batchSize: batchSize,
 		buffer:    make([]models.Contact, 0, batchSize),
@@ -135,35 +294,41 @@ func (c *HTTPClient) StreamContacts(ctx context.Context, batchSize int) *Contact
 
 // Next advances to the next contact and returns true if a contact is available.
 func (s *ContactStream) Next() bool {
-	if s.done {
-		return false
-	}
-
 	if s.bufferPos < len(s.buffer) {
 		s.bufferPos++
 		return true
 	}
 
+	if s.done {
+		return false
+	}
+
 	// Fetch next batch
 	s.bufferPos = 0
 	s.buffer = s.buffer[:0]
 
-	contactList, err := s.client.ListContacts(s.ctx)
+	var contactList *models.ContactList
+	var err error
+	more := false
+	if paged, ok := s.client.(PagedClient); ok {
+		contactList, s.cursor, err = paged.ListContactsPage(s.ctx, s.batchSize, s.cursor)
+		more = s.cursor != ""
+	} else {
+		contactList, err = s.client.ListContacts(s.ctx)
+	}
 	if err != nil {
 		s.err = err
 		s.done = true
 		return false
 	}
 
-	// For contacts, we might not have pagination, so we load all at once
-	// In a real implementation, this would support pagination
 	if len(contactList.Contacts) == 0 {
 		s.done = true
 		return false
 	}
 
 	s.buffer = contactList.Contacts
-	s.done = true // All contacts loaded
+	s.done = !more
 	s.bufferPos = 1
 
 	return true
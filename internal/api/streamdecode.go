@@ -0,0 +1,226 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// RouteStreamingClient is implemented by clients that can decode a route
+// listing page incrementally, so a page of any size is never held in memory
+// at once.
+type RouteStreamingClient interface {
+	OpenRoutesPage(ctx context.Context, filters map[string]string, pageSize int, cursor string) (*RoutePageReader, error)
+}
+
+// Ensure HTTPClient implements RouteStreamingClient.
+var _ RouteStreamingClient = (*HTTPClient)(nil)
+
+// RoutePageReader decodes the routes of one listing page as they arrive.
+// Both bare JSON arrays and the {"results", "next_token"} envelope are read.
+type RoutePageReader struct {
+	resp     *http.Response
+	body     *limitedBody // Nil when the response was buffered or is unlimited
+	dec      *json.Decoder
+	path     string
+	link     string // Cursor from a rel="next" Link header
+	token    string // next_token from the envelope
+	envelope bool
+	inArray  bool
+	route    models.RouteObject
+	err      error
+}
+
+// OpenRoutesPage requests one page of routes and returns a reader over it.
+// Pass an empty cursor for the first page and the reader's Cursor for each
+// following page. The caller must Close the reader.
+func (c *HTTPClient) OpenRoutesPage(ctx context.Context, filters map[string]string, pageSize int, cursor string) (*RoutePageReader, error) {
+	if !c.IsAuthenticated() {
+		return nil, fmt.Errorf("not authenticated: please login first")
+	}
+
+	path := cursor
+	if path == "" {
+		params := url.Values{}
+		for key, value := range filters {
+			params.Add(key, value)
+		}
+		if pageSize > 0 {
+			params.Set("limit", strconv.Itoa(pageSize))
+		}
+
+		path = fmt.Sprintf("/%s/route", c.source)
+		if len(params) > 0 {
+			path += "?" + params.Encode()
+		}
+	}
+
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list routes failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	reader := &RoutePageReader{
+		resp: resp,
+		dec:  json.NewDecoder(resp.Body),
+		path: path,
+	}
+	// A page of any size may be streamed, but no single route may exceed
+	// the object limit
+	if body, ok := resp.Body.(*limitedBody); ok {
+		body.stream(c.maxObjectSize)
+		reader.body = body
+	}
+	if link := nextLink(resp.Header); link != "" {
+		if reader.link, err = c.cursorFromLink(resp.Request.URL, link); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	if err := reader.start(); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to decode routes response: %w", err)
+	}
+	return reader, nil
+}
+
+// start reads up to the first route: past the opening bracket of a bare
+// array, or through the envelope up to its results array.
+func (r *RoutePageReader) start() error {
+	token, err := r.dec.Token()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('['):
+		r.inArray = true
+		return nil
+	case json.Delim('{'):
+		r.envelope = true
+		return r.readEnvelope(true)
+	default:
+		return fmt.Errorf("unexpected %v at start of listing", token)
+	}
+}
+
+// readEnvelope reads envelope fields, keeping next_token and skipping any
+// others. With untilResults it stops once the results array is open;
+// otherwise it reads to the end of the envelope.
+func (r *RoutePageReader) readEnvelope(untilResults bool) error {
+	for r.dec.More() {
+		token, err := r.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected %v in listing envelope", token)
+		}
+
+		switch {
+		case key == "results" && untilResults:
+			token, err := r.dec.Token()
+			if err != nil {
+				return err
+			}
+			if token == json.Delim('[') {
+				r.inArray = true
+				return nil
+			}
+			if token != nil {
+				return fmt.Errorf("results is %v, not an array", token)
+			}
+		case key == "next_token":
+			if err := r.dec.Decode(&r.token); err != nil {
+				return fmt.Errorf("invalid next_token: %w", err)
+			}
+		default:
+			var skip json.RawMessage
+			if err := r.dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Closing brace of the envelope
+	_, err := r.dec.Token()
+	return err
+}
+
+// Next decodes the next route and reports whether there is one. It returns
+// false at the end of the page or on error; check Err to tell them apart.
+func (r *RoutePageReader) Next() bool {
+	if !r.inArray || r.err != nil {
+		return false
+	}
+
+	if r.body != nil {
+		r.body.next(r.dec.InputOffset())
+	}
+	if r.dec.More() {
+		r.route = models.RouteObject{}
+		if err := r.dec.Decode(&r.route); err != nil {
+			r.err = fmt.Errorf("failed to decode route: %w", err)
+			r.inArray = false
+			return false
+		}
+		return true
+	}
+
+	// Closing bracket of the routes array, then the rest of the envelope,
+	// which may still hold next_token
+	r.inArray = false
+	if _, err := r.dec.Token(); err != nil {
+		r.err = fmt.Errorf("failed to decode routes response: %w", err)
+		return false
+	}
+	if r.envelope {
+		if err := r.readEnvelope(false); err != nil {
+			r.err = fmt.Errorf("failed to decode routes response: %w", err)
+		}
+	}
+	return false
+}
+
+// Route returns the route decoded by the last call to Next.
+func (r *RoutePageReader) Route() models.RouteObject {
+	return r.route
+}
+
+// Err returns the error that stopped the reader, if any.
+func (r *RoutePageReader) Err() error {
+	return r.err
+}
+
+// Cursor returns the cursor for the next page, or "" on the last page.
+// It is only complete once Next has returned false.
+func (r *RoutePageReader) Cursor() string {
+	if r.link != "" {
+		return r.link
+	}
+	if r.token != "" {
+		return withQueryParam(r.path, "next_token", r.token)
+	}
+	return ""
+}
+
+// Close releases the response.
+func (r *RoutePageReader) Close() error {
+	return r.resp.Body.Close()
+}
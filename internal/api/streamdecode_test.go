@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRouteStreamDecodesIncrementally(t *testing.T) {
+	// Pages ignore the requested limit and put next_token after the
+	// results, as some mirrors do
+	const pageSize = 250
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start := 0
+		if r.URL.Query().Get("next_token") == "page2" {
+			start = pageSize
+		}
+
+		var routes []string
+		for i := start; i < start+pageSize; i++ {
+			routes = append(routes, fmt.Sprintf(`{"route":"10.%d.%d.0/24","origin":"AS64500"}`, i/256, i%256))
+		}
+		next := `"page2"`
+		if start > 0 {
+			next = "null"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"count":%d,"results":[%s],"meta":{"source":"RADB"},"next_token":%s}`, pageSize, strings.Join(routes, ","), next)
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+	stream := client.StreamRoutes(context.Background(), map[string]string{"origin": "AS64500"}, 100)
+	defer stream.Close()
+
+	count := 0
+	for stream.Next() {
+		if len(stream.buffer) > 100 {
+			t.Fatalf("buffer holds %d routes, want at most 100", len(stream.buffer))
+		}
+		if want := fmt.Sprintf("10.%d.%d.0/24", count/256, count%256); stream.Route().Route != want {
+			t.Fatalf("route %d = %s, want %s", count, stream.Route().Route, want)
+		}
+		count++
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if count != 2*pageSize {
+		t.Errorf("streamed %d routes, want %d", count, 2*pageSize)
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+}
+
+func TestRoutePageReaderRejectsMalformedListing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"route":"192.0.2.0/24","origin":"AS64500"},{"route":`)
+	}))
+	defer server.Close()
+
+	client := newPagedClient(t, server.URL)
+	reader, err := client.OpenRoutesPage(context.Background(), nil, 10, "")
+	if err != nil {
+		t.Fatalf("OpenRoutesPage() failed: %v", err)
+	}
+	defer reader.Close()
+
+	if !reader.Next() || reader.Route().Route != "192.0.2.0/24" {
+		t.Fatalf("first route = %+v, %v; want 192.0.2.0/24", reader.Route(), reader.Err())
+	}
+	if reader.Next() {
+		t.Fatal("Next() returned a route from a truncated listing")
+	}
+	if reader.Err() == nil {
+		t.Error("Err() = nil after a truncated listing")
+	}
+}
+
+func TestRouteStreamSeek(t *testing.T) {
+	for _, useToken := range []bool{false, true} {
+		server := newPagedServer(t, 8, useToken)
+		client := newPagedClient(t, server.URL)
+		client.disableRateLimits()
+
+		// Read through the middle of the second page, checkpointing as we go
+		stream := client.StreamRoutes(context.Background(), nil, 2)
+		var all []string
+		tokens := map[int]string{0: stream.Token()}
+		for stream.Next() {
+			all = append(all, stream.Route().Route)
+			tokens[len(all)] = stream.Token()
+		}
+		stream.Close()
+		if err := stream.Err(); err != nil || len(all) != 8 {
+			t.Fatalf("useToken=%v: streamed %d routes, %v; want 8", useToken, len(all), err)
+		}
+
+		for read, token := range tokens {
+			resumed := client.StreamRoutes(context.Background(), nil, 2)
+			if err := resumed.Seek(token); err != nil {
+				t.Fatalf("Seek(%q) failed: %v", token, err)
+			}
+			var rest []string
+			for resumed.Next() {
+				rest = append(rest, resumed.Route().Route)
+			}
+			resumed.Close()
+
+			if fmt.Sprint(rest) != fmt.Sprint(all[read:]) {
+				t.Errorf("useToken=%v: resumed after %d routes got %v, want %v", useToken, read, rest, all[read:])
+			}
+		}
+	}
+
+	stream := NewRouteStream(context.Background(), NewMemoryClient("RADB", logrus.New()), nil, 2)
+	if err := stream.Seek("not a token"); err == nil {
+		t.Error("Seek() accepted a malformed token")
+	}
+}
+
+func TestRouteStreamStopsWhenCancelled(t *testing.T) {
+	server := newPagedServer(t, 7, false)
+	client := newPagedClient(t, server.URL)
+
+	cmdCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := client.StreamRoutes(cmdCtx, nil, 3)
+	defer stream.Close()
+
+	count := 0
+	for stream.Next() {
+		count++
+		if count == 3 {
+			cancel()
+		}
+	}
+	if count != 3 {
+		t.Errorf("streamed %d routes after cancelling, want the 3 already fetched", count)
+	}
+	if !errors.Is(stream.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", stream.Err())
+	}
+}
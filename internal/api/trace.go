@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SetTrace dumps every request and response, with headers and bodies, to w.
+// Credentials are redacted. Authentication events are traced too; they are
+// never written to the regular log. Call it after RecordCassette or
+// ReplayCassette so replayed traffic is traced as well. A nil w disables tracing.
+func (c *HTTPClient) SetTrace(w io.Writer) {
+	if tt, ok := c.httpClient.Transport.(*traceTransport); ok {
+		c.httpClient.Transport = tt.next
+	}
+	c.trace = nil
+	if w == nil {
+		return
+	}
+
+	c.trace = &traceWriter{w: w}
+	c.httpClient.Transport = &traceTransport{
+		next:    c.httpClient.Transport,
+		baseURL: c.baseURL,
+		out:     c.trace,
+	}
+}
+
+// Tracef writes a line to the trace output, if tracing is enabled.
+func (c *HTTPClient) Tracef(format string, args ...interface{}) {
+	c.trace.printf(format, args...)
+}
+
+// traceWriter serializes writes from concurrent requests.
+type traceWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// printf writes one timestamped line. It does nothing on a nil writer.
+func (t *traceWriter) printf(format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.w, "%s %s\n", time.Now().Format("15:04:05.000"), fmt.Sprintf(format, args...))
+}
+
+// block writes a multi-line dump atomically.
+func (t *traceWriter) block(text string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.w, text)
+}
+
+// traceTransport dumps requests and responses as they pass through.
+type traceTransport struct {
+	next    http.RoundTripper
+	baseURL string
+	out     *traceWriter
+}
+
+// RoundTrip dumps the request, performs it, and dumps the response.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req, t.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var dump strings.Builder
+	fmt.Fprintf(&dump, "%s > %s %s\n", time.Now().Format("15:04:05.000"), recorded.Method, req.URL.String())
+	writeTraceHeaders(&dump, ">", recorded.Headers)
+	writeTraceBody(&dump, ">", recorded.Body)
+	t.out.block(dump.String())
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.out.printf("< %s %s failed after %s: %v", req.Method, req.URL.Path, elapsed, err)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for trace: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Show compressed bodies decompressed, but pass them on untouched
+	shown := body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		if plain, err := gunzip(body); err == nil {
+			shown = plain
+		}
+	}
+
+	dump.Reset()
+	fmt.Fprintf(&dump, "%s < %s (%s)\n", time.Now().Format("15:04:05.000"), resp.Status, elapsed)
+	writeTraceHeaders(&dump, "<", sanitizeHeaders(resp.Header))
+	writeTraceBody(&dump, "<", sanitizeBody(shown))
+	t.out.block(dump.String())
+
+	return resp, nil
+}
+
+// writeTraceHeaders writes headers in sorted order, one per line.
+func writeTraceHeaders(dump *strings.Builder, direction string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(dump, "%s %s: %s\n", direction, name, value)
+		}
+	}
+}
+
+// writeTraceBody writes a body after a blank line, if there is one.
+func writeTraceBody(dump *strings.Builder, direction, body string) {
+	if body == "" {
+		return
+	}
+	fmt.Fprintf(dump, "%s\n", direction)
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		fmt.Fprintf(dump, "%s %s\n", direction, line)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestTraceRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc123")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "CONTACT-1", "name": "NOC", "email": "noc@example.com", "role": "tech", "password": "s3cret"}`))
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	var trace strings.Builder
+	client := NewHTTPClient(server.URL, "RADB", 5, logger)
+	client.SetTrace(&trace)
+	client.Login(context.Background(), "user", "hunter2")
+
+	contact := &models.Contact{Name: "NOC", Email: "noc@example.com", Role: models.ContactRoleTech}
+	if err := client.CreateContact(context.Background(), contact); err != nil {
+		t.Fatalf("CreateContact() failed: %v", err)
+	}
+
+	out := trace.String()
+	for _, want := range []string{
+		"auth: credentials set for user",
+		"> POST " + server.URL + "/RADB/contact",
+		"> Authorization: REDACTED",
+		`> {"id":"","name":"NOC","email":"noc@example.com"`,
+		"< 200 OK",
+		"< Set-Cookie: REDACTED",
+		`"password":"REDACTED"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("trace missing %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"hunter2", "s3cret", "abc123", "Basic "} {
+		if strings.Contains(out, secret) {
+			t.Errorf("trace contains %q:\n%s", secret, out)
+		}
+	}
+
+	// Disabling the trace restores the original transport
+	client.SetTrace(nil)
+	trace.Reset()
+	if _, err := client.GetContact(context.Background(), "CONTACT-1"); err != nil {
+		t.Fatalf("GetContact() failed: %v", err)
+	}
+	if trace.Len() != 0 {
+		t.Errorf("trace written after SetTrace(nil):\n%s", trace.String())
+	}
+}
@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Assertion is an invariant that every matching route must satisfy, such as
+// "routes originated by AS64500 have mnt-by MAINT-X and a descr starting with
+// ACME". Empty match fields match every route; empty requirements are not
+// checked.
+type Assertion struct {
+	Name string
+
+	// Match selects the routes the assertion applies to
+	MatchOrigin string       // Origin ASN
+	MatchPrefix netip.Prefix // Routes within this prefix
+	MatchMntBy  string       // Routes with this maintainer
+
+	// Requirements for the matched routes
+	RequireMntBy  []string       // Every listed maintainer must be present
+	RequireOrigin string         // The route must be originated by this ASN
+	RequireDescr  *regexp.Regexp // Some descr line must match
+}
+
+// Violation is a route that breaks an assertion.
+type Violation struct {
+	Assertion string   `json:"assertion"`
+	Route     string   `json:"route"`
+	Origin    string   `json:"origin"`
+	MntBy     []string `json:"mnt_by,omitempty"`
+	Detail    string   `json:"detail"`
+}
+
+// NewAssertion builds an assertion from its configured form. prefix and
+// descr may be empty; descr is a regular expression.
+func NewAssertion(name, matchOrigin, matchPrefix, matchMntBy string, requireMntBy []string, requireOrigin, requireDescr string) (Assertion, error) {
+	assertion := Assertion{
+		Name:          name,
+		MatchOrigin:   matchOrigin,
+		MatchMntBy:    matchMntBy,
+		RequireMntBy:  requireMntBy,
+		RequireOrigin: requireOrigin,
+	}
+
+	if matchPrefix != "" {
+		prefix, err := netip.ParsePrefix(matchPrefix)
+		if err != nil {
+			return Assertion{}, fmt.Errorf("assertion %s: invalid prefix %q", name, matchPrefix)
+		}
+		assertion.MatchPrefix = prefix.Masked()
+	}
+
+	if requireDescr != "" {
+		re, err := regexp.Compile(requireDescr)
+		if err != nil {
+			return Assertion{}, fmt.Errorf("assertion %s: invalid descr pattern: %w", name, err)
+		}
+		assertion.RequireDescr = re
+	}
+
+	if len(requireMntBy) == 0 && requireOrigin == "" && requireDescr == "" {
+		return Assertion{}, fmt.Errorf("assertion %s: at least one requirement is needed", name)
+	}
+
+	return assertion, nil
+}
+
+// Matches reports whether the assertion applies to route.
+func (a *Assertion) Matches(route *models.RouteObject) bool {
+	if a.MatchOrigin != "" && !strings.EqualFold(route.Origin, a.MatchOrigin) {
+		return false
+	}
+	if a.MatchMntBy != "" && !hasMaintainer(route.MntBy, a.MatchMntBy) {
+		return false
+	}
+	if a.MatchPrefix.IsValid() {
+		prefix, err := netip.ParsePrefix(route.Route)
+		if err != nil || prefix.Bits() < a.MatchPrefix.Bits() || !a.MatchPrefix.Contains(prefix.Addr()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns a description of each requirement route fails.
+func (a *Assertion) Check(route *models.RouteObject) []string {
+	var failures []string
+
+	for _, mntner := range a.RequireMntBy {
+		if !hasMaintainer(route.MntBy, mntner) {
+			failures = append(failures, fmt.Sprintf("missing mnt-by %s", mntner))
+		}
+	}
+
+	if a.RequireOrigin != "" && !strings.EqualFold(route.Origin, a.RequireOrigin) {
+		failures = append(failures, fmt.Sprintf("origin is %s, want %s", route.Origin, a.RequireOrigin))
+	}
+
+	if a.RequireDescr != nil {
+		matched := false
+		for _, descr := range route.Descr {
+			if a.RequireDescr.MatchString(descr) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			failures = append(failures, fmt.Sprintf("no descr matches %s", a.RequireDescr))
+		}
+	}
+
+	return failures
+}
+
+// EvaluateAssertions checks every route against every assertion that
+// applies to it and returns the violations, grouped by assertion.
+func EvaluateAssertions(routes []models.RouteObject, assertions []Assertion) []Violation {
+	violations := make([]Violation, 0)
+	for i := range assertions {
+		assertion := &assertions[i]
+		for j := range routes {
+			route := &routes[j]
+			if !assertion.Matches(route) {
+				continue
+			}
+			for _, failure := range assertion.Check(route) {
+				violations = append(violations, Violation{
+					Assertion: assertion.Name,
+					Route:     route.Route,
+					Origin:    route.Origin,
+					MntBy:     route.MntBy,
+					Detail:    failure,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// hasMaintainer reports whether maintainers includes name, ignoring case.
+func hasMaintainer(maintainers []string, name string) bool {
+	for _, mntner := range maintainers {
+		if strings.EqualFold(mntner, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestEvaluateAssertions(t *testing.T) {
+	routes := []models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"maint-x"}, Descr: []string{"ACME Corp"}},
+		{Route: "198.51.100.0/24", Origin: "AS64500", MntBy: []string{"MAINT-Y"}, Descr: []string{"Other"}},
+		{Route: "203.0.113.0/25", Origin: "AS64501", MntBy: []string{"MAINT-X"}},
+		{Route: "203.0.112.0/24", Origin: "AS64501", MntBy: []string{"MAINT-X"}},
+	}
+
+	byOrigin, err := NewAssertion("as64500", "AS64500", "", "", []string{"MAINT-X"}, "", "^ACME")
+	if err != nil {
+		t.Fatalf("NewAssertion() failed: %v", err)
+	}
+	byPrefix, err := NewAssertion("block", "", "203.0.113.0/24", "", nil, "AS64500", "")
+	if err != nil {
+		t.Fatalf("NewAssertion() failed: %v", err)
+	}
+
+	got := EvaluateAssertions(routes, []Assertion{byOrigin, byPrefix})
+	want := []Violation{
+		{Assertion: "as64500", Route: "198.51.100.0/24", Origin: "AS64500", MntBy: []string{"MAINT-Y"}, Detail: "missing mnt-by MAINT-X"},
+		{Assertion: "as64500", Route: "198.51.100.0/24", Origin: "AS64500", MntBy: []string{"MAINT-Y"}, Detail: "no descr matches ^ACME"},
+		{Assertion: "block", Route: "203.0.113.0/25", Origin: "AS64501", MntBy: []string{"MAINT-X"}, Detail: "origin is AS64501, want AS64500"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EvaluateAssertions() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestNewAssertionRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		prefix, descr string
+		requireMntBy  []string
+	}{
+		{name: "bad prefix", prefix: "203.0.113.0", requireMntBy: []string{"MAINT-X"}},
+		{name: "bad descr", descr: "(unclosed"},
+		{name: "no requirements"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAssertion(tt.name, "", tt.prefix, "", tt.requireMntBy, "", tt.descr); err == nil {
+				t.Error("NewAssertion() should fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package audit checks registered objects against operational policy.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// DefaultRequiredRoles are the contact roles every maintainer and aut-num
+// must be covered by when no policy is configured.
+var DefaultRequiredRoles = []models.ContactRole{models.ContactRoleAbuse, models.ContactRoleTech}
+
+// Object types covered by contacts.
+const (
+	ObjectTypeMaintainer = "mntner"
+	ObjectTypeAutNum     = "aut-num"
+	ObjectTypeContact    = "contact"
+)
+
+// FindingKind classifies a coverage problem.
+type FindingKind string
+
+const (
+	// FindingMissingRole means no contact with a required role covers an object
+	FindingMissingRole FindingKind = "missing-role"
+
+	// FindingUnreachableEmail means a contact's email failed the reachability check
+	FindingUnreachableEmail FindingKind = "unreachable-email"
+)
+
+// Finding is one coverage problem.
+type Finding struct {
+	Kind       FindingKind        `json:"kind"`
+	ObjectType string             `json:"object_type"`
+	Object     string             `json:"object"`
+	Role       models.ContactRole `json:"role,omitempty"`
+	Detail     string             `json:"detail"`
+}
+
+// ObjectCoverage lists the contacts covering a maintainer or aut-num, by role.
+type ObjectCoverage struct {
+	Type     string                          `json:"type"`
+	Name     string                          `json:"name"`
+	Contacts map[models.ContactRole][]string `json:"contacts"`
+	Missing  []models.ContactRole            `json:"missing,omitempty"`
+}
+
+// CoverageReport is the result of a contact coverage audit.
+type CoverageReport struct {
+	RequiredRoles []models.ContactRole `json:"required_roles"`
+	Objects       []ObjectCoverage     `json:"objects"`
+	EmailsChecked int                  `json:"emails_checked"`
+	Findings      []Finding            `json:"findings"`
+}
+
+// EmailChecker reports whether mail to an address can be delivered.
+type EmailChecker func(ctx context.Context, email string) error
+
+// CoverageOptions configures a coverage audit.
+type CoverageOptions struct {
+	// RequiredRoles defaults to DefaultRequiredRoles
+	RequiredRoles []models.ContactRole
+
+	// CheckEmail, if set, is run once per distinct contact email
+	CheckEmail EmailChecker
+}
+
+// Coverage cross-references the maintainers and origin ASNs (aut-nums) of
+// routes with contacts. A contact covers the objects named in its mnt-by and
+// aut-num attributes; a contact with neither is account-wide and covers every
+// object.
+func Coverage(ctx context.Context, routes []models.RouteObject, contacts []models.Contact, opts CoverageOptions) *CoverageReport {
+	required := opts.RequiredRoles
+	if len(required) == 0 {
+		required = DefaultRequiredRoles
+	}
+
+	report := &CoverageReport{
+		RequiredRoles: required,
+		Objects:       make([]ObjectCoverage, 0),
+		Findings:      make([]Finding, 0),
+	}
+
+	maintainers := make(map[string]bool)
+	autNums := make(map[string]bool)
+	for _, route := range routes {
+		for _, mntner := range route.MntBy {
+			maintainers[strings.ToUpper(mntner)] = true
+		}
+		if route.Origin != "" {
+			autNums[strings.ToUpper(route.Origin)] = true
+		}
+	}
+
+	for _, objectType := range []string{ObjectTypeMaintainer, ObjectTypeAutNum} {
+		names := maintainers
+		if objectType == ObjectTypeAutNum {
+			names = autNums
+		}
+
+		for _, name := range sortedNames(names) {
+			coverage := ObjectCoverage{
+				Type:     objectType,
+				Name:     name,
+				Contacts: make(map[models.ContactRole][]string),
+			}
+			for _, contact := range contacts {
+				if covers(contact, objectType, name) {
+					coverage.Contacts[contact.Role] = append(coverage.Contacts[contact.Role], contact.ID)
+				}
+			}
+
+			for _, role := range required {
+				if len(coverage.Contacts[role]) > 0 {
+					continue
+				}
+				coverage.Missing = append(coverage.Missing, role)
+				report.Findings = append(report.Findings, Finding{
+					Kind:       FindingMissingRole,
+					ObjectType: objectType,
+					Object:     name,
+					Role:       role,
+					Detail:     fmt.Sprintf("no %s contact", role),
+				})
+			}
+			report.Objects = append(report.Objects, coverage)
+		}
+	}
+
+	if opts.CheckEmail != nil {
+		checked := make(map[string]error)
+		for _, contact := range contacts {
+			email := strings.ToLower(contact.Email)
+			err, seen := checked[email]
+			if !seen {
+				err = opts.CheckEmail(ctx, contact.Email)
+				checked[email] = err
+				report.EmailsChecked++
+			}
+			if err != nil {
+				report.Findings = append(report.Findings, Finding{
+					Kind:       FindingUnreachableEmail,
+					ObjectType: ObjectTypeContact,
+					Object:     contact.ID,
+					Role:       contact.Role,
+					Detail:     fmt.Sprintf("%s: %v", contact.Email, err),
+				})
+			}
+		}
+	}
+
+	return report
+}
+
+// covers reports whether a contact covers the named object.
+func covers(contact models.Contact, objectType, name string) bool {
+	mntners := contact.RawAttributes["mnt-by"]
+	autNums := contact.RawAttributes["aut-num"]
+	if len(mntners) == 0 && len(autNums) == 0 {
+		return true
+	}
+
+	values := mntners
+	if objectType == ObjectTypeAutNum {
+		values = autNums
+	}
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedNames returns the keys of a set in order.
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
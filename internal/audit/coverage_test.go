@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestCoverage(t *testing.T) {
+	routes := []models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}},
+		{Route: "198.51.100.0/24", Origin: "AS64501", MntBy: []string{"maint-b"}},
+	}
+	contacts := []models.Contact{
+		// Account-wide abuse contact
+		{ID: "C1", Email: "abuse@example.com", Role: models.ContactRoleAbuse},
+		// Tech contact for MAINT-A and AS64500 only
+		{ID: "C2", Email: "noc@example.com", Role: models.ContactRoleTech, RawAttributes: map[string][]string{
+			"mnt-by":  {"MAINT-A"},
+			"aut-num": {"AS64500"},
+		}},
+		{ID: "C3", Email: "NOC@example.com", Role: models.ContactRoleAdmin},
+		{ID: "C4", Email: "ops@bad.example", Role: models.ContactRoleTech, RawAttributes: map[string][]string{
+			"mnt-by": {"MAINT-C"},
+		}},
+	}
+
+	checked := 0
+	check := func(ctx context.Context, email string) error {
+		checked++
+		if email == "ops@bad.example" {
+			return errors.New("no MX")
+		}
+		return nil
+	}
+
+	report := Coverage(context.Background(), routes, contacts, CoverageOptions{CheckEmail: check})
+
+	if len(report.Objects) != 4 {
+		t.Fatalf("covered %d objects, want 4: %+v", len(report.Objects), report.Objects)
+	}
+	if got := report.Objects[0]; got.Name != "MAINT-A" || len(got.Missing) != 0 || !reflect.DeepEqual(got.Contacts[models.ContactRoleTech], []string{"C2"}) {
+		t.Errorf("MAINT-A coverage = %+v", got)
+	}
+
+	want := []Finding{
+		{Kind: FindingMissingRole, ObjectType: ObjectTypeMaintainer, Object: "MAINT-B", Role: models.ContactRoleTech, Detail: "no tech contact"},
+		{Kind: FindingMissingRole, ObjectType: ObjectTypeAutNum, Object: "AS64501", Role: models.ContactRoleTech, Detail: "no tech contact"},
+		{Kind: FindingUnreachableEmail, ObjectType: ObjectTypeContact, Object: "C4", Role: models.ContactRoleTech, Detail: "ops@bad.example: no MX"},
+	}
+	if !reflect.DeepEqual(report.Findings, want) {
+		t.Errorf("findings = %+v, want %+v", report.Findings, want)
+	}
+
+	// Addresses differing only in case are checked once
+	if checked != 3 || report.EmailsChecked != 3 {
+		t.Errorf("checked %d emails (reported %d), want 3", checked, report.EmailsChecked)
+	}
+}
+
+func TestCoverageRequiredRoles(t *testing.T) {
+	routes := []models.RouteObject{{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}}}
+	contacts := []models.Contact{{ID: "C1", Email: "abuse@example.com", Role: models.ContactRoleAbuse}}
+
+	report := Coverage(context.Background(), routes, contacts, CoverageOptions{
+		RequiredRoles: []models.ContactRole{models.ContactRoleAbuse},
+	})
+	if len(report.Findings) != 0 {
+		t.Errorf("findings = %+v, want none", report.Findings)
+	}
+	if report.EmailsChecked != 0 {
+		t.Errorf("checked %d emails without a checker", report.EmailsChecked)
+	}
+}
+
+func TestEmailDomain(t *testing.T) {
+	tests := []struct {
+		email   string
+		want    string
+		wantErr bool
+	}{
+		{"noc@Example.COM", "example.com", false},
+		{"a@b@example.com", "example.com", false},
+		{"noc@", "", true},
+		{"@example.com", "", true},
+		{"noc", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := emailDomain(tt.email)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("emailDomain(%q) = %q, %v; want %q, error %v", tt.email, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package audit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// PrefixPair maps an IPv4 prefix to the IPv6 prefixes that should be
+// registered alongside it.
+type PrefixPair struct {
+	IPv4 netip.Prefix
+	IPv6 []netip.Prefix
+}
+
+// DualStackOrigin counts the route and route6 objects of an origin.
+type DualStackOrigin struct {
+	Origin     string `json:"origin"`
+	IPv4Routes int    `json:"ipv4_routes"`
+	IPv6Routes int    `json:"ipv6_routes"`
+}
+
+// DualStackFinding is a place where IPv6 registrations lag IPv4.
+type DualStackFinding struct {
+	Origin string `json:"origin"`
+	IPv4   string `json:"ipv4,omitempty"` // The registered IPv4 route, with a mapping
+	IPv6   string `json:"ipv6,omitempty"` // The expected IPv6 route, with a mapping
+	Detail string `json:"detail"`
+}
+
+// DualStackReport is the result of a dual-stack audit.
+type DualStackReport struct {
+	Origins  []DualStackOrigin  `json:"origins"`
+	Findings []DualStackFinding `json:"findings"`
+}
+
+// ParseDualStackMapping reads a mapping of IPv4 prefixes to IPv6 prefixes,
+// one IPv4 prefix per line followed by its IPv6 prefixes, separated by
+// whitespace or commas. Blank lines and lines starting with # are skipped.
+func ParseDualStackMapping(r io.Reader) ([]PrefixPair, error) {
+	var pairs []PrefixPair
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: want an IPv4 prefix and at least one IPv6 prefix", lineNo)
+		}
+
+		v4, err := netip.ParsePrefix(fields[0])
+		if err != nil || !v4.Addr().Is4() {
+			return nil, fmt.Errorf("line %d: invalid IPv4 prefix %q", lineNo, fields[0])
+		}
+		pair := PrefixPair{IPv4: v4.Masked()}
+		for _, field := range fields[1:] {
+			v6, err := netip.ParsePrefix(field)
+			if err != nil || !v6.Addr().Is6() || v6.Addr().Is4In6() {
+				return nil, fmt.Errorf("line %d: invalid IPv6 prefix %q", lineNo, field)
+			}
+			pair.IPv6 = append(pair.IPv6, v6.Masked())
+		}
+		pairs = append(pairs, pair)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mapping: %w", err)
+	}
+
+	return pairs, nil
+}
+
+// DualStack checks that IPv6 registrations keep up with IPv4 ones. Without a
+// mapping, every origin with route objects needs at least one route6
+// object. With a mapping, every registered IPv4 prefix in it needs route6
+// objects for its IPv6 prefixes with the same origin.
+func DualStack(routes []models.RouteObject, mapping []PrefixPair) *DualStackReport {
+	report := &DualStackReport{
+		Origins:  make([]DualStackOrigin, 0),
+		Findings: make([]DualStackFinding, 0),
+	}
+
+	counts := make(map[string]*DualStackOrigin)
+	// registered holds the origins of each registered prefix
+	registered := make(map[netip.Prefix][]string)
+	for _, route := range routes {
+		origin := strings.ToUpper(route.Origin)
+		count, ok := counts[origin]
+		if !ok {
+			count = &DualStackOrigin{Origin: origin}
+			counts[origin] = count
+		}
+
+		prefix, err := netip.ParsePrefix(route.Route)
+		if err != nil {
+			continue
+		}
+		prefix = prefix.Masked()
+		if prefix.Addr().Is4() {
+			count.IPv4Routes++
+		} else {
+			count.IPv6Routes++
+		}
+		registered[prefix] = append(registered[prefix], origin)
+	}
+
+	origins := make([]string, 0, len(counts))
+	for origin := range counts {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+	for _, origin := range origins {
+		report.Origins = append(report.Origins, *counts[origin])
+	}
+
+	if len(mapping) == 0 {
+		for _, count := range report.Origins {
+			if count.IPv4Routes > 0 && count.IPv6Routes == 0 {
+				report.Findings = append(report.Findings, DualStackFinding{
+					Origin: count.Origin,
+					Detail: fmt.Sprintf("%d route objects, no route6", count.IPv4Routes),
+				})
+			}
+		}
+		return report
+	}
+
+	for _, pair := range mapping {
+		for _, origin := range registered[pair.IPv4] {
+			for _, v6 := range pair.IPv6 {
+				v6Origins := registered[v6]
+				switch {
+				case len(v6Origins) == 0:
+					report.Findings = append(report.Findings, DualStackFinding{
+						Origin: origin, IPv4: pair.IPv4.String(), IPv6: v6.String(),
+						Detail: "route6 not registered",
+					})
+				case !containsFold(v6Origins, origin):
+					report.Findings = append(report.Findings, DualStackFinding{
+						Origin: origin, IPv4: pair.IPv4.String(), IPv6: v6.String(),
+						Detail: fmt.Sprintf("route6 registered with origin %s", strings.Join(v6Origins, ", ")),
+					})
+				}
+			}
+		}
+	}
+
+	return report
+}
+
+// containsFold reports whether values includes value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
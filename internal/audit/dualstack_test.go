@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestDualStack(t *testing.T) {
+	routes := []models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500"},
+		{Route: "198.51.100.0/24", Origin: "AS64500"},
+		{Route: "2001:db8:1::/48", Origin: "as64500"},
+		{Route: "203.0.113.0/24", Origin: "AS64501"},
+		{Route: "2001:db8:3::/48", Origin: "AS64502"},
+	}
+
+	t.Run("ByOrigin", func(t *testing.T) {
+		report := DualStack(routes, nil)
+		wantOrigins := []DualStackOrigin{
+			{Origin: "AS64500", IPv4Routes: 2, IPv6Routes: 1},
+			{Origin: "AS64501", IPv4Routes: 1},
+			{Origin: "AS64502", IPv6Routes: 1},
+		}
+		if !reflect.DeepEqual(report.Origins, wantOrigins) {
+			t.Errorf("Origins = %+v, want %+v", report.Origins, wantOrigins)
+		}
+		wantFindings := []DualStackFinding{{Origin: "AS64501", Detail: "1 route objects, no route6"}}
+		if !reflect.DeepEqual(report.Findings, wantFindings) {
+			t.Errorf("Findings = %+v, want %+v", report.Findings, wantFindings)
+		}
+	})
+
+	t.Run("Mapping", func(t *testing.T) {
+		mapping, err := ParseDualStackMapping(strings.NewReader(`
+# IPv4 prefix, then its IPv6 prefixes
+192.0.2.0/24     2001:db8:1::/48
+198.51.100.0/24  2001:db8:2::/48, 2001:db8:3::/48
+192.0.2.128/25   2001:db8:4::/48
+`))
+		if err != nil {
+			t.Fatalf("ParseDualStackMapping() failed: %v", err)
+		}
+
+		got := DualStack(routes, mapping).Findings
+		want := []DualStackFinding{
+			{Origin: "AS64500", IPv4: "198.51.100.0/24", IPv6: "2001:db8:2::/48", Detail: "route6 not registered"},
+			{Origin: "AS64500", IPv4: "198.51.100.0/24", IPv6: "2001:db8:3::/48", Detail: "route6 registered with origin AS64502"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Findings =\n%+v\nwant\n%+v", got, want)
+		}
+	})
+
+	for _, invalid := range []string{
+		"192.0.2.0/24",
+		"2001:db8::/32 2001:db8:1::/48",
+		"192.0.2.0/24 198.51.100.0/24",
+	} {
+		if _, err := ParseDualStackMapping(strings.NewReader(invalid)); err == nil {
+			t.Errorf("ParseDualStackMapping(%q) succeeded, want an error", invalid)
+		}
+	}
+}
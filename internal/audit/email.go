@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MXChecker returns an EmailChecker that verifies the domain of an address
+// accepts mail: it has MX records, or an address record to fall back to as
+// an implicit MX (RFC 5321). Lookups are cached per domain.
+func MXChecker(resolver *net.Resolver) EmailChecker {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	var (
+		mu    sync.Mutex
+		cache = make(map[string]error)
+	)
+
+	return func(ctx context.Context, email string) error {
+		domain, err := emailDomain(email)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err, ok := cache[domain]; ok {
+			return err
+		}
+
+		_, err = mailHosts(ctx, resolver, domain)
+		cache[domain] = err
+		return err
+	}
+}
+
+// SMTPChecker returns an EmailChecker that asks the domain's mail servers
+// whether they accept the address, without sending a message. Servers that
+// cannot be reached or refuse to answer fall through to the next; a server
+// rejecting the recipient is reported as unreachable.
+func SMTPChecker(resolver *net.Resolver, heloName string, timeout time.Duration) EmailChecker {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return func(ctx context.Context, email string) error {
+		domain, err := emailDomain(email)
+		if err != nil {
+			return err
+		}
+
+		hosts, err := mailHosts(ctx, resolver, domain)
+		if err != nil {
+			return err
+		}
+
+		var lastErr error
+		for _, host := range hosts {
+			err := probeRecipient(ctx, host, heloName, email, timeout)
+			if err == nil {
+				return nil
+			}
+			var rejected *recipientRejectedError
+			if errors.As(err, &rejected) {
+				return err
+			}
+			lastErr = err
+		}
+		return fmt.Errorf("no mail server answered: %w", lastErr)
+	}
+}
+
+// recipientRejectedError is a mail server refusing a recipient.
+type recipientRejectedError struct {
+	host string
+	err  error
+}
+
+func (e *recipientRejectedError) Error() string {
+	return fmt.Sprintf("%s rejected recipient: %v", e.host, e.err)
+}
+
+// probeRecipient runs an SMTP conversation up to RCPT TO and quits.
+func probeRecipient(ctx context.Context, host, heloName, email string, timeout time.Duration) error {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, "25"))
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Hello(heloName); err != nil {
+		return err
+	}
+	if err := client.Mail(""); err != nil {
+		return err
+	}
+	if err := client.Rcpt(email); err != nil {
+		if strings.HasPrefix(err.Error(), "5") {
+			return &recipientRejectedError{host: host, err: err}
+		}
+		return err
+	}
+	client.Quit()
+	return nil
+}
+
+// mailHosts returns the mail servers for a domain in preference order.
+func mailHosts(ctx context.Context, resolver *net.Resolver, domain string) ([]string, error) {
+	records, err := resolver.LookupMX(ctx, domain)
+	if err == nil && len(records) > 0 {
+		hosts := make([]string, 0, len(records))
+		for _, record := range records {
+			// A null MX ("." per RFC 7505) means the domain accepts no mail
+			if record.Host == "." {
+				return nil, fmt.Errorf("domain %s does not accept mail (null MX)", domain)
+			}
+			hosts = append(hosts, strings.TrimSuffix(record.Host, "."))
+		}
+		return hosts, nil
+	}
+
+	if _, hostErr := resolver.LookupHost(ctx, domain); hostErr != nil {
+		return nil, fmt.Errorf("domain %s has no MX or address records", domain)
+	}
+	return []string{domain}, nil
+}
+
+// emailDomain returns the domain part of an address.
+func emailDomain(email string) (string, error) {
+	at := strings.LastIndex(email, "@")
+	if at < 1 || at == len(email)-1 {
+		return "", fmt.Errorf("invalid email address %q", email)
+	}
+	return strings.ToLower(email[at+1:]), nil
+}
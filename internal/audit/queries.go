@@ -0,0 +1,316 @@
+package audit
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Route object types a query reports.
+const (
+	ObjectTypeRoute  = "route"
+	ObjectTypeRoute6 = "route6"
+)
+
+// Query is a named audit over the account's routes and contacts, such as
+// "routes without a descr". Queries are built in or saved in the
+// configuration.
+type Query struct {
+	Name        string
+	Description string
+	Builtin     bool
+	run         func(routes []models.RouteObject, contacts []models.Contact) []QueryResult
+}
+
+// QueryResult is an object a query reports.
+type QueryResult struct {
+	Query      string `json:"query"`
+	ObjectType string `json:"object_type"`
+	Object     string `json:"object"`
+	Detail     string `json:"detail"`
+}
+
+// Run runs the query and returns the objects it reports.
+func (q *Query) Run(routes []models.RouteObject, contacts []models.Contact) []QueryResult {
+	results := q.run(routes, contacts)
+	for i := range results {
+		results[i].Query = q.Name
+	}
+	return results
+}
+
+// SavedQuery is the configured form of a query: the objects of one type
+// matching every condition given. Empty conditions are not checked.
+type SavedQuery struct {
+	Name        string
+	Description string
+	Object      string   // route or contact
+	Origin      string   // Routes originated by this ASN
+	Prefix      string   // Routes within this prefix
+	MntBy       string   // Objects with this maintainer
+	Descr       string   // Regular expression some descr line must match
+	Email       string   // Regular expression the contact's email must match
+	Role        string   // Contacts in this role
+	Missing     []string // Attributes the object lacks, e.g. descr, remarks
+}
+
+// freeMailDomains are consumer mail providers. Contacts at these domains
+// are usually personal addresses that stop working when people leave.
+var freeMailDomains = []string{
+	"163.com", "aol.com", "gmail.com", "gmx.com", "gmx.de", "gmx.net",
+	"googlemail.com", "hotmail.com", "icloud.com", "live.com", "mail.com",
+	"mail.ru", "me.com", "msn.com", "outlook.com", "proton.me",
+	"protonmail.com", "qq.com", "yahoo.com", "yandex.com", "yandex.ru",
+	"zoho.com",
+}
+
+// BuiltinQueries returns the queries shipped with the client, in order.
+func BuiltinQueries() []Query {
+	return []Query{
+		{
+			Name:        "routes-without-descr",
+			Description: "Routes with no descr attribute",
+			Builtin:     true,
+			run: func(routes []models.RouteObject, _ []models.Contact) []QueryResult {
+				var results []QueryResult
+				for _, route := range routes {
+					if len(nonEmpty(route.Descr)) == 0 {
+						results = append(results, routeResult(route, "no descr"))
+					}
+				}
+				return results
+			},
+		},
+		{
+			Name:        "single-maintainer",
+			Description: "Routes and contacts maintained by only one mntner",
+			Builtin:     true,
+			run: func(routes []models.RouteObject, contacts []models.Contact) []QueryResult {
+				var results []QueryResult
+				for _, route := range routes {
+					if mntners := nonEmpty(route.MntBy); len(mntners) == 1 {
+						results = append(results, routeResult(route, "only mnt-by "+mntners[0]))
+					}
+				}
+				for _, contact := range contacts {
+					if mntners := nonEmpty(contact.RawAttributes["mnt-by"]); len(mntners) == 1 {
+						results = append(results, contactResult(contact, "only mnt-by "+mntners[0]))
+					}
+				}
+				return results
+			},
+		},
+		{
+			Name:        "missing-route6",
+			Description: "Origins with route objects but no route6 objects",
+			Builtin:     true,
+			run: func(routes []models.RouteObject, _ []models.Contact) []QueryResult {
+				var results []QueryResult
+				for _, finding := range DualStack(routes, nil).Findings {
+					results = append(results, QueryResult{ObjectType: ObjectTypeAutNum, Object: finding.Origin, Detail: finding.Detail})
+				}
+				return results
+			},
+		},
+		{
+			Name:        "free-mail-contacts",
+			Description: "Contacts with email addresses at free mail providers",
+			Builtin:     true,
+			run: func(_ []models.RouteObject, contacts []models.Contact) []QueryResult {
+				var results []QueryResult
+				for _, contact := range contacts {
+					domain, err := emailDomain(contact.Email)
+					if err == nil && slices.Contains(freeMailDomains, strings.ToLower(domain)) {
+						results = append(results, contactResult(contact, "free mail address "+contact.Email))
+					}
+				}
+				return results
+			},
+		},
+	}
+}
+
+// NewSavedQuery builds a query from its configured form.
+func NewSavedQuery(saved SavedQuery) (Query, error) {
+	if saved.Name == "" {
+		return Query{}, fmt.Errorf("query name is required")
+	}
+
+	var (
+		prefix netip.Prefix
+		descr  *regexp.Regexp
+		email  *regexp.Regexp
+		err    error
+	)
+	if saved.Prefix != "" {
+		if prefix, err = netip.ParsePrefix(saved.Prefix); err != nil {
+			return Query{}, fmt.Errorf("query %s: invalid prefix %q", saved.Name, saved.Prefix)
+		}
+		prefix = prefix.Masked()
+	}
+	if saved.Descr != "" {
+		if descr, err = regexp.Compile(saved.Descr); err != nil {
+			return Query{}, fmt.Errorf("query %s: invalid descr pattern: %w", saved.Name, err)
+		}
+	}
+	if saved.Email != "" {
+		if email, err = regexp.Compile(saved.Email); err != nil {
+			return Query{}, fmt.Errorf("query %s: invalid email pattern: %w", saved.Name, err)
+		}
+	}
+
+	query := Query{Name: saved.Name, Description: saved.Description}
+	switch saved.Object {
+	case ObjectTypeRoute:
+		if saved.Email != "" || saved.Role != "" {
+			return Query{}, fmt.Errorf("query %s: email and role apply to contacts", saved.Name)
+		}
+		assertion := Assertion{MatchOrigin: saved.Origin, MatchPrefix: prefix, MatchMntBy: saved.MntBy}
+		query.run = func(routes []models.RouteObject, _ []models.Contact) []QueryResult {
+			var results []QueryResult
+			for _, route := range routes {
+				if !assertion.Matches(&route) {
+					continue
+				}
+				if descr != nil && !slices.ContainsFunc(route.Descr, descr.MatchString) {
+					continue
+				}
+				if missing, ok := lacks(route.RawAttributes, routeAttributes(route), saved.Missing); ok {
+					results = append(results, routeResult(route, matchDetail(missing)))
+				}
+			}
+			return results
+		}
+	case ObjectTypeContact:
+		if saved.Origin != "" || saved.Prefix != "" || saved.Descr != "" {
+			return Query{}, fmt.Errorf("query %s: origin, prefix, and descr apply to routes", saved.Name)
+		}
+		query.run = func(_ []models.RouteObject, contacts []models.Contact) []QueryResult {
+			var results []QueryResult
+			for _, contact := range contacts {
+				if saved.MntBy != "" && !hasMaintainer(contact.RawAttributes["mnt-by"], saved.MntBy) {
+					continue
+				}
+				if saved.Role != "" && !strings.EqualFold(string(contact.Role), saved.Role) {
+					continue
+				}
+				if email != nil && !email.MatchString(contact.Email) {
+					continue
+				}
+				if missing, ok := lacks(contact.RawAttributes, contactAttributes(contact), saved.Missing); ok {
+					results = append(results, contactResult(contact, matchDetail(missing)))
+				}
+			}
+			return results
+		}
+	default:
+		return Query{}, fmt.Errorf("query %s: unsupported object %q (want route or contact)", saved.Name, saved.Object)
+	}
+
+	return query, nil
+}
+
+// Queries returns the built-in queries followed by the saved ones. A saved
+// query may not reuse the name of another query.
+func Queries(saved []SavedQuery) ([]Query, error) {
+	queries := BuiltinQueries()
+	for _, s := range saved {
+		query, err := NewSavedQuery(s)
+		if err != nil {
+			return nil, err
+		}
+		if FindQuery(queries, query.Name) != nil {
+			return nil, fmt.Errorf("query %s is already defined", query.Name)
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
+
+// FindQuery returns the query with the given name, or nil.
+func FindQuery(queries []Query, name string) *Query {
+	for i := range queries {
+		if queries[i].Name == name {
+			return &queries[i]
+		}
+	}
+	return nil
+}
+
+// routeAttributes returns the values of a route's modelled attributes.
+func routeAttributes(route models.RouteObject) map[string][]string {
+	return map[string][]string{
+		"descr":     route.Descr,
+		"mnt-by":    route.MntBy,
+		"remarks":   route.Remarks,
+		"member-of": route.MemberOf,
+		"holes":     route.Holes,
+	}
+}
+
+// contactAttributes returns the values of a contact's modelled attributes.
+func contactAttributes(contact models.Contact) map[string][]string {
+	return map[string][]string{
+		"phone":        {contact.Phone},
+		"organization": {contact.Organization},
+		"address":      contact.Address,
+	}
+}
+
+// lacks reports whether an object has none of the attributes in missing,
+// looking in its modelled attributes first and its raw attributes
+// otherwise, and returns the attributes it lacks.
+func lacks(raw, modelled map[string][]string, missing []string) ([]string, bool) {
+	for _, attribute := range missing {
+		key := strings.ToLower(attribute)
+		values, ok := modelled[key]
+		if !ok {
+			values = raw[key]
+		}
+		if len(nonEmpty(values)) > 0 {
+			return nil, false
+		}
+	}
+	return missing, true
+}
+
+// matchDetail describes why a saved query reported an object.
+func matchDetail(missing []string) string {
+	if len(missing) == 0 {
+		return "matches"
+	}
+	return "no " + strings.Join(missing, ", ")
+}
+
+// routeResult returns a result for a route.
+func routeResult(route models.RouteObject, detail string) QueryResult {
+	return QueryResult{ObjectType: routeObjectType(route), Object: route.ID(), Detail: detail}
+}
+
+// routeObjectType returns route6 for an IPv6 route and route otherwise.
+func routeObjectType(route models.RouteObject) string {
+	if strings.Contains(route.Route, ":") {
+		return ObjectTypeRoute6
+	}
+	return ObjectTypeRoute
+}
+
+// contactResult returns a result for a contact.
+func contactResult(contact models.Contact, detail string) QueryResult {
+	return QueryResult{ObjectType: ObjectTypeContact, Object: contact.ID, Detail: detail}
+}
+
+// nonEmpty returns the values that are not blank.
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			kept = append(kept, strings.TrimSpace(value))
+		}
+	}
+	return kept
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestBuiltinQueries(t *testing.T) {
+	routes := []models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-X", "MAINT-Y"}, Descr: []string{"ACME Corp"}},
+		{Route: "2001:db8::/32", Origin: "AS64500", MntBy: []string{"MAINT-X", "MAINT-Y"}, Descr: []string{"ACME Corp"}},
+		{Route: "198.51.100.0/24", Origin: "AS64501", MntBy: []string{"MAINT-X"}, Descr: []string{" "}},
+	}
+	contacts := []models.Contact{
+		{ID: "C1", Email: "noc@example.com", RawAttributes: map[string][]string{"mnt-by": {"MAINT-X"}}},
+		{ID: "C2", Email: "someone@Gmail.com"},
+	}
+
+	tests := []struct {
+		query string
+		want  []QueryResult
+	}{
+		{
+			query: "routes-without-descr",
+			want:  []QueryResult{{Query: "routes-without-descr", ObjectType: "route", Object: "198.51.100.0/24-AS64501", Detail: "no descr"}},
+		},
+		{
+			query: "single-maintainer",
+			want: []QueryResult{
+				{Query: "single-maintainer", ObjectType: "route", Object: "198.51.100.0/24-AS64501", Detail: "only mnt-by MAINT-X"},
+				{Query: "single-maintainer", ObjectType: "contact", Object: "C1", Detail: "only mnt-by MAINT-X"},
+			},
+		},
+		{
+			query: "missing-route6",
+			want:  []QueryResult{{Query: "missing-route6", ObjectType: "aut-num", Object: "AS64501", Detail: "1 route objects, no route6"}},
+		},
+		{
+			query: "free-mail-contacts",
+			want:  []QueryResult{{Query: "free-mail-contacts", ObjectType: "contact", Object: "C2", Detail: "free mail address someone@Gmail.com"}},
+		},
+	}
+
+	queries := BuiltinQueries()
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query := FindQuery(queries, tt.query)
+			if query == nil {
+				t.Fatalf("FindQuery(%q) = nil", tt.query)
+			}
+			if got := query.Run(routes, contacts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSavedQueries(t *testing.T) {
+	routes := []models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64499", Remarks: []string{"legacy"}},
+		{Route: "198.51.100.0/24", Origin: "AS64499", RawAttributes: map[string][]string{"geofeed": {"https://example.com/geofeed.csv"}}},
+		{Route: "203.0.113.0/24", Origin: "AS64500"},
+	}
+	contacts := []models.Contact{
+		{ID: "C1", Email: "noc@old.example", Role: models.ContactRoleTech},
+		{ID: "C2", Email: "abuse@old.example", Role: models.ContactRoleAbuse},
+	}
+
+	queries, err := Queries([]SavedQuery{
+		{Name: "legacy-origin", Object: "route", Origin: "AS64499", Missing: []string{"remarks"}},
+		{Name: "no-geofeed", Object: "route", Missing: []string{"geofeed"}},
+		{Name: "old-domain", Object: "contact", Email: `@old\.example$`, Role: "abuse"},
+	})
+	if err != nil {
+		t.Fatalf("Queries() failed: %v", err)
+	}
+
+	run := func(name string) []string {
+		var objects []string
+		for _, result := range FindQuery(queries, name).Run(routes, contacts) {
+			objects = append(objects, result.Object)
+		}
+		return objects
+	}
+	if got, want := run("legacy-origin"), []string{"198.51.100.0/24-AS64499"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("legacy-origin = %v, want %v", got, want)
+	}
+	if got, want := run("no-geofeed"), []string{"192.0.2.0/24-AS64499", "203.0.113.0/24-AS64500"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("no-geofeed = %v, want %v", got, want)
+	}
+	if got, want := run("old-domain"), []string{"C2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("old-domain = %v, want %v", got, want)
+	}
+
+	for _, invalid := range []SavedQuery{
+		{Name: "routes-without-descr", Object: "route"},
+		{Name: "bad-object", Object: "aut-num"},
+		{Name: "bad-field", Object: "route", Email: "x"},
+		{Name: "bad-pattern", Object: "contact", Email: "("},
+	} {
+		if _, err := Queries([]SavedQuery{invalid}); err == nil {
+			t.Errorf("Queries(%+v) succeeded, want an error", invalid)
+		}
+	}
+}
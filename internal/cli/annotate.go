@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// annotateFlags holds the flags shared by the annotate commands.
+type annotateFlags struct {
+	labels   []string
+	unlabels []string
+	clear    bool
+}
+
+// register adds the annotate flags to a command.
+func (f *annotateFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&f.labels, "label", "l", nil, "Add label(s)")
+	cmd.Flags().StringSliceVar(&f.unlabels, "unlabel", nil, "Remove label(s)")
+	cmd.Flags().BoolVar(&f.clear, "clear", false, "Remove the note and all labels")
+}
+
+// newRouteAnnotateCmd creates the route annotate command.
+func newRouteAnnotateCmd(logger *logrus.Logger) *cobra.Command {
+	var flags annotateFlags
+
+	cmd := &cobra.Command{
+		Use:   "annotate <prefix> <asn> [note] | annotate <urn> [note]",
+		Short: "Attach a local note or labels to a route",
+		Long: `Attach a local note or labels to a route. Annotations are stored with
+your snapshots, never sent to RADb, and shown in list, show, diff, and
+history output. Without a note or flags, the current annotation is printed.`,
+		Example: `  radb-client route annotate 192.0.2.0/24 AS64500 "legacy, do not delete"
+  radb-client route annotate 192.0.2.0/24 AS64500 --label legacy --label customer-x
+  radb-client route annotate 192.0.2.0/24 AS64500 --clear
+  radb-client route annotate radb:route:192.0.2.0/24:AS64500 --label legacy`,
+		Args: cobra.RangeArgs(1, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefix, asn, rest, err := routeArgs(args)
+			if err != nil {
+				return err
+			}
+			if len(rest) > 1 {
+				return fmt.Errorf("unexpected argument %q", rest[1])
+			}
+
+			route := models.RouteObject{Route: prefix, Origin: asn}
+			return runAnnotate(cmd.Context(), logger, "route", route.ID(), rest, flags)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
+
+// newContactAnnotateCmd creates the contact annotate command.
+func newContactAnnotateCmd(logger *logrus.Logger) *cobra.Command {
+	var flags annotateFlags
+
+	cmd := &cobra.Command{
+		Use:   "annotate <id|urn> [note]",
+		Short: "Attach a local note or labels to a contact",
+		Long: `Attach a local note or labels to a contact. Annotations are stored with
+your snapshots, never sent to RADb, and shown in list, show, diff, and
+history output. Without a note or flags, the current annotation is printed.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := contactArg(args[0])
+			if err != nil {
+				return err
+			}
+			return runAnnotate(cmd.Context(), logger, "contact", id, args[1:], flags)
+		},
+	}
+
+	flags.register(cmd)
+	return cmd
+}
+
+// runAnnotate applies or prints an object's annotation.
+func runAnnotate(cmdCtx context.Context, logger *logrus.Logger, objectType, objectID string, noteArgs []string, flags annotateFlags) error {
+	store := state.NewAnnotationStore(ctx.Config.StateDir(), logger)
+
+	// Print the current annotation when nothing would change
+	if len(noteArgs) == 0 && len(flags.labels) == 0 && len(flags.unlabels) == 0 && !flags.clear {
+		annotation, err := store.Get(cmdCtx, objectType, objectID)
+		if err != nil {
+			return fmt.Errorf("failed to load annotation: %w", err)
+		}
+		if annotation == nil {
+			fmt.Printf("No annotation for %s %s\n", objectType, objectID)
+			return nil
+		}
+		printAnnotation(annotation)
+		return nil
+	}
+
+	annotation, err := store.Update(cmdCtx, objectType, objectID, func(a *models.Annotation) {
+		if flags.clear {
+			a.Note = ""
+			a.Labels = nil
+		}
+		if len(noteArgs) > 0 {
+			a.Note = noteArgs[0]
+		}
+		a.AddLabels(flags.labels...)
+		a.RemoveLabels(flags.unlabels...)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save annotation: %w", err)
+	}
+
+	if annotation.IsEmpty() {
+		fmt.Printf("Removed annotation for %s %s\n", objectType, objectID)
+		return nil
+	}
+
+	fmt.Printf("Annotated %s %s\n", objectType, objectID)
+	printAnnotation(annotation)
+	return nil
+}
+
+// printAnnotation prints the note and labels of an annotation.
+func printAnnotation(annotation *models.Annotation) {
+	if annotation.Note != "" {
+		fmt.Printf("Note: %s\n", annotation.Note)
+	}
+	if len(annotation.Labels) > 0 {
+		fmt.Printf("Labels: %s\n", strings.Join(annotation.Labels, ", "))
+	}
+}
+
+// loadAnnotations returns all local annotations, or nil if they cannot be read.
+// Annotations are supplementary, so failures are logged rather than returned.
+func loadAnnotations(cmdCtx context.Context, logger *logrus.Logger) models.Annotations {
+	annotations, err := state.NewAnnotationStore(ctx.Config.StateDir(), logger).All(cmdCtx)
+	if err != nil {
+		logger.Warnf("Failed to load annotations: %v", err)
+		return nil
+	}
+	return annotations
+}
@@ -0,0 +1,382 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// diffChange is one route write needed to move from one snapshot to another.
+type diffChange struct {
+	Operation models.BulkOperation
+	Before    *models.RouteObject // nil for creates
+	After     *models.RouteObject // nil for deletes
+}
+
+// Route returns the route the change writes.
+func (c diffChange) Route() *models.RouteObject {
+	if c.After != nil {
+		return c.After
+	}
+	return c.Before
+}
+
+// planDiffChanges converts the route items of a diff into writes: creates,
+// then updates, then deletes, each sorted by route ID.
+func planDiffChanges(diff *models.DiffResult) []diffChange {
+	var creates, updates, deletes []diffChange
+	for _, item := range diff.Added {
+		if route, ok := item.(*models.RouteObject); ok {
+			creates = append(creates, diffChange{Operation: models.BulkCreateRoutes, After: route})
+		}
+	}
+	for _, item := range diff.Modified {
+		before, okBefore := item.Before.(*models.RouteObject)
+		after, okAfter := item.After.(*models.RouteObject)
+		if okBefore && okAfter {
+			updates = append(updates, diffChange{Operation: models.BulkUpdateRoutes, Before: before, After: after})
+		}
+	}
+	for _, item := range diff.Removed {
+		if route, ok := item.(*models.RouteObject); ok {
+			deletes = append(deletes, diffChange{Operation: models.BulkDeleteRoutes, Before: route})
+		}
+	}
+
+	changes := make([]diffChange, 0, len(creates)+len(updates)+len(deletes))
+	for _, group := range [][]diffChange{creates, updates, deletes} {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Route().ID() < group[j].Route().ID()
+		})
+		changes = append(changes, group...)
+	}
+	return changes
+}
+
+// printDiffChange prints a change as RPSL lines marked with - and +.
+func printDiffChange(change diffChange) {
+	fmt.Printf("%s %s\n", change.Operation, change.Route().ID())
+	writeRouteDiff(os.Stdout, change.Before, change.After)
+}
+
+// writeRouteDiff writes the RPSL lines that differ between two versions of a
+// route, marked with - and +. When either version is nil the other is
+// written in full.
+func writeRouteDiff(w io.Writer, before, after *models.RouteObject) {
+	var beforeLines, afterLines []string
+	if before != nil {
+		beforeLines = strings.Split(strings.TrimRight(before.ToRPSL(), "\n"), "\n")
+	}
+	if after != nil {
+		afterLines = strings.Split(strings.TrimRight(after.ToRPSL(), "\n"), "\n")
+	}
+
+	for _, line := range beforeLines {
+		if !containsLine(afterLines, line) {
+			fmt.Fprintf(w, "  - %s\n", line)
+		} else if before == nil || after == nil {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	}
+	for _, line := range afterLines {
+		if !containsLine(beforeLines, line) {
+			fmt.Fprintf(w, "  + %s\n", line)
+		}
+	}
+}
+
+// containsLine reports whether lines contains line.
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewAnswer is an operator's decision on a change during interactive review.
+type reviewAnswer int
+
+const (
+	reviewAccept reviewAnswer = iota
+	reviewSkip
+	reviewEdit
+	reviewAcceptRest
+	reviewSkipRest
+	reviewQuit
+)
+
+const reviewHelp = `y - apply this change
+n - skip this change
+e - edit this change before applying it
+a - apply this change and all remaining changes
+d - skip this change and all remaining changes
+q - stop reviewing; apply only the changes accepted so far
+? - show this help
+`
+
+// promptReview asks the operator what to do with a change.
+func promptReview(reader *bufio.Reader, index, total int, change diffChange) (reviewAnswer, error) {
+	choices := "y,n,e,a,d,q,?"
+	if change.Operation == models.BulkDeleteRoutes {
+		choices = "y,n,a,d,q,?"
+	}
+
+	for {
+		fmt.Printf("(%d/%d) Apply this %s [%s]? ", index, total, change.Operation, choices)
+		answer, err := reader.ReadString('\n')
+		if err != nil && strings.TrimSpace(answer) == "" {
+			if err == io.EOF {
+				return reviewQuit, nil
+			}
+			return reviewQuit, fmt.Errorf("failed to read answer: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y":
+			return reviewAccept, nil
+		case "n":
+			return reviewSkip, nil
+		case "e":
+			if change.Operation != models.BulkDeleteRoutes {
+				return reviewEdit, nil
+			}
+		case "a":
+			return reviewAcceptRest, nil
+		case "d":
+			return reviewSkipRest, nil
+		case "q":
+			return reviewQuit, nil
+		}
+		fmt.Print(reviewHelp)
+	}
+}
+
+// editRoute opens route as JSON in the user's editor and returns the edited
+// route. The edit may not change the route's prefix or origin.
+func editRoute(route *models.RouteObject) (*models.RouteObject, error) {
+	data, err := json.MarshalIndent(route, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal route: %w", err)
+	}
+
+	file, err := os.CreateTemp("", "radb-route-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create edit file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write edit file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write edit file: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s failed: %w", args[0], err)
+	}
+
+	data, err = os.ReadFile(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edit file: %w", err)
+	}
+
+	var edited models.RouteObject
+	if err := json.Unmarshal(data, &edited); err != nil {
+		return nil, fmt.Errorf("failed to parse edited route: %w", err)
+	}
+	if edited.ID() != route.ID() {
+		return nil, fmt.Errorf("edited route is %s, not %s; prefix and origin cannot be changed", edited.ID(), route.ID())
+	}
+	if err := edited.Validate(); err != nil {
+		return nil, fmt.Errorf("edited route is invalid: %w", err)
+	}
+	return &edited, nil
+}
+
+// reviewDiffChanges steps through changes interactively, reading answers
+// from in, and returns those the operator accepted, with any edits applied.
+func reviewDiffChanges(in io.Reader, changes []diffChange) ([]diffChange, error) {
+	reader := bufio.NewReader(in)
+	accepted := make([]diffChange, 0, len(changes))
+
+	for i := 0; i < len(changes); i++ {
+		change := changes[i]
+		fmt.Println()
+		printDiffChange(change)
+
+		answer, err := promptReview(reader, i+1, len(changes), change)
+		if err != nil {
+			return nil, err
+		}
+
+		switch answer {
+		case reviewAccept:
+			accepted = append(accepted, change)
+		case reviewSkip:
+		case reviewEdit:
+			edited, err := editRoute(change.After)
+			if err != nil {
+				fmt.Printf("Edit discarded: %v\n", err)
+			} else {
+				change.After = edited
+				changes[i] = change
+			}
+			// Review the change again with the edit applied
+			i--
+		case reviewAcceptRest:
+			return append(accepted, changes[i:]...), nil
+		case reviewSkipRest, reviewQuit:
+			return accepted, nil
+		}
+	}
+	return accepted, nil
+}
+
+// submitJournals runs each non-empty journal as its own bulk job, in order,
+// and reports the results. It stops at the first job that cannot run; jobs
+// with failed items are reported and the first such failure returned.
+func submitJournals(cmdCtx context.Context, logger *logrus.Logger, journals []*models.BulkJournal, workers int) error {
+	var failed error
+	for _, journal := range journals {
+		if len(journal.Items) == 0 {
+			continue
+		}
+
+		result, err := startBulk(cmdCtx, logger, journal, workers)
+		if err != nil {
+			return fmt.Errorf("failed to apply %s: %w", journal.Operation, err)
+		}
+		if err := reportBulkResult(bulkVerb(journal.Operation), bulkObjects(journal.Operation), result); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// newRouteApplyDiffCmd creates the route apply-diff command.
+func newRouteApplyDiffCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		interactive bool
+		dryRun      bool
+		confirm     bool
+		workers     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply-diff <from-snapshot> <to-snapshot>",
+		Short: "Apply the route changes between two snapshots",
+		Long: `Apply the route changes between two snapshots to the registry.
+
+Routes added in the second snapshot are created, routes removed are deleted,
+and modified routes are updated to their state in the second snapshot.
+
+With --interactive, each change is shown in turn, similar to git add -p, and
+can be applied, skipped, or edited in $EDITOR before anything is submitted.
+Otherwise all changes are shown and --confirm is required to apply them.`,
+		Example: `  # Review the changes between two snapshots one at a time
+  radb-client route apply-diff route-1704110400000000000 route-1704715200000000000 --interactive
+
+  # Show the changes without applying them
+  radb-client route apply-diff route-1704110400000000000 route-1704715200000000000 --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			from, err := ctx.StateMgr.LoadSnapshot(cmdCtx, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load snapshot %s: %w", args[0], err)
+			}
+			to, err := ctx.StateMgr.LoadSnapshot(cmdCtx, args[1])
+			if err != nil {
+				return fmt.Errorf("failed to load snapshot %s: %w", args[1], err)
+			}
+
+			if from.Scope() != to.Scope() {
+				fmt.Fprintf(os.Stderr, "Warning: snapshots have different scopes (%s vs %s); routes outside the narrower scope will be deleted or created\n",
+					scopeLabel(from), scopeLabel(to))
+			}
+
+			diff, err := state.ComputeDiff(cmdCtx, from, to)
+			if err != nil {
+				return fmt.Errorf("failed to compute diff: %w", err)
+			}
+
+			changes := planDiffChanges(diff)
+			if len(changes) == 0 {
+				fmt.Println("No route changes between the snapshots")
+				return nil
+			}
+
+			if interactive && !dryRun {
+				changes, err = reviewDiffChanges(cmd.InOrStdin(), changes)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("\n%d changes accepted\n", len(changes))
+				if len(changes) == 0 {
+					return nil
+				}
+			} else {
+				for _, change := range changes {
+					printDiffChange(change)
+				}
+				fmt.Printf("\n%d route changes\n", len(changes))
+
+				if dryRun {
+					return nil
+				}
+				if !confirm {
+					return fmt.Errorf("re-run with --confirm or --interactive to apply these changes")
+				}
+			}
+
+			// Submit each operation as its own bulk job
+			var journals []*models.BulkJournal
+			for _, operation := range []models.BulkOperation{models.BulkCreateRoutes, models.BulkUpdateRoutes, models.BulkDeleteRoutes} {
+				journal := models.NewBulkJournal(operation)
+				for _, change := range changes {
+					if change.Operation == operation {
+						journal.AddRoute(change.Route())
+					}
+				}
+				journals = append(journals, journal)
+			}
+
+			return submitJournals(cmdCtx, logger, journals, workers)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Review each change before applying it")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without applying them")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Apply all changes without review")
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel workers (default from performance.max_concurrent_requests)")
+
+	return cmd
+}
@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+)
+
+// testDiffSnapshots returns two route snapshots: the second modifies
+// 198.51.100.0/24, removes 203.0.113.0/24, and adds two routes.
+func testDiffSnapshots() (*models.Snapshot, *models.Snapshot) {
+	from := models.NewSnapshot(models.SnapshotTypeRoute, "from")
+	from.Routes = models.NewRouteList([]models.RouteObject{
+		testRoute("192.0.2.0/24", "AS64500", "unchanged"),
+		testRoute("198.51.100.0/24", "AS64500", "before"),
+		testRoute("203.0.113.0/24", "AS64500", "removed"),
+	})
+
+	to := models.NewSnapshot(models.SnapshotTypeRoute, "to")
+	to.ID = from.ID + "-to"
+	to.Routes = models.NewRouteList([]models.RouteObject{
+		testRoute("192.0.2.0/24", "AS64500", "unchanged"),
+		testRoute("198.51.100.0/24", "AS64500", "after"),
+		testRoute("192.0.2.128/25", "AS64501", "added"),
+		testRoute("192.0.2.128/25", "AS64500", "added"),
+	})
+	return from, to
+}
+
+// testDiffChanges returns the planned changes between the test snapshots.
+func testDiffChanges(t *testing.T) []diffChange {
+	t.Helper()
+
+	from, to := testDiffSnapshots()
+	diff, err := state.ComputeDiff(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("ComputeDiff() failed: %v", err)
+	}
+	return planDiffChanges(diff)
+}
+
+// describeChanges lists changes as "operation route-id".
+func describeChanges(changes []diffChange) []string {
+	var described []string
+	for _, change := range changes {
+		described = append(described, fmt.Sprintf("%s %s", change.Operation, change.Route().ID()))
+	}
+	return described
+}
+
+func TestPlanDiffChanges(t *testing.T) {
+	changes := testDiffChanges(t)
+
+	want := []string{
+		fmt.Sprintf("%s 192.0.2.128/25-AS64500", models.BulkCreateRoutes),
+		fmt.Sprintf("%s 192.0.2.128/25-AS64501", models.BulkCreateRoutes),
+		fmt.Sprintf("%s 198.51.100.0/24-AS64500", models.BulkUpdateRoutes),
+		fmt.Sprintf("%s 203.0.113.0/24-AS64500", models.BulkDeleteRoutes),
+	}
+	if got := describeChanges(changes); !reflect.DeepEqual(got, want) {
+		t.Fatalf("planned changes = %v, want %v", got, want)
+	}
+
+	update := changes[2]
+	if update.Before.Descr[0] != "before" || update.After.Descr[0] != "after" {
+		t.Errorf("update = %+v -> %+v, want before -> after", update.Before, update.After)
+	}
+	if deletion := changes[3]; deletion.After != nil || deletion.Route().Descr[0] != "removed" {
+		t.Errorf("delete = %+v, want the removed route with no after state", deletion)
+	}
+}
+
+func TestReviewDiffChanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []int // Indexes of the accepted changes
+	}{
+		{"accept and skip", "y\nn\ny\nn\n", []int{0, 2}},
+		{"accept the rest", "n\na\n", []int{1, 2, 3}},
+		{"skip the rest", "y\nd\n", []int{0}},
+		{"quit", "y\ny\nq\n", []int{0, 1}},
+		{"end of input quits", "y\n", []int{0}},
+		{"unknown answer asks again", "maybe\ny\nn\nn\nn\n", []int{0}},
+		{"no edit for deletes", "n\nn\nn\ne\ny\n", []int{3}},
+		{"upper case and spaces", " Y \nN\nY\nN\n", []int{0, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := testDiffChanges(t)
+			accepted, err := reviewDiffChanges(strings.NewReader(tt.input), changes)
+			if err != nil {
+				t.Fatalf("reviewDiffChanges() failed: %v", err)
+			}
+
+			var want []diffChange
+			for _, index := range tt.want {
+				want = append(want, changes[index])
+			}
+			if got, wantDescribed := describeChanges(accepted), describeChanges(want); !reflect.DeepEqual(got, wantDescribed) {
+				t.Errorf("accepted = %v, want %v", got, wantDescribed)
+			}
+		})
+	}
+}
+
+// runApplyDiff runs route apply-diff between the test snapshots with stdin
+// holding input.
+func runApplyDiff(t *testing.T, client *api.MemoryClient, input string, args ...string) (*recordingClient, error) {
+	t.Helper()
+
+	from, to := testDiffSnapshots()
+	client.LoadSnapshot(from)
+	for _, snapshot := range []*models.Snapshot{from, to} {
+		if err := ctx.StateMgr.SaveSnapshot(context.Background(), snapshot); err != nil {
+			t.Fatalf("SaveSnapshot() failed: %v", err)
+		}
+	}
+
+	recorder := &recordingClient{Client: client}
+	ctx.APIClient = recorder
+
+	cmd := newRouteApplyDiffCmd(ctx.Logger)
+	cmd.SetArgs(append([]string{from.ID, to.ID}, args...))
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetErr(&strings.Builder{})
+	cmd.SilenceUsage = true
+	return recorder, cmd.Execute()
+}
+
+func TestApplyDiffInteractive(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		writes []string
+	}{
+		{"accept, skip, quit", "y\nn\nq\n", []string{"create 192.0.2.128/25-AS64500"}},
+		{"accept all", "a\n", []string{
+			"create 192.0.2.128/25-AS64500",
+			"create 192.0.2.128/25-AS64501",
+			"update 198.51.100.0/24-AS64500",
+			"delete 203.0.113.0/24-AS64500",
+		}},
+		{"skip the update and delete", "y\ny\nn\nn\n", []string{
+			"create 192.0.2.128/25-AS64500",
+			"create 192.0.2.128/25-AS64501",
+		}},
+		{"quit at once", "q\n", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setTestContext(t)
+			recorder, err := runApplyDiff(t, client, tt.input, "--interactive")
+			if err != nil {
+				t.Fatalf("apply-diff --interactive failed: %v", err)
+			}
+
+			writes := recorder.Writes()
+			if len(writes) != len(tt.writes) {
+				t.Fatalf("writes = %v, want %v", writes, tt.writes)
+			}
+			for _, write := range tt.writes {
+				if !containsLine(writes, write) {
+					t.Errorf("writes = %v, missing %s", writes, write)
+				}
+			}
+
+			routes, err := client.ListRoutes(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("ListRoutes() failed: %v", err)
+			}
+			if want := 3 + countPrefixed(tt.writes, "create") - countPrefixed(tt.writes, "delete"); routes.Count != want {
+				t.Errorf("registry holds %d routes, want %d", routes.Count, want)
+			}
+		})
+	}
+}
+
+func TestApplyDiffRequiresConfirm(t *testing.T) {
+	client, _ := setTestContext(t)
+
+	recorder, err := runApplyDiff(t, client, "")
+	if err == nil || !strings.Contains(err.Error(), "--confirm") {
+		t.Fatalf("apply-diff without --confirm = %v, want a refusal", err)
+	}
+	if writes := recorder.Writes(); len(writes) != 0 {
+		t.Errorf("refused apply-diff wrote %v", writes)
+	}
+}
+
+func TestApplyDiffDryRunIgnoresInteractive(t *testing.T) {
+	client, _ := setTestContext(t)
+
+	recorder, err := runApplyDiff(t, client, "y\ny\ny\ny\n", "--interactive", "--dry-run")
+	if err != nil {
+		t.Fatalf("apply-diff --dry-run failed: %v", err)
+	}
+	if writes := recorder.Writes(); len(writes) != 0 {
+		t.Errorf("dry run wrote %v", writes)
+	}
+}
+
+// countPrefixed counts the lines starting with prefix.
+func countPrefixed(lines []string, prefix string) int {
+	count := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			count++
+		}
+	}
+	return count
+}
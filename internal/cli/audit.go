@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewAuditCmd creates the audit command and its subcommands.
+func NewAuditCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Run audit queries over routes and contacts",
+		Long: `Run named audit queries over the account's routes and contacts.
+
+Built-in queries cover common hygiene checks. More queries can be saved
+under audit.queries in the configuration; a saved query reports the routes
+or contacts matching all of its conditions.`,
+	}
+
+	cmd.AddCommand(
+		newAuditListCmd(logger),
+		newAuditRunCmd(logger),
+	)
+
+	return cmd
+}
+
+// newAuditListCmd creates the audit list command.
+func newAuditListCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the built-in and saved audit queries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queries, err := auditQueries(ctx.Config.Audit)
+			if err != nil {
+				return err
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderAuditQueries(queries)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// newAuditRunCmd creates the audit run command.
+func newAuditRunCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		all          bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run <name>...",
+		Short: "Run audit queries",
+		Long: `Run one or more audit queries by name, or every query with --all, and
+report the objects they find. See 'audit list' for the available queries.
+
+The command exits non-zero when any query reports an object.`,
+		Example: `  radb-client audit run routes-without-descr
+  radb-client audit run single-maintainer free-mail-contacts -o json
+  radb-client audit run --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			queries, err := auditQueries(ctx.Config.Audit)
+			if err != nil {
+				return err
+			}
+
+			var selected []audit.Query
+			switch {
+			case all && len(args) > 0:
+				return fmt.Errorf("--all cannot be combined with query names")
+			case all:
+				selected = queries
+			case len(args) == 0:
+				return fmt.Errorf("name a query to run, or use --all (see 'audit list')")
+			default:
+				for _, name := range args {
+					query := audit.FindQuery(queries, name)
+					if query == nil {
+						return fmt.Errorf("unknown audit query %q (see 'audit list')", name)
+					}
+					selected = append(selected, *query)
+				}
+			}
+
+			routes, err := ctx.APIClient.ListRoutes(cmdCtx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to list routes: %w", err)
+			}
+			contacts, err := ctx.APIClient.ListContacts(cmdCtx)
+			if err != nil {
+				return fmt.Errorf("failed to list contacts: %w", err)
+			}
+			logger.Debugf("Running %d audit queries over %d routes and %d contacts", len(selected), routes.Count, contacts.Count)
+
+			results := make([]audit.QueryResult, 0)
+			for _, query := range selected {
+				results = append(results, query.Run(routes.Routes, contacts.Contacts)...)
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if err := outputter.RenderQueryResults(results); err != nil {
+				return err
+			}
+
+			if len(results) > 0 {
+				return fmt.Errorf("audit found %d objects", len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Run every built-in and saved query")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// auditQueries returns the built-in queries and those saved in the configuration.
+func auditQueries(cfg config.AuditConfig) ([]audit.Query, error) {
+	saved := make([]audit.SavedQuery, 0, len(cfg.Queries))
+	for _, qc := range cfg.Queries {
+		saved = append(saved, audit.SavedQuery{
+			Name:        qc.Name,
+			Description: qc.Description,
+			Object:      qc.Object,
+			Origin:      qc.Origin,
+			Prefix:      qc.Prefix,
+			MntBy:       qc.MntBy,
+			Descr:       qc.Descr,
+			Email:       qc.Email,
+			Role:        qc.Role,
+			Missing:     qc.Missing,
+		})
+	}
+
+	queries, err := audit.Queries(saved)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit configuration: %w", err)
+	}
+	return queries, nil
+}
@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 	"syscall"
 
@@ -53,8 +52,7 @@ var authLoginCmd = &cobra.Command{
 		}
 
 		// Attempt login
-		ctxTimeout := context.Background()
-		if err := ctx.APIClient.Login(ctxTimeout, username, password); err != nil {
+		if err := ctx.APIClient.Login(cmd.Context(), username, password); err != nil {
 			return fmt.Errorf("login failed: %w", err)
 		}
 
@@ -80,6 +78,18 @@ var authStatusCmd = &cobra.Command{
 	Short: "Check authentication status",
 	Long:  "Display current authentication status and configured username.",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if ctx.Config.API.AuthMode == "hmac" {
+			keyID := ctx.Config.API.Signing.KeyID
+			fmt.Printf("Auth mode: HMAC request signing (key %s)\n", keyID)
+			if _, err := ctx.CredMgr.GetSigningKey(keyID); err != nil {
+				fmt.Println("Status: Signing key not found (run 'radb-client auth signing-key')")
+			} else {
+				fmt.Println("Status: Signing key stored")
+			}
+			fmt.Printf("Storage: %s\n", ctx.CredMgr.Backend())
+			return nil
+		}
+
 		if ctx.Config.Credentials.Username == "" {
 			fmt.Println("Status: Not authenticated")
 			fmt.Println("\nRun 'radb-client auth login' to authenticate")
@@ -95,6 +105,7 @@ var authStatusCmd = &cobra.Command{
 		} else {
 			fmt.Println("Status: Authenticated (credentials stored)")
 		}
+		fmt.Printf("Storage: %s\n", ctx.CredMgr.Backend())
 
 		return nil
 	},
@@ -117,8 +128,7 @@ var authLogoutCmd = &cobra.Command{
 		}
 
 		// Logout from API
-		ctxTimeout := context.Background()
-		if err := ctx.APIClient.Logout(ctxTimeout); err != nil {
+		if err := ctx.APIClient.Logout(cmd.Context()); err != nil {
 			ctx.Logger.Warnf("API logout warning: %v", err)
 		}
 
@@ -133,8 +143,42 @@ var authLogoutCmd = &cobra.Command{
 	},
 }
 
+var authSigningKeyCmd = &cobra.Command{
+	Use:   "signing-key",
+	Short: "Store the request signing key",
+	Long: `Store the HMAC key used to sign requests when api.auth_mode is hmac.
+The key is kept in the system keyring or encrypted file under api.signing.key_id.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyID := ctx.Config.API.Signing.KeyID
+		if keyID == "" {
+			return fmt.Errorf("set api.signing.key_id before storing a signing key")
+		}
+
+		fmt.Printf("Signing key for %s: ", keyID)
+		keyBytes, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			return fmt.Errorf("failed to read signing key: %w", err)
+		}
+		if len(keyBytes) == 0 {
+			return fmt.Errorf("signing key is required")
+		}
+
+		if err := ctx.CredMgr.SetSigningKey(keyID, string(keyBytes)); err != nil {
+			return err
+		}
+
+		fmt.Printf("Stored signing key %s\n", keyID)
+		if ctx.Config.API.AuthMode != "hmac" {
+			fmt.Println("Set api.auth_mode to hmac to sign requests with it")
+		}
+		return nil
+	},
+}
+
 func init() {
 	authCmd.AddCommand(authLoginCmd)
 	authCmd.AddCommand(authStatusCmd)
 	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authSigningKeyCmd)
 }
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/filtersim"
+	"github.com/bss/radb-client/internal/policy"
+	"github.com/bss/radb-client/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewAutNumCmd creates the autnum command and its subcommands.
+func NewAutNumCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "autnum",
+		Aliases: []string{"aut-num"},
+		Short:   "Inspect aut-num routing policy",
+		Long: `Parse the import, export, mp-import, and mp-export attributes of aut-num
+objects into per-peer rules, and compare policies peer by peer.
+
+Each argument is an ASN, looked up in the registry, or a file of RPSL text
+such as a proposed aut-num update.`,
+	}
+
+	cmd.AddCommand(
+		newAutNumPolicyCmd(logger),
+		newAutNumDiffCmd(logger),
+	)
+
+	return cmd
+}
+
+// newAutNumPolicyCmd creates the autnum policy command.
+func newAutNumPolicyCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "policy <asn|file>",
+		Short: "Show an aut-num's routing policy as per-peer rules",
+		Example: `  radb-client autnum policy AS64500
+  radb-client autnum policy proposed-aut-num.txt -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, p, err := loadAutNumPolicy(cmd.Context(), logger, args[0])
+			if err != nil {
+				return err
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderAutNumPolicy(name, p)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	addCopyFlag(cmd)
+
+	return cmd
+}
+
+// newAutNumDiffCmd creates the autnum diff command.
+func newAutNumDiffCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "diff <from> <to>",
+		Short: "Compare two aut-num policies peer by peer",
+		Long: `Compare the routing policy of two aut-num objects and report the changes
+per peer, such as "added peer AS64501 import" or a changed filter, rather
+than a changed block of text. Rules for the same peer, direction, and
+address families are compared with each other.`,
+		Example: `  # Review a proposed update against the registered object
+  radb-client autnum diff AS64500 proposed-aut-num.txt`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			_, from, err := loadAutNumPolicy(cmdCtx, logger, args[0])
+			if err != nil {
+				return err
+			}
+			_, to, err := loadAutNumPolicy(cmdCtx, logger, args[1])
+			if err != nil {
+				return err
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderPolicyDiff(policy.Diff(from, to))
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	addCopyFlag(cmd)
+
+	return cmd
+}
+
+// loadAutNumPolicy parses the policy of an aut-num given as a file of RPSL
+// text or an ASN, and returns the aut-num's name with it. Policy values that
+// could not be parsed are logged.
+func loadAutNumPolicy(cmdCtx context.Context, logger *logrus.Logger, arg string) (string, *policy.Policy, error) {
+	var (
+		name  string
+		attrs map[string][]string
+	)
+
+	if data, err := os.ReadFile(arg); err == nil {
+		for _, object := range api.ParseRPSL(string(data)) {
+			if values := object["aut-num"]; len(values) > 0 {
+				name, attrs = values[0], object
+				break
+			}
+		}
+		if attrs == nil {
+			return "", nil, fmt.Errorf("no aut-num object in %s", arg)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("failed to read %s: %w", arg, err)
+	} else {
+		name = filtersim.NormalizeASN(arg)
+		if err := validator.ValidateASN(name); err != nil {
+			return "", nil, fmt.Errorf("%s is neither a file nor an ASN: %w", arg, err)
+		}
+		if attrs, err = api.ObjectAttributes(cmdCtx, ctx.APIClient, "aut-num", name); err != nil {
+			return "", nil, fmt.Errorf("failed to look up %s: %w", name, err)
+		}
+	}
+
+	p := policy.Parse(attrs)
+	for _, value := range p.Unparsed {
+		logger.Warnf("%s: policy not parsed: %s", name, value)
+	}
+	return name, p, nil
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newRouteBatchCmd creates the route batch command.
+func newRouteBatchCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Apply an operation to every route matching a filter",
+	}
+
+	cmd.AddCommand(
+		newRouteBatchDeleteCmd(logger),
+		newRouteBatchRetryCmd(logger),
+	)
+
+	return cmd
+}
+
+// newRouteBatchDeleteCmd creates the route batch delete command.
+func newRouteBatchDeleteCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		prefix       string
+		origin       string
+		mntBy        string
+		snapshotID   string
+		preview      bool
+		confirmCount int
+		workers      int
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete every route matching a filter",
+		Long: `Delete every route matching the filters. The filters are resolved to a
+concrete list of routes, from live data or from a snapshot, which is shown
+before anything is deleted. To proceed you must type the number of routes
+to delete, or pass it with --confirm-count when running non-interactively.`,
+		Example: `  # Show what would be deleted
+  radb-client route batch delete --mnt-by MAINT-OLD --origin AS64500 --preview
+
+  # Delete the routes recorded in a snapshot, confirming interactively
+  radb-client route batch delete --mnt-by MAINT-OLD --snapshot route-1704110400000000000
+
+  # Non-interactive, e.g. from a script that already reviewed the preview
+  radb-client route batch delete --mnt-by MAINT-OLD --confirm-count 42`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			filters := make(map[string]string)
+			if prefix != "" {
+				filters["prefix"] = prefix
+			}
+			if origin != "" {
+				if !strings.HasPrefix(strings.ToUpper(origin), "AS") {
+					origin = "AS" + origin
+				}
+				filters["origin"] = origin
+			}
+			if mntBy != "" {
+				filters["mnt-by"] = mntBy
+			}
+			if len(filters) == 0 {
+				return fmt.Errorf("at least one filter (--prefix, --origin, --mnt-by) is required")
+			}
+
+			// Resolve the filters to concrete routes
+			var routes *models.RouteList
+			if snapshotID != "" {
+				snapshot, err := ctx.StateMgr.LoadSnapshot(cmdCtx, snapshotID)
+				if err != nil {
+					return fmt.Errorf("failed to load snapshot %s: %w", snapshotID, err)
+				}
+				if snapshot.Routes == nil {
+					return fmt.Errorf("snapshot %s contains no routes", snapshotID)
+				}
+				routes = snapshot.Routes.Filter(filters)
+			} else {
+				var err error
+				routes, err = ctx.APIClient.ListRoutes(cmdCtx, filters)
+				if err != nil {
+					return fmt.Errorf("failed to list routes: %w", err)
+				}
+			}
+
+			if routes.Count == 0 {
+				fmt.Println("No routes match the filters")
+				return nil
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			outputter.SetAnnotations(loadAnnotations(cmdCtx, logger))
+			if err := outputter.RenderRoutes(routes); err != nil {
+				return err
+			}
+
+			// Keep JSON and YAML previews parseable
+			summary := os.Stdout
+			if OutputFormat(outputFormat) != OutputFormatTable {
+				summary = os.Stderr
+			}
+			fmt.Fprintf(summary, "\n%d routes match (%s)\n", routes.Count, models.FilterScope(filters))
+
+			if preview {
+				return nil
+			}
+
+			if !cmd.Flags().Changed("confirm-count") {
+				var err error
+				confirmCount, err = promptCount(cmd.InOrStdin(), fmt.Sprintf("Type %d to delete these routes", routes.Count))
+				if err != nil {
+					return err
+				}
+			}
+			if confirmCount != routes.Count {
+				return fmt.Errorf("confirmation count %d does not match %d routes; nothing deleted", confirmCount, routes.Count)
+			}
+
+			journal := models.NewBulkJournal(models.BulkDeleteRoutes)
+			for i := range routes.Routes {
+				journal.AddRoute(&routes.Routes[i])
+			}
+
+			result, err := startBulk(cmdCtx, logger, journal, workers)
+			if err != nil {
+				return fmt.Errorf("failed to delete routes: %w", err)
+			}
+
+			return reportBulkResult("Deleted", "routes", result)
+		},
+	}
+
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Filter by prefix")
+	cmd.Flags().StringVar(&origin, "origin", "", "Filter by origin ASN")
+	cmd.Flags().StringVar(&mntBy, "mnt-by", "", "Filter by maintainer")
+	cmd.Flags().StringVar(&snapshotID, "snapshot", "", "Resolve the filters against a snapshot instead of live data")
+	cmd.Flags().BoolVar(&preview, "preview", false, "Show the matching routes without deleting them")
+	cmd.Flags().IntVar(&confirmCount, "confirm-count", 0, "Confirm by giving the number of routes to delete")
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel delete workers (default from performance.max_concurrent_requests)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format of the matching routes (table, json, yaml)")
+
+	return cmd
+}
+
+// newRouteBatchRetryCmd creates the route batch retry command.
+func newRouteBatchRetryCmd(logger *logrus.Logger) *cobra.Command {
+	var workers int
+
+	cmd := &cobra.Command{
+		Use:   "retry <run-id>",
+		Short: "Retry only the failed items of a route bulk operation",
+		Long: `Re-attempt the items of a route bulk operation (batch delete, bulk-edit,
+apply-diff) whose last attempt failed. The outcome of every item and each
+run's totals are kept in the operation's journal, listed by 'bulk list'.
+Items that were never attempted are left alone; use 'bulk resume' to run
+those too.`,
+		Example: `  radb-client bulk list --incomplete
+  radb-client route batch retry delete-routes-1704110400000000000`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := state.NewJournalStore(ctx.Config.StateDir(), logger)
+			journal, err := store.Load(args[0])
+			if err != nil {
+				return err
+			}
+			if bulkObjects(journal.Operation) != "routes" {
+				return fmt.Errorf("%s is a %s operation; use 'radb-client bulk resume %s'", journal.ID, journal.Operation, journal.ID)
+			}
+
+			failed := journal.Failed()
+			if len(failed) == 0 {
+				fmt.Printf("Bulk operation %s has no failed items\n", journal.ID)
+				if pending, _, _ := journal.Counts(); pending > 0 {
+					fmt.Printf("%d items were never attempted; run them with: radb-client bulk resume %s\n", pending, journal.ID)
+				}
+				return nil
+			}
+			fmt.Printf("Retrying %d failed items of %s\n", len(failed), journal.ID)
+
+			result, err := runJournal(cmd.Context(), logger, store, journal, failed, workers)
+			if err != nil {
+				return err
+			}
+			return reportBulkResult(bulkVerb(journal.Operation), bulkObjects(journal.Operation), result)
+		},
+	}
+
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel workers (default from performance.max_concurrent_requests)")
+
+	return cmd
+}
+
+// promptCount asks the user to type a number, read from in.
+func promptCount(in io.Reader, question string) (int, error) {
+	fmt.Printf("%s: ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && strings.TrimSpace(answer) == "" {
+		return 0, fmt.Errorf("no confirmation given; nothing deleted")
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil {
+		return 0, fmt.Errorf("confirmation %q is not a number; nothing deleted", strings.TrimSpace(answer))
+	}
+	return count, nil
+}
+
+// reportBulkResult prints the outcome of a bulk operation and returns an
+// error if any item failed.
+func reportBulkResult(verb, objects string, result *api.BulkResult) error {
+	fmt.Printf("%s %d %s", verb, result.Succeeded, objects)
+	if result.Failed > 0 {
+		fmt.Printf(", %d failed:\n", result.Failed)
+		for _, e := range result.Errors {
+			fmt.Printf("  %s: %s\n", e.ID, e.Error)
+		}
+		return fmt.Errorf("%d of %d operations failed", result.Failed, result.Total)
+	}
+	fmt.Println()
+	return nil
+}
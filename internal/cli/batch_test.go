@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/spf13/cobra"
+)
+
+func TestPromptCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr string
+	}{
+		{"number", "42\n", 42, ""},
+		{"surrounding spaces", "  7 \n", 7, ""},
+		{"no trailing newline", "3", 3, ""},
+		{"zero", "0\n", 0, ""},
+		{"not a number", "yes\n", 0, "not a number"},
+		{"number with text", "42 routes\n", 0, "not a number"},
+		{"empty line", "\n", 0, "not a number"},
+		{"no input", "", 0, "no confirmation given"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := promptCount(strings.NewReader(tt.input), "Type the count")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("promptCount(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), "nothing deleted") {
+					t.Errorf("error %q should say nothing was deleted", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("promptCount(%q) failed: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("promptCount(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteBatchDeleteConfirmation(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		args      []string
+		wantErr   bool
+		remaining int
+	}{
+		{"typed count matches", "2\n", nil, false, 1},
+		{"typed count differs", "3\n", nil, true, 3},
+		{"typed answer not a number", "y\n", nil, true, 3},
+		{"no answer", "", nil, true, 3},
+		{"confirm count flag", "", []string{"--confirm-count", "2"}, false, 1},
+		{"wrong confirm count flag", "2\n", []string{"--confirm-count", "1"}, true, 3},
+		{"preview", "2\n", []string{"--preview"}, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setTestContext(t)
+			for _, route := range []models.RouteObject{
+				testRoute("192.0.2.0/24", "AS64500", "old"),
+				testRoute("198.51.100.0/24", "AS64500", "old"),
+				testRoute("203.0.113.0/24", "AS64501", "other"),
+			} {
+				if err := client.CreateRoute(context.Background(), &route); err != nil {
+					t.Fatalf("CreateRoute() failed: %v", err)
+				}
+			}
+
+			cmd := newRouteBatchDeleteCmd(ctx.Logger)
+			cmd.SetArgs(append([]string{"--origin", "AS64500"}, tt.args...))
+			cmd.SetIn(strings.NewReader(tt.input))
+			cmd.SetOut(&strings.Builder{})
+			cmd.SetErr(&strings.Builder{})
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("batch delete error = %v, want error %v", err, tt.wantErr)
+			}
+
+			routes, err := client.ListRoutes(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("ListRoutes() failed: %v", err)
+			}
+			if routes.Count != tt.remaining {
+				t.Errorf("%d routes remain, want %d", routes.Count, tt.remaining)
+			}
+			if routes.ByID()["203.0.113.0/24-AS64501"] == nil {
+				t.Error("batch delete removed a route outside the filter")
+			}
+		})
+	}
+}
+
+// captureStdout returns what run writes to stdout.
+func captureStdout(t *testing.T, run func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() failed: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	run()
+	w.Close()
+	return <-done
+}
+
+func TestRouteBatchDeleteOutputFormat(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultOutput string
+		args          []string
+		wantJSON      bool
+	}{
+		{"table by default", "", nil, false},
+		{"configured default", "json", nil, true},
+		{"flag overrides configured default", "json", []string{"-o", "table"}, false},
+		{"flag", "", []string{"--output", "json"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setTestContext(t)
+			for _, route := range []models.RouteObject{
+				testRoute("192.0.2.0/24", "AS64500", "old"),
+				testRoute("198.51.100.0/24", "AS64500", "old"),
+			} {
+				if err := client.CreateRoute(context.Background(), &route); err != nil {
+					t.Fatalf("CreateRoute() failed: %v", err)
+				}
+			}
+
+			cmd := newRouteBatchDeleteCmd(ctx.Logger)
+			cmd.SetArgs(append([]string{"--origin", "AS64500", "--preview"}, tt.args...))
+			cmd.SetOut(&strings.Builder{})
+			cmd.SetErr(&strings.Builder{})
+			cmd.SilenceUsage = true
+			cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+				return applyDefaultOutput(cmd, tt.defaultOutput)
+			}
+
+			var err error
+			out := captureStdout(t, func() { err = cmd.Execute() })
+			if err != nil {
+				t.Fatalf("batch delete --preview failed: %v", err)
+			}
+
+			var routes models.RouteList
+			isJSON := json.Unmarshal([]byte(out), &routes) == nil
+			if isJSON != tt.wantJSON {
+				t.Fatalf("output is JSON = %v, want %v:\n%s", isJSON, tt.wantJSON, out)
+			}
+			if isJSON && len(routes.Routes) != 2 {
+				t.Errorf("JSON preview lists %d routes, want 2", len(routes.Routes))
+			}
+			if !isJSON && !strings.Contains(out, "2 routes match") {
+				t.Errorf("table preview missing the match count:\n%s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewBulkCmd creates the bulk command for inspecting and resuming bulk operations.
+func NewBulkCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bulk",
+		Short: "Inspect and resume bulk operations",
+		Long: `Every bulk operation (route bulk-edit, route batch delete, contact import)
+records each item and its outcome in a journal under the state directory.
+If an operation is interrupted or some items fail, resume it to retry only
+the items that did not succeed.
+
+Before each run, the maintainers of the items are probed for write
+authority, and items that will be rejected for lack of it are listed.`,
+	}
+
+	cmd.AddCommand(
+		newBulkListCmd(logger),
+		newBulkShowCmd(logger),
+		newBulkResumeCmd(logger),
+	)
+
+	return cmd
+}
+
+// newBulkListCmd creates the bulk list command.
+func newBulkListCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		incomplete   bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List bulk operation journals",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			journals, err := state.NewJournalStore(ctx.Config.StateDir(), logger).List()
+			if err != nil {
+				return err
+			}
+
+			if incomplete {
+				filtered := journals[:0]
+				for _, journal := range journals {
+					if !journal.Complete() {
+						filtered = append(filtered, journal)
+					}
+				}
+				journals = filtered
+			}
+
+			if len(journals) == 0 {
+				fmt.Println("No bulk operations recorded")
+				return nil
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderJournals(journals)
+		},
+	}
+
+	cmd.Flags().BoolVar(&incomplete, "incomplete", false, "Only list operations with pending or failed items")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// newBulkShowCmd creates the bulk show command.
+func newBulkShowCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show the items of a bulk operation and their status",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			journal, err := state.NewJournalStore(ctx.Config.StateDir(), logger).Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderJournal(journal)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// newBulkResumeCmd creates the bulk resume command.
+func newBulkResumeCmd(logger *logrus.Logger) *cobra.Command {
+	var workers int
+
+	cmd := &cobra.Command{
+		Use:   "resume <id>",
+		Short: "Retry the pending and failed items of a bulk operation",
+		Example: `  radb-client bulk list --incomplete
+  radb-client bulk resume delete-routes-1704110400000000000`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := state.NewJournalStore(ctx.Config.StateDir(), logger)
+			journal, err := store.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			remaining := journal.Remaining()
+			if len(remaining) == 0 {
+				fmt.Printf("Bulk operation %s already completed; nothing to resume\n", journal.ID)
+				return nil
+			}
+			fmt.Printf("Resuming %s: %d of %d items remaining\n", journal.ID, len(remaining), len(journal.Items))
+
+			result, err := runJournal(cmd.Context(), logger, store, journal, remaining, workers)
+			if err != nil {
+				return err
+			}
+			return reportBulkResult(bulkVerb(journal.Operation), bulkObjects(journal.Operation), result)
+		},
+	}
+
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel workers (default from performance.max_concurrent_requests)")
+
+	return cmd
+}
+
+// startBulk saves a new journal and runs every item in it.
+func startBulk(cmdCtx context.Context, logger *logrus.Logger, journal *models.BulkJournal, workers int) (*api.BulkResult, error) {
+	store := state.NewJournalStore(ctx.Config.StateDir(), logger)
+	if err := store.Save(journal); err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, len(journal.Items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return runJournal(cmdCtx, logger, store, journal, indexes, workers)
+}
+
+// runJournal performs the journal's operation on the items at indexes,
+// recording each outcome as it arrives so an interrupted run can be resumed.
+func runJournal(cmdCtx context.Context, logger *logrus.Logger, store *state.JournalStore, journal *models.BulkJournal, indexes []int, workers int) (*api.BulkResult, error) {
+	if workers <= 0 {
+		workers = ctx.Config.Performance.MaxConcurrentRequests
+	}
+
+	preflightJournal(cmdCtx, logger, journal, indexes)
+	startedAt := time.Now()
+
+	save := func() {
+		if err := store.Save(journal); err != nil {
+			logger.Warnf("Failed to update journal %s: %v", journal.ID, err)
+		}
+	}
+
+	recorded := make(map[int]bool, len(indexes))
+	enableBulkProgress(logger, func(p api.BulkProgress) {
+		if p.Index < 0 || p.Index >= len(indexes) {
+			return
+		}
+		journal.Record(indexes[p.Index], p.ItemErr)
+		recorded[p.Index] = true
+		save()
+	})
+
+	result, err := executeJournal(cmdCtx, journal, indexes, workers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients without progress reporting only return the final result
+	failed := make(map[int]error, len(result.Errors))
+	for _, e := range result.Errors {
+		failed[e.Index] = errors.New(e.Error)
+	}
+	for i := range indexes {
+		if !recorded[i] {
+			journal.Record(indexes[i], failed[i])
+		}
+	}
+	journal.AddRun(models.BulkRun{
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+		Total:      result.Total,
+		Succeeded:  result.Succeeded,
+		Failed:     result.Failed,
+	})
+	save()
+
+	if pending, _, _ := journal.Counts(); pending == 0 && bulkObjects(journal.Operation) == "routes" && !journal.Complete() {
+		fmt.Fprintf(os.Stderr, "Retry the failed items with: radb-client route batch retry %s\n", journal.ID)
+	} else if !journal.Complete() {
+		fmt.Fprintf(os.Stderr, "Retry the remaining items with: radb-client bulk resume %s\n", journal.ID)
+	}
+
+	return result, nil
+}
+
+// executeJournal dispatches the journal's operation for the items at indexes.
+func executeJournal(cmdCtx context.Context, journal *models.BulkJournal, indexes []int, workers int) (*api.BulkResult, error) {
+	routes := make([]*models.RouteObject, 0, len(indexes))
+	contacts := make([]*models.Contact, 0, len(indexes))
+	for _, index := range indexes {
+		item := journal.Items[index]
+		switch {
+		case item.Route != nil:
+			routes = append(routes, item.Route)
+		case item.Contact != nil:
+			contacts = append(contacts, item.Contact)
+		default:
+			return nil, fmt.Errorf("journal item %s has no route or contact", item.ID)
+		}
+	}
+
+	switch journal.Operation {
+	case models.BulkCreateRoutes:
+		return api.CreateRoutes(cmdCtx, ctx.APIClient, routes, workers)
+	case models.BulkUpdateRoutes:
+		return api.UpdateRoutes(cmdCtx, ctx.APIClient, routes, workers)
+	case models.BulkDeleteRoutes:
+		identifiers := make([]api.RouteIdentifier, len(routes))
+		for i, route := range routes {
+			identifiers[i] = api.RouteIdentifier{Prefix: route.Route, ASN: route.Origin}
+		}
+		return api.DeleteRoutes(cmdCtx, ctx.APIClient, identifiers, workers)
+	case models.BulkCreateContacts:
+		return api.CreateContacts(cmdCtx, ctx.APIClient, contacts, workers)
+	case models.BulkUpdateContacts:
+		return api.UpdateContacts(cmdCtx, ctx.APIClient, contacts, workers)
+	case models.BulkDeleteContacts:
+		ids := make([]string, len(contacts))
+		for i, contact := range contacts {
+			ids[i] = contact.ID
+		}
+		return api.DeleteContacts(cmdCtx, ctx.APIClient, ids, workers)
+	}
+
+	return nil, fmt.Errorf("unsupported bulk operation: %s", journal.Operation)
+}
+
+// bulkVerb returns the past-tense verb used to report an operation.
+func bulkVerb(operation models.BulkOperation) string {
+	switch operation {
+	case models.BulkCreateRoutes, models.BulkCreateContacts:
+		return "Created"
+	case models.BulkUpdateRoutes, models.BulkUpdateContacts:
+		return "Updated"
+	default:
+		return "Deleted"
+	}
+}
+
+// bulkObjects returns the object kind of an operation.
+func bulkObjects(operation models.BulkOperation) string {
+	switch operation {
+	case models.BulkCreateContacts, models.BulkUpdateContacts, models.BulkDeleteContacts:
+		return "contacts"
+	default:
+		return "routes"
+	}
+}
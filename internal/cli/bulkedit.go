@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// routeTemplateData exposes route attributes to bulk-edit templates.
+type routeTemplateData struct {
+	Route     string              // Prefix, e.g. 192.0.2.0/24
+	Origin    string              // Origin ASN, e.g. AS64500
+	OriginNum string              // Origin ASN without the AS prefix, e.g. 64500
+	MntBy     string              // First maintainer
+	Source    string              // IRR source
+	Descr     string              // Current description lines, joined with newlines
+	Remarks   string              // Current remarks lines, joined with newlines
+	Customer  string              // From a "customer:" remark, else the first descr line
+	Attr      map[string][]string // Additional RPSL attributes
+}
+
+// newRouteTemplateData builds template data for a route.
+func newRouteTemplateData(route *models.RouteObject) routeTemplateData {
+	data := routeTemplateData{
+		Route:     route.Route,
+		Origin:    route.Origin,
+		OriginNum: strings.TrimPrefix(strings.ToUpper(route.Origin), "AS"),
+		Source:    route.Source,
+		Descr:     strings.Join(route.Descr, "\n"),
+		Remarks:   strings.Join(route.Remarks, "\n"),
+		Attr:      route.RawAttributes,
+	}
+
+	if len(route.MntBy) > 0 {
+		data.MntBy = route.MntBy[0]
+	}
+	if len(route.Descr) > 0 {
+		data.Customer = route.Descr[0]
+	}
+	for _, remark := range route.Remarks {
+		key, value, ok := strings.Cut(remark, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "customer") {
+			data.Customer = strings.TrimSpace(value)
+			break
+		}
+	}
+
+	return data
+}
+
+// parseEditTemplate parses a bulk-edit template with helper functions.
+func parseEditTemplate(name, text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	funcs := template.FuncMap{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"trim":  strings.TrimSpace,
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// renderEditTemplate renders a template into attribute lines.
+func renderEditTemplate(tmpl *template.Template, data routeTemplateData) ([]string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// routeEdit is a pending change to a single route.
+type routeEdit struct {
+	Before *models.RouteObject
+	After  *models.RouteObject
+}
+
+// newRouteBulkEditCmd creates the route bulk-edit command.
+func newRouteBulkEditCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		prefix          string
+		origin          string
+		mntBy           string
+		descrTemplate   string
+		remarksTemplate string
+		dryRun          bool
+		confirm         bool
+		workers         int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bulk-edit",
+		Short: "Rewrite descriptions or remarks across matching routes",
+		Long: `Rewrite descr and remarks attributes on every route matching the filters,
+using Go templates. The computed changes are shown as a diff before anything
+is written; pass --confirm to apply them through the batch API.
+
+Template fields:
+  .Route      Prefix (192.0.2.0/24)
+  .Origin     Origin ASN (AS64500)
+  .OriginNum  Origin ASN without the AS prefix (64500)
+  .MntBy      First maintainer
+  .Source     IRR source
+  .Descr      Current description (lines joined with newlines)
+  .Remarks    Current remarks (lines joined with newlines)
+  .Customer   Value of a "customer:" remark, else the first descr line
+  .Attr       Other RPSL attributes, e.g. {{index .Attr "country"}}
+
+Functions: upper, lower, trim. Newlines in the output produce multiple lines.`,
+		Example: `  # Preview standardized descriptions
+  radb-client route bulk-edit --mnt-by MAINT-EXAMPLE \
+    --set-descr-template "{{.Customer}} via AS{{.OriginNum}}" --dry-run
+
+  # Apply them
+  radb-client route bulk-edit --mnt-by MAINT-EXAMPLE \
+    --set-descr-template "{{.Customer}} via AS{{.OriginNum}}" --confirm`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			if descrTemplate == "" && remarksTemplate == "" {
+				return fmt.Errorf("at least one of --set-descr-template or --set-remarks-template is required")
+			}
+
+			descrTmpl, err := parseEditTemplate("descr", descrTemplate)
+			if err != nil {
+				return err
+			}
+			remarksTmpl, err := parseEditTemplate("remarks", remarksTemplate)
+			if err != nil {
+				return err
+			}
+
+			// Build filters
+			filters := make(map[string]string)
+			if prefix != "" {
+				filters["prefix"] = prefix
+			}
+			if origin != "" {
+				filters["origin"] = origin
+			}
+			if mntBy != "" {
+				filters["mnt-by"] = mntBy
+			}
+			if len(filters) == 0 {
+				return fmt.Errorf("at least one filter (--prefix, --origin, --mnt-by) is required")
+			}
+
+			routes, err := ctx.APIClient.ListRoutes(cmdCtx, filters)
+			if err != nil {
+				return fmt.Errorf("failed to list routes: %w", err)
+			}
+
+			// Compute edits
+			var edits []routeEdit
+			for i := range routes.Routes {
+				before := &routes.Routes[i]
+				data := newRouteTemplateData(before)
+
+				after := *before
+				if descrTmpl != nil {
+					lines, err := renderEditTemplate(descrTmpl, data)
+					if err != nil {
+						return fmt.Errorf("failed to render descr for %s: %w", before.ID(), err)
+					}
+					if len(lines) == 0 {
+						logger.Warnf("Skipping %s: descr template rendered empty", before.ID())
+						continue
+					}
+					after.Descr = lines
+				}
+				if remarksTmpl != nil {
+					lines, err := renderEditTemplate(remarksTmpl, data)
+					if err != nil {
+						return fmt.Errorf("failed to render remarks for %s: %w", before.ID(), err)
+					}
+					after.Remarks = lines
+				}
+
+				if slices.Equal(before.Descr, after.Descr) && slices.Equal(before.Remarks, after.Remarks) {
+					continue
+				}
+				edits = append(edits, routeEdit{Before: before, After: &after})
+			}
+
+			// Show diff
+			for _, edit := range edits {
+				fmt.Println(edit.Before.ID())
+				printAttrDiff("descr", edit.Before.Descr, edit.After.Descr)
+				printAttrDiff("remarks", edit.Before.Remarks, edit.After.Remarks)
+			}
+			fmt.Printf("\n%d of %d matching routes would change\n", len(edits), routes.Count)
+
+			if len(edits) == 0 || dryRun {
+				return nil
+			}
+			if !confirm {
+				return fmt.Errorf("re-run with --confirm to apply these changes")
+			}
+
+			journal := models.NewBulkJournal(models.BulkUpdateRoutes)
+			for _, edit := range edits {
+				journal.AddRoute(edit.After)
+			}
+
+			result, err := startBulk(cmdCtx, logger, journal, workers)
+			if err != nil {
+				return fmt.Errorf("failed to apply edits: %w", err)
+			}
+
+			return reportBulkResult("Updated", "routes", result)
+		},
+	}
+
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Filter by prefix")
+	cmd.Flags().StringVar(&origin, "origin", "", "Filter by origin ASN")
+	cmd.Flags().StringVar(&mntBy, "mnt-by", "", "Filter by maintainer")
+	cmd.Flags().StringVar(&descrTemplate, "set-descr-template", "", "Template for the descr attribute")
+	cmd.Flags().StringVar(&remarksTemplate, "set-remarks-template", "", "Template for the remarks attribute")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without applying them")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Apply the changes")
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel update workers (default from performance.max_concurrent_requests)")
+
+	return cmd
+}
+
+// printAttrDiff prints removed and added lines for an attribute that changed.
+func printAttrDiff(attr string, before, after []string) {
+	if slices.Equal(before, after) {
+		return
+	}
+	for _, line := range before {
+		fmt.Printf("  - %s: %s\n", attr, line)
+	}
+	for _, line := range after {
+		fmt.Printf("  + %s: %s\n", attr, line)
+	}
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestNewRouteTemplateData(t *testing.T) {
+	tests := []struct {
+		name  string
+		route models.RouteObject
+		want  routeTemplateData
+	}{
+		{
+			name: "customer from remarks",
+			route: models.RouteObject{
+				Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A", "MAINT-B"}, Source: "RADB",
+				Descr: []string{"Line one", "Line two"}, Remarks: []string{"Note", " Customer : Example Corp "},
+				RawAttributes: map[string][]string{"country": {"US"}},
+			},
+			want: routeTemplateData{
+				Route: "192.0.2.0/24", Origin: "AS64500", OriginNum: "64500", MntBy: "MAINT-A", Source: "RADB",
+				Descr: "Line one\nLine two", Remarks: "Note\n Customer : Example Corp ", Customer: "Example Corp",
+				Attr: map[string][]string{"country": {"US"}},
+			},
+		},
+		{
+			name:  "customer from descr",
+			route: models.RouteObject{Route: "198.51.100.0/24", Origin: "as64501", Descr: []string{"Acme", "Backup"}, Remarks: []string{"customer-id: 7"}},
+			want:  routeTemplateData{Route: "198.51.100.0/24", Origin: "as64501", OriginNum: "64501", Descr: "Acme\nBackup", Remarks: "customer-id: 7", Customer: "Acme"},
+		},
+		{
+			name:  "bare route",
+			route: models.RouteObject{Route: "203.0.113.0/24", Origin: "AS64502"},
+			want:  routeTemplateData{Route: "203.0.113.0/24", Origin: "AS64502", OriginNum: "64502"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newRouteTemplateData(&tt.route); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newRouteTemplateData() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderEditTemplate(t *testing.T) {
+	data := newRouteTemplateData(&models.RouteObject{
+		Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB",
+		Descr: []string{"Example Corp"}, RawAttributes: map[string][]string{"country": {"US"}},
+	})
+
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+		wantErr  bool
+	}{
+		{"fields", "{{.Customer}} via AS{{.OriginNum}} ({{.Route}}, {{.MntBy}}, {{.Source}})", []string{"Example Corp via AS64500 (192.0.2.0/24, MAINT-A, RADB)"}, false},
+		{"functions", "{{upper .Customer}} {{lower .Origin}} [{{trim \"  x  \"}}]", []string{"EXAMPLE CORP as64500 [x]"}, false},
+		{"attributes", `Country {{index .Attr "country" 0}}`, []string{"Country US"}, false},
+		{"multiple lines", "{{.Customer}}\n\n  Managed by {{.MntBy}}  \n", []string{"Example Corp", "Managed by MAINT-A"}, false},
+		{"existing lines", "{{.Descr}}\nupdated", []string{"Example Corp", "updated"}, false},
+		{"empty output", "{{if false}}x{{end}}", nil, false},
+		{"unknown field", "{{.Nope}}", nil, true},
+		{"missing attribute", "{{.Attr.region}}", nil, true},
+		{"failing function", `{{index .Attr "country" 5}}`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseEditTemplate("descr", tt.template)
+			if err != nil {
+				t.Fatalf("parseEditTemplate() failed: %v", err)
+			}
+
+			got, err := renderEditTemplate(tmpl, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderEditTemplate() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderEditTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEditTemplate(t *testing.T) {
+	if tmpl, err := parseEditTemplate("descr", ""); tmpl != nil || err != nil {
+		t.Errorf("parseEditTemplate(\"\") = %v, %v; want no template", tmpl, err)
+	}
+
+	_, err := parseEditTemplate("remarks", "{{.Customer")
+	if err == nil || !strings.Contains(err.Error(), "invalid remarks template") {
+		t.Errorf("parseEditTemplate() error = %v, want an invalid remarks template", err)
+	}
+
+	if _, err := parseEditTemplate("descr", "{{nosuchfunc .Route}}"); err == nil {
+		t.Error("parseEditTemplate() accepted an unknown function")
+	}
+}
+
+// runBulkEdit runs route bulk-edit with args.
+func runBulkEdit(t *testing.T, args ...string) error {
+	t.Helper()
+
+	cmd := newRouteBulkEditCmd(ctx.Logger)
+	cmd.SetArgs(args)
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetErr(&strings.Builder{})
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func TestRouteBulkEditRoundTrip(t *testing.T) {
+	client, _ := setTestContext(t)
+	cmdCtx := context.Background()
+
+	for _, route := range []models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB", Descr: []string{"Example Corp"}},
+		{Route: "198.51.100.0/24", Origin: "AS64501", MntBy: []string{"MAINT-A"}, Source: "RADB", Descr: []string{"Old name"}, Remarks: []string{"customer: Acme"}},
+		{Route: "203.0.113.0/24", Origin: "AS64502", MntBy: []string{"MAINT-B"}, Source: "RADB", Descr: []string{"Untouched"}},
+	} {
+		if err := client.CreateRoute(cmdCtx, &route); err != nil {
+			t.Fatalf("CreateRoute() failed: %v", err)
+		}
+	}
+
+	args := []string{"--mnt-by", "MAINT-A", "--set-descr-template", "{{.Customer}} via AS{{.OriginNum}}\n{{.Route}}", "--set-remarks-template", "customer: {{.Customer}}"}
+	if err := runBulkEdit(t, args...); err == nil || !strings.Contains(err.Error(), "--confirm") {
+		t.Fatalf("bulk-edit without --confirm = %v, want a refusal", err)
+	}
+	if err := runBulkEdit(t, append(args, "--confirm")...); err != nil {
+		t.Fatalf("bulk-edit --confirm failed: %v", err)
+	}
+
+	want := map[string]models.RouteObject{
+		"192.0.2.0/24-AS64500":    {Descr: []string{"Example Corp via AS64500", "192.0.2.0/24"}, Remarks: []string{"customer: Example Corp"}},
+		"198.51.100.0/24-AS64501": {Descr: []string{"Acme via AS64501", "198.51.100.0/24"}, Remarks: []string{"customer: Acme"}},
+		"203.0.113.0/24-AS64502":  {Descr: []string{"Untouched"}},
+	}
+	routes, err := client.ListRoutes(cmdCtx, nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	for _, route := range routes.Routes {
+		expected := want[route.ID()]
+		if !reflect.DeepEqual(route.Descr, expected.Descr) || !reflect.DeepEqual(route.Remarks, expected.Remarks) {
+			t.Errorf("%s: descr %q, remarks %q; want %q, %q", route.ID(), route.Descr, route.Remarks, expected.Descr, expected.Remarks)
+		}
+	}
+
+	// Rendering the edited routes again changes nothing
+	recorder := &recordingClient{Client: client}
+	ctx.APIClient = recorder
+	if err := runBulkEdit(t, append(args, "--confirm")...); err != nil {
+		t.Fatalf("second bulk-edit failed: %v", err)
+	}
+	if writes := recorder.Writes(); len(writes) != 0 {
+		t.Errorf("re-running the template wrote %v", writes)
+	}
+}
+
+func TestRouteBulkEditTemplateError(t *testing.T) {
+	client, _ := setTestContext(t)
+	route := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB", Descr: []string{"Example"}}
+	if err := client.CreateRoute(context.Background(), &route); err != nil {
+		t.Fatalf("CreateRoute() failed: %v", err)
+	}
+	recorder := &recordingClient{Client: client}
+	ctx.APIClient = recorder
+
+	err := runBulkEdit(t, "--mnt-by", "MAINT-A", "--set-descr-template", "{{.Attr.region}}", "--confirm")
+	if err == nil || !strings.Contains(err.Error(), "failed to render descr for 192.0.2.0/24-AS64500") {
+		t.Fatalf("bulk-edit error = %v, want a render failure naming the route", err)
+	}
+	if writes := recorder.Writes(); len(writes) != 0 {
+		t.Errorf("failed bulk-edit wrote %v", writes)
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// responseCacheDir is the response cache directory under the state directory.
+const responseCacheDir = "http-cache"
+
+// newResponseCache opens the response cache configured by cfg.
+func newResponseCache(cfg *config.Config) (*api.ResponseCache, error) {
+	cache, err := api.NewResponseCache(filepath.Join(cfg.StateDir(), responseCacheDir), time.Duration(cfg.API.Cache.TTL)*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open response cache: %w", err)
+	}
+	return cache, nil
+}
+
+// NewCacheCmd creates the cache command for the API response cache.
+func NewCacheCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the cache of API reads",
+		Long: `With api.cache.enabled set, route and contact reads are kept on disk under
+the state directory. Reads within api.cache.ttl seconds are answered from
+the cache, writes drop the entries they change, and when the API fails or
+returns a server error an expired entry is served with a warning.`,
+	}
+
+	cmd.AddCommand(
+		newCacheWarmCmd(logger),
+		newCacheClearCmd(logger),
+	)
+
+	return cmd
+}
+
+func newCacheWarmCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		mntBy        string
+		skipContacts bool
+		tags         []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Prefetch a maintainer's objects before a maintenance window",
+		Long: `Fetch every route maintained by --mnt-by, each route individually, and the
+contact list from the API, refreshing the response cache, and save them as
+snapshots. Run it before a maintenance window so later reads are answered
+locally and keep working if the API slows down or fails.
+
+Without api.cache.enabled only the snapshots are saved.`,
+		Example: `  radb-client cache warm --mnt-by MAINT-EXAMPLE
+  radb-client cache warm --mnt-by MAINT-EXAMPLE --skip-contacts --tag pre-maintenance`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !ctx.Config.API.Cache.Enabled {
+				logger.Warn("api.cache.enabled is false: only snapshots are saved, reads will still go to the API")
+			} else if _, ok := ctx.APIClient.(api.CachingClient); !ok {
+				logger.Warn("The API client does not cache responses (offline mode?): only snapshots are saved")
+			}
+
+			// Reads refresh cache entries even when they are still fresh
+			cmdCtx := api.WithCacheRefresh(cmd.Context())
+			start := time.Now()
+
+			filters := map[string]string{"mnt-by": mntBy}
+			routes, err := ctx.APIClient.ListRoutes(cmdCtx, filters)
+			if err != nil {
+				return fmt.Errorf("failed to list routes: %w", err)
+			}
+
+			var failed []string
+			for i, route := range routes.Routes {
+				logger.Debugf("Warming route %d/%d: %s %s", i+1, routes.Count, route.Route, route.Origin)
+				if _, err := ctx.APIClient.GetRoute(cmdCtx, route.Route, route.Origin); err != nil {
+					logger.Warnf("Failed to fetch %s %s: %v", route.Route, route.Origin, err)
+					failed = append(failed, route.ID())
+				}
+			}
+
+			snapshot := models.NewScopedSnapshot(models.SnapshotTypeRoute, fmt.Sprintf("Cache warm-up (%s)", models.FilterScope(filters)), filters)
+			snapshot.Routes = routes
+			snapshot.AddTags(tags...)
+			if err := ctx.StateMgr.SaveSnapshot(cmdCtx, snapshot); err != nil {
+				return fmt.Errorf("failed to save route snapshot: %w", err)
+			}
+			fmt.Printf("Warmed %d routes maintained by %s (snapshot %s)\n", routes.Count-len(failed), mntBy, snapshot.ID)
+
+			if !skipContacts {
+				contacts, err := ctx.APIClient.ListContacts(cmdCtx)
+				if err != nil {
+					return fmt.Errorf("failed to list contacts: %w", err)
+				}
+
+				contactSnapshot := models.NewSnapshot(models.SnapshotTypeContact, "Cache warm-up")
+				contactSnapshot.Contacts = contacts
+				contactSnapshot.AddTags(tags...)
+				if err := ctx.StateMgr.SaveSnapshot(cmdCtx, contactSnapshot); err != nil {
+					return fmt.Errorf("failed to save contact snapshot: %w", err)
+				}
+				fmt.Printf("Warmed %d contacts (snapshot %s)\n", contacts.Count, contactSnapshot.ID)
+			}
+
+			logger.Infof("Cache warm-up finished in %s", time.Since(start).Round(time.Millisecond))
+			if len(failed) > 0 {
+				return fmt.Errorf("failed to fetch %d routes: %s", len(failed), strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mntBy, "mnt-by", "", "Maintainer whose routes are prefetched")
+	cmd.Flags().BoolVar(&skipContacts, "skip-contacts", false, "Do not prefetch the contact list")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Tag the saved snapshots (protects them from cleanup)")
+	cmd.MarkFlagRequired("mnt-by")
+
+	return cmd
+}
+
+func newCacheClearCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached API response",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, err := newResponseCache(ctx.Config)
+			if err != nil {
+				return err
+			}
+
+			removed, err := cache.Clear()
+			if err != nil {
+				return err
+			}
+			logger.Debugf("Cleared response cache in %s", filepath.Join(ctx.Config.StateDir(), responseCacheDir))
+			fmt.Printf("Removed %d cached responses\n", removed)
+			return nil
+		},
+	}
+}
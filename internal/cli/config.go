@@ -3,8 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/bss/radb-client/internal/api"
 	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/state"
 	"github.com/spf13/cobra"
 )
 
@@ -62,14 +65,43 @@ var configShowCmd = &cobra.Command{
 		fmt.Printf("  Format: %s\n", ctx.Config.API.Format)
 		fmt.Printf("  Timeout: %ds\n", ctx.Config.API.Timeout)
 
+		if ctx.Config.API.Proxy.URL != "" {
+			fmt.Printf("  Proxy: %s\n", ctx.Config.API.Proxy.URL)
+			if len(ctx.Config.API.Proxy.NoProxy) > 0 {
+				fmt.Printf("  No proxy: %s\n", strings.Join(ctx.Config.API.Proxy.NoProxy, ", "))
+			}
+		} else {
+			fmt.Println("  Proxy: (from environment)")
+		}
+
+		tlsCfg := ctx.Config.API.TLS
+		if tlsCfg.CAFile != "" || tlsCfg.CertFile != "" || tlsCfg.MinVersion != "" {
+			fmt.Println("\nTLS:")
+			if tlsCfg.CAFile != "" {
+				fmt.Printf("  CA bundle: %s\n", tlsCfg.CAFile)
+			}
+			if tlsCfg.CertFile != "" {
+				fmt.Printf("  Client cert: %s\n", tlsCfg.CertFile)
+			}
+			if tlsCfg.MinVersion != "" {
+				fmt.Printf("  Min version: %s\n", tlsCfg.MinVersion)
+			}
+		}
+
 		fmt.Println("\nRate Limiting:")
 		fmt.Printf("  Requests/min: %d\n", ctx.Config.API.RateLimit.RequestsPerMinute)
 		fmt.Printf("  Burst size: %d\n", ctx.Config.API.RateLimit.BurstSize)
+		for _, class := range api.EndpointClasses {
+			if limit, ok := ctx.Config.API.RateLimit.Endpoints[string(class)]; ok {
+				fmt.Printf("  %s: %d/min, burst %d\n", class, limit.RequestsPerMinute, limit.BurstSize)
+			}
+		}
 
 		fmt.Println("\nPreferences:")
 		fmt.Printf("  Cache dir: %s\n", ctx.Config.Preferences.CacheDir)
 		fmt.Printf("  History dir: %s\n", ctx.Config.Preferences.HistoryDir)
 		fmt.Printf("  Log level: %s\n", ctx.Config.Preferences.LogLevel)
+		fmt.Printf("  Default output: %s\n", ctx.Config.Preferences.DefaultOutput)
 
 		fmt.Println("\nCredentials:")
 		if ctx.Config.Credentials.Username != "" {
@@ -85,7 +117,7 @@ var configShowCmd = &cobra.Command{
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a configuration value",
-	Long:  "Set a configuration value. Supported keys: api.base_url, api.source, api.timeout, preferences.log_level",
+	Long:  "Set a configuration value. Supported keys: api.base_url, api.source, api.timeout, preferences.log_level, preferences.default_output",
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
@@ -99,6 +131,11 @@ var configSetCmd = &cobra.Command{
 			ctx.Config.API.Source = value
 		case "preferences.log_level":
 			ctx.Config.Preferences.LogLevel = value
+		case "preferences.default_output":
+			ctx.Config.Preferences.DefaultOutput = value
+			if err := ctx.Config.Validate(); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unsupported configuration key: %s", key)
 		}
@@ -113,12 +150,54 @@ var configSetCmd = &cobra.Command{
 	},
 }
 
+var configLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the configuration for risky settings",
+	Long: `Validate the configuration and flag settings that are valid but dangerous:
+request rates above RADb guidance, state locking disabled where a daemon
+runs, cache and history sharing a directory, and state directories other
+users can read. Each finding has a severity and an explanation.
+
+Exits non-zero when any finding is an error, or with --strict any finding
+at all.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		// A daemon heartbeat means the state directory is shared with a daemon
+		daemonStatus, err := state.LoadDaemonStatus(ctx.Config.StateDir())
+		if err != nil {
+			ctx.Logger.Debugf("Failed to read daemon status: %v", err)
+		}
+
+		findings := ctx.Config.Lint(daemonStatus != nil)
+		if len(findings) == 0 && OutputFormat(outputFormat) == OutputFormatTable {
+			fmt.Println("No problems found")
+			return nil
+		}
+
+		outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+		if err := outputter.RenderLintFindings(findings); err != nil {
+			return err
+		}
+
+		if config.HasErrors(findings) || (strict && len(findings) > 0) {
+			return fmt.Errorf("configuration has %d problems", len(findings))
+		}
+		return nil
+	},
+}
+
 func init() {
 	// Add flags to commands
 	configInitCmd.Flags().Bool("force", false, "Overwrite existing configuration")
+	configLintCmd.Flags().StringP("output", "o", "table", "Output format (table, json, yaml)")
+	configLintCmd.Flags().Bool("strict", false, "Also fail on warnings")
 
 	// Register subcommands
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configLintCmd)
 }
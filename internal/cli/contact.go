@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/bss/radb-client/internal/models"
@@ -24,6 +23,11 @@ func NewContactCmd(logger *logrus.Logger) *cobra.Command {
 		newContactCreateCmd(logger),
 		newContactUpdateCmd(logger),
 		newContactDeleteCmd(logger),
+		newContactAnnotateCmd(logger),
+		newContactExportCmd(logger),
+		newContactImportCmd(logger),
+		newContactAuditCmd(logger),
+		newContactReassignCmd(logger),
 	)
 
 	return cmd
@@ -38,15 +42,21 @@ func newContactListCmd(logger *logrus.Logger) *cobra.Command {
 		Aliases: []string{"ls"},
 		Short:   "List all contacts",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
-
-			// Use shared API client (already authenticated)
-			contacts, err := ctx.APIClient.ListContacts(cmdCtx)
-			if err != nil {
-				return fmt.Errorf("failed to list contacts: %w", err)
+			cmdCtx := cmd.Context()
+
+			// A daemon on this host answers from its snapshots; otherwise use
+			// shared API client (already authenticated)
+			contacts, fromDaemon := daemonContacts(cmdCtx, logger)
+			if !fromDaemon {
+				var err error
+				contacts, err = ctx.APIClient.ListContacts(cmdCtx)
+				if err != nil {
+					return fmt.Errorf("failed to list contacts: %w", err)
+				}
 			}
 
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			outputter.SetAnnotations(loadAnnotations(cmdCtx, logger))
 			return outputter.RenderContacts(contacts)
 		},
 	}
@@ -60,12 +70,15 @@ func newContactShowCmd(logger *logrus.Logger) *cobra.Command {
 	var outputFormat string
 
 	cmd := &cobra.Command{
-		Use:   "show <id>",
+		Use:   "show <id|urn>",
 		Short: "Show a specific contact",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
-			id := args[0]
+			cmdCtx := cmd.Context()
+			id, err := contactArg(args[0])
+			if err != nil {
+				return err
+			}
 
 			// Use shared API client (already authenticated)
 			contact, err := ctx.APIClient.GetContact(cmdCtx, id)
@@ -76,11 +89,14 @@ func newContactShowCmd(logger *logrus.Logger) *cobra.Command {
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
 			switch outputFormat {
 			case "json":
-				return outputter.renderJSON(contact)
+				return outputter.renderJSON(newContactView(contact))
 			case "yaml":
-				return outputter.renderYAML(contact)
+				return outputter.renderYAML(newContactView(contact))
+			case "rpsl":
+				fmt.Print(contact.ToRPSL())
 			default:
 				fmt.Printf("ID: %s\n", contact.ID)
+				fmt.Printf("Reference: %s\n", contact.URN())
 				fmt.Printf("Name: %s\n", contact.Name)
 				fmt.Printf("Email: %s\n", contact.Email)
 				fmt.Printf("Role: %s\n", contact.Role)
@@ -90,13 +106,17 @@ func newContactShowCmd(logger *logrus.Logger) *cobra.Command {
 				if contact.Organization != "" {
 					fmt.Printf("Organization: %s\n", contact.Organization)
 				}
+				if note := loadAnnotations(cmdCtx, logger).Label("contact", contact.ID); note != "" {
+					fmt.Printf("Annotation: %s\n", note)
+				}
 			}
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml, rpsl)")
+	addCopyFlag(cmd)
 	return cmd
 }
 
@@ -109,13 +129,14 @@ func newContactCreateCmd(logger *logrus.Logger) *cobra.Command {
 		phone   string
 		org     string
 		address []string
+		dryRun  bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new contact",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 
 			contact := &models.Contact{
 				Name:         name,
@@ -130,11 +151,21 @@ func newContactCreateCmd(logger *logrus.Logger) *cobra.Command {
 				return fmt.Errorf("contact validation failed: %w", err)
 			}
 
+			if dryRun {
+				if err := enableDryRun(); err != nil {
+					return err
+				}
+			}
+
 			// Use shared API client (already authenticated)
 			if err := ctx.APIClient.CreateContact(cmdCtx, contact); err != nil {
 				return fmt.Errorf("failed to create contact: %w", err)
 			}
 
+			if dryRun {
+				fmt.Printf("Dry run: contact %s was not created\n", contact.Name)
+				return nil
+			}
 			fmt.Printf("Successfully created contact %s\n", contact.ID)
 			return nil
 		},
@@ -146,6 +177,7 @@ func newContactCreateCmd(logger *logrus.Logger) *cobra.Command {
 	cmd.Flags().StringVar(&phone, "phone", "", "Contact phone")
 	cmd.Flags().StringVar(&org, "org", "", "Organization")
 	cmd.Flags().StringSliceVar(&address, "address", nil, "Address lines")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and show the request without sending it")
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("email")
 
@@ -155,11 +187,12 @@ func newContactCreateCmd(logger *logrus.Logger) *cobra.Command {
 // newContactUpdateCmd creates the contact update command.
 func newContactUpdateCmd(logger *logrus.Logger) *cobra.Command {
 	var (
-		name  string
-		email string
-		role  string
-		phone string
-		org   string
+		name   string
+		email  string
+		role   string
+		phone  string
+		org    string
+		dryRun bool
 	)
 
 	cmd := &cobra.Command{
@@ -167,7 +200,7 @@ func newContactUpdateCmd(logger *logrus.Logger) *cobra.Command {
 		Short: "Update an existing contact",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			id := args[0]
 
 			// Use shared API client (already authenticated)
@@ -192,10 +225,20 @@ func newContactUpdateCmd(logger *logrus.Logger) *cobra.Command {
 				contact.Organization = org
 			}
 
+			if dryRun {
+				if err := enableDryRun(); err != nil {
+					return err
+				}
+			}
+
 			if err := ctx.APIClient.UpdateContact(cmdCtx, contact); err != nil {
 				return fmt.Errorf("failed to update contact: %w", err)
 			}
 
+			if dryRun {
+				fmt.Printf("Dry run: contact %s was not updated\n", contact.ID)
+				return nil
+			}
 			fmt.Printf("Successfully updated contact %s\n", contact.ID)
 			return nil
 		},
@@ -206,24 +249,61 @@ func newContactUpdateCmd(logger *logrus.Logger) *cobra.Command {
 	cmd.Flags().StringVar(&role, "role", "", "Contact role")
 	cmd.Flags().StringVar(&phone, "phone", "", "Contact phone")
 	cmd.Flags().StringVar(&org, "org", "", "Organization")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and show the request without sending it")
 
 	return cmd
 }
 
 // newContactDeleteCmd creates the contact delete command.
 func newContactDeleteCmd(logger *logrus.Logger) *cobra.Command {
-	var confirm bool
+	var (
+		confirm bool
+		dryRun  bool
+		rpsl    bool
+		reason  string
+		auth    string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "delete <id>",
 		Short: "Delete a contact",
-		Args:  cobra.ExactArgs(1),
+		Long: `Delete a contact through the API.
+
+With --rpsl, nothing is deleted: the contact is fetched and printed as an
+RPSL deletion (the contact followed by a delete: attribute carrying
+--reason) for submission by mail. With --auth password, the stored password
+of the configured user is appended as a password: attribute.`,
+		Example: `  radb-client contact delete ADMIN-1 --confirm
+  radb-client contact delete ADMIN-1 --rpsl --reason "left the company" --auth password > delete.txt`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			id := args[0]
 
-			if !confirm {
-				return fmt.Errorf("please confirm deletion with --confirm flag")
+			if rpsl {
+				if err := validateRPSLAuth(auth); err != nil {
+					return err
+				}
+				existing, err := ctx.APIClient.GetContact(cmdCtx, id)
+				if err != nil {
+					return fmt.Errorf("failed to get contact: %w", err)
+				}
+				submission, err := authenticateRPSL(existing.ToRPSLDelete(reason), auth)
+				if err != nil {
+					return err
+				}
+				fmt.Print(submission)
+				return nil
+			}
+
+			if !confirm && !dryRun {
+				return fmt.Errorf("please confirm deletion with --confirm flag, or preview with --dry-run")
+			}
+
+			if dryRun {
+				if err := enableDryRun(); err != nil {
+					return err
+				}
 			}
 
 			// Use shared API client (already authenticated)
@@ -231,11 +311,19 @@ func newContactDeleteCmd(logger *logrus.Logger) *cobra.Command {
 				return fmt.Errorf("failed to delete contact: %w", err)
 			}
 
+			if dryRun {
+				fmt.Printf("Dry run: contact %s was not deleted\n", id)
+				return nil
+			}
 			fmt.Printf("Successfully deleted contact %s\n", id)
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&confirm, "confirm", false, "Confirm deletion")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and show the request without sending it")
+	cmd.Flags().BoolVar(&rpsl, "rpsl", false, "Print an RPSL deletion for mail submission instead of deleting")
+	cmd.Flags().StringVar(&reason, "reason", "", "Reason recorded in the delete: attribute (with --rpsl)")
+	cmd.Flags().StringVar(&auth, "auth", rpslAuthNone, "Authentication added to the RPSL deletion: none or password (with --rpsl)")
 	return cmd
 }
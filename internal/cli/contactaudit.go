@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newContactAuditCmd creates the contact audit command.
+func newContactAuditCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit contacts against policy",
+	}
+
+	cmd.AddCommand(newContactAuditCoverageCmd(logger))
+
+	return cmd
+}
+
+// newContactAuditCoverageCmd creates the contact audit coverage command.
+func newContactAuditCoverageCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		require      []string
+		checkEmail   string
+		helo         string
+		timeout      time.Duration
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Find maintainers and aut-nums without required contacts",
+		Long: `Cross-reference the maintainers and origin ASNs (aut-nums) of your routes
+with contacts and report objects missing a contact in a required role.
+
+A contact covers the objects listed in its mnt-by and aut-num attributes;
+a contact with neither attribute is account-wide and covers every object.
+Required roles come from audit.required_contact_roles (default abuse and
+tech) or --require.
+
+--check-email mx verifies that each contact's email domain accepts mail.
+--check-email smtp also asks the domain's mail servers whether they accept
+the address, without sending a message; many servers refuse to answer, and
+outbound port 25 is often blocked, so treat failures as hints.
+
+The command exits non-zero when it finds any gap.`,
+		Example: `  radb-client contact audit coverage
+  radb-client contact audit coverage --require abuse,tech,admin --check-email mx
+  radb-client contact audit coverage -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			if len(require) == 0 {
+				require = ctx.Config.Audit.RequiredContactRoles
+			}
+			roles := make([]models.ContactRole, 0, len(require))
+			for _, role := range require {
+				contactRole := models.ContactRole(role)
+				probe := models.Contact{Name: "-", Email: "-", Role: contactRole}
+				if err := probe.Validate(); err != nil {
+					return fmt.Errorf("invalid required role: %w", err)
+				}
+				roles = append(roles, contactRole)
+			}
+
+			opts := audit.CoverageOptions{RequiredRoles: roles}
+			switch checkEmail {
+			case "", "none":
+			case "mx":
+				opts.CheckEmail = audit.MXChecker(nil)
+			case "smtp":
+				if helo == "" {
+					helo, _ = os.Hostname()
+				}
+				opts.CheckEmail = audit.SMTPChecker(nil, helo, timeout)
+			default:
+				return fmt.Errorf("unsupported email check %q (use none, mx, or smtp)", checkEmail)
+			}
+
+			routes, err := ctx.APIClient.ListRoutes(cmdCtx, nil)
+			if err != nil {
+				return fmt.Errorf("failed to list routes: %w", err)
+			}
+			contacts, err := ctx.APIClient.ListContacts(cmdCtx)
+			if err != nil {
+				return fmt.Errorf("failed to list contacts: %w", err)
+			}
+			logger.Debugf("Auditing coverage of %d routes by %d contacts", routes.Count, contacts.Count)
+
+			report := audit.Coverage(cmdCtx, routes.Routes, contacts.Contacts, opts)
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if err := outputter.RenderCoverage(report); err != nil {
+				return err
+			}
+
+			if len(report.Findings) > 0 {
+				return fmt.Errorf("contact coverage audit found %d problems", len(report.Findings))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&require, "require", nil, "Required contact roles (default from audit.required_contact_roles)")
+	cmd.Flags().StringVar(&checkEmail, "check-email", "none", "Check contact email reachability (none, mx, smtp)")
+	cmd.Flags().StringVar(&helo, "helo", "", "Host name announced for --check-email smtp (default this host)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 10*time.Second, "Timeout per mail server for --check-email smtp")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/directory"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newContactExportCmd creates the contact export command.
+func newContactExportCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		format string
+		baseDN string
+		file   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export contacts as vCard or LDIF",
+		Long: `Export all contacts as vCard 3.0 or LDIF (inetOrgPerson entries) for
+import into a corporate directory. The contact ID is exported as the vCard
+UID or LDAP uid, so re-importing updates contacts rather than duplicating them.`,
+		Example: `  radb-client contact export --format vcard --file contacts.vcf
+  radb-client contact export --format ldif --base-dn "ou=radb,dc=example,dc=com" > contacts.ldif`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			if format != "vcard" && format != "ldif" {
+				return fmt.Errorf("unsupported export format: %s (expected vcard or ldif)", format)
+			}
+
+			contacts, err := ctx.APIClient.ListContacts(cmdCtx)
+			if err != nil {
+				return fmt.Errorf("failed to list contacts: %w", err)
+			}
+
+			people := make([]directory.Person, len(contacts.Contacts))
+			for i := range contacts.Contacts {
+				people[i] = contactToPerson(&contacts.Contacts[i])
+			}
+
+			var w io.Writer = os.Stdout
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", file, err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if format == "ldif" {
+				err = directory.WriteLDIF(w, people, baseDN)
+			} else {
+				err = directory.WriteVCards(w, people)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+
+			if file != "" {
+				fmt.Fprintf(os.Stderr, "Exported %d contacts to %s\n", len(people), file)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "vcard", "Export format (vcard, ldif)")
+	cmd.Flags().StringVar(&baseDN, "base-dn", "", "Base DN for LDIF entries, e.g. ou=radb,dc=example,dc=com")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write to a file instead of standard output")
+
+	return cmd
+}
+
+// newContactImportCmd creates the contact import command.
+func newContactImportCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		defaultRole string
+		dryRun      bool
+		workers     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file.vcf>",
+		Short: "Create or update contacts from a vCard file",
+		Long: `Create or update contacts from a vCard file. Each card is matched to an
+existing contact by UID (contact ID) and then by email address; matched
+contacts are updated, the rest are created. Cards without a recognized
+ROLE get the --role default.`,
+		Example: `  radb-client contact import directory-export.vcf --dry-run
+  radb-client contact import directory-export.vcf --role admin`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", args[0], err)
+			}
+			defer f.Close()
+
+			people, err := directory.ReadVCards(f)
+			if err != nil {
+				return fmt.Errorf("failed to parse %s: %w", args[0], err)
+			}
+
+			existing, err := ctx.APIClient.ListContacts(cmdCtx)
+			if err != nil {
+				return fmt.Errorf("failed to list contacts: %w", err)
+			}
+
+			plan, err := planContactImport(people, existing, models.ContactRole(defaultRole))
+			if err != nil {
+				return err
+			}
+
+			for _, contact := range plan.creates {
+				fmt.Printf("  + %s <%s>\n", contact.Name, contact.Email)
+			}
+			for _, contact := range plan.updates {
+				fmt.Printf("  ~ %s %s <%s>\n", contact.ID, contact.Name, contact.Email)
+			}
+			fmt.Printf("\n%d to create, %d to update, %d unchanged\n", len(plan.creates), len(plan.updates), plan.unchanged)
+
+			if dryRun || (len(plan.creates) == 0 && len(plan.updates) == 0) {
+				return nil
+			}
+
+			var failed error
+			if len(plan.creates) > 0 {
+				journal := models.NewBulkJournal(models.BulkCreateContacts)
+				for _, contact := range plan.creates {
+					journal.AddContact(contact)
+				}
+				result, err := startBulk(cmdCtx, logger, journal, workers)
+				if err != nil {
+					return fmt.Errorf("failed to create contacts: %w", err)
+				}
+				if err := reportBulkResult("Created", "contacts", result); err != nil {
+					failed = err
+				}
+			}
+			if len(plan.updates) > 0 {
+				journal := models.NewBulkJournal(models.BulkUpdateContacts)
+				for _, contact := range plan.updates {
+					journal.AddContact(contact)
+				}
+				result, err := startBulk(cmdCtx, logger, journal, workers)
+				if err != nil {
+					return fmt.Errorf("failed to update contacts: %w", err)
+				}
+				if err := reportBulkResult("Updated", "contacts", result); err != nil {
+					failed = err
+				}
+			}
+
+			return failed
+		},
+	}
+
+	cmd.Flags().StringVar(&defaultRole, "role", string(models.ContactRoleTech), "Role for cards without a recognized ROLE (admin, tech, billing, abuse)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created and updated without applying it")
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel workers (default from performance.max_concurrent_requests)")
+
+	return cmd
+}
+
+// contactImportPlan is the set of changes needed to import a vCard file.
+type contactImportPlan struct {
+	creates   []*models.Contact
+	updates   []*models.Contact
+	unchanged int
+}
+
+// planContactImport matches imported people to existing contacts by ID and
+// then by email, and validates every resulting contact.
+func planContactImport(people []directory.Person, existing *models.ContactList, defaultRole models.ContactRole) (*contactImportPlan, error) {
+	byID := existing.ByID()
+	byEmail := make(map[string]*models.Contact, len(existing.Contacts))
+	for i := range existing.Contacts {
+		contact := &existing.Contacts[i]
+		byEmail[strings.ToLower(contact.Email)] = contact
+	}
+
+	plan := &contactImportPlan{}
+	for i, person := range people {
+		match := byID[person.UID]
+		if match == nil {
+			match = byEmail[strings.ToLower(person.Email)]
+		}
+
+		contact := personToContact(person, match, defaultRole)
+		if err := contact.Validate(); err != nil {
+			return nil, fmt.Errorf("card %d (%s): %w", i+1, person.Name, err)
+		}
+
+		switch {
+		case match == nil:
+			plan.creates = append(plan.creates, contact)
+		case reflect.DeepEqual(contact, match):
+			plan.unchanged++
+		default:
+			plan.updates = append(plan.updates, contact)
+		}
+	}
+
+	return plan, nil
+}
+
+// contactToPerson converts a contact to a directory entry.
+func contactToPerson(contact *models.Contact) directory.Person {
+	return directory.Person{
+		UID:          contact.ID,
+		Name:         contact.Name,
+		Email:        contact.Email,
+		Phone:        contact.Phone,
+		Organization: contact.Organization,
+		Role:         string(contact.Role),
+		Address:      contact.Address,
+	}
+}
+
+// personToContact converts a directory entry to a contact. When updating an
+// existing contact, attributes missing from the entry are kept.
+func personToContact(person directory.Person, existing *models.Contact, defaultRole models.ContactRole) *models.Contact {
+	contact := &models.Contact{Role: defaultRole}
+	if existing != nil {
+		copied := *existing
+		contact = &copied
+	}
+
+	contact.Name = person.Name
+	if person.Email != "" {
+		contact.Email = person.Email
+	}
+	if person.Phone != "" {
+		contact.Phone = person.Phone
+	}
+	if person.Organization != "" {
+		contact.Organization = person.Organization
+	}
+	if len(person.Address) > 0 {
+		contact.Address = person.Address
+	}
+
+	switch role := models.ContactRole(strings.ToLower(person.Role)); role {
+	case models.ContactRoleAdmin, models.ContactRoleTech, models.ContactRoleBilling, models.ContactRoleAbuse:
+		contact.Role = role
+	}
+
+	return contact
+}
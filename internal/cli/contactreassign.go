@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// emailReplacer rewrites one email address wherever it appears in attribute
+// values, matching case-insensitively and only as a whole address.
+type emailReplacer struct {
+	pattern *regexp.Regexp
+	to      string
+}
+
+// newEmailReplacer creates a replacer from one address to another.
+func newEmailReplacer(from, to string) *emailReplacer {
+	return &emailReplacer{pattern: regexp.MustCompile(`(?i)` + regexp.QuoteMeta(from)), to: to}
+}
+
+// replace returns the value with the address replaced. Matches that are part
+// of a longer address, such as bold@corp.com for old@corp.com, are kept.
+func (r *emailReplacer) replace(value string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range r.pattern.FindAllStringIndex(value, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && isLocalPartChar(value[start-1]) {
+			continue
+		}
+		if end < len(value) && isDomainChar(value[end]) {
+			continue
+		}
+		if end+1 < len(value) && value[end] == '.' && isDomainChar(value[end+1]) {
+			continue
+		}
+		b.WriteString(value[last:start])
+		b.WriteString(r.to)
+		last = end
+	}
+	if last == 0 {
+		return value
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+// isLocalPartChar reports whether c can appear in the local part of an
+// address.
+func isLocalPartChar(c byte) bool {
+	return isDomainChar(c) || strings.IndexByte("._%+", c) >= 0
+}
+
+// isDomainChar reports whether c can continue a domain name label.
+func isDomainChar(c byte) bool {
+	return c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// replaceLines returns lines with the address replaced, or nil if no line
+// referenced it.
+func (r *emailReplacer) replaceLines(lines []string) []string {
+	var replaced []string
+	for i, line := range lines {
+		if updated := r.replace(line); updated != line {
+			if replaced == nil {
+				replaced = slices.Clone(lines)
+			}
+			replaced[i] = updated
+		}
+	}
+	return replaced
+}
+
+// replaceAttributes returns a copy of attrs with the address replaced, or
+// nil if no attribute referenced it.
+func (r *emailReplacer) replaceAttributes(attrs map[string][]string) map[string][]string {
+	var replaced map[string][]string
+	for key, values := range attrs {
+		if updated := r.replaceLines(values); updated != nil {
+			if replaced == nil {
+				replaced = maps.Clone(attrs)
+			}
+			replaced[key] = updated
+		}
+	}
+	return replaced
+}
+
+// contactEdit is a pending change to a single contact.
+type contactEdit struct {
+	Before *models.Contact
+	After  *models.Contact
+}
+
+// planContactReassign returns the changes that move contacts from the old
+// address to the new one. A non-empty role limits the plan to contacts in
+// that role.
+func planContactReassign(contacts []models.Contact, r *emailReplacer, from string, role models.ContactRole) []contactEdit {
+	var edits []contactEdit
+	for i := range contacts {
+		before := &contacts[i]
+		if role != "" && before.Role != role {
+			continue
+		}
+
+		after := *before
+		changed := false
+		if strings.EqualFold(before.Email, from) {
+			after.Email = r.to
+			changed = true
+		}
+		if attrs := r.replaceAttributes(before.RawAttributes); attrs != nil {
+			after.RawAttributes = attrs
+			changed = true
+		}
+		if changed {
+			edits = append(edits, contactEdit{Before: before, After: &after})
+		}
+	}
+	return edits
+}
+
+// planRouteReassign returns the changes that replace the old address in
+// route descriptions, remarks, and other attributes such as notify.
+func planRouteReassign(routes []models.RouteObject, r *emailReplacer) []routeEdit {
+	var edits []routeEdit
+	for i := range routes {
+		before := &routes[i]
+		after := *before
+		changed := false
+		if lines := r.replaceLines(before.Descr); lines != nil {
+			after.Descr = lines
+			changed = true
+		}
+		if lines := r.replaceLines(before.Remarks); lines != nil {
+			after.Remarks = lines
+			changed = true
+		}
+		if attrs := r.replaceAttributes(before.RawAttributes); attrs != nil {
+			after.RawAttributes = attrs
+			changed = true
+		}
+		if changed {
+			edits = append(edits, routeEdit{Before: before, After: &after})
+		}
+	}
+	return edits
+}
+
+// printRawAttrDiff prints the changed additional attributes, in key order.
+func printRawAttrDiff(before, after map[string][]string) {
+	for _, key := range slices.Sorted(maps.Keys(after)) {
+		printAttrDiff(key, before[key], after[key])
+	}
+}
+
+// newContactReassignCmd creates the contact reassign command.
+func newContactReassignCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		from    string
+		to      string
+		role    string
+		dryRun  bool
+		confirm bool
+		workers int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reassign",
+		Short: "Move every reference to an email address to a new one",
+		Long: `Find every contact and route object that references an email address and
+replace it with another, for example when someone leaves the organization.
+
+Contacts are matched on their email and additional attributes; routes on
+their descr, remarks, and additional attributes such as notify. Addresses
+match case-insensitively and only as a whole address, so old@corp.com does
+not match bold@corp.com.
+
+With --role, only contacts in that role are reassigned and routes are left
+alone, since their references carry no role.
+
+The computed plan is shown before anything is written; pass --confirm to
+apply it through the batch API.`,
+		Example: `  # Review the plan
+  radb-client contact reassign --from old@corp.com --to new@corp.com --dry-run
+
+  # Hand over only the technical contacts
+  radb-client contact reassign --from old@corp.com --to new@corp.com --role tech --confirm`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			if strings.EqualFold(from, to) {
+				return fmt.Errorf("--from and --to are the same address")
+			}
+			if !strings.Contains(from, "@") || !strings.Contains(to, "@") {
+				return fmt.Errorf("--from and --to must be email addresses")
+			}
+			switch models.ContactRole(role) {
+			case "", models.ContactRoleAdmin, models.ContactRoleTech, models.ContactRoleBilling, models.ContactRoleAbuse:
+			default:
+				return fmt.Errorf("invalid contact role: %s", role)
+			}
+
+			replacer := newEmailReplacer(from, to)
+
+			contacts, err := ctx.APIClient.ListContacts(cmdCtx)
+			if err != nil {
+				return fmt.Errorf("failed to list contacts: %w", err)
+			}
+			contactEdits := planContactReassign(contacts.Contacts, replacer, from, models.ContactRole(role))
+
+			var routeEdits []routeEdit
+			if role == "" {
+				routes, err := ctx.APIClient.ListRoutes(cmdCtx, nil)
+				if err != nil {
+					return fmt.Errorf("failed to list routes: %w", err)
+				}
+				routeEdits = planRouteReassign(routes.Routes, replacer)
+			}
+			logger.Debugf("Reassigning %s to %s: %d contacts, %d routes", from, to, len(contactEdits), len(routeEdits))
+
+			// Show plan
+			for _, edit := range contactEdits {
+				fmt.Printf("contact %s (%s, %s)\n", edit.Before.ID, edit.Before.Name, edit.Before.Role)
+				printAttrDiff("email", []string{edit.Before.Email}, []string{edit.After.Email})
+				printRawAttrDiff(edit.Before.RawAttributes, edit.After.RawAttributes)
+			}
+			for _, edit := range routeEdits {
+				fmt.Printf("route %s\n", edit.Before.ID())
+				printAttrDiff("descr", edit.Before.Descr, edit.After.Descr)
+				printAttrDiff("remarks", edit.Before.Remarks, edit.After.Remarks)
+				printRawAttrDiff(edit.Before.RawAttributes, edit.After.RawAttributes)
+			}
+			fmt.Printf("\n%d contacts and %d routes reference %s\n", len(contactEdits), len(routeEdits), from)
+
+			if len(contactEdits)+len(routeEdits) == 0 || dryRun {
+				return nil
+			}
+			if !confirm {
+				return fmt.Errorf("re-run with --confirm to apply these changes")
+			}
+
+			contactJournal := models.NewBulkJournal(models.BulkUpdateContacts)
+			for _, edit := range contactEdits {
+				contactJournal.AddContact(edit.After)
+			}
+			routeJournal := models.NewBulkJournal(models.BulkUpdateRoutes)
+			for _, edit := range routeEdits {
+				routeJournal.AddRoute(edit.After)
+			}
+
+			return submitJournals(cmdCtx, logger, []*models.BulkJournal{contactJournal, routeJournal}, workers)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Address to replace")
+	cmd.Flags().StringVar(&to, "to", "", "Replacement address")
+	cmd.Flags().StringVar(&role, "role", "", "Only reassign contacts in this role (admin, tech, billing, abuse)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the plan without applying it")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Apply the plan")
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel update workers (default from performance.max_concurrent_requests)")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestEmailReplacerReplace(t *testing.T) {
+	r := newEmailReplacer("old@corp.com", "new@corp.com")
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"whole value", "old@corp.com", "new@corp.com"},
+		{"within text", "Contact old@corp.com for peering", "Contact new@corp.com for peering"},
+		{"angle brackets", "NOC <old@corp.com>", "NOC <new@corp.com>"},
+		{"sentence end", "Mail old@corp.com.", "Mail new@corp.com."},
+		{"upper case", "OLD@CORP.COM", "new@corp.com"},
+		{"mixed case", "Old@Corp.Com", "new@corp.com"},
+		{"two occurrences", "old@corp.com, cc old@corp.com", "new@corp.com, cc new@corp.com"},
+		{"one of two addresses", "xold@corp.com, old@corp.com", "xold@corp.com, new@corp.com"},
+		{"longer local part", "xold@corp.com", "xold@corp.com"},
+		{"local part with dot", "b.old@corp.com", "b.old@corp.com"},
+		{"local part with plus", "tag+old@corp.com", "tag+old@corp.com"},
+		{"longer domain", "old@corp.com.au", "old@corp.com.au"},
+		{"longer label", "old@corp.community", "old@corp.community"},
+		{"hyphenated domain", "old@corp.com-mail.net", "old@corp.com-mail.net"},
+		{"other address", "someone@corp.com", "someone@corp.com"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.replace(tt.value); got != tt.want {
+				t.Errorf("replace(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddressChars(t *testing.T) {
+	tests := []struct {
+		c          byte
+		localPart  bool
+		domainPart bool
+	}{
+		{'a', true, true},
+		{'Z', true, true},
+		{'7', true, true},
+		{'-', true, true},
+		{'.', true, false},
+		{'_', true, false},
+		{'%', true, false},
+		{'+', true, false},
+		{'@', false, false},
+		{' ', false, false},
+		{',', false, false},
+		{'<', false, false},
+	}
+
+	for _, tt := range tests {
+		if got := isLocalPartChar(tt.c); got != tt.localPart {
+			t.Errorf("isLocalPartChar(%q) = %v, want %v", tt.c, got, tt.localPart)
+		}
+		if got := isDomainChar(tt.c); got != tt.domainPart {
+			t.Errorf("isDomainChar(%q) = %v, want %v", tt.c, got, tt.domainPart)
+		}
+	}
+}
+
+func TestPlanContactReassign(t *testing.T) {
+	contacts := []models.Contact{
+		{ID: "C-1", Email: "old@corp.com", Role: models.ContactRoleTech},
+		{ID: "C-2", Email: "OLD@corp.com", Role: models.ContactRoleAdmin},
+		{ID: "C-3", Email: "noc@corp.com", Role: models.ContactRoleTech, RawAttributes: map[string][]string{"notify": {"old@corp.com"}}},
+		{ID: "C-4", Email: "xold@corp.com", Role: models.ContactRoleTech},
+		{ID: "C-5", Email: "old@corp.com.au", Role: models.ContactRoleAbuse},
+	}
+	r := newEmailReplacer("old@corp.com", "new@corp.com")
+
+	tests := []struct {
+		name string
+		role models.ContactRole
+		want []string
+	}{
+		{"all roles", "", []string{"C-1", "C-2", "C-3"}},
+		{"tech only", models.ContactRoleTech, []string{"C-1", "C-3"}},
+		{"admin only", models.ContactRoleAdmin, []string{"C-2"}},
+		{"no matching contacts", models.ContactRoleBilling, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := planContactReassign(contacts, r, "old@corp.com", tt.role)
+
+			var ids []string
+			for _, edit := range edits {
+				ids = append(ids, edit.After.ID)
+				if edit.After.Email == "old@corp.com" || edit.After.Email == "OLD@corp.com" {
+					t.Errorf("contact %s keeps email %s", edit.After.ID, edit.After.Email)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("planned contacts = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	edits := planContactReassign(contacts, r, "old@corp.com", models.ContactRoleTech)
+	notify := edits[1]
+	if notify.After.Email != "noc@corp.com" || notify.After.RawAttributes["notify"][0] != "new@corp.com" {
+		t.Errorf("contact C-3 = %+v, want only its notify attribute reassigned", notify.After)
+	}
+	if contacts[2].RawAttributes["notify"][0] != "old@corp.com" {
+		t.Error("planning modified the original contact's attributes")
+	}
+}
+
+func TestPlanRouteReassign(t *testing.T) {
+	routes := []models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"Peering: old@corp.com, escalation old@corp.com"}},
+		{Route: "198.51.100.0/24", Origin: "AS64500", Remarks: []string{"Abuse: OLD@CORP.COM"}},
+		{Route: "203.0.113.0/24", Origin: "AS64500", RawAttributes: map[string][]string{"notify": {"old@corp.com"}}},
+		{Route: "192.0.2.128/25", Origin: "AS64500", Descr: []string{"Ask xold@corp.com or old@corp.com.au"}},
+	}
+	r := newEmailReplacer("old@corp.com", "new@corp.com")
+
+	edits := planRouteReassign(routes, r)
+	if len(edits) != 3 {
+		t.Fatalf("planned %d route edits, want 3", len(edits))
+	}
+	if got := edits[0].After.Descr[0]; got != "Peering: new@corp.com, escalation new@corp.com" {
+		t.Errorf("descr = %q, want both occurrences replaced", got)
+	}
+	if got := edits[1].After.Remarks[0]; got != "Abuse: new@corp.com" {
+		t.Errorf("remarks = %q, want the upper-case address replaced", got)
+	}
+	if got := edits[2].After.RawAttributes["notify"][0]; got != "new@corp.com" {
+		t.Errorf("notify = %q, want it replaced", got)
+	}
+	if routes[0].Descr[0] != "Peering: old@corp.com, escalation old@corp.com" {
+		t.Error("planning modified the original route")
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+)
+
+// setTestContext points the global CLI context at an in-memory API client
+// and a file state manager in a temporary directory, restoring the previous
+// context when the test ends.
+func setTestContext(t *testing.T) (*api.MemoryClient, *logrus.Logger) {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	dir := t.TempDir()
+	mgr, err := state.NewFileManager(dir, logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	t.Cleanup(func() { mgr.Close() })
+
+	cfg := &config.Config{}
+	cfg.Preferences.CacheDir = dir
+	cfg.Performance.MaxConcurrentRequests = 2
+	client := api.NewMemoryClient("RADB", logger)
+
+	saved := ctx
+	t.Cleanup(func() { ctx = saved })
+	ctx = CLIContext{Config: cfg, APIClient: client, StateMgr: mgr, Logger: logger}
+
+	return client, logger
+}
+
+// recordingClient records the writes made through it, calling beforeWrite,
+// if set, ahead of each one.
+type recordingClient struct {
+	api.Client
+	beforeWrite func()
+
+	mu     sync.Mutex
+	writes []string
+}
+
+// record notes a write of an object.
+func (c *recordingClient) record(operation, id string) {
+	if c.beforeWrite != nil {
+		c.beforeWrite()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes = append(c.writes, operation+" "+id)
+}
+
+// Writes returns the writes made so far.
+func (c *recordingClient) Writes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.writes...)
+}
+
+func (c *recordingClient) CreateRoute(cmdCtx context.Context, route *models.RouteObject) error {
+	c.record("create", route.ID())
+	return c.Client.CreateRoute(cmdCtx, route)
+}
+
+func (c *recordingClient) UpdateRoute(cmdCtx context.Context, route *models.RouteObject) error {
+	c.record("update", route.ID())
+	return c.Client.UpdateRoute(cmdCtx, route)
+}
+
+func (c *recordingClient) DeleteRoute(cmdCtx context.Context, prefix, asn string) error {
+	c.record("delete", prefix+"-"+asn)
+	return c.Client.DeleteRoute(cmdCtx, prefix, asn)
+}
+
+func (c *recordingClient) CreateContact(cmdCtx context.Context, contact *models.Contact) error {
+	c.record("create", contact.ID)
+	return c.Client.CreateContact(cmdCtx, contact)
+}
+
+func (c *recordingClient) UpdateContact(cmdCtx context.Context, contact *models.Contact) error {
+	c.record("update", contact.ID)
+	return c.Client.UpdateContact(cmdCtx, contact)
+}
+
+func (c *recordingClient) DeleteContact(cmdCtx context.Context, id string) error {
+	c.record("delete", id)
+	return c.Client.DeleteContact(cmdCtx, id)
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/daemon"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// lockDaemonState marks this process as the daemon of the state directory
+// with the advisory daemon lock and its PID file, and returns the function
+// that releases them. It fails when another daemon holds the directory, since
+// two daemons would race on its snapshots and changelog.
+func lockDaemonState(stateDir string) (func(), error) {
+	unlock, err := state.LockDaemon(stateDir)
+	if errors.Is(err, state.ErrDaemonRunning) {
+		return nil, fmt.Errorf("%w; stop it with 'radb-client daemon stop' or point this one at another preferences.cache_dir", err)
+	}
+	return unlock, err
+}
+
+// coordinateDaemon serves reads to CLI commands on the local socket, with
+// daemon.local_socket, from cache, or from a read cache of its own when
+// cache is nil. The caller holds the daemon lock. The returned function
+// stops serving.
+func coordinateDaemon(runner *daemon.Runner, cache *daemon.ReadCache) func() {
+	cfg := ctx.Config
+	stateDir := cfg.StateDir()
+
+	if !cfg.Daemon.LocalSocket {
+		return func() {}
+	}
+
+	var stops []func()
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+	}
+
+	if cache == nil {
+		cache = daemon.NewReadCache(runner, readMaxAge(cfg), ctx.Logger)
+		stops = append(stops, cache.Wait, cache.Subscribe(runner.EventBus()))
+	}
+
+	path := state.DaemonSocket(stateDir)
+	listener, err := daemon.ListenSocket(path)
+	if err != nil {
+		logrus.Warnf("CLI commands cannot share this daemon: %v", err)
+		return stop
+	}
+
+	server := &http.Server{
+		Handler:           daemon.NewLocalReadHandler(cache, ctx.Logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+	stops = append(stops, func() { server.Close() })
+	logrus.Infof("Serving CLI reads on %s", path)
+
+	return stop
+}
+
+// localDaemon returns a client for the daemon running on this host, or nil
+// when there is none, the local socket is disabled, --no-daemon is set, or
+// the command records or replays API interactions.
+func localDaemon(cmd *cobra.Command, cfg *config.Config, cassette bool) *daemon.SocketClient {
+	if noDaemon, _ := cmd.Flags().GetBool("no-daemon"); noDaemon || cassette || !cfg.Daemon.LocalSocket {
+		return nil
+	}
+	if !state.DaemonRunning(cfg.StateDir()) {
+		return nil
+	}
+	return daemon.NewSocketClient(state.DaemonSocket(cfg.StateDir()))
+}
+
+// readMaxAge is how old a snapshot may be before daemon reads refresh it.
+func readMaxAge(cfg *config.Config) time.Duration {
+	if cfg.Serve.ReadMaxAge <= 0 {
+		return time.Duration(config.Default().Serve.ReadMaxAge) * time.Second
+	}
+	return time.Duration(cfg.Serve.ReadMaxAge) * time.Second
+}
+
+// daemonRoutes lists routes from the local daemon. It returns ok false, and
+// the caller asks the API, when no daemon runs, it fails, or its snapshot is
+// older than serve.read_max_age.
+func daemonRoutes(cmdCtx context.Context, filters map[string]string, logger *logrus.Logger) (*models.RouteList, bool) {
+	if ctx.Daemon == nil {
+		return nil, false
+	}
+	routes, served, err := ctx.Daemon.ListRoutes(cmdCtx, filters)
+	if !usableDaemonRead(served, err, logger) {
+		return nil, false
+	}
+	return routes, true
+}
+
+// daemonContacts lists contacts from the local daemon, like daemonRoutes.
+func daemonContacts(cmdCtx context.Context, logger *logrus.Logger) (*models.ContactList, bool) {
+	if ctx.Daemon == nil {
+		return nil, false
+	}
+	contacts, served, err := ctx.Daemon.ListContacts(cmdCtx)
+	if !usableDaemonRead(served, err, logger) {
+		return nil, false
+	}
+	return contacts, true
+}
+
+// usableDaemonRead reports whether a daemon read succeeded with fresh data.
+func usableDaemonRead(served *daemon.Served, err error, logger *logrus.Logger) bool {
+	if err != nil {
+		logger.Debugf("Daemon read failed, using the API: %v", err)
+		return false
+	}
+	if maxAge := readMaxAge(ctx.Config); served.Age > maxAge {
+		logger.Debugf("Daemon snapshot %s is %s old (limit %s), using the API", served.SnapshotID, served.Age, maxAge)
+		return false
+	}
+	logger.Infof("Read from the running daemon's snapshot %s (%s old)", served.SnapshotID, served.Age)
+	return true
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/bss/radb-client/pkg/clipboard"
+	"github.com/spf13/cobra"
+)
+
+// ansiEscape matches the color sequences table output may contain.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// addCopyFlag adds --copy to a command, which places everything the
+// command prints to stdout on the system clipboard as well.
+func addCopyFlag(cmd *cobra.Command) {
+	var copyOutput bool
+	cmd.Flags().BoolVar(&copyOutput, "copy", false, "Also copy the output to the system clipboard")
+
+	runE := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if !copyOutput {
+			return runE(cmd, args)
+		}
+
+		var captured bytes.Buffer
+		restore, err := teeStdout(&captured)
+		if err != nil {
+			return err
+		}
+		err = runE(cmd, args)
+		restore()
+		if err != nil {
+			return err
+		}
+
+		if err := clipboard.Write(ansiEscape.ReplaceAllString(captured.String(), "")); err != nil {
+			return fmt.Errorf("failed to copy output: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "Copied to clipboard")
+		return nil
+	}
+}
+
+// teeStdout copies everything written to os.Stdout into w until the
+// returned function is called, which restores os.Stdout.
+func teeStdout(w io.Writer) (func(), error) {
+	r, pw, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = pw
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.MultiWriter(stdout, w), r)
+		close(done)
+	}()
+
+	return func() {
+		pw.Close()
+		<-done
+		r.Close()
+		os.Stdout = stdout
+	}, nil
+}
@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -20,7 +19,7 @@ This is equivalent to running:
   radb-client search query -- "-i mnt-by MAINT-AS32298"
   radb-client search query -- "-i mnt-by MAINT-AS12213"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 
 			// Query for MAINT-AS32298
 			fmt.Println("# Routes maintained by MAINT-AS32298 (Evoque Data Center Solutions)")
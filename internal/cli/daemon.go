@@ -1,21 +1,37 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/bss/radb-client/internal/api"
 	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/daemon"
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/notify"
+	"github.com/bss/radb-client/internal/publish"
+	"github.com/bss/radb-client/internal/state"
 	"github.com/bss/radb-client/internal/version"
+	"github.com/bss/radb-client/pkg/cron"
+	"github.com/bss/radb-client/pkg/metrics"
+	"github.com/bss/radb-client/pkg/systemd"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 var (
-	daemonInterval int
-	daemonOnce     bool
+	daemonInterval      int
+	daemonOnce          bool
+	daemonMetricsListen string
 )
 
 var daemonCmd = &cobra.Command{
@@ -36,71 +52,673 @@ This mode is designed for server deployment and systemd integration.`,
 }
 
 func init() {
-	daemonCmd.Flags().IntVarP(&daemonInterval, "interval", "i", 3600, "Check interval in seconds (default: 3600 = 1 hour)")
+	daemonCmd.Flags().IntVarP(&daemonInterval, "interval", "i", 3600, "Check interval in seconds (default from daemon.interval)")
 	daemonCmd.Flags().BoolVar(&daemonOnce, "once", false, "Run once and exit (useful for testing)")
+	daemonCmd.Flags().StringVar(&daemonMetricsListen, "metrics-listen", "", "Address to serve Prometheus metrics and health checks on (default from daemon.metrics_listen)")
 }
 
 func runDaemon(cmd *cobra.Command, args []string) error {
-
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("load configuration: %w", err)
-	}
+	cmdCtx := cmd.Context()
+	cfg := ctx.Config
 
 	// Setup logging for daemon mode
 	setupDaemonLogging(cfg)
 
 	logrus.Info("RADb Client Daemon starting...")
 	logrus.Infof("Version: %s", version.Short())
-	logrus.Infof("Check interval: %d seconds (%d minutes)", daemonInterval, daemonInterval/60)
 
-	// TODO: Implement actual daemon functionality
-	// For now, this is a placeholder that shows the structure
-	logrus.Warn("Daemon mode is not fully implemented yet")
-	logrus.Info("This would perform periodic checks of RADb routes")
-	logrus.Info("For now, use 'radb-client route list' and 'radb-client route diff' manually")
+	if !cmd.Flags().Changed("interval") {
+		daemonInterval = cfg.Daemon.Interval
+	}
+	schedules, err := newDaemonSchedules(cfg.Schedules, cfg.Daemon.Targets, cmd.Flags().Changed("interval"))
+	if err != nil {
+		return err
+	}
+	if schedules.routeCheck == nil && schedules.checksRoutes() {
+		logrus.Infof("Check interval: %d seconds (%d minutes)", daemonInterval, daemonInterval/60)
+	}
 
-	// Setup signal handling for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	unlock, err := lockDaemonState(cfg.StateDir())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	runner, stopNotifications, err := newDaemonRunner()
+	if err != nil {
+		return err
+	}
+	reloader := newDaemonReloader(cmd, runner, stopNotifications)
+	defer reloader.close()
 
 	// If running once, just execute and exit
 	if daemonOnce {
 		logrus.Info("Running in one-shot mode")
-		logrus.Info("Would perform a single check and exit")
-		return nil
+		if len(schedules.targets) > 0 {
+			targets := make([]daemon.Target, len(schedules.targets))
+			for i, target := range schedules.targets {
+				targets[i] = target.target
+			}
+			_, err := checkTargets(cmdCtx, runner, targets)
+			return err
+		}
+		_, err := runner.Check(cmdCtx)
+		return err
+	}
+
+	health := newDaemonHealth(runner, cfg, daemonInterval, schedules)
+	reloader.health = health
+
+	if !cmd.Flags().Changed("metrics-listen") {
+		daemonMetricsListen = cfg.Daemon.MetricsListen
+	}
+	stopMonitoring, err := startMonitoringListener(daemonMetricsListen, health)
+	if err != nil {
+		return err
+	}
+	defer stopMonitoring()
+
+	defer coordinateDaemon(runner, nil)()
+
+	logrus.Info("Daemon started successfully")
+
+	return runDaemonLoop(cmdCtx, reloader, "daemon", daemonInterval, schedules)
+}
+
+// newDaemonRunner creates a monitoring runner from the shared CLI context,
+// with an event bus that subscribers can attach to, configured by
+// configureDaemonRunner. The returned function waits for pending
+// deliveries.
+func newDaemonRunner() (*daemon.Runner, func(), error) {
+	history := state.NewHistoryManager(ctx.Config.StateDir(), ctx.Logger)
+	runner := daemon.NewRunner(ctx.APIClient, ctx.StateMgr, history, ctx.Logger)
+
+	bus := events.NewBus()
+	bus.Subscribe(func(event events.Event) {
+		ctx.Logger.WithField("event", event.EventType()).Debugf("Event: %+v", event)
+	})
+	bus.SetLogger(ctx.Logger)
+	runner.SetEventBus(bus)
+
+	// The online client already registered its request metrics
+	if ctx.Metrics == nil {
+		ctx.Metrics = metrics.NewRegistry()
+	}
+	bus.SetMetrics(ctx.Metrics)
+	runner.SetMetrics(daemon.NewMetrics(ctx.Metrics, ctx.Config.StateDir()))
+
+	runner.SetRuntimeState(daemon.LoadRuntimeState(ctx.Config.StateDir(), ctx.Logger))
+
+	stop, err := configureDaemonRunner(runner, ctx.Config, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return runner, stop, nil
+}
+
+// newDaemonHealth creates the health tracker of runner's checks, counting
+// data as fresh from the last successful check a previous daemon recorded.
+func newDaemonHealth(runner *daemon.Runner, cfg *config.Config, interval int, schedules *daemonSchedules) *daemon.Health {
+	health := daemon.NewHealth(ctx.APIClient, cfg.StateDir(), healthMaxAge(cfg.Daemon, interval, schedules))
+	health.Restore(runner.RuntimeState().State())
+	runner.SetHealth(health)
+	return health
+}
+
+// configureDaemonRunner applies cfg's assertions, baseline, and watch
+// targets to runner, and attaches its notification sinks and message
+// brokers to the runner's event bus. It returns a function that detaches
+// them and waits for pending deliveries. On reload, stopPrevious detaches
+// those of the previous configuration once cfg has been found valid; if it
+// is not, nothing changes.
+func configureDaemonRunner(runner *daemon.Runner, cfg *config.Config, stopPrevious func()) (func(), error) {
+	assertions, err := routeAssertions(cfg.Audit)
+	if err != nil {
+		return nil, err
+	}
+
+	router, err := newNotificationRouter(cfg.Notifications, cfg.StateDir(), ctx.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notifications configuration: %w", err)
+	}
+	exporter, err := newEventExporter(cfg.Publish, cfg.API.Source, ctx.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid publish configuration: %w", err)
+	}
+
+	for _, target := range cfg.Daemon.Targets {
+		if len(target.Sinks) == 0 {
+			continue
+		}
+		if router == nil {
+			return nil, fmt.Errorf("target %s has sinks but no notification sinks are configured", target.Name)
+		}
+		if err := router.SetTargetSinks(target.Name, target.Sinks); err != nil {
+			return nil, fmt.Errorf("invalid notifications configuration: %w", err)
+		}
+	}
+
+	if len(assertions) > 0 {
+		logrus.Infof("Checking %d route assertions each cycle", len(assertions))
+	}
+	runner.SetAssertions(assertions)
+	if baseline := cfg.Daemon.Baseline; baseline != "" {
+		logrus.Infof("Reporting changes against baseline %s", baseline)
+	}
+	runner.SetBaseline(cfg.Daemon.Baseline)
+	targets := make([]daemon.Target, len(cfg.Daemon.Targets))
+	for i, target := range cfg.Daemon.Targets {
+		targets[i] = newDaemonTarget(target)
+	}
+	runner.SetTargets(targets)
+
+	if stopPrevious != nil {
+		stopPrevious()
+	}
+
+	bus := runner.EventBus()
+	var stops []func()
+	if router != nil {
+		logrus.Infof("Delivering change notifications to %d sinks", len(cfg.Notifications.Sinks))
+		stops = append(stops, router.Subscribe(bus, notificationBuffer))
+	}
+	if exporter != nil {
+		logrus.Infof("Publishing events to %d message brokers", len(cfg.Publish.Publishers))
+		stops = append(stops, exporter.Subscribe(bus, notificationBuffer))
+	}
+
+	return func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}, nil
+}
+
+// startMonitoringListener serves the shared metrics registry for
+// Prometheus at /metrics and the daemon's health at /healthz and /readyz on
+// addr, and returns a function that stops the listener. An empty addr
+// serves nothing.
+func startMonitoringListener(addr string, health *daemon.Health) (func(), error) {
+	if addr == "" {
+		return func() {}, nil
+	}
+
+	// Bind now so address errors surface at startup
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for metrics on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", ctx.Metrics)
+	mux.Handle("/healthz", health)
+	mux.Handle("/readyz", health)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logrus.Infof("Serving metrics on http://%s/metrics", listener.Addr())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Errorf("Metrics server failed: %v", err)
+		}
+	}()
+
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}, nil
+}
+
+// healthMaxAge returns how old the last check may be before the daemon
+// reports itself unhealthy: two intervals, so one failed check is
+// tolerated. Adaptive schedules use their longest interval, and cron
+// schedules the longest gap between runs over the coming week. Retries
+// under daemon.failure_backoff may be further apart, up to its max_delay.
+// Without scheduled checks it returns 0, and check age is not considered.
+func healthMaxAge(cfg config.DaemonConfig, interval int, schedules *daemonSchedules) time.Duration {
+	if !schedules.checksRoutes() {
+		// Every target runs on its own schedule; any of them records a check
+		var shortest time.Duration
+		for _, target := range schedules.targets {
+			if gap := longestGap(target.schedule); shortest == 0 || gap < shortest {
+				shortest = gap
+			}
+		}
+		return 2 * shortest
+	}
+
+	var gap time.Duration
+	switch {
+	case schedules.routeCheck != nil:
+		gap = longestGap(schedules.routeCheck)
+	case interval <= 0:
+		return 0
+	case cfg.Adaptive.Enabled && cfg.Adaptive.MaxInterval > interval:
+		gap = time.Duration(cfg.Adaptive.MaxInterval) * time.Second
+	default:
+		gap = time.Duration(interval) * time.Second
+	}
+	if cfg.Backoff.Enabled {
+		gap = max(gap, time.Duration(cfg.Backoff.MaxDelay)*time.Second)
+	}
+	return 2 * gap
+}
+
+// longestGap returns the longest time between runs of a schedule over
+// the coming week.
+func longestGap(schedule cron.Schedule) time.Duration {
+	var gap time.Duration
+	prev := time.Now()
+	end := prev.AddDate(0, 0, 7)
+	for prev.Before(end) {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		gap = max(gap, next.Sub(prev))
+		prev = next
+	}
+	return gap
+}
+
+// notificationBuffer is the number of change events that may wait for delivery.
+const notificationBuffer = 16
+
+// newNotificationRouter builds the notification router from config, with
+// failed deliveries queued for retry in the state directory. It returns nil
+// when no sinks are configured.
+func newNotificationRouter(cfg config.NotificationsConfig, stateDir string, logger *logrus.Logger) (*notify.Router, error) {
+	if len(cfg.Sinks) == 0 {
+		return nil, nil
+	}
+
+	sinks := make(map[string]notify.Notifier, len(cfg.Sinks))
+	for _, sink := range cfg.Sinks {
+		switch sink.Type {
+		case "webhook":
+			sinks[sink.Name] = notify.NewWebhookNotifier(sink.URL, sink.Headers)
+		case "slack":
+			sinks[sink.Name] = notify.NewSlackNotifier(sink.URL)
+		case "teams":
+			sinks[sink.Name] = notify.NewTeamsNotifier(sink.URL)
+		case "email":
+			notifier, err := notify.NewEmailNotifier(sink.URL, sink.From, sink.To, sink.Subject, sink.Body)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+			}
+			sinks[sink.Name] = notifier
+		case "pagerduty":
+			notifier, err := notify.NewPagerDutyNotifier(sink.URL, sink.Key, sink.Severity)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+			}
+			sinks[sink.Name] = notifier
+		case "opsgenie":
+			notifier, err := notify.NewOpsgenieNotifier(sink.URL, sink.Key, sink.Severity)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+			}
+			sinks[sink.Name] = notifier
+		default:
+			return nil, fmt.Errorf("sink %s has unsupported type %q", sink.Name, sink.Type)
+		}
+	}
+
+	teams := make([]notify.Team, 0, len(cfg.Teams))
+	for _, t := range cfg.Teams {
+		team := notify.Team{Name: t.Name, Maintainers: t.Maintainers, Sinks: t.Sinks}
+		for _, prefix := range t.Prefixes {
+			parsed, err := netip.ParsePrefix(prefix)
+			if err != nil {
+				return nil, fmt.Errorf("team %s has invalid prefix %q: %w", t.Name, prefix, err)
+			}
+			team.Prefixes = append(team.Prefixes, parsed.Masked())
+		}
+		teams = append(teams, team)
+	}
+
+	router, err := notify.NewRouter(sinks, teams, cfg.DefaultSinks, logger)
+	if err != nil {
+		return nil, err
+	}
+	for _, sink := range cfg.Sinks {
+		if len(sink.ObjectTypes) == 0 && len(sink.ChangeTypes) == 0 && len(sink.Prefixes) == 0 && sink.MinSeverity == "" {
+			continue
+		}
+		filter := notify.SinkFilter{ObjectTypes: sink.ObjectTypes}
+		if sink.MinSeverity != "" {
+			if filter.MinSeverity, err = notify.ParseSeverity(sink.MinSeverity); err != nil {
+				return nil, fmt.Errorf("sink %s: %w", sink.Name, err)
+			}
+		}
+		for _, changeType := range sink.ChangeTypes {
+			filter.ChangeTypes = append(filter.ChangeTypes, models.ChangeType(changeType))
+		}
+		for _, prefix := range sink.Prefixes {
+			parsed, err := netip.ParsePrefix(prefix)
+			if err != nil {
+				return nil, fmt.Errorf("sink %s has invalid prefix %q: %w", sink.Name, prefix, err)
+			}
+			filter.Prefixes = append(filter.Prefixes, parsed.Masked())
+		}
+		router.SetSinkFilter(sink.Name, filter)
+	}
+	if len(cfg.Rules) > 0 || cfg.DefaultSeverity != "" {
+		rules, err := newAlertRules(cfg)
+		if err != nil {
+			return nil, err
+		}
+		router.SetRules(rules)
+	}
+	router.SetQueue(newNotificationQueue(cfg, stateDir, logger))
+	return router, nil
+}
+
+// newAlertRules builds the alert rules that classify changes before they
+// are routed.
+func newAlertRules(cfg config.NotificationsConfig) (*notify.Rules, error) {
+	fallback := notify.SeverityInfo
+	if cfg.DefaultSeverity != "" {
+		var err error
+		if fallback, err = notify.ParseSeverity(cfg.DefaultSeverity); err != nil {
+			return nil, fmt.Errorf("default severity: %w", err)
+		}
+	}
+
+	rules := make([]notify.Rule, 0, len(cfg.Rules))
+	for i, r := range cfg.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		severity, err := notify.ParseSeverity(r.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %s: %w", name, err)
+		}
+		rule := notify.Rule{
+			Name:        name,
+			ObjectTypes: r.ObjectTypes,
+			Origins:     r.Origins,
+			Fields:      r.Fields,
+			OnlyFields:  r.OnlyFields,
+			Severity:    severity,
+		}
+		for _, changeType := range r.ChangeTypes {
+			rule.ChangeTypes = append(rule.ChangeTypes, models.ChangeType(changeType))
+		}
+		for _, prefix := range r.Prefixes {
+			parsed, err := netip.ParsePrefix(prefix)
+			if err != nil {
+				return nil, fmt.Errorf("alert rule %s has invalid prefix %q: %w", name, prefix, err)
+			}
+			rule.Prefixes = append(rule.Prefixes, parsed.Masked())
+		}
+		rules = append(rules, rule)
+	}
+	return notify.NewRules(rules, fallback), nil
+}
+
+// newNotificationQueue opens the queue of notifications awaiting redelivery.
+func newNotificationQueue(cfg config.NotificationsConfig, stateDir string, logger *logrus.Logger) *notify.Queue {
+	return notify.NewQueue(stateDir, notify.RetryPolicy{
+		InitialDelay: time.Duration(cfg.Retry.InitialDelay) * time.Second,
+		MaxDelay:     time.Duration(cfg.Retry.MaxDelay) * time.Second,
+		MaxAttempts:  cfg.Retry.MaxAttempts,
+	}, logger)
+}
+
+// newEventExporter builds the message broker exporter from config. It
+// returns nil when no publishers are configured.
+func newEventExporter(cfg config.PublishConfig, source string, logger *logrus.Logger) (*publish.Exporter, error) {
+	if len(cfg.Publishers) == 0 {
+		return nil, nil
+	}
+
+	publishers := make(map[string]publish.Publisher, len(cfg.Publishers))
+	for _, p := range cfg.Publishers {
+		topic := p.Topic
+		if topic == "" {
+			topic = defaultPublishTopic
+		}
+
+		var (
+			publisher publish.Publisher
+			err       error
+		)
+		switch p.Type {
+		case "nats":
+			publisher, err = publish.NewNATSPublisher(p.URL, topic)
+		case "kafka":
+			publisher, err = publish.NewKafkaPublisher(p.URL, topic, p.Headers)
+		case "rabbitmq-management":
+			exchange := p.Exchange
+			if exchange == "" {
+				exchange = defaultPublishExchange
+			}
+			publisher, err = publish.NewRabbitMQManagementPublisher(p.URL, p.VHost, exchange, topic, p.Headers)
+		default:
+			err = fmt.Errorf("unsupported type %q", p.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("publisher %s: %w", p.Name, err)
+		}
+		publishers[p.Name] = publisher
+	}
+
+	return publish.NewExporter(publishers, source, logger), nil
+}
+
+// Defaults for publishers that leave topic or exchange unset.
+const (
+	defaultPublishTopic    = "radb.events"
+	defaultPublishExchange = "amq.topic"
+)
+
+// runDaemonLoop runs a check immediately and then every interval seconds
+// until interrupted. A non-positive interval disables scheduled checks.
+// With daemon.adaptive.enabled the interval follows the rate of change.
+// With watch targets, each check covers the targets without a schedule of
+// their own, and the others run on their schedules. With
+// daemon.failure_backoff.enabled, failed checks are retried with
+// exponential backoff until one succeeds, continuing across restarts.
+// SIGHUP, or with daemon.auto_reload a change to the config file, reloads
+// the configuration and restarts the timers. Liveness is recorded for the
+// status command.
+func runDaemonLoop(cmdCtx context.Context, reloader *daemonReloader, command string, interval int, schedules *daemonSchedules) error {
+	runner := reloader.runner
+	daemonState := runner.RuntimeState()
+
+	// Setup signal handling for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	var (
+		schedule     *daemon.AdaptiveSchedule
+		backoff      *daemon.FailureBackoff
+		next         time.Duration
+		timer        *time.Timer
+		tick         <-chan time.Time
+		contactCheck *taskTimer
+		cleanup      *taskTimer
+		targets      *targetTimer
+	)
+	// arm starts the timers for the current interval and schedules
+	arm := func() {
+		schedule = newCheckSchedule(ctx.Config.Daemon.Adaptive, interval)
+		backoff = newFailureBackoff(ctx.Config.Daemon.Backoff)
+		if backoff != nil {
+			backoff.Restore(daemonState.Failures())
+		}
+		next = time.Duration(interval) * time.Second
+		if schedule != nil {
+			next = schedule.Interval()
+		}
+		if schedules.routeCheck != nil {
+			next = time.Until(schedules.routeCheck.Next(time.Now()))
+		}
+		timer, tick = nil, nil
+		if (interval > 0 || schedules.routeCheck != nil) && schedules.checksRoutes() {
+			timer = time.NewTimer(next)
+			tick = timer.C
+		}
+		contactCheck = newTaskTimer(schedules.contactCheck)
+		cleanup = newTaskTimer(schedules.cleanup)
+		targets = newTargetTimer(schedules.targets)
+	}
+	disarm := func() {
+		if timer != nil {
+			timer.Stop()
+		}
+		contactCheck.stop()
+		cleanup.stop()
+		targets.stop()
 	}
+	arm()
+	defer func() { disarm() }()
+
+	var configChanges <-chan struct{}
+	if ctx.Config.Daemon.AutoReload {
+		changes, stopWatch, err := watchConfig()
+		if err != nil {
+			logrus.Warnf("Config auto-reload disabled: %v", err)
+		} else {
+			configChanges = changes
+			defer stopWatch()
+		}
+	}
+
+	heartbeat := newDaemonHeartbeat(command, int(next.Seconds()))
+	heartbeat.beat()
+	defer heartbeat.stop()
 
-	// Start daemon loop
-	ticker := time.NewTicker(time.Duration(daemonInterval) * time.Second)
-	defer ticker.Stop()
+	heartbeatTicker := time.NewTicker(daemonHeartbeatInterval)
+	defer heartbeatTicker.Stop()
 
-	logrus.Info("Daemon started successfully (placeholder mode)")
-	logrus.Infof("Would check every %d seconds", daemonInterval)
+	// Watchdog keepalives come from this loop, so a check that hangs
+	// stops them and systemd restarts the daemon
+	var watchdog <-chan time.Time
+	if interval := systemd.WatchdogInterval(); interval > 0 {
+		watchdogTicker := time.NewTicker(interval / 2)
+		defer watchdogTicker.Stop()
+		watchdog = watchdogTicker.C
+		logrus.Infof("systemd watchdog enabled, keepalive every %s", interval/2)
+	}
+	defer notifySystemd(systemd.Stopping)
+
+	check := func() {
+		var result *daemon.CheckResult
+		var err error
+		if len(schedules.targets) > 0 {
+			result, err = checkTargets(cmdCtx, runner, schedules.routeCheckTargets())
+		} else {
+			result, err = runner.Check(cmdCtx)
+		}
+		if err != nil {
+			logrus.Errorf("Check failed: %v", err)
+		}
+		switch {
+		case schedules.routeCheck != nil:
+			next = time.Until(schedules.routeCheck.Next(time.Now()))
+			heartbeat.status.Interval = int(next.Seconds())
+		case schedule != nil:
+			if adjusted := schedule.Next(result, err); adjusted != next {
+				logrus.Infof("Adaptive schedule: check interval changed from %s to %s", next, adjusted)
+				next = adjusted
+				heartbeat.status.Interval = int(next.Seconds())
+			}
+		}
+		delay := next
+		if backoff != nil {
+			if err != nil {
+				delay = backoff.Failure().Round(time.Second)
+				failures, _ := backoff.Failures()
+				logrus.Warnf("%d consecutive checks failed; retrying in %s", failures, delay)
+			} else if failures, since := backoff.Success(); failures > 0 {
+				logrus.Infof("Checks recovered after %d consecutive failures over %s", failures, time.Since(since).Round(time.Second))
+			}
+			daemonState.SetFailures(backoff.Failures())
+		}
+		timer.Reset(delay)
+		heartbeat.checked(err)
+		saveClientStatus()
+		notifySystemd(systemd.Status(checkStatus(result, err, delay)))
+		logrus.Infof("Next check in %d seconds", int(delay.Seconds()))
+	}
+
+	reload := func() {
+		logrus.Info("Reloading configuration...")
+		newInterval, newSchedules, err := reloader.reload(cmdCtx)
+		if err != nil {
+			logrus.Errorf("Failed to reload configuration: %v", err)
+			return
+		}
+		disarm()
+		interval, schedules = newInterval, newSchedules
+		arm()
+		heartbeat.status.Interval = int(next.Seconds())
+		logrus.Info("Configuration reloaded successfully")
+		if timer != nil {
+			logrus.Infof("Next check in %d seconds", int(next.Seconds()))
+		}
+	}
+
+	// Ready before the initial check, which may outlast the start timeout
+	notifySystemd(systemd.Ready, systemd.Status("Running initial check"))
+
+	// Run an initial check immediately rather than waiting a full interval
+	if timer != nil {
+		check()
+	}
 
 	// Main daemon loop
 	for {
 		select {
-		case <-ticker.C:
-			logrus.Info("Periodic check (placeholder - not yet implemented)")
-			logrus.Infof("Next check in %d seconds", daemonInterval)
+		case <-tick:
+			check()
+
+		case <-targets.C():
+			_, err := checkTargets(cmdCtx, runner, targets.due())
+			if err != nil {
+				logrus.Errorf("Check failed: %v", err)
+			}
+			heartbeat.checked(err)
+			saveClientStatus()
+
+		case <-contactCheck.C():
+			if _, err := runner.CheckContacts(cmdCtx); err != nil {
+				logrus.Errorf("Contact check failed: %v", err)
+			}
+			contactCheck.reset()
+
+		case <-cleanup.C():
+			if _, err := runner.Cleanup(cmdCtx); err != nil {
+				logrus.Errorf("Cleanup failed: %v", err)
+			}
+			cleanup.reset()
+
+		case <-heartbeatTicker.C:
+			heartbeat.beat()
+
+		case <-watchdog:
+			notifySystemd(systemd.Watchdog)
+
+		case <-configChanges:
+			logrus.Info("Config file changed")
+			reload()
+
+		case <-cmdCtx.Done():
+			logrus.Info("Shutting down gracefully...")
+			return nil
 
 		case sig := <-sigChan:
 			logrus.Infof("Received signal: %v", sig)
 
 			switch sig {
 			case syscall.SIGHUP:
-				// Reload configuration
-				logrus.Info("Reloading configuration...")
-				newCfg, err := config.Load()
-				if err != nil {
-					logrus.Errorf("Failed to reload configuration: %v", err)
-				} else {
-					cfg = newCfg
-					setupDaemonLogging(cfg)
-					logrus.Info("Configuration reloaded successfully")
-				}
+				reload()
 
 			case os.Interrupt, syscall.SIGTERM:
 				// Graceful shutdown
@@ -111,9 +729,318 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 	}
 }
 
-// TODO: Implement daemon functionality
-// This requires completing the API client and state manager implementations
-// For now, daemon mode shows the structure but doesn't perform actual operations
+// watchConfig watches the loaded config file for daemon.auto_reload.
+func watchConfig() (<-chan struct{}, func(), error) {
+	path := config.File()
+	if path == "" {
+		return nil, nil, fmt.Errorf("no config file was loaded")
+	}
+	changes, stop, err := config.Watch(path, configSettle, ctx.Logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	logrus.Infof("Reloading when %s changes", path)
+	return changes, stop, nil
+}
+
+// newCheckSchedule returns the adaptive schedule configured by cfg starting
+// at interval seconds, or nil when the interval is fixed.
+func newCheckSchedule(cfg config.AdaptiveIntervalConfig, interval int) *daemon.AdaptiveSchedule {
+	if !cfg.Enabled || interval <= 0 {
+		return nil
+	}
+	logrus.Infof("Adaptive check interval between %d and %d seconds", cfg.MinInterval, cfg.MaxInterval)
+	return daemon.NewAdaptiveSchedule(time.Duration(interval)*time.Second,
+		time.Duration(cfg.MinInterval)*time.Second, time.Duration(cfg.MaxInterval)*time.Second, cfg.Backoff)
+}
+
+// newFailureBackoff returns the backoff for failed route checks configured
+// by cfg, or nil when failed checks keep the usual schedule.
+func newFailureBackoff(cfg config.FailureBackoffConfig) *daemon.FailureBackoff {
+	if !cfg.Enabled {
+		return nil
+	}
+	return daemon.NewFailureBackoff(time.Duration(cfg.InitialDelay)*time.Second,
+		time.Duration(cfg.MaxDelay)*time.Second, cfg.Multiplier, cfg.Jitter)
+}
+
+// daemonSchedules holds the cron schedules of the daemon's tasks. Tasks
+// without a schedule do not run, except route checks, which fall back to
+// the fixed interval.
+type daemonSchedules struct {
+	routeCheck   cron.Schedule
+	contactCheck cron.Schedule
+	cleanup      cron.Schedule
+	targets      []watchTarget // Checked instead of the whole account, if any
+}
+
+// watchTarget is a watch target and its schedule, if it has its own.
+type watchTarget struct {
+	target   daemon.Target
+	schedule cron.Schedule // nil to run with the route check
+	next     time.Time
+}
+
+// checksRoutes reports whether route checks have anything to check: the
+// whole account, or the watch targets without a schedule of their own.
+func (s *daemonSchedules) checksRoutes() bool {
+	return len(s.targets) == 0 || len(s.routeCheckTargets()) > 0
+}
+
+// routeCheckTargets returns the watch targets checked with the route check.
+func (s *daemonSchedules) routeCheckTargets() []daemon.Target {
+	var targets []daemon.Target
+	for _, target := range s.targets {
+		if target.schedule == nil {
+			targets = append(targets, target.target)
+		}
+	}
+	return targets
+}
+
+// newDaemonTarget converts a configured watch target for the runner.
+func newDaemonTarget(cfg config.WatchTargetConfig) daemon.Target {
+	return daemon.Target{Name: cfg.Name, Filters: cfg.Filters(), Retention: cfg.Retention}
+}
+
+// newDaemonSchedules parses the configured task and watch target
+// schedules. An --interval given on the command line takes precedence
+// over schedules.route_check.
+func newDaemonSchedules(cfg config.SchedulesConfig, targets []config.WatchTargetConfig, intervalFlag bool) (*daemonSchedules, error) {
+	schedules := &daemonSchedules{}
+	for _, task := range []struct {
+		name     string
+		spec     string
+		schedule *cron.Schedule
+	}{
+		{"Route checks", cfg.RouteCheck, &schedules.routeCheck},
+		{"Contact checks", cfg.ContactCheck, &schedules.contactCheck},
+		{"Cleanups", cfg.Cleanup, &schedules.cleanup},
+	} {
+		if task.spec == "" {
+			continue
+		}
+		if task.schedule == &schedules.routeCheck && intervalFlag {
+			logrus.Info("Route checks follow --interval instead of schedules.route_check")
+			continue
+		}
+		schedule, err := cron.Parse(task.spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", task.spec, err)
+		}
+		*task.schedule = schedule
+		logrus.Infof("%s scheduled at %q, next at %s", task.name, task.spec, schedule.Next(time.Now()).Format(time.RFC3339))
+	}
+
+	for _, cfg := range targets {
+		target := watchTarget{target: newDaemonTarget(cfg)}
+		if cfg.Schedule == "" {
+			logrus.Infof("Watching target %s with the route check", cfg.Name)
+		} else {
+			schedule, err := cron.Parse(cfg.Schedule)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schedule %q for target %s: %w", cfg.Schedule, cfg.Name, err)
+			}
+			target.schedule = schedule
+			logrus.Infof("Watching target %s at %q, next at %s", cfg.Name, cfg.Schedule, schedule.Next(time.Now()).Format(time.RFC3339))
+		}
+		schedules.targets = append(schedules.targets, target)
+	}
+	return schedules, nil
+}
+
+// checkTargets checks each target in turn and combines the results. A
+// failed target does not stop the others; the errors are joined.
+func checkTargets(cmdCtx context.Context, runner *daemon.Runner, targets []daemon.Target) (*daemon.CheckResult, error) {
+	combined := &daemon.CheckResult{Summary: make(map[models.ChangeType]int)}
+	var errs []error
+	for _, target := range targets {
+		result, err := runner.CheckTarget(cmdCtx, target)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("target %s: %w", target.Name, err))
+			continue
+		}
+		logrus.Infof("Target %s: %d routes, %d changes", target.Name, result.RouteCount, result.Changes)
+		combined.RouteCount += result.RouteCount
+		combined.Changes += result.Changes
+		combined.Violations += result.Violations
+		for changeType, count := range result.Summary {
+			combined.Summary[changeType] += count
+		}
+	}
+	return combined, errors.Join(errs...)
+}
+
+// targetTimer fires when watch targets with their own schedule are due.
+type targetTimer struct {
+	targets []*watchTarget
+	timer   *time.Timer
+}
+
+// newTargetTimer starts a timer for the next run of any scheduled target.
+func newTargetTimer(targets []watchTarget) *targetTimer {
+	t := &targetTimer{}
+	now := time.Now()
+	for i := range targets {
+		if targets[i].schedule != nil {
+			targets[i].next = targets[i].schedule.Next(now)
+			t.targets = append(t.targets, &targets[i])
+		}
+	}
+	if len(t.targets) > 0 {
+		t.timer = time.NewTimer(t.until())
+	}
+	return t
+}
+
+// C returns the channel the timer fires on, or nil when no target has a
+// schedule.
+func (t *targetTimer) C() <-chan time.Time {
+	if t.timer == nil {
+		return nil
+	}
+	return t.timer.C
+}
+
+// due returns the targets whose run has come, schedules their next runs,
+// and resets the timer for the earliest.
+func (t *targetTimer) due() []daemon.Target {
+	now := time.Now()
+	var due []daemon.Target
+	for _, target := range t.targets {
+		if !target.next.After(now) {
+			due = append(due, target.target)
+			target.next = target.schedule.Next(now)
+		}
+	}
+	t.timer.Reset(t.until())
+	return due
+}
+
+// until returns the time until the earliest target run.
+func (t *targetTimer) until() time.Duration {
+	next := t.targets[0].next
+	for _, target := range t.targets[1:] {
+		if target.next.Before(next) {
+			next = target.next
+		}
+	}
+	return time.Until(next)
+}
+
+// stop stops the timer.
+func (t *targetTimer) stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// taskTimer fires at the runs of a cron schedule. A taskTimer without a
+// schedule never fires.
+type taskTimer struct {
+	schedule cron.Schedule
+	timer    *time.Timer
+}
+
+// newTaskTimer starts a timer for the next run of schedule, which may be nil.
+func newTaskTimer(schedule cron.Schedule) *taskTimer {
+	t := &taskTimer{schedule: schedule}
+	if schedule != nil {
+		t.timer = time.NewTimer(time.Until(schedule.Next(time.Now())))
+	}
+	return t
+}
+
+// C returns the channel the timer fires on, or nil when unscheduled.
+func (t *taskTimer) C() <-chan time.Time {
+	if t.timer == nil {
+		return nil
+	}
+	return t.timer.C
+}
+
+// reset schedules the next run after one fired.
+func (t *taskTimer) reset() {
+	t.timer.Reset(time.Until(t.schedule.Next(time.Now())))
+}
+
+// stop stops the timer.
+func (t *taskTimer) stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// checkStatus describes the outcome of a check for systemctl status.
+func checkStatus(result *daemon.CheckResult, err error, next time.Duration) string {
+	at := time.Now().Format("15:04")
+	if err != nil {
+		return fmt.Sprintf("Last check at %s failed: %v; next in %s", at, err, next)
+	}
+	status := fmt.Sprintf("Last check at %s: %d routes, %d changes", at, result.RouteCount, result.Changes)
+	if result.Violations > 0 {
+		status += fmt.Sprintf(", %d assertion violations", result.Violations)
+	}
+	return fmt.Sprintf("%s; next in %s", status, next)
+}
+
+// notifySystemd sends notifications to systemd when running under it.
+func notifySystemd(states ...string) {
+	if _, err := systemd.Notify(states...); err != nil {
+		logrus.Warnf("Failed to notify systemd: %v", err)
+	}
+}
+
+// daemonHeartbeatInterval is how often a running daemon records that it is alive.
+const daemonHeartbeatInterval = time.Minute
+
+// daemonHeartbeat records daemon liveness in the state directory.
+type daemonHeartbeat struct {
+	stateDir string
+	status   models.DaemonStatus
+}
+
+// newDaemonHeartbeat creates a heartbeat for the current process.
+func newDaemonHeartbeat(command string, interval int) *daemonHeartbeat {
+	return &daemonHeartbeat{
+		stateDir: ctx.Config.StateDir(),
+		status: models.DaemonStatus{
+			PID:       os.Getpid(),
+			Command:   command,
+			Interval:  interval,
+			StartedAt: time.Now(),
+		},
+	}
+}
+
+// beat records that the daemon is alive.
+func (h *daemonHeartbeat) beat() {
+	h.status.HeartbeatAt = time.Now()
+	if reporter, ok := ctx.APIClient.(api.StatusReporter); ok {
+		h.status.RequestsPerMinute = reporter.Status().RequestsPerMinute
+	}
+	if err := state.SaveDaemonStatus(h.stateDir, &h.status); err != nil {
+		logrus.Warnf("Failed to record daemon status: %v", err)
+	}
+}
+
+// checked records the outcome of a check cycle.
+func (h *daemonHeartbeat) checked(err error) {
+	now := time.Now()
+	h.status.LastCheckAt = &now
+	h.status.LastCheckError = ""
+	if err != nil {
+		h.status.LastCheckError = err.Error()
+	}
+	h.beat()
+}
+
+// stop records a clean shutdown.
+func (h *daemonHeartbeat) stop() {
+	now := time.Now()
+	h.status.StoppedAt = &now
+	h.beat()
+}
 
 // setupDaemonLogging configures logging for daemon mode
 func setupDaemonLogging(cfg *config.Config) {
@@ -137,5 +1064,37 @@ func setupDaemonLogging(cfg *config.Config) {
 	// Output to stdout (systemd captures this)
 	logrus.SetOutput(os.Stdout)
 
+	// Under systemd, send entries to the journal with their fields intact
+	if systemd.JournalConnected(os.Stdout) {
+		if journal, err := daemonJournal(); err != nil {
+			logrus.Warnf("Logging to stdout: %v", err)
+		} else {
+			logrus.SetFormatter(&systemd.JournalFormatter{Identifier: "radb-client"})
+			logrus.SetOutput(journal)
+		}
+	}
+
+	// Apply the same settings to the logger shared with the API client and state manager
+	if ctx.Logger != nil {
+		ctx.Logger.SetLevel(level)
+		ctx.Logger.SetFormatter(logrus.StandardLogger().Formatter)
+		ctx.Logger.SetOutput(logrus.StandardLogger().Out)
+	}
+
 	logrus.Debug("Daemon logging configured")
 }
+
+// journalWriter is the daemon's journal connection, kept across reloads.
+var journalWriter *systemd.JournalWriter
+
+// daemonJournal connects to the journal once.
+func daemonJournal() (*systemd.JournalWriter, error) {
+	if journalWriter == nil {
+		w, err := systemd.NewJournalWriter()
+		if err != nil {
+			return nil, err
+		}
+		journalWriter = w
+	}
+	return journalWriter, nil
+}
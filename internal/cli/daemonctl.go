@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newDaemonStopCmd creates the daemon stop command.
+func newDaemonStopCmd(logger *logrus.Logger) *cobra.Command {
+	var timeout int
+
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the daemon running on this state directory",
+		Long: `Send SIGTERM to the daemon or serve process holding the state directory,
+found through the PID file it writes there, and wait for it to finish its
+current cycle and exit.
+
+The command fails if no daemon is running or it has not exited within
+--timeout seconds.`,
+		Example: `  radb-client daemon stop
+  radb-client daemon stop --timeout 300`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stateDir := ctx.Config.StateDir()
+			pid, err := signalDaemon(stateDir, syscall.SIGTERM)
+			if err != nil {
+				return err
+			}
+			logger.Debugf("Sent SIGTERM to daemon %d", pid)
+
+			deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+			for state.DaemonRunning(stateDir) {
+				if time.Now().After(deadline) {
+					return fmt.Errorf("daemon %d has not exited after %d seconds", pid, timeout)
+				}
+				select {
+				case <-cmd.Context().Done():
+					return cmd.Context().Err()
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+			fmt.Printf("Stopped daemon %d\n", pid)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&timeout, "timeout", 60, "Seconds to wait for the daemon to exit")
+
+	return cmd
+}
+
+// newDaemonReloadCmd creates the daemon reload command.
+func newDaemonReloadCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reload",
+		Short: "Make the running daemon reload its configuration",
+		Long: `Send SIGHUP to the daemon or serve process holding the state directory,
+found through the PID file it writes there. The daemon validates the
+configuration and applies it, or logs why it cannot and keeps the previous
+one; see its log for the outcome.`,
+		Example: `  radb-client daemon reload`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pid, err := signalDaemon(ctx.Config.StateDir(), syscall.SIGHUP)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Asked daemon %d to reload its configuration\n", pid)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// signalDaemon sends sig to the daemon holding stateDir and returns its
+// process ID.
+func signalDaemon(stateDir string, sig os.Signal) (int, error) {
+	pid, err := state.DaemonPID(stateDir)
+	if errors.Is(err, state.ErrDaemonNotRunning) {
+		return 0, fmt.Errorf("%w (%s)", err, stateDir)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find daemon %d: %w", pid, err)
+	}
+	if err := process.Signal(sig); err != nil {
+		return 0, fmt.Errorf("failed to signal daemon %d: %w", pid, err)
+	}
+	return pid, nil
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/daemon"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newDaemonStatusCmd creates the daemon status command.
+func newDaemonStatusCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		outputFormat string
+		address      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Query a running daemon's health and readiness",
+		Long: `Query the /readyz endpoint of a running daemon or serve process and show
+its last successful check, whether the API accepts its credentials, and
+whether its state directory is writable.
+
+The daemon is live while checks keep finishing and it can write state, and
+ready while it is live, its last successful check is less than two check
+intervals old, and its credentials are accepted. The command exits non-zero
+when the daemon is not ready, so it can serve as a monitoring check.
+
+The address defaults to daemon.metrics_listen. Serve processes also answer
+on their --listen address.`,
+		Example: `  radb-client daemon status
+  radb-client daemon status --address 127.0.0.1:8080 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if address == "" {
+				address = ctx.Config.Daemon.MetricsListen
+			}
+			if address == "" {
+				return fmt.Errorf("no daemon address: set daemon.metrics_listen or pass --address")
+			}
+
+			url := healthURL(address, "/readyz")
+			logger.Debugf("Querying %s", url)
+			report, err := fetchDaemonHealth(cmd.Context(), url)
+			if err != nil {
+				return err
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if err := outputter.RenderDaemonHealth(report); err != nil {
+				return err
+			}
+			if !report.Ready {
+				return fmt.Errorf("daemon is not ready")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringVar(&address, "address", "", "Daemon address as host:port or URL (default from daemon.metrics_listen)")
+
+	return cmd
+}
+
+// healthURL returns the URL of a health endpoint at address, which may be a
+// listen address such as ":9465" or a base URL.
+func healthURL(address, path string) string {
+	if strings.HasPrefix(address, "http://") || strings.HasPrefix(address, "https://") {
+		return strings.TrimSuffix(address, "/") + path
+	}
+
+	// A listener on all interfaces is reached through loopback
+	if host, port, err := net.SplitHostPort(address); err == nil && (host == "" || host == "0.0.0.0" || host == "::") {
+		address = net.JoinHostPort("127.0.0.1", port)
+	}
+	return "http://" + address + path
+}
+
+// fetchDaemonHealth requests a health endpoint. Unhealthy daemons answer
+// 503 with a report, so only other statuses are errors.
+func fetchDaemonHealth(cmdCtx context.Context, url string) (*daemon.HealthReport, error) {
+	reqCtx, cancel := context.WithTimeout(cmdCtx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daemon address: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach daemon (is it running with a metrics listener?): %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil, fmt.Errorf("daemon health check failed with status %d", resp.StatusCode)
+	}
+
+	var report daemon.HealthReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse health report: %w", err)
+	}
+	return &report, nil
+}
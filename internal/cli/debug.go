@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/version"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces secrets in debug bundles.
+const redactedValue = "REDACTED"
+
+// sensitiveConfigWords mark config keys whose values are never bundled
+// when they appear anywhere in a key, and sensitiveConfigSuffixes when a
+// key ends with them. Both are lower case without underscores, as Save may
+// write keys either way.
+var (
+	sensitiveConfigWords    = []string{"password", "secret", "token", "authorization"}
+	sensitiveConfigSuffixes = []string{"key"}
+)
+
+// NewDebugCmd creates the debug command.
+func NewDebugCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Collect diagnostics for bug reports",
+		Long:  "Collect configuration, status, and captured API responses for attaching to issue reports.",
+	}
+
+	cmd.AddCommand(newDebugBundleCmd(logger))
+
+	return cmd
+}
+
+// newDebugBundleCmd creates the debug bundle command.
+func newDebugBundleCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		file     string
+		logFiles []string
+		lines    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Write a diagnostics archive for an issue report",
+		Long: `Write a gzipped tar archive of diagnostics for attaching to an issue report.
+
+The archive contains:
+  config.yaml     the configuration file, with secrets and proxy passwords redacted
+  version.txt     version, build, and platform information
+  state/          client and daemon status and the most recent changelog entries
+  captures/       API responses saved with --capture-dir
+  logs/           the last lines of each file given with --log
+
+Credentials are never read from the keyring, and captured responses are
+already sanitized, but review the archive before sharing it.`,
+		Example: `  radb-client --capture-dir ~/radb-captures route list
+  radb-client --capture-dir ~/radb-captures debug bundle
+  radb-client debug bundle --log /var/log/radb-daemon.log -f report.tar.gz`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if lines < 1 {
+				return fmt.Errorf("--lines must be at least 1")
+			}
+			if file == "" {
+				file = fmt.Sprintf("radb-debug-%s.tar.gz", time.Now().Format("20060102-150405"))
+			}
+			captureDir, _ := cmd.Flags().GetString("capture-dir")
+
+			count, err := writeDebugBundle(file, captureDir, logFiles, lines, logger)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Wrote %d files to %s\n", count, file)
+			if captureDir == "" {
+				fmt.Println("No --capture-dir given; re-run the failing command with --capture-dir to include API responses.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Archive to write (default radb-debug-<timestamp>.tar.gz)")
+	cmd.Flags().StringArrayVar(&logFiles, "log", nil, "Log file to include (repeatable)")
+	cmd.Flags().IntVar(&lines, "lines", 1000, "Number of trailing lines to include from logs and the changelog")
+
+	return cmd
+}
+
+// writeDebugBundle writes the diagnostics archive and returns the number of files in it.
+func writeDebugBundle(path, captureDir string, logFiles []string, lines int, logger *logrus.Logger) (int, error) {
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	count := 0
+	add := func(name string, data []byte) error {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+		count++
+		return nil
+	}
+
+	if err := add("version.txt", []byte(version.Full()+"\n")); err != nil {
+		return 0, err
+	}
+
+	if ctx.Config != nil && ctx.Config.ConfigFile != "" {
+		config, err := redactedConfig(ctx.Config.ConfigFile)
+		if err != nil {
+			logger.Warnf("Config not included: %v", err)
+		} else if err := add("config.yaml", config); err != nil {
+			return 0, err
+		}
+	}
+
+	if ctx.Config != nil {
+		stateDir := ctx.Config.StateDir()
+		for _, name := range []string{"client-status.json", "daemon-status.json"} {
+			data, err := os.ReadFile(filepath.Join(stateDir, name))
+			if err != nil {
+				logger.Debugf("%s not included: %v", name, err)
+				continue
+			}
+			if err := add("state/"+name, data); err != nil {
+				return 0, err
+			}
+		}
+
+		changelog, err := tailFile(filepath.Join(stateDir, "changelog.jsonl"), lines)
+		if err != nil {
+			logger.Debugf("Changelog not included: %v", err)
+		} else if err := add("state/changelog.jsonl", changelog); err != nil {
+			return 0, err
+		}
+	}
+
+	if captureDir != "" {
+		captures, err := filepath.Glob(filepath.Join(captureDir, "response-*.json"))
+		if err != nil {
+			return 0, fmt.Errorf("failed to list captures: %w", err)
+		}
+		for _, capture := range captures {
+			data, err := os.ReadFile(capture)
+			if err != nil {
+				logger.Warnf("Capture %s not included: %v", capture, err)
+				continue
+			}
+			if err := add("captures/"+filepath.Base(capture), data); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	for i, logFile := range logFiles {
+		data, err := tailFile(logFile, lines)
+		if err != nil {
+			logger.Warnf("Log %s not included: %v", logFile, err)
+			continue
+		}
+		if err := add(fmt.Sprintf("logs/%d-%s", i+1, filepath.Base(logFile)), data); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return count, nil
+}
+
+// redactedConfig reads a config file and redacts secrets and the passwords
+// in any URLs.
+func redactedConfig(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	redactConfigValue(config)
+
+	return yaml.Marshal(config)
+}
+
+// redactConfigValue redacts sensitive keys and URL passwords in a decoded config value.
+func redactConfigValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveConfigKey(key) {
+				if field != nil && field != "" {
+					v[key] = redactedValue
+				}
+				continue
+			}
+			v[key] = redactConfigValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactConfigValue(item)
+		}
+	case string:
+		if u, err := url.Parse(v); err == nil && u.User != nil {
+			if _, hasPassword := u.User.Password(); hasPassword {
+				u.User = url.UserPassword(u.User.Username(), redactedValue)
+				return u.String()
+			}
+		}
+	}
+	return value
+}
+
+// isSensitiveConfigKey reports whether a config key names a secret.
+func isSensitiveConfigKey(key string) bool {
+	key = strings.ReplaceAll(strings.ToLower(key), "_", "")
+	for _, word := range sensitiveConfigWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	for _, suffix := range sensitiveConfigSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// tailFile returns the last n lines of a file.
+func tailFile(path string, n int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tail := make([]string, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(tail) == n {
+			tail = tail[1:]
+		}
+		tail = append(tail, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(tail) == 0 {
+		return nil, nil
+	}
+
+	return []byte(strings.Join(tail, "\n") + "\n"), nil
+}
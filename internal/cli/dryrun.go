@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+)
+
+// enableDryRun switches the shared API client to dry-run mode. Writes are
+// validated locally and printed instead of being sent.
+func enableDryRun() error {
+	client, ok := ctx.APIClient.(api.DryRunClient)
+	if !ok {
+		return fmt.Errorf("dry run is not supported by this API client")
+	}
+	client.SetDryRun(printPlannedWrite)
+	return nil
+}
+
+// printPlannedWrite prints the endpoint and payload of a withheld write.
+// Routes are shown as RPSL, other objects as JSON.
+func printPlannedWrite(write api.PlannedWrite) {
+	fmt.Printf("Would send %s %s\n", write.Method, write.URL)
+
+	var body string
+	switch obj := write.Object.(type) {
+	case nil:
+		return
+	case *models.RouteObject:
+		body = obj.ToRPSL()
+	default:
+		data, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			fmt.Printf("  (failed to encode payload: %v)\n", err)
+			return
+		}
+		body = string(data)
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+}
@@ -1,11 +1,14 @@
 package cli
 
 import (
-	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/models"
 	"github.com/bss/radb-client/internal/state"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -22,7 +25,10 @@ func NewHistoryCmd(logger *logrus.Logger) *cobra.Command {
 
 	cmd.AddCommand(
 		newHistoryShowCmd(logger),
+		newHistoryObjectCmd(logger),
+		newHistoryAnnotateCmd(logger),
 		newHistoryStatsCmd(logger),
+		newHistoryExportCmd(logger),
 	)
 
 	return cmd
@@ -35,24 +41,36 @@ func newHistoryShowCmd(logger *logrus.Logger) *cobra.Command {
 		since        string
 		until        string
 		objectType   string
+		object       string
 		limit        int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "show",
 		Short: "Show change history",
+		Example: `  # Changes to one route over the last 90 days
+  radb-client history show --object radb:route:192.0.2.0/24:AS64500 --since 90d`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
-
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+			cmdCtx := cmd.Context()
+			historyMgr := state.NewHistoryManager(ctx.Config.StateDir(), logger)
+
+			var ref models.ObjectRef
+			if object != "" {
+				var err error
+				if ref, err = models.ParseURN(object); err != nil {
+					return err
+				}
+				if objectType != "" && objectType != ref.ObjectType {
+					return fmt.Errorf("--type %s conflicts with %s reference %s", objectType, ref.ObjectType, object)
+				}
+				objectType = ref.ObjectType
 			}
 
-			historyMgr := state.NewHistoryManager(cfg.StateDir(), logger)
-
 			// Parse time range
-			var fromTime, toTime time.Time
+			var (
+				fromTime, toTime time.Time
+				err              error
+			)
 			if since != "" {
 				fromTime, err = parseTimeSpec(since)
 				if err != nil {
@@ -72,11 +90,21 @@ func newHistoryShowCmd(logger *logrus.Logger) *cobra.Command {
 			}
 
 			// Query changes
-			entries, err := historyMgr.QueryChanges(ctx, fromTime, toTime, objectType)
+			entries, err := historyMgr.QueryChanges(cmdCtx, fromTime, toTime, objectType)
 			if err != nil {
 				return fmt.Errorf("failed to query history: %w", err)
 			}
 
+			if object != "" {
+				filtered := entries[:0]
+				for _, entry := range entries {
+					if entry.ObjectID == ref.ObjectID() {
+						filtered = append(filtered, entry)
+					}
+				}
+				entries = filtered
+			}
+
 			// Apply limit if specified
 			if limit > 0 && len(entries) > limit {
 				entries = entries[len(entries)-limit:]
@@ -84,6 +112,7 @@ func newHistoryShowCmd(logger *logrus.Logger) *cobra.Command {
 
 			// Render output
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			outputter.SetAnnotations(loadAnnotations(cmdCtx, logger))
 			return outputter.RenderChangeHistory(entries)
 		},
 	}
@@ -92,11 +121,104 @@ func newHistoryShowCmd(logger *logrus.Logger) *cobra.Command {
 	cmd.Flags().StringVar(&since, "since", "", "Show changes since (e.g., '2024-01-01', '7d', '1h')")
 	cmd.Flags().StringVar(&until, "until", "", "Show changes until (e.g., '2024-12-31')")
 	cmd.Flags().StringVar(&objectType, "type", "", "Filter by object type (route, contact)")
+	cmd.Flags().StringVar(&object, "object", "", "Filter by object reference (e.g. radb:route:192.0.2.0/24:AS64500)")
 	cmd.Flags().IntVar(&limit, "limit", 0, "Limit number of entries shown")
 
 	return cmd
 }
 
+// newHistoryObjectCmd creates the history object command.
+func newHistoryObjectCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "object <route-id|contact-id|urn>",
+		Short: "Show the full lifecycle of one route or contact",
+		Long: `Show every recorded change to one route or contact, oldest first, with
+the before and after value of each changed field. The whole changelog is
+searched, so the lifecycle spans every snapshot comparison since the object
+first appeared.
+
+Routes are identified as <prefix>-<origin> or by reference, contacts by
+their ID or reference.`,
+		Example: `  radb-client history object 192.0.2.0/24-AS64500
+  radb-client history object radb:route:192.0.2.0/24:AS64500 -o json
+  radb-client history object C-1234`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+			historyMgr := state.NewHistoryManager(ctx.Config.StateDir(), logger)
+
+			objectID := args[0]
+			if models.IsURN(objectID) {
+				ref, err := models.ParseURN(objectID)
+				if err != nil {
+					return err
+				}
+				objectID = ref.ObjectID()
+			}
+
+			entries, err := historyMgr.QueryByObjectID(cmdCtx, objectID)
+			if err != nil {
+				return fmt.Errorf("failed to query history: %w", err)
+			}
+			if len(entries) == 0 {
+				return fmt.Errorf("no recorded changes for %s", args[0])
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			outputter.SetAnnotations(loadAnnotations(cmdCtx, logger))
+			return outputter.RenderObjectHistory(state.BuildObjectHistory(entries))
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// newHistoryAnnotateCmd creates the history annotate command.
+func newHistoryAnnotateCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		note  string
+		flags annotateFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "annotate <entry-id>",
+		Short: "Attach a local note or labels to a changelog entry",
+		Long: `Attach a local note or labels to a changelog entry, for example the
+ticket that authorized the change. Entry IDs are shown by history show and
+history object; a unique prefix of an ID is enough. Annotations are stored
+with your snapshots, never sent to RADb, and shown in history show. Without
+--note or flags, the current annotation is printed.`,
+		Example: `  radb-client history annotate 3f9a1c2b7d4e --note "ticket NET-1234"
+  radb-client history annotate 3f9a1c --label emergency
+  radb-client history annotate 3f9a1c --clear`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+			historyMgr := state.NewHistoryManager(ctx.Config.StateDir(), logger)
+
+			entry, err := historyMgr.GetEntry(cmdCtx, args[0])
+			if err != nil {
+				return err
+			}
+
+			var noteArgs []string
+			if cmd.Flags().Changed("note") {
+				noteArgs = []string{note}
+			}
+			return runAnnotate(cmdCtx, logger, models.AnnotationTypeChange, entry.ID, noteArgs, flags)
+		},
+	}
+
+	cmd.Flags().StringVar(&note, "note", "", "Note to attach, e.g. a ticket reference")
+	flags.register(cmd)
+
+	return cmd
+}
+
 // newHistoryStatsCmd creates the history stats command.
 func newHistoryStatsCmd(logger *logrus.Logger) *cobra.Command {
 	var (
@@ -109,17 +231,14 @@ func newHistoryStatsCmd(logger *logrus.Logger) *cobra.Command {
 		Use:   "stats",
 		Short: "Show change statistics",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
-
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
-			}
-
-			historyMgr := state.NewHistoryManager(cfg.StateDir(), logger)
+			cmdCtx := cmd.Context()
+			historyMgr := state.NewHistoryManager(ctx.Config.StateDir(), logger)
 
 			// Parse time range
-			var fromTime, toTime time.Time
+			var (
+				fromTime, toTime time.Time
+				err              error
+			)
 			if since != "" {
 				fromTime, err = parseTimeSpec(since)
 				if err != nil {
@@ -139,7 +258,7 @@ func newHistoryStatsCmd(logger *logrus.Logger) *cobra.Command {
 			}
 
 			// Get statistics
-			stats, err := historyMgr.GetStatistics(ctx, fromTime, toTime)
+			stats, err := historyMgr.GetStatistics(cmdCtx, fromTime, toTime)
 			if err != nil {
 				return fmt.Errorf("failed to get statistics: %w", err)
 			}
@@ -186,9 +305,104 @@ func newHistoryStatsCmd(logger *logrus.Logger) *cobra.Command {
 	return cmd
 }
 
+// newHistoryExportCmd creates the history export command.
+func newHistoryExportCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		format     string
+		file       string
+		since      string
+		until      string
+		objectType string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export change history as CSV or JSON Lines",
+		Long: `Export changelog entries for loading into spreadsheets and BI tools.
+
+CSV has one row per change with the columns id, timestamp, change_type,
+object_type, object_id, urn, snapshot_id, field_changes (semicolon
+separated), note, before, and after; object states are compact JSON.
+JSON Lines writes each entry as it is stored in the changelog.
+
+Without --since, the whole changelog is exported.`,
+		Example: `  radb-client history export --since 90d --format csv -f changes.csv
+  radb-client history export --since 2024-01-01 --until 2024-03-31 --format jsonl > q1.jsonl`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+			historyMgr := state.NewHistoryManager(ctx.Config.StateDir(), logger)
+
+			if format != "csv" && format != "jsonl" {
+				return fmt.Errorf("unsupported export format: %s (expected csv or jsonl)", format)
+			}
+
+			// Parse time range
+			var (
+				fromTime time.Time
+				toTime   = time.Now()
+				err      error
+			)
+			if since != "" {
+				fromTime, err = parseTimeSpec(since)
+				if err != nil {
+					return fmt.Errorf("invalid since time: %w", err)
+				}
+			}
+			if until != "" {
+				toTime, err = parseTimeSpec(until)
+				if err != nil {
+					return fmt.Errorf("invalid until time: %w", err)
+				}
+			}
+
+			entries, err := historyMgr.QueryChanges(cmdCtx, fromTime, toTime, objectType)
+			if err != nil {
+				return fmt.Errorf("failed to query history: %w", err)
+			}
+
+			var w io.Writer = os.Stdout
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", file, err)
+				}
+				defer f.Close()
+				w = f
+			}
+
+			if format == "csv" {
+				err = state.WriteChangelogCSV(w, entries)
+			} else {
+				err = state.WriteChangelogJSONL(w, entries)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+
+			if file != "" {
+				fmt.Fprintf(os.Stderr, "Exported %d changes to %s\n", len(entries), file)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "csv", "Export format (csv, jsonl)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write to a file instead of standard output")
+	cmd.Flags().StringVar(&since, "since", "", "Export changes since (e.g., '2024-01-01', '90d')")
+	cmd.Flags().StringVar(&until, "until", "", "Export changes until (e.g., '2024-12-31')")
+	cmd.Flags().StringVar(&objectType, "type", "", "Filter by object type (route, contact)")
+
+	return cmd
+}
+
 // parseTimeSpec parses various time specifications.
 func parseTimeSpec(spec string) (time.Time, error) {
-	// Try parsing as duration relative to now
+	// Try parsing as duration relative to now, allowing a day suffix ("7d")
+	if days, ok := strings.CutSuffix(spec, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
 	if d, err := time.ParseDuration(spec); err == nil {
 		return time.Now().Add(-d), nil
 	}
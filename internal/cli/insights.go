@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/internal/version"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewInsightsCmd creates the insights command.
+func NewInsightsCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		outputFormat string
+		since        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "insights",
+		Short: "Summarize local command usage",
+		Long: `Summarize the command usage recorded by local telemetry: how often each
+command ran, how often it failed, and how long it took.
+
+Telemetry is off unless telemetry.enabled is set in the configuration. Each
+run records only the command name, start time, duration, result, and client
+version; arguments, flag values, and objects are never recorded. Records
+stay in the state directory and are never transmitted.`,
+		Example: `  # Usage over the last 30 days
+  radb-client insights --since 30d
+
+  # Remove all recorded usage
+  radb-client insights clear`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var from time.Time
+			if since != "" {
+				var err error
+				if from, err = parseTimeSpec(since); err != nil {
+					return err
+				}
+			}
+
+			if !ctx.Config.Telemetry.Enabled {
+				fmt.Fprintln(os.Stderr, "Telemetry is disabled; set telemetry.enabled to record command usage")
+			}
+
+			records, err := state.LoadUsage(ctx.Config.StateDir(), from)
+			if err != nil {
+				return fmt.Errorf("failed to load usage: %w", err)
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderUsage(state.SummarizeUsage(records))
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringVar(&since, "since", "", "Only include runs since (e.g., '2024-01-01', '30d')")
+
+	cmd.AddCommand(newInsightsClearCmd(logger))
+
+	return cmd
+}
+
+// newInsightsClearCmd creates the insights clear command.
+func newInsightsClearCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete all recorded command usage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := state.ClearUsage(ctx.Config.StateDir()); err != nil {
+				return err
+			}
+			fmt.Println("Cleared recorded command usage")
+			return nil
+		},
+	}
+}
+
+// recordUsage appends the finished command to the local usage records when
+// telemetry is enabled. Long-running commands are not recorded.
+func recordUsage(cmd *cobra.Command, started time.Time, err error) {
+	if cmd == nil || ctx.Config == nil || !ctx.Config.Telemetry.Enabled {
+		return
+	}
+	switch cmd.Name() {
+	case "daemon", "serve":
+		return
+	}
+
+	result := models.UsageResultOK
+	switch {
+	case errors.Is(err, context.Canceled):
+		result = models.UsageResultInterrupted
+	case err != nil:
+		result = models.UsageResultError
+	}
+
+	record := &models.UsageRecord{
+		Command:    strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		StartedAt:  started.UTC(),
+		DurationMs: time.Since(started).Milliseconds(),
+		Result:     result,
+		Version:    version.Short(),
+	}
+	if err := state.RecordUsage(ctx.Config.StateDir(), record); err != nil && ctx.Logger != nil {
+		ctx.Logger.Debugf("Failed to record command usage: %v", err)
+	}
+}
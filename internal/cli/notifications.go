@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewNotificationsCmd creates the notifications command for the retry queue.
+func NewNotificationsCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notifications",
+		Short: "Inspect and redeliver queued notifications",
+		Long: `When a notification sink rejects a delivery or cannot be reached, the
+daemon and serve commands queue the notification under the state directory
+and retry it with exponential backoff (notifications.retry in the config).
+A notification is dropped after the last attempt fails.`,
+	}
+
+	cmd.AddCommand(
+		newNotificationsListCmd(logger),
+		newNotificationsFlushCmd(logger),
+	)
+
+	return cmd
+}
+
+func newNotificationsListCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List notifications awaiting redelivery",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queue := newNotificationQueue(ctx.Config.Notifications, ctx.Config.StateDir(), logger)
+			queued, err := queue.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if len(queued) == 0 && OutputFormat(outputFormat) == OutputFormatTable {
+				fmt.Println("No queued notifications")
+				return nil
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderQueuedNotifications(queued)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+func newNotificationsFlushCmd(logger *logrus.Logger) *cobra.Command {
+	var dueOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Redeliver queued notifications now",
+		Long: `Attempts every queued notification immediately, ignoring the backoff.
+Delivered notifications leave the queue; failed ones count an attempt and
+stay queued until the last attempt.`,
+		Example: `  radb-client notifications flush
+  radb-client notifications flush --due`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			router, err := newNotificationRouter(ctx.Config.Notifications, ctx.Config.StateDir(), logger)
+			if err != nil {
+				return fmt.Errorf("invalid notifications configuration: %w", err)
+			}
+			if router == nil {
+				return errors.New("no notification sinks are configured")
+			}
+
+			result, err := router.Retry(cmd.Context(), !dueOnly)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Delivered %d, failed %d, dropped %d; %d still queued\n",
+				result.Delivered, result.Failed, result.Dropped, result.Pending)
+			if result.Failed > 0 || result.Dropped > 0 {
+				return fmt.Errorf("%d notifications could not be delivered", result.Failed+result.Dropped)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dueOnly, "due", false, "Only retry notifications whose backoff has elapsed")
+
+	return cmd
+}
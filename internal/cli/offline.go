@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// offlineMode reports whether --offline or RADB_OFFLINE selects the
+// in-memory client, and which fixture file (--fixtures or RADB_FIXTURES) to load.
+func offlineMode(cmd *cobra.Command) (bool, string) {
+	offline, _ := cmd.Flags().GetBool("offline")
+	if !cmd.Flags().Changed("offline") {
+		offline, _ = strconv.ParseBool(os.Getenv("RADB_OFFLINE"))
+	}
+
+	fixtures, _ := cmd.Flags().GetString("fixtures")
+	if fixtures == "" {
+		fixtures = os.Getenv("RADB_FIXTURES")
+	}
+
+	return offline, fixtures
+}
+
+// newOfflineClient creates an in-memory client loaded from a fixture file,
+// or from the latest route and contact snapshots when no fixture is given.
+func newOfflineClient(cmdCtx context.Context, cfg *config.Config, stateMgr state.Manager, fixtures string, logger *logrus.Logger) (api.Client, error) {
+	client := api.NewMemoryClient(cfg.API.Source, logger)
+
+	if fixtures != "" {
+		if err := client.LoadFixture(fixtures); err != nil {
+			return nil, err
+		}
+		logger.Infof("Offline mode: using fixtures from %s", fixtures)
+		return client, nil
+	}
+
+	loaded := 0
+	for _, snapshotType := range []models.SnapshotType{models.SnapshotTypeRoute, models.SnapshotTypeContact} {
+		snapshot, err := stateMgr.GetLatestSnapshot(cmdCtx, snapshotType)
+		if err != nil {
+			logger.Debugf("No %s snapshot for offline mode: %v", snapshotType, err)
+			continue
+		}
+		client.LoadSnapshot(snapshot)
+		loaded++
+	}
+
+	if loaded == 0 {
+		logger.Warn("Offline mode: no snapshots found, starting with no data")
+	} else {
+		logger.Info("Offline mode: using the latest snapshots; changes are not sent to RADb")
+	}
+
+	return client, nil
+}
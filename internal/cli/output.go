@@ -5,11 +5,23 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/daemon"
+	"github.com/bss/radb-client/internal/filtersim"
 	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/notify"
+	"github.com/bss/radb-client/internal/policy"
+	"github.com/bss/radb-client/internal/state"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
@@ -25,13 +37,39 @@ const (
 
 	// OutputFormatYAML renders output as YAML
 	OutputFormatYAML OutputFormat = "yaml"
+
+	// OutputFormatMarkdown renders output as Markdown (reports only)
+	OutputFormatMarkdown OutputFormat = "markdown"
+
+	// OutputFormatText renders output as plain text diffs (diffs only)
+	OutputFormatText OutputFormat = "text"
 )
 
+// applyDefaultOutput sets the command's -o flag to the configured default
+// format unless it was given explicitly.
+func applyDefaultOutput(cmd *cobra.Command, format string) error {
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil || flag.Changed || format == "" {
+		return nil
+	}
+
+	switch OutputFormat(format) {
+	case OutputFormatTable, OutputFormatJSON, OutputFormatYAML:
+	default:
+		return fmt.Errorf("invalid default output format %q (expected table, json, or yaml)", format)
+	}
+
+	return flag.Value.Set(format)
+}
+
 // Outputter handles formatting and rendering output.
 type Outputter struct {
-	format OutputFormat
-	writer io.Writer
-	color  bool
+	format         OutputFormat
+	writer         io.Writer
+	color          bool
+	annotations    models.Annotations
+	churn          map[string]int
+	churnThreshold int
 }
 
 // NewOutputter creates a new outputter.
@@ -46,13 +84,87 @@ func NewOutputter(format OutputFormat, writer io.Writer, enableColor bool) *Outp
 	}
 }
 
+// SetAnnotations attaches local annotations to show alongside objects in tables.
+func (o *Outputter) SetAnnotations(annotations models.Annotations) {
+	o.annotations = annotations
+}
+
+// SetChurn adds a column to route tables with each route's number of
+// recorded changes, highlighting routes with at least threshold changes.
+func (o *Outputter) SetChurn(churn map[string]int, threshold int) {
+	if churn == nil {
+		churn = make(map[string]int)
+	}
+	o.churn = churn
+	o.churnThreshold = threshold
+}
+
+// routeView is a route with its canonical reference, for JSON and YAML output.
+type routeView struct {
+	URN                string `json:"urn" yaml:"urn"`
+	models.RouteObject `yaml:",inline"`
+}
+
+func newRouteView(route *models.RouteObject) routeView {
+	return routeView{URN: route.URN(), RouteObject: *route}
+}
+
+// routeListView is a route list whose routes carry their references.
+type routeListView struct {
+	Routes    []routeView `json:"routes" yaml:"routes"`
+	Timestamp time.Time   `json:"timestamp" yaml:"timestamp"`
+	Count     int         `json:"count" yaml:"count"`
+}
+
+func newRouteListView(routes *models.RouteList) routeListView {
+	view := routeListView{
+		Routes:    make([]routeView, len(routes.Routes)),
+		Timestamp: routes.Timestamp,
+		Count:     routes.Count,
+	}
+	for i := range routes.Routes {
+		view.Routes[i] = newRouteView(&routes.Routes[i])
+	}
+	return view
+}
+
+// contactView is a contact with its canonical reference, for JSON and YAML
+// output.
+type contactView struct {
+	URN            string `json:"urn" yaml:"urn"`
+	models.Contact `yaml:",inline"`
+}
+
+func newContactView(contact *models.Contact) contactView {
+	return contactView{URN: contact.URN(), Contact: *contact}
+}
+
+// contactListView is a contact list whose contacts carry their references.
+type contactListView struct {
+	Contacts  []contactView `json:"contacts" yaml:"contacts"`
+	Timestamp time.Time     `json:"timestamp" yaml:"timestamp"`
+	Count     int           `json:"count" yaml:"count"`
+}
+
+func newContactListView(contacts *models.ContactList) contactListView {
+	view := contactListView{
+		Contacts:  make([]contactView, len(contacts.Contacts)),
+		Timestamp: contacts.Timestamp,
+		Count:     contacts.Count,
+	}
+	for i := range contacts.Contacts {
+		view.Contacts[i] = newContactView(&contacts.Contacts[i])
+	}
+	return view
+}
+
 // RenderRoutes renders a list of routes.
 func (o *Outputter) RenderRoutes(routes *models.RouteList) error {
 	switch o.format {
 	case OutputFormatJSON:
-		return o.renderJSON(routes)
+		return o.renderJSON(newRouteListView(routes))
 	case OutputFormatYAML:
-		return o.renderYAML(routes)
+		return o.renderYAML(newRouteListView(routes))
 	case OutputFormatTable:
 		return o.renderRoutesTable(routes.Routes)
 	default:
@@ -64,9 +176,9 @@ func (o *Outputter) RenderRoutes(routes *models.RouteList) error {
 func (o *Outputter) RenderContacts(contacts *models.ContactList) error {
 	switch o.format {
 	case OutputFormatJSON:
-		return o.renderJSON(contacts)
+		return o.renderJSON(newContactListView(contacts))
 	case OutputFormatYAML:
-		return o.renderYAML(contacts)
+		return o.renderYAML(newContactListView(contacts))
 	case OutputFormatTable:
 		return o.renderContactsTable(contacts.Contacts)
 	default:
@@ -97,6 +209,8 @@ func (o *Outputter) RenderDiff(diff *models.DiffResult) error {
 		return o.renderYAML(diff)
 	case OutputFormatTable:
 		return o.renderDiffTable(diff)
+	case OutputFormatText:
+		return o.renderDiffText(diff)
 	default:
 		return fmt.Errorf("unsupported output format: %s", o.format)
 	}
@@ -118,8 +232,20 @@ func (o *Outputter) renderYAML(data interface{}) error {
 
 // renderRoutesTable renders routes as a table.
 func (o *Outputter) renderRoutesTable(routes []models.RouteObject) error {
+	red := color.New(color.FgRed)
+	if !o.color {
+		color.NoColor = true
+	}
+
 	table := tablewriter.NewWriter(o.writer)
-	table.Header("Route", "Origin", "Maintainer", "Description")
+	header := []any{"Route", "Origin", "Maintainer", "Description"}
+	if o.churn != nil {
+		header = append(header, "Churn")
+	}
+	if len(o.annotations) > 0 {
+		header = append(header, "Note")
+	}
+	table.Header(header...)
 
 	for _, route := range routes {
 		descr := strings.Join(route.Descr, ", ")
@@ -131,7 +257,18 @@ func (o *Outputter) renderRoutesTable(routes []models.RouteObject) error {
 			mntBy = mntBy[:27] + "..."
 		}
 
-		table.Append(route.Route, route.Origin, mntBy, descr)
+		row := []any{route.Route, route.Origin, mntBy, descr}
+		if o.churn != nil {
+			churn := fmt.Sprintf("%d", o.churn[route.ID()])
+			if o.churnThreshold > 0 && o.churn[route.ID()] >= o.churnThreshold {
+				churn = red.Sprint(churn)
+			}
+			row = append(row, churn)
+		}
+		if len(o.annotations) > 0 {
+			row = append(row, o.annotations.Label("route", route.ID()))
+		}
+		table.Append(row...)
 	}
 
 	return table.Render()
@@ -140,10 +277,18 @@ func (o *Outputter) renderRoutesTable(routes []models.RouteObject) error {
 // renderContactsTable renders contacts as a table.
 func (o *Outputter) renderContactsTable(contacts []models.Contact) error {
 	table := tablewriter.NewWriter(o.writer)
-	table.Header("ID", "Name", "Email", "Role", "Organization")
+	if len(o.annotations) > 0 {
+		table.Header("ID", "Name", "Email", "Role", "Organization", "Note")
+	} else {
+		table.Header("ID", "Name", "Email", "Role", "Organization")
+	}
 
 	for _, contact := range contacts {
-		table.Append(contact.ID, contact.Name, contact.Email, string(contact.Role), contact.Organization)
+		if len(o.annotations) > 0 {
+			table.Append(contact.ID, contact.Name, contact.Email, string(contact.Role), contact.Organization, o.annotations.Label("contact", contact.ID))
+		} else {
+			table.Append(contact.ID, contact.Name, contact.Email, string(contact.Role), contact.Organization)
+		}
 	}
 
 	return table.Render()
@@ -152,18 +297,10 @@ func (o *Outputter) renderContactsTable(contacts []models.Contact) error {
 // renderSnapshotsTable renders snapshots as a table.
 func (o *Outputter) renderSnapshotsTable(snapshots []models.Snapshot) error {
 	table := tablewriter.NewWriter(o.writer)
-	table.Header("ID", "Type", "Timestamp", "Note", "Items")
+	table.Header("ID", "Type", "Scope", "Timestamp", "Note", "Tags", "Items")
 
 	for _, snap := range snapshots {
-		items := 0
-		if snap.Routes != nil {
-			items += snap.Routes.Count
-		}
-		if snap.Contacts != nil {
-			items += snap.Contacts.Count
-		}
-
-		table.Append(snap.ID, string(snap.Type), snap.Timestamp.Format("2006-01-02 15:04:05"), snap.Note, fmt.Sprintf("%d", items))
+		table.Append(snap.ID, string(snap.Type), scopeLabel(&snap), snap.Timestamp.Format("2006-01-02 15:04:05"), snap.Note, strings.Join(snap.Tags, ", "), fmt.Sprintf("%d", snap.ItemCount()))
 	}
 
 	return table.Render()
@@ -194,7 +331,7 @@ func (o *Outputter) renderDiffTable(diff *models.DiffResult) error {
 
 		for _, item := range diff.Added {
 			typeStr, id, details := formatDiffItem(item)
-			table.Append(typeStr, id, details)
+			table.Append(typeStr, id, o.withNote(details, typeStr, id))
 		}
 		table.Render()
 		fmt.Fprintln(o.writer)
@@ -208,7 +345,7 @@ func (o *Outputter) renderDiffTable(diff *models.DiffResult) error {
 
 		for _, item := range diff.Removed {
 			typeStr, id, details := formatDiffItem(item)
-			table.Append(typeStr, id, details)
+			table.Append(typeStr, id, o.withNote(details, typeStr, id))
 		}
 		table.Render()
 		fmt.Fprintln(o.writer)
@@ -225,7 +362,7 @@ func (o *Outputter) renderDiffTable(diff *models.DiffResult) error {
 			for i, fc := range item.FieldChanges {
 				fields[i] = fc.Field
 			}
-			table.Append(item.ObjectType, item.ID, strings.Join(fields, ", "))
+			table.Append(item.ObjectType, item.ID, o.withNote(strings.Join(fields, ", "), item.ObjectType, item.ID))
 		}
 		table.Render()
 	}
@@ -233,6 +370,78 @@ func (o *Outputter) renderDiffTable(diff *models.DiffResult) error {
 	return nil
 }
 
+// renderDiffText renders a diff as RPSL text: added and removed objects in
+// full, and a unified diff of each modified object.
+func (o *Outputter) renderDiffText(diff *models.DiffResult) error {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+	cyan := color.New(color.FgCyan)
+	bold := color.New(color.Bold)
+
+	if !o.color {
+		color.NoColor = true
+	}
+
+	// printObject prints an added or removed object with every line prefixed
+	printObject := func(item interface{}, prefix string, c *color.Color) {
+		typeStr, id, _ := formatDiffItem(item)
+		text, ok := models.ObjectRPSL(typeStr, item)
+		if !ok {
+			return
+		}
+		fmt.Fprintln(o.writer, bold.Sprintf("%s %s %s", prefix, typeStr, id))
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			fmt.Fprintln(o.writer, c.Sprint(prefix+line))
+		}
+		fmt.Fprintln(o.writer)
+	}
+
+	for _, item := range diff.Added {
+		printObject(item, "+", green)
+	}
+	for _, item := range diff.Removed {
+		printObject(item, "-", red)
+	}
+
+	for _, item := range diff.Modified {
+		text := item.RPSLDiff()
+		if text == "" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+				fmt.Fprintln(o.writer, bold.Sprint(line))
+			case strings.HasPrefix(line, "@@"):
+				fmt.Fprintln(o.writer, cyan.Sprint(line))
+			case strings.HasPrefix(line, "+"):
+				fmt.Fprintln(o.writer, green.Sprint(line))
+			case strings.HasPrefix(line, "-"):
+				fmt.Fprintln(o.writer, red.Sprint(line))
+			default:
+				fmt.Fprintln(o.writer, line)
+			}
+		}
+		fmt.Fprintln(o.writer)
+	}
+
+	fmt.Fprintf(o.writer, "%d added, %d removed, %d modified\n",
+		diff.Summary.AddedCount, diff.Summary.RemovedCount, diff.Summary.ModifiedCount)
+	return nil
+}
+
+// withNote appends an object's annotation to a table cell.
+func (o *Outputter) withNote(text, objectType, objectID string) string {
+	note := o.annotations.Label(objectType, objectID)
+	if note == "" {
+		return text
+	}
+	if text == "" {
+		return note
+	}
+	return fmt.Sprintf("%s (%s)", text, note)
+}
+
 // formatDiffItem extracts information from a diff item for display.
 func formatDiffItem(item interface{}) (typeStr, id, details string) {
 	switch v := item.(type) {
@@ -247,6 +456,13 @@ func formatDiffItem(item interface{}) (typeStr, id, details string) {
 
 // RenderChangeHistory renders changelog entries.
 func (o *Outputter) RenderChangeHistory(entries []models.ChangelogEntry) error {
+	// Entries recorded before references were introduced lack them
+	for i := range entries {
+		if entries[i].URN == "" {
+			entries[i].URN = models.ObjectURN(entries[i].ObjectType, entries[i].ObjectID)
+		}
+	}
+
 	switch o.format {
 	case OutputFormatJSON:
 		return o.renderJSON(entries)
@@ -262,7 +478,7 @@ func (o *Outputter) RenderChangeHistory(entries []models.ChangelogEntry) error {
 // renderChangeHistoryTable renders changelog entries as a table.
 func (o *Outputter) renderChangeHistoryTable(entries []models.ChangelogEntry) error {
 	table := tablewriter.NewWriter(o.writer)
-	table.Header("Timestamp", "Type", "Object Type", "Object ID", "Fields")
+	table.Header("ID", "Timestamp", "Type", "Object Type", "Object ID", "Fields", "Note")
 
 	for _, entry := range entries {
 		fields := strings.Join(entry.FieldChanges, ", ")
@@ -270,8 +486,1096 @@ func (o *Outputter) renderChangeHistoryTable(entries []models.ChangelogEntry) er
 			fields = fields[:37] + "..."
 		}
 
-		table.Append(entry.Timestamp.Format("2006-01-02 15:04:05"), string(entry.ChangeType), entry.ObjectType, entry.ObjectID, fields)
+		id := entry.EntryID()
+		table.Append(id, entry.Timestamp.Format("2006-01-02 15:04:05"), string(entry.ChangeType), entry.ObjectType, entry.ObjectID,
+			o.withNote(fields, entry.ObjectType, entry.ObjectID), o.annotations.Label(models.AnnotationTypeChange, id))
+	}
+
+	return table.Render()
+}
+
+// RenderObjectHistory renders the lifecycle of one object with the before
+// and after value of each changed field.
+func (o *Outputter) RenderObjectHistory(history *state.ObjectHistory) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(history)
+	case OutputFormatYAML:
+		return o.renderYAML(history)
+	case OutputFormatTable:
+		title := history.ObjectID
+		if history.URN != "" {
+			title = history.URN
+		}
+		status := "present"
+		if !history.Exists {
+			status = "removed"
+		}
+		fmt.Fprintf(o.writer, "%s (%s, %d changes, %s)\n", title, history.ObjectType, len(history.Events), status)
+
+		for _, event := range history.Events {
+			fmt.Fprintf(o.writer, "\n%s  %s  (entry %s, snapshot %s)\n", event.Timestamp.Local().Format("2006-01-02 15:04:05"), event.ChangeType, event.EntryID, event.SnapshotID)
+			if event.Note != "" {
+				fmt.Fprintf(o.writer, "  Note: %s\n", event.Note)
+			}
+			if label := o.annotations.Label(models.AnnotationTypeChange, event.EntryID); label != "" {
+				fmt.Fprintf(o.writer, "  Annotation: %s\n", label)
+			}
+			for _, field := range event.Fields {
+				switch event.ChangeType {
+				case models.ChangeTypeAdded:
+					fmt.Fprintf(o.writer, "  + %s: %s\n", field.Field, formatFieldValue(field.NewValue))
+				case models.ChangeTypeRemoved:
+					fmt.Fprintf(o.writer, "  - %s: %s\n", field.Field, formatFieldValue(field.OldValue))
+				default:
+					fmt.Fprintf(o.writer, "  ~ %s: %s -> %s\n", field.Field, formatFieldValue(field.OldValue), formatFieldValue(field.NewValue))
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// formatFieldValue renders a JSON field value for reading: strings
+// unquoted, lists comma-separated, and missing values as (none).
+func formatFieldValue(value json.RawMessage) string {
+	var decoded interface{}
+	if err := json.Unmarshal(value, &decoded); err != nil {
+		return string(value)
+	}
+	switch v := decoded.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if v == "" {
+			return "(none)"
+		}
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return "(none)"
+		}
+		items := make([]string, len(v))
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				items[i] = s
+			} else {
+				data, _ := json.Marshal(item)
+				items[i] = string(data)
+			}
+		}
+		return strings.Join(items, ", ")
+	}
+	return string(value)
+}
+
+// RenderSearchDiff renders the changes in search results since a baseline.
+func (o *Outputter) RenderSearchDiff(diff *api.SearchDiff) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(diff)
+	case OutputFormatYAML:
+		return o.renderYAML(diff)
+	case OutputFormatTable:
+		return o.renderSearchDiffTable(diff)
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// renderSearchDiffTable renders search result changes as a table.
+func (o *Outputter) renderSearchDiffTable(diff *api.SearchDiff) error {
+	fmt.Fprintf(o.writer, "Query %q: %d added, %d removed, %d unchanged\n",
+		diff.Query, len(diff.Added), len(diff.Removed), diff.Unchanged)
+	if !diff.HasChanges() {
+		return nil
+	}
+
+	table := tablewriter.NewWriter(o.writer)
+	table.Header("Change", "Object", "Details")
+	for _, group := range []struct {
+		change  string
+		results []map[string]interface{}
+	}{{"added", diff.Added}, {"removed", diff.Removed}} {
+		for _, result := range group.results {
+			table.Append(group.change, api.SearchResultKey(result), formatSearchFields(result))
+		}
+	}
+	return table.Render()
+}
+
+// formatSearchFields formats a search result's fields as sorted key=value pairs.
+func formatSearchFields(result map[string]interface{}) string {
+	keys := make([]string, 0, len(result))
+	for key := range result {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, result[key]))
+	}
+	return strings.Join(fields, " ")
+}
+
+// RenderCleanupResult renders a snapshot cleanup report.
+func (o *Outputter) RenderCleanupResult(result *state.CleanupResult) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(result)
+	case OutputFormatYAML:
+		return o.renderYAML(result)
+	case OutputFormatTable:
+		return o.renderCleanupTable(result)
+	case OutputFormatMarkdown:
+		return o.renderCleanupMarkdown(result)
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// renderCleanupTable renders a cleanup report as tables.
+func (o *Outputter) renderCleanupTable(result *state.CleanupResult) error {
+	action := "Deleted"
+	if result.DryRun {
+		action = "Would delete"
+	}
+
+	fmt.Fprintf(o.writer, "Snapshots: %d total, %d kept, %s %d (%s)\n",
+		result.TotalSnapshots, result.Kept, strings.ToLower(action), result.Deleted, formatBytes(result.BytesReclaimed))
+	if result.OldestKept != nil {
+		fmt.Fprintf(o.writer, "Oldest kept: %s (%s)\n", result.OldestKept.ID, result.OldestKept.Timestamp.Format("2006-01-02 15:04:05"))
+	}
+	if len(result.ProtectedIDs) > 0 {
+		fmt.Fprintf(o.writer, "Kept because tagged: %s\n", strings.Join(result.ProtectedIDs, ", "))
+	}
+	fmt.Fprintln(o.writer)
+
+	table := tablewriter.NewWriter(o.writer)
+	table.Header("Type", "Total", "Kept", action, "Reclaimed")
+	for _, snapshotType := range sortedCleanupTypes(result) {
+		summary := result.ByType[snapshotType]
+		table.Append(string(snapshotType), fmt.Sprintf("%d", summary.Total), fmt.Sprintf("%d", summary.Kept),
+			fmt.Sprintf("%d", summary.Deleted), formatBytes(summary.BytesReclaimed))
+	}
+	if err := table.Render(); err != nil {
+		return err
+	}
+
+	if len(result.DeletedSnapshots) > 0 {
+		fmt.Fprintf(o.writer, "\n%s:\n", action)
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("ID", "Type", "Timestamp", "Size")
+		for _, entry := range result.DeletedSnapshots {
+			table.Append(entry.ID, string(entry.Type), entry.Timestamp.Format("2006-01-02 15:04:05"), formatBytes(entry.Size))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+	}
+
+	for _, msg := range result.Errors {
+		fmt.Fprintf(o.writer, "Error: %s\n", msg)
+	}
+
+	return nil
+}
+
+// renderCleanupMarkdown renders a cleanup report as Markdown for tickets.
+func (o *Outputter) renderCleanupMarkdown(result *state.CleanupResult) error {
+	action := "Deleted"
+	title := "Snapshot Cleanup Report"
+	if result.DryRun {
+		action = "Would delete"
+		title += " (dry run)"
+	}
+
+	w := o.writer
+	fmt.Fprintf(w, "## %s\n\n", title)
+	fmt.Fprintf(w, "- Total snapshots: %d\n", result.TotalSnapshots)
+	fmt.Fprintf(w, "- Kept: %d\n", result.Kept)
+	fmt.Fprintf(w, "- %s: %d\n", action, result.Deleted)
+	fmt.Fprintf(w, "- Space reclaimed: %s\n", formatBytes(result.BytesReclaimed))
+	if result.OldestKept != nil {
+		fmt.Fprintf(w, "- Oldest kept: `%s` (%s)\n", result.OldestKept.ID, result.OldestKept.Timestamp.Format(time.RFC3339))
+	}
+	if len(result.ProtectedIDs) > 0 {
+		fmt.Fprintf(w, "- Kept because tagged: `%s`\n", strings.Join(result.ProtectedIDs, "`, `"))
+	}
+
+	fmt.Fprintf(w, "\n### By type\n\n")
+	fmt.Fprintf(w, "| Type | Total | Kept | %s | Reclaimed |\n", action)
+	fmt.Fprintf(w, "|------|------:|-----:|-----:|----------:|\n")
+	for _, snapshotType := range sortedCleanupTypes(result) {
+		summary := result.ByType[snapshotType]
+		fmt.Fprintf(w, "| %s | %d | %d | %d | %s |\n",
+			snapshotType, summary.Total, summary.Kept, summary.Deleted, formatBytes(summary.BytesReclaimed))
+	}
+
+	if len(result.DeletedSnapshots) > 0 {
+		fmt.Fprintf(w, "\n### %s\n\n", action)
+		fmt.Fprintf(w, "| ID | Type | Timestamp | Size |\n")
+		fmt.Fprintf(w, "|----|------|-----------|-----:|\n")
+		for _, entry := range result.DeletedSnapshots {
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n",
+				entry.ID, entry.Type, entry.Timestamp.Format(time.RFC3339), formatBytes(entry.Size))
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(w, "\n### Errors\n\n")
+		for _, msg := range result.Errors {
+			fmt.Fprintf(w, "- %s\n", msg)
+		}
+	}
+
+	return nil
+}
+
+// sortedCleanupTypes returns the snapshot types in a cleanup report in name order.
+func sortedCleanupTypes(result *state.CleanupResult) []models.SnapshotType {
+	types := make([]models.SnapshotType, 0, len(result.ByType))
+	for snapshotType := range result.ByType {
+		types = append(types, snapshotType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// formatBytes formats a byte count with a binary unit suffix.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// RenderStatus renders the status report.
+func (o *Outputter) RenderStatus(report *statusReport) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(report)
+	case OutputFormatYAML:
+		return o.renderYAML(report)
+	case OutputFormatTable:
+		return o.renderStatusTable(report)
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// renderStatusTable renders the status report as labeled sections.
+func (o *Outputter) renderStatusTable(report *statusReport) error {
+	const timeFormat = "2006-01-02 15:04:05"
+
+	fmt.Fprintln(o.writer, "Authentication:")
+	if report.Username != "" {
+		fmt.Fprintf(o.writer, "  User: %s (%s)\n", report.Username, report.Authentication)
+	} else {
+		fmt.Fprintf(o.writer, "  User: %s\n", report.Authentication)
+	}
+
+	status := report.API
+	fmt.Fprintln(o.writer, "\nAPI:")
+	fmt.Fprintf(o.writer, "  Request rate: %s\n", formatRequestRate(status.RequestsPerMinute, status.ConfiguredRequestsPerMinute))
+	classes := make([]string, 0, len(status.EndpointRequestsPerMinute))
+	for class := range status.EndpointRequestsPerMinute {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		fmt.Fprintf(o.writer, "  Request rate (%s): %d/min\n", class, status.EndpointRequestsPerMinute[class])
+	}
+	if status.RateLimit != nil {
+		budget := fmt.Sprintf("%d", status.RateLimit.Remaining)
+		if status.RateLimit.Limit > 0 {
+			budget += fmt.Sprintf(" of %d", status.RateLimit.Limit)
+		}
+		if status.RateLimit.Reset != nil {
+			budget += fmt.Sprintf(", resets %s", status.RateLimit.Reset.Local().Format(timeFormat))
+		}
+		fmt.Fprintf(o.writer, "  Remaining budget: %s\n", budget)
+	} else {
+		fmt.Fprintln(o.writer, "  Remaining budget: not reported by server")
+	}
+	if status.LastRequestAt != nil {
+		fmt.Fprintf(o.writer, "  Last request: %s\n", status.LastRequestAt.Local().Format(timeFormat))
+	} else {
+		fmt.Fprintln(o.writer, "  Last request: none recorded")
+	}
+	if status.LastError != "" {
+		fmt.Fprintf(o.writer, "  Last error: %s (%s)\n", status.LastError, status.LastErrorAt.Local().Format(timeFormat))
+		fmt.Fprintf(o.writer, "  Consecutive failures: %d\n", status.ConsecutiveFailures)
+	}
+
+	fmt.Fprintln(o.writer, "\nDaemon:")
+	fmt.Fprintf(o.writer, "  State: %s\n", report.DaemonState)
+	if daemon := report.Daemon; daemon != nil {
+		fmt.Fprintf(o.writer, "  Process: %s (pid %d), started %s\n", daemon.Command, daemon.PID, daemon.StartedAt.Local().Format(timeFormat))
+		fmt.Fprintf(o.writer, "  Last heartbeat: %s\n", daemon.HeartbeatAt.Local().Format(timeFormat))
+		if daemon.LastCheckAt != nil {
+			fmt.Fprintf(o.writer, "  Last check: %s\n", daemon.LastCheckAt.Local().Format(timeFormat))
+		}
+		if daemon.LastCheckError != "" {
+			fmt.Fprintf(o.writer, "  Last check error: %s\n", daemon.LastCheckError)
+		}
+		if daemon.RequestsPerMinute > 0 {
+			fmt.Fprintf(o.writer, "  Request rate: %d/min\n", daemon.RequestsPerMinute)
+		}
+	}
+
+	return nil
+}
+
+// RenderDaemonHealth renders a running daemon's health report.
+func (o *Outputter) RenderDaemonHealth(report *daemon.HealthReport) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(report)
+	case OutputFormatYAML:
+		return o.renderYAML(report)
+	case OutputFormatTable:
+		return o.renderDaemonHealthTable(report)
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// renderDaemonHealthTable renders a health report as a summary and a table
+// of checks.
+func (o *Outputter) renderDaemonHealthTable(report *daemon.HealthReport) error {
+	const timeFormat = "2006-01-02 15:04:05"
+
+	state := "ready"
+	switch {
+	case !report.Live:
+		state = "not live"
+	case !report.Ready:
+		state = "live, not ready"
+	}
+	fmt.Fprintf(o.writer, "State: %s\n", state)
+	fmt.Fprintf(o.writer, "Started: %s\n", report.StartedAt.Local().Format(timeFormat))
+	if report.LastSuccessAt != nil {
+		fmt.Fprintf(o.writer, "Last successful check: %s\n", report.LastSuccessAt.Local().Format(timeFormat))
+	} else {
+		fmt.Fprintln(o.writer, "Last successful check: none")
+	}
+	if report.LastError != "" {
+		fmt.Fprintf(o.writer, "Last check error: %s (%s)\n", report.LastError, report.LastCheckAt.Local().Format(timeFormat))
 	}
+	fmt.Fprintln(o.writer)
 
+	table := tablewriter.NewWriter(o.writer)
+	table.Header("Check", "Status", "Detail")
+	for _, check := range report.Checks {
+		status := "ok"
+		if !check.OK {
+			status = "FAIL"
+		}
+		table.Append(check.Name, status, check.Detail)
+	}
 	return table.Render()
 }
+
+// formatRequestRate formats the client's request rate, noting when it has
+// adapted away from the configured rate.
+func formatRequestRate(current, configured int) string {
+	if configured == 0 || current == configured {
+		return fmt.Sprintf("%d/min", current)
+	}
+	return fmt.Sprintf("%d/min (configured %d/min)", current, configured)
+}
+
+// RenderJournals renders a list of bulk operation journals.
+func (o *Outputter) RenderJournals(journals []*models.BulkJournal) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(journals)
+	case OutputFormatYAML:
+		return o.renderYAML(journals)
+	case OutputFormatTable:
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("ID", "Operation", "Updated", "Items", "Succeeded", "Failed", "Pending")
+		for _, journal := range journals {
+			pending, succeeded, failed := journal.Counts()
+			table.Append(journal.ID, string(journal.Operation), journal.UpdatedAt.Format("2006-01-02 15:04:05"),
+				fmt.Sprintf("%d", len(journal.Items)), fmt.Sprintf("%d", succeeded), fmt.Sprintf("%d", failed), fmt.Sprintf("%d", pending))
+		}
+		return table.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderJournal renders a single journal with the status of each item.
+func (o *Outputter) RenderJournal(journal *models.BulkJournal) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(journal)
+	case OutputFormatYAML:
+		return o.renderYAML(journal)
+	case OutputFormatTable:
+		pending, succeeded, failed := journal.Counts()
+		fmt.Fprintf(o.writer, "%s (%s), started %s\n", journal.ID, journal.Operation, journal.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(o.writer, "%d succeeded, %d failed, %d pending\n\n", succeeded, failed, pending)
+
+		if len(journal.Runs) > 0 {
+			runs := tablewriter.NewWriter(o.writer)
+			runs.Header("Run", "Started", "Duration", "Attempted", "Succeeded", "Failed")
+			for i, run := range journal.Runs {
+				runs.Append(fmt.Sprintf("%d", i+1), run.StartedAt.Format("2006-01-02 15:04:05"),
+					run.FinishedAt.Sub(run.StartedAt).Round(time.Millisecond).String(),
+					fmt.Sprintf("%d", run.Total), fmt.Sprintf("%d", run.Succeeded), fmt.Sprintf("%d", run.Failed))
+			}
+			if err := runs.Render(); err != nil {
+				return err
+			}
+			fmt.Fprintln(o.writer)
+		}
+
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Item", "Status", "Attempts", "Error")
+		for _, item := range journal.Items {
+			table.Append(item.ID, string(item.Status), fmt.Sprintf("%d", item.Attempts), item.Error)
+		}
+		return table.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderTransactions renders interrupted transactions and their next step.
+func (o *Outputter) RenderTransactions(transactions []*models.Transaction) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(transactions)
+	case OutputFormatYAML:
+		return o.renderYAML(transactions)
+	case OutputFormatTable:
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("ID", "Operation", "Route", "Updated", "Next Step", "Error")
+		for _, tx := range transactions {
+			var lastError string
+			for _, step := range tx.Steps {
+				if step.Error != "" {
+					lastError = step.Error
+				}
+			}
+			table.Append(tx.ID, string(tx.Operation), tx.ObjectID, tx.UpdatedAt.Format("2006-01-02 15:04:05"),
+				string(tx.NextStep()), lastError)
+		}
+		return table.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderQueuedNotifications renders notifications awaiting redelivery.
+func (o *Outputter) RenderQueuedNotifications(queued []notify.QueuedNotification) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(queued)
+	case OutputFormatYAML:
+		return o.renderYAML(queued)
+	case OutputFormatTable:
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("ID", "Sink", "Team", "Snapshot", "Items", "Attempts", "Next Attempt", "Last Error")
+		for _, q := range queued {
+			n := q.Notification
+			table.Append(q.ID, q.Sink, n.Team, n.SnapshotID, fmt.Sprintf("%d", len(n.Changes)+len(n.Violations)),
+				fmt.Sprintf("%d", q.Attempts), q.NextAttempt.Format("2006-01-02 15:04:05"), q.LastError)
+		}
+		return table.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderLintFindings renders configuration lint findings.
+func (o *Outputter) RenderLintFindings(findings []config.LintFinding) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(findings)
+	case OutputFormatYAML:
+		return o.renderYAML(findings)
+	case OutputFormatTable:
+		for _, finding := range findings {
+			key := finding.Key
+			if key == "" {
+				key = "config"
+			}
+			fmt.Fprintf(o.writer, "%s  %s: %s\n", strings.ToUpper(string(finding.Severity)), key, finding.Message)
+			fmt.Fprintf(o.writer, "    %s\n", finding.Explanation)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderThreeWayDiff renders the changes two snapshots made since their
+// base, with what each side did to conflicting routes.
+func (o *Outputter) RenderThreeWayDiff(diff *state.ThreeWayDiff) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(diff)
+	case OutputFormatYAML:
+		return o.renderYAML(diff)
+	case OutputFormatTable:
+		fmt.Fprintf(o.writer, "Base: %s\nOurs: %s\nTheirs: %s\n\n", diff.BaseID, diff.OursID, diff.TheirsID)
+		if len(diff.Changes) == 0 && len(diff.Conflicts) == 0 {
+			fmt.Fprintln(o.writer, "No changes since the base")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Type", "Object", "Changed By")
+		for _, change := range diff.Changes {
+			table.Append(change.ObjectType, change.ID, change.Side)
+		}
+		for _, change := range diff.Conflicts {
+			table.Append(change.ObjectType, change.ID, "CONFLICT ("+string(change.Conflict)+")")
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+
+		for _, change := range diff.Conflicts {
+			if change.ObjectType != "route" {
+				continue
+			}
+			base, _ := change.Base.(*models.RouteObject)
+			for _, side := range []struct {
+				name   string
+				object interface{}
+			}{{"ours", change.Ours}, {"theirs", change.Theirs}} {
+				route, _ := side.object.(*models.RouteObject)
+				if route == nil {
+					fmt.Fprintf(o.writer, "\n%s in %s: deleted\n", change.ID, side.name)
+					continue
+				}
+				fmt.Fprintf(o.writer, "\n%s in %s:\n", change.ID, side.name)
+				writeRouteDiff(o.writer, base, route)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderSelftest renders the steps of a self-test run.
+func (o *Outputter) RenderSelftest(report *selftestReport) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(report)
+	case OutputFormatYAML:
+		return o.renderYAML(report)
+	case OutputFormatTable:
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Step", "Result", "Time", "Error")
+		for _, step := range report.Steps {
+			result := "ok"
+			if !step.Passed {
+				result = "FAILED"
+			}
+			table.Append(step.Name, result, fmt.Sprintf("%dms", step.DurationMs), step.Error)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+
+		result := "PASSED"
+		if !report.Passed {
+			result = "FAILED"
+		}
+		fmt.Fprintf(o.writer, "\nSelf-test of %s: %s\n", report.Route, result)
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderCoverage renders a contact coverage audit.
+func (o *Outputter) RenderCoverage(report *audit.CoverageReport) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(report)
+	case OutputFormatYAML:
+		return o.renderYAML(report)
+	case OutputFormatTable:
+		header := []string{"Type", "Object"}
+		for _, role := range report.RequiredRoles {
+			header = append(header, strings.ToUpper(string(role)))
+		}
+		table := tablewriter.NewWriter(o.writer)
+		table.Header(header)
+		for _, object := range report.Objects {
+			row := []string{object.Type, object.Name}
+			for _, role := range report.RequiredRoles {
+				if ids := object.Contacts[role]; len(ids) > 0 {
+					row = append(row, strings.Join(ids, ", "))
+				} else {
+					row = append(row, "MISSING")
+				}
+			}
+			table.Append(row)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+
+		if report.EmailsChecked > 0 {
+			fmt.Fprintf(o.writer, "\nChecked %d contact email addresses\n", report.EmailsChecked)
+		}
+		if len(report.Findings) == 0 {
+			fmt.Fprintln(o.writer, "\nNo coverage gaps found")
+			return nil
+		}
+
+		fmt.Fprintf(o.writer, "\n%d findings:\n", len(report.Findings))
+		findings := tablewriter.NewWriter(o.writer)
+		findings.Header("Kind", "Type", "Object", "Detail")
+		for _, finding := range report.Findings {
+			findings.Append(string(finding.Kind), finding.ObjectType, finding.Object, finding.Detail)
+		}
+		return findings.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderViolations renders route assertion violations.
+func (o *Outputter) RenderViolations(violations []audit.Violation) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(violations)
+	case OutputFormatYAML:
+		return o.renderYAML(violations)
+	case OutputFormatTable:
+		if len(violations) == 0 {
+			fmt.Fprintln(o.writer, "No assertion violations found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Assertion", "Route", "Origin", "Maintainers", "Detail")
+		for _, v := range violations {
+			table.Append(v.Assertion, v.Route, v.Origin, strings.Join(v.MntBy, ", "), v.Detail)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.writer, "\n%d violations\n", len(violations))
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderDualStack renders a dual-stack audit report.
+func (o *Outputter) RenderDualStack(report *audit.DualStackReport) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(report)
+	case OutputFormatYAML:
+		return o.renderYAML(report)
+	case OutputFormatTable:
+		// Automatic header formatting would split IPv4 into I PV 4
+		noAutoFormat := tablewriter.WithHeaderAutoFormat(tw.Off)
+
+		table := tablewriter.NewTable(o.writer, noAutoFormat)
+		table.Header("ORIGIN", "ROUTE", "ROUTE6")
+		for _, origin := range report.Origins {
+			table.Append(origin.Origin, fmt.Sprintf("%d", origin.IPv4Routes), fmt.Sprintf("%d", origin.IPv6Routes))
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		fmt.Fprintln(o.writer)
+
+		if len(report.Findings) == 0 {
+			fmt.Fprintln(o.writer, "No dual-stack gaps found")
+			return nil
+		}
+
+		table = tablewriter.NewTable(o.writer, noAutoFormat)
+		table.Header("ORIGIN", "IPV4", "IPV6", "DETAIL")
+		for _, f := range report.Findings {
+			table.Append(f.Origin, f.IPv4, f.IPv6, f.Detail)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.writer, "\n%d gaps\n", len(report.Findings))
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderUsage renders command usage summaries.
+func (o *Outputter) RenderUsage(summaries []models.UsageSummary) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(summaries)
+	case OutputFormatYAML:
+		return o.renderYAML(summaries)
+	case OutputFormatTable:
+		if len(summaries) == 0 {
+			fmt.Fprintln(o.writer, "No command usage recorded")
+			return nil
+		}
+
+		// Automatic header formatting would split P95 into P 95
+		table := tablewriter.NewTable(o.writer, tablewriter.WithHeaderAutoFormat(tw.Off))
+		table.Header("COMMAND", "RUNS", "ERRORS", "ERROR RATE", "MEAN", "P95", "MAX", "LAST RUN")
+		for _, s := range summaries {
+			table.Append(
+				s.Command,
+				fmt.Sprintf("%d", s.Runs),
+				fmt.Sprintf("%d", s.Errors),
+				fmt.Sprintf("%.0f%%", s.ErrorRate()*100),
+				formatMillis(s.MeanMs),
+				formatMillis(s.P95Ms),
+				formatMillis(s.MaxMs),
+				s.LastRunAt.Local().Format("2006-01-02 15:04"),
+			)
+		}
+		return table.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderActivitySummary renders what changed in local data over a period.
+func (o *Outputter) RenderActivitySummary(summary *models.ActivitySummary) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(summary)
+	case OutputFormatYAML:
+		return o.renderYAML(summary)
+	case OutputFormatTable:
+		return o.renderActivitySummaryTable(summary)
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// maxSummaryEvents limits the notable events listed by the summary table.
+const maxSummaryEvents = 10
+
+// renderActivitySummaryTable renders an activity summary as a short narrative.
+func (o *Outputter) renderActivitySummaryTable(summary *models.ActivitySummary) error {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+	yellow := color.New(color.FgYellow)
+
+	if !o.color {
+		color.NoColor = true
+	}
+
+	fmt.Fprintf(o.writer, "From %s to %s\n\n",
+		summary.Since.Local().Format("2006-01-02 15:04"),
+		summary.Until.Local().Format("2006-01-02 15:04"))
+
+	snapshots := fmt.Sprintf("%d taken", summary.Snapshots)
+	if summary.Snapshots > 0 {
+		var byType []string
+		for _, snapshotType := range []models.SnapshotType{models.SnapshotTypeRoute, models.SnapshotTypeContact, models.SnapshotTypeFull} {
+			if n := summary.SnapshotsByType[snapshotType]; n > 0 {
+				byType = append(byType, fmt.Sprintf("%d %s", n, snapshotType))
+			}
+		}
+		snapshots += " (" + strings.Join(byType, ", ") + ")"
+	}
+	fmt.Fprintf(o.writer, "Snapshots:  %s\n", snapshots)
+
+	if net, ok := summary.NetRoutes(); ok {
+		change := fmt.Sprintf("%+d", net)
+		switch {
+		case net > 0:
+			change = green.Sprint(change)
+		case net < 0:
+			change = red.Sprint(change)
+		}
+		fmt.Fprintf(o.writer, "Routes:     %d -> %d (%s)\n", *summary.RoutesBefore, *summary.RoutesAfter, change)
+	} else {
+		fmt.Fprintln(o.writer, "Routes:     no route snapshot in this period")
+	}
+
+	fmt.Fprintf(o.writer, "Changes:    %s added, %s removed, %s modified\n",
+		green.Sprintf("%d", summary.Changes[models.ChangeTypeAdded]),
+		red.Sprintf("%d", summary.Changes[models.ChangeTypeRemoved]),
+		yellow.Sprintf("%d", summary.Changes[models.ChangeTypeModified]))
+
+	failed := fmt.Sprintf("%d failed", summary.WritesFailed)
+	if summary.WritesFailed > 0 {
+		failed = red.Sprint(failed)
+	}
+	fmt.Fprintf(o.writer, "Writes:     %d succeeded, %s\n", summary.WritesSucceeded, failed)
+
+	if len(summary.TopMaintainers) > 0 {
+		fmt.Fprintln(o.writer, "\nMost changed maintainers:")
+		for _, m := range summary.TopMaintainers {
+			fmt.Fprintf(o.writer, "  %-24s %d\n", m.Maintainer, m.Changes)
+		}
+	}
+
+	if len(summary.Events) > 0 {
+		fmt.Fprintln(o.writer, "\nNotable events:")
+		events := summary.Events
+		if len(events) > maxSummaryEvents {
+			events = events[len(events)-maxSummaryEvents:]
+		}
+		for _, event := range events {
+			kind := red.Sprintf("%-14s", event.Kind)
+			if event.Kind == models.ActivityOriginChange {
+				kind = yellow.Sprintf("%-14s", event.Kind)
+			}
+			fmt.Fprintf(o.writer, "  %s  %s  %s\n",
+				event.Timestamp.Local().Format("2006-01-02 15:04"), kind, strings.TrimSpace(event.ObjectID+" "+event.Detail))
+		}
+		if more := len(summary.Events) - len(events); more > 0 {
+			fmt.Fprintf(o.writer, "  ... and %d earlier (use -o json for all)\n", more)
+		}
+	}
+	return nil
+}
+
+// formatMillis formats a duration in milliseconds for display.
+func formatMillis(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Second {
+		return d.String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// RenderBaselines renders named snapshot baselines.
+func (o *Outputter) RenderBaselines(baselines []state.Baseline) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(baselines)
+	case OutputFormatYAML:
+		return o.renderYAML(baselines)
+	case OutputFormatTable:
+		if len(baselines) == 0 {
+			fmt.Fprintln(o.writer, "No baselines set")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Name", "Snapshot", "Type", "Taken")
+		for _, b := range baselines {
+			table.Append(b.Name, b.SnapshotID, string(b.Type), b.Timestamp.Local().Format("2006-01-02 15:04:05"))
+		}
+		return table.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderAuditQueries renders the available audit queries.
+func (o *Outputter) RenderAuditQueries(queries []audit.Query) error {
+	type queryView struct {
+		Name        string `json:"name" yaml:"name"`
+		Description string `json:"description" yaml:"description"`
+		Source      string `json:"source" yaml:"source"`
+	}
+	views := make([]queryView, 0, len(queries))
+	for _, query := range queries {
+		source := "saved"
+		if query.Builtin {
+			source = "built-in"
+		}
+		views = append(views, queryView{Name: query.Name, Description: query.Description, Source: source})
+	}
+
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(views)
+	case OutputFormatYAML:
+		return o.renderYAML(views)
+	case OutputFormatTable:
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Name", "Source", "Description")
+		for _, v := range views {
+			table.Append(v.Name, v.Source, v.Description)
+		}
+		return table.Render()
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderQueryResults renders the objects reported by audit queries.
+func (o *Outputter) RenderQueryResults(results []audit.QueryResult) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(results)
+	case OutputFormatYAML:
+		return o.renderYAML(results)
+	case OutputFormatTable:
+		if len(results) == 0 {
+			fmt.Fprintln(o.writer, "No objects found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Query", "Type", "Object", "Detail")
+		for _, r := range results {
+			table.Append(r.Query, r.ObjectType, r.Object, r.Detail)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.writer, "\n%d objects\n", len(results))
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderRouteAt renders a route's past state alongside its current state.
+func (o *Outputter) RenderRouteAt(view *routeAtTimeView) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(view)
+	case OutputFormatYAML:
+		return o.renderYAML(view)
+	case OutputFormatTable:
+		const timeFormat = "2006-01-02 15:04:05"
+
+		fmt.Fprintf(o.writer, "%s as of %s\n", view.ID, view.At.Local().Format(timeFormat))
+		if view.SnapshotID != "" {
+			fmt.Fprintf(o.writer, "Resolved from snapshot %s and %d changelog entries\n\n", view.SnapshotID, view.Replayed)
+		} else {
+			fmt.Fprintf(o.writer, "Resolved from %d changelog entries\n\n", view.Replayed)
+		}
+
+		if view.Then == nil {
+			fmt.Fprintln(o.writer, "The route did not exist then")
+		} else {
+			fmt.Fprint(o.writer, view.Then.ToRPSL())
+		}
+
+		fmt.Fprintln(o.writer, "\nChanges since then:")
+		switch {
+		case view.Then == nil && view.Current == nil:
+			fmt.Fprintln(o.writer, "  None; the route does not exist now either")
+		case view.Current == nil:
+			fmt.Fprintln(o.writer, "  The route has since been deleted")
+		case view.Then != nil && len(view.Changes) == 0:
+			fmt.Fprintln(o.writer, "  None")
+		default:
+			writeRouteDiff(o.writer, view.Then, view.Current)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderFilterSimulation renders the predicted filtering of a proposed route.
+func (o *Outputter) RenderFilterSimulation(result *filtersim.Result) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(result)
+	case OutputFormatYAML:
+		return o.renderYAML(result)
+	case OutputFormatTable:
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Check", "Result", "Detail")
+		for _, check := range result.Checks {
+			outcome := string(check.Result)
+			if check.Result == filtersim.CheckFail {
+				outcome = "FAIL"
+			}
+			table.Append(check.Name, outcome, check.Detail)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+
+		verdict := "ACCEPTED"
+		if !result.Accepted {
+			verdict = "FILTERED"
+		}
+		fmt.Fprintf(o.writer, "\n%s %s: %s\n", result.Route, result.Origin, verdict)
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderAutNumPolicy renders the parsed routing policy of an aut-num.
+func (o *Outputter) RenderAutNumPolicy(asn string, p *policy.Policy) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(p)
+	case OutputFormatYAML:
+		return o.renderYAML(p)
+	case OutputFormatTable:
+		table := tablewriter.NewWriter(o.writer)
+		table.Header("Direction", "Family", "Peer", "Actions", "Filter")
+		for _, rule := range p.Rules {
+			peer := rule.Peer
+			if rule.PeerRouter != "" {
+				peer += " " + rule.PeerRouter
+			}
+			if rule.LocalRouter != "" {
+				peer += " at " + rule.LocalRouter
+			}
+			table.Append(string(rule.Direction), rule.Family(), peer, strings.Join(rule.Actions, "; "), rule.Filter)
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(o.writer, "\n%s: %d rules\n", asn, len(p.Rules))
+		for _, value := range p.Unparsed {
+			fmt.Fprintf(o.writer, "Not parsed: %s\n", value)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// RenderPolicyDiff renders the peer-level changes between two policies.
+func (o *Outputter) RenderPolicyDiff(changes []policy.Change) error {
+	switch o.format {
+	case OutputFormatJSON:
+		return o.renderJSON(changes)
+	case OutputFormatYAML:
+		return o.renderYAML(changes)
+	case OutputFormatTable:
+		if len(changes) == 0 {
+			fmt.Fprintln(o.writer, "No policy changes")
+			return nil
+		}
+		for _, change := range changes {
+			marker := "~"
+			switch change.Kind {
+			case policy.RuleAdded:
+				marker = "+"
+			case policy.RuleRemoved:
+				marker = "-"
+			}
+			fmt.Fprintf(o.writer, "%s %s\n", marker, change)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// maxPreflightItems caps the items listed by a preflight warning.
+const maxPreflightItems = 10
+
+// preflightJournal probes write authority once for each maintainer of the
+// journal items at indexes and warns about the items none of whose
+// maintainers grant it, before a long bulk run fails on them halfway. Items
+// without maintainers, and clients that cannot probe, are not checked.
+func preflightJournal(cmdCtx context.Context, logger *logrus.Logger, journal *models.BulkJournal, indexes []int) {
+	prober, ok := ctx.APIClient.(api.AuthorityProber)
+	if !ok {
+		return
+	}
+
+	method := bulkMethod(journal.Operation)
+	authority := make(map[string]api.MaintainerAuthority)
+	for _, index := range indexes {
+		for _, mntner := range journalItemMaintainers(journal.Items[index]) {
+			if _, probed := authority[mntner]; probed {
+				continue
+			}
+
+			result, err := prober.ProbeMaintainer(cmdCtx, mntner, method)
+			if err != nil {
+				logger.Warnf("Preflight skipped: %v", err)
+				return
+			}
+			authority[mntner] = result
+			if result.Authority == api.AuthorityDenied {
+				fmt.Fprintf(os.Stderr, "Preflight: no %s authority for %s (%s)\n", method, mntner, result.Reason)
+			}
+		}
+	}
+
+	var failing []string
+	for _, index := range indexes {
+		item := journal.Items[index]
+		maintainers := journalItemMaintainers(item)
+		if len(maintainers) == 0 {
+			continue
+		}
+
+		denied := true
+		for _, mntner := range maintainers {
+			if authority[mntner].Authority != api.AuthorityDenied {
+				denied = false
+				break
+			}
+		}
+		if denied {
+			failing = append(failing, fmt.Sprintf("%s (%s)", item.ID, strings.Join(maintainers, ", ")))
+		}
+	}
+
+	if len(failing) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %d of %d items will likely fail for lack of maintainer authority:\n", len(failing), len(indexes))
+	for i, item := range failing {
+		if i == maxPreflightItems {
+			fmt.Fprintf(os.Stderr, "  ... and %d more\n", len(failing)-maxPreflightItems)
+			break
+		}
+		fmt.Fprintf(os.Stderr, "  %s\n", item)
+	}
+}
+
+// journalItemMaintainers returns the distinct maintainers of a journal item,
+// upper-cased and sorted.
+func journalItemMaintainers(item models.JournalItem) []string {
+	var names []string
+	switch {
+	case item.Route != nil:
+		names = item.Route.MntBy
+	case item.Contact != nil:
+		names = item.Contact.RawAttributes["mnt-by"]
+	}
+
+	seen := make(map[string]bool, len(names))
+	maintainers := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			maintainers = append(maintainers, name)
+		}
+	}
+	sort.Strings(maintainers)
+	return maintainers
+}
+
+// bulkMethod returns the HTTP method an operation writes with.
+func bulkMethod(operation models.BulkOperation) string {
+	switch operation {
+	case models.BulkCreateRoutes, models.BulkCreateContacts:
+		return http.MethodPost
+	case models.BulkUpdateRoutes, models.BulkUpdateContacts:
+		return http.MethodPut
+	default:
+		return http.MethodDelete
+	}
+}
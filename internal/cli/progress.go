@@ -1,12 +1,20 @@
 package cli
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"time"
 
+	"github.com/bss/radb-client/internal/api"
 	"github.com/schollz/progressbar/v3"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
 )
 
+// progressLogInterval is how often progress is logged when stderr is not a terminal.
+const progressLogInterval = 10 * time.Second
+
 // ProgressBar wraps the progressbar library for consistent usage.
 type ProgressBar struct {
 	bar *progressbar.ProgressBar
@@ -71,3 +79,47 @@ func (pb *ProgressBar) Clear() error {
 func (pb *ProgressBar) Describe(description string) {
 	pb.bar.Describe(description)
 }
+
+// enableBulkProgress reports bulk operation progress from the shared API
+// client: a live progress bar on a terminal, periodic log lines otherwise.
+// record, if not nil, is called first with every update.
+func enableBulkProgress(logger *logrus.Logger, record api.ProgressFunc) {
+	client, ok := ctx.APIClient.(api.ProgressReporter)
+	if !ok {
+		return
+	}
+
+	interactive := term.IsTerminal(int(os.Stderr.Fd()))
+	var (
+		bar     *ProgressBar
+		lastLog time.Time
+	)
+
+	client.SetProgressFunc(func(p api.BulkProgress) {
+		if record != nil {
+			record(p)
+		}
+
+		done := p.Completed >= p.Total
+
+		if interactive {
+			if bar == nil {
+				bar = NewProgressBar(p.Total, p.Operation)
+			}
+			bar.Describe(fmt.Sprintf("%s (ETA %s)", p.Operation, p.ETA.Round(time.Second)))
+			bar.Set(p.Completed)
+			if done {
+				bar.Finish()
+				fmt.Fprintln(os.Stderr)
+				bar = nil
+			}
+			return
+		}
+
+		if done || time.Since(lastLog) >= progressLogInterval {
+			lastLog = time.Now()
+			logger.Infof("%s: %d/%d done, %d failed, ETA %s",
+				p.Operation, p.Completed, p.Total, p.Failed, p.ETA.Round(time.Second))
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/daemon"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// configSettle is how long the config file must be left alone after a
+// change before daemon.auto_reload reloads it.
+const configSettle = time.Second
+
+// daemonReloader applies a changed configuration to a running daemon, on
+// SIGHUP or, with daemon.auto_reload, when the config file changes.
+type daemonReloader struct {
+	cmd          *cobra.Command
+	runner       *daemon.Runner
+	health       *daemon.Health // Updated on reload, if set
+	intervalFlag bool           // --interval was given and overrides the config
+
+	// stopNotifications detaches the current notification sinks and
+	// message brokers
+	stopNotifications func()
+}
+
+// newDaemonReloader creates a reloader for runner, whose notification
+// sinks and message brokers are detached by stopNotifications.
+func newDaemonReloader(cmd *cobra.Command, runner *daemon.Runner, stopNotifications func()) *daemonReloader {
+	return &daemonReloader{
+		cmd:               cmd,
+		runner:            runner,
+		intervalFlag:      cmd.Flags().Changed("interval"),
+		stopNotifications: stopNotifications,
+	}
+}
+
+// interval returns the route check interval in seconds: the --interval
+// flag if given, and daemon.interval otherwise.
+func (d *daemonReloader) interval(cfg *config.Config) int {
+	if d.intervalFlag {
+		interval, _ := d.cmd.Flags().GetInt("interval")
+		return interval
+	}
+	return cfg.Daemon.Interval
+}
+
+// reload loads the configuration and applies it: logging, the API client
+// with its base URL, rate limits, and credentials, assertions, baseline,
+// watch targets, notification sinks, and message brokers. It returns the
+// new check interval and schedules for the loop's timers. If the
+// configuration cannot be loaded or is invalid, the daemon keeps running
+// with the previous one.
+func (d *daemonReloader) reload(cmdCtx context.Context) (int, *daemonSchedules, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return 0, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	interval := d.interval(cfg)
+	schedules, err := newDaemonSchedules(cfg.Schedules, cfg.Daemon.Targets, d.intervalFlag)
+	if err != nil {
+		return 0, nil, err
+	}
+	client, err := d.newClient(cmdCtx, cfg)
+	if err != nil {
+		return 0, nil, err
+	}
+	stop, err := configureDaemonRunner(d.runner, cfg, d.stopNotifications)
+	if err != nil {
+		return 0, nil, err
+	}
+	d.stopNotifications = stop
+
+	d.runner.SetClient(client)
+	if d.health != nil {
+		d.health.SetClient(client)
+		d.health.SetMaxAge(healthMaxAge(cfg.Daemon, interval, schedules))
+	}
+	for _, key := range restartRequired(ctx.Config, cfg) {
+		logrus.Warnf("Restart the daemon to apply the change to %s", key)
+	}
+	ctx.Config = cfg
+	ctx.APIClient = client
+	setupDaemonLogging(cfg)
+
+	return interval, schedules, nil
+}
+
+// newClient builds an API client from cfg and logs it in with the stored
+// credentials. Offline and cassette clients do not depend on the API
+// settings and are kept.
+func (d *daemonReloader) newClient(cmdCtx context.Context, cfg *config.Config) (api.Client, error) {
+	offline, _ := offlineMode(d.cmd)
+	record, _ := d.cmd.Flags().GetString("record")
+	replay, _ := d.cmd.Flags().GetString("replay")
+	if offline || record != "" || replay != "" {
+		logrus.Debug("Keeping the offline or cassette API client")
+		return ctx.APIClient, nil
+	}
+
+	client, err := newHTTPClient(d.cmd, cfg, ctx.CredMgr, ctx.Logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := setResponseCache(client, cfg); err != nil {
+		return nil, err
+	}
+	if err := enableTrace(d.cmd, client); err != nil {
+		return nil, err
+	}
+	if err := applySandbox(d.cmd, cfg, client, ctx.Logger); err != nil {
+		return nil, err
+	}
+	loadStoredCredentials(cmdCtx, client, cfg, ctx.CredMgr, ctx.Logger)
+	return client, nil
+}
+
+// close detaches the notification sinks and message brokers and waits for
+// pending deliveries.
+func (d *daemonReloader) close() {
+	d.stopNotifications()
+}
+
+// restartRequired lists the changed settings that are read only at
+// startup: listeners, the state directory, and the credential store.
+func restartRequired(old, cfg *config.Config) []string {
+	var keys []string
+	for _, setting := range []struct {
+		key     string
+		changed bool
+	}{
+		{"preferences.cache_dir", old.StateDir() != cfg.StateDir()},
+		{"credentials.keyring_backend", old.Credentials.KeyringBackend != cfg.Credentials.KeyringBackend},
+		{"daemon.metrics_listen", old.Daemon.MetricsListen != cfg.Daemon.MetricsListen},
+		{"daemon.local_socket", old.Daemon.LocalSocket != cfg.Daemon.LocalSocket},
+		{"serve.listen", old.Serve.Listen != cfg.Serve.Listen},
+		{"serve.webhook_secret", old.Serve.WebhookSecret != cfg.Serve.WebhookSecret},
+		{"serve.read_max_age", old.Serve.ReadMaxAge != cfg.Serve.ReadMaxAge},
+		{"tracing", !reflect.DeepEqual(old.Tracing, cfg.Tracing)},
+	} {
+		if setting.changed {
+			keys = append(keys, setting.key)
+		}
+	}
+	return keys
+}
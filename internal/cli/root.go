@@ -5,48 +5,87 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/bss/radb-client/internal/api"
 	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/daemon"
 	"github.com/bss/radb-client/internal/state"
 	"github.com/bss/radb-client/internal/version"
+	"github.com/bss/radb-client/pkg/keyring"
+	"github.com/bss/radb-client/pkg/metrics"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 // Global context shared across commands
 type CLIContext struct {
-	Config     *config.Config
-	APIClient  api.Client
-	StateMgr   state.Manager
-	CredMgr    *config.CredentialManager
-	Logger     *logrus.Logger
+	Config    *config.Config
+	APIClient api.Client
+	StateMgr  state.Manager
+	CredMgr   *config.CredentialManager
+	Logger    *logrus.Logger
+	Metrics   *metrics.Registry
+
+	// Daemon answers reads while a daemon runs on this host, or is nil
+	Daemon *daemon.SocketClient
 }
 
 var (
 	ctx CLIContext
 
+	// traceFile receives the --trace output when it names a file
+	traceFile *os.File
+
+	// apiMetrics instruments API clients, including those rebuilt when the
+	// daemon reloads its configuration
+	apiMetrics *api.Metrics
+
 	rootCmd = &cobra.Command{
-		Use:     "radb-client",
-		Short:   "RADb API client for route and contact management",
-		Long:    `A command-line client for interacting with the RADb (Routing Assets Database) API.
+		Use:   "radb-client",
+		Short: "RADb API client for route and contact management",
+		Long: `A command-line client for interacting with the RADb (Routing Assets Database) API.
 Manage route objects, contacts, and track changes over time.`,
-		Version: version.Short(),
+		Version:           version.Short(),
 		PersistentPreRunE: initializeContext,
-		SilenceUsage:      true,
-		SilenceErrors:     false, // Show errors during debugging
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			cleanup()
+		},
+		SilenceUsage:  true,
+		SilenceErrors: false, // Show errors during debugging
 	}
 )
 
 // Execute runs the root command.
+// The command context is cancelled on interrupt so in-flight API calls and
+// lock waits stop promptly.
 func Execute() error {
-	return rootCmd.Execute()
+	execCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	started := time.Now()
+	cmd, err := rootCmd.ExecuteContextC(execCtx)
+	saveClientStatus()
+	recordUsage(cmd, started, err)
+	finishTracing(err)
+	return err
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().String("config", "", "config file (default is $HOME/.radb-client/config.yaml)")
 	rootCmd.PersistentFlags().Bool("debug", false, "enable debug logging")
+	rootCmd.PersistentFlags().Bool("offline", false, "use local snapshots or fixtures instead of the API (or set RADB_OFFLINE=1)")
+	rootCmd.PersistentFlags().String("fixtures", "", "JSON fixture file to load in offline mode (or set RADB_FIXTURES)")
+	rootCmd.PersistentFlags().Bool("sandbox", false, "refuse writes outside the configured sandbox maintainer and ranges (or set RADB_SANDBOX=1)")
+	rootCmd.PersistentFlags().String("record", "", "record sanitized API interactions to a cassette file")
+	rootCmd.PersistentFlags().String("replay", "", "answer API requests from a cassette file instead of the network")
+	rootCmd.PersistentFlags().String("capture-dir", "", "save sanitized API responses that fail to parse or validate to this directory")
+	rootCmd.PersistentFlags().String("trace", "", "dump HTTP requests and responses, credentials redacted, to a file (or stderr if no file is given)")
+	rootCmd.PersistentFlags().Lookup("trace").NoOptDefVal = "-"
+	rootCmd.PersistentFlags().Bool("no-daemon", false, "call the API directly even when a daemon on this host could answer reads")
 
 	// Create logger for command initialization
 	logger := logrus.New()
@@ -62,16 +101,32 @@ func init() {
 	rootCmd.AddCommand(NewRouteCmd(logger))
 	rootCmd.AddCommand(NewContactCmd(logger))
 	rootCmd.AddCommand(NewSnapshotCmd(logger))
+	rootCmd.AddCommand(NewBulkCmd(logger))
+	rootCmd.AddCommand(NewResumeCmd(logger))
 
 	// Phase 3 commands
 	rootCmd.AddCommand(NewHistoryCmd(logger))
 	rootCmd.AddCommand(NewSearchCmd(logger))
+	rootCmd.AddCommand(NewSimulateCmd(logger))
+	rootCmd.AddCommand(NewAutNumCmd(logger))
+	rootCmd.AddCommand(NewAuditCmd(logger))
 
 	// CenterSquare-specific commands
 	rootCmd.AddCommand(NewCsqrCmd())
 
 	// Daemon mode
+	daemonCmd.AddCommand(newDaemonStatusCmd(logger))
+	daemonCmd.AddCommand(newDaemonStopCmd(logger))
+	daemonCmd.AddCommand(newDaemonReloadCmd(logger))
 	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(NewServeCmd(logger))
+	rootCmd.AddCommand(NewNotificationsCmd(logger))
+	rootCmd.AddCommand(NewCacheCmd(logger))
+	rootCmd.AddCommand(NewStatusCmd(logger))
+	rootCmd.AddCommand(NewSelftestCmd(logger))
+	rootCmd.AddCommand(NewDebugCmd(logger))
+	rootCmd.AddCommand(NewInsightsCmd(logger))
+	rootCmd.AddCommand(NewSummaryCmd(logger))
 }
 
 // initializeContext initializes the CLI context before command execution.
@@ -99,45 +154,211 @@ func initializeContext(cmd *cobra.Command, args []string) error {
 	ctx.Config = cfg
 	ctx.Logger = logger
 
+	if err := applyDefaultOutput(cmd, cfg.Preferences.DefaultOutput); err != nil {
+		return err
+	}
+
+	startTracing(cmd, cfg.Tracing, logger)
+
 	// Initialize credential manager
-	credMgr, err := config.NewCredentialManager(cfg.ConfigDir, logger)
+	credMgr, err := config.NewCredentialManagerWithBackend(cfg.ConfigDir, keyring.Backend(cfg.Credentials.KeyringBackend), logger)
 	if err != nil {
 		return fmt.Errorf("failed to initialize credential manager: %w", err)
 	}
 	ctx.CredMgr = credMgr
 
+	// Initialize state manager
+	stateMgr, err := newStateManager(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to initialize state manager: %w", err)
+	}
+	ctx.StateMgr = stateMgr
+
+	// Offline mode replaces the API with local data and needs no credentials
+	offline, fixtures := offlineMode(cmd)
+	if offline {
+		client, err := newOfflineClient(cmd.Context(), cfg, stateMgr, fixtures, logger)
+		if err != nil {
+			return err
+		}
+		if trace, _ := cmd.Flags().GetString("trace"); trace != "" {
+			logger.Warn("--trace has no effect in offline mode: no HTTP requests are made")
+		}
+		if err := applySandbox(cmd, cfg, client, logger); err != nil {
+			return err
+		}
+		ctx.APIClient = client
+		return nil
+	}
+	if fixtures != "" {
+		return fmt.Errorf("--fixtures requires --offline or RADB_OFFLINE=1")
+	}
+
 	// Initialize API client
-	ctx.APIClient = api.NewHTTPClient(
+	client, err := newHTTPClient(cmd, cfg, credMgr, logger)
+	if err != nil {
+		return err
+	}
+
+	// Record or replay API interactions
+	record, _ := cmd.Flags().GetString("record")
+	replay, _ := cmd.Flags().GetString("replay")
+	if record != "" && replay != "" {
+		return fmt.Errorf("--record and --replay cannot be used together")
+	}
+	if record != "" {
+		if err := client.RecordCassette(record); err != nil {
+			return err
+		}
+	}
+	if replay != "" {
+		if err := client.ReplayCassette(replay); err != nil {
+			return err
+		}
+	}
+	if replay == "" {
+		if err := setResponseCache(client, cfg); err != nil {
+			return err
+		}
+	}
+	if err := enableTrace(cmd, client); err != nil {
+		return err
+	}
+	if err := applySandbox(cmd, cfg, client, logger); err != nil {
+		return err
+	}
+	ctx.APIClient = client
+	ctx.Daemon = localDaemon(cmd, cfg, record != "" || replay != "")
+
+	// Load credentials into API client if available
+	loadStoredCredentials(cmd.Context(), client, cfg, credMgr, logger)
+
+	// Recorded credentials are redacted, so replay needs none
+	if replay != "" && !ctx.APIClient.IsAuthenticated() {
+		ctx.APIClient.Login(cmd.Context(), "replay", "")
+	}
+
+	return nil
+}
+
+// newHTTPClient builds the API client from cfg: transport, rate limits,
+// retries, metrics, request signing, and the credential source. The daemon
+// calls it again on reload; the cassette flags are left to the caller.
+func newHTTPClient(cmd *cobra.Command, cfg *config.Config, credMgr *config.CredentialManager, logger *logrus.Logger) (*api.HTTPClient, error) {
+	client := api.NewHTTPClient(
 		cfg.API.BaseURL,
 		cfg.API.Source,
 		cfg.API.Timeout,
 		logger,
 	)
+	if err := client.SetProxy(cfg.API.Proxy.URL, cfg.API.Proxy.NoProxy); err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+	client.SetRateLimit(cfg.API.RateLimit.RequestsPerMinute, cfg.API.RateLimit.BurstSize)
+	for class, limit := range cfg.API.RateLimit.Endpoints {
+		client.SetEndpointRateLimit(api.EndpointClass(class), limit.RequestsPerMinute, limit.BurstSize)
+	}
+	client.SetJobWeights(cfg.API.RateLimit.JobWeights)
+	client.SetRetryPolicy(retryPolicy(cfg.API.Retry))
+	client.SetPagination(cfg.API.PageSize, cfg.API.MaxResults)
+	client.SetRequestCompression(cfg.API.CompressRequests)
+	client.SetResponseLimits(int64(cfg.API.MaxResponseSize)<<20, int64(cfg.API.MaxObjectSize)<<10)
+	tlsCfg := cfg.API.TLS
+	if err := client.SetTLS(tlsCfg.CAFile, tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.MinVersion); err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	captureDir, _ := cmd.Flags().GetString("capture-dir")
+	client.SetCaptureDir(captureDir)
 
-	// Load credentials into API client if available
-	if cfg.Credentials.Username != "" {
-		password, err := credMgr.GetPassword(cfg.Credentials.Username)
-		if err == nil {
-			logger.Debugf("Retrieved password from storage (length: %d)", len(password))
-			// Login with stored credentials
-			bgCtx := context.Background()
-			if err := ctx.APIClient.Login(bgCtx, cfg.Credentials.Username, password); err != nil {
-				logger.Warnf("Failed to load stored credentials: %v", err)
-			} else {
-				logger.Debugf("Loaded credentials for %s", cfg.Credentials.Username)
-			}
+	// Instrument requests so long-running commands can expose metrics
+	if apiMetrics == nil {
+		ctx.Metrics = metrics.NewRegistry()
+		apiMetrics = api.NewMetrics(ctx.Metrics)
+	}
+	client.SetMetrics(apiMetrics)
+
+	// Gateways in front of private mirrors authenticate signed requests
+	// instead of Basic Auth
+	hmacAuth := cfg.API.AuthMode == "hmac"
+	if hmacAuth {
+		key, err := credMgr.GetSigningKey(cfg.API.Signing.KeyID)
+		if err != nil {
+			logger.Warnf("No signing key stored for %s; run 'radb-client auth signing-key': %v", cfg.API.Signing.KeyID, err)
 		} else {
-			logger.Debugf("No stored credentials found: %v", err)
+			signing := cfg.API.Signing
+			client.SetRequestSigner(api.NewRequestSigner(signing.KeyID, []byte(key), api.SigningHeaders{
+				Signature: signing.SignatureHeader,
+				Timestamp: signing.TimestampHeader,
+				KeyID:     signing.KeyIDHeader,
+			}))
 		}
 	}
 
-	// Initialize state manager
-	stateMgr, err := state.NewFileManager(cfg.Preferences.CacheDir, logger)
+	// Reload credentials from storage if the API rejects the current ones,
+	// so a rotated password does not break long-running sessions
+	if cfg.Credentials.Username != "" && !hmacAuth {
+		username := cfg.Credentials.Username
+		client.SetCredentialSource(func(reqCtx context.Context) (string, string, error) {
+			password, err := credMgr.GetPassword(username)
+			return username, password, err
+		})
+	}
+
+	return client, nil
+}
+
+// setResponseCache caches the client's reads when api.cache.enabled is set.
+func setResponseCache(client *api.HTTPClient, cfg *config.Config) error {
+	if !cfg.API.Cache.Enabled {
+		return nil
+	}
+	cache, err := newResponseCache(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to initialize state manager: %w", err)
+		return err
 	}
-	ctx.StateMgr = stateMgr
+	client.SetResponseCache(cache)
+	return nil
+}
+
+// loadStoredCredentials logs the client in with the stored password of the
+// configured user, if there is one. Signed requests need no login.
+func loadStoredCredentials(cmdCtx context.Context, client *api.HTTPClient, cfg *config.Config, credMgr *config.CredentialManager, logger *logrus.Logger) {
+	if cfg.Credentials.Username == "" || cfg.API.AuthMode == "hmac" {
+		return
+	}
+	password, err := credMgr.GetPassword(cfg.Credentials.Username)
+	if err != nil {
+		logger.Debugf("No stored credentials found: %v", err)
+		return
+	}
+	client.Tracef("auth: retrieved stored password for %s", cfg.Credentials.Username)
+	if err := client.Login(cmdCtx, cfg.Credentials.Username, password); err != nil {
+		logger.Warnf("Failed to load stored credentials: %v", err)
+	} else {
+		logger.Debugf("Loaded credentials for %s", cfg.Credentials.Username)
+	}
+}
 
+// enableTrace sends the client's HTTP trace to the --trace destination.
+func enableTrace(cmd *cobra.Command, client *api.HTTPClient) error {
+	dest, _ := cmd.Flags().GetString("trace")
+	switch dest {
+	case "":
+		return nil
+	case "-", "stderr":
+		client.SetTrace(os.Stderr)
+		return nil
+	}
+
+	// A reloaded daemon keeps writing to the file it opened at startup
+	if traceFile == nil {
+		file, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open trace file: %w", err)
+		}
+		traceFile = file
+	}
+	client.SetTrace(traceFile)
 	return nil
 }
 
@@ -146,6 +367,10 @@ func cleanup() {
 	if ctx.StateMgr != nil {
 		ctx.StateMgr.Close()
 	}
+	if traceFile != nil {
+		traceFile.Close()
+		traceFile = nil
+	}
 	if ctx.CredMgr != nil {
 		ctx.CredMgr.Close()
 	}
@@ -159,3 +384,14 @@ func handleError(err error) {
 		os.Exit(1)
 	}
 }
+
+// retryPolicy converts the retry configuration into an API retry policy.
+func retryPolicy(cfg config.RetryConfig) api.RetryPolicy {
+	return api.RetryPolicy{
+		MaxAttempts:  cfg.MaxAttempts,
+		InitialDelay: time.Duration(cfg.InitialDelayMs) * time.Millisecond,
+		Multiplier:   float64(cfg.BackoffMultiplier),
+		MaxDelay:     time.Duration(cfg.MaxDelayMs) * time.Millisecond,
+		MaxElapsed:   time.Duration(cfg.MaxElapsedMs) * time.Millisecond,
+	}
+}
@@ -2,10 +2,18 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/bss/radb-client/internal/api"
 	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/report"
 	"github.com/bss/radb-client/internal/state"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -27,6 +35,11 @@ func NewRouteCmd(logger *logrus.Logger) *cobra.Command {
 		newRouteUpdateCmd(logger),
 		newRouteDeleteCmd(logger),
 		newRouteDiffCmd(logger),
+		newRouteApplyDiffCmd(logger),
+		newRouteBulkEditCmd(logger),
+		newRouteBatchCmd(logger),
+		newRouteAnnotateCmd(logger),
+		newRouteAuditCmd(logger),
 	)
 
 	return cmd
@@ -40,14 +53,35 @@ func newRouteListCmd(logger *logrus.Logger) *cobra.Command {
 		prefix       string
 		origin       string
 		mntBy        string
+		stream       bool
+		resumeFrom   string
+		withChurn    bool
+		churnDays    int
+		churnAlert   int
 	)
 
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List all routes",
+		Long: `List all routes.
+
+With --stream, routes are decoded from the API response as they arrive and
+written one JSON object per line, so memory stays bounded by
+performance.stream_threshold routes however large the account is. Streamed
+listings are not snapshotted. If a streamed listing is interrupted, the
+command prints a token; rerun it with the same filters and --resume-from
+to continue after the last route written.
+
+With --with-churn, the table gains a column counting each route's changes in
+the local changelog over the last --churn-days days. Routes with at least
+--churn-threshold changes are highlighted as unstable.`,
+		Example: `  radb-client route list --origin AS64500
+  radb-client route list --with-churn --churn-days 7
+  radb-client route list --stream > routes.jsonl
+  radb-client route list --stream --resume-from eyJwb3NpdGlvbiI6MTIwMDB9 >> routes.jsonl`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 
 			// Build filters
 			filters := make(map[string]string)
@@ -61,24 +95,42 @@ func newRouteListCmd(logger *logrus.Logger) *cobra.Command {
 				filters["mnt-by"] = mntBy
 			}
 
-			// List routes using shared API client (already authenticated)
-			routes, err := ctx.APIClient.ListRoutes(cmdCtx, filters)
-			if err != nil {
-				return fmt.Errorf("failed to list routes: %w", err)
+			if withChurn && churnDays <= 0 {
+				return fmt.Errorf("--churn-days must be positive")
+			}
+			if resumeFrom != "" && !stream {
+				return fmt.Errorf("--resume-from requires --stream")
+			}
+			if stream {
+				return streamRoutes(cmdCtx, filters, resumeFrom)
 			}
 
-			// Auto-snapshot if enabled
-			if autoSnapshot {
-				stateManager, _ := state.NewFileManager(ctx.Config.StateDir(), logger)
-				defer stateManager.Close()
+			// A daemon on this host answers from its snapshots; otherwise list
+			// routes using shared API client (already authenticated)
+			routes, fromDaemon := daemonRoutes(cmdCtx, filters, logger)
+			if !fromDaemon {
+				var err error
+				routes, err = ctx.APIClient.ListRoutes(cmdCtx, filters)
+				if err != nil {
+					return fmt.Errorf("failed to list routes: %w", err)
+				}
+			}
 
-				snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "Auto-snapshot from route list")
+			// Auto-snapshot if enabled. Daemon reads are already snapshotted.
+			if autoSnapshot && !fromDaemon {
+				// Filtered listings produce scoped snapshots so they are not
+				// mistaken for full-account captures in later diffs
+				note := "Auto-snapshot from route list"
+				if scope := models.FilterScope(filters); scope != "" {
+					note = fmt.Sprintf("Auto-snapshot from route list (%s)", scope)
+				}
+				snapshot := models.NewScopedSnapshot(models.SnapshotTypeRoute, note, filters)
 				snapshot.Routes = routes
 				if err := snapshot.ComputeChecksum(); err != nil {
 					logger.Warnf("Failed to compute snapshot checksum: %v", err)
 				}
 
-				if err := stateManager.SaveSnapshot(cmdCtx, snapshot); err != nil {
+				if err := ctx.StateMgr.SaveSnapshot(cmdCtx, snapshot); err != nil {
 					logger.Warnf("Failed to save auto-snapshot: %v", err)
 				} else {
 					logger.Infof("Created snapshot: %s", snapshot.ID)
@@ -87,6 +139,15 @@ func newRouteListCmd(logger *logrus.Logger) *cobra.Command {
 
 			// Render output
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			outputter.SetAnnotations(loadAnnotations(cmdCtx, logger))
+			if withChurn {
+				since := time.Now().AddDate(0, 0, -churnDays)
+				churn, err := state.NewHistoryManager(ctx.Config.StateDir(), logger).ChurnScores(cmdCtx, "route", since)
+				if err != nil {
+					return fmt.Errorf("failed to compute churn: %w", err)
+				}
+				outputter.SetChurn(churn, churnAlert)
+			}
 			return outputter.RenderRoutes(routes)
 		},
 	}
@@ -96,22 +157,86 @@ func newRouteListCmd(logger *logrus.Logger) *cobra.Command {
 	cmd.Flags().StringVar(&prefix, "prefix", "", "Filter by prefix")
 	cmd.Flags().StringVar(&origin, "origin", "", "Filter by origin ASN")
 	cmd.Flags().StringVar(&mntBy, "mnt-by", "", "Filter by maintainer")
+	cmd.Flags().BoolVar(&stream, "stream", false, "Stream routes as JSON lines without loading the full listing")
+	cmd.Flags().StringVar(&resumeFrom, "resume-from", "", "Continue an interrupted --stream listing from its token")
+	cmd.Flags().BoolVar(&withChurn, "with-churn", false, "Add a column with each route's recent changes (table output)")
+	cmd.Flags().IntVar(&churnDays, "churn-days", 30, "Days of changelog history counted by --with-churn")
+	cmd.Flags().IntVar(&churnAlert, "churn-threshold", 3, "Highlight routes with at least this many changes")
 
 	return cmd
 }
 
+// streamRoutes writes routes as JSON lines while they are decoded, holding
+// at most one batch of performance.stream_threshold routes in memory. An
+// interrupted listing reports the token to resume it from.
+func streamRoutes(cmdCtx context.Context, filters map[string]string, resumeFrom string) error {
+	stream := api.NewRouteStream(cmdCtx, ctx.APIClient, filters, ctx.Config.Performance.StreamThreshold)
+	defer stream.Close()
+
+	if resumeFrom != "" {
+		if err := stream.Seek(resumeFrom); err != nil {
+			return err
+		}
+	}
+
+	// The checkpoint trails the stream by one route until that route is written
+	encoder := json.NewEncoder(os.Stdout)
+	checkpoint := stream.Token()
+	for stream.Next() {
+		if err := encoder.Encode(stream.Route()); err != nil {
+			return fmt.Errorf("failed to write route: %w (resume with: --resume-from %s)", err, checkpoint)
+		}
+		checkpoint = stream.Token()
+	}
+	if err := stream.Err(); err != nil {
+		return fmt.Errorf("failed to list routes: %w (resume with: --resume-from %s)", err, checkpoint)
+	}
+	return nil
+}
+
 // newRouteShowCmd creates the route show command.
 func newRouteShowCmd(logger *logrus.Logger) *cobra.Command {
-	var outputFormat string
+	var (
+		outputFormat string
+		at           string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "show <prefix> <asn>",
+		Use:   "show <prefix> <asn> | show <urn>",
 		Short: "Show a specific route",
-		Args:  cobra.ExactArgs(2),
+		Long: `Show a specific route, named by prefix and ASN or by its reference
+(radb:route:<prefix>:<asn>).
+
+With --at, the route's state at a past point in time is resolved from the
+latest snapshot taken before then plus the changelog entries recorded since,
+and shown alongside the current version with a diff.`,
+		Example: `  # Show a route
+  radb-client route show 192.0.2.0/24 AS64500
+  radb-client route show radb:route:192.0.2.0/24:AS64500
+
+  # Show how a route looked on May 1st and what changed since
+  radb-client route show 192.0.2.0/24 AS64500 --at 2024-05-01
+
+  # Copy the route as RPSL, e.g. for a ticket
+  radb-client route show 192.0.2.0/24 AS64500 -o rpsl --copy`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
-			prefix := args[0]
-			asn := args[1]
+			cmdCtx := cmd.Context()
+			prefix, asn, rest, err := routeArgs(args)
+			if err != nil {
+				return err
+			}
+			if len(rest) > 0 {
+				return fmt.Errorf("unexpected argument %q", rest[0])
+			}
+
+			if at != "" {
+				atTime, err := parseTimeSpec(at)
+				if err != nil {
+					return err
+				}
+				return showRouteAt(cmd, logger, prefix, asn, atTime, outputFormat)
+			}
 
 			// Get route using shared API client (already authenticated)
 			route, err := ctx.APIClient.GetRoute(cmdCtx, prefix, asn)
@@ -123,13 +248,16 @@ func newRouteShowCmd(logger *logrus.Logger) *cobra.Command {
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
 			switch outputFormat {
 			case "json":
-				return outputter.renderJSON(route)
+				return outputter.renderJSON(newRouteView(route))
 			case "yaml":
-				return outputter.renderYAML(route)
+				return outputter.renderYAML(newRouteView(route))
+			case "rpsl":
+				fmt.Print(route.ToRPSL())
 			default:
 				// Pretty print for table format
 				fmt.Printf("Route: %s\n", route.Route)
 				fmt.Printf("Origin: %s\n", route.Origin)
+				fmt.Printf("Reference: %s\n", route.URN())
 				fmt.Printf("Maintainers: %s\n", strings.Join(route.MntBy, ", "))
 				if len(route.Descr) > 0 {
 					fmt.Printf("Description: %s\n", strings.Join(route.Descr, "; "))
@@ -138,22 +266,77 @@ func newRouteShowCmd(logger *logrus.Logger) *cobra.Command {
 					fmt.Printf("Remarks: %s\n", strings.Join(route.Remarks, "; "))
 				}
 				fmt.Printf("Source: %s\n", route.Source)
+				if note := loadAnnotations(cmdCtx, logger).Label("route", route.ID()); note != "" {
+					fmt.Printf("Annotation: %s\n", note)
+				}
 			}
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml, rpsl)")
+	cmd.Flags().StringVar(&at, "at", "", "Show the route as of a past time (e.g. 2024-05-01, 7d)")
+	addCopyFlag(cmd)
 	return cmd
 }
 
+// routeAtTimeView is a route's state at a past time alongside its current state.
+type routeAtTimeView struct {
+	ID         string               `json:"id"`
+	At         time.Time            `json:"at"`
+	SnapshotID string               `json:"snapshot_id,omitempty"`
+	Replayed   int                  `json:"replayed"`
+	Then       *models.RouteObject  `json:"then"`
+	Current    *models.RouteObject  `json:"current"`
+	Changes    []models.FieldChange `json:"changes"`
+}
+
+// showRouteAt renders a route's state at a past time next to its current state.
+func showRouteAt(cmd *cobra.Command, logger *logrus.Logger, prefix, asn string, at time.Time, outputFormat string) error {
+	cmdCtx := cmd.Context()
+	if !strings.HasPrefix(strings.ToUpper(asn), "AS") {
+		asn = "AS" + asn
+	}
+	id := fmt.Sprintf("%s-%s", prefix, strings.ToUpper(asn))
+
+	history := state.NewHistoryManager(ctx.Config.StateDir(), logger)
+	then, err := state.RouteAt(cmdCtx, ctx.StateMgr, history, id, at)
+	if err != nil {
+		return err
+	}
+
+	current, err := ctx.APIClient.GetRoute(cmdCtx, prefix, asn)
+	if errors.Is(err, api.ErrNotFound) {
+		current = nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get route: %w", err)
+	}
+
+	view := &routeAtTimeView{
+		ID:         id,
+		At:         then.At,
+		SnapshotID: then.SnapshotID,
+		Replayed:   then.Replayed,
+		Then:       then.Route,
+		Current:    current,
+		Changes:    make([]models.FieldChange, 0),
+	}
+	if view.Then != nil && view.Current != nil {
+		view.Changes = models.DetectFieldChanges(view.Then, view.Current)
+	}
+
+	outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+	return outputter.RenderRouteAt(view)
+}
+
 // newRouteCreateCmd creates the route create command.
 func newRouteCreateCmd(logger *logrus.Logger) *cobra.Command {
 	var (
 		descr   []string
 		mntBy   []string
 		remarks []string
+		dryRun  bool
 	)
 
 	cmd := &cobra.Command{
@@ -161,7 +344,7 @@ func newRouteCreateCmd(logger *logrus.Logger) *cobra.Command {
 		Short: "Create a new route",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			prefix := args[0]
 			asn := args[1]
 
@@ -185,11 +368,22 @@ func newRouteCreateCmd(logger *logrus.Logger) *cobra.Command {
 				return fmt.Errorf("route validation failed: %w", err)
 			}
 
+			if dryRun {
+				if err := enableDryRun(); err != nil {
+					return err
+				}
+			}
+
 			// Create route using shared API client (already authenticated)
-			if err := ctx.APIClient.CreateRoute(cmdCtx, route); err != nil {
-				return fmt.Errorf("failed to create route: %w", err)
+			tx := models.NewRouteTransaction(models.ChangeTypeAdded, nil, route)
+			if err := applyRouteChange(cmdCtx, logger, tx, dryRun); err != nil {
+				return err
 			}
 
+			if dryRun {
+				fmt.Printf("Dry run: route %s was not created\n", route.ID())
+				return nil
+			}
 			fmt.Printf("Successfully created route %s\n", route.ID())
 			return nil
 		},
@@ -198,6 +392,7 @@ func newRouteCreateCmd(logger *logrus.Logger) *cobra.Command {
 	cmd.Flags().StringSliceVar(&descr, "descr", nil, "Description(s)")
 	cmd.Flags().StringSliceVar(&mntBy, "mnt-by", nil, "Maintainer(s) (required)")
 	cmd.Flags().StringSliceVar(&remarks, "remarks", nil, "Remarks")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and show the request without sending it")
 	cmd.MarkFlagRequired("mnt-by")
 
 	return cmd
@@ -209,6 +404,7 @@ func newRouteUpdateCmd(logger *logrus.Logger) *cobra.Command {
 		descr   []string
 		mntBy   []string
 		remarks []string
+		dryRun  bool
 	)
 
 	cmd := &cobra.Command{
@@ -216,7 +412,7 @@ func newRouteUpdateCmd(logger *logrus.Logger) *cobra.Command {
 		Short: "Update an existing route",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			prefix := args[0]
 			asn := args[1]
 
@@ -231,6 +427,8 @@ func newRouteUpdateCmd(logger *logrus.Logger) *cobra.Command {
 				return fmt.Errorf("failed to get route: %w", err)
 			}
 
+			before := *route
+
 			// Update fields if provided
 			if len(descr) > 0 {
 				route.Descr = descr
@@ -242,11 +440,22 @@ func newRouteUpdateCmd(logger *logrus.Logger) *cobra.Command {
 				route.Remarks = remarks
 			}
 
+			if dryRun {
+				if err := enableDryRun(); err != nil {
+					return err
+				}
+			}
+
 			// Update route using shared API client
-			if err := ctx.APIClient.UpdateRoute(cmdCtx, route); err != nil {
-				return fmt.Errorf("failed to update route: %w", err)
+			tx := models.NewRouteTransaction(models.ChangeTypeModified, &before, route)
+			if err := applyRouteChange(cmdCtx, logger, tx, dryRun); err != nil {
+				return err
 			}
 
+			if dryRun {
+				fmt.Printf("Dry run: route %s was not updated\n", route.ID())
+				return nil
+			}
 			fmt.Printf("Successfully updated route %s\n", route.ID())
 			return nil
 		},
@@ -255,81 +464,291 @@ func newRouteUpdateCmd(logger *logrus.Logger) *cobra.Command {
 	cmd.Flags().StringSliceVar(&descr, "descr", nil, "Description(s)")
 	cmd.Flags().StringSliceVar(&mntBy, "mnt-by", nil, "Maintainer(s)")
 	cmd.Flags().StringSliceVar(&remarks, "remarks", nil, "Remarks")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and show the request without sending it")
 
 	return cmd
 }
 
 // newRouteDeleteCmd creates the route delete command.
 func newRouteDeleteCmd(logger *logrus.Logger) *cobra.Command {
-	var confirm bool
+	var (
+		confirm bool
+		dryRun  bool
+		rpsl    bool
+		reason  string
+		auth    string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "delete <prefix> <asn>",
 		Short: "Delete a route",
-		Args:  cobra.ExactArgs(2),
+		Long: `Delete a route through the API.
+
+With --rpsl, nothing is deleted: the registered route is fetched and printed
+as an RPSL deletion (the object followed by a delete: attribute carrying
+--reason) for submission by mail. With --auth password, the stored password
+of the configured user is appended as a password: attribute; it must match
+the maintainer's password auth. Otherwise add the password: attribute or a
+PGP signature before mailing it.`,
+		Example: `  radb-client route delete 192.0.2.0/24 AS64500 --confirm
+  radb-client route delete 192.0.2.0/24 AS64500 --rpsl --reason "prefix returned to ARIN" > delete.txt
+  radb-client route delete 192.0.2.0/24 AS64500 --rpsl --auth password > delete.txt`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			prefix := args[0]
 			asn := args[1]
 
-			if !confirm {
-				return fmt.Errorf("please confirm deletion with --confirm flag")
+			if rpsl {
+				if err := validateRPSLAuth(auth); err != nil {
+					return err
+				}
+				existing, err := ctx.APIClient.GetRoute(cmdCtx, prefix, asn)
+				if err != nil {
+					return fmt.Errorf("failed to get route: %w", err)
+				}
+				submission, err := authenticateRPSL(existing.ToRPSLDelete(reason), auth)
+				if err != nil {
+					return err
+				}
+				fmt.Print(submission)
+				return nil
+			}
+
+			if !confirm && !dryRun {
+				return fmt.Errorf("please confirm deletion with --confirm flag, or preview with --dry-run")
+			}
+
+			// Keep the route being deleted so the deletion can be rolled back
+			route := &models.RouteObject{Route: prefix, Origin: asn}
+			if dryRun {
+				if err := enableDryRun(); err != nil {
+					return err
+				}
+			} else {
+				existing, err := ctx.APIClient.GetRoute(cmdCtx, prefix, asn)
+				if err != nil {
+					return fmt.Errorf("failed to get route: %w", err)
+				}
+				route = existing
 			}
 
 			// Delete route using shared API client (already authenticated)
-			if err := ctx.APIClient.DeleteRoute(cmdCtx, prefix, asn); err != nil {
-				return fmt.Errorf("failed to delete route: %w", err)
+			tx := models.NewRouteTransaction(models.ChangeTypeRemoved, route, nil)
+			if err := applyRouteChange(cmdCtx, logger, tx, dryRun); err != nil {
+				return err
 			}
 
+			if dryRun {
+				fmt.Printf("Dry run: route %s-%s was not deleted\n", prefix, asn)
+				return nil
+			}
 			fmt.Printf("Successfully deleted route %s-%s\n", prefix, asn)
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&confirm, "confirm", false, "Confirm deletion")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate and show the request without sending it")
+	cmd.Flags().BoolVar(&rpsl, "rpsl", false, "Print an RPSL deletion for mail submission instead of deleting")
+	cmd.Flags().StringVar(&reason, "reason", "", "Reason recorded in the delete: attribute (with --rpsl)")
+	cmd.Flags().StringVar(&auth, "auth", rpslAuthNone, "Authentication added to the RPSL deletion: none or password (with --rpsl)")
 	return cmd
 }
 
+// Authentication methods for RPSL submissions.
+const (
+	rpslAuthNone     = "none"
+	rpslAuthPassword = "password"
+)
+
+// validateRPSLAuth checks an --auth value.
+func validateRPSLAuth(auth string) error {
+	switch auth {
+	case rpslAuthNone, rpslAuthPassword:
+		return nil
+	default:
+		return fmt.Errorf("invalid --auth %q (expected none or password)", auth)
+	}
+}
+
+// authenticateRPSL adds the authentication named by auth to an RPSL
+// submission. Password auth uses the stored password of the configured user.
+func authenticateRPSL(submission, auth string) (string, error) {
+	if err := validateRPSLAuth(auth); err != nil {
+		return "", err
+	}
+	if auth == rpslAuthNone {
+		return submission, nil
+	}
+
+	username := ctx.Config.Credentials.Username
+	if username == "" || ctx.CredMgr == nil {
+		return "", fmt.Errorf("no stored credentials for --auth password: run 'radb-client auth login'")
+	}
+	password, err := ctx.CredMgr.GetPassword(username)
+	if err != nil {
+		return "", fmt.Errorf("failed to get password for %s: %w", username, err)
+	}
+	submission, err = models.WithRPSLPassword(submission, password)
+	if err != nil {
+		return "", fmt.Errorf("invalid stored password for %s: %w", username, err)
+	}
+	return submission, nil
+}
+
 // newRouteDiffCmd creates the route diff command.
 func newRouteDiffCmd(logger *logrus.Logger) *cobra.Command {
-	var outputFormat string
+	var (
+		outputFormat string
+		reportFormat string
+		reportFile   string
+		ignoreFields []string
+		unordered    []string
+		ignoreCase   bool
+		live         bool
+	)
 
 	cmd := &cobra.Command{
-		Use:   "diff <snapshot-id-1> <snapshot-id-2>",
+		Use:   "diff <snapshot-id-1> [<snapshot-id-2>]",
 		Short: "Compare two route snapshots",
-		Args:  cobra.ExactArgs(2),
+		Long: `Compare two snapshots and report the routes and contacts that were added,
+removed, or modified.
+
+With --live the snapshot is compared with the objects currently registered,
+fetched with the snapshot's filters, without saving a new snapshot.
+
+By default any difference is a modification. The diff section of the
+configuration and the flags below relax this: ignored fields are never
+compared, unordered list fields such as descr and mnt-by match regardless
+of line order, and --ignore-case compares values case-insensitively. Flags
+add to the configured fields.
+
+-o text prints added and removed objects in RPSL form and a unified diff
+of the RPSL form of each modified object.
+
+--report html or --report markdown writes a self-contained report with
+summary tables and each object before and after the change, for attaching
+to a change ticket.`,
+		Example: `  # Show what changed since a snapshot was taken
+  radb-client route diff snap-1 --live
+
+  # Write an HTML report for a change ticket
+  radb-client route diff snap-1 snap-2 --report html -f report.html
+
+  # Review modified objects as unified RPSL diffs
+  radb-client route diff snap-1 snap-2 -o text
+
+  # Ignore remarks and reordered descr and mnt-by lines
+  radb-client route diff snap-1 snap-2 --ignore-field remarks --unordered descr,mnt-by`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			snapshot1ID := args[0]
-			snapshot2ID := args[1]
 
-			// Create state manager using shared config
-			stateManager, _ := state.NewFileManager(ctx.Config.StateDir(), logger)
-			defer stateManager.Close()
+			if live && len(args) != 1 {
+				return fmt.Errorf("--live takes a single snapshot ID")
+			}
+			if !live && len(args) != 2 {
+				return fmt.Errorf("two snapshot IDs are required unless --live is set")
+			}
+
+			switch reportFormat {
+			case "", report.FormatHTML, report.FormatMarkdown:
+			default:
+				return fmt.Errorf("unsupported report format %q (use html or markdown)", reportFormat)
+			}
+			if reportFile != "" && reportFormat == "" {
+				return fmt.Errorf("--file requires --report")
+			}
 
 			// Load snapshots
-			snap1, err := stateManager.LoadSnapshot(cmdCtx, snapshot1ID)
+			snap1, err := ctx.StateMgr.LoadSnapshot(cmdCtx, snapshot1ID)
 			if err != nil {
 				return fmt.Errorf("failed to load snapshot %s: %w", snapshot1ID, err)
 			}
 
-			snap2, err := stateManager.LoadSnapshot(cmdCtx, snapshot2ID)
-			if err != nil {
-				return fmt.Errorf("failed to load snapshot %s: %w", snapshot2ID, err)
+			opts := state.DiffOptions{
+				IgnoreFields:        append(slices.Clone(ctx.Config.Diff.IgnoreFields), ignoreFields...),
+				UnorderedListFields: append(slices.Clone(ctx.Config.Diff.UnorderedListFields), unordered...),
+				IgnoreCase:          ctx.Config.Diff.IgnoreCase,
+			}
+			if cmd.Flags().Changed("ignore-case") {
+				opts.IgnoreCase = ignoreCase
+			}
+			if err := opts.Validate(); err != nil {
+				return fmt.Errorf("invalid diff options: %w", err)
 			}
 
-			// Compute diff
-			diff, err := state.ComputeDiff(cmdCtx, snap1, snap2)
-			if err != nil {
-				return fmt.Errorf("failed to compute diff: %w", err)
+			var (
+				snap2 *models.Snapshot
+				diff  *models.DiffResult
+			)
+			if live {
+				diff, snap2, err = state.DiffAgainstLive(cmdCtx, snap1, ctx.APIClient, opts)
+				if err != nil {
+					return fmt.Errorf("failed to diff %s against live objects: %w", snapshot1ID, err)
+				}
+			} else {
+				snapshot2ID := args[1]
+				snap2, err = ctx.StateMgr.LoadSnapshot(cmdCtx, snapshot2ID)
+				if err != nil {
+					return fmt.Errorf("failed to load snapshot %s: %w", snapshot2ID, err)
+				}
+
+				if snap1.Scope() != snap2.Scope() {
+					fmt.Fprintf(os.Stderr, "Warning: comparing snapshots with different scopes (%s vs %s); differences may reflect filters rather than changes\n",
+						scopeLabel(snap1), scopeLabel(snap2))
+				}
+
+				// Compute diff
+				diff, err = state.ComputeDiffWithOptions(cmdCtx, snap1, snap2, opts)
+				if err != nil {
+					return fmt.Errorf("failed to compute diff: %w", err)
+				}
+			}
+
+			if reportFormat != "" {
+				var w io.Writer = os.Stdout
+				if reportFile != "" {
+					f, err := os.Create(reportFile)
+					if err != nil {
+						return fmt.Errorf("failed to create %s: %w", reportFile, err)
+					}
+					defer f.Close()
+					w = f
+				}
+				if err := report.NewDiffReport(snap1, snap2, diff).Write(w, reportFormat); err != nil {
+					return fmt.Errorf("failed to write report: %w", err)
+				}
+				if reportFile != "" {
+					fmt.Fprintf(os.Stderr, "Wrote %s report to %s\n", reportFormat, reportFile)
+				}
+				return nil
 			}
 
 			// Render output
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			outputter.SetAnnotations(loadAnnotations(cmdCtx, logger))
 			return outputter.RenderDiff(diff)
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml, text)")
+	cmd.Flags().StringVar(&reportFormat, "report", "", "Write a report instead of the diff output (html, markdown)")
+	cmd.Flags().StringVarP(&reportFile, "file", "f", "", "Write the report to a file instead of standard output")
+	cmd.Flags().StringSliceVar(&ignoreFields, "ignore-field", nil, "Fields not compared, e.g. remarks (repeatable)")
+	cmd.Flags().StringSliceVar(&unordered, "unordered", nil, "List fields compared regardless of line order, e.g. descr,mnt-by")
+	cmd.Flags().BoolVar(&ignoreCase, "ignore-case", false, "Compare values case-insensitively")
+	cmd.Flags().BoolVar(&live, "live", false, "Compare the snapshot with the objects currently registered")
+	addCopyFlag(cmd)
 	return cmd
 }
+
+// scopeLabel returns a human-readable scope for a snapshot.
+func scopeLabel(snapshot *models.Snapshot) string {
+	if snapshot.IsFullScope() {
+		return "full"
+	}
+	return snapshot.Scope()
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/keyring"
+	"github.com/spf13/cobra"
+)
+
+// setTestCredentials stores password for user in a file credential store
+// used by the CLI context.
+func setTestCredentials(t *testing.T, user, password string) {
+	t.Helper()
+
+	credMgr, err := config.NewCredentialManagerWithBackend(t.TempDir(), keyring.BackendFile, ctx.Logger)
+	if err != nil {
+		t.Fatalf("NewCredentialManagerWithBackend() failed: %v", err)
+	}
+	if err := credMgr.SetPassword(user, password); err != nil {
+		t.Fatalf("SetPassword() failed: %v", err)
+	}
+	ctx.Config.Credentials.Username = user
+	ctx.CredMgr = credMgr
+}
+
+// runRPSLDelete runs cmd with args and returns what it printed.
+func runRPSLDelete(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+
+	cmd.SetArgs(args)
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetErr(&strings.Builder{})
+	cmd.SilenceUsage = true
+
+	var err error
+	out := captureStdout(t, func() { err = cmd.Execute() })
+	return out, err
+}
+
+func TestDeleteRPSL(t *testing.T) {
+	route := testRoute("192.0.2.0/24", "AS64500", "Example")
+	contact := testContact("ADMIN-1", "noc@example.com", models.ContactRoleAdmin)
+
+	tests := []struct {
+		name        string
+		object      string
+		credentials bool
+		args        []string
+		wantSuffix  string
+		wantErr     string
+	}{
+		{"route", "route", false, []string{"--reason", "returned"}, "delete: returned\n", ""},
+		{"route with password", "route", true, []string{"--reason", "returned", "--auth", "password"}, "delete: returned\npassword: s3cret pass\n", ""},
+		{"route password without credentials", "route", false, []string{"--auth", "password"}, "", "no stored credentials"},
+		{"route unknown auth", "route", true, []string{"--auth", "pgp"}, "", "invalid --auth"},
+		{"contact", "contact", false, nil, "delete: no longer in use\n", ""},
+		{"contact with password", "contact", true, []string{"--reason", "left", "--auth", "password"}, "delete: left\npassword: s3cret pass\n", ""},
+		{"contact unknown auth", "contact", true, []string{"--auth", "md5"}, "", "invalid --auth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := setTestContext(t)
+			if err := client.CreateRoute(context.Background(), &route); err != nil {
+				t.Fatalf("CreateRoute() failed: %v", err)
+			}
+			if err := client.CreateContact(context.Background(), &contact); err != nil {
+				t.Fatalf("CreateContact() failed: %v", err)
+			}
+			if tt.credentials {
+				setTestCredentials(t, "alice", "s3cret pass")
+			}
+
+			var cmd *cobra.Command
+			var args []string
+			var body string
+			switch tt.object {
+			case "route":
+				cmd = newRouteDeleteCmd(ctx.Logger)
+				args = []string{"192.0.2.0/24", "AS64500", "--rpsl"}
+				body = route.ToRPSL()
+			default:
+				cmd = newContactDeleteCmd(ctx.Logger)
+				args = []string{"ADMIN-1", "--rpsl"}
+				body = contact.ToRPSL()
+			}
+
+			out, err := runRPSLDelete(t, cmd, append(args, tt.args...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("delete --rpsl error = %v, want %q", err, tt.wantErr)
+				}
+				if out != "" {
+					t.Errorf("failed delete --rpsl printed %q", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("delete --rpsl failed: %v", err)
+			}
+			if want := body + tt.wantSuffix; out != want {
+				t.Errorf("delete --rpsl printed\n%s\nwant\n%s", out, want)
+			}
+
+			// Nothing is deleted
+			if _, err := client.GetRoute(context.Background(), "192.0.2.0/24", "AS64500"); err != nil {
+				t.Errorf("route gone after delete --rpsl: %v", err)
+			}
+			if _, err := client.GetContact(context.Background(), "ADMIN-1"); err != nil {
+				t.Errorf("contact gone after delete --rpsl: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newRouteAuditCmd creates the route audit command.
+func newRouteAuditCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit routes against policy",
+	}
+
+	cmd.AddCommand(
+		newRouteAuditAssertionsCmd(logger),
+		newRouteAuditDualStackCmd(logger),
+	)
+
+	return cmd
+}
+
+// newRouteAuditAssertionsCmd creates the route audit assertions command.
+func newRouteAuditAssertionsCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "assertions",
+		Short: "Check routes against the assertions in audit.assertions",
+		Long: `Check every route against the ownership assertions declared under
+audit.assertions in the configuration, such as "every route originated by
+AS64500 has mnt-by MAINT-X and a descr matching ^ACME".
+
+An assertion's match (origin, prefix, mnt_by) selects routes; every matched
+route must then satisfy all of its requirements (mnt_by, origin, descr).
+The daemon evaluates the same assertions on each check and alerts the
+notification sinks of the teams owning the violating routes.
+
+The command exits non-zero when any route violates an assertion.`,
+		Example: `  radb-client route audit assertions
+  radb-client route audit assertions -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			assertions, err := routeAssertions(ctx.Config.Audit)
+			if err != nil {
+				return err
+			}
+			if len(assertions) == 0 {
+				return fmt.Errorf("no assertions configured (add them under audit.assertions)")
+			}
+
+			routes, err := ctx.APIClient.ListRoutes(cmd.Context(), nil)
+			if err != nil {
+				return fmt.Errorf("failed to list routes: %w", err)
+			}
+			logger.Debugf("Checking %d routes against %d assertions", routes.Count, len(assertions))
+
+			violations := audit.EvaluateAssertions(routes.Routes, assertions)
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if err := outputter.RenderViolations(violations); err != nil {
+				return err
+			}
+
+			if len(violations) > 0 {
+				return fmt.Errorf("route assertions audit found %d violations", len(violations))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// newRouteAuditDualStackCmd creates the route audit dualstack command.
+func newRouteAuditDualStackCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		mappingFile  string
+		origin       string
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dualstack",
+		Short: "Find where IPv6 route registrations lag IPv4",
+		Long: `Pair IPv4 route objects with their expected route6 counterparts and report
+where IPv6 registrations lag IPv4.
+
+Without --mapping, routes are grouped by origin ASN and every origin with
+route objects needs at least one route6 object. With --mapping, each
+registered IPv4 prefix in the mapping file needs a route6 object with the
+same origin for each of its IPv6 prefixes. The mapping file has one IPv4
+prefix per line followed by its IPv6 prefixes:
+
+  # IPv4 prefix     IPv6 prefixes
+  192.0.2.0/24      2001:db8:1::/48
+  198.51.100.0/24   2001:db8:2::/48, 2001:db8:3::/48
+
+The command exits non-zero when IPv6 registrations lag anywhere.`,
+		Example: `  radb-client route audit dualstack
+  radb-client route audit dualstack --origin AS64500
+  radb-client route audit dualstack --mapping prefixes.txt -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var mapping []audit.PrefixPair
+			if mappingFile != "" {
+				file, err := os.Open(mappingFile)
+				if err != nil {
+					return fmt.Errorf("failed to open mapping: %w", err)
+				}
+				defer file.Close()
+				if mapping, err = audit.ParseDualStackMapping(file); err != nil {
+					return fmt.Errorf("invalid mapping %s: %w", mappingFile, err)
+				}
+			}
+
+			var filters map[string]string
+			if origin != "" {
+				filters = map[string]string{"origin": origin}
+			}
+			routes, err := ctx.APIClient.ListRoutes(cmd.Context(), filters)
+			if err != nil {
+				return fmt.Errorf("failed to list routes: %w", err)
+			}
+			logger.Debugf("Auditing dual-stack registration of %d routes against %d mapped prefixes", routes.Count, len(mapping))
+
+			report := audit.DualStack(routes.Routes, mapping)
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if err := outputter.RenderDualStack(report); err != nil {
+				return err
+			}
+
+			if len(report.Findings) > 0 {
+				return fmt.Errorf("dual-stack audit found %d gaps", len(report.Findings))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mappingFile, "mapping", "", "File mapping IPv4 prefixes to their IPv6 prefixes")
+	cmd.Flags().StringVar(&origin, "origin", "", "Only audit routes of this origin ASN")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// routeAssertions builds the configured assertions.
+func routeAssertions(cfg config.AuditConfig) ([]audit.Assertion, error) {
+	assertions := make([]audit.Assertion, 0, len(cfg.Assertions))
+	for _, ac := range cfg.Assertions {
+		assertion, err := audit.NewAssertion(ac.Name, ac.Match.Origin, ac.Match.Prefix, ac.Match.MntBy,
+			ac.Require.MntBy, ac.Require.Origin, ac.Require.Descr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit configuration: %w", err)
+		}
+		assertions = append(assertions, assertion)
+	}
+	return assertions, nil
+}
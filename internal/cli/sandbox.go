@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/config"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// sandboxMode reports whether --sandbox, RADB_SANDBOX, or sandbox.enabled
+// selects sandbox mode. The flag and variable override the config.
+func sandboxMode(cmd *cobra.Command, cfg *config.Config) bool {
+	if cmd.Flags().Changed("sandbox") {
+		enabled, _ := cmd.Flags().GetBool("sandbox")
+		return enabled
+	}
+	if env := os.Getenv("RADB_SANDBOX"); env != "" {
+		enabled, _ := strconv.ParseBool(env)
+		return enabled
+	}
+	return cfg.Sandbox.Enabled
+}
+
+// applySandbox restricts the client's writes to the configured sandbox
+// when sandbox mode is selected, and says so on stderr so the mode is
+// never mistaken for production.
+func applySandbox(cmd *cobra.Command, cfg *config.Config, client api.Client, logger *logrus.Logger) error {
+	if !sandboxMode(cmd, cfg) {
+		return nil
+	}
+
+	sandbox, err := api.NewSandbox(cfg.Sandbox.Maintainer, cfg.Sandbox.Prefixes, cfg.Sandbox.ASNs)
+	if err != nil {
+		return fmt.Errorf("invalid sandbox configuration: %w (set sandbox.maintainer in the config)", err)
+	}
+	sc, ok := client.(api.SandboxClient)
+	if !ok {
+		return fmt.Errorf("sandbox mode is not supported by this client")
+	}
+	sc.SetSandbox(sandbox)
+
+	banner := color.New(color.FgBlack, color.BgYellow, color.Bold)
+	banner.Fprintf(os.Stderr, "[SANDBOX]")
+	fmt.Fprintf(os.Stderr, " writes are limited to maintainer %s and the sandbox prefixes and AS numbers; contacts are read-only\n", sandbox.Maintainer)
+	logger.Debugf("Sandbox mode: prefixes %v, AS numbers %v", sandbox.Prefixes, sandbox.ASNs)
+	return nil
+}
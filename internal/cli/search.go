@@ -1,8 +1,11 @@
 package cli
 
 import (
-	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 
 	"github.com/bss/radb-client/internal/api"
 	"github.com/sirupsen/logrus"
@@ -29,18 +32,34 @@ func NewSearchCmd(logger *logrus.Logger) *cobra.Command {
 // newSearchQueryCmd creates the search query command.
 func newSearchQueryCmd(logger *logrus.Logger) *cobra.Command {
 	var (
-		outputFormat string
-		objectType   string
+		outputFormat   string
+		objectType     string
+		baseline       string
+		updateBaseline bool
+		failOnChange   bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "query <search-term>",
 		Short: "Search for objects",
-		Args:  cobra.ExactArgs(1),
+		Long: `Search for objects.
+
+With --baseline, the results are compared with a result set saved earlier
+(by --update-baseline or 'search query -o json') and only additions and
+removals are reported, which tracks third-party registrations matching a
+pattern without snapshots.`,
+		Example: `  # Record today's matches, then report what changed on later runs
+  radb-client search query AS64500 --baseline as64500.json --update-baseline
+  radb-client search query AS64500 --baseline as64500.json --fail-on-change`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			query := args[0]
 
+			if baseline == "" && (updateBaseline || failOnChange) {
+				return fmt.Errorf("--update-baseline and --fail-on-change require --baseline")
+			}
+
 			// Use the shared API client from CLI context (already authenticated)
 			results, err := ctx.APIClient.Search(cmdCtx, query, objectType)
 			if err != nil {
@@ -48,6 +67,14 @@ func newSearchQueryCmd(logger *logrus.Logger) *cobra.Command {
 			}
 
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if baseline != "" {
+				current, ok := results.(*api.SearchResult)
+				if !ok {
+					return fmt.Errorf("search returned RPSL text; baseline comparison needs JSON results")
+				}
+				return compareSearchBaseline(outputter, baseline, current, updateBaseline, failOnChange)
+			}
+
 			switch outputFormat {
 			case "json":
 				return outputter.renderJSON(results)
@@ -79,10 +106,55 @@ func newSearchQueryCmd(logger *logrus.Logger) *cobra.Command {
 
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
 	cmd.Flags().StringVarP(&objectType, "type", "t", "", "Object type (route, contact, as-set, etc.)")
+	cmd.Flags().StringVar(&baseline, "baseline", "", "Report additions and removals since the result set saved in this file")
+	cmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "Save the current results to the baseline file after comparing")
+	cmd.Flags().BoolVar(&failOnChange, "fail-on-change", false, "Exit non-zero when results were added or removed")
 
 	return cmd
 }
 
+// compareSearchBaseline renders the differences between a saved result set
+// and the current results. A missing baseline file is only accepted when it
+// is about to be created.
+func compareSearchBaseline(outputter *Outputter, path string, current *api.SearchResult, update, failOnChange bool) error {
+	previous := &api.SearchResult{Query: current.Query}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, previous); err != nil {
+			return fmt.Errorf("failed to parse baseline %s: %w", path, err)
+		}
+		if previous.Query != "" && previous.Query != current.Query {
+			return fmt.Errorf("baseline %s was saved for query %q, not %q", path, previous.Query, current.Query)
+		}
+	case errors.Is(err, fs.ErrNotExist) && update:
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("baseline %s does not exist (use --update-baseline to create it)", path)
+	default:
+		return fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	diff := api.DiffSearchResults(previous, current)
+	if err := outputter.RenderSearchDiff(diff); err != nil {
+		return err
+	}
+
+	if update {
+		data, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("failed to save baseline: %w", err)
+		}
+	}
+
+	if failOnChange && diff.HasChanges() {
+		return fmt.Errorf("search results changed: %d added, %d removed", len(diff.Added), len(diff.Removed))
+	}
+	return nil
+}
+
 // newSearchValidateASNCmd creates the validate asn command.
 func newSearchValidateASNCmd(logger *logrus.Logger) *cobra.Command {
 	cmd := &cobra.Command{
@@ -90,7 +162,7 @@ func newSearchValidateASNCmd(logger *logrus.Logger) *cobra.Command {
 		Short: "Validate an ASN",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cmdCtx := context.Background()
+			cmdCtx := cmd.Context()
 			asn := args[0]
 
 			// Use the shared API client from CLI context (already authenticated)
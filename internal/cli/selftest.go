@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/validator"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// selftestMarker begins the description of every route created by selftest.
+// A pre-existing test route is only removed if its description carries it.
+const selftestMarker = "radb-client self-test"
+
+// selftestStep is the outcome of one self-test step.
+type selftestStep struct {
+	Name       string `json:"name"`
+	Passed     bool   `json:"passed"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// selftestReport is the output of the selftest command.
+type selftestReport struct {
+	Route     string         `json:"route"`
+	Write     bool           `json:"write"`
+	Passed    bool           `json:"passed"`
+	StartedAt time.Time      `json:"started_at"`
+	Steps     []selftestStep `json:"steps"`
+}
+
+// run executes a step and records its outcome. It returns false if the step failed.
+func (r *selftestReport) run(name string, fn func() error) bool {
+	start := time.Now()
+	err := fn()
+
+	step := selftestStep{
+		Name:       name,
+		Passed:     err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	r.Steps = append(r.Steps, step)
+	return err == nil
+}
+
+// NewSelftestCmd creates the selftest command.
+func NewSelftestCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		write        bool
+		prefix       string
+		origin       string
+		mntBy        string
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Verify credentials and API health end to end",
+		Long: `Verify credentials and API health against a designated test route.
+
+Without --write, the test authenticates and reads the test route. With
+--write, it creates, reads, updates, and deletes the test route, proving
+that write credentials and every write endpoint work. The test route is
+configured with selftest.prefix, selftest.origin, and selftest.mnt_by and
+should be a prefix reserved for the purpose.
+
+The command exits non-zero if any step fails, so it can be scheduled from
+cron or a monitoring system and alert on failure.`,
+		Example: `  radb-client selftest
+  radb-client selftest --write
+  radb-client selftest --write --prefix 192.0.2.0/24 --origin AS64500 --mnt-by MAINT-EXAMPLE -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if prefix == "" {
+				prefix = ctx.Config.Selftest.Prefix
+			}
+			if origin == "" {
+				origin = ctx.Config.Selftest.Origin
+			}
+			if mntBy == "" {
+				mntBy = ctx.Config.Selftest.MntBy
+			}
+			if prefix == "" || origin == "" || (write && mntBy == "") {
+				return fmt.Errorf("no test route configured: set selftest.prefix, selftest.origin, and selftest.mnt_by, or pass --prefix, --origin, and --mnt-by")
+			}
+
+			if err := validator.ValidatePrefix(prefix); err != nil {
+				return fmt.Errorf("invalid prefix: %w", err)
+			}
+			if err := validator.ValidateASN(origin); err != nil {
+				return fmt.Errorf("invalid ASN: %w", err)
+			}
+			if !strings.HasPrefix(origin, "AS") {
+				origin = "AS" + origin
+			}
+
+			route := &models.RouteObject{
+				Route:  prefix,
+				Origin: origin,
+				MntBy:  []string{mntBy},
+				Source: ctx.Config.API.Source,
+			}
+
+			report := runSelftest(cmd.Context(), logger, ctx.APIClient, route, write)
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if err := outputter.RenderSelftest(report); err != nil {
+				return err
+			}
+
+			if !report.Passed {
+				for _, step := range report.Steps {
+					if !step.Passed {
+						return fmt.Errorf("self-test failed at %s: %s", step.Name, step.Error)
+					}
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&write, "write", false, "Create, update, and delete the test route")
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Test route prefix (default from selftest.prefix)")
+	cmd.Flags().StringVar(&origin, "origin", "", "Test route origin ASN (default from selftest.origin)")
+	cmd.Flags().StringVar(&mntBy, "mnt-by", "", "Test route maintainer (default from selftest.mnt_by)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// runSelftest runs the self-test steps against the test route, stopping at
+// the first failure. A route created by the test is always deleted again.
+func runSelftest(cmdCtx context.Context, logger *logrus.Logger, client api.Client, route *models.RouteObject, write bool) *selftestReport {
+	report := &selftestReport{
+		Route:     route.ID(),
+		Write:     write,
+		StartedAt: time.Now(),
+	}
+
+	var existing *models.RouteObject
+	ok := report.run("authenticate", func() error {
+		if !client.IsAuthenticated() {
+			return fmt.Errorf("not authenticated: run 'radb-client auth login'")
+		}
+		return nil
+	}) && report.run("read", func() error {
+		var err error
+		existing, err = client.GetRoute(cmdCtx, route.Route, route.Origin)
+		if errors.Is(err, api.ErrNotFound) {
+			return nil
+		}
+		return err
+	})
+
+	if !ok || !write {
+		report.Passed = ok
+		return report
+	}
+
+	if existing != nil {
+		// Left over from an earlier run that could not clean up
+		ok = report.run("remove stale test route", func() error {
+			if len(existing.Descr) == 0 || !strings.HasPrefix(existing.Descr[0], selftestMarker) {
+				return fmt.Errorf("%s exists and was not created by selftest; refusing to modify it", route.ID())
+			}
+			return client.DeleteRoute(cmdCtx, route.Route, route.Origin)
+		})
+		if !ok {
+			return report
+		}
+	}
+
+	stamp := time.Now().UTC().Format(time.RFC3339)
+	route.Descr = []string{fmt.Sprintf("%s %s", selftestMarker, stamp)}
+
+	if !report.run("create", func() error {
+		return client.CreateRoute(cmdCtx, route)
+	}) {
+		return report
+	}
+
+	deleted := false
+	ok = report.run("read back", func() error {
+		return verifyDescr(cmdCtx, client, route)
+	}) && report.run("update", func() error {
+		route.Descr = []string{fmt.Sprintf("%s %s (updated)", selftestMarker, stamp)}
+		return client.UpdateRoute(cmdCtx, route)
+	}) && report.run("read update", func() error {
+		return verifyDescr(cmdCtx, client, route)
+	}) && report.run("delete", func() error {
+		if err := client.DeleteRoute(cmdCtx, route.Route, route.Origin); err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	}) && report.run("verify delete", func() error {
+		_, err := client.GetRoute(cmdCtx, route.Route, route.Origin)
+		if errors.Is(err, api.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("route still exists after delete")
+	})
+
+	if !deleted {
+		if err := client.DeleteRoute(cmdCtx, route.Route, route.Origin); err != nil && !errors.Is(err, api.ErrNotFound) {
+			logger.Warnf("Failed to remove test route %s: %v", route.ID(), err)
+		}
+	}
+
+	report.Passed = ok
+	return report
+}
+
+// verifyDescr fetches the route and checks that its description matches.
+func verifyDescr(cmdCtx context.Context, client api.Client, want *models.RouteObject) error {
+	got, err := client.GetRoute(cmdCtx, want.Route, want.Origin)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(got.Descr, want.Descr) {
+		return fmt.Errorf("descr is %q, want %q", got.Descr, want.Descr)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// faultyClient fails route updates when failUpdate is set, and acknowledges
+// deletes without deleting when ignoreDelete is set.
+type faultyClient struct {
+	api.Client
+	failUpdate   bool
+	ignoreDelete bool
+}
+
+func (c *faultyClient) UpdateRoute(cmdCtx context.Context, route *models.RouteObject) error {
+	if c.failUpdate {
+		return errors.New("status 500: internal server error")
+	}
+	return c.Client.UpdateRoute(cmdCtx, route)
+}
+
+func (c *faultyClient) DeleteRoute(cmdCtx context.Context, prefix, asn string) error {
+	if c.ignoreDelete {
+		return nil
+	}
+	return c.Client.DeleteRoute(cmdCtx, prefix, asn)
+}
+
+// newSelftestRoute returns the route the self-test writes.
+func newSelftestRoute() *models.RouteObject {
+	return &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"}
+}
+
+// selftestSteps returns the names of the steps in a report, and the name of
+// the first that failed.
+func selftestSteps(report *selftestReport) ([]string, string) {
+	var names []string
+	failed := ""
+	for _, step := range report.Steps {
+		names = append(names, step.Name)
+		if !step.Passed && failed == "" {
+			failed = step.Name
+		}
+	}
+	return names, failed
+}
+
+// selftestRouteExists reports whether the test route is in client.
+func selftestRouteExists(t *testing.T, client api.Client) bool {
+	t.Helper()
+
+	_, err := client.GetRoute(context.Background(), "192.0.2.0/24", "AS64500")
+	if errors.Is(err, api.ErrNotFound) {
+		return false
+	}
+	if err != nil {
+		t.Fatalf("GetRoute() failed: %v", err)
+	}
+	return true
+}
+
+func newSelftestLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return logger
+}
+
+func TestSelftestPasses(t *testing.T) {
+	client := api.NewMemoryClient("RADB", newSelftestLogger())
+	recorder := &recordingClient{Client: client}
+
+	report := runSelftest(context.Background(), newSelftestLogger(), recorder, newSelftestRoute(), true)
+	steps, failed := selftestSteps(report)
+	if !report.Passed || failed != "" {
+		t.Fatalf("self-test failed at %s: %+v", failed, report.Steps)
+	}
+
+	want := []string{"authenticate", "read", "create", "read back", "update", "read update", "delete", "verify delete"}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps = %v, want %v", steps, want)
+	}
+	if selftestRouteExists(t, client) {
+		t.Error("test route left behind")
+	}
+
+	readOnly := &recordingClient{Client: client}
+	report = runSelftest(context.Background(), newSelftestLogger(), readOnly, newSelftestRoute(), false)
+	if steps, _ := selftestSteps(report); !report.Passed || len(steps) != 2 {
+		t.Errorf("read-only steps = %v, passed %v; want authenticate and read", steps, report.Passed)
+	}
+	if writes := readOnly.Writes(); len(writes) != 0 {
+		t.Errorf("read-only self-test wrote %v", writes)
+	}
+}
+
+func TestSelftestStaleRoute(t *testing.T) {
+	tests := []struct {
+		name       string
+		descr      []string
+		wantPassed bool
+	}{
+		{"left by an earlier self-test", []string{selftestMarker + " 2024-01-01T00:00:00Z"}, true},
+		{"not created by selftest", []string{"Production route"}, false},
+		{"without a description", nil, false},
+		{"marker not at the start", []string{"Copy of " + selftestMarker}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := api.NewMemoryClient("RADB", newSelftestLogger())
+			stale := newSelftestRoute()
+			stale.Descr = tt.descr
+			if err := client.CreateRoute(context.Background(), stale); err != nil {
+				t.Fatalf("CreateRoute() failed: %v", err)
+			}
+			recorder := &recordingClient{Client: client}
+
+			report := runSelftest(context.Background(), newSelftestLogger(), recorder, newSelftestRoute(), true)
+			steps, failed := selftestSteps(report)
+			if report.Passed != tt.wantPassed {
+				t.Fatalf("passed = %v, want %v (failed at %s)", report.Passed, tt.wantPassed, failed)
+			}
+			if steps[2] != "remove stale test route" {
+				t.Errorf("steps = %v, want the stale route handled after reading", steps)
+			}
+			if tt.wantPassed {
+				return
+			}
+
+			if failed != "remove stale test route" || !strings.Contains(report.Steps[2].Error, "refusing") {
+				t.Errorf("failed step = %s (%s), want a refusal to remove the stale route", failed, report.Steps[2].Error)
+			}
+			if len(steps) != 3 {
+				t.Errorf("steps = %v, want none after the refusal", steps)
+			}
+			if writes := recorder.Writes(); len(writes) != 0 {
+				t.Errorf("refused self-test wrote %v", writes)
+			}
+			live, err := client.GetRoute(context.Background(), "192.0.2.0/24", "AS64500")
+			if err != nil || !reflect.DeepEqual(live.Descr, tt.descr) {
+				t.Errorf("route = %+v, %v; want it untouched", live, err)
+			}
+		})
+	}
+}
+
+func TestSelftestCleansUpAfterFailure(t *testing.T) {
+	client := api.NewMemoryClient("RADB", newSelftestLogger())
+	faulty := &faultyClient{Client: client, failUpdate: true}
+
+	report := runSelftest(context.Background(), newSelftestLogger(), faulty, newSelftestRoute(), true)
+	steps, failed := selftestSteps(report)
+	if report.Passed || failed != "update" {
+		t.Fatalf("failed at %q (steps %v), want the update to fail", failed, steps)
+	}
+	if steps[len(steps)-1] != "update" {
+		t.Errorf("steps = %v, want none after the failed update", steps)
+	}
+	if selftestRouteExists(t, client) {
+		t.Error("test route not removed after the failed update")
+	}
+}
+
+func TestSelftestVerifiesDelete(t *testing.T) {
+	client := api.NewMemoryClient("RADB", newSelftestLogger())
+	faulty := &faultyClient{Client: client, ignoreDelete: true}
+
+	report := runSelftest(context.Background(), newSelftestLogger(), faulty, newSelftestRoute(), true)
+	_, failed := selftestSteps(report)
+	if report.Passed || failed != "verify delete" {
+		t.Fatalf("failed at %q, want the delete verification to fail", failed)
+	}
+	last := report.Steps[len(report.Steps)-1]
+	if !strings.Contains(last.Error, "still exists") {
+		t.Errorf("verify delete error = %q, want the route reported as still existing", last.Error)
+	}
+}
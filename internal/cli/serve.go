@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bss/radb-client/internal/daemon"
+	"github.com/bss/radb-client/internal/version"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewServeCmd creates the serve command.
+func NewServeCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		listen        string
+		webhooks      bool
+		readAPI       bool
+		stateAPI      bool
+		grpcAPI       bool
+		interval      int
+		metricsListen string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the daemon with an HTTP listener",
+		Long: `Run the monitoring daemon together with an HTTP listener.
+
+With --webhooks, authenticated POST requests trigger work immediately
+instead of waiting for the next interval:
+
+  POST /webhooks/check      Run a check cycle
+  POST /webhooks/snapshot   Save a snapshot (JSON body: {"filters": {...}, "note": "..."})
+  POST /webhooks/reconcile  Run a check cycle and apply snapshot retention
+
+Requests must carry "Authorization: Bearer <secret>" or an X-Radb-Signature
+header of the form "sha256=<hex HMAC-SHA256 of the body>". The secret is read
+from serve.webhook_secret or the RADB_SERVE_WEBHOOK_SECRET environment variable.
+
+With --read-api, dashboards can query the latest snapshots, authenticated
+with the same bearer secret:
+
+  GET /api/routes          Routes (query parameters prefix, origin, mnt-by)
+  GET /api/contacts        Contacts
+  GET /api/contacts/{id}   A single contact
+
+Reads are answered from the latest snapshot without calling RADb. Once a
+snapshot is older than serve.read_max_age seconds it is still served, but
+refreshed from RADb in the background. Responses name the serving snapshot
+in X-Radb-Snapshot and give its age in seconds in Age.
+
+With --state-api, tools can read the snapshots and changelog the daemon
+collects and trigger checks, authenticated with the same bearer secret:
+
+  GET  /api/snapshots        Snapshot listings (query parameters type, scope, tag, since, until, limit)
+  GET  /api/snapshots/{id}   A snapshot with its routes or contacts
+  GET  /api/diff             Changes between two snapshots (query parameters from, to)
+  GET  /api/history          Changelog entries (query parameters since, until, type, limit)
+  POST /api/check            Run a check cycle
+
+Times are RFC 3339 timestamps, dates (2024-01-31), or ages (24h, 7d).
+
+With --grpc, the listener also speaks HTTP/2 without TLS (h2c) and serves
+the radb.daemon.v1.DaemonState gRPC service defined in
+proto/radb/daemon/v1/daemon.proto: ListSnapshots, GetSnapshot, Diff, and
+History mirror the state API, and Watch streams snapshot, change, failure,
+and assertion events as they happen. Calls carry the bearer secret in the
+"authorization" metadata key. Generate clients from the proto file with
+protoc or buf.
+
+GET /healthz and GET /readyz report liveness and readiness without
+authentication, for Kubernetes probes and monitoring (see 'radb-client
+daemon status').`,
+		Example: `  # Accept webhooks and check hourly
+  radb-client serve --webhooks
+
+  # Trigger a check from CI
+  curl -X POST -H "Authorization: Bearer $SECRET" http://127.0.0.1:8080/webhooks/check
+
+  # Serve dashboards from snapshots
+  radb-client serve --read-api
+  curl -H "Authorization: Bearer $SECRET" 'http://127.0.0.1:8080/api/routes?origin=AS64500'
+
+  # Chart changes from the last week
+  radb-client serve --state-api
+  curl -H "Authorization: Bearer $SECRET" 'http://127.0.0.1:8080/api/history?since=7d'
+
+  # Stream events over gRPC
+  radb-client serve --grpc
+  grpcurl -plaintext -import-path proto -proto radb/daemon/v1/daemon.proto \
+    -H "authorization: Bearer $SECRET" 127.0.0.1:8080 radb.daemon.v1.DaemonState/Watch`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+			cfg := ctx.Config
+
+			if !webhooks && !readAPI && !stateAPI && !grpcAPI {
+				return fmt.Errorf("no listeners enabled (use --webhooks, --read-api, --state-api, or --grpc)")
+			}
+
+			if !cmd.Flags().Changed("listen") {
+				listen = cfg.Serve.Listen
+			}
+
+			secret := cfg.Serve.WebhookSecret
+			if env := os.Getenv("RADB_SERVE_WEBHOOK_SECRET"); env != "" {
+				secret = env
+			}
+			if secret == "" {
+				return fmt.Errorf("serve requires serve.webhook_secret or RADB_SERVE_WEBHOOK_SECRET")
+			}
+
+			setupDaemonLogging(cfg)
+
+			logrus.Info("RADb Client server starting...")
+			logrus.Infof("Version: %s", version.Short())
+
+			if !cmd.Flags().Changed("interval") {
+				interval = cfg.Daemon.Interval
+			}
+			schedules, err := newDaemonSchedules(cfg.Schedules, cfg.Daemon.Targets, cmd.Flags().Changed("interval"))
+			if err != nil {
+				return err
+			}
+
+			unlock, err := lockDaemonState(cfg.StateDir())
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			runner, stopNotifications, err := newDaemonRunner()
+			if err != nil {
+				return err
+			}
+			reloader := newDaemonReloader(cmd, runner, stopNotifications)
+			defer reloader.close()
+
+			health := newDaemonHealth(runner, cfg, interval, schedules)
+			reloader.health = health
+
+			var cache *daemon.ReadCache
+			mux := http.NewServeMux()
+			mux.Handle("/healthz", health)
+			mux.Handle("/readyz", health)
+			if webhooks {
+				mux.Handle("/webhooks/", daemon.NewWebhookHandler(runner, secret, ctx.Logger))
+			}
+			if readAPI {
+				if cfg.Serve.ReadMaxAge <= 0 {
+					return fmt.Errorf("serve.read_max_age must be positive")
+				}
+				cache = daemon.NewReadCache(runner, time.Duration(cfg.Serve.ReadMaxAge)*time.Second, ctx.Logger)
+				defer cache.Wait()
+				defer cache.Subscribe(runner.EventBus())()
+				mux.Handle("/api/", daemon.NewReadHandler(cache, secret, ctx.Logger))
+				logrus.Infof("Serving reads from snapshots (refreshed after %d seconds)", cfg.Serve.ReadMaxAge)
+			}
+			if stateAPI {
+				stateHandler := daemon.NewStateHandler(runner, secret, ctx.Logger)
+				for _, path := range daemon.StateAPIPaths {
+					mux.Handle(path, stateHandler)
+				}
+				logrus.Info("Serving snapshots, diffs, and history from local state")
+			}
+			var grpcHandler *daemon.GRPCHandler
+			if grpcAPI {
+				grpcHandler = daemon.NewGRPCHandler(runner, secret, ctx.Logger)
+				mux.Handle(daemon.GRPCService, grpcHandler)
+				logrus.Info("Serving the DaemonState gRPC service over h2c")
+			}
+
+			server := &http.Server{
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			if grpcAPI {
+				server.Protocols = new(http.Protocols)
+				server.Protocols.SetHTTP1(true)
+				server.Protocols.SetUnencryptedHTTP2(true)
+			}
+
+			// Bind before starting the loop so address errors surface immediately
+			listener, err := net.Listen("tcp", listen)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", listen, err)
+			}
+
+			if !cmd.Flags().Changed("metrics-listen") {
+				metricsListen = cfg.Daemon.MetricsListen
+			}
+			stopMonitoring, err := startMonitoringListener(metricsListen, health)
+			if err != nil {
+				listener.Close()
+				return err
+			}
+			defer stopMonitoring()
+
+			defer coordinateDaemon(runner, cache)()
+
+			loopCtx, cancel := context.WithCancel(cmdCtx)
+			defer cancel()
+
+			go func() {
+				logrus.Infof("Listening on %s", listener.Addr())
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logrus.Errorf("HTTP server failed: %v", err)
+					cancel()
+				}
+			}()
+
+			if schedules.routeCheck == nil && interval > 0 && schedules.checksRoutes() {
+				logrus.Infof("Check interval: %d seconds (%d minutes)", interval, interval/60)
+			} else if schedules.routeCheck == nil && len(schedules.targets) == 0 {
+				logrus.Info("Scheduled checks disabled; waiting for requests")
+			}
+
+			loopErr := runDaemonLoop(loopCtx, reloader, "serve", interval, schedules)
+
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer shutdownCancel()
+			if grpcHandler != nil {
+				// Watch streams never finish on their own
+				grpcHandler.Close()
+			}
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				return fmt.Errorf("failed to shut down server: %w", err)
+			}
+
+			return loopErr
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", "127.0.0.1:8080", "Address to listen on (default from serve.listen)")
+	cmd.Flags().BoolVar(&webhooks, "webhooks", false, "Accept webhook triggers for check, snapshot, and reconcile")
+	cmd.Flags().BoolVar(&readAPI, "read-api", false, "Serve route and contact reads from the latest snapshots")
+	cmd.Flags().BoolVar(&stateAPI, "state-api", false, "Serve snapshots, diffs, and history from local state, and accept check triggers")
+	cmd.Flags().BoolVar(&grpcAPI, "grpc", false, "Serve the DaemonState gRPC service over HTTP/2 (h2c)")
+	cmd.Flags().IntVarP(&interval, "interval", "i", 3600, "Check interval in seconds, 0 disables scheduled checks (default from daemon.interval)")
+	cmd.Flags().StringVar(&metricsListen, "metrics-listen", "", "Address to serve Prometheus metrics and health checks on (default from daemon.metrics_listen)")
+
+	return cmd
+}
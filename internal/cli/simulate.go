@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/filtersim"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewSimulateCmd creates the simulate command and its subcommands.
+func NewSimulateCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Predict how other networks will treat objects before registering them",
+	}
+
+	cmd.AddCommand(newSimulateFilterCmd(logger))
+
+	return cmd
+}
+
+// newSimulateFilterCmd creates the simulate filter command.
+func newSimulateFilterCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		outputFormat string
+		asSets       []string
+		roaFile      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "filter <prefix> <asn>",
+		Short: "Predict whether IRR-based filters will accept a proposed route",
+		Long: `Evaluate a proposed route object the way common IRR-based filter
+generators (bgpq4, route server import policies) would, before it is
+registered:
+
+  prefix-length  IPv4 longer than /24 and IPv6 longer than /48 are dropped
+  as-set         the origin must be a member of --as-set, expanded
+                 recursively, since filters only include member ASNs
+  rpki           with --roas, route origin validation; RPKI-invalid routes
+                 (wrong origin, or longer than the ROA's maxLength) are dropped
+  customer-cone  the prefix must lie within a route object already
+                 registered by an ASN of --as-set
+
+--roas reads validated ROAs exported as JSON by Routinator or rpki-client.
+Checks without their data are skipped. The command exits non-zero when a
+check predicts the route will be filtered.`,
+		Example: `  radb-client simulate filter 192.0.2.0/24 AS64500 --as-set AS-UPSTREAM-CUSTOMERS
+  radb-client simulate filter 192.0.2.0/24 AS64500 --as-set AS-UPSTREAM-CUSTOMERS --roas vrps.json -o json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+			opts := filtersim.Options{ASSets: asSets}
+
+			if roaFile != "" {
+				f, err := os.Open(roaFile)
+				if err != nil {
+					return fmt.Errorf("failed to open ROA file: %w", err)
+				}
+				defer f.Close()
+				if opts.ROAs, err = filtersim.LoadROAs(f); err != nil {
+					return err
+				}
+				logger.Debugf("Loaded %d ROAs from %s", len(opts.ROAs), roaFile)
+			}
+
+			if len(asSets) > 0 {
+				client := ctx.APIClient
+				opts.Resolve = func(resolveCtx context.Context, name string) ([]string, error) {
+					logger.Debugf("Resolving %s", name)
+					return api.ASSetMembers(resolveCtx, client, name)
+				}
+
+				routes, err := ctx.APIClient.ListRoutes(cmdCtx, nil)
+				if err != nil {
+					return fmt.Errorf("failed to list routes: %w", err)
+				}
+				opts.Routes = routes.Routes
+			}
+
+			result, err := filtersim.Simulate(cmdCtx, args[0], args[1], opts)
+			if err != nil {
+				return fmt.Errorf("simulation failed: %w", err)
+			}
+			for _, warning := range result.Warnings {
+				logger.Warn(warning)
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			if err := outputter.RenderFilterSimulation(result); err != nil {
+				return err
+			}
+
+			if !result.Accepted {
+				return fmt.Errorf("%s %s would be filtered", result.Route, result.Origin)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringSliceVar(&asSets, "as-set", nil, "As-set the filters are generated from (repeatable)")
+	cmd.Flags().StringVar(&roaFile, "roas", "", "JSON file of validated ROAs for origin validation")
+
+	return cmd
+}
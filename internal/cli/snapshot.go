@@ -1,10 +1,9 @@
 package cli
 
 import (
-	"context"
 	"fmt"
+	"strings"
 
-	"github.com/bss/radb-client/internal/config"
 	"github.com/bss/radb-client/internal/models"
 	"github.com/bss/radb-client/internal/state"
 	"github.com/sirupsen/logrus"
@@ -25,6 +24,13 @@ func NewSnapshotCmd(logger *logrus.Logger) *cobra.Command {
 		newSnapshotListCmd(logger),
 		newSnapshotShowCmd(logger),
 		newSnapshotDeleteCmd(logger),
+		newSnapshotPruneCmd(logger),
+		newSnapshotTagCmd(logger),
+		newSnapshotBaselineCmd(logger),
+		newSnapshotExportCmd(logger),
+		newSnapshotImportCmd(logger),
+		newSnapshotRestoreCmd(logger),
+		newSnapshotMergeCmd(logger),
 	)
 
 	return cmd
@@ -35,31 +41,25 @@ func newSnapshotCreateCmd(logger *logrus.Logger) *cobra.Command {
 	var (
 		snapshotType string
 		note         string
+		tags         []string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new snapshot",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
-
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
-			}
-
-			stateManager, _ := state.NewFileManager(cfg.StateDir(), logger)
-			defer stateManager.Close()
+			cmdCtx := cmd.Context()
 
 			// For now, create an empty snapshot
 			// In a real implementation, this would fetch current data from the API
 			snapshot := models.NewSnapshot(models.SnapshotType(snapshotType), note)
+			snapshot.AddTags(tags...)
 
 			if err := snapshot.ComputeChecksum(); err != nil {
 				return fmt.Errorf("failed to compute checksum: %w", err)
 			}
 
-			if err := stateManager.SaveSnapshot(ctx, snapshot); err != nil {
+			if err := ctx.StateMgr.SaveSnapshot(cmdCtx, snapshot); err != nil {
 				return fmt.Errorf("failed to save snapshot: %w", err)
 			}
 
@@ -70,33 +70,56 @@ func newSnapshotCreateCmd(logger *logrus.Logger) *cobra.Command {
 
 	cmd.Flags().StringVar(&snapshotType, "type", "route", "Snapshot type (route, contact, full)")
 	cmd.Flags().StringVar(&note, "note", "", "Snapshot note/description")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Tag the snapshot (protects it from cleanup)")
 
 	return cmd
 }
 
 // newSnapshotListCmd creates the snapshot list command.
 func newSnapshotListCmd(logger *logrus.Logger) *cobra.Command {
-	var outputFormat string
+	var (
+		outputFormat string
+		query        state.SnapshotQuery
+		snapshotType string
+		since        string
+		until        string
+	)
 
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List all snapshots",
+		Long: `List snapshots, newest first, optionally filtered by their metadata.
+Filters combine; only the catalog is read, so searching hundreds of
+snapshots is fast.`,
+		Example: `  radb-client snapshot list --type route --since 7d --note-contains migration --min-items 100
+  radb-client snapshot list --tag baseline --limit 1`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
+			cmdCtx := cmd.Context()
 
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+			if snapshotType != "" {
+				query.Type = models.SnapshotType(snapshotType)
+				if query.Type != models.SnapshotTypeRoute && query.Type != models.SnapshotTypeContact {
+					return fmt.Errorf("invalid snapshot type %q (route or contact)", snapshotType)
+				}
+			}
+			var err error
+			if since != "" {
+				if query.Since, err = parseTimeSpec(since); err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+			}
+			if until != "" {
+				if query.Until, err = parseTimeSpec(until); err != nil {
+					return fmt.Errorf("invalid --until: %w", err)
+				}
 			}
 
-			stateManager, _ := state.NewFileManager(cfg.StateDir(), logger)
-			defer stateManager.Close()
-
-			snapshots, err := stateManager.ListSnapshots(ctx)
+			snapshots, err := ctx.StateMgr.QuerySnapshots(cmdCtx, query)
 			if err != nil {
 				return fmt.Errorf("failed to list snapshots: %w", err)
 			}
+			logger.Debugf("%d snapshots match", len(snapshots))
 
 			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
 			return outputter.RenderSnapshots(snapshots)
@@ -104,6 +127,14 @@ func newSnapshotListCmd(logger *logrus.Logger) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringSliceVar(&query.Tags, "tag", nil, "Only list snapshots carrying all of these tags")
+	cmd.Flags().StringVar(&snapshotType, "type", "", "Only list snapshots of this type (route, contact)")
+	cmd.Flags().StringVar(&since, "since", "", "Only list snapshots taken since (e.g., '2024-01-01', '7d')")
+	cmd.Flags().StringVar(&until, "until", "", "Only list snapshots taken until (e.g., '2024-12-31', '1d')")
+	cmd.Flags().StringVar(&query.NoteContains, "note-contains", "", "Only list snapshots whose note contains this text (case-insensitive)")
+	cmd.Flags().IntVar(&query.MinItems, "min-items", 0, "Only list snapshots with at least this many routes or contacts")
+	cmd.Flags().IntVar(&query.MaxItems, "max-items", 0, "Only list snapshots with at most this many routes or contacts")
+	cmd.Flags().IntVar(&query.Limit, "limit", 0, "Only list the newest N matches")
 	return cmd
 }
 
@@ -116,18 +147,10 @@ func newSnapshotShowCmd(logger *logrus.Logger) *cobra.Command {
 		Short: "Show snapshot details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
+			cmdCtx := cmd.Context()
 			snapshotID := args[0]
 
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
-			}
-
-			stateManager, _ := state.NewFileManager(cfg.StateDir(), logger)
-			defer stateManager.Close()
-
-			snapshot, err := stateManager.LoadSnapshot(ctx, snapshotID)
+			snapshot, err := ctx.StateMgr.LoadSnapshot(cmdCtx, snapshotID)
 			if err != nil {
 				return fmt.Errorf("failed to load snapshot: %w", err)
 			}
@@ -143,7 +166,17 @@ func newSnapshotShowCmd(logger *logrus.Logger) *cobra.Command {
 				fmt.Printf("Type: %s\n", snapshot.Type)
 				fmt.Printf("Timestamp: %s\n", snapshot.Timestamp.Format("2006-01-02 15:04:05"))
 				fmt.Printf("Note: %s\n", snapshot.Note)
+				fmt.Printf("Scope: %s\n", scopeLabel(snapshot))
+				if len(snapshot.Tags) > 0 {
+					fmt.Printf("Tags: %s\n", strings.Join(snapshot.Tags, ", "))
+				}
 				fmt.Printf("Checksum: %s\n", snapshot.Checksum)
+				if snapshot.ChecksumAlgorithm != "" {
+					fmt.Printf("Checksum algorithm: %s\n", snapshot.ChecksumAlgorithm)
+				}
+				if snapshot.AliasOf != "" {
+					fmt.Printf("Unchanged from: %s\n", snapshot.AliasOf)
+				}
 				if snapshot.Routes != nil {
 					fmt.Printf("Routes: %d\n", snapshot.Routes.Count)
 				}
@@ -169,22 +202,14 @@ func newSnapshotDeleteCmd(logger *logrus.Logger) *cobra.Command {
 		Short: "Delete a snapshot",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := context.Background()
+			cmdCtx := cmd.Context()
 			snapshotID := args[0]
 
 			if !confirm {
 				return fmt.Errorf("please confirm deletion with --confirm flag")
 			}
 
-			cfg, err := config.Load()
-			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
-			}
-
-			stateManager, _ := state.NewFileManager(cfg.StateDir(), logger)
-			defer stateManager.Close()
-
-			if err := stateManager.DeleteSnapshot(ctx, snapshotID); err != nil {
+			if err := ctx.StateMgr.DeleteSnapshot(cmdCtx, snapshotID); err != nil {
 				return fmt.Errorf("failed to delete snapshot: %w", err)
 			}
 
@@ -196,3 +221,125 @@ func newSnapshotDeleteCmd(logger *logrus.Logger) *cobra.Command {
 	cmd.Flags().BoolVar(&confirm, "confirm", false, "Confirm deletion")
 	return cmd
 }
+
+// newSnapshotPruneCmd creates the snapshot prune command.
+func newSnapshotPruneCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		outputFormat string
+		keep         int
+		olderThan    string
+		dryRun       bool
+		confirm      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete snapshots beyond the retention policy",
+		Long: `Delete old snapshots. By default the per-type retention policy is applied
+(30 route, 10 contact, and 5 full snapshots); --keep or --older-than select
+a different policy.
+
+Use --dry-run to produce a report of what would be deleted, including space
+reclaimed, per-type counts, and the oldest snapshot kept. The markdown format
+is suitable for attaching to maintenance tickets.`,
+		Example: `  # Review before pruning
+  radb-client snapshot prune --dry-run -o markdown > prune-report.md
+
+  # Keep only the last 10 snapshots
+  radb-client snapshot prune --keep 10 --confirm
+
+  # Delete snapshots older than 90 days
+  radb-client snapshot prune --older-than 90d --confirm`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			if !dryRun && !confirm {
+				return fmt.Errorf("please confirm pruning with --confirm flag, or preview with --dry-run")
+			}
+
+			options := state.CleanupOptions{DryRun: dryRun}
+			switch {
+			case keep > 0 && olderThan != "":
+				return fmt.Errorf("--keep and --older-than cannot be combined")
+			case keep > 0:
+				options.KeepCount = keep
+			case olderThan != "":
+				keepAfter, err := parseTimeSpec(olderThan)
+				if err != nil {
+					return fmt.Errorf("invalid --older-than: %w", err)
+				}
+				options.KeepAfter = keepAfter
+			default:
+				options.KeepByType = state.DefaultRetention
+			}
+
+			result, err := ctx.StateMgr.Cleanup(cmdCtx, options)
+			if err != nil {
+				return fmt.Errorf("failed to prune snapshots: %w", err)
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderCleanupResult(result)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml, markdown)")
+	cmd.Flags().IntVar(&keep, "keep", 0, "Keep only the N most recent snapshots")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Delete snapshots older than this (e.g., '90d', '720h', '2024-01-01')")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what would be deleted without deleting")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Confirm deletion")
+
+	return cmd
+}
+
+// newSnapshotTagCmd creates the snapshot tag command and its subcommands.
+func newSnapshotTagCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Tag snapshots",
+		Long: `Tag snapshots such as "baseline" or "pre-migration". Tagged snapshots
+are never removed by cleanup; remove their tags to let retention apply.`,
+	}
+
+	cmd.AddCommand(
+		newSnapshotTagUpdateCmd(logger, "add"),
+		newSnapshotTagUpdateCmd(logger, "remove"),
+	)
+
+	return cmd
+}
+
+// newSnapshotTagUpdateCmd creates the snapshot tag add or remove command.
+func newSnapshotTagUpdateCmd(logger *logrus.Logger, action string) *cobra.Command {
+	short := "Add tags to a snapshot"
+	if action == "remove" {
+		short = "Remove tags from a snapshot"
+	}
+
+	return &cobra.Command{
+		Use:     action + " <snapshot-id> <tag>...",
+		Short:   short,
+		Example: fmt.Sprintf("  radb-client snapshot tag %s route-1704110400000000000 pre-migration", action),
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var add, remove []string
+			if action == "remove" {
+				remove = args[1:]
+			} else {
+				add = args[1:]
+			}
+
+			tags, err := ctx.StateMgr.TagSnapshot(cmd.Context(), args[0], add, remove)
+			if err != nil {
+				return fmt.Errorf("failed to tag snapshot: %w", err)
+			}
+
+			if len(tags) == 0 {
+				fmt.Printf("Snapshot %s has no tags\n", args[0])
+				return nil
+			}
+			fmt.Printf("Snapshot %s tags: %s\n", args[0], strings.Join(tags, ", "))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newSnapshotBaselineCmd creates the snapshot baseline command and its subcommands.
+func newSnapshotBaselineCmd(logger *logrus.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Name snapshots as approved baselines",
+		Long: `Name a snapshot as a baseline, such as "golden", to record the approved
+state explicitly. Any command that takes a snapshot ID also accepts
+baseline:<name>, and the daemon can compare each check against a baseline
+(daemon.baseline) instead of the previous snapshot.
+
+A baseline is stored as the tag baseline:<name>, so the snapshot is never
+removed by cleanup while it is a baseline.`,
+		Example: `  radb-client snapshot baseline set golden route-1704110400000000000
+  radb-client route diff baseline:golden --live`,
+	}
+
+	cmd.AddCommand(
+		newSnapshotBaselineSetCmd(logger),
+		newSnapshotBaselineUnsetCmd(logger),
+		newSnapshotBaselineListCmd(logger),
+	)
+
+	return cmd
+}
+
+// newSnapshotBaselineSetCmd creates the snapshot baseline set command.
+func newSnapshotBaselineSetCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> <snapshot-id>",
+		Short: "Make a snapshot the named baseline",
+		Long:  "Make a snapshot the named baseline, moving the name from the snapshot that held it before.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Resolve references such as another baseline or an alias
+			snapshot, err := ctx.StateMgr.LoadSnapshot(cmd.Context(), args[1])
+			if err != nil {
+				return fmt.Errorf("failed to load snapshot: %w", err)
+			}
+			if err := state.SetBaseline(cmd.Context(), ctx.StateMgr, name, snapshot.ID); err != nil {
+				return fmt.Errorf("failed to set baseline: %w", err)
+			}
+
+			logger.Debugf("Baseline %s set to %s", name, snapshot.ID)
+			fmt.Printf("Baseline %s is now snapshot %s (%s)\n", name, snapshot.ID, snapshot.Timestamp.Format("2006-01-02 15:04:05"))
+			return nil
+		},
+	}
+}
+
+// newSnapshotBaselineUnsetCmd creates the snapshot baseline unset command.
+func newSnapshotBaselineUnsetCmd(logger *logrus.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <name>",
+		Short: "Remove a baseline",
+		Long:  "Remove a baseline. The snapshot itself is kept.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := state.UnsetBaseline(cmd.Context(), ctx.StateMgr, args[0]); err != nil {
+				return fmt.Errorf("failed to remove baseline: %w", err)
+			}
+			fmt.Printf("Baseline %s removed\n", args[0])
+			return nil
+		},
+	}
+}
+
+// newSnapshotBaselineListCmd creates the snapshot baseline list command.
+func newSnapshotBaselineListCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List baselines",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			baselines, err := state.ListBaselines(cmd.Context(), ctx.StateMgr)
+			if err != nil {
+				return fmt.Errorf("failed to list baselines: %w", err)
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderBaselines(baselines)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/internal/version"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newSnapshotExportCmd creates the snapshot export command.
+func newSnapshotExportCmd(logger *logrus.Logger) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "export <snapshot-id>...",
+		Short: "Export snapshots to a bundle",
+		Long: `Export snapshots to a gzipped tar bundle for moving them between machines
+or attaching them to a ticket. The bundle holds each snapshot's JSON and a
+manifest with its metadata and checksums; import verifies both.`,
+		Example: `  radb-client snapshot export route-1704110400000000000 -f pre-migration.tar.gz`,
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+			if file == "" {
+				file = fmt.Sprintf("radb-snapshots-%s.tar.gz", time.Now().Format("20060102-150405"))
+			}
+
+			snapshots := make([]*models.Snapshot, 0, len(args))
+			for _, id := range args {
+				snapshot, err := ctx.StateMgr.LoadSnapshot(cmdCtx, id)
+				if err != nil {
+					return fmt.Errorf("failed to load snapshot: %w", err)
+				}
+				snapshots = append(snapshots, snapshot)
+			}
+
+			out, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to create bundle: %w", err)
+			}
+			defer out.Close()
+
+			if err := state.WriteBundle(out, snapshots, "radb-client "+version.Version); err != nil {
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return fmt.Errorf("failed to write bundle: %w", err)
+			}
+
+			fmt.Printf("Exported %d snapshots to %s\n", len(snapshots), file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Bundle to write (default radb-snapshots-<timestamp>.tar.gz)")
+	return cmd
+}
+
+// newSnapshotImportCmd creates the snapshot import command.
+func newSnapshotImportCmd(logger *logrus.Logger) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "import <bundle>",
+		Short: "Import snapshots from a bundle",
+		Long: `Import the snapshots in a bundle written by 'snapshot export'. Every
+snapshot is verified against the manifest and its checksum before any is
+saved. Snapshots that already exist are skipped unless --force is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			in, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open bundle: %w", err)
+			}
+			defer in.Close()
+
+			manifest, snapshots, err := state.ReadBundle(in)
+			if err != nil {
+				return err
+			}
+			logger.Debugf("Bundle written %s by %s", manifest.CreatedAt.Format(time.RFC3339), manifest.Producer)
+
+			existing, err := ctx.StateMgr.ListSnapshots(cmdCtx)
+			if err != nil {
+				return fmt.Errorf("failed to list snapshots: %w", err)
+			}
+			exists := make(map[string]bool, len(existing))
+			for _, snapshot := range existing {
+				exists[snapshot.ID] = true
+			}
+
+			imported := 0
+			for _, snapshot := range snapshots {
+				if exists[snapshot.ID] && !force {
+					fmt.Printf("Skipped %s: already exists\n", snapshot.ID)
+					continue
+				}
+				if err := ctx.StateMgr.SaveSnapshot(cmdCtx, snapshot); err != nil {
+					return fmt.Errorf("failed to save snapshot %s: %w", snapshot.ID, err)
+				}
+				fmt.Printf("Imported %s (%s, %d items)\n", snapshot.ID, snapshot.Type, snapshot.ItemCount())
+				imported++
+			}
+
+			fmt.Printf("Imported %d of %d snapshots from %s\n", imported, len(snapshots), args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite snapshots that already exist")
+	return cmd
+}
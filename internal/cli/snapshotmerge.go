@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newSnapshotMergeCmd creates the snapshot merge command.
+func newSnapshotMergeCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		strategy     string
+		note         string
+		tags         []string
+		dryRun       bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "merge <base> <ours> <theirs>",
+		Short: "Merge two snapshots taken since a common base",
+		Long: `Compare two snapshots against the snapshot they both started from, such
+as two operators' working snapshots or an operator's and the daemon's, and
+combine their changes into a new snapshot.
+
+An object changed on only one side takes that side's version. An object
+changed differently on both sides is a conflict: both-modified, both-added,
+or modify-delete when one side deleted what the other changed. By default
+conflicts stop the merge; --strategy ours or theirs resolves them with
+that side's version.
+
+The three snapshots must have the same type and scope.`,
+		Example: `  # Show what each side changed and any conflicts
+  radb-client snapshot merge route-1704110400000000000 route-1704196800000000000 route-1704200000000000 --dry-run
+
+  # Merge, preferring our version of conflicting objects
+  radb-client snapshot merge route-1704110400000000000 route-1704196800000000000 route-1704200000000000 --strategy ours`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			mergeStrategy, err := state.ParseMergeStrategy(strategy)
+			if err != nil {
+				return err
+			}
+
+			base, err := ctx.StateMgr.LoadSnapshot(cmdCtx, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load base snapshot %s: %w", args[0], err)
+			}
+			ours, err := ctx.StateMgr.LoadSnapshot(cmdCtx, args[1])
+			if err != nil {
+				return fmt.Errorf("failed to load snapshot %s: %w", args[1], err)
+			}
+			theirs, err := ctx.StateMgr.LoadSnapshot(cmdCtx, args[2])
+			if err != nil {
+				return fmt.Errorf("failed to load snapshot %s: %w", args[2], err)
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+
+			if dryRun {
+				diff, err := state.ComputeThreeWayDiff(cmdCtx, base, ours, theirs)
+				if err != nil {
+					return err
+				}
+				return outputter.RenderThreeWayDiff(diff)
+			}
+
+			merged, diff, err := state.MergeSnapshots(cmdCtx, base, ours, theirs, mergeStrategy, note)
+			if err != nil {
+				if diff != nil {
+					if renderErr := outputter.RenderThreeWayDiff(diff); renderErr != nil {
+						logger.Warnf("Failed to render merge: %v", renderErr)
+					}
+					return fmt.Errorf("%w; resolve them with --strategy ours or --strategy theirs", err)
+				}
+				return err
+			}
+			merged.AddTags(tags...)
+
+			if err := ctx.StateMgr.SaveSnapshot(cmdCtx, merged); err != nil {
+				return fmt.Errorf("failed to save merged snapshot: %w", err)
+			}
+
+			if err := outputter.RenderThreeWayDiff(diff); err != nil {
+				return err
+			}
+			if OutputFormat(outputFormat) == OutputFormatTable {
+				fmt.Printf("\nSaved merged snapshot %s with %d changes", merged.ID, len(diff.Changes))
+				if diff.HasConflicts() {
+					fmt.Printf(" and %d conflicts resolved with %s", len(diff.Conflicts), mergeStrategy)
+				}
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&strategy, "strategy", string(state.MergeFail), "Conflict resolution: fail, ours, or theirs")
+	cmd.Flags().StringVar(&note, "note", "", "Note for the merged snapshot")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Tag the merged snapshot (protects it from cleanup)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes and conflicts without saving a snapshot")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
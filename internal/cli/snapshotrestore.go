@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// contactChange is one contact write needed to restore a snapshot.
+type contactChange struct {
+	Operation    models.BulkOperation
+	Contact      *models.Contact
+	FieldChanges []models.FieldChange // Updates only
+}
+
+// planContactChanges converts the contact items of a diff into writes:
+// creates, then updates, then deletes, each sorted by contact ID.
+func planContactChanges(diff *models.DiffResult) []contactChange {
+	var creates, updates, deletes []contactChange
+	for _, item := range diff.Added {
+		if contact, ok := item.(*models.Contact); ok {
+			creates = append(creates, contactChange{Operation: models.BulkCreateContacts, Contact: contact})
+		}
+	}
+	for _, item := range diff.Modified {
+		if contact, ok := item.After.(*models.Contact); ok {
+			updates = append(updates, contactChange{Operation: models.BulkUpdateContacts, Contact: contact, FieldChanges: item.FieldChanges})
+		}
+	}
+	for _, item := range diff.Removed {
+		if contact, ok := item.(*models.Contact); ok {
+			deletes = append(deletes, contactChange{Operation: models.BulkDeleteContacts, Contact: contact})
+		}
+	}
+
+	changes := make([]contactChange, 0, len(creates)+len(updates)+len(deletes))
+	for _, group := range [][]contactChange{creates, updates, deletes} {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Contact.ID < group[j].Contact.ID
+		})
+		changes = append(changes, group...)
+	}
+	return changes
+}
+
+// printContactChange prints a contact write and, for updates, the fields it
+// restores.
+func printContactChange(change contactChange) {
+	fmt.Printf("%s %s (%s)\n", change.Operation, change.Contact.ID, change.Contact.Name)
+	for _, field := range change.FieldChanges {
+		fmt.Printf("  %s: %s -> %s\n", field.Field, field.OldValue, field.NewValue)
+	}
+}
+
+// captureLiveState fetches the current registry data covered by a snapshot:
+// its routes, with the filters it was captured with, and its contacts.
+func captureLiveState(cmdCtx context.Context, snapshot *models.Snapshot) (*models.Snapshot, error) {
+	live := models.NewScopedSnapshot(snapshot.Type, fmt.Sprintf("Before restore of %s", snapshot.ID), snapshot.Filters())
+
+	if snapshot.Routes != nil {
+		routes, err := ctx.APIClient.ListRoutes(cmdCtx, snapshot.Filters())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list routes: %w", err)
+		}
+		if routes.Truncated {
+			return nil, fmt.Errorf("live routes were truncated by api.max_results; raise or unset it to restore")
+		}
+		live.Routes = routes
+	}
+	if snapshot.Contacts != nil {
+		contacts, err := ctx.APIClient.ListContacts(cmdCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list contacts: %w", err)
+		}
+		if contacts.Truncated {
+			return nil, fmt.Errorf("live contacts were truncated by api.max_results; raise or unset it to restore")
+		}
+		live.Contacts = contacts
+	}
+	return live, nil
+}
+
+// newSnapshotRestoreCmd creates the snapshot restore command.
+func newSnapshotRestoreCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		dryRun  bool
+		confirm bool
+		workers int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore <snapshot-id>",
+		Short: "Push a snapshot back to the registry",
+		Long: `Converge the registry on a snapshot, for example to roll back a bad bulk
+change.
+
+The snapshot is compared with the current registry data it covers: the
+routes within its scope, and contacts for contact and full snapshots.
+Objects missing from the registry are created, changed objects are updated
+to their state in the snapshot, and objects created since are deleted.
+
+The plan is shown first and --confirm is required to apply it. Before any
+write, the current state is saved as a snapshot so the restore can itself be
+undone with another restore. Writes run as bulk jobs that can be resumed
+with "bulk resume" if interrupted.`,
+		Example: `  # Show what restoring a snapshot would change
+  radb-client snapshot restore route-1704110400000000000 --dry-run
+
+  # Apply the plan
+  radb-client snapshot restore route-1704110400000000000 --confirm`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdCtx := cmd.Context()
+
+			snapshot, err := ctx.StateMgr.LoadSnapshot(cmdCtx, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load snapshot %s: %w", args[0], err)
+			}
+			if snapshot.Routes == nil && snapshot.Contacts == nil {
+				return fmt.Errorf("snapshot %s holds no routes or contacts to restore", snapshot.ID)
+			}
+
+			live, err := captureLiveState(cmdCtx, snapshot)
+			if err != nil {
+				return err
+			}
+
+			diff, err := state.ComputeDiff(cmdCtx, live, snapshot)
+			if err != nil {
+				return fmt.Errorf("failed to compute diff: %w", err)
+			}
+
+			routeChanges := planDiffChanges(diff)
+			contactChanges := planContactChanges(diff)
+			if len(routeChanges)+len(contactChanges) == 0 {
+				fmt.Printf("The registry already matches %s\n", snapshot.ID)
+				return nil
+			}
+
+			for _, change := range routeChanges {
+				printDiffChange(change)
+			}
+			for _, change := range contactChanges {
+				printContactChange(change)
+			}
+			fmt.Printf("\nRestoring %s needs %d route and %d contact changes\n", snapshot.ID, len(routeChanges), len(contactChanges))
+
+			if dryRun {
+				return nil
+			}
+			if !confirm {
+				return fmt.Errorf("re-run with --confirm to apply this plan")
+			}
+
+			if err := ctx.StateMgr.SaveSnapshot(cmdCtx, live); err != nil {
+				return fmt.Errorf("failed to save current state before restoring: %w", err)
+			}
+			fmt.Printf("Saved current state as %s\n", live.ID)
+
+			var journals []*models.BulkJournal
+			for _, operation := range []models.BulkOperation{models.BulkCreateRoutes, models.BulkUpdateRoutes, models.BulkDeleteRoutes} {
+				journal := models.NewBulkJournal(operation)
+				for _, change := range routeChanges {
+					if change.Operation == operation {
+						journal.AddRoute(change.Route())
+					}
+				}
+				journals = append(journals, journal)
+			}
+			for _, operation := range []models.BulkOperation{models.BulkCreateContacts, models.BulkUpdateContacts, models.BulkDeleteContacts} {
+				journal := models.NewBulkJournal(operation)
+				for _, change := range contactChanges {
+					if change.Operation == operation {
+						journal.AddContact(change.Contact)
+					}
+				}
+				journals = append(journals, journal)
+			}
+
+			return submitJournals(cmdCtx, logger, journals, workers)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the plan without applying it")
+	cmd.Flags().BoolVar(&confirm, "confirm", false, "Apply the plan")
+	cmd.Flags().IntVar(&workers, "workers", 0, "Parallel workers (default from performance.max_concurrent_requests)")
+
+	return cmd
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+)
+
+// testRoute returns a valid route for tests.
+func testRoute(prefix, origin, descr string) models.RouteObject {
+	return models.RouteObject{Route: prefix, Origin: origin, Descr: []string{descr}, MntBy: []string{"MAINT-TEST"}, Source: "RADB"}
+}
+
+// testContact returns a valid contact for tests.
+func testContact(id, email string, role models.ContactRole) models.Contact {
+	return models.Contact{ID: id, Name: "Contact " + id, Email: email, Role: role}
+}
+
+// setupRestore saves a full snapshot and leaves the registry changed since:
+// a route and a contact were modified, one of each deleted, and one of each
+// created.
+func setupRestore(t *testing.T) (*api.MemoryClient, *models.Snapshot) {
+	t.Helper()
+
+	client, _ := setTestContext(t)
+	cmdCtx := context.Background()
+
+	snapshot := models.NewSnapshot(models.SnapshotTypeFull, "before the bad change")
+	snapshot.Routes = models.NewRouteList([]models.RouteObject{
+		testRoute("192.0.2.0/24", "AS64500", "unchanged"),
+		testRoute("198.51.100.0/24", "AS64500", "original"),
+		testRoute("203.0.113.0/24", "AS64500", "deleted since"),
+	})
+	snapshot.Contacts = models.NewContactList([]models.Contact{
+		testContact("C-1", "keep@example.com", models.ContactRoleAdmin),
+		testContact("C-2", "original@example.com", models.ContactRoleTech),
+		testContact("C-3", "deleted@example.com", models.ContactRoleAbuse),
+	})
+	if err := ctx.StateMgr.SaveSnapshot(cmdCtx, snapshot); err != nil {
+		t.Fatalf("SaveSnapshot() failed: %v", err)
+	}
+
+	client.LoadSnapshot(snapshot)
+	modified := testRoute("198.51.100.0/24", "AS64500", "changed")
+	created := testRoute("192.0.2.128/25", "AS64500", "created since")
+	changedContact := testContact("C-2", "changed@example.com", models.ContactRoleTech)
+	createdContact := testContact("C-4", "created@example.com", models.ContactRoleBilling)
+	for _, err := range []error{
+		client.UpdateRoute(cmdCtx, &modified),
+		client.DeleteRoute(cmdCtx, "203.0.113.0/24", "AS64500"),
+		client.CreateRoute(cmdCtx, &created),
+		client.UpdateContact(cmdCtx, &changedContact),
+		client.DeleteContact(cmdCtx, "C-3"),
+		client.CreateContact(cmdCtx, &createdContact),
+	} {
+		if err != nil {
+			t.Fatalf("failed to change the registry: %v", err)
+		}
+	}
+
+	return client, snapshot
+}
+
+// runRestore runs snapshot restore with args.
+func runRestore(t *testing.T, args ...string) error {
+	t.Helper()
+
+	cmd := newSnapshotRestoreCmd(ctx.Logger)
+	cmd.SetArgs(args)
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetErr(&strings.Builder{})
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+// countSnapshots returns the number of saved snapshots.
+func countSnapshots(t *testing.T) int {
+	t.Helper()
+
+	snapshots, err := ctx.StateMgr.ListSnapshots(context.Background())
+	if err != nil {
+		t.Fatalf("ListSnapshots() failed: %v", err)
+	}
+	return len(snapshots)
+}
+
+func TestRestorePlan(t *testing.T) {
+	_, snapshot := setupRestore(t)
+
+	live, err := captureLiveState(context.Background(), snapshot)
+	if err != nil {
+		t.Fatalf("captureLiveState() failed: %v", err)
+	}
+	diff, err := state.ComputeDiff(context.Background(), live, snapshot)
+	if err != nil {
+		t.Fatalf("ComputeDiff() failed: %v", err)
+	}
+
+	var routePlan []string
+	for _, change := range planDiffChanges(diff) {
+		routePlan = append(routePlan, fmt.Sprintf("%s %s", change.Operation, change.Route().ID()))
+	}
+	wantRoutes := []string{
+		fmt.Sprintf("%s 203.0.113.0/24-AS64500", models.BulkCreateRoutes),
+		fmt.Sprintf("%s 198.51.100.0/24-AS64500", models.BulkUpdateRoutes),
+		fmt.Sprintf("%s 192.0.2.128/25-AS64500", models.BulkDeleteRoutes),
+	}
+	if !reflect.DeepEqual(routePlan, wantRoutes) {
+		t.Errorf("route plan = %v, want %v", routePlan, wantRoutes)
+	}
+
+	var contactPlan []string
+	for _, change := range planContactChanges(diff) {
+		contactPlan = append(contactPlan, fmt.Sprintf("%s %s", change.Operation, change.Contact.ID))
+	}
+	wantContacts := []string{
+		fmt.Sprintf("%s C-3", models.BulkCreateContacts),
+		fmt.Sprintf("%s C-2", models.BulkUpdateContacts),
+		fmt.Sprintf("%s C-4", models.BulkDeleteContacts),
+	}
+	if !reflect.DeepEqual(contactPlan, wantContacts) {
+		t.Errorf("contact plan = %v, want %v", contactPlan, wantContacts)
+	}
+
+	for _, change := range planDiffChanges(diff) {
+		if change.Operation == models.BulkUpdateRoutes && change.After.Descr[0] != "original" {
+			t.Errorf("update restores description %q, want the snapshot's", change.After.Descr[0])
+		}
+	}
+}
+
+func TestRestoreDryRunWritesNothing(t *testing.T) {
+	client, snapshot := setupRestore(t)
+	recorder := &recordingClient{Client: client}
+	ctx.APIClient = recorder
+	before := countSnapshots(t)
+
+	if err := runRestore(t, snapshot.ID, "--dry-run"); err != nil {
+		t.Fatalf("restore --dry-run failed: %v", err)
+	}
+	if writes := recorder.Writes(); len(writes) != 0 {
+		t.Errorf("dry run wrote %v", writes)
+	}
+	if after := countSnapshots(t); after != before {
+		t.Errorf("dry run saved %d snapshots", after-before)
+	}
+}
+
+func TestRestoreRequiresConfirm(t *testing.T) {
+	client, snapshot := setupRestore(t)
+	recorder := &recordingClient{Client: client}
+	ctx.APIClient = recorder
+	before := countSnapshots(t)
+
+	err := runRestore(t, snapshot.ID)
+	if err == nil || !strings.Contains(err.Error(), "--confirm") {
+		t.Fatalf("restore without --confirm = %v, want a refusal", err)
+	}
+	if writes := recorder.Writes(); len(writes) != 0 {
+		t.Errorf("refused restore wrote %v", writes)
+	}
+	if after := countSnapshots(t); after != before {
+		t.Errorf("refused restore saved %d snapshots", after-before)
+	}
+}
+
+func TestRestoreSavesCurrentStateFirst(t *testing.T) {
+	client, snapshot := setupRestore(t)
+	before := countSnapshots(t)
+
+	var writtenEarly atomic.Bool
+	recorder := &recordingClient{Client: client}
+	recorder.beforeWrite = func() {
+		if countSnapshots(t) != before+1 {
+			writtenEarly.Store(true)
+		}
+	}
+	ctx.APIClient = recorder
+
+	if err := runRestore(t, snapshot.ID, "--confirm"); err != nil {
+		t.Fatalf("restore --confirm failed: %v", err)
+	}
+	if len(recorder.Writes()) != 6 {
+		t.Errorf("writes = %v, want 6", recorder.Writes())
+	}
+	if writtenEarly.Load() {
+		t.Error("a write was sent before the current state was saved")
+	}
+
+	snapshots, err := ctx.StateMgr.ListSnapshots(context.Background())
+	if err != nil {
+		t.Fatalf("ListSnapshots() failed: %v", err)
+	}
+	var preRestore *models.Snapshot
+	for _, listed := range snapshots {
+		if listed.ID != snapshot.ID {
+			preRestore, err = ctx.StateMgr.LoadSnapshot(context.Background(), listed.ID)
+			if err != nil {
+				t.Fatalf("LoadSnapshot() failed: %v", err)
+			}
+		}
+	}
+	if preRestore == nil || !strings.Contains(preRestore.Note, snapshot.ID) {
+		t.Fatalf("pre-restore snapshot = %+v, want one noting %s", preRestore, snapshot.ID)
+	}
+	if preRestore.Routes.Count != 3 || preRestore.Routes.ByID()["192.0.2.128/25-AS64500"] == nil {
+		t.Errorf("pre-restore routes = %+v, want the registry before the restore", preRestore.Routes.Routes)
+	}
+
+	live, err := captureLiveState(context.Background(), snapshot)
+	if err != nil {
+		t.Fatalf("captureLiveState() failed: %v", err)
+	}
+	diff, err := state.ComputeDiff(context.Background(), live, snapshot)
+	if err != nil {
+		t.Fatalf("ComputeDiff() failed: %v", err)
+	}
+	if len(planDiffChanges(diff))+len(planContactChanges(diff)) != 0 {
+		t.Errorf("registry still differs from the snapshot after restore: %+v", diff)
+	}
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/pkg/objectstore"
+	"github.com/sirupsen/logrus"
+)
+
+// Ensure the object store client can back a state manager.
+var _ state.Backend = (*objectstore.Client)(nil)
+
+// newStateManager creates the state manager for the configured backend.
+func newStateManager(cfg *config.Config, logger *logrus.Logger) (state.Manager, error) {
+	integrity := state.Integrity{
+		Algorithm: cfg.State.Integrity.Algorithm,
+		PerObject: cfg.State.Integrity.PerObject,
+	}
+
+	switch cfg.State.Backend {
+	case "", "file":
+		mgr, err := state.NewFileManager(cfg.Preferences.CacheDir, logger)
+		if err != nil {
+			return nil, err
+		}
+		mgr.SetIntegrity(integrity)
+		return mgr, nil
+	case "s3", "gcs", "minio":
+	default:
+		return nil, fmt.Errorf("unsupported state backend %q (want file, s3, gcs, or minio)", cfg.State.Backend)
+	}
+
+	store, err := objectstore.New(objectStoreConfig(cfg.State))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Storing snapshots in %s (%s backend)", store, cfg.State.Backend)
+	mgr := state.NewBackendManager(store, logger)
+	mgr.SetIntegrity(integrity)
+	return mgr, nil
+}
+
+// objectStoreConfig fills in the endpoint, region, and credentials each
+// object store backend defaults to. Google Cloud Storage is reached through
+// its S3-compatible XML API with HMAC keys.
+func objectStoreConfig(sc config.StateConfig) objectstore.Config {
+	oc := sc.ObjectStore
+	cfg := objectstore.Config{
+		Endpoint:        oc.Endpoint,
+		Region:          oc.Region,
+		Bucket:          oc.Bucket,
+		Prefix:          oc.Prefix,
+		AccessKeyID:     oc.AccessKeyID,
+		SecretAccessKey: oc.SecretAccessKey,
+		PathStyle:       oc.PathStyle,
+	}
+
+	switch sc.Backend {
+	case "s3":
+		if cfg.Region == "" {
+			cfg.Region = "us-east-1"
+		}
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+		}
+	case "gcs":
+		if cfg.Region == "" {
+			cfg.Region = "auto"
+		}
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "https://storage.googleapis.com"
+		}
+	case "minio":
+		cfg.PathStyle = true
+	}
+
+	if cfg.AccessKeyID == "" && cfg.SecretAccessKey == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+
+	return cfg
+}
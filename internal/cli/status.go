@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// Daemon states reported by the status command.
+const (
+	daemonStateRunning      = "running"
+	daemonStateStopped      = "stopped"
+	daemonStateUnresponsive = "unresponsive"
+	daemonStateNeverStarted = "never started"
+)
+
+// statusReport is the output of the status command.
+type statusReport struct {
+	Username       string               `json:"username,omitempty"`
+	Authentication string               `json:"authentication"`
+	API            models.ClientStatus  `json:"api"`
+	DaemonState    string               `json:"daemon_state"`
+	Daemon         *models.DaemonStatus `json:"daemon,omitempty"`
+}
+
+// NewStatusCmd creates the status command.
+func NewStatusCmd(logger *logrus.Logger) *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show authentication, rate limit, and daemon status",
+		Long: `Show authentication state, the current request rate, the request budget
+reported by the server, the last API error, and whether a daemon is running.
+
+API figures come from the most recent command or daemon check that contacted
+the server; this command itself makes no API requests.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := buildStatusReport(logger)
+			if err != nil {
+				return err
+			}
+
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderStatus(report)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// buildStatusReport gathers status from the credential store, the last
+// recorded client status, and the daemon heartbeat.
+func buildStatusReport(logger *logrus.Logger) (*statusReport, error) {
+	report := &statusReport{
+		Username:       ctx.Config.Credentials.Username,
+		Authentication: "not configured",
+		DaemonState:    daemonStateNeverStarted,
+	}
+
+	if report.Username != "" {
+		if _, err := ctx.CredMgr.GetPassword(report.Username); err != nil {
+			report.Authentication = "credentials missing"
+		} else {
+			report.Authentication = "credentials stored"
+		}
+	}
+
+	stateDir := ctx.Config.StateDir()
+
+	apiStatus, err := state.LoadClientStatus(stateDir)
+	if err != nil {
+		logger.Warnf("Failed to load API status: %v", err)
+	}
+	if apiStatus != nil {
+		report.API = *apiStatus
+	} else if reporter, ok := ctx.APIClient.(api.StatusReporter); ok {
+		report.API = reporter.Status()
+	}
+
+	daemonStatus, err := state.LoadDaemonStatus(stateDir)
+	if err != nil {
+		logger.Warnf("Failed to load daemon status: %v", err)
+	}
+	if daemonStatus != nil {
+		report.Daemon = daemonStatus
+		switch {
+		case daemonStatus.StoppedAt != nil:
+			report.DaemonState = daemonStateStopped
+		case daemonStatus.Alive(time.Now()):
+			report.DaemonState = daemonStateRunning
+		default:
+			report.DaemonState = daemonStateUnresponsive
+		}
+	}
+
+	return report, nil
+}
+
+// saveClientStatus records the API client status for the status command.
+// Commands that made no API requests leave the previous record in place.
+func saveClientStatus() {
+	if ctx.Config == nil {
+		return
+	}
+	reporter, ok := ctx.APIClient.(api.StatusReporter)
+	if !ok {
+		return
+	}
+
+	status := reporter.Status()
+	if status.Requests == 0 {
+		return
+	}
+	if err := state.SaveClientStatus(ctx.Config.StateDir(), &status); err != nil && ctx.Logger != nil {
+		ctx.Logger.Debugf("Failed to record API status: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewSummaryCmd creates the summary command.
+func NewSummaryCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		outputFormat string
+		since        string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "summary",
+		Short: "Summarize what changed in local data over a period",
+		Long: `Summarize on one screen what happened to your data over a period: the
+snapshots taken, the net change in route count, the maintainers whose objects
+changed most, and notable events such as origin changes, deleted routes, and
+failed writes.
+
+The summary pulls together snapshots, the changelog, and the records kept by
+route commands and bulk operations, so it covers only what this client has
+seen and done. Run 'snapshot create' or the daemon regularly to keep it
+complete.`,
+		Example: `  # The last week
+  radb-client summary --since 7d
+
+  # Since a date, as JSON
+  radb-client summary --since 2025-06-01 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := parseTimeSpec(since)
+			if err != nil {
+				return err
+			}
+			until := time.Now()
+			cmdCtx := cmd.Context()
+
+			snapshots, err := ctx.StateMgr.ListSnapshots(cmdCtx)
+			if err != nil {
+				return fmt.Errorf("failed to list snapshots: %w", err)
+			}
+
+			entries, err := state.NewHistoryManager(ctx.Config.StateDir(), logger).QueryChanges(cmdCtx, from, until, "")
+			if err != nil {
+				return fmt.Errorf("failed to query changes: %w", err)
+			}
+
+			transactions, err := state.NewTransactionStore(ctx.Config.StateDir(), logger).List()
+			if err != nil {
+				return fmt.Errorf("failed to list transactions: %w", err)
+			}
+
+			journals, err := state.NewJournalStore(ctx.Config.StateDir(), logger).List()
+			if err != nil {
+				return fmt.Errorf("failed to list bulk operations: %w", err)
+			}
+
+			summary := state.SummarizeActivity(from, until, snapshots, entries, transactions, journals)
+			outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+			return outputter.RenderActivitySummary(summary)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+	cmd.Flags().StringVar(&since, "since", "7d", "Start of the period (e.g., 7d, 24h, 2025-06-01)")
+
+	return cmd
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/bss/radb-client/internal/config"
+	"github.com/bss/radb-client/pkg/tracing"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// tracingShutdownTimeout bounds the final export of spans on exit.
+const tracingShutdownTimeout = 5 * time.Second
+
+var (
+	tracer      *tracing.Tracer
+	commandSpan *tracing.Span
+)
+
+// startTracing installs the tracer when tracing is enabled and starts a span
+// for the command, carried in the command context so every API call, snapshot
+// operation, and diff it makes becomes a child span. Long-running commands
+// get no command span; their cycles and requests are traced individually.
+func startTracing(cmd *cobra.Command, cfg config.TracingConfig, logger *logrus.Logger) {
+	if !cfg.Enabled {
+		return
+	}
+
+	tracer = tracing.NewTracer(cfg.ServiceName, cfg.Endpoint, cfg.Headers, logger)
+	tracing.SetTracer(tracer)
+	logger.Debugf("Exporting traces to %s", cfg.Endpoint)
+
+	switch cmd.Name() {
+	case "daemon", "serve":
+		return
+	}
+
+	spanCtx, span := tracing.Start(cmd.Context(), cmd.CommandPath(), tracing.String("command", cmd.CommandPath()))
+	cmd.SetContext(spanCtx)
+	commandSpan = span
+}
+
+// finishTracing ends the command span and exports any remaining spans.
+func finishTracing(err error) {
+	if tracer == nil {
+		return
+	}
+
+	commandSpan.EndErr(err)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+	defer cancel()
+	if err := tracer.Shutdown(shutdownCtx); err != nil && ctx.Logger != nil {
+		ctx.Logger.Warnf("Failed to export traces: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewResumeCmd creates the resume command for interrupted multi-step commands.
+func NewResumeCmd(logger *logrus.Logger) *cobra.Command {
+	var (
+		rollback     bool
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "resume [transaction-id]",
+		Short: "Resume or roll back interrupted route changes",
+		Long: `Route create, update, and delete run as transactions: they snapshot the
+route, then write it to the API, logging each step under the state
+directory. If a command is interrupted between steps, resume finishes the
+remaining steps, or with --rollback restores the route as it was. The
+change itself reaches the changelog from the next snapshot diff.
+
+Without a transaction ID, lists the interrupted transactions.`,
+		Example: `  radb-client resume
+  radb-client resume route-modified-1704110400000000000
+  radb-client resume route-modified-1704110400000000000 --rollback`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := state.NewTransactionStore(ctx.Config.StateDir(), logger)
+
+			if len(args) == 0 {
+				transactions, err := store.List()
+				if err != nil {
+					return err
+				}
+				if len(transactions) == 0 {
+					fmt.Println("No interrupted transactions")
+					return nil
+				}
+
+				outputter := NewOutputter(OutputFormat(outputFormat), nil, true)
+				return outputter.RenderTransactions(transactions)
+			}
+
+			tx, err := store.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			if rollback {
+				if err := rollbackRouteTransaction(cmd.Context(), logger, store, tx); err != nil {
+					return err
+				}
+				fmt.Printf("Rolled back %s of route %s\n", tx.Operation, tx.ObjectID)
+				return nil
+			}
+
+			fmt.Printf("Resuming %s at step %s\n", tx.ID, tx.NextStep())
+			if err := runRouteTransaction(cmd.Context(), logger, store, tx, true); err != nil {
+				return err
+			}
+			fmt.Printf("Completed %s of route %s\n", tx.Operation, tx.ObjectID)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&rollback, "rollback", false, "Restore the route as it was before the transaction")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, yaml)")
+
+	return cmd
+}
+
+// applyRouteChange runs a route create, update, or delete as a transaction.
+// In dry-run mode nothing is written, so the write is sent directly.
+func applyRouteChange(cmdCtx context.Context, logger *logrus.Logger, tx *models.Transaction, dryRun bool) error {
+	if dryRun {
+		return writeRouteChange(cmdCtx, tx.Operation, tx.Before, tx.After)
+	}
+
+	store := state.NewTransactionStore(ctx.Config.StateDir(), logger)
+	if err := runRouteTransaction(cmdCtx, logger, store, tx, false); err != nil {
+		if tx.StepDone(models.StepWrite) {
+			return fmt.Errorf("%w (resume with: radb-client resume %s)", err, tx.ID)
+		}
+		if deleteErr := store.Delete(tx.ID); deleteErr != nil {
+			logger.Warnf("Failed to discard transaction %s: %v", tx.ID, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// runRouteTransaction runs the remaining steps of a transaction, saving its
+// progress after each one, and removes it once every step has completed.
+// When resuming, the write is skipped if the API already reflects it.
+func runRouteTransaction(cmdCtx context.Context, logger *logrus.Logger, store *state.TransactionStore, tx *models.Transaction, resuming bool) error {
+	if err := store.Save(tx); err != nil {
+		return err
+	}
+
+	for step := tx.NextStep(); step != ""; step = tx.NextStep() {
+		var err error
+		switch step {
+		case models.StepSnapshot:
+			err = snapshotRouteTransaction(cmdCtx, logger, tx)
+		case models.StepWrite:
+			applied := false
+			if resuming {
+				if applied, err = routeChangeApplied(cmdCtx, tx); err != nil {
+					break
+				}
+			}
+			if !applied {
+				err = writeRouteChange(cmdCtx, tx.Operation, tx.Before, tx.After)
+			}
+		case models.StepChangelog:
+			// Earlier versions recorded the change here; the next snapshot
+			// diff records it now, so it is not logged twice
+		default:
+			err = fmt.Errorf("unknown transaction step: %s", step)
+		}
+
+		if err != nil {
+			tx.FailStep(step, err)
+			if saveErr := store.Save(tx); saveErr != nil {
+				logger.Warnf("Failed to save transaction %s: %v", tx.ID, saveErr)
+			}
+			return err
+		}
+
+		tx.CompleteStep(step)
+		if err := store.Save(tx); err != nil {
+			return err
+		}
+	}
+
+	tx.Status = models.TransactionCommitted
+	return store.Delete(tx.ID)
+}
+
+// rollbackRouteTransaction restores the route to its state before the
+// transaction, if the write reached the API, and removes the transaction.
+func rollbackRouteTransaction(cmdCtx context.Context, logger *logrus.Logger, store *state.TransactionStore, tx *models.Transaction) error {
+	written := tx.StepDone(models.StepWrite)
+	if !written {
+		applied, err := routeChangeApplied(cmdCtx, tx)
+		if err != nil {
+			return err
+		}
+		written = applied
+	}
+
+	if written {
+		if err := writeRouteChange(cmdCtx, tx.Change(true).Type, tx.After, tx.Before); err != nil {
+			return fmt.Errorf("failed to roll back route %s: %w", tx.ObjectID, err)
+		}
+	}
+
+	tx.Status = models.TransactionRolledBack
+	return store.Delete(tx.ID)
+}
+
+// snapshotRouteTransaction saves a scoped snapshot of the route before it is
+// changed, so the previous state stays in local history.
+func snapshotRouteTransaction(cmdCtx context.Context, logger *logrus.Logger, tx *models.Transaction) error {
+	filters := map[string]string{"prefix": tx.Before.Route, "origin": tx.Before.Origin}
+	note := fmt.Sprintf("Before %s of route %s (%s)", tx.Operation, tx.ObjectID, tx.ID)
+	snapshot := models.NewScopedSnapshot(models.SnapshotTypeRoute, note, filters)
+	snapshot.Routes = models.NewRouteList([]models.RouteObject{*tx.Before})
+	if err := snapshot.ComputeChecksum(); err != nil {
+		logger.Warnf("Failed to compute snapshot checksum: %v", err)
+	}
+
+	if err := ctx.StateMgr.SaveSnapshot(cmdCtx, snapshot); err != nil {
+		return fmt.Errorf("failed to snapshot route %s: %w", tx.ObjectID, err)
+	}
+	tx.SnapshotID = snapshot.ID
+	return nil
+}
+
+// writeRouteChange sends the API write that turns before into after.
+func writeRouteChange(cmdCtx context.Context, operation models.ChangeType, before, after *models.RouteObject) error {
+	switch operation {
+	case models.ChangeTypeAdded:
+		if err := ctx.APIClient.CreateRoute(cmdCtx, after); err != nil {
+			return fmt.Errorf("failed to create route: %w", err)
+		}
+	case models.ChangeTypeModified:
+		if err := ctx.APIClient.UpdateRoute(cmdCtx, after); err != nil {
+			return fmt.Errorf("failed to update route: %w", err)
+		}
+	case models.ChangeTypeRemoved:
+		if err := ctx.APIClient.DeleteRoute(cmdCtx, before.Route, before.Origin); err != nil {
+			return fmt.Errorf("failed to delete route: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported route operation: %s", operation)
+	}
+	return nil
+}
+
+// routeChangeApplied reports whether the live route already matches the
+// transaction's after state, or is absent for a delete.
+func routeChangeApplied(cmdCtx context.Context, tx *models.Transaction) (bool, error) {
+	route := tx.After
+	if route == nil {
+		route = tx.Before
+	}
+
+	live, err := ctx.APIClient.GetRoute(cmdCtx, route.Route, route.Origin)
+	if errors.Is(err, api.ErrNotFound) {
+		return tx.After == nil, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get route %s: %w", tx.ObjectID, err)
+	}
+	return tx.After != nil && live.ToRPSL() == tx.After.ToRPSL(), nil
+}
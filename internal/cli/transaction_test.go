@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+)
+
+// failingUpdateClient fails every route update, after applying it when
+// applied is set, like a write whose response was lost.
+type failingUpdateClient struct {
+	api.Client
+	applied bool
+}
+
+func (c *failingUpdateClient) UpdateRoute(cmdCtx context.Context, route *models.RouteObject) error {
+	if c.applied {
+		if err := c.Client.UpdateRoute(cmdCtx, route); err != nil {
+			return err
+		}
+	}
+	return errors.New("connection reset by peer")
+}
+
+// newTestRouteUpdate stores a route in client and returns a transaction
+// changing its description.
+func newTestRouteUpdate(t *testing.T, client *api.MemoryClient) *models.Transaction {
+	t.Helper()
+
+	before := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"old"}, MntBy: []string{"MAINT-TEST"}, Source: "RADB"}
+	if err := client.CreateRoute(context.Background(), before); err != nil {
+		t.Fatalf("CreateRoute() failed: %v", err)
+	}
+	after := *before
+	after.Descr = []string{"new"}
+	return models.NewRouteTransaction(models.ChangeTypeModified, before, &after)
+}
+
+// liveDescr returns the description of the test route in client.
+func liveDescr(t *testing.T, client api.Client) string {
+	t.Helper()
+
+	live, err := client.GetRoute(context.Background(), "192.0.2.0/24", "AS64500")
+	if err != nil {
+		t.Fatalf("GetRoute() failed: %v", err)
+	}
+	return live.Descr[0]
+}
+
+func TestRunRouteTransactionCommits(t *testing.T) {
+	client, logger := setTestContext(t)
+	store := state.NewTransactionStore(ctx.Config.StateDir(), logger)
+	tx := newTestRouteUpdate(t, client)
+
+	if err := runRouteTransaction(context.Background(), logger, store, tx, false); err != nil {
+		t.Fatalf("runRouteTransaction() failed: %v", err)
+	}
+
+	if tx.Status != models.TransactionCommitted {
+		t.Errorf("status = %s, want committed", tx.Status)
+	}
+	if got := liveDescr(t, client); got != "new" {
+		t.Errorf("live description = %q, want the update written", got)
+	}
+
+	snapshot, err := ctx.StateMgr.LoadSnapshot(context.Background(), tx.SnapshotID)
+	if err != nil {
+		t.Fatalf("pre-write snapshot not saved: %v", err)
+	}
+	if snapshot.Routes.Count != 1 || snapshot.Routes.Routes[0].Descr[0] != "old" {
+		t.Errorf("snapshot routes = %+v, want the route before the update", snapshot.Routes.Routes)
+	}
+
+	if pending, _ := store.List(); len(pending) != 0 {
+		t.Errorf("transactions after commit = %d, want none", len(pending))
+	}
+
+	history := state.NewHistoryManager(ctx.Config.StateDir(), logger)
+	entries, err := history.GetRecentChanges(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("GetRecentChanges() failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("changelog entries = %d, want none until the next snapshot diff", len(entries))
+	}
+}
+
+func TestRunRouteTransactionCompletesLegacyChangelogStep(t *testing.T) {
+	client, logger := setTestContext(t)
+	store := state.NewTransactionStore(ctx.Config.StateDir(), logger)
+	tx := newTestRouteUpdate(t, client)
+	tx.Steps = append(tx.Steps, models.TransactionStepRecord{Name: models.StepChangelog})
+
+	if err := runRouteTransaction(context.Background(), logger, store, tx, true); err != nil {
+		t.Fatalf("runRouteTransaction() failed: %v", err)
+	}
+	if tx.NextStep() != "" || tx.Status != models.TransactionCommitted {
+		t.Errorf("steps = %+v, status %s; want all done and committed", tx.Steps, tx.Status)
+	}
+}
+
+func TestRollbackAfterFailedWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		applied bool
+	}{
+		{"write lost", false},
+		{"write applied before failing", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory, logger := setTestContext(t)
+			ctx.APIClient = &failingUpdateClient{Client: memory, applied: tt.applied}
+			store := state.NewTransactionStore(ctx.Config.StateDir(), logger)
+			tx := newTestRouteUpdate(t, memory)
+
+			if err := runRouteTransaction(context.Background(), logger, store, tx, false); err == nil {
+				t.Fatal("runRouteTransaction() should fail when the write fails")
+			}
+			if tx.StepDone(models.StepWrite) || !tx.StepDone(models.StepSnapshot) {
+				t.Fatalf("steps = %+v, want the snapshot done and the write failed", tx.Steps)
+			}
+
+			saved, err := store.Load(tx.ID)
+			if err != nil {
+				t.Fatalf("failed transaction not kept for resume: %v", err)
+			}
+			if saved.Steps[1].Error == "" {
+				t.Errorf("saved steps = %+v, want the write error recorded", saved.Steps)
+			}
+
+			if tt.applied && liveDescr(t, memory) != "new" {
+				t.Fatal("test client should have applied the write")
+			}
+
+			ctx.APIClient = memory
+			if err := rollbackRouteTransaction(context.Background(), logger, store, saved); err != nil {
+				t.Fatalf("rollbackRouteTransaction() failed: %v", err)
+			}
+			if got := liveDescr(t, memory); got != "old" {
+				t.Errorf("live description after rollback = %q, want the original", got)
+			}
+			if saved.Status != models.TransactionRolledBack {
+				t.Errorf("status = %s, want rolled-back", saved.Status)
+			}
+			if pending, _ := store.List(); len(pending) != 0 {
+				t.Errorf("transactions after rollback = %d, want none", len(pending))
+			}
+		})
+	}
+}
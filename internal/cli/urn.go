@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/validator"
+)
+
+// routeArgs resolves the route named at the start of args, either by a
+// radb:route URN or by prefix and ASN, and returns the remaining args.
+func routeArgs(args []string) (prefix, asn string, rest []string, err error) {
+	if len(args) > 0 && models.IsURN(args[0]) {
+		ref, err := models.ParseURN(args[0])
+		if err != nil {
+			return "", "", nil, err
+		}
+		if ref.ObjectType != "route" {
+			return "", "", nil, fmt.Errorf("%s is not a route reference", args[0])
+		}
+		prefix, asn, rest = ref.Prefix, ref.Origin, args[1:]
+	} else {
+		if len(args) < 2 {
+			return "", "", nil, fmt.Errorf("expected <prefix> <asn> or a %sroute:<prefix>:<asn> reference", models.URNScheme)
+		}
+		prefix, asn, rest = args[0], args[1], args[2:]
+	}
+
+	if err := validator.ValidatePrefix(prefix); err != nil {
+		return "", "", nil, fmt.Errorf("invalid prefix: %w", err)
+	}
+	if err := validator.ValidateASN(asn); err != nil {
+		return "", "", nil, fmt.Errorf("invalid ASN: %w", err)
+	}
+	if !strings.HasPrefix(asn, "AS") {
+		asn = "AS" + asn
+	}
+	return prefix, asn, rest, nil
+}
+
+// contactArg resolves a contact named by ID or by a radb:contact URN.
+func contactArg(arg string) (string, error) {
+	if !models.IsURN(arg) {
+		return arg, nil
+	}
+	ref, err := models.ParseURN(arg)
+	if err != nil {
+		return "", err
+	}
+	if ref.ObjectType != "contact" {
+		return "", fmt.Errorf("%s is not a contact reference", arg)
+	}
+	return ref.ContactID, nil
+}
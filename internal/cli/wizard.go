@@ -21,14 +21,14 @@ func NewWizardCmd(logger *logrus.Logger) *cobra.Command {
 		Short: "Interactive configuration wizard",
 		Long:  "Run an interactive wizard to set up your RADb client configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runWizard(logger)
+			return runWizard(cmd.Context(), logger)
 		},
 	}
 
 	return cmd
 }
 
-func runWizard(logger *logrus.Logger) error {
+func runWizard(cmdCtx context.Context, logger *logrus.Logger) error {
 	reader := bufio.NewReader(os.Stdin)
 
 	fmt.Println("RADb Client Configuration Wizard")
@@ -68,10 +68,16 @@ func runWizard(logger *logrus.Logger) error {
 	fmt.Println()
 	fmt.Println("Testing connection...")
 
-	ctx := context.Background()
 	client := api.NewHTTPClient(cfg.API.BaseURL, cfg.API.Source, cfg.API.Timeout, logger)
+	if err := client.SetProxy(cfg.API.Proxy.URL, cfg.API.Proxy.NoProxy); err != nil {
+		fmt.Printf("Warning: Ignoring invalid proxy configuration: %v\n", err)
+	}
+	tlsCfg := cfg.API.TLS
+	if err := client.SetTLS(tlsCfg.CAFile, tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.MinVersion); err != nil {
+		fmt.Printf("Warning: Ignoring invalid TLS configuration: %v\n", err)
+	}
 
-	if err := client.Login(ctx, username, password); err != nil {
+	if err := client.Login(cmdCtx, username, password); err != nil {
 		fmt.Printf("Warning: Connection test failed: %v\n", err)
 		fmt.Println("Configuration will be saved anyway.")
 	} else {
@@ -3,9 +3,18 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/bss/radb-client/pkg/cron"
+	"github.com/bss/radb-client/pkg/keyring"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -20,11 +29,22 @@ const (
 
 // Config represents the application configuration.
 type Config struct {
-	API          APIConfig          `mapstructure:"api"`
-	Credentials  CredentialsConfig  `mapstructure:"credentials"`
-	Preferences  PreferencesConfig  `mapstructure:"preferences"`
-	Performance  PerformanceConfig  `mapstructure:"performance"`
-	State        StateConfig        `mapstructure:"state"`
+	API           APIConfig           `mapstructure:"api"`
+	Credentials   CredentialsConfig   `mapstructure:"credentials"`
+	Preferences   PreferencesConfig   `mapstructure:"preferences"`
+	Performance   PerformanceConfig   `mapstructure:"performance"`
+	State         StateConfig         `mapstructure:"state"`
+	Serve         ServeConfig         `mapstructure:"serve"`
+	Daemon        DaemonConfig        `mapstructure:"daemon"`
+	Schedules     SchedulesConfig     `mapstructure:"schedules"`
+	Selftest      SelftestConfig      `mapstructure:"selftest"`
+	Audit         AuditConfig         `mapstructure:"audit"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	Publish       PublishConfig       `mapstructure:"publish"`
+	Diff          DiffConfig          `mapstructure:"diff"`
+	Telemetry     TelemetryConfig     `mapstructure:"telemetry"`
+	Sandbox       SandboxConfig       `mapstructure:"sandbox"`
 
 	// Runtime fields (not persisted)
 	ConfigDir  string `mapstructure:"-"`
@@ -33,38 +53,97 @@ type Config struct {
 
 // APIConfig contains API-related configuration.
 type APIConfig struct {
-	BaseURL    string       `mapstructure:"base_url"`
-	Source     string       `mapstructure:"source"`
-	Format     string       `mapstructure:"format"`
-	Timeout    int          `mapstructure:"timeout"`
-	RateLimit  RateLimit    `mapstructure:"rate_limit"`
-	Retry      RetryConfig  `mapstructure:"retry"`
+	BaseURL          string        `mapstructure:"base_url"`
+	Source           string        `mapstructure:"source"`
+	Format           string        `mapstructure:"format"`
+	Timeout          int           `mapstructure:"timeout"`
+	PageSize         int           `mapstructure:"page_size"`         // Objects per listing page (0 = server default)
+	MaxResults       int           `mapstructure:"max_results"`       // Cap on objects returned by a listing (0 = unlimited)
+	CompressRequests bool          `mapstructure:"compress_requests"` // Gzip large request bodies
+	MaxResponseSize  int           `mapstructure:"max_response_size"` // Megabytes a response may decode to (0 = unlimited)
+	MaxObjectSize    int           `mapstructure:"max_object_size"`   // Kilobytes a single streamed route may take (0 = unlimited)
+	AuthMode         string        `mapstructure:"auth_mode"`         // basic (default) or hmac
+	Signing          SigningConfig `mapstructure:"signing"`           // Request signing for auth_mode hmac
+	RateLimit        RateLimit     `mapstructure:"rate_limit"`
+	Retry            RetryConfig   `mapstructure:"retry"`
+	Proxy            ProxyConfig   `mapstructure:"proxy"`
+	TLS              TLSConfig     `mapstructure:"tls"`
+	Cache            CacheConfig   `mapstructure:"cache"`
+}
+
+// CacheConfig configures the on-disk cache of route and contact reads.
+type CacheConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	TTL     int  `mapstructure:"ttl"` // Seconds a cached response is served without asking the API
+}
+
+// SigningConfig configures HMAC request signing, used instead of Basic Auth
+// when api.auth_mode is hmac. The signing key itself is stored in the keyring.
+type SigningConfig struct {
+	KeyID           string `mapstructure:"key_id"`           // Identifies the key to the gateway
+	SignatureHeader string `mapstructure:"signature_header"` // Header carrying the signature
+	TimestampHeader string `mapstructure:"timestamp_header"` // Header carrying the signing time
+	KeyIDHeader     string `mapstructure:"key_id_header"`    // Header carrying the key ID
+}
+
+// ProxyConfig contains outbound proxy configuration.
+// When URL is empty, the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables are used.
+type ProxyConfig struct {
+	URL     string   `mapstructure:"url"`      // http://, https://, or socks5:// proxy URL
+	NoProxy []string `mapstructure:"no_proxy"` // Hosts, domains, or CIDRs to reach directly
 }
 
 // RateLimit contains rate limiting configuration.
 type RateLimit struct {
+	RequestsPerMinute int                          `mapstructure:"requests_per_minute"`
+	BurstSize         int                          `mapstructure:"burst_size"`
+	Endpoints         map[string]EndpointRateLimit `mapstructure:"endpoints"`   // Per endpoint class overrides: read, list, search, write
+	JobWeights        map[string]float64           `mapstructure:"job_weights"` // Share of the rate for each job while jobs compete
+}
+
+// EndpointRateLimit replaces the shared rate limit for one class of endpoints.
+type EndpointRateLimit struct {
 	RequestsPerMinute int `mapstructure:"requests_per_minute"`
 	BurstSize         int `mapstructure:"burst_size"`
 }
 
+// rateLimitEndpointClasses are the endpoint classes accepted in api.rate_limit.endpoints.
+var rateLimitEndpointClasses = []string{"read", "list", "search", "write"}
+
 // RetryConfig contains retry configuration.
 type RetryConfig struct {
-	MaxAttempts        int `mapstructure:"max_attempts"`
-	BackoffMultiplier  int `mapstructure:"backoff_multiplier"`
-	InitialDelayMs     int `mapstructure:"initial_delay_ms"`
+	MaxAttempts       int `mapstructure:"max_attempts"`
+	BackoffMultiplier int `mapstructure:"backoff_multiplier"`
+	InitialDelayMs    int `mapstructure:"initial_delay_ms"`
+	MaxDelayMs        int `mapstructure:"max_delay_ms"`   // Cap on a single backoff delay
+	MaxElapsedMs      int `mapstructure:"max_elapsed_ms"` // Total retry budget (0 = unlimited)
+}
+
+// TLSConfig contains TLS settings for API connections.
+// Empty fields fall back to the system defaults.
+type TLSConfig struct {
+	CAFile     string `mapstructure:"ca_file"`     // PEM bundle of additional trusted CAs
+	CertFile   string `mapstructure:"cert_file"`   // Client certificate for mutual TLS
+	KeyFile    string `mapstructure:"key_file"`    // Client private key for mutual TLS
+	MinVersion string `mapstructure:"min_version"` // Minimum TLS version ("1.2" or "1.3")
 }
 
 // CredentialsConfig contains credential storage configuration.
 type CredentialsConfig struct {
 	Username string `mapstructure:"username"`
 	// Password and API key are stored in keyring, not in config file
+
+	// KeyringBackend selects credential storage: auto (system keyring when
+	// one is detected), system, or file (encrypted file only)
+	KeyringBackend string `mapstructure:"keyring_backend"`
 }
 
 // PreferencesConfig contains user preferences.
 type PreferencesConfig struct {
-	CacheDir   string `mapstructure:"cache_dir"`
-	HistoryDir string `mapstructure:"history_dir"`
-	LogLevel   string `mapstructure:"log_level"`
+	CacheDir      string `mapstructure:"cache_dir"`
+	HistoryDir    string `mapstructure:"history_dir"`
+	LogLevel      string `mapstructure:"log_level"`
+	DefaultOutput string `mapstructure:"default_output"` // Output format when -o is not given
 }
 
 // PerformanceConfig contains performance-related settings.
@@ -79,6 +158,280 @@ type StateConfig struct {
 	EnableLocking bool   `mapstructure:"enable_locking"`
 	AtomicWrites  bool   `mapstructure:"atomic_writes"`
 	FormatVersion string `mapstructure:"format_version"`
+
+	// Backend is where snapshots are stored: file (the cache directory),
+	// or s3, gcs, or minio for a bucket shared between hosts
+	Backend     string            `mapstructure:"backend"`
+	ObjectStore ObjectStoreConfig `mapstructure:"object_store"`
+
+	Integrity IntegrityConfig `mapstructure:"integrity"`
+}
+
+// IntegrityConfig controls how snapshots are checksummed.
+type IntegrityConfig struct {
+	Algorithm string `mapstructure:"algorithm"`  // sha256 (default) or blake3
+	PerObject bool   `mapstructure:"per_object"` // Record a hash per route and contact to pinpoint corruption
+}
+
+// ObjectStoreConfig locates the bucket used by the s3, gcs, and minio state backends.
+type ObjectStoreConfig struct {
+	Endpoint        string `mapstructure:"endpoint"` // Defaults per backend; required for minio
+	Region          string `mapstructure:"region"`   // Signing region (default us-east-1)
+	Bucket          string `mapstructure:"bucket"`
+	Prefix          string `mapstructure:"prefix"`            // Key prefix, e.g. radb/
+	AccessKeyID     string `mapstructure:"access_key_id"`     // Falls back to AWS_ACCESS_KEY_ID
+	SecretAccessKey string `mapstructure:"secret_access_key"` // Falls back to AWS_SECRET_ACCESS_KEY
+	PathStyle       bool   `mapstructure:"path_style"`        // Always on for minio
+}
+
+// ServeConfig contains settings for the serve command's HTTP listener.
+type ServeConfig struct {
+	Listen        string `mapstructure:"listen"`         // Address to listen on
+	WebhookSecret string `mapstructure:"webhook_secret"` // Shared secret for webhook authentication
+	ReadMaxAge    int    `mapstructure:"read_max_age"`   // Seconds a snapshot serves reads before it is refreshed
+}
+
+// DaemonConfig contains settings for the check loop of the daemon and serve commands.
+type DaemonConfig struct {
+	Interval      int                    `mapstructure:"interval"` // Seconds between route checks without schedules.route_check (0 = none); --interval overrides
+	Adaptive      AdaptiveIntervalConfig `mapstructure:"adaptive"`
+	Backoff       FailureBackoffConfig   `mapstructure:"failure_backoff"`
+	LocalSocket   bool                   `mapstructure:"local_socket"`   // Serve reads to CLI commands on a socket in the state directory
+	Baseline      string                 `mapstructure:"baseline"`       // Named baseline checks report changes against (empty = previous snapshot)
+	MetricsListen string                 `mapstructure:"metrics_listen"` // Address serving Prometheus metrics at /metrics (empty = disabled)
+	AutoReload    bool                   `mapstructure:"auto_reload"`    // Reload when the config file changes, as on SIGHUP
+
+	// Targets are watched independently instead of the whole account
+	Targets []WatchTargetConfig `mapstructure:"targets"`
+}
+
+// WatchTargetConfig is a subset of routes the daemon checks on its own,
+// selected by maintainer, origin ASN, or prefix set. Each target keeps its
+// own snapshots and changelog entries and can notify its own sinks.
+type WatchTargetConfig struct {
+	Name       string   `mapstructure:"name"`
+	Maintainer string   `mapstructure:"maintainer"` // Routes with this mnt-by
+	Origin     string   `mapstructure:"origin"`     // Routes originated by this ASN
+	Prefixes   []string `mapstructure:"prefixes"`   // Routes within any of these ranges
+	Schedule   string   `mapstructure:"schedule"`   // Cron expression (empty = with the route check)
+	Retention  int      `mapstructure:"retention"`  // Snapshots kept for the target (0 = the route default)
+	Sinks      []string `mapstructure:"sinks"`      // Sinks for the target's changes (empty = team routing)
+}
+
+// Filters returns the route listing filters that select the target.
+func (t *WatchTargetConfig) Filters() map[string]string {
+	filters := make(map[string]string)
+	if t.Maintainer != "" {
+		filters["mnt-by"] = t.Maintainer
+	}
+	if t.Origin != "" {
+		filters["origin"] = t.Origin
+	}
+	if len(t.Prefixes) > 0 {
+		filters["prefixes"] = strings.Join(t.Prefixes, ",")
+	}
+	return filters
+}
+
+// AdaptiveIntervalConfig lets the check interval follow the rate of change:
+// a check that finds changes drops it to min_interval, and each quiet check
+// stretches it by backoff up to max_interval.
+type AdaptiveIntervalConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	MinInterval int     `mapstructure:"min_interval"` // Seconds between checks after changes
+	MaxInterval int     `mapstructure:"max_interval"` // Longest seconds between checks when quiet
+	Backoff     float64 `mapstructure:"backoff"`      // Factor the interval grows by per quiet check
+}
+
+// FailureBackoffConfig spaces out route checks while they fail, such as
+// during an API outage: the first retry follows after initial_delay, and
+// each further failure multiplies the delay by multiplier up to max_delay.
+// The usual schedule resumes after a successful check.
+type FailureBackoffConfig struct {
+	Enabled      bool    `mapstructure:"enabled"`
+	InitialDelay int     `mapstructure:"initial_delay"` // Seconds before retrying after the first failure
+	MaxDelay     int     `mapstructure:"max_delay"`     // Longest seconds between retries
+	Multiplier   float64 `mapstructure:"multiplier"`    // Factor the delay grows by per consecutive failure
+	Jitter       float64 `mapstructure:"jitter"`        // Fraction of each delay randomized either way, 0 to 1
+}
+
+// SchedulesConfig holds cron expressions for the daemon's tasks, such as
+// "*/15 * * * *", "@daily", or "@every 30m". An empty route check keeps
+// the fixed --interval; empty contact checks and cleanups do not run.
+type SchedulesConfig struct {
+	RouteCheck   string `mapstructure:"route_check"`   // Fetch routes and record changes
+	ContactCheck string `mapstructure:"contact_check"` // Fetch contacts and record changes
+	Cleanup      string `mapstructure:"cleanup"`       // Prune snapshots beyond the default retention
+}
+
+// SelftestConfig identifies the route created and deleted by selftest --write.
+// It should be a prefix reserved for the purpose, so the test never touches
+// a route that carries traffic.
+type SelftestConfig struct {
+	Prefix string `mapstructure:"prefix"` // Test route prefix
+	Origin string `mapstructure:"origin"` // Test route origin ASN
+	MntBy  string `mapstructure:"mnt_by"` // Maintainer for the test route
+}
+
+// AuditConfig contains the policy checked by audit commands.
+type AuditConfig struct {
+	RequiredContactRoles []string `mapstructure:"required_contact_roles"` // Roles every maintainer and aut-num needs
+
+	// Assertions are invariants checked by 'route audit assertions' and each daemon cycle
+	Assertions []AssertionConfig `mapstructure:"assertions"`
+
+	// Queries are saved audits run by 'audit run' alongside the built-in ones
+	Queries []QueryConfig `mapstructure:"queries"`
+}
+
+// QueryConfig is a saved audit query: the objects of one type matching
+// every condition given.
+type QueryConfig struct {
+	Name        string   `mapstructure:"name"`
+	Description string   `mapstructure:"description"`
+	Object      string   `mapstructure:"object"`  // route or contact
+	Origin      string   `mapstructure:"origin"`  // Routes originated by this ASN
+	Prefix      string   `mapstructure:"prefix"`  // Routes within this prefix
+	MntBy       string   `mapstructure:"mnt_by"`  // Objects with this maintainer
+	Descr       string   `mapstructure:"descr"`   // Regular expression some descr line must match
+	Email       string   `mapstructure:"email"`   // Regular expression the contact's email must match
+	Role        string   `mapstructure:"role"`    // Contacts in this role
+	Missing     []string `mapstructure:"missing"` // Attributes the object lacks, e.g. [descr, remarks]
+}
+
+// AssertionConfig declares an invariant every matching route must satisfy.
+type AssertionConfig struct {
+	Name    string                 `mapstructure:"name"`
+	Match   AssertionMatchConfig   `mapstructure:"match"`
+	Require AssertionRequireConfig `mapstructure:"require"`
+}
+
+// AssertionMatchConfig selects routes. Empty fields match every route.
+type AssertionMatchConfig struct {
+	Origin string `mapstructure:"origin"`
+	Prefix string `mapstructure:"prefix"` // Routes within this prefix
+	MntBy  string `mapstructure:"mnt_by"`
+}
+
+// AssertionRequireConfig lists what matched routes must have.
+type AssertionRequireConfig struct {
+	MntBy  []string `mapstructure:"mnt_by"` // Every listed maintainer
+	Origin string   `mapstructure:"origin"`
+	Descr  string   `mapstructure:"descr"` // Regular expression some descr line must match
+}
+
+// TracingConfig contains OpenTelemetry trace export settings.
+type TracingConfig struct {
+	Enabled     bool              `mapstructure:"enabled"`
+	Endpoint    string            `mapstructure:"endpoint"`     // OTLP/HTTP collector URL, e.g. http://localhost:4318
+	ServiceName string            `mapstructure:"service_name"` // service.name reported with spans
+	Headers     map[string]string `mapstructure:"headers"`      // Extra headers sent to the collector, e.g. for authentication
+}
+
+// TelemetryConfig controls local command usage statistics. Records stay in
+// the state directory and are never sent anywhere.
+type TelemetryConfig struct {
+	Enabled bool `mapstructure:"enabled"` // Record each command's name, duration, and result
+}
+
+// SandboxConfig restricts writes to a practice maintainer and to address
+// space that is never routed, for training new operators.
+type SandboxConfig struct {
+	Enabled    bool     `mapstructure:"enabled"`    // Refuse writes outside the sandbox (or use --sandbox)
+	Maintainer string   `mapstructure:"maintainer"` // The only maintainer writes may use
+	Prefixes   []string `mapstructure:"prefixes"`   // Allowed prefixes; default is the documentation prefixes
+	ASNs       []string `mapstructure:"asns"`       // Allowed origins, as "AS64512" or "64512-65534"; default is the private and documentation ranges
+}
+
+// NotificationsConfig routes detected changes to the teams that own them.
+type NotificationsConfig struct {
+	Sinks        []SinkConfig            `mapstructure:"sinks"`         // Named delivery destinations
+	Teams        []TeamConfig            `mapstructure:"teams"`         // A change goes to every team that owns it
+	DefaultSinks []string                `mapstructure:"default_sinks"` // Sinks for changes no team owns
+	Retry        NotificationRetryConfig `mapstructure:"retry"`         // Redelivery of notifications a sink rejected
+
+	// Alert rules classify each change by the first rule it matches;
+	// severity none suppresses it
+	Rules           []AlertRuleConfig `mapstructure:"rules"`
+	DefaultSeverity string            `mapstructure:"default_severity"` // Severity of changes no rule matches (default info)
+}
+
+// AlertRuleConfig matches changes and sets their severity. Every condition
+// that is set must hold; empty lists match everything.
+type AlertRuleConfig struct {
+	Name        string   `mapstructure:"name"`
+	ObjectTypes []string `mapstructure:"object_types"` // route and/or contact
+	ChangeTypes []string `mapstructure:"change_types"` // added, removed, and/or modified
+	Prefixes    []string `mapstructure:"prefixes"`     // Routes within any of these; excludes contacts
+	Origins     []string `mapstructure:"origins"`      // Routes with any of these origin ASNs; excludes contacts
+	Fields      []string `mapstructure:"fields"`       // Modifications changing any of these fields
+	OnlyFields  []string `mapstructure:"only_fields"`  // Modifications changing none but these fields
+	Severity    string   `mapstructure:"severity"`     // none, info, warning, error, or critical
+}
+
+// NotificationRetryConfig controls the queue of notifications awaiting
+// redelivery. Zero values use the defaults.
+type NotificationRetryConfig struct {
+	InitialDelay int `mapstructure:"initial_delay"` // Seconds before the first retry (default 30); doubles per attempt
+	MaxDelay     int `mapstructure:"max_delay"`     // Longest wait between retries in seconds (default 3600)
+	MaxAttempts  int `mapstructure:"max_attempts"`  // Attempts before a notification is dropped (default 12)
+}
+
+// SinkConfig is a named notification destination.
+type SinkConfig struct {
+	Name    string            `mapstructure:"name"`
+	Type    string            `mapstructure:"type"`    // webhook, slack, teams, email, pagerduty, or opsgenie
+	URL     string            `mapstructure:"url"`     // http(s) URL for webhooks and Slack or Teams incoming webhooks; smtp:// or smtps:// server for email; optional API endpoint for pagerduty and opsgenie
+	Headers map[string]string `mapstructure:"headers"` // Extra HTTP headers, e.g. Authorization
+
+	// Narrow what the sink receives; empty lists allow everything. For
+	// pagerduty and opsgenie sinks they decide which changes are critical.
+	ObjectTypes []string `mapstructure:"object_types"` // route and/or contact
+	ChangeTypes []string `mapstructure:"change_types"` // added, removed, and/or modified
+	Prefixes    []string `mapstructure:"prefixes"`     // Routes within any of these; excludes contacts
+	MinSeverity string   `mapstructure:"min_severity"` // Changes alert rules classify at least this severe
+
+	// PagerDuty and Opsgenie sinks
+	Key      string `mapstructure:"key"`      // PagerDuty integration routing key or Opsgenie API key
+	Severity string `mapstructure:"severity"` // PagerDuty severity (default critical) or Opsgenie priority (default P1)
+
+	// Email sinks
+	From    string   `mapstructure:"from"`    // Sender address
+	To      []string `mapstructure:"to"`      // Recipient addresses
+	Subject string   `mapstructure:"subject"` // Subject template (Go text/template); empty for the default
+	Body    string   `mapstructure:"body"`    // Body template (Go text/template); empty for the default
+}
+
+// PublishConfig exports daemon events to message brokers.
+type PublishConfig struct {
+	Publishers []PublisherConfig `mapstructure:"publishers"`
+}
+
+// PublisherConfig is a named message broker destination.
+type PublisherConfig struct {
+	Name     string            `mapstructure:"name"`
+	Type     string            `mapstructure:"type"`     // nats, kafka (REST Proxy), or rabbitmq-management
+	URL      string            `mapstructure:"url"`      // Credentials may be given in the URL
+	Topic    string            `mapstructure:"topic"`    // NATS subject prefix, Kafka topic, or RabbitMQ routing key prefix
+	Exchange string            `mapstructure:"exchange"` // RabbitMQ exchange (default amq.topic)
+	VHost    string            `mapstructure:"vhost"`    // RabbitMQ virtual host (default /)
+	Headers  map[string]string `mapstructure:"headers"`  // Extra HTTP headers for kafka and rabbitmq-management
+}
+
+// DiffConfig controls which differences route diff reports as
+// modifications. Fields are named as RPSL attributes, e.g. mnt-by.
+type DiffConfig struct {
+	IgnoreFields        []string `mapstructure:"ignore_fields"`         // Fields never compared, e.g. remarks
+	UnorderedListFields []string `mapstructure:"unordered_list_fields"` // List fields compared regardless of line order
+	IgnoreCase          bool     `mapstructure:"ignore_case"`           // Compare values case-insensitively
+}
+
+// TeamConfig maps maintainers and prefix ranges to a team's sinks.
+type TeamConfig struct {
+	Name        string   `mapstructure:"name"`
+	Maintainers []string `mapstructure:"maintainers"` // Owns routes and contacts with any of these mnt-by
+	Prefixes    []string `mapstructure:"prefixes"`    // Owns routes within any of these ranges
+	Sinks       []string `mapstructure:"sinks"`
 }
 
 // Default returns a configuration with sensible defaults.
@@ -88,10 +441,18 @@ func Default() *Config {
 
 	return &Config{
 		API: APIConfig{
-			BaseURL: "https://api.radb.net/api",
-			Source:  "RADB",
-			Format:  "json",
-			Timeout: 30,
+			BaseURL:         "https://api.radb.net/api",
+			Source:          "RADB",
+			Format:          "json",
+			Timeout:         30,
+			AuthMode:        "basic",
+			MaxResponseSize: 64,
+			MaxObjectSize:   1024,
+			Signing: SigningConfig{
+				SignatureHeader: "X-Signature",
+				TimestampHeader: "X-Signature-Timestamp",
+				KeyIDHeader:     "X-Signature-Key-Id",
+			},
 			RateLimit: RateLimit{
 				RequestsPerMinute: 60,
 				BurstSize:         10,
@@ -100,15 +461,22 @@ func Default() *Config {
 				MaxAttempts:       3,
 				BackoffMultiplier: 2,
 				InitialDelayMs:    1000,
+				MaxDelayMs:        30000,
+				MaxElapsedMs:      120000,
+			},
+			Cache: CacheConfig{
+				TTL: 900,
 			},
 		},
 		Credentials: CredentialsConfig{
-			Username: "",
+			Username:       "",
+			KeyringBackend: string(keyring.BackendAuto),
 		},
 		Preferences: PreferencesConfig{
-			CacheDir:   filepath.Join(configDir, "cache"),
-			HistoryDir: filepath.Join(configDir, "history"),
-			LogLevel:   "INFO",
+			CacheDir:      filepath.Join(configDir, "cache"),
+			HistoryDir:    filepath.Join(configDir, "history"),
+			LogLevel:      "INFO",
+			DefaultOutput: "table",
 		},
 		Performance: PerformanceConfig{
 			StreamThreshold:       1000,
@@ -119,6 +487,37 @@ func Default() *Config {
 			EnableLocking: true,
 			AtomicWrites:  true,
 			FormatVersion: "1.0",
+			Backend:       "file",
+			Integrity: IntegrityConfig{
+				Algorithm: "sha256",
+			},
+		},
+		Serve: ServeConfig{
+			Listen:     "127.0.0.1:8080",
+			ReadMaxAge: 300,
+		},
+		Daemon: DaemonConfig{
+			Interval:    3600,
+			LocalSocket: true,
+			Adaptive: AdaptiveIntervalConfig{
+				MinInterval: 300,
+				MaxInterval: 14400,
+				Backoff:     1.5,
+			},
+			Backoff: FailureBackoffConfig{
+				Enabled:      true,
+				InitialDelay: 60,
+				MaxDelay:     3600,
+				Multiplier:   2,
+				Jitter:       0.2,
+			},
+		},
+		Audit: AuditConfig{
+			RequiredContactRoles: []string{"abuse", "tech"},
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "http://localhost:4318",
+			ServiceName: "radb-client",
 		},
 		ConfigDir:  configDir,
 		ConfigFile: filepath.Join(configDir, DefaultConfigFile),
@@ -138,6 +537,8 @@ func Load() (*Config, error) {
 	// Environment variable support
 	viper.SetEnvPrefix("RADB")
 	viper.AutomaticEnv()
+	viper.BindEnv("preferences.default_output", "RADB_OUTPUT")
+	viper.BindEnv("credentials.keyring_backend", "RADB_KEYRING_BACKEND")
 
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
@@ -168,6 +569,15 @@ func (c *Config) Save() error {
 	viper.Set("preferences", c.Preferences)
 	viper.Set("performance", c.Performance)
 	viper.Set("state", c.State)
+	viper.Set("serve", c.Serve)
+	viper.Set("selftest", c.Selftest)
+	viper.Set("audit", c.Audit)
+	viper.Set("tracing", c.Tracing)
+	viper.Set("telemetry", c.Telemetry)
+	viper.Set("sandbox", c.Sandbox)
+	viper.Set("schedules", c.Schedules)
+	viper.Set("notifications", c.Notifications)
+	viper.Set("publish", c.Publish)
 
 	// Write config file
 	if err := viper.WriteConfigAs(c.ConfigFile); err != nil {
@@ -240,6 +650,81 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("api.timeout must be positive")
 	}
 
+	if c.API.PageSize < 0 || c.API.MaxResults < 0 {
+		return fmt.Errorf("api.page_size and api.max_results must not be negative")
+	}
+
+	if c.API.MaxResponseSize < 0 || c.API.MaxObjectSize < 0 {
+		return fmt.Errorf("api.max_response_size and api.max_object_size must not be negative")
+	}
+
+	if c.API.RateLimit.RequestsPerMinute <= 0 || c.API.RateLimit.BurstSize < 0 {
+		return fmt.Errorf("api.rate_limit.requests_per_minute must be positive and burst_size must not be negative")
+	}
+
+	for class, limit := range c.API.RateLimit.Endpoints {
+		if !slices.Contains(rateLimitEndpointClasses, class) {
+			return fmt.Errorf("api.rate_limit.endpoints.%s is not an endpoint class (read, list, search, write)", class)
+		}
+		if limit.RequestsPerMinute <= 0 || limit.BurstSize < 0 {
+			return fmt.Errorf("api.rate_limit.endpoints.%s.requests_per_minute must be positive and burst_size must not be negative", class)
+		}
+	}
+
+	for job, weight := range c.API.RateLimit.JobWeights {
+		if weight <= 0 {
+			return fmt.Errorf("api.rate_limit.job_weights.%s must be positive", job)
+		}
+	}
+
+	if c.API.Retry.MaxAttempts < 1 {
+		return fmt.Errorf("api.retry.max_attempts must be at least 1")
+	}
+
+	if c.API.Retry.InitialDelayMs < 0 || c.API.Retry.MaxDelayMs < 0 || c.API.Retry.MaxElapsedMs < 0 {
+		return fmt.Errorf("api.retry delays must not be negative")
+	}
+
+	if c.API.Cache.TTL < 0 {
+		return fmt.Errorf("api.cache.ttl must not be negative")
+	}
+
+	switch c.API.AuthMode {
+	case "", "basic":
+	case "hmac":
+		if c.API.Signing.KeyID == "" {
+			return fmt.Errorf("api.signing.key_id is required when api.auth_mode is hmac")
+		}
+	default:
+		return fmt.Errorf("api.auth_mode must be basic or hmac")
+	}
+
+	if c.API.Proxy.URL != "" {
+		proxyURL, err := url.Parse(c.API.Proxy.URL)
+		if err != nil {
+			return fmt.Errorf("api.proxy.url is invalid: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("api.proxy.url must use http, https, or socks5 scheme")
+		}
+	}
+
+	if (c.API.TLS.CertFile == "") != (c.API.TLS.KeyFile == "") {
+		return fmt.Errorf("api.tls.cert_file and api.tls.key_file must be set together")
+	}
+
+	switch c.API.TLS.MinVersion {
+	case "", "1.2", "1.3":
+	default:
+		return fmt.Errorf("api.tls.min_version must be 1.2 or 1.3")
+	}
+
+	if _, err := keyring.ParseBackend(c.Credentials.KeyringBackend); err != nil {
+		return fmt.Errorf("credentials.keyring_backend: %w", err)
+	}
+
 	if c.Preferences.CacheDir == "" {
 		return fmt.Errorf("preferences.cache_dir is required")
 	}
@@ -248,6 +733,451 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("preferences.history_dir is required")
 	}
 
+	switch c.Preferences.DefaultOutput {
+	case "", "table", "json", "yaml":
+	default:
+		return fmt.Errorf("preferences.default_output must be table, json, or yaml")
+	}
+
+	if c.Daemon.Interval < 0 {
+		return fmt.Errorf("daemon.interval must not be negative")
+	}
+
+	if adaptive := c.Daemon.Adaptive; adaptive.Enabled {
+		if adaptive.MinInterval <= 0 || adaptive.MaxInterval < adaptive.MinInterval {
+			return fmt.Errorf("daemon.adaptive.min_interval must be positive and at most max_interval")
+		}
+		if adaptive.Backoff < 1 {
+			return fmt.Errorf("daemon.adaptive.backoff must be at least 1")
+		}
+	}
+
+	if backoff := c.Daemon.Backoff; backoff.Enabled {
+		if backoff.InitialDelay <= 0 || backoff.MaxDelay < backoff.InitialDelay {
+			return fmt.Errorf("daemon.failure_backoff.initial_delay must be positive and at most max_delay")
+		}
+		if backoff.Multiplier < 1 {
+			return fmt.Errorf("daemon.failure_backoff.multiplier must be at least 1")
+		}
+		if backoff.Jitter < 0 || backoff.Jitter > 1 {
+			return fmt.Errorf("daemon.failure_backoff.jitter must be between 0 and 1")
+		}
+	}
+
+	for _, schedule := range []struct{ name, spec string }{
+		{"route_check", c.Schedules.RouteCheck},
+		{"contact_check", c.Schedules.ContactCheck},
+		{"cleanup", c.Schedules.Cleanup},
+	} {
+		if schedule.spec == "" {
+			continue
+		}
+		parsed, err := cron.Parse(schedule.spec)
+		if err != nil {
+			return fmt.Errorf("schedules.%s: %w", schedule.name, err)
+		}
+		if parsed.Next(time.Now()).IsZero() {
+			return fmt.Errorf("schedules.%s never runs", schedule.name)
+		}
+	}
+	if c.Schedules.RouteCheck != "" && c.Daemon.Adaptive.Enabled {
+		return fmt.Errorf("schedules.route_check and daemon.adaptive cannot both be set")
+	}
+
+	if err := c.Daemon.validateTargets(c.Notifications.Sinks); err != nil {
+		return err
+	}
+
+	if c.Daemon.MetricsListen != "" {
+		if _, _, err := net.SplitHostPort(c.Daemon.MetricsListen); err != nil {
+			return fmt.Errorf("daemon.metrics_listen must be a host:port address: %w", err)
+		}
+	}
+
+	if c.Tracing.Enabled {
+		endpoint, err := url.Parse(c.Tracing.Endpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("tracing.endpoint must be an http or https URL")
+		}
+	}
+
+	if err := c.Notifications.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Publish.validate(); err != nil {
+		return err
+	}
+
+	if err := c.State.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Audit.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Diff.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Sandbox.validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTargets checks that watch targets are named, select routes,
+// do not overlap in selection, and reference only defined sinks.
+func (d *DaemonConfig) validateTargets(sinks []SinkConfig) error {
+	names := make(map[string]bool)
+	selections := make(map[string]string)
+	for i, target := range d.Targets {
+		if target.Name == "" {
+			return fmt.Errorf("daemon.targets[%d].name is required", i)
+		}
+		if names[target.Name] {
+			return fmt.Errorf("daemon.targets: duplicate target %s", target.Name)
+		}
+		names[target.Name] = true
+
+		if target.Maintainer == "" && target.Origin == "" && len(target.Prefixes) == 0 {
+			return fmt.Errorf("daemon target %s: maintainer, origin, or prefixes is required", target.Name)
+		}
+		for _, prefix := range target.Prefixes {
+			if _, err := netip.ParsePrefix(strings.TrimSpace(prefix)); err != nil {
+				return fmt.Errorf("daemon target %s: invalid prefix %q: %w", target.Name, prefix, err)
+			}
+		}
+
+		selection := fmt.Sprint(target.Filters())
+		if other, ok := selections[selection]; ok {
+			return fmt.Errorf("daemon target %s selects the same routes as %s", target.Name, other)
+		}
+		selections[selection] = target.Name
+
+		if target.Schedule != "" {
+			schedule, err := cron.Parse(target.Schedule)
+			if err != nil {
+				return fmt.Errorf("daemon target %s: schedule: %w", target.Name, err)
+			}
+			if schedule.Next(time.Now()).IsZero() {
+				return fmt.Errorf("daemon target %s: schedule never runs", target.Name)
+			}
+		}
+		if target.Retention < 0 {
+			return fmt.Errorf("daemon target %s: retention must not be negative", target.Name)
+		}
+		for _, sink := range target.Sinks {
+			if !slices.ContainsFunc(sinks, func(s SinkConfig) bool { return s.Name == sink }) {
+				return fmt.Errorf("daemon target %s: unknown sink %s", target.Name, sink)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks the sandbox prefixes and, when sandbox mode is enabled,
+// that a maintainer is set. AS number ranges are checked when the sandbox
+// is created.
+func (s *SandboxConfig) validate() error {
+	if s.Enabled && s.Maintainer == "" {
+		return fmt.Errorf("sandbox.maintainer is required when sandbox mode is enabled")
+	}
+	for _, prefix := range s.Prefixes {
+		if _, err := netip.ParsePrefix(prefix); err != nil {
+			return fmt.Errorf("sandbox.prefixes: invalid prefix %q: %w", prefix, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that no field name is empty. Whether a field is compared
+// at all is checked when a diff is computed.
+func (d *DiffConfig) validate() error {
+	for i, field := range d.IgnoreFields {
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("diff.ignore_fields[%d] is empty", i)
+		}
+	}
+	for i, field := range d.UnorderedListFields {
+		if strings.TrimSpace(field) == "" {
+			return fmt.Errorf("diff.unordered_list_fields[%d] is empty", i)
+		}
+	}
+	return nil
+}
+
+// originPattern matches an origin ASN such as AS64500.
+var originPattern = regexp.MustCompile(`^(?i)AS[0-9]+$`)
+
+// validSeverity reports whether name is an alert rule severity.
+func validSeverity(name string) bool {
+	switch name {
+	case "none", "info", "warning", "error", "critical":
+		return true
+	}
+	return false
+}
+
+// validate checks that sinks are complete and that every sink referenced is defined.
+func (n *NotificationsConfig) validate() error {
+	sinks := make(map[string]bool)
+	for i, sink := range n.Sinks {
+		if sink.Name == "" {
+			return fmt.Errorf("notifications.sinks[%d].name is required", i)
+		}
+		if sinks[sink.Name] {
+			return fmt.Errorf("notifications.sinks: duplicate sink %s", sink.Name)
+		}
+		sinks[sink.Name] = true
+
+		switch sink.Type {
+		case "webhook", "slack", "teams":
+			u, err := url.Parse(sink.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("notifications sink %s: url must be an http or https URL", sink.Name)
+			}
+		case "email":
+			u, err := url.Parse(sink.URL)
+			if err != nil || (u.Scheme != "smtp" && u.Scheme != "smtps") || u.Hostname() == "" {
+				return fmt.Errorf("notifications sink %s: url must be an smtp or smtps URL", sink.Name)
+			}
+			if sink.From == "" {
+				return fmt.Errorf("notifications sink %s: from is required", sink.Name)
+			}
+			if len(sink.To) == 0 {
+				return fmt.Errorf("notifications sink %s: at least one to address is required", sink.Name)
+			}
+		case "pagerduty", "opsgenie":
+			if sink.Key == "" {
+				return fmt.Errorf("notifications sink %s: key is required", sink.Name)
+			}
+			if sink.URL != "" {
+				u, err := url.Parse(sink.URL)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("notifications sink %s: url must be an http or https URL", sink.Name)
+				}
+			}
+		default:
+			return fmt.Errorf("notifications sink %s: unsupported type %q (want webhook, slack, teams, email, pagerduty, or opsgenie)", sink.Name, sink.Type)
+		}
+		for _, prefix := range sink.Prefixes {
+			if _, err := netip.ParsePrefix(prefix); err != nil {
+				return fmt.Errorf("notifications sink %s: invalid prefix %q", sink.Name, prefix)
+			}
+		}
+		for _, objectType := range sink.ObjectTypes {
+			if objectType != "route" && objectType != "contact" {
+				return fmt.Errorf("notifications sink %s: unsupported object type %q (want route or contact)", sink.Name, objectType)
+			}
+		}
+		for _, changeType := range sink.ChangeTypes {
+			if changeType != "added" && changeType != "removed" && changeType != "modified" {
+				return fmt.Errorf("notifications sink %s: unsupported change type %q (want added, removed, or modified)", sink.Name, changeType)
+			}
+		}
+		if sink.MinSeverity != "" && (sink.MinSeverity == "none" || !validSeverity(sink.MinSeverity)) {
+			return fmt.Errorf("notifications sink %s: invalid min_severity %q (want info, warning, error, or critical)", sink.Name, sink.MinSeverity)
+		}
+	}
+
+	if n.DefaultSeverity != "" && !validSeverity(n.DefaultSeverity) {
+		return fmt.Errorf("notifications.default_severity %q is invalid (want none, info, warning, error, or critical)", n.DefaultSeverity)
+	}
+	for i, rule := range n.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if !validSeverity(rule.Severity) {
+			return fmt.Errorf("notifications rule %s: invalid severity %q (want none, info, warning, error, or critical)", name, rule.Severity)
+		}
+		for _, objectType := range rule.ObjectTypes {
+			if objectType != "route" && objectType != "contact" {
+				return fmt.Errorf("notifications rule %s: unsupported object type %q (want route or contact)", name, objectType)
+			}
+		}
+		for _, changeType := range rule.ChangeTypes {
+			if changeType != "added" && changeType != "removed" && changeType != "modified" {
+				return fmt.Errorf("notifications rule %s: unsupported change type %q (want added, removed, or modified)", name, changeType)
+			}
+		}
+		for _, prefix := range rule.Prefixes {
+			if _, err := netip.ParsePrefix(prefix); err != nil {
+				return fmt.Errorf("notifications rule %s: invalid prefix %q", name, prefix)
+			}
+		}
+		for _, origin := range rule.Origins {
+			if !originPattern.MatchString(origin) {
+				return fmt.Errorf("notifications rule %s: invalid origin %q (want an ASN such as AS64500)", name, origin)
+			}
+		}
+		for _, field := range append(append([]string{}, rule.Fields...), rule.OnlyFields...) {
+			if strings.TrimSpace(field) == "" {
+				return fmt.Errorf("notifications rule %s: empty field name", name)
+			}
+		}
+	}
+
+	for i, team := range n.Teams {
+		if team.Name == "" {
+			return fmt.Errorf("notifications.teams[%d].name is required", i)
+		}
+		if len(team.Maintainers) == 0 && len(team.Prefixes) == 0 {
+			return fmt.Errorf("notifications team %s: at least one of maintainers or prefixes is required", team.Name)
+		}
+		for _, prefix := range team.Prefixes {
+			if _, err := netip.ParsePrefix(prefix); err != nil {
+				return fmt.Errorf("notifications team %s: invalid prefix %q", team.Name, prefix)
+			}
+		}
+		if len(team.Sinks) == 0 {
+			return fmt.Errorf("notifications team %s: at least one sink is required", team.Name)
+		}
+		for _, sink := range team.Sinks {
+			if !sinks[sink] {
+				return fmt.Errorf("notifications team %s: unknown sink %s", team.Name, sink)
+			}
+		}
+	}
+
+	for _, sink := range n.DefaultSinks {
+		if !sinks[sink] {
+			return fmt.Errorf("notifications.default_sinks: unknown sink %s", sink)
+		}
+	}
+
+	if n.Retry.InitialDelay < 0 || n.Retry.MaxDelay < 0 || n.Retry.MaxAttempts < 0 {
+		return fmt.Errorf("notifications.retry: values must not be negative")
+	}
+
+	return nil
+}
+
+// validate checks that publishers are named uniquely and have a URL their
+// type can use.
+func (p *PublishConfig) validate() error {
+	names := make(map[string]bool)
+	for i, publisher := range p.Publishers {
+		if publisher.Name == "" {
+			return fmt.Errorf("publish.publishers[%d].name is required", i)
+		}
+		if names[publisher.Name] {
+			return fmt.Errorf("publish.publishers: duplicate publisher %s", publisher.Name)
+		}
+		names[publisher.Name] = true
+
+		u, err := url.Parse(publisher.URL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("publisher %s: url is required", publisher.Name)
+		}
+		switch publisher.Type {
+		case "nats":
+			if u.Scheme != "nats" && u.Scheme != "tls" {
+				return fmt.Errorf("publisher %s: url must be a nats:// or tls:// URL", publisher.Name)
+			}
+		case "kafka", "rabbitmq-management":
+			if u.Scheme != "http" && u.Scheme != "https" {
+				return fmt.Errorf("publisher %s: url must be an http or https URL", publisher.Name)
+			}
+		case "amqp":
+			return fmt.Errorf("publisher %s: type amqp is not supported; rabbitmq-management publishes through the RabbitMQ management HTTP API", publisher.Name)
+		default:
+			return fmt.Errorf("publisher %s: unsupported type %q (want nats, kafka, or rabbitmq-management)", publisher.Name, publisher.Type)
+		}
+	}
+
+	return nil
+}
+
+// validate checks that assertions are named uniquely, parse, and require
+// something, and that saved queries are named uniquely and parse.
+func (a *AuditConfig) validate() error {
+	names := make(map[string]bool)
+	for i, assertion := range a.Assertions {
+		if assertion.Name == "" {
+			return fmt.Errorf("audit.assertions[%d].name is required", i)
+		}
+		if names[assertion.Name] {
+			return fmt.Errorf("audit.assertions: duplicate assertion %s", assertion.Name)
+		}
+		names[assertion.Name] = true
+
+		if assertion.Match.Prefix != "" {
+			if _, err := netip.ParsePrefix(assertion.Match.Prefix); err != nil {
+				return fmt.Errorf("assertion %s: invalid prefix %q", assertion.Name, assertion.Match.Prefix)
+			}
+		}
+		if assertion.Require.Descr != "" {
+			if _, err := regexp.Compile(assertion.Require.Descr); err != nil {
+				return fmt.Errorf("assertion %s: invalid descr pattern: %w", assertion.Name, err)
+			}
+		}
+		require := assertion.Require
+		if len(require.MntBy) == 0 && require.Origin == "" && require.Descr == "" {
+			return fmt.Errorf("assertion %s: at least one of require.mnt_by, require.origin, or require.descr is needed", assertion.Name)
+		}
+	}
+
+	queries := make(map[string]bool)
+	for i, query := range a.Queries {
+		if query.Name == "" {
+			return fmt.Errorf("audit.queries[%d].name is required", i)
+		}
+		if queries[query.Name] {
+			return fmt.Errorf("audit.queries: duplicate query %s", query.Name)
+		}
+		queries[query.Name] = true
+
+		if query.Object != "route" && query.Object != "contact" {
+			return fmt.Errorf("query %s: unsupported object %q (want route or contact)", query.Name, query.Object)
+		}
+		if query.Prefix != "" {
+			if _, err := netip.ParsePrefix(query.Prefix); err != nil {
+				return fmt.Errorf("query %s: invalid prefix %q", query.Name, query.Prefix)
+			}
+		}
+		for field, pattern := range map[string]string{"descr": query.Descr, "email": query.Email} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("query %s: invalid %s pattern: %w", query.Name, field, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks the state backend and its object store settings.
+func (s *StateConfig) validate() error {
+	switch s.Integrity.Algorithm {
+	case "", "sha256", "blake3":
+	default:
+		return fmt.Errorf("state.integrity.algorithm: unsupported algorithm %q (want sha256 or blake3)", s.Integrity.Algorithm)
+	}
+
+	switch s.Backend {
+	case "", "file":
+		return nil
+	case "s3", "gcs", "minio":
+	default:
+		return fmt.Errorf("state.backend: unsupported backend %q (want file, s3, gcs, or minio)", s.Backend)
+	}
+
+	if s.ObjectStore.Bucket == "" {
+		return fmt.Errorf("state.object_store.bucket is required for the %s backend", s.Backend)
+	}
+	if s.Backend == "minio" && s.ObjectStore.Endpoint == "" {
+		return fmt.Errorf("state.object_store.endpoint is required for the minio backend")
+	}
+	if s.ObjectStore.Endpoint != "" {
+		u, err := url.Parse(s.ObjectStore.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("state.object_store.endpoint must be an http or https URL")
+		}
+	}
+
 	return nil
 }
 
@@ -276,7 +1206,7 @@ func LoadCredentials() (*Credentials, error) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.WarnLevel)
 
-	credMgr, err := NewCredentialManager(cfg.ConfigDir, logger)
+	credMgr, err := NewCredentialManagerWithBackend(cfg.ConfigDir, keyring.Backend(cfg.Credentials.KeyringBackend), logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize credential manager: %w", err)
 	}
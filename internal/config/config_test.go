@@ -2,7 +2,6 @@ package config
 
 import (
 	"os"
-	"path/filepath"
 	"testing"
 )
 
@@ -58,6 +57,440 @@ func TestValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "hmac auth",
+			modify: func(c *Config) {
+				c.API.AuthMode = "hmac"
+				c.API.Signing.KeyID = "mirror-1"
+			},
+			wantErr: false,
+		},
+		{
+			name: "hmac auth without key ID",
+			modify: func(c *Config) {
+				c.API.AuthMode = "hmac"
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported auth mode",
+			modify: func(c *Config) {
+				c.API.AuthMode = "oauth"
+			},
+			wantErr: true,
+		},
+		{
+			name: "socks5 proxy",
+			modify: func(c *Config) {
+				c.API.Proxy.URL = "socks5://127.0.0.1:1080"
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported proxy scheme",
+			modify: func(c *Config) {
+				c.API.Proxy.URL = "ftp://proxy.example.com"
+			},
+			wantErr: true,
+		},
+		{
+			name: "write rate limit",
+			modify: func(c *Config) {
+				c.API.RateLimit.Endpoints = map[string]EndpointRateLimit{"write": {RequestsPerMinute: 10}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown rate limit endpoint class",
+			modify: func(c *Config) {
+				c.API.RateLimit.Endpoints = map[string]EndpointRateLimit{"delete": {RequestsPerMinute: 10}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "job weights",
+			modify: func(c *Config) {
+				c.API.RateLimit.JobWeights = map[string]float64{"check": 1, "webhook": 4}
+			},
+			wantErr: false,
+		},
+		{
+			name: "zero job weight",
+			modify: func(c *Config) {
+				c.API.RateLimit.JobWeights = map[string]float64{"check": 0}
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero rate limit",
+			modify: func(c *Config) {
+				c.API.RateLimit.RequestsPerMinute = 0
+			},
+			wantErr: true,
+		},
+		{
+			name: "notification routing",
+			modify: func(c *Config) {
+				c.Notifications = NotificationsConfig{
+					Sinks:        []SinkConfig{{Name: "noc", Type: "webhook", URL: "https://hooks.example.com/noc"}},
+					Teams:        []TeamConfig{{Name: "noc", Maintainers: []string{"MAINT-NOC"}, Prefixes: []string{"192.0.2.0/23"}, Sinks: []string{"noc"}}},
+					DefaultSinks: []string{"noc"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "email notification sink",
+			modify: func(c *Config) {
+				c.Notifications = NotificationsConfig{
+					Sinks:        []SinkConfig{{Name: "noc", Type: "email", URL: "smtp://mail.example.com:587", From: "radb@example.com", To: []string{"noc@example.com"}}},
+					DefaultSinks: []string{"noc"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "email notification sink without recipients",
+			modify: func(c *Config) {
+				c.Notifications = NotificationsConfig{
+					Sinks: []SinkConfig{{Name: "noc", Type: "email", URL: "smtp://mail.example.com:587", From: "radb@example.com"}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "notification team with unknown sink",
+			modify: func(c *Config) {
+				c.Notifications = NotificationsConfig{
+					Teams: []TeamConfig{{Name: "noc", Maintainers: []string{"MAINT-NOC"}, Sinks: []string{"noc"}}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "notification team with invalid prefix",
+			modify: func(c *Config) {
+				c.Notifications = NotificationsConfig{
+					Sinks: []SinkConfig{{Name: "noc", Type: "webhook", URL: "https://hooks.example.com/noc"}},
+					Teams: []TeamConfig{{Name: "noc", Prefixes: []string{"192.0.2.0"}, Sinks: []string{"noc"}}},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "alert rule with invalid severity",
+			modify: func(c *Config) {
+				c.Notifications.Rules = []AlertRuleConfig{{Name: "removals", ChangeTypes: []string{"removed"}, Severity: "urgent"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "alert rule with invalid origin",
+			modify: func(c *Config) {
+				c.Notifications.Rules = []AlertRuleConfig{{Name: "anycast", Origins: []string{"64500x"}, Severity: "critical"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid alert rules",
+			modify: func(c *Config) {
+				c.Notifications = NotificationsConfig{
+					Sinks: []SinkConfig{{Name: "pager", Type: "pagerduty", Key: "key", MinSeverity: "critical"}},
+					Rules: []AlertRuleConfig{
+						{Name: "remarks", ChangeTypes: []string{"modified"}, OnlyFields: []string{"remarks"}, Severity: "none"},
+						{Name: "production", Prefixes: []string{"192.0.2.0/24"}, Origins: []string{"AS64500"}, Severity: "critical"},
+					},
+					DefaultSeverity: "info",
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "sink with invalid min severity",
+			modify: func(c *Config) {
+				c.Notifications.Sinks = []SinkConfig{{Name: "pager", Type: "pagerduty", Key: "key", MinSeverity: "none"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative cache ttl",
+			modify: func(c *Config) {
+				c.API.Cache.TTL = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "adaptive interval bounds reversed",
+			modify: func(c *Config) {
+				c.Daemon.Adaptive.Enabled = true
+				c.Daemon.Adaptive.MinInterval = 3600
+				c.Daemon.Adaptive.MaxInterval = 600
+			},
+			wantErr: true,
+		},
+		{
+			name: "adaptive backoff below one",
+			modify: func(c *Config) {
+				c.Daemon.Adaptive.Enabled = true
+				c.Daemon.Adaptive.Backoff = 0.5
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure backoff delays reversed",
+			modify: func(c *Config) {
+				c.Daemon.Backoff.InitialDelay = 600
+				c.Daemon.Backoff.MaxDelay = 60
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure backoff jitter above one",
+			modify: func(c *Config) {
+				c.Daemon.Backoff.Jitter = 1.5
+			},
+			wantErr: true,
+		},
+		{
+			name: "failure backoff disabled ignores its settings",
+			modify: func(c *Config) {
+				c.Daemon.Backoff.Enabled = false
+				c.Daemon.Backoff.Multiplier = 0
+			},
+			wantErr: false,
+		},
+		{
+			name: "task schedules",
+			modify: func(c *Config) {
+				c.Schedules.RouteCheck = "*/15 * * * *"
+				c.Schedules.ContactCheck = "0 2 * * *"
+				c.Schedules.Cleanup = "@weekly"
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid task schedule",
+			modify: func(c *Config) {
+				c.Schedules.Cleanup = "0 3 * *"
+			},
+			wantErr: true,
+		},
+		{
+			name: "route check schedule with adaptive interval",
+			modify: func(c *Config) {
+				c.Schedules.RouteCheck = "@hourly"
+				c.Daemon.Adaptive.Enabled = true
+			},
+			wantErr: true,
+		},
+		{
+			name: "watch targets",
+			modify: func(c *Config) {
+				c.Notifications.Sinks = []SinkConfig{{Name: "peering", Type: "webhook", URL: "https://hooks.example.com/peering"}}
+				c.Daemon.Targets = []WatchTargetConfig{
+					{Name: "customers", Maintainer: "MAINT-CUSTOMERS", Schedule: "*/10 * * * *", Retention: 100},
+					{Name: "anycast", Origin: "AS64500", Prefixes: []string{"192.0.2.0/24", "2001:db8::/32"}, Sinks: []string{"peering"}},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "watch target without filters",
+			modify: func(c *Config) {
+				c.Daemon.Targets = []WatchTargetConfig{{Name: "everything"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "watch targets selecting the same routes",
+			modify: func(c *Config) {
+				c.Daemon.Targets = []WatchTargetConfig{
+					{Name: "customers", Maintainer: "MAINT-CUSTOMERS"},
+					{Name: "clients", Maintainer: "MAINT-CUSTOMERS"},
+				}
+			},
+			wantErr: true,
+		},
+		{
+			name: "watch target with unknown sink",
+			modify: func(c *Config) {
+				c.Daemon.Targets = []WatchTargetConfig{{Name: "customers", Maintainer: "MAINT-CUSTOMERS", Sinks: []string{"noc"}}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative notification retry delay",
+			modify: func(c *Config) {
+				c.Notifications.Retry.InitialDelay = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "event publishers",
+			modify: func(c *Config) {
+				c.Publish.Publishers = []PublisherConfig{
+					{Name: "bus", Type: "nats", URL: "nats://token@nats.example.com:4222"},
+					{Name: "billing", Type: "kafka", URL: "https://kafka-rest.example.com", Topic: "irr-changes"},
+					{Name: "security", Type: "rabbitmq-management", URL: "https://rabbitmq.example.com:15672"},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "amqp publisher",
+			modify: func(c *Config) {
+				c.Publish.Publishers = []PublisherConfig{{Name: "security", Type: "amqp", URL: "https://rabbitmq.example.com:15672"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "kafka publisher with nats URL",
+			modify: func(c *Config) {
+				c.Publish.Publishers = []PublisherConfig{{Name: "billing", Type: "kafka", URL: "nats://nats.example.com"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "s3 state backend",
+			modify: func(c *Config) {
+				c.State.Backend = "s3"
+				c.State.ObjectStore = ObjectStoreConfig{Bucket: "radb-snapshots", Region: "eu-west-1"}
+			},
+			wantErr: false,
+		},
+		{
+			name: "s3 state backend without bucket",
+			modify: func(c *Config) {
+				c.State.Backend = "s3"
+			},
+			wantErr: true,
+		},
+		{
+			name: "minio state backend without endpoint",
+			modify: func(c *Config) {
+				c.State.Backend = "minio"
+				c.State.ObjectStore.Bucket = "radb"
+			},
+			wantErr: true,
+		},
+		{
+			name: "blake3 integrity",
+			modify: func(c *Config) {
+				c.State.Integrity = IntegrityConfig{Algorithm: "blake3", PerObject: true}
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown integrity algorithm",
+			modify: func(c *Config) {
+				c.State.Integrity.Algorithm = "md5"
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown state backend",
+			modify: func(c *Config) {
+				c.State.Backend = "azure"
+			},
+			wantErr: true,
+		},
+		{
+			name: "ownership assertion",
+			modify: func(c *Config) {
+				c.Audit.Assertions = []AssertionConfig{{
+					Name:    "as64500",
+					Match:   AssertionMatchConfig{Origin: "AS64500"},
+					Require: AssertionRequireConfig{MntBy: []string{"MAINT-X"}, Descr: "^ACME"},
+				}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "assertion with invalid descr pattern",
+			modify: func(c *Config) {
+				c.Audit.Assertions = []AssertionConfig{{Name: "bad", Require: AssertionRequireConfig{Descr: "(unclosed"}}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "assertion without requirements",
+			modify: func(c *Config) {
+				c.Audit.Assertions = []AssertionConfig{{Name: "empty", Match: AssertionMatchConfig{Origin: "AS64500"}}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "client cert without key",
+			modify: func(c *Config) {
+				c.API.TLS.CertFile = "/etc/radb/client.pem"
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported TLS version",
+			modify: func(c *Config) {
+				c.API.TLS.MinVersion = "1.0"
+			},
+			wantErr: true,
+		},
+		{
+			name: "file keyring backend",
+			modify: func(c *Config) {
+				c.Credentials.KeyringBackend = "file"
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown keyring backend",
+			modify: func(c *Config) {
+				c.Credentials.KeyringBackend = "kwallet"
+			},
+			wantErr: true,
+		},
+		{
+			name: "json default output",
+			modify: func(c *Config) {
+				c.Preferences.DefaultOutput = "json"
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported default output",
+			modify: func(c *Config) {
+				c.Preferences.DefaultOutput = "xml"
+			},
+			wantErr: true,
+		},
+		{
+			name: "diff options",
+			modify: func(c *Config) {
+				c.Diff.IgnoreFields = []string{"remarks"}
+				c.Diff.UnorderedListFields = []string{"descr", "mnt-by"}
+				c.Diff.IgnoreCase = true
+			},
+			wantErr: false,
+		},
+		{
+			name: "saved audit query",
+			modify: func(c *Config) {
+				c.Audit.Queries = []QueryConfig{{Name: "legacy", Object: "route", Origin: "AS64499", Missing: []string{"remarks"}}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "saved audit query with unsupported object",
+			modify: func(c *Config) {
+				c.Audit.Queries = []QueryConfig{{Name: "legacy", Object: "aut-num"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty diff field",
+			modify: func(c *Config) {
+				c.Diff.UnorderedListFields = []string{" "}
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -23,9 +23,15 @@ type CredentialManager struct {
 
 // NewCredentialManager creates a new credential manager.
 func NewCredentialManager(configDir string, logger *logrus.Logger) (*CredentialManager, error) {
+	return NewCredentialManagerWithBackend(configDir, keyring.BackendAuto, logger)
+}
+
+// NewCredentialManagerWithBackend creates a credential manager that stores
+// credentials in the given keyring backend.
+func NewCredentialManagerWithBackend(configDir string, backend keyring.Backend, logger *logrus.Logger) (*CredentialManager, error) {
 	fallbackPath := filepath.Join(configDir, "credentials.enc")
 
-	store, err := keyring.NewStore(logger, fallbackPath)
+	store, err := keyring.NewStoreWithBackend(logger, fallbackPath, backend)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize credential store: %w", err)
 	}
@@ -90,6 +96,24 @@ func (cm *CredentialManager) GetCryptedPassword(username string) (string, error)
 	return cryptedPassword, nil
 }
 
+// SetSigningKey stores the HMAC request signing key for a key ID.
+func (cm *CredentialManager) SetSigningKey(keyID, key string) error {
+	if err := cm.store.Set(keyID, "signing_key", key); err != nil {
+		return fmt.Errorf("failed to store signing key: %w", err)
+	}
+	cm.logger.Debugf("Stored signing key %s", keyID)
+	return nil
+}
+
+// GetSigningKey retrieves the HMAC request signing key for a key ID.
+func (cm *CredentialManager) GetSigningKey(keyID string) (string, error) {
+	key, err := cm.store.Get(keyID, "signing_key")
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve signing key: %w", err)
+	}
+	return key, nil
+}
+
 // DeleteAll removes all credentials for a user.
 func (cm *CredentialManager) DeleteAll(username string) error {
 	if err := cm.store.DeleteAll(username); err != nil {
@@ -130,6 +154,11 @@ func (cm *CredentialManager) SaveCredentials(creds *Credentials) error {
 	return nil
 }
 
+// Backend describes where credentials are stored.
+func (cm *CredentialManager) Backend() string {
+	return cm.store.Backend()
+}
+
 // Close closes the credential manager and releases resources.
 func (cm *CredentialManager) Close() error {
 	return cm.store.Close()
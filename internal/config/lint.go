@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RADbRequestsPerMinute is the sustained request rate RADb asks automated
+// clients to stay within.
+const RADbRequestsPerMinute = 60
+
+// Severity ranks lint findings.
+type Severity string
+
+const (
+	// SeverityError marks a configuration that is invalid or will lose data
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks a configuration that works but is risky
+	SeverityWarning Severity = "warning"
+)
+
+// LintFinding is a risky or invalid setting found by Lint.
+type LintFinding struct {
+	Severity    Severity `json:"severity"`
+	Key         string   `json:"key"` // Setting the finding is about, e.g. api.rate_limit
+	Message     string   `json:"message"`
+	Explanation string   `json:"explanation"`
+}
+
+// Lint validates the configuration and then flags valid but dangerous
+// combinations of settings. daemon reports whether a daemon is known to
+// use this configuration. Errors are listed before warnings.
+func (c *Config) Lint(daemon bool) []LintFinding {
+	var findings []LintFinding
+	add := func(severity Severity, key, message, explanation string) {
+		findings = append(findings, LintFinding{Severity: severity, Key: key, Message: message, Explanation: explanation})
+	}
+
+	if err := c.Validate(); err != nil {
+		add(SeverityError, "", err.Error(), "The configuration is rejected as invalid.")
+	}
+
+	c.lintRateLimits(add)
+	c.lintState(daemon, add)
+	c.lintDirectories(add)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity == SeverityError && findings[j].Severity != SeverityError
+	})
+	return findings
+}
+
+// HasErrors reports whether any finding is an error.
+func HasErrors(findings []LintFinding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type lintFunc func(severity Severity, key, message, explanation string)
+
+// lintRateLimits flags request rates above RADb guidance.
+func (c *Config) lintRateLimits(add lintFunc) {
+	limit := c.API.RateLimit
+	if limit.RequestsPerMinute > RADbRequestsPerMinute {
+		add(SeverityWarning, "api.rate_limit.requests_per_minute",
+			fmt.Sprintf("%d requests per minute exceeds RADb guidance of %d", limit.RequestsPerMinute, RADbRequestsPerMinute),
+			"RADb may throttle or block clients that exceed its guidance, failing every command until the block lifts.")
+	}
+	if limit.BurstSize > limit.RequestsPerMinute && limit.RequestsPerMinute > 0 {
+		add(SeverityWarning, "api.rate_limit.burst_size",
+			fmt.Sprintf("burst size %d is larger than the per-minute rate %d", limit.BurstSize, limit.RequestsPerMinute),
+			"A burst larger than a minute's budget lets bulk commands send more than a minute's requests at once.")
+	}
+
+	classes := make([]string, 0, len(limit.Endpoints))
+	for class := range limit.Endpoints {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		if rate := limit.Endpoints[class].RequestsPerMinute; rate > RADbRequestsPerMinute {
+			add(SeverityWarning, "api.rate_limit.endpoints."+class+".requests_per_minute",
+				fmt.Sprintf("%d requests per minute exceeds RADb guidance of %d", rate, RADbRequestsPerMinute),
+				"Endpoint overrides replace the shared limit, so this class alone can exceed RADb guidance.")
+		}
+	}
+}
+
+// lintState flags state settings that risk corrupting snapshots.
+func (c *Config) lintState(daemon bool, add lintFunc) {
+	// The daemon-only features imply a daemon even when none has run yet
+	daemon = daemon || len(c.Notifications.Sinks) > 0 || len(c.Publish.Publishers) > 0 || len(c.Audit.Assertions) > 0
+
+	if !c.State.EnableLocking {
+		severity, explanation := SeverityWarning, "Concurrent commands may interleave writes to the state directory."
+		if daemon {
+			severity = SeverityError
+			explanation = "The daemon writes snapshots on its own schedule, so commands run alongside it can corrupt or lose snapshots."
+		}
+		add(severity, "state.enable_locking", "state locking is disabled", explanation)
+	}
+
+	if !c.State.AtomicWrites {
+		add(SeverityWarning, "state.atomic_writes", "atomic writes are disabled",
+			"A crash or full disk during a write can leave a truncated snapshot behind.")
+	}
+}
+
+// lintDirectories flags cache and history directories that overlap or that
+// other users can read.
+func (c *Config) lintDirectories(add lintFunc) {
+	cache := filepath.Clean(c.Preferences.CacheDir)
+	history := filepath.Clean(c.Preferences.HistoryDir)
+	if c.Preferences.CacheDir != "" && c.Preferences.HistoryDir != "" {
+		if cache == history {
+			add(SeverityError, "preferences.history_dir", "cache and history point at the same directory",
+				"Snapshot cleanup treats every snapshot file in the cache as its own and can delete history, and history files can be mistaken for snapshots.")
+		} else if within(cache, history) || within(history, cache) {
+			add(SeverityWarning, "preferences.history_dir", "cache and history directories are nested",
+				"Tools that clear one directory, such as a cache reset, also remove the other.")
+		}
+	}
+
+	for _, dir := range []struct{ key, path string }{
+		{"preferences.cache_dir", c.Preferences.CacheDir},
+		{"preferences.history_dir", c.Preferences.HistoryDir},
+	} {
+		if dir.path == "" || (dir.key == "preferences.history_dir" && filepath.Clean(dir.path) == cache) {
+			continue
+		}
+		info, err := os.Stat(dir.path)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		if info.Mode().Perm()&0007 != 0 {
+			add(SeverityWarning, dir.key,
+				fmt.Sprintf("%s is accessible to all users (mode %04o)", dir.path, info.Mode().Perm()),
+				"Snapshots and history hold the full contents of your registry objects, including contact details. Restrict it with chmod 700.")
+		}
+	}
+}
+
+// within reports whether path lies inside dir.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
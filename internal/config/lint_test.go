@@ -0,0 +1,107 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	// lintedConfig returns defaults with private directories under a temp dir
+	lintedConfig := func(t *testing.T) *Config {
+		dir := t.TempDir()
+		cfg := Default()
+		cfg.Preferences.CacheDir = filepath.Join(dir, "cache")
+		cfg.Preferences.HistoryDir = filepath.Join(dir, "history")
+		for _, path := range []string{cfg.Preferences.CacheDir, cfg.Preferences.HistoryDir} {
+			if err := os.Mkdir(path, 0700); err != nil {
+				t.Fatalf("Mkdir() failed: %v", err)
+			}
+		}
+		return cfg
+	}
+
+	// severities returns the severity of each finding by key
+	severities := func(findings []LintFinding) map[string]Severity {
+		bySetting := make(map[string]Severity)
+		for _, finding := range findings {
+			bySetting[finding.Key] = finding.Severity
+		}
+		return bySetting
+	}
+
+	t.Run("DefaultsAreClean", func(t *testing.T) {
+		if findings := lintedConfig(t).Lint(false); len(findings) != 0 {
+			t.Errorf("Expected no findings for the defaults, got %+v", findings)
+		}
+	})
+
+	t.Run("RateAboveGuidance", func(t *testing.T) {
+		cfg := lintedConfig(t)
+		cfg.API.RateLimit.RequestsPerMinute = 600
+		cfg.API.RateLimit.Endpoints = map[string]EndpointRateLimit{"search": {RequestsPerMinute: 120}}
+
+		got := severities(cfg.Lint(false))
+		if got["api.rate_limit.requests_per_minute"] != SeverityWarning {
+			t.Errorf("Expected a warning for the shared rate, got %v", got)
+		}
+		if got["api.rate_limit.endpoints.search.requests_per_minute"] != SeverityWarning {
+			t.Errorf("Expected a warning for the search override, got %v", got)
+		}
+	})
+
+	t.Run("LockingDisabledWithDaemon", func(t *testing.T) {
+		cfg := lintedConfig(t)
+		cfg.State.EnableLocking = false
+
+		if got := severities(cfg.Lint(false))["state.enable_locking"]; got != SeverityWarning {
+			t.Errorf("Expected a warning without a daemon, got %q", got)
+		}
+		findings := cfg.Lint(true)
+		if got := severities(findings)["state.enable_locking"]; got != SeverityError {
+			t.Errorf("Expected an error with a daemon, got %q", got)
+		}
+		if !HasErrors(findings) {
+			t.Error("Expected HasErrors() to report the error")
+		}
+
+		cfg.Notifications.Sinks = []SinkConfig{{Name: "noc", Type: "webhook", URL: "https://hooks.example.com/noc"}}
+		if got := severities(cfg.Lint(false))["state.enable_locking"]; got != SeverityError {
+			t.Errorf("Expected notification sinks to imply a daemon, got %q", got)
+		}
+	})
+
+	t.Run("SharedDirectories", func(t *testing.T) {
+		cfg := lintedConfig(t)
+		cfg.Preferences.HistoryDir = cfg.Preferences.CacheDir + string(filepath.Separator)
+		if got := severities(cfg.Lint(false))["preferences.history_dir"]; got != SeverityError {
+			t.Errorf("Expected an error for a shared directory, got %q", got)
+		}
+
+		cfg.Preferences.HistoryDir = filepath.Join(cfg.Preferences.CacheDir, "history")
+		if got := severities(cfg.Lint(false))["preferences.history_dir"]; got != SeverityWarning {
+			t.Errorf("Expected a warning for nested directories, got %q", got)
+		}
+	})
+
+	t.Run("WorldReadableStateDir", func(t *testing.T) {
+		cfg := lintedConfig(t)
+		if err := os.Chmod(cfg.Preferences.CacheDir, 0755); err != nil {
+			t.Fatalf("Chmod() failed: %v", err)
+		}
+		if got := severities(cfg.Lint(false))["preferences.cache_dir"]; got != SeverityWarning {
+			t.Errorf("Expected a warning for a world-readable cache, got %q", got)
+		}
+	})
+
+	t.Run("InvalidConfigErrorsFirst", func(t *testing.T) {
+		cfg := lintedConfig(t)
+		cfg.API.RateLimit.RequestsPerMinute = 600
+		cfg.API.Source = ""
+
+		findings := cfg.Lint(false)
+		if len(findings) < 2 || findings[0].Severity != SeverityError {
+			t.Errorf("Expected the validation error first, got %+v", findings)
+		}
+	})
+}
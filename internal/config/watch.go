@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// File returns the path of the config file Load read, or "" when no file
+// was found and defaults are in use.
+func File() string {
+	return viper.ConfigFileUsed()
+}
+
+// Watch reports changes to the config file at path on the returned
+// channel until stop is called. Writes that follow each other within
+// settle are reported once, after the last of them, so a reload never sees
+// a half-written file. The file's directory is watched, so editors that
+// save by replacing the file are followed.
+func Watch(path string, settle time.Duration, logger *logrus.Logger) (changes <-chan struct{}, stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+	path = filepath.Clean(path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+
+	ch := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		timer := time.NewTimer(settle)
+		timer.Stop()
+		defer timer.Stop()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || !event.Has(fsnotify.Write|fsnotify.Create) {
+					continue
+				}
+				timer.Reset(settle)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warnf("Config file watch error: %v", err)
+
+			case <-timer.C:
+				select {
+				case ch <- struct{}{}:
+				default:
+					// A change is already waiting to be picked up
+				}
+
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			close(done)
+			watcher.Close()
+		})
+	}, nil
+}
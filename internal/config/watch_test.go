@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("api: {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, stop, err := Watch(path, 50*time.Millisecond, logrus.New())
+	if err != nil {
+		t.Fatalf("Watch() failed: %v", err)
+	}
+	defer stop()
+
+	// Other files in the directory are ignored
+	if err := os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x: 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+		t.Fatal("Watch reported a change to another file")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// A burst of writes is reported once
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(path, []byte("api: {timeout: 30}\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not report the change")
+	}
+	select {
+	case <-changes:
+		t.Fatal("Watch reported a burst of writes more than once")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	// Replacing the file by renaming is followed
+	tmp := filepath.Join(dir, "config.yaml.tmp")
+	if err := os.WriteFile(tmp, []byte("api: {timeout: 60}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not report the replaced file")
+	}
+}
@@ -0,0 +1,323 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/pkg/grpcwire"
+	"github.com/sirupsen/logrus"
+)
+
+// GRPCService is the path prefix of the DaemonState service's methods, as
+// defined in proto/radb/daemon/v1/daemon.proto.
+const GRPCService = "/radb.daemon.v1.DaemonState/"
+
+// watchBuffer is the number of events a Watch call may fall behind by
+// before events are dropped.
+const watchBuffer = 64
+
+// GRPCHandler serves the DaemonState gRPC service: snapshot listings,
+// snapshots, diffs, and history from local state, and a stream of the
+// runner's events. It must be served over HTTP/2. Callers authenticate with
+// "authorization: Bearer <secret>" metadata.
+type GRPCHandler struct {
+	runner *Runner
+	secret []byte
+	logger *logrus.Logger
+	server *grpcwire.Server
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewGRPCHandler creates a gRPC handler authenticated by secret.
+func NewGRPCHandler(runner *Runner, secret string, logger *logrus.Logger) *GRPCHandler {
+	h := &GRPCHandler{
+		runner: runner,
+		secret: []byte(secret),
+		logger: logger,
+		server: grpcwire.NewServer(),
+		done:   make(chan struct{}),
+	}
+	h.server.HandleStream(GRPCService+"ListSnapshots", h.listSnapshots)
+	h.server.HandleUnary(GRPCService+"GetSnapshot", h.getSnapshot)
+	h.server.HandleUnary(GRPCService+"Diff", h.diff)
+	h.server.HandleStream(GRPCService+"History", h.history)
+	h.server.HandleStream(GRPCService+"Watch", h.watch)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *GRPCHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorized(h.secret, r, nil) {
+		h.logger.Warnf("Rejected unauthenticated gRPC call from %s", r.RemoteAddr)
+		grpcwire.WriteStatus(w, grpcwire.Errorf(grpcwire.Unauthenticated, "invalid or missing credentials"))
+		return
+	}
+	h.server.ServeHTTP(w, r)
+}
+
+// Close ends open Watch streams, which otherwise last until the client
+// cancels them. Call it before shutting down the HTTP server.
+func (h *GRPCHandler) Close() {
+	h.once.Do(func() { close(h.done) })
+}
+
+func (h *GRPCHandler) listSnapshots(ctx context.Context, req []byte, send func([]byte) error) error {
+	fields, err := grpcwire.Parse(req)
+	if err != nil {
+		return grpcwire.Errorf(grpcwire.InvalidArgument, "invalid request: %v", err)
+	}
+
+	var query state.SnapshotQuery
+	for _, field := range fields {
+		switch field.Number {
+		case 1:
+			query.Type = models.SnapshotType(field.String())
+		case 2:
+			query.Scope = field.String()
+		case 3:
+			query.Tags = append(query.Tags, field.String())
+		case 4:
+			query.Since, err = grpcwire.ParseTimestamp(field.Bytes)
+		case 5:
+			query.Until, err = grpcwire.ParseTimestamp(field.Bytes)
+		case 6:
+			query.Limit = int(field.Int())
+		}
+		if err != nil {
+			return grpcwire.Errorf(grpcwire.InvalidArgument, "invalid timestamp: %v", err)
+		}
+	}
+
+	snapshots, err := h.runner.stateMgr.QuerySnapshots(ctx, query)
+	if err != nil {
+		return grpcwire.Errorf(grpcwire.InvalidArgument, "%v", err)
+	}
+	for i := range snapshots {
+		if err := send(encodeSnapshotInfo(&snapshots[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *GRPCHandler) getSnapshot(ctx context.Context, req []byte) ([]byte, error) {
+	fields, err := grpcwire.Parse(req)
+	if err != nil {
+		return nil, grpcwire.Errorf(grpcwire.InvalidArgument, "invalid request: %v", err)
+	}
+	var id string
+	for _, field := range fields {
+		if field.Number == 1 {
+			id = field.String()
+		}
+	}
+
+	snapshot, err := h.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, grpcwire.Errorf(grpcwire.Internal, "failed to encode snapshot: %v", err)
+	}
+
+	var msg []byte
+	msg = grpcwire.AppendMessage(msg, 1, encodeSnapshotInfo(snapshot))
+	msg = grpcwire.AppendBytes(msg, 2, data)
+	return msg, nil
+}
+
+func (h *GRPCHandler) diff(ctx context.Context, req []byte) ([]byte, error) {
+	fields, err := grpcwire.Parse(req)
+	if err != nil {
+		return nil, grpcwire.Errorf(grpcwire.InvalidArgument, "invalid request: %v", err)
+	}
+	var fromID, toID string
+	for _, field := range fields {
+		switch field.Number {
+		case 1:
+			fromID = field.String()
+		case 2:
+			toID = field.String()
+		}
+	}
+	if fromID == "" || toID == "" {
+		return nil, grpcwire.Errorf(grpcwire.InvalidArgument, "from and to snapshot IDs are required")
+	}
+
+	from, err := h.load(ctx, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := h.load(ctx, toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.Type != to.Type {
+		return nil, grpcwire.Errorf(grpcwire.InvalidArgument, "cannot compare a %s snapshot with a %s snapshot", from.Type, to.Type)
+	}
+
+	changes, err := h.runner.stateMgr.ComputeChanges(ctx, from, to)
+	if err != nil {
+		h.logger.Errorf("Failed to compare %s with %s: %v", from.ID, to.ID, err)
+		return nil, grpcwire.Errorf(grpcwire.Internal, "%v", err)
+	}
+
+	var msg []byte
+	for _, change := range changes.Changes {
+		data, err := json.Marshal(change)
+		if err != nil {
+			return nil, grpcwire.Errorf(grpcwire.Internal, "failed to encode change: %v", err)
+		}
+		var entry []byte
+		entry = grpcwire.AppendString(entry, 1, string(change.Type))
+		entry = grpcwire.AppendString(entry, 2, change.ObjectType)
+		entry = grpcwire.AppendString(entry, 3, change.ObjectID)
+		entry = grpcwire.AppendBytes(entry, 4, data)
+		msg = grpcwire.AppendMessage(msg, 1, entry)
+	}
+	changeTypes := make([]string, 0, len(changes.Summary))
+	for changeType := range changes.Summary {
+		changeTypes = append(changeTypes, string(changeType))
+	}
+	sort.Strings(changeTypes)
+	for _, changeType := range changeTypes {
+		msg = grpcwire.AppendMapEntry(msg, 2, changeType, int64(changes.Summary[models.ChangeType(changeType)]))
+	}
+	return msg, nil
+}
+
+func (h *GRPCHandler) history(ctx context.Context, req []byte, send func([]byte) error) error {
+	fields, err := grpcwire.Parse(req)
+	if err != nil {
+		return grpcwire.Errorf(grpcwire.InvalidArgument, "invalid request: %v", err)
+	}
+
+	var since, until time.Time
+	var objectType string
+	var limit int
+	for _, field := range fields {
+		switch field.Number {
+		case 1:
+			since, err = grpcwire.ParseTimestamp(field.Bytes)
+		case 2:
+			until, err = grpcwire.ParseTimestamp(field.Bytes)
+		case 3:
+			objectType = field.String()
+		case 4:
+			limit = int(field.Int())
+		}
+		if err != nil {
+			return grpcwire.Errorf(grpcwire.InvalidArgument, "invalid timestamp: %v", err)
+		}
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+
+	entries, err := h.runner.history.QueryChanges(ctx, since, until, objectType)
+	if err != nil {
+		h.logger.Errorf("Failed to query changelog: %v", err)
+		return grpcwire.Errorf(grpcwire.Internal, "%v", err)
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return grpcwire.Errorf(grpcwire.Internal, "failed to encode changelog entry: %v", err)
+		}
+		var msg []byte
+		msg = grpcwire.AppendString(msg, 1, entry.ID)
+		msg = grpcwire.AppendTimestamp(msg, 2, entry.Timestamp)
+		msg = grpcwire.AppendString(msg, 3, string(entry.ChangeType))
+		msg = grpcwire.AppendString(msg, 4, entry.ObjectType)
+		msg = grpcwire.AppendString(msg, 5, entry.ObjectID)
+		msg = grpcwire.AppendString(msg, 6, entry.SnapshotID)
+		msg = grpcwire.AppendBytes(msg, 7, data)
+		if err := send(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *GRPCHandler) watch(ctx context.Context, req []byte, send func([]byte) error) error {
+	fields, err := grpcwire.Parse(req)
+	if err != nil {
+		return grpcwire.Errorf(grpcwire.InvalidArgument, "invalid request: %v", err)
+	}
+	var types []events.Type
+	for _, field := range fields {
+		if field.Number == 1 {
+			types = append(types, events.Type(field.String()))
+		}
+	}
+
+	bus := h.runner.EventBus()
+	if bus == nil {
+		return grpcwire.Errorf(grpcwire.Unavailable, "the daemon publishes no events")
+	}
+	ch, unsubscribe := bus.SubscribeChan("grpc-watch", watchBuffer)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.done:
+			return grpcwire.Errorf(grpcwire.Unavailable, "the daemon is shutting down")
+		case event := <-ch:
+			if len(types) > 0 && !slices.Contains(types, event.EventType()) {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return grpcwire.Errorf(grpcwire.Internal, "failed to encode %s event: %v", event.EventType(), err)
+			}
+			var msg []byte
+			msg = grpcwire.AppendString(msg, 1, string(event.EventType()))
+			msg = grpcwire.AppendTimestamp(msg, 2, event.OccurredAt())
+			msg = grpcwire.AppendBytes(msg, 3, data)
+			if err := send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// load loads a snapshot, returning NotFound if it does not exist.
+func (h *GRPCHandler) load(ctx context.Context, id string) (*models.Snapshot, error) {
+	if id == "" {
+		return nil, grpcwire.Errorf(grpcwire.InvalidArgument, "snapshot ID is required")
+	}
+	snapshot, err := h.runner.stateMgr.LoadSnapshot(ctx, id)
+	if err != nil {
+		return nil, grpcwire.Errorf(grpcwire.NotFound, "snapshot %s not found", id)
+	}
+	return snapshot, nil
+}
+
+// encodeSnapshotInfo encodes a SnapshotInfo message.
+func encodeSnapshotInfo(snapshot *models.Snapshot) []byte {
+	var msg []byte
+	msg = grpcwire.AppendString(msg, 1, snapshot.ID)
+	msg = grpcwire.AppendString(msg, 2, string(snapshot.Type))
+	msg = grpcwire.AppendTimestamp(msg, 3, snapshot.Timestamp)
+	msg = grpcwire.AppendString(msg, 4, snapshot.Note)
+	msg = grpcwire.AppendString(msg, 5, snapshot.Scope())
+	msg = grpcwire.AppendStrings(msg, 6, snapshot.Tags)
+	msg = grpcwire.AppendInt(msg, 7, int64(snapshot.ItemCount()))
+	return msg
+}
@@ -0,0 +1,158 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/grpcwire"
+)
+
+func newGRPCTestServer(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	ts := httptest.NewUnstartedServer(handler)
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+// fieldsOf parses a message into its last value per field number.
+func fieldsOf(t *testing.T, msg []byte) map[int]grpcwire.Field {
+	t.Helper()
+
+	fields, err := grpcwire.Parse(msg)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	byNumber := make(map[int]grpcwire.Field)
+	for _, field := range fields {
+		byNumber[field.Number] = field
+	}
+	return byNumber
+}
+
+func TestGRPCHandler(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	runner.SetEventBus(events.NewBus())
+	handler := NewGRPCHandler(runner, testSecret, runner.logger)
+	url := newGRPCTestServer(t, handler)
+	client := grpcwire.NewH2CClient()
+	ctx := context.Background()
+
+	auth := http.Header{"Authorization": {"Bearer " + testSecret}}
+	call := func(method string, req []byte, recv func([]byte) error) error {
+		return grpcwire.Invoke(ctx, client, url, GRPCService+method, auth, req, recv)
+	}
+
+	var status *grpcwire.Status
+	err := grpcwire.Invoke(ctx, client, url, GRPCService+"ListSnapshots", nil, nil, func([]byte) error { return nil })
+	if !errors.As(err, &status) || status.Code != grpcwire.Unauthenticated {
+		t.Fatalf("unauthenticated call = %v, want Unauthenticated", err)
+	}
+
+	var results [2]*CheckResult
+	for i := range results {
+		if results[i], err = runner.Check(ctx); err != nil {
+			t.Fatalf("Check() failed: %v", err)
+		}
+	}
+
+	var listed []string
+	req := grpcwire.AppendString(nil, 1, string(models.SnapshotTypeRoute))
+	err = call("ListSnapshots", req, func(msg []byte) error {
+		listed = append(listed, fieldsOf(t, msg)[1].String())
+		return nil
+	})
+	if err != nil || len(listed) != 2 || listed[0] != results[1].SnapshotID {
+		t.Errorf("ListSnapshots = %v, %v; want both snapshots, newest first", listed, err)
+	}
+
+	var snapshot models.Snapshot
+	err = call("GetSnapshot", grpcwire.AppendString(nil, 1, results[0].SnapshotID), func(msg []byte) error {
+		return json.Unmarshal(fieldsOf(t, msg)[2].Bytes, &snapshot)
+	})
+	if err != nil || snapshot.ID != results[0].SnapshotID || snapshot.Routes == nil || snapshot.Routes.Count != 1 {
+		t.Errorf("GetSnapshot = %+v, %v; want the first snapshot with its route", snapshot, err)
+	}
+
+	err = call("GetSnapshot", grpcwire.AppendString(nil, 1, "missing"), func([]byte) error { return nil })
+	if !errors.As(err, &status) || status.Code != grpcwire.NotFound {
+		t.Errorf("GetSnapshot(missing) = %v, want NotFound", err)
+	}
+
+	var diffed bool
+	req = grpcwire.AppendString(nil, 1, results[0].SnapshotID)
+	req = grpcwire.AppendString(req, 2, results[1].SnapshotID)
+	err = call("Diff", req, func(msg []byte) error {
+		diffed = true
+		if _, ok := fieldsOf(t, msg)[1]; ok {
+			t.Errorf("Diff of identical snapshots returned changes")
+		}
+		return nil
+	})
+	if err != nil || !diffed {
+		t.Errorf("Diff = %v, want an empty response", err)
+	}
+
+	err = call("Diff", grpcwire.AppendString(nil, 1, results[0].SnapshotID), func([]byte) error { return nil })
+	if !errors.As(err, &status) || status.Code != grpcwire.InvalidArgument {
+		t.Errorf("Diff without to = %v, want InvalidArgument", err)
+	}
+
+	if err := call("History", nil, func([]byte) error { return nil }); err != nil {
+		t.Errorf("History failed: %v", err)
+	}
+
+	// Watch until an event arrives, checking repeatedly since the stream may
+	// not be subscribed yet, then end the stream by closing the handler
+	received := make(chan string, 1)
+	watched := make(chan error, 1)
+	go func() {
+		req := grpcwire.AppendStrings(nil, 1, []string{string(events.TypeSnapshotSaved)})
+		watched <- call("Watch", req, func(msg []byte) error {
+			select {
+			case received <- fieldsOf(t, msg)[1].String():
+			default:
+			}
+			return nil
+		})
+	}()
+
+	deadline := time.After(5 * time.Second)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for waiting := true; waiting; {
+		select {
+		case eventType := <-received:
+			if eventType != string(events.TypeSnapshotSaved) {
+				t.Errorf("Watch streamed %q, want %q", eventType, events.TypeSnapshotSaved)
+			}
+			waiting = false
+		case <-ticker.C:
+			if _, err := runner.Check(ctx); err != nil {
+				t.Fatalf("Check() failed: %v", err)
+			}
+		case <-deadline:
+			t.Fatal("Watch received no event")
+		}
+	}
+
+	handler.Close()
+	select {
+	case err := <-watched:
+		if !errors.As(err, &status) || status.Code != grpcwire.Unavailable {
+			t.Errorf("Watch after Close = %v, want Unavailable", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not end the Watch stream")
+	}
+}
@@ -0,0 +1,218 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Health check names in a HealthReport.
+const (
+	HealthCheckLoop        = "loop"        // A check cycle finished recently (liveness)
+	HealthCheckStateDir    = "state_dir"   // The state directory is writable (liveness)
+	HealthCheckFreshness   = "freshness"   // The last successful check is recent (readiness)
+	HealthCheckCredentials = "credentials" // The API accepts the credentials (readiness)
+)
+
+// HealthCheck is the outcome of one health check.
+type HealthCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// HealthReport is served by the /healthz and /readyz endpoints. A daemon
+// is live while its check loop runs and it can write state; it is ready
+// while it is live and its data is fresh.
+type HealthReport struct {
+	Live          bool          `json:"live"`
+	Ready         bool          `json:"ready"`
+	StartedAt     time.Time     `json:"started_at"`
+	LastCheckAt   *time.Time    `json:"last_check_at,omitempty"`
+	LastSuccessAt *time.Time    `json:"last_success_at,omitempty"`
+	LastError     string        `json:"last_error,omitempty"`
+	Checks        []HealthCheck `json:"checks"`
+}
+
+// Health tracks the outcome of check cycles to report whether the daemon
+// is live and ready.
+type Health struct {
+	client   api.Client
+	stateDir string
+	started  time.Time
+	maxAge   time.Duration
+
+	mu          sync.Mutex
+	lastCheck   *time.Time
+	lastSuccess *time.Time
+	lastError   string
+}
+
+// NewHealth creates a health tracker. Checks are expected at least every
+// maxAge; a non-positive maxAge means checks are not scheduled, so their
+// age is not held against the daemon.
+func NewHealth(client api.Client, stateDir string, maxAge time.Duration) *Health {
+	return &Health{
+		client:   client,
+		stateDir: stateDir,
+		started:  time.Now(),
+		maxAge:   maxAge,
+	}
+}
+
+// SetClient replaces the client whose credentials are checked, such as
+// after a configuration reload.
+func (h *Health) SetClient(client api.Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.client = client
+}
+
+// SetMaxAge changes how old the last check may be, such as after a
+// configuration reload changes the schedule.
+func (h *Health) SetMaxAge(maxAge time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxAge = maxAge
+}
+
+// Restore seeds the health tracker with the last successful check a
+// previous daemon recorded, so a restarted daemon whose data is still fresh
+// is ready before its own first check finishes.
+func (h *Health) Restore(recorded models.DaemonState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.lastSuccess == nil {
+		h.lastSuccess = recorded.LastSuccessAt
+	}
+}
+
+// SetHealth makes the runner record the outcome of each check in h.
+func (r *Runner) SetHealth(h *Health) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.health = h
+}
+
+// record notes a finished check cycle. It is safe on a nil receiver.
+func (h *Health) record(err error) {
+	if h == nil {
+		return
+	}
+
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastCheck = &now
+	if err != nil {
+		h.lastError = err.Error()
+		return
+	}
+	h.lastSuccess = &now
+	h.lastError = ""
+}
+
+// Report runs the health checks.
+func (h *Health) Report() *HealthReport {
+	h.mu.Lock()
+	report := &HealthReport{
+		StartedAt:     h.started,
+		LastCheckAt:   h.lastCheck,
+		LastSuccessAt: h.lastSuccess,
+		LastError:     h.lastError,
+	}
+	client, maxAge := h.client, h.maxAge
+	h.mu.Unlock()
+
+	now := time.Now()
+	loop := HealthCheck{Name: HealthCheckLoop, OK: true}
+	freshness := HealthCheck{Name: HealthCheckFreshness, OK: true}
+	if maxAge > 0 {
+		// A cycle that is still running counts from when the daemon started
+		since := h.started
+		if report.LastCheckAt != nil {
+			since = *report.LastCheckAt
+		}
+		if age := now.Sub(since); age > maxAge {
+			loop.OK = false
+			loop.Detail = fmt.Sprintf("no check has finished for %s", age.Round(time.Second))
+		}
+
+		switch {
+		case report.LastSuccessAt == nil:
+			freshness.OK = false
+			freshness.Detail = "no successful check yet"
+		case now.Sub(*report.LastSuccessAt) > maxAge:
+			freshness.OK = false
+			freshness.Detail = fmt.Sprintf("last successful check was %s ago", now.Sub(*report.LastSuccessAt).Round(time.Second))
+		}
+	} else {
+		loop.Detail = "checks are not scheduled"
+	}
+
+	stateDir := HealthCheck{Name: HealthCheckStateDir, OK: true}
+	if err := probeWritable(h.stateDir); err != nil {
+		stateDir.OK = false
+		stateDir.Detail = err.Error()
+	}
+
+	credentials := HealthCheck{Name: HealthCheckCredentials, OK: true}
+	if reporter, ok := client.(api.StatusReporter); ok && reporter.Status().CredentialsRejected {
+		credentials.OK = false
+		credentials.Detail = "the API rejected the credentials"
+	} else if !client.IsAuthenticated() {
+		credentials.OK = false
+		credentials.Detail = "no credentials loaded"
+	}
+
+	report.Checks = []HealthCheck{loop, stateDir, freshness, credentials}
+	report.Live = loop.OK && stateDir.OK
+	report.Ready = report.Live && freshness.OK && credentials.OK
+	return report
+}
+
+// probeWritable creates and removes a file in dir.
+func probeWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".healthz-*")
+	if err != nil {
+		return fmt.Errorf("state directory is not writable: %w", err)
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// ServeHTTP answers /healthz with liveness and /readyz with readiness:
+// 200 OK or 503 Service Unavailable, with the HealthReport as the body.
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := h.Report()
+	var ok bool
+	switch r.URL.Path {
+	case "/healthz":
+		ok = report.Live
+	case "/readyz":
+		ok = report.Ready
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
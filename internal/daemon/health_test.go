@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// probe requests a health endpoint and returns the status code and report.
+func probe(t *testing.T, h *Health, path string) (int, *HealthReport) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var report HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("%s returned invalid JSON: %v", path, err)
+	}
+	return rec.Code, &report
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	health := NewHealth(runner.client, t.TempDir(), time.Hour)
+	runner.SetHealth(health)
+
+	// Live but not ready until the first check succeeds
+	if code, _ := probe(t, health, "/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz before the first check = %d, want 200", code)
+	}
+	if code, report := probe(t, health, "/readyz"); code != http.StatusServiceUnavailable || report.Ready {
+		t.Errorf("/readyz before the first check = %d, want 503", code)
+	}
+
+	ctx := context.Background()
+	if _, err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	code, report := probe(t, health, "/readyz")
+	if code != http.StatusOK || !report.Ready || report.LastSuccessAt == nil {
+		t.Errorf("/readyz after a check = %d %+v, want ready", code, report)
+	}
+
+	// A failed check keeps the daemon ready while the last success is fresh
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	runner.Check(canceled)
+	code, report = probe(t, health, "/readyz")
+	if code != http.StatusOK || report.LastError == "" {
+		t.Errorf("/readyz after a failed check = %d, last error %q; want 200 and the error", code, report.LastError)
+	}
+
+	// Stale checks make the daemon neither live nor ready
+	health.maxAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	if code, _ := probe(t, health, "/healthz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/healthz with stale checks = %d, want 503", code)
+	}
+	if code, _ := probe(t, health, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz with stale checks = %d, want 503", code)
+	}
+}
+
+func TestHealthUnwritableStateDir(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	health := NewHealth(runner.client, filepath.Join(t.TempDir(), "missing"), 0)
+
+	code, report := probe(t, health, "/healthz")
+	if code != http.StatusServiceUnavailable || report.Live {
+		t.Errorf("/healthz with an unwritable state directory = %d, want 503", code)
+	}
+	for _, check := range report.Checks {
+		if check.Name == HealthCheckStateDir && (check.OK || check.Detail == "") {
+			t.Errorf("state_dir check = %+v, want a failure with detail", check)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package daemon
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/pkg/metrics"
+)
+
+// Metrics instruments monitoring cycles and the state they leave behind.
+// All methods are safe on a nil receiver, so an uninstrumented runner pays
+// nothing.
+type Metrics struct {
+	cycles      *metrics.Counter
+	duration    *metrics.Histogram
+	lastCycle   *metrics.Gauge
+	lastSuccess *metrics.Gauge
+	routes      *metrics.Gauge
+	changes     *metrics.Counter
+	violations  *metrics.Gauge
+	snapshots   *metrics.Gauge
+	diskUsage   *metrics.Gauge
+	stateDir    string
+}
+
+// NewMetrics registers the daemon metrics in reg. Disk usage is measured
+// under stateDir.
+func NewMetrics(reg *metrics.Registry, stateDir string) *Metrics {
+	return &Metrics{
+		cycles: reg.NewCounter("radb_daemon_cycles_total",
+			"Monitoring cycles run, by action (check, contact-check, snapshot, reconcile, cleanup) and result (success, failure).",
+			"action", "result"),
+		duration: reg.NewHistogram("radb_daemon_cycle_duration_seconds",
+			"Duration of monitoring cycles, by action.",
+			[]float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}, "action"),
+		lastCycle: reg.NewGauge("radb_daemon_last_cycle_timestamp_seconds",
+			"Unix time the last cycle of each action finished.",
+			"action"),
+		lastSuccess: reg.NewGauge("radb_daemon_last_success_timestamp_seconds",
+			"Unix time the last successful cycle of each action finished.",
+			"action"),
+		routes: reg.NewGauge("radb_daemon_routes",
+			"Routes fetched by the last check."),
+		changes: reg.NewCounter("radb_daemon_changes_total",
+			"Changes detected between consecutive checks, by type (added, removed, modified).",
+			"type"),
+		violations: reg.NewGauge("radb_daemon_assertion_violations",
+			"Routes breaking configured assertions in the last check."),
+		snapshots: reg.NewGauge("radb_state_snapshots",
+			"Snapshots kept in local state, by type.",
+			"type"),
+		diskUsage: reg.NewGauge("radb_state_disk_usage_bytes",
+			"Bytes used by the state directory."),
+		stateDir: stateDir,
+	}
+}
+
+// SetMetrics instruments the runner's cycles. A nil m disables instrumentation.
+func (r *Runner) SetMetrics(m *Metrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = m
+}
+
+// observeCycle records a finished cycle started at started.
+func (m *Metrics) observeCycle(action string, started time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	now := time.Now()
+	result := "success"
+	if err != nil {
+		result = "failure"
+	} else {
+		m.lastSuccess.Set(float64(now.Unix()), action)
+	}
+	m.cycles.Inc(action, result)
+	m.duration.Observe(now.Sub(started).Seconds(), action)
+	m.lastCycle.Set(float64(now.Unix()), action)
+}
+
+// observeCheck records the outcome of a successful check.
+func (m *Metrics) observeCheck(result *CheckResult) {
+	if m == nil {
+		return
+	}
+
+	m.routes.Set(float64(result.RouteCount))
+	m.violations.Set(float64(result.Violations))
+	m.observeChanges(result.Summary)
+}
+
+// observeChanges counts the changes a check recorded. Target checks count
+// only changes, since the route and violation gauges describe the whole
+// account.
+func (m *Metrics) observeChanges(summary map[models.ChangeType]int) {
+	if m == nil {
+		return
+	}
+
+	for changeType, count := range summary {
+		m.changes.Add(float64(count), string(changeType))
+	}
+}
+
+// observeState records the snapshots kept and the state directory's size.
+// Failures leave the previous values in place.
+func (m *Metrics) observeState(ctx context.Context, stateMgr state.Manager) {
+	if m == nil {
+		return
+	}
+
+	if snapshots, err := stateMgr.ListSnapshots(ctx); err == nil {
+		counts := map[models.SnapshotType]int{
+			models.SnapshotTypeRoute:   0,
+			models.SnapshotTypeContact: 0,
+			models.SnapshotTypeFull:    0,
+		}
+		for _, snapshot := range snapshots {
+			counts[snapshot.Type]++
+		}
+		for snapshotType, count := range counts {
+			m.snapshots.Set(float64(count), string(snapshotType))
+		}
+	}
+
+	if size, err := dirSize(m.stateDir); err == nil {
+		m.diskUsage.Set(float64(size))
+	}
+}
+
+// dirSize returns the total size of the regular files under dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
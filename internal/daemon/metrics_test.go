@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/pkg/metrics"
+)
+
+func TestRunnerMetrics(t *testing.T) {
+	runner, _ := newTestRunner(t)
+
+	stateDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(stateDir, "snapshot.json"), make([]byte, 100), 0600); err != nil {
+		t.Fatal(err)
+	}
+	reg := metrics.NewRegistry()
+	m := NewMetrics(reg, stateDir)
+	runner.SetMetrics(m)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := runner.Check(ctx); err != nil {
+			t.Fatalf("Check() failed: %v", err)
+		}
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := runner.Check(canceled); err == nil {
+		t.Fatal("Check() succeeded with a canceled context")
+	}
+
+	if got := m.cycles.Value("check", "success"); got != 2 {
+		t.Errorf("successful checks = %v, want 2", got)
+	}
+	if got := m.cycles.Value("check", "failure"); got != 1 {
+		t.Errorf("failed checks = %v, want 1", got)
+	}
+	if m.lastSuccess.Value("check") == 0 || m.lastCycle.Value("check") < m.lastSuccess.Value("check") {
+		t.Errorf("last cycle %v and last success %v timestamps not recorded", m.lastCycle.Value("check"), m.lastSuccess.Value("check"))
+	}
+	if got := m.routes.Value(); got != 1 {
+		t.Errorf("routes = %v, want 1", got)
+	}
+	if got := m.snapshots.Value("route"); got != 2 {
+		t.Errorf("route snapshots = %v, want 2", got)
+	}
+	if got := m.diskUsage.Value(); got != 100 {
+		t.Errorf("disk usage = %v, want 100", got)
+	}
+
+	var text strings.Builder
+	if err := reg.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"radb_daemon_cycle_duration_seconds_count", "radb_state_disk_usage_bytes 100"} {
+		if !strings.Contains(text.String(), name) {
+			t.Errorf("exposition missing %q:\n%s", name, text.String())
+		}
+	}
+}
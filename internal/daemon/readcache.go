@@ -0,0 +1,281 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/ratelimit"
+	"github.com/sirupsen/logrus"
+)
+
+// refreshTimeout bounds a background refresh from the API.
+const refreshTimeout = 2 * time.Minute
+
+// ReadCache answers read queries from the latest full snapshot of each type.
+// Snapshots older than maxAge are still served, but trigger a background
+// refresh from the API (stale-while-revalidate), so readers get immediate
+// answers that are never more than one refresh behind.
+type ReadCache struct {
+	runner  *Runner
+	maxAge  time.Duration
+	logger  *logrus.Logger
+	mu      sync.Mutex
+	entries map[models.SnapshotType]*cacheEntry
+	wg      sync.WaitGroup
+}
+
+// cacheEntry is the snapshot served for one type.
+type cacheEntry struct {
+	snapshot   *models.Snapshot
+	refreshing bool
+}
+
+// NewReadCache creates a read cache over the runner's client and state.
+func NewReadCache(runner *Runner, maxAge time.Duration, logger *logrus.Logger) *ReadCache {
+	return &ReadCache{
+		runner:  runner,
+		maxAge:  maxAge,
+		logger:  logger,
+		entries: make(map[models.SnapshotType]*cacheEntry),
+	}
+}
+
+// Subscribe drops cached snapshots when a newer full snapshot is saved, so
+// scheduled checks and webhook triggers are picked up on the next read.
+func (c *ReadCache) Subscribe(bus *events.Bus) (unsubscribe func()) {
+	return bus.Subscribe(func(event events.Event) {
+		saved, ok := event.(*events.SnapshotSaved)
+		if !ok || saved.Scope != "" {
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if entry := c.entries[saved.SnapshotType]; entry != nil && entry.snapshot.ID != saved.SnapshotID && !entry.refreshing {
+			delete(c.entries, saved.SnapshotType)
+		}
+	})
+}
+
+// Get returns the cached snapshot of a type, hydrating it from local state
+// or, if there is none, fetching it from the API. A stale snapshot is
+// returned as is and refreshed in the background.
+func (c *ReadCache) Get(ctx context.Context, snapshotType models.SnapshotType) (*models.Snapshot, error) {
+	c.mu.Lock()
+	entry := c.entries[snapshotType]
+	c.mu.Unlock()
+
+	if entry == nil {
+		snapshot, err := c.runner.stateMgr.GetLatestSnapshot(ctx, snapshotType)
+		if err != nil {
+			c.logger.Debugf("No %s snapshot to serve from, fetching: %v", snapshotType, err)
+			if snapshot, err = c.fetch(ctx, snapshotType); err != nil {
+				return nil, err
+			}
+		}
+
+		c.mu.Lock()
+		entry = &cacheEntry{snapshot: snapshot}
+		c.entries[snapshotType] = entry
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stale(entry.snapshot) && !entry.refreshing {
+		entry.refreshing = true
+		c.wg.Add(1)
+		go c.refresh(snapshotType, entry)
+	}
+	return entry.snapshot, nil
+}
+
+// Wait blocks until background refreshes have finished.
+func (c *ReadCache) Wait() {
+	c.wg.Wait()
+}
+
+// stale reports whether a snapshot is older than the cache's maximum age.
+func (c *ReadCache) stale(snapshot *models.Snapshot) bool {
+	return time.Since(snapshot.Timestamp) > c.maxAge
+}
+
+// refresh replaces a stale entry's snapshot with a fresh one from the API.
+func (c *ReadCache) refresh(snapshotType models.SnapshotType, entry *cacheEntry) {
+	defer c.wg.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	snapshot, err := c.fetch(ctx, snapshotType)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.refreshing = false
+	if err != nil {
+		c.logger.Warnf("Failed to refresh %s snapshot: %v", snapshotType, err)
+		return
+	}
+	entry.snapshot = snapshot
+	c.entries[snapshotType] = entry
+	c.logger.Debugf("Refreshed %s snapshot: %s", snapshotType, snapshot.ID)
+}
+
+// fetch reads a type from the API and saves it as a snapshot. Routes go
+// through a check cycle, so the refresh is recorded in the changelog and
+// publishes the usual events.
+func (c *ReadCache) fetch(ctx context.Context, snapshotType models.SnapshotType) (*models.Snapshot, error) {
+	ctx = ratelimit.WithJob(ctx, JobReadCache)
+	switch snapshotType {
+	case models.SnapshotTypeRoute:
+		result, err := c.runner.Check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return c.runner.stateMgr.LoadSnapshot(ctx, result.SnapshotID)
+	case models.SnapshotTypeContact:
+		contacts, err := c.runner.apiClient().ListContacts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list contacts: %w", err)
+		}
+		snapshot := models.NewSnapshot(models.SnapshotTypeContact, "Read cache refresh")
+		snapshot.Contacts = contacts
+		if err := c.runner.stateMgr.SaveSnapshot(ctx, snapshot); err != nil {
+			return nil, fmt.Errorf("save snapshot: %w", err)
+		}
+		c.runner.publishSnapshot(snapshot)
+		return snapshot, nil
+	}
+	return nil, fmt.Errorf("unsupported snapshot type %s", snapshotType)
+}
+
+// ReadHandler serves routes and contacts from a ReadCache.
+//
+// Routes:
+//
+//	GET /api/routes          routes, filtered by the prefix, origin, and mnt-by query parameters
+//	GET /api/contacts        all contacts
+//	GET /api/contacts/{id}   a single contact
+//
+// Responses carry the serving snapshot in X-Radb-Snapshot and its age in
+// seconds in Age. Callers authenticate with "Authorization: Bearer <secret>".
+type ReadHandler struct {
+	cache  *ReadCache
+	secret []byte
+	local  bool // Served on the local socket, where file permissions authenticate
+	logger *logrus.Logger
+	mux    *http.ServeMux
+}
+
+// NewReadHandler creates a read handler authenticated by secret.
+func NewReadHandler(cache *ReadCache, secret string, logger *logrus.Logger) *ReadHandler {
+	h := &ReadHandler{
+		cache:  cache,
+		secret: []byte(secret),
+		logger: logger,
+		mux:    http.NewServeMux(),
+	}
+	h.routeMux()
+	return h
+}
+
+// NewLocalReadHandler creates a read handler for the daemon's local socket.
+// Requests are not authenticated: only the socket's owner can connect.
+func NewLocalReadHandler(cache *ReadCache, logger *logrus.Logger) *ReadHandler {
+	h := &ReadHandler{
+		cache:  cache,
+		local:  true,
+		logger: logger,
+		mux:    http.NewServeMux(),
+	}
+	h.routeMux()
+	return h
+}
+
+// routeMux registers the read endpoints.
+func (h *ReadHandler) routeMux() {
+	h.mux.HandleFunc("GET /api/routes", h.routes)
+	h.mux.HandleFunc("GET /api/contacts", h.contacts)
+	h.mux.HandleFunc("GET /api/contacts/{id}", h.contact)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *ReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.local && !authorized(h.secret, r, nil) {
+		h.logger.Warnf("Rejected unauthenticated read from %s", r.RemoteAddr)
+		writeJSONError(w, http.StatusUnauthorized, "invalid or missing credentials")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *ReadHandler) routes(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := h.snapshot(w, r, models.SnapshotTypeRoute)
+	if !ok {
+		return
+	}
+
+	filters := make(map[string]string)
+	for _, key := range []string{"prefix", "origin", "mnt-by"} {
+		if value := r.URL.Query().Get(key); value != "" {
+			filters[key] = value
+		}
+	}
+
+	routes := make([]models.RouteObject, 0)
+	for _, route := range snapshot.Routes.Routes {
+		if route.MatchesFilters(filters) {
+			routes = append(routes, route)
+		}
+	}
+	writeJSON(w, http.StatusOK, models.NewRouteList(routes))
+}
+
+func (h *ReadHandler) contacts(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := h.snapshot(w, r, models.SnapshotTypeContact)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot.Contacts)
+}
+
+func (h *ReadHandler) contact(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := h.snapshot(w, r, models.SnapshotTypeContact)
+	if !ok {
+		return
+	}
+
+	id := r.PathValue("id")
+	for _, contact := range snapshot.Contacts.Contacts {
+		if contact.ID == id {
+			writeJSON(w, http.StatusOK, contact)
+			return
+		}
+	}
+	writeJSONError(w, http.StatusNotFound, fmt.Sprintf("contact %s not found", id))
+}
+
+// snapshot fetches the snapshot of a type and sets the freshness headers,
+// writing an error response if there is none.
+func (h *ReadHandler) snapshot(w http.ResponseWriter, r *http.Request, snapshotType models.SnapshotType) (*models.Snapshot, bool) {
+	snapshot, err := h.cache.Get(r.Context(), snapshotType)
+	if err == nil && ((snapshotType == models.SnapshotTypeRoute && snapshot.Routes == nil) ||
+		(snapshotType == models.SnapshotTypeContact && snapshot.Contacts == nil)) {
+		err = fmt.Errorf("snapshot %s holds no %s data", snapshot.ID, snapshotType)
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to serve %s read: %v", snapshotType, err)
+		writeJSONError(w, http.StatusServiceUnavailable, err.Error())
+		return nil, false
+	}
+
+	w.Header().Set("X-Radb-Snapshot", snapshot.ID)
+	w.Header().Set("Age", strconv.Itoa(int(time.Since(snapshot.Timestamp).Seconds())))
+	return snapshot, true
+}
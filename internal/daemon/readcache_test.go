@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+)
+
+func readRoutes(t *testing.T, handler http.Handler, query string) (*httptest.ResponseRecorder, *models.RouteList) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/routes"+query, nil)
+	req.Header.Set("Authorization", "Bearer "+testSecret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var routes models.RouteList
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&routes); err != nil {
+			t.Fatalf("failed to decode routes: %v", err)
+		}
+	}
+	return rec, &routes
+}
+
+func TestReadHandlerHydratesFromAPI(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	cache := NewReadCache(runner, time.Hour, runner.logger)
+	handler := NewReadHandler(cache, testSecret, runner.logger)
+
+	rec, routes := readRoutes(t, handler, "")
+	if rec.Code != http.StatusOK || routes.Count != 1 {
+		t.Fatalf("GET /api/routes = %d with %d routes, want 200 with 1", rec.Code, routes.Count)
+	}
+	if rec.Header().Get("X-Radb-Snapshot") == "" {
+		t.Error("response should name the serving snapshot")
+	}
+
+	rec, routes = readRoutes(t, handler, "?origin=AS64501")
+	if rec.Code != http.StatusOK || routes.Count != 0 {
+		t.Errorf("filtered GET = %d with %d routes, want 200 with 0", rec.Code, routes.Count)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/routes", nil)
+	unauthorized := httptest.NewRecorder()
+	handler.ServeHTTP(unauthorized, req)
+	if unauthorized.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated GET = %d, want 401", unauthorized.Code)
+	}
+}
+
+func TestReadCacheServesStaleWhileRefreshing(t *testing.T) {
+	runner, stateMgr := newTestRunner(t)
+	ctx := context.Background()
+
+	old := models.NewSnapshot(models.SnapshotTypeRoute, "old")
+	old.Timestamp = time.Now().Add(-2 * time.Hour)
+	old.Routes = models.NewRouteList(nil)
+	if err := stateMgr.SaveSnapshot(ctx, old); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewReadCache(runner, time.Hour, runner.logger)
+	bus := events.NewBus()
+	runner.SetEventBus(bus)
+	cache.Subscribe(bus)
+
+	served, err := cache.Get(ctx, models.SnapshotTypeRoute)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if served.ID != old.ID {
+		t.Fatalf("Get() served %s, want the stale snapshot %s", served.ID, old.ID)
+	}
+
+	cache.Wait()
+	fresh, err := cache.Get(ctx, models.SnapshotTypeRoute)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if fresh.ID == old.ID || len(fresh.Routes.Routes) != 1 {
+		t.Errorf("Get() after refresh served %s with %d routes, want a fresh snapshot", fresh.ID, len(fresh.Routes.Routes))
+	}
+}
@@ -0,0 +1,613 @@
+// Package daemon implements the monitoring cycles shared by the daemon and serve commands.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/pkg/ratelimit"
+	"github.com/bss/radb-client/pkg/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// Runner executes monitoring cycles against the API and local state.
+// Cycles are serialized so scheduled checks and external triggers never overlap.
+type Runner struct {
+	client     api.Client
+	stateMgr   state.Manager
+	history    *state.HistoryManager
+	logger     *logrus.Logger
+	events     *events.Bus
+	assertions []audit.Assertion
+	baseline   string // Named baseline changes are reported against, if set
+	targets    []Target
+	metrics    *Metrics
+	health     *Health
+	runtime    *RuntimeState
+	mu         sync.Mutex
+}
+
+// Rate limit jobs that share the API client's request budget, as named in
+// api.rate_limit.job_weights. Cycles started by a webhook or a read cache
+// refresh count towards that job rather than the cycle's own.
+const (
+	JobCheck     = "check"
+	JobSnapshot  = "snapshot"
+	JobReconcile = "reconcile"
+	JobContacts  = "contact-check"
+	JobWebhook   = "webhook"
+	JobReadCache = "read-cache"
+)
+
+// CheckResult summarizes a single check cycle.
+type CheckResult struct {
+	Target     string                    `json:"target,omitempty"` // Watch target checked, if not the whole account
+	SnapshotID string                    `json:"snapshot_id"`
+	PreviousID string                    `json:"previous_id,omitempty"`
+	BaselineID string                    `json:"baseline_id,omitempty"` // Snapshot of the baseline changes were reported against
+	Drift      int                       `json:"drift,omitempty"`       // Differences from the baseline
+	RouteCount int                       `json:"route_count"`
+	Changes    int                       `json:"changes"`
+	Summary    map[models.ChangeType]int `json:"summary,omitempty"`
+	Violations int                       `json:"violations,omitempty"` // Routes breaking configured assertions
+}
+
+// Target is a subset of routes checked on its own, with its own snapshots
+// and retention.
+type Target struct {
+	Name      string
+	Filters   map[string]string // Route listing filters that select the target
+	Retention int               // Snapshots kept for the target (0 = the route default)
+}
+
+// ContactCheckResult summarizes a contact check cycle.
+type ContactCheckResult struct {
+	SnapshotID   string                    `json:"snapshot_id"`
+	PreviousID   string                    `json:"previous_id,omitempty"`
+	ContactCount int                       `json:"contact_count"`
+	Changes      int                       `json:"changes"`
+	Summary      map[models.ChangeType]int `json:"summary,omitempty"`
+}
+
+// ReconcileResult summarizes a reconcile run.
+type ReconcileResult struct {
+	Check   *CheckResult         `json:"check"`
+	Cleanup *state.CleanupResult `json:"cleanup"`
+}
+
+// NewRunner creates a new monitoring runner.
+func NewRunner(client api.Client, stateMgr state.Manager, history *state.HistoryManager, logger *logrus.Logger) *Runner {
+	return &Runner{
+		client:   client,
+		stateMgr: stateMgr,
+		history:  history,
+		logger:   logger,
+	}
+}
+
+// SetClient replaces the API client, such as after a configuration
+// reload. It waits for a running cycle to finish, so no cycle mixes clients.
+func (r *Runner) SetClient(client api.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.client = client
+}
+
+// apiClient returns the current API client, for reads outside cycles.
+func (r *Runner) apiClient() api.Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.client
+}
+
+// SetEventBus sets the bus that receives snapshot, change, and failure events.
+func (r *Runner) SetEventBus(bus *events.Bus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = bus
+}
+
+// EventBus returns the bus set with SetEventBus, or nil.
+func (r *Runner) EventBus() *events.Bus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events
+}
+
+// SetAssertions sets the assertions every check evaluates against the
+// fetched routes.
+func (r *Runner) SetAssertions(assertions []audit.Assertion) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.assertions = assertions
+}
+
+// SetBaseline makes checks report changes against the named baseline
+// rather than the previous snapshot, so notifications describe drift from
+// the approved state. The changelog still records changes since the
+// previous snapshot, and nothing is reported while routes stay unchanged.
+func (r *Runner) SetBaseline(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.baseline = name
+}
+
+// SetTargets sets the watch targets whose snapshots are pruned by
+// CheckTarget alone, so cleanups and reconciles leave them to the
+// targets' own retention.
+func (r *Runner) SetTargets(targets []Target) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.targets = targets
+}
+
+// Check performs a single monitoring cycle: it fetches the current routes,
+// saves them as a snapshot, and records changes against the previous snapshot
+// in the changelog.
+func (r *Runner) Check(ctx context.Context) (*CheckResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx = ratelimit.WithDefaultJob(ctx, JobCheck)
+	ctx, span := tracing.Start(ctx, "daemon.Check")
+	started := time.Now()
+	result, err := r.check(ctx)
+	span.EndErr(err)
+	if err != nil {
+		r.publishFailure("check", err)
+	} else {
+		r.metrics.observeCheck(result)
+	}
+	r.health.record(err)
+	r.observe(ctx, "check", started, err)
+	return result, err
+}
+
+func (r *Runner) check(ctx context.Context) (*CheckResult, error) {
+	return r.checkRoutes(ctx, nil)
+}
+
+// CheckTarget performs a monitoring cycle for one watch target: it fetches
+// the target's routes, saves them as a snapshot scoped to the target's
+// filters, records changes against the target's previous snapshot, and
+// prunes the target's snapshots beyond its retention.
+func (r *Runner) CheckTarget(ctx context.Context, target Target) (*CheckResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx = ratelimit.WithDefaultJob(ctx, JobCheck)
+	ctx, span := tracing.Start(ctx, "daemon.CheckTarget")
+	started := time.Now()
+	result, err := r.checkRoutes(ctx, &target)
+	if err == nil {
+		err = r.pruneTarget(ctx, target)
+	}
+	span.EndErr(err)
+	if err != nil {
+		r.events.Publish(&events.CheckFailed{
+			Time:   time.Now(),
+			Action: "target-check",
+			Target: target.Name,
+			Error:  err.Error(),
+		})
+	} else {
+		r.metrics.observeChanges(result.Summary)
+	}
+	r.health.record(err)
+	r.observe(ctx, "target-check", started, err)
+	return result, err
+}
+
+// checkRoutes checks the routes a target selects, or the whole account if
+// target is nil.
+func (r *Runner) checkRoutes(ctx context.Context, target *Target) (*CheckResult, error) {
+	var filters map[string]string
+	if target != nil {
+		filters = target.Filters
+	}
+
+	result, err := r.saveRoutes(ctx, target, filters)
+	var snapshotID, name string
+	if result != nil {
+		snapshotID = result.SnapshotID
+	}
+	if target != nil {
+		name = target.Name
+	}
+	r.runtime.record(models.FilterScope(filters), name, snapshotID, err)
+	return result, err
+}
+
+// saveRoutes fetches and saves the routes filters select and records the
+// changes since the previous snapshot of the same scope.
+func (r *Runner) saveRoutes(ctx context.Context, target *Target, filters map[string]string) (*CheckResult, error) {
+	routes, err := r.client.ListRoutes(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("list routes: %w", err)
+	}
+
+	previous, err := r.previousSnapshot(ctx, filters)
+	if err != nil {
+		r.logger.Debugf("No previous snapshot to compare against: %v", err)
+		previous = nil
+	}
+
+	note := "Daemon check"
+	if target != nil {
+		note = "Daemon check of target " + target.Name
+	}
+	snapshot := models.NewScopedSnapshot(models.SnapshotTypeRoute, note, filters)
+	snapshot.Routes = routes
+	if err := r.stateMgr.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("save snapshot: %w", err)
+	}
+	r.publishSnapshot(snapshot)
+
+	result := &CheckResult{
+		SnapshotID: snapshot.ID,
+		RouteCount: routes.Count,
+	}
+	if target != nil {
+		result.Target = target.Name
+	}
+	result.Violations = r.checkAssertions(snapshot, result.Target)
+
+	if previous == nil {
+		r.logger.Infof("Created baseline snapshot %s with %d routes", snapshot.ID, routes.Count)
+		return result, nil
+	}
+	result.PreviousID = previous.ID
+
+	changes, err := r.stateMgr.ComputeChanges(ctx, previous, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("compute changes: %w", err)
+	}
+
+	if changes.IsEmpty() {
+		r.logger.Infof("No changes detected since %s", previous.ID)
+		return result, nil
+	}
+
+	if err := r.history.AppendChanges(ctx, changes); err != nil {
+		return nil, fmt.Errorf("append changelog: %w", err)
+	}
+
+	result.Changes = len(changes.Changes)
+	result.Summary = changes.Summary
+
+	r.logger.WithFields(logrus.Fields{
+		"target":   result.Target,
+		"snapshot": snapshot.ID,
+		"added":    changes.Summary[models.ChangeTypeAdded],
+		"removed":  changes.Summary[models.ChangeTypeRemoved],
+		"modified": changes.Summary[models.ChangeTypeModified],
+	}).Infof("Detected %d changes since %s", len(changes.Changes), previous.ID)
+
+	event := &events.ChangesDetected{
+		Time:       time.Now(),
+		SnapshotID: snapshot.ID,
+		PreviousID: previous.ID,
+		Target:     result.Target,
+		Summary:    changes.Summary,
+		Changes:    changes,
+	}
+	// Baselines describe the whole account, so targets report changes
+	// since their previous snapshot
+	if r.baseline != "" && target == nil {
+		drift, baseline, err := r.baselineDrift(ctx, snapshot)
+		if err != nil {
+			r.logger.Warnf("Reporting changes since %s instead of baseline %s: %v", previous.ID, r.baseline, err)
+		} else if drift.IsEmpty() {
+			result.BaselineID = baseline.ID
+			r.logger.Infof("Routes match baseline %s (%s) again", r.baseline, baseline.ID)
+			return result, nil
+		} else {
+			result.BaselineID = baseline.ID
+			result.Drift = len(drift.Changes)
+			event.PreviousID = baseline.ID
+			event.Baseline = r.baseline
+			event.Summary = drift.Summary
+			event.Changes = drift
+		}
+	}
+	r.events.Publish(event)
+
+	return result, nil
+}
+
+// previousSnapshot returns the snapshot the last successful check with the
+// given filters saved, as recorded in the runtime state, so snapshots other
+// commands saved in between are not mistaken for it. Without a record, or
+// once the recorded snapshot is pruned, it returns the latest route
+// snapshot captured with the filters.
+func (r *Runner) previousSnapshot(ctx context.Context, filters map[string]string) (*models.Snapshot, error) {
+	scope := models.FilterScope(filters)
+	if id := r.runtime.lastSnapshotID(scope); id != "" {
+		snapshot, err := r.stateMgr.LoadSnapshot(ctx, id)
+		if err == nil && snapshot.Type == models.SnapshotTypeRoute && models.FilterScope(snapshot.Filters()) == scope {
+			return snapshot, nil
+		}
+		r.logger.Debugf("Recorded previous snapshot %s is unavailable, using the latest: %v", id, err)
+	}
+
+	if scope == "" {
+		return r.stateMgr.GetLatestSnapshot(ctx, models.SnapshotTypeRoute)
+	}
+
+	matches, err := r.stateMgr.QuerySnapshots(ctx, state.SnapshotQuery{
+		Type:  models.SnapshotTypeRoute,
+		Scope: scope,
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no snapshots with scope %s", scope)
+	}
+	return r.stateMgr.LoadSnapshot(ctx, matches[0].ID)
+}
+
+// cleanupOptions applies the default retention to every snapshot not
+// kept by a watch target.
+func (r *Runner) cleanupOptions() state.CleanupOptions {
+	options := state.CleanupOptions{KeepByType: state.DefaultRetention}
+	for _, target := range r.targets {
+		options.ExcludeScopes = append(options.ExcludeScopes, models.FilterScope(target.Filters))
+	}
+	return options
+}
+
+// pruneTarget deletes the target's snapshots beyond its retention.
+func (r *Runner) pruneTarget(ctx context.Context, target Target) error {
+	keep := target.Retention
+	if keep == 0 {
+		keep = state.DefaultRetention[models.SnapshotTypeRoute]
+	}
+
+	result, err := r.stateMgr.Cleanup(ctx, state.CleanupOptions{
+		KeepCount: keep,
+		Scopes:    []string{models.FilterScope(target.Filters)},
+	})
+	if err != nil {
+		return fmt.Errorf("cleanup snapshots: %w", err)
+	}
+	if result.Deleted > 0 {
+		r.logger.Infof("Pruned %d snapshots of target %s", result.Deleted, target.Name)
+	}
+	return nil
+}
+
+// baselineDrift returns the differences between the runner's baseline and
+// snapshot, and the baseline snapshot.
+func (r *Runner) baselineDrift(ctx context.Context, snapshot *models.Snapshot) (*models.ChangeSet, *models.Snapshot, error) {
+	baseline, err := r.stateMgr.LoadSnapshot(ctx, state.BaselinePrefix+r.baseline)
+	if err != nil {
+		return nil, nil, err
+	}
+	if baseline.Type != models.SnapshotTypeRoute {
+		return nil, nil, fmt.Errorf("baseline %s is a %s snapshot, not a route snapshot", r.baseline, baseline.Type)
+	}
+	drift, err := r.stateMgr.ComputeChanges(ctx, baseline, snapshot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("compute drift: %w", err)
+	}
+	return drift, baseline, nil
+}
+
+// Snapshot saves a route snapshot without recording changes.
+// Non-empty filters produce a scoped snapshot that is excluded from change tracking.
+func (r *Runner) Snapshot(ctx context.Context, filters map[string]string, note string) (_ *models.Snapshot, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx = ratelimit.WithDefaultJob(ctx, JobSnapshot)
+	ctx, span := tracing.Start(ctx, "daemon.Snapshot")
+	started := time.Now()
+	defer func() {
+		span.EndErr(err)
+		r.observe(ctx, "snapshot", started, err)
+	}()
+
+	routes, err := r.client.ListRoutes(ctx, filters)
+	if err != nil {
+		err = fmt.Errorf("list routes: %w", err)
+		r.publishFailure("snapshot", err)
+		return nil, err
+	}
+
+	snapshot := models.NewScopedSnapshot(models.SnapshotTypeRoute, note, filters)
+	snapshot.Routes = routes
+	if err := r.stateMgr.SaveSnapshot(ctx, snapshot); err != nil {
+		err = fmt.Errorf("save snapshot: %w", err)
+		r.publishFailure("snapshot", err)
+		return nil, err
+	}
+	r.publishSnapshot(snapshot)
+
+	r.logger.Infof("Created snapshot %s with %d routes", snapshot.ID, routes.Count)
+	return snapshot, nil
+}
+
+// Reconcile runs a check cycle and then prunes snapshots beyond the default
+// retention so local state matches policy.
+func (r *Runner) Reconcile(ctx context.Context) (_ *ReconcileResult, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx = ratelimit.WithDefaultJob(ctx, JobReconcile)
+	ctx, span := tracing.Start(ctx, "daemon.Reconcile")
+	started := time.Now()
+	defer func() {
+		span.EndErr(err)
+		r.observe(ctx, "reconcile", started, err)
+	}()
+
+	check, err := r.check(ctx)
+	r.health.record(err)
+	if err != nil {
+		r.publishFailure("reconcile", err)
+		return nil, err
+	}
+	r.metrics.observeCheck(check)
+
+	cleanup, err := r.stateMgr.Cleanup(ctx, r.cleanupOptions())
+	if err != nil {
+		err = fmt.Errorf("cleanup snapshots: %w", err)
+		r.publishFailure("reconcile", err)
+		return nil, err
+	}
+
+	r.logger.Infof("Reconcile complete: %d snapshots pruned", cleanup.Deleted)
+	return &ReconcileResult{Check: check, Cleanup: cleanup}, nil
+}
+
+// CheckContacts fetches the current contacts, saves them as a snapshot, and
+// records changes against the previous contact snapshot in the changelog.
+func (r *Runner) CheckContacts(ctx context.Context) (_ *ContactCheckResult, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx = ratelimit.WithDefaultJob(ctx, JobContacts)
+	ctx, span := tracing.Start(ctx, "daemon.CheckContacts")
+	started := time.Now()
+	defer func() {
+		span.EndErr(err)
+		if err != nil {
+			r.publishFailure("contact-check", err)
+		}
+		r.observe(ctx, "contact-check", started, err)
+	}()
+
+	contacts, err := r.client.ListContacts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list contacts: %w", err)
+	}
+
+	previous, err := r.stateMgr.GetLatestSnapshot(ctx, models.SnapshotTypeContact)
+	if err != nil {
+		r.logger.Debugf("No previous contact snapshot to compare against: %v", err)
+		previous = nil
+	}
+
+	snapshot := models.NewSnapshot(models.SnapshotTypeContact, "Daemon contact check")
+	snapshot.Contacts = contacts
+	if err := r.stateMgr.SaveSnapshot(ctx, snapshot); err != nil {
+		return nil, fmt.Errorf("save snapshot: %w", err)
+	}
+	r.publishSnapshot(snapshot)
+
+	result := &ContactCheckResult{
+		SnapshotID:   snapshot.ID,
+		ContactCount: contacts.Count,
+	}
+	if previous == nil {
+		r.logger.Infof("Created baseline contact snapshot %s with %d contacts", snapshot.ID, contacts.Count)
+		return result, nil
+	}
+	result.PreviousID = previous.ID
+
+	changes, err := r.stateMgr.ComputeChanges(ctx, previous, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("compute changes: %w", err)
+	}
+	if changes.IsEmpty() {
+		r.logger.Infof("No contact changes detected since %s", previous.ID)
+		return result, nil
+	}
+	if err := r.history.AppendChanges(ctx, changes); err != nil {
+		return nil, fmt.Errorf("append changelog: %w", err)
+	}
+	result.Changes = len(changes.Changes)
+	result.Summary = changes.Summary
+
+	r.logger.WithField("snapshot", snapshot.ID).Infof("Detected %d contact changes since %s", len(changes.Changes), previous.ID)
+	r.events.Publish(&events.ChangesDetected{
+		Time:       time.Now(),
+		SnapshotID: snapshot.ID,
+		PreviousID: previous.ID,
+		Summary:    changes.Summary,
+		Changes:    changes,
+	})
+	return result, nil
+}
+
+// Cleanup prunes snapshots beyond the default retention without running a
+// check.
+func (r *Runner) Cleanup(ctx context.Context) (_ *state.CleanupResult, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx, span := tracing.Start(ctx, "daemon.Cleanup")
+	started := time.Now()
+	defer func() {
+		span.EndErr(err)
+		if err != nil {
+			r.publishFailure("cleanup", err)
+		}
+		r.observe(ctx, "cleanup", started, err)
+	}()
+
+	result, err := r.stateMgr.Cleanup(ctx, r.cleanupOptions())
+	if err != nil {
+		return nil, fmt.Errorf("cleanup snapshots: %w", err)
+	}
+	r.logger.Infof("Cleanup complete: %d snapshots pruned", result.Deleted)
+	return result, nil
+}
+
+// observe records a finished cycle and the state it left behind.
+func (r *Runner) observe(ctx context.Context, action string, started time.Time, err error) {
+	r.metrics.observeCycle(action, started, err)
+	r.metrics.observeState(ctx, r.stateMgr)
+}
+
+// checkAssertions evaluates the assertions against a snapshot's routes,
+// publishing an AssertionsViolated event for any violations, and returns
+// the number found.
+func (r *Runner) checkAssertions(snapshot *models.Snapshot, target string) int {
+	if len(r.assertions) == 0 {
+		return 0
+	}
+
+	violations := audit.EvaluateAssertions(snapshot.Routes.Routes, r.assertions)
+	if len(violations) == 0 {
+		return 0
+	}
+
+	r.logger.WithField("snapshot", snapshot.ID).Warnf("%d assertion violations", len(violations))
+	r.events.Publish(&events.AssertionsViolated{
+		Time:       time.Now(),
+		SnapshotID: snapshot.ID,
+		Target:     target,
+		Violations: violations,
+	})
+	return len(violations)
+}
+
+// publishSnapshot emits a SnapshotSaved event.
+func (r *Runner) publishSnapshot(snapshot *models.Snapshot) {
+	r.events.Publish(&events.SnapshotSaved{
+		Time:         time.Now(),
+		SnapshotID:   snapshot.ID,
+		SnapshotType: snapshot.Type,
+		Scope:        snapshot.Scope(),
+		ItemCount:    snapshot.ItemCount(),
+	})
+}
+
+// publishFailure emits a CheckFailed event.
+func (r *Runner) publishFailure(action string, err error) {
+	r.events.Publish(&events.CheckFailed{
+		Time:   time.Now(),
+		Action: action,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,288 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+)
+
+func TestRunnerPublishesEvents(t *testing.T) {
+	runner, _ := newTestRunner(t)
+
+	bus := events.NewBus()
+	var received []events.Event
+	bus.Subscribe(func(event events.Event) { received = append(received, event) })
+	runner.SetEventBus(bus)
+
+	ctx := context.Background()
+	if _, err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if len(received) != 1 || received[0].EventType() != events.TypeSnapshotSaved {
+		t.Fatalf("baseline check published %v, want one SnapshotSaved", received)
+	}
+	if saved := received[0].(*events.SnapshotSaved); saved.SnapshotType != models.SnapshotTypeRoute || saved.ItemCount != 1 {
+		t.Errorf("SnapshotSaved = %+v, want one route", saved)
+	}
+
+	// A failing cycle publishes CheckFailed
+	received = nil
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := runner.Check(canceled); err == nil {
+		t.Fatal("Check() succeeded with a canceled context")
+	}
+	if len(received) != 1 || received[0].EventType() != events.TypeCheckFailed {
+		t.Fatalf("failed check published %v, want one CheckFailed", received)
+	}
+	if failed := received[0].(*events.CheckFailed); failed.Action != "check" || failed.Error == "" {
+		t.Errorf("CheckFailed = %+v", failed)
+	}
+}
+
+func TestRunnerChecksAssertions(t *testing.T) {
+	runner, _ := newTestRunner(t)
+
+	assertion, err := audit.NewAssertion("as64500", "AS64500", "", "", []string{"MAINT-X"}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner.SetAssertions([]audit.Assertion{assertion})
+
+	bus := events.NewBus()
+	var violated []*events.AssertionsViolated
+	bus.Subscribe(func(event events.Event) {
+		if e, ok := event.(*events.AssertionsViolated); ok {
+			violated = append(violated, e)
+		}
+	})
+	runner.SetEventBus(bus)
+
+	result, err := runner.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if result.Violations != 1 {
+		t.Errorf("Violations = %d, want 1", result.Violations)
+	}
+	if len(violated) != 1 || violated[0].SnapshotID != result.SnapshotID || violated[0].Violations[0].Route != "192.0.2.0/24" {
+		t.Errorf("AssertionsViolated events = %+v", violated)
+	}
+}
+
+func TestRunnerCountsChanges(t *testing.T) {
+	runner, stateMgr := newTestRunner(t)
+	ctx := context.Background()
+
+	client := api.NewMemoryClient("RADB", runner.logger)
+	client.Login(ctx, "user", "password")
+	kept := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"Kept"}, MntBy: []string{"MAINT-A"}, Source: "RADB"}
+	removed := &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}
+	for _, route := range []*models.RouteObject{kept, removed} {
+		if err := client.CreateRoute(ctx, route); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runner = NewRunner(client, stateMgr, state.NewHistoryManager(t.TempDir(), runner.logger), runner.logger)
+
+	if _, err := runner.Check(ctx); err != nil {
+		t.Fatalf("baseline Check() failed: %v", err)
+	}
+
+	kept.Descr = []string{"Changed"}
+	if err := client.UpdateRoute(ctx, kept); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteRoute(ctx, removed.Route, removed.Origin); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateRoute(ctx, &models.RouteObject{Route: "203.0.113.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := runner.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if result.Changes != 3 {
+		t.Errorf("Changes = %d, want 3", result.Changes)
+	}
+	for _, changeType := range []models.ChangeType{models.ChangeTypeAdded, models.ChangeTypeRemoved, models.ChangeTypeModified} {
+		if result.Summary[changeType] != 1 {
+			t.Errorf("Summary[%s] = %d, want 1", changeType, result.Summary[changeType])
+		}
+	}
+}
+
+func TestRunnerReportsDriftFromBaseline(t *testing.T) {
+	runner, stateMgr := newTestRunner(t)
+	ctx := context.Background()
+
+	client := api.NewMemoryClient("RADB", runner.logger)
+	client.Login(ctx, "user", "password")
+	addRoute := func(prefix string) {
+		t.Helper()
+		if err := client.CreateRoute(ctx, &models.RouteObject{Route: prefix, Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addRoute("192.0.2.0/24")
+	runner = NewRunner(client, stateMgr, state.NewHistoryManager(t.TempDir(), runner.logger), runner.logger)
+
+	bus := events.NewBus()
+	var detected []*events.ChangesDetected
+	bus.Subscribe(func(event events.Event) {
+		if changes, ok := event.(*events.ChangesDetected); ok {
+			detected = append(detected, changes)
+		}
+	})
+	runner.SetEventBus(bus)
+
+	first, err := runner.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if err := state.SetBaseline(ctx, stateMgr, "golden", first.SnapshotID); err != nil {
+		t.Fatal(err)
+	}
+	runner.SetBaseline("golden")
+
+	addRoute("198.51.100.0/24")
+	if _, err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	addRoute("203.0.113.0/24")
+	result, err := runner.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+
+	if result.Changes != 1 || result.Drift != 2 || result.BaselineID != first.SnapshotID {
+		t.Errorf("Check() = %d changes and %d drift from %s, want 1 and 2 from %s", result.Changes, result.Drift, result.BaselineID, first.SnapshotID)
+	}
+	if len(detected) != 2 {
+		t.Fatalf("published %d change events, want 2", len(detected))
+	}
+	last := detected[1]
+	if last.Baseline != "golden" || last.PreviousID != first.SnapshotID || len(last.Changes.Changes) != 2 {
+		t.Errorf("event reports %d changes since %s (baseline %q), want 2 since %s (golden)", len(last.Changes.Changes), last.PreviousID, last.Baseline, first.SnapshotID)
+	}
+
+	// A quiet check reports nothing, although the drift remains
+	if _, err := runner.Check(ctx); err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if len(detected) != 2 {
+		t.Errorf("a quiet check published a change event")
+	}
+}
+
+func TestRunnerChecksTargets(t *testing.T) {
+	runner, stateMgr := newTestRunner(t)
+	ctx := context.Background()
+
+	client := api.NewMemoryClient("RADB", runner.logger)
+	client.Login(ctx, "user", "password")
+	for _, route := range []*models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"},
+		{Route: "198.51.100.0/24", Origin: "AS64501", MntBy: []string{"MAINT-B"}, Source: "RADB"},
+	} {
+		if err := client.CreateRoute(ctx, route); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runner = NewRunner(client, stateMgr, state.NewHistoryManager(t.TempDir(), runner.logger), runner.logger)
+
+	bus := events.NewBus()
+	var detected []*events.ChangesDetected
+	bus.Subscribe(func(event events.Event) {
+		if e, ok := event.(*events.ChangesDetected); ok {
+			detected = append(detected, e)
+		}
+	})
+	runner.SetEventBus(bus)
+
+	a := Target{Name: "a", Filters: map[string]string{"mnt-by": "MAINT-A"}, Retention: 2}
+	b := Target{Name: "b", Filters: map[string]string{"origin": "AS64501"}}
+	runner.SetTargets([]Target{a, b})
+
+	for _, target := range []Target{a, b} {
+		result, err := runner.CheckTarget(ctx, target)
+		if err != nil {
+			t.Fatalf("CheckTarget(%s) failed: %v", target.Name, err)
+		}
+		if result.RouteCount != 1 || result.Target != target.Name {
+			t.Errorf("CheckTarget(%s) = %+v, want one route", target.Name, result)
+		}
+	}
+
+	// Only the target whose routes changed reports changes, against its
+	// own previous snapshot
+	if err := client.CreateRoute(ctx, &models.RouteObject{Route: "203.0.113.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		for _, target := range []Target{a, b} {
+			if _, err := runner.CheckTarget(ctx, target); err != nil {
+				t.Fatalf("CheckTarget(%s) failed: %v", target.Name, err)
+			}
+		}
+	}
+	if len(detected) != 1 || detected[0].Target != "a" || detected[0].Summary[models.ChangeTypeAdded] != 1 {
+		t.Errorf("ChangesDetected events = %+v, want one addition for target a", detected)
+	}
+
+	// Each target keeps its own retention, and cleanups leave them alone
+	if _, err := runner.Cleanup(ctx); err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+	counts := map[string]int{}
+	snapshots, err := stateMgr.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, snapshot := range snapshots {
+		counts[snapshot.Scope()]++
+	}
+	if got := counts[models.FilterScope(a.Filters)]; got != 2 {
+		t.Errorf("target a kept %d snapshots, want 2", got)
+	}
+	if got := counts[models.FilterScope(b.Filters)]; got != 3 {
+		t.Errorf("target b kept %d snapshots, want 3", got)
+	}
+}
+
+func TestRunnerSetClient(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	ctx := context.Background()
+
+	client := api.NewMemoryClient("RADB", runner.logger)
+	client.Login(ctx, "user", "password")
+	for _, prefix := range []string{"192.0.2.0/24", "198.51.100.0/24"} {
+		if err := client.CreateRoute(ctx, &models.RouteObject{Route: prefix, Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, err := runner.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	runner.SetClient(client)
+	second, err := runner.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() after SetClient failed: %v", err)
+	}
+
+	if first.RouteCount != 1 || second.RouteCount != 2 {
+		t.Errorf("route counts = %d, %d; want 1 from the first client, then 2 from the second", first.RouteCount, second.RouteCount)
+	}
+	if second.Summary[models.ChangeTypeAdded] != 1 {
+		t.Errorf("summary = %v, want the added route", second.Summary)
+	}
+}
@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+)
+
+// RuntimeState keeps the daemon's runtime state in the state directory, so
+// a restarted daemon compares its first check against the snapshot its
+// last check saved, counts its data as fresh from the last successful
+// check, and continues its failure backoff.
+type RuntimeState struct {
+	stateDir string
+	logger   *logrus.Logger
+
+	mu    sync.Mutex
+	state models.DaemonState
+}
+
+// LoadRuntimeState loads the runtime state the last daemon recorded in
+// stateDir. A missing or unreadable record starts afresh.
+func LoadRuntimeState(stateDir string, logger *logrus.Logger) *RuntimeState {
+	s := &RuntimeState{stateDir: stateDir, logger: logger}
+	recorded, err := state.LoadDaemonState(stateDir)
+	if err != nil {
+		logger.Warnf("Ignoring recorded daemon state: %v", err)
+	} else if recorded != nil {
+		s.state = *recorded
+		if recorded.LastCheckAt != nil {
+			logger.Infof("Resuming from the daemon state recorded at %s", recorded.LastCheckAt.Format(time.RFC3339))
+		}
+	}
+	if s.state.Scopes == nil {
+		s.state.Scopes = make(map[string]*models.CheckState)
+	}
+	return s
+}
+
+// State returns a copy of the recorded state.
+func (s *RuntimeState) State() models.DaemonState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := s.state
+	copied.Scopes = make(map[string]*models.CheckState, len(s.state.Scopes))
+	for scope, check := range s.state.Scopes {
+		c := *check
+		copied.Scopes[scope] = &c
+	}
+	return copied
+}
+
+// Failures returns how many of the daemon loop's checks have failed in a
+// row and since when.
+func (s *RuntimeState) Failures() (int, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var since time.Time
+	if s.state.FailingSince != nil {
+		since = *s.state.FailingSince
+	}
+	return s.state.ConsecutiveFailures, since
+}
+
+// SetFailures records how many of the daemon loop's checks have failed in
+// a row and since when; zero failures clears the record.
+func (s *RuntimeState) SetFailures(failures int, since time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state.ConsecutiveFailures = failures
+	s.state.FailingSince = nil
+	if failures > 0 {
+		s.state.FailingSince = &since
+	}
+	s.save()
+}
+
+// lastSnapshotID returns the snapshot the last successful check of scope
+// saved, or "".
+func (s *RuntimeState) lastSnapshotID(scope string) string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if check, ok := s.state.Scopes[scope]; ok {
+		return check.LastSnapshotID
+	}
+	return ""
+}
+
+// record notes a finished check of scope, which saved snapshotID if it
+// succeeded. It is safe on a nil receiver.
+func (s *RuntimeState) record(scope, target, snapshotID string, err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	check, ok := s.state.Scopes[scope]
+	if !ok {
+		check = &models.CheckState{}
+		s.state.Scopes[scope] = check
+	}
+	check.Target = target
+	check.LastCheckAt = &now
+	s.state.LastCheckAt = &now
+	if err != nil {
+		check.ConsecutiveFailures++
+		s.state.LastError = err.Error()
+	} else {
+		check.LastSnapshotID = snapshotID
+		check.ConsecutiveFailures = 0
+		s.state.LastSuccessAt = &now
+		s.state.LastError = ""
+	}
+	s.save()
+}
+
+// save writes the state to the state directory. Failures are logged, since
+// the daemon keeps running without a record to resume from.
+func (s *RuntimeState) save() {
+	if err := state.SaveDaemonState(s.stateDir, &s.state); err != nil {
+		s.logger.Warnf("Failed to record daemon state: %v", err)
+	}
+}
+
+// SetRuntimeState makes the runner compare each check against the snapshot
+// the previous check recorded in s saved, and record its checks in s.
+func (r *Runner) SetRuntimeState(s *RuntimeState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runtime = s
+}
+
+// RuntimeState returns the state set with SetRuntimeState, or nil.
+func (r *Runner) RuntimeState() *RuntimeState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runtime
+}
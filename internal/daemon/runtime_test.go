@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+)
+
+func TestRuntimeStateResumes(t *testing.T) {
+	runner, stateMgr := newTestRunner(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	runner.SetRuntimeState(LoadRuntimeState(dir, runner.logger))
+
+	first, err := runner.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+
+	// Another command saves a snapshot of different routes in between
+	other := models.NewScopedSnapshot(models.SnapshotTypeRoute, "manual", nil)
+	other.Routes = models.NewRouteList([]models.RouteObject{{Route: "198.51.100.0/24", Origin: "AS64501", Source: "RADB"}})
+	if err := stateMgr.SaveSnapshot(ctx, other); err != nil {
+		t.Fatalf("SaveSnapshot() failed: %v", err)
+	}
+	runner.RuntimeState().SetFailures(2, time.Now().Add(-time.Hour))
+
+	// A restarted daemon compares against its own last snapshot
+	restarted := NewRunner(runner.client, stateMgr, state.NewHistoryManager(dir, runner.logger), runner.logger)
+	resumed := LoadRuntimeState(dir, runner.logger)
+	restarted.SetRuntimeState(resumed)
+
+	second, err := restarted.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() after restart failed: %v", err)
+	}
+	if second.PreviousID != first.SnapshotID || second.Changes != 0 {
+		t.Errorf("check after restart compared against %s with %d changes, want %s with none", second.PreviousID, second.Changes, first.SnapshotID)
+	}
+
+	if failures, since := resumed.Failures(); failures != 2 || since.IsZero() {
+		t.Errorf("Failures() = %d, %v; want the 2 recorded before the restart", failures, since)
+	}
+	recorded := resumed.State()
+	if recorded.LastSuccessAt == nil || recorded.Scopes[""].LastSnapshotID != second.SnapshotID {
+		t.Errorf("state = %+v, want the last successful check and its snapshot", recorded)
+	}
+
+	// A pruned snapshot falls back to the latest
+	if err := stateMgr.DeleteSnapshot(ctx, second.SnapshotID); err != nil {
+		t.Fatalf("DeleteSnapshot() failed: %v", err)
+	}
+	third, err := restarted.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() after pruning failed: %v", err)
+	}
+	if third.PreviousID != other.ID {
+		t.Errorf("check after pruning compared against %s, want the latest snapshot %s", third.PreviousID, other.ID)
+	}
+
+	// Health counts data as fresh from the recorded check
+	health := NewHealth(runner.client, dir, time.Hour)
+	health.Restore(resumed.State())
+	if report := health.Report(); report.LastSuccessAt == nil {
+		t.Errorf("health report = %+v, want the recorded successful check", report)
+	}
+}
@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// AdaptiveSchedule adjusts the interval between scheduled checks to the
+// rate of change: a check that finds changes drops the interval to min
+// (burst mode), so a change storm is followed closely, and each quiet check
+// after that stretches it by growth, up to max, reducing API load.
+type AdaptiveSchedule struct {
+	min, max time.Duration
+	growth   float64
+	current  time.Duration
+}
+
+// NewAdaptiveSchedule creates a schedule that starts at interval, clamped
+// to [min, max]. A growth of 1 or less keeps quiet intervals unchanged.
+func NewAdaptiveSchedule(interval, min, max time.Duration, growth float64) *AdaptiveSchedule {
+	s := &AdaptiveSchedule{min: min, max: max, growth: growth}
+	s.current = s.clamp(interval)
+	return s
+}
+
+// Interval returns the current interval.
+func (s *AdaptiveSchedule) Interval() time.Duration {
+	return s.current
+}
+
+// Next returns the interval until the next check after a check cycle.
+// Failed cycles leave the interval unchanged.
+func (s *AdaptiveSchedule) Next(result *CheckResult, err error) time.Duration {
+	switch {
+	case err != nil || result == nil:
+	case result.Changes > 0:
+		s.current = s.min
+	case s.growth > 1:
+		s.current = s.clamp(time.Duration(float64(s.current) * s.growth))
+	}
+	return s.current
+}
+
+// clamp limits an interval to the schedule's bounds.
+func (s *AdaptiveSchedule) clamp(d time.Duration) time.Duration {
+	return min(max(d, s.min), s.max)
+}
+
+// FailureBackoff spaces out checks while they keep failing, so an API
+// outage is not polled at the usual cadence: the first retry follows after
+// initial, and each further failure multiplies the delay by factor, up to
+// max. Jitter randomizes each delay by up to that fraction either way, so
+// daemons sharing an API do not retry in step. A successful check resets
+// it.
+type FailureBackoff struct {
+	initial, max time.Duration
+	factor       float64
+	jitter       float64
+	failures     int
+	since        time.Time // When the current run of failures began
+	random       func() float64
+}
+
+// NewFailureBackoff creates a backoff that waits initial after the first
+// failure and at most maxDelay after later ones. A factor of 1 or less
+// retries at a fixed delay of initial; jitter is clamped to [0, 1].
+func NewFailureBackoff(initial, maxDelay time.Duration, factor, jitter float64) *FailureBackoff {
+	return &FailureBackoff{
+		initial: initial,
+		max:     maxDelay,
+		factor:  factor,
+		jitter:  min(max(jitter, 0), 1),
+		random:  rand.Float64,
+	}
+}
+
+// Failure records a failed check and returns the delay before the next
+// attempt.
+func (b *FailureBackoff) Failure() time.Duration {
+	if b.failures == 0 {
+		b.since = time.Now()
+	}
+	b.failures++
+
+	delay := float64(b.initial)
+	if b.factor > 1 {
+		delay *= math.Pow(b.factor, float64(b.failures-1))
+	}
+	delay = min(delay, float64(b.max))
+	delay *= 1 + b.jitter*(2*b.random()-1)
+	return min(time.Duration(delay), b.max)
+}
+
+// Success records a successful check. It returns how many consecutive
+// checks failed before it and when the first of them did, for logging the
+// recovery; failures is 0 when the previous check succeeded too.
+func (b *FailureBackoff) Success() (failures int, since time.Time) {
+	failures, since = b.failures, b.since
+	b.failures, b.since = 0, time.Time{}
+	return failures, since
+}
+
+// Failures returns the number of consecutive failed checks and when the
+// first of them failed.
+func (b *FailureBackoff) Failures() (int, time.Time) {
+	return b.failures, b.since
+}
+
+// Restore continues from failures consecutive failed checks, the first at
+// since, such as those recorded before a restart.
+func (b *FailureBackoff) Restore(failures int, since time.Time) {
+	b.failures, b.since = failures, since
+}
@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveSchedule(t *testing.T) {
+	s := NewAdaptiveSchedule(time.Hour, 5*time.Minute, 4*time.Hour, 2)
+	if got := s.Interval(); got != time.Hour {
+		t.Fatalf("Initial interval = %s, want 1h", got)
+	}
+
+	steps := []struct {
+		name   string
+		result *CheckResult
+		err    error
+		want   time.Duration
+	}{
+		{"quiet check grows the interval", &CheckResult{}, nil, 2 * time.Hour},
+		{"growth stops at max", &CheckResult{}, nil, 4 * time.Hour},
+		{"still at max", &CheckResult{}, nil, 4 * time.Hour},
+		{"changes enter burst mode", &CheckResult{Changes: 3}, nil, 5 * time.Minute},
+		{"failures keep the interval", nil, errors.New("API down"), 5 * time.Minute},
+		{"quiet checks back off again", &CheckResult{}, nil, 10 * time.Minute},
+	}
+	for _, step := range steps {
+		if got := s.Next(step.result, step.err); got != step.want {
+			t.Errorf("%s: Next() = %s, want %s", step.name, got, step.want)
+		}
+	}
+
+	// The starting interval is clamped to the bounds
+	if got := NewAdaptiveSchedule(time.Minute, 5*time.Minute, time.Hour, 2).Interval(); got != 5*time.Minute {
+		t.Errorf("Clamped interval = %s, want 5m", got)
+	}
+}
+
+func TestFailureBackoff(t *testing.T) {
+	b := NewFailureBackoff(time.Minute, 10*time.Minute, 2, 0)
+
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute} {
+		if got := b.Failure(); got != want {
+			t.Errorf("failure %d: delay = %s, want %s", i+1, got, want)
+		}
+	}
+	if got, _ := b.Failures(); got != 6 {
+		t.Errorf("Failures() = %d, want 6", got)
+	}
+
+	failures, since := b.Success()
+	if failures != 6 || since.IsZero() {
+		t.Errorf("Success() = %d, %v; want 6 failures and when they began", failures, since)
+	}
+	if failures, _ := b.Success(); failures != 0 {
+		t.Errorf("second Success() = %d failures, want 0", failures)
+	}
+	if got := b.Failure(); got != time.Minute {
+		t.Errorf("delay after recovery = %s, want the initial 1m", got)
+	}
+
+	// A restored backoff continues where it left off
+	began := time.Now().Add(-time.Hour)
+	b.Restore(3, began)
+	if got := b.Failure(); got != 8*time.Minute {
+		t.Errorf("delay after restoring 3 failures = %s, want 8m", got)
+	}
+	if failures, since := b.Failures(); failures != 4 || !since.Equal(began) {
+		t.Errorf("Failures() = %d, %v; want 4 since %v", failures, since, began)
+	}
+
+	// Jitter spreads delays either way but never past the cap
+	jittered := NewFailureBackoff(time.Minute, 90*time.Second, 2, 0.5)
+	jittered.random = func() float64 { return 0 }
+	if got := jittered.Failure(); got != 30*time.Second {
+		t.Errorf("lowest jittered delay = %s, want 30s", got)
+	}
+	jittered.random = func() float64 { return 1 }
+	if got := jittered.Failure(); got != 90*time.Second {
+		t.Errorf("highest jittered delay = %s, want the 90s cap", got)
+	}
+}
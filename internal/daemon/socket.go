@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// socketRequestTimeout bounds a CLI read over the daemon's local socket, so
+// a hung daemon falls back to the API quickly.
+const socketRequestTimeout = 10 * time.Second
+
+// ListenSocket listens on a Unix socket at path that only the current user
+// can connect to. A socket left behind by a daemon that died is replaced;
+// callers hold the daemon lock, so no live daemon is listening on it.
+func ListenSocket(path string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return listener, nil
+}
+
+// Served describes the snapshot a daemon answered a read from.
+type Served struct {
+	SnapshotID string
+	Age        time.Duration
+}
+
+// SocketClient reads routes and contacts from a running daemon over its
+// local socket. Reads are answered from the daemon's snapshots with its
+// authenticated client, so CLI commands need no API requests of their own.
+type SocketClient struct {
+	http *http.Client
+}
+
+// NewSocketClient creates a client for the daemon socket at path.
+func NewSocketClient(path string) *SocketClient {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}
+	return &SocketClient{http: &http.Client{Transport: transport, Timeout: socketRequestTimeout}}
+}
+
+// socketRouteFilters are the route filters the read API applies.
+var socketRouteFilters = map[string]bool{"prefix": true, "origin": true, "mnt-by": true}
+
+// ListRoutes returns the daemon's routes matching filters.
+func (c *SocketClient) ListRoutes(ctx context.Context, filters map[string]string) (*models.RouteList, *Served, error) {
+	query := url.Values{}
+	for key, value := range filters {
+		if !socketRouteFilters[key] {
+			return nil, nil, fmt.Errorf("filter %q is not supported by the daemon", key)
+		}
+		query.Set(key, value)
+	}
+
+	var routes models.RouteList
+	served, err := c.get(ctx, "/api/routes?"+query.Encode(), &routes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &routes, served, nil
+}
+
+// ListContacts returns the daemon's contacts.
+func (c *SocketClient) ListContacts(ctx context.Context) (*models.ContactList, *Served, error) {
+	var contacts models.ContactList
+	served, err := c.get(ctx, "/api/contacts", &contacts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &contacts, served, nil
+}
+
+// get reads a JSON response from the daemon into v.
+func (c *SocketClient) get(ctx context.Context, path string, v interface{}) (*Served, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://daemon"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach daemon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return nil, fmt.Errorf("daemon returned %d: %s", resp.StatusCode, body.Error)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, fmt.Errorf("failed to decode daemon response: %w", err)
+	}
+
+	age, _ := strconv.Atoi(resp.Header.Get("Age"))
+	return &Served{
+		SnapshotID: resp.Header.Get("X-Radb-Snapshot"),
+		Age:        time.Duration(age) * time.Second,
+	}, nil
+}
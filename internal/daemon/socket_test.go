@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSocketClient(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	cache := NewReadCache(runner, time.Hour, runner.logger)
+
+	path := filepath.Join(t.TempDir(), "daemon.sock")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stale socket file is replaced
+	listener, err := ListenSocket(path)
+	if err != nil {
+		t.Fatalf("ListenSocket() failed: %v", err)
+	}
+	server := &http.Server{Handler: NewLocalReadHandler(cache, runner.logger)}
+	go server.Serve(listener)
+	defer server.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket permissions = %o, want 600", perm)
+	}
+
+	ctx := context.Background()
+	client := NewSocketClient(path)
+
+	routes, served, err := client.ListRoutes(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListRoutes() failed: %v", err)
+	}
+	if routes.Count != 1 || served.SnapshotID == "" {
+		t.Errorf("ListRoutes() = %d routes from %q, want 1 route from a snapshot", routes.Count, served.SnapshotID)
+	}
+
+	routes, _, err = client.ListRoutes(ctx, map[string]string{"origin": "AS64501"})
+	if err != nil || routes.Count != 0 {
+		t.Errorf("filtered ListRoutes() = %v, %v; want no routes", routes, err)
+	}
+
+	if _, _, err := client.ListRoutes(ctx, map[string]string{"source": "RADB"}); err == nil {
+		t.Error("ListRoutes() accepted a filter the daemon does not apply")
+	}
+
+	if _, _, err := NewSocketClient(filepath.Join(t.TempDir(), "missing.sock")).ListContacts(ctx); err == nil {
+		t.Error("ListContacts() succeeded without a daemon")
+	}
+}
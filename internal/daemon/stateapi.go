@@ -0,0 +1,229 @@
+package daemon
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/pkg/ratelimit"
+	"github.com/bss/radb-client/pkg/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// StateHandler serves the snapshots and changelog the daemon collects.
+//
+// Routes:
+//
+//	GET  /api/snapshots        snapshot listings, filtered by the type, scope, tag, since, until, and limit query parameters
+//	GET  /api/snapshots/{id}   a snapshot with its data
+//	GET  /api/diff             changes between the from and to snapshots
+//	GET  /api/history          changelog entries, filtered by the since, until, type, and limit query parameters
+//	POST /api/check            run a check cycle immediately
+//
+// Times are RFC 3339 timestamps, dates, or ages such as 24h or 7d.
+// Callers authenticate with "Authorization: Bearer <secret>".
+type StateHandler struct {
+	runner *Runner
+	secret []byte
+	logger *logrus.Logger
+	mux    *http.ServeMux
+}
+
+// StateAPIPaths are the paths StateHandler serves, for registering it
+// alongside other handlers under /api/.
+var StateAPIPaths = []string{"/api/snapshots", "/api/snapshots/", "/api/diff", "/api/history", "/api/check"}
+
+// NewStateHandler creates a state handler authenticated by secret.
+func NewStateHandler(runner *Runner, secret string, logger *logrus.Logger) *StateHandler {
+	h := &StateHandler{
+		runner: runner,
+		secret: []byte(secret),
+		logger: logger,
+		mux:    http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /api/snapshots", h.snapshots)
+	h.mux.HandleFunc("GET /api/snapshots/{id}", h.snapshot)
+	h.mux.HandleFunc("GET /api/diff", h.diff)
+	h.mux.HandleFunc("GET /api/history", h.history)
+	h.mux.HandleFunc("POST /api/check", h.check)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *StateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !authorized(h.secret, r, nil) {
+		h.logger.Warnf("Rejected unauthenticated state request from %s", r.RemoteAddr)
+		writeJSONError(w, http.StatusUnauthorized, "invalid or missing credentials")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *StateHandler) snapshots(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := state.SnapshotQuery{
+		Type:  models.SnapshotType(params.Get("type")),
+		Scope: params.Get("scope"),
+	}
+	if tag := params.Get("tag"); tag != "" {
+		query.Tags = []string{tag}
+	}
+
+	var err error
+	if query.Since, err = queryTime(params.Get("since")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid since: %v", err))
+		return
+	}
+	if query.Until, err = queryTime(params.Get("until")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid until: %v", err))
+		return
+	}
+	if query.Limit, err = queryLimit(params.Get("limit")); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snapshots, err := h.runner.stateMgr.QuerySnapshots(r.Context(), query)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshots)
+}
+
+func (h *StateHandler) snapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := h.load(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+func (h *StateHandler) diff(w http.ResponseWriter, r *http.Request) {
+	fromID, toID := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if fromID == "" || toID == "" {
+		writeJSONError(w, http.StatusBadRequest, "from and to snapshot IDs are required")
+		return
+	}
+
+	from, ok := h.load(w, r, fromID)
+	if !ok {
+		return
+	}
+	to, ok := h.load(w, r, toID)
+	if !ok {
+		return
+	}
+	if from.Type != to.Type {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("cannot compare a %s snapshot with a %s snapshot", from.Type, to.Type))
+		return
+	}
+
+	changes, err := h.runner.stateMgr.ComputeChanges(r.Context(), from, to)
+	if err != nil {
+		h.logger.Errorf("Failed to compare %s with %s: %v", from.ID, to.ID, err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, changes)
+}
+
+func (h *StateHandler) history(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	since, err := queryTime(params.Get("since"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid since: %v", err))
+		return
+	}
+	until, err := queryTime(params.Get("until"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid until: %v", err))
+		return
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	limit, err := queryLimit(params.Get("limit"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := h.runner.history.QueryChanges(r.Context(), since, until, params.Get("type"))
+	if err != nil {
+		h.logger.Errorf("Failed to query changelog: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if entries == nil {
+		entries = []models.ChangelogEntry{}
+	}
+	// Entries are oldest first; the limit keeps the newest
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+func (h *StateHandler) check(w http.ResponseWriter, r *http.Request) {
+	h.logger.Infof("State API triggered a check from %s", r.RemoteAddr)
+
+	ctx, span := tracing.StartKind(tracing.Extract(r.Context(), r.Header), "api check", tracing.KindServer)
+	ctx = ratelimit.WithJob(ctx, JobWebhook)
+	result, err := h.runner.Check(ctx)
+	span.EndErr(err)
+	if err != nil {
+		h.logger.Errorf("Triggered check failed: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// load loads a snapshot, writing an error response if it does not exist.
+func (h *StateHandler) load(w http.ResponseWriter, r *http.Request, id string) (*models.Snapshot, bool) {
+	snapshot, err := h.runner.stateMgr.LoadSnapshot(r.Context(), id)
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, fmt.Sprintf("snapshot %s not found", id))
+		return nil, false
+	}
+	return snapshot, true
+}
+
+// queryTime parses a timestamp, date, or age ("24h", "7d") query
+// parameter. An empty value is the zero time.
+func queryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return time.Now().Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a timestamp, date, or age", value)
+}
+
+// queryLimit parses a non-negative limit query parameter.
+func queryLimit(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid limit %q", value)
+	}
+	return limit, nil
+}
@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func stateRequest(t *testing.T, handler http.Handler, method, target string, v interface{}) int {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer "+testSecret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code == http.StatusOK && v != nil {
+		if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+			t.Fatalf("failed to decode %s: %v", target, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestStateHandler(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	handler := NewStateHandler(runner, testSecret, runner.logger)
+
+	// Two checks through the API give two snapshots to compare
+	var results [2]CheckResult
+	for i := range results {
+		if code := stateRequest(t, handler, http.MethodPost, "/api/check", &results[i]); code != http.StatusOK {
+			t.Fatalf("POST /api/check = %d, want 200", code)
+		}
+	}
+
+	var snapshots []models.Snapshot
+	if code := stateRequest(t, handler, http.MethodGet, "/api/snapshots?type=route&since=1h&limit=1", &snapshots); code != http.StatusOK {
+		t.Fatalf("GET /api/snapshots = %d, want 200", code)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != results[1].SnapshotID {
+		t.Errorf("snapshots = %+v, want only %s", snapshots, results[1].SnapshotID)
+	}
+
+	var snapshot models.Snapshot
+	if code := stateRequest(t, handler, http.MethodGet, "/api/snapshots/"+results[0].SnapshotID, &snapshot); code != http.StatusOK {
+		t.Fatalf("GET /api/snapshots/{id} = %d, want 200", code)
+	}
+	if snapshot.Routes == nil || snapshot.Routes.Count != 1 {
+		t.Errorf("snapshot = %+v, want its route", snapshot)
+	}
+
+	var changes models.ChangeSet
+	target := "/api/diff?from=" + results[0].SnapshotID + "&to=" + results[1].SnapshotID
+	if code := stateRequest(t, handler, http.MethodGet, target, &changes); code != http.StatusOK {
+		t.Fatalf("GET /api/diff = %d, want 200", code)
+	}
+	if !changes.IsEmpty() {
+		t.Errorf("diff = %+v, want no changes", changes.Changes)
+	}
+
+	var entries []models.ChangelogEntry
+	if code := stateRequest(t, handler, http.MethodGet, "/api/history?since=7d&type=route", &entries); code != http.StatusOK {
+		t.Fatalf("GET /api/history = %d, want 200", code)
+	}
+	if len(entries) != 0 {
+		t.Errorf("history = %+v, want no entries", entries)
+	}
+
+	for _, tt := range []struct {
+		target string
+		want   int
+	}{
+		{"/api/snapshots/route-missing", http.StatusNotFound},
+		{"/api/snapshots?since=yesterday", http.StatusBadRequest},
+		{"/api/diff?from=" + results[0].SnapshotID, http.StatusBadRequest},
+		{"/api/history?limit=-1", http.StatusBadRequest},
+	} {
+		if code := stateRequest(t, handler, http.MethodGet, tt.target, nil); code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.target, code, tt.want)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/snapshots", nil)
+	unauthorized := httptest.NewRecorder()
+	handler.ServeHTTP(unauthorized, req)
+	if unauthorized.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated GET = %d, want 401", unauthorized.Code)
+	}
+}
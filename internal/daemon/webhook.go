@@ -0,0 +1,161 @@
+package daemon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bss/radb-client/pkg/ratelimit"
+	"github.com/bss/radb-client/pkg/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureHeader carries the hex-encoded HMAC-SHA256 of the request body,
+// prefixed with "sha256=".
+const SignatureHeader = "X-Radb-Signature"
+
+// maxWebhookBody bounds the size of accepted webhook payloads.
+const maxWebhookBody = 64 * 1024
+
+// WebhookRequest is the optional JSON body of a webhook call.
+type WebhookRequest struct {
+	Filters map[string]string `json:"filters,omitempty"` // Route filters for targeted snapshots
+	Note    string            `json:"note,omitempty"`    // Snapshot note
+}
+
+// WebhookHandler triggers monitoring cycles from authenticated HTTP calls.
+//
+// Routes:
+//
+//	POST /webhooks/check      run a check cycle immediately
+//	POST /webhooks/snapshot   save a (optionally filtered) snapshot
+//	POST /webhooks/reconcile  run a check cycle and apply snapshot retention
+//
+// Callers authenticate with either "Authorization: Bearer <secret>" or an
+// X-Radb-Signature header containing the HMAC-SHA256 of the body.
+type WebhookHandler struct {
+	runner *Runner
+	secret []byte
+	logger *logrus.Logger
+	mux    *http.ServeMux
+}
+
+// NewWebhookHandler creates a webhook handler authenticated by secret.
+func NewWebhookHandler(runner *Runner, secret string, logger *logrus.Logger) *WebhookHandler {
+	h := &WebhookHandler{
+		runner: runner,
+		secret: []byte(secret),
+		logger: logger,
+		mux:    http.NewServeMux(),
+	}
+	h.mux.HandleFunc("POST /webhooks/{action}", h.handle)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *WebhookHandler) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	if len(body) > maxWebhookBody {
+		writeJSONError(w, http.StatusRequestEntityTooLarge, "payload too large")
+		return
+	}
+
+	if !authorized(h.secret, r, body) {
+		h.logger.Warnf("Rejected unauthenticated webhook from %s", r.RemoteAddr)
+		writeJSONError(w, http.StatusUnauthorized, "invalid or missing credentials")
+		return
+	}
+
+	var req WebhookRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+			return
+		}
+	}
+
+	action := r.PathValue("action")
+	h.logger.Infof("Webhook triggered %s from %s", action, r.RemoteAddr)
+
+	// Continue the caller's trace, if it sent one
+	ctx, span := tracing.StartKind(tracing.Extract(r.Context(), r.Header), "webhook "+action, tracing.KindServer)
+	defer func() { span.EndErr(err) }()
+	ctx = ratelimit.WithJob(ctx, JobWebhook)
+
+	var result interface{}
+	switch action {
+	case "check":
+		result, err = h.runner.Check(ctx)
+	case "snapshot":
+		note := req.Note
+		if note == "" {
+			note = "Webhook snapshot"
+		}
+		result, err = h.runner.Snapshot(ctx, req.Filters, note)
+	case "reconcile":
+		result, err = h.runner.Reconcile(ctx)
+	default:
+		writeJSONError(w, http.StatusNotFound, fmt.Sprintf("unknown action %q", action))
+		return
+	}
+
+	if err != nil {
+		h.logger.Errorf("Webhook %s failed: %v", action, err)
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// authorized checks the bearer token or body signature in constant time.
+func authorized(secret []byte, r *http.Request, body []byte) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return subtle.ConstantTimeCompare([]byte(token), secret) == 1
+	}
+
+	if sig, ok := strings.CutPrefix(r.Header.Get(SignatureHeader), "sha256="); ok {
+		got, err := hex.DecodeString(sig)
+		if err != nil {
+			return false
+		}
+		return hmac.Equal(got, Sign(secret, body))
+	}
+
+	return false
+}
+
+// Sign returns the HMAC-SHA256 of body keyed by secret.
+func Sign(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
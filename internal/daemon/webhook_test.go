@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bss/radb-client/internal/api"
+	"github.com/bss/radb-client/internal/api/apitest"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+	"github.com/sirupsen/logrus"
+)
+
+const testSecret = "s3cret"
+
+func newTestRunner(t *testing.T) (*Runner, state.Manager) {
+	t.Helper()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	server := apitest.NewServer()
+	t.Cleanup(server.Close)
+	server.AddRoute(models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"})
+
+	client := api.NewHTTPClient(server.URL, "RADB", 5, logger)
+	if err := client.Login(context.Background(), "user", "password"); err != nil {
+		t.Fatalf("Login() failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	stateMgr, err := state.NewFileManager(dir, logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	t.Cleanup(func() { stateMgr.Close() })
+
+	return NewRunner(client, stateMgr, state.NewHistoryManager(dir, logger), logger), stateMgr
+}
+
+func TestWebhookAuthentication(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	handler := NewWebhookHandler(runner, testSecret, runner.logger)
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{"missing credentials", "", ""},
+		{"wrong bearer token", "Authorization", "Bearer wrong"},
+		{"bad signature", SignatureHeader, "sha256=" + hex.EncodeToString(Sign([]byte("wrong"), nil))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/check", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Expected 401, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestWebhookCheck(t *testing.T) {
+	runner, stateMgr := newTestRunner(t)
+	handler := NewWebhookHandler(runner, testSecret, runner.logger)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/check", nil)
+	req.Header.Set("Authorization", "Bearer "+testSecret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var result CheckResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.RouteCount != 1 {
+		t.Errorf("Expected 1 route, got %d", result.RouteCount)
+	}
+
+	if _, err := stateMgr.LoadSnapshot(context.Background(), result.SnapshotID); err != nil {
+		t.Errorf("Expected snapshot %s to be saved: %v", result.SnapshotID, err)
+	}
+}
+
+func TestWebhookSignedSnapshot(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	handler := NewWebhookHandler(runner, testSecret, runner.logger)
+
+	body := []byte(`{"filters":{"origin":"AS64500"},"note":"CI deploy"}`)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/snapshot", bytes.NewReader(body))
+	req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(Sign([]byte(testSecret), body)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var snapshot models.Snapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if snapshot.Scope() != "origin=AS64500" {
+		t.Errorf("Expected scoped snapshot, got scope %q", snapshot.Scope())
+	}
+	if snapshot.Note != "CI deploy" {
+		t.Errorf("Expected note %q, got %q", "CI deploy", snapshot.Note)
+	}
+}
+
+func TestWebhookUnknownAction(t *testing.T) {
+	runner, _ := newTestRunner(t)
+	handler := NewWebhookHandler(runner, testSecret, runner.logger)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/explode", nil)
+	req.Header.Set("Authorization", "Bearer "+testSecret)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,228 @@
+// Package events provides an in-process bus for monitoring events, so
+// embedders and long-running modes can react to changes without polling
+// the state directory.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// Type identifies the kind of an event.
+type Type string
+
+const (
+	// TypeSnapshotSaved is emitted after a snapshot is written.
+	TypeSnapshotSaved Type = "snapshot_saved"
+
+	// TypeChangesDetected is emitted when a check finds changes since the previous snapshot.
+	TypeChangesDetected Type = "changes_detected"
+
+	// TypeCheckFailed is emitted when a monitoring cycle fails.
+	TypeCheckFailed Type = "check_failed"
+
+	// TypeAssertionsViolated is emitted when a check finds routes breaking configured assertions.
+	TypeAssertionsViolated Type = "assertions_violated"
+)
+
+// Event is implemented by all event types. Subscribers type-switch on the
+// concrete type to access its fields.
+type Event interface {
+	EventType() Type
+	OccurredAt() time.Time
+}
+
+// SnapshotSaved reports a newly saved snapshot.
+type SnapshotSaved struct {
+	Time         time.Time           `json:"time"`
+	SnapshotID   string              `json:"snapshot_id"`
+	SnapshotType models.SnapshotType `json:"snapshot_type"`
+	Scope        string              `json:"scope,omitempty"`
+	ItemCount    int                 `json:"item_count"`
+}
+
+// ChangesDetected reports changes between two snapshots.
+type ChangesDetected struct {
+	Time       time.Time                 `json:"time"`
+	SnapshotID string                    `json:"snapshot_id"`
+	PreviousID string                    `json:"previous_id"`
+	Baseline   string                    `json:"baseline,omitempty"` // Set when PreviousID is a named baseline
+	Target     string                    `json:"target,omitempty"`   // Watch target checked, if not the whole account
+	Summary    map[models.ChangeType]int `json:"summary"`
+	Changes    *models.ChangeSet         `json:"changes"`
+}
+
+// CheckFailed reports a failed monitoring cycle.
+type CheckFailed struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`           // check, target-check, contact-check, snapshot, reconcile, or cleanup
+	Target string    `json:"target,omitempty"` // Watch target of a failed target-check
+	Error  string    `json:"error"`
+}
+
+// AssertionsViolated reports routes in a snapshot that break configured assertions.
+type AssertionsViolated struct {
+	Time       time.Time         `json:"time"`
+	SnapshotID string            `json:"snapshot_id"`
+	Target     string            `json:"target,omitempty"` // Watch target checked, if not the whole account
+	Violations []audit.Violation `json:"violations"`
+}
+
+// EventType returns TypeSnapshotSaved.
+func (e *SnapshotSaved) EventType() Type { return TypeSnapshotSaved }
+
+// OccurredAt returns when the snapshot was saved.
+func (e *SnapshotSaved) OccurredAt() time.Time { return e.Time }
+
+// EventType returns TypeChangesDetected.
+func (e *ChangesDetected) EventType() Type { return TypeChangesDetected }
+
+// OccurredAt returns when the changes were detected.
+func (e *ChangesDetected) OccurredAt() time.Time { return e.Time }
+
+// EventType returns TypeCheckFailed.
+func (e *CheckFailed) EventType() Type { return TypeCheckFailed }
+
+// OccurredAt returns when the cycle failed.
+func (e *CheckFailed) OccurredAt() time.Time { return e.Time }
+
+// EventType returns TypeAssertionsViolated.
+func (e *AssertionsViolated) EventType() Type { return TypeAssertionsViolated }
+
+// OccurredAt returns when the violations were found.
+func (e *AssertionsViolated) OccurredAt() time.Time { return e.Time }
+
+// Handler receives published events.
+type Handler func(Event)
+
+// Bus delivers published events to subscribers. A nil *Bus is valid and
+// discards everything, so publishers need not check whether one is set.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	nextID        int
+	logger        *logrus.Logger
+	dropped       *metrics.Counter
+}
+
+// subscription is a registered handler.
+type subscription struct {
+	id      int
+	handler Handler
+}
+
+// NewBus creates an empty event bus.
+func NewBus() *Bus {
+	return &Bus{logger: logrus.StandardLogger()}
+}
+
+// SetLogger sets the logger warned when a channel subscriber drops events.
+func (b *Bus) SetLogger(logger *logrus.Logger) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logger = logger
+}
+
+// SetMetrics registers the bus metrics in reg.
+func (b *Bus) SetMetrics(reg *metrics.Registry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dropped = reg.NewCounter("radb_events_dropped_total",
+		"Events dropped because a channel subscriber's buffer was full, by subscriber.",
+		"subscriber")
+}
+
+// Subscribe registers a handler for all events and returns a function that
+// removes it. Handlers run synchronously on the publishing goroutine, in
+// subscription order, and should return quickly.
+func (b *Bus) Subscribe(handler Handler) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscriptions = append(b.subscriptions, subscription{id: id, handler: handler})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, sub := range b.subscriptions {
+			if sub.id == id {
+				b.subscriptions = append(b.subscriptions[:i:i], b.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SubscribeChan returns a channel receiving all events and a function that
+// unsubscribes and closes it. Events are dropped if the channel's buffer is
+// full, so a slow reader never stalls the publisher; each drop is logged and
+// counted in radb_events_dropped_total under name.
+func (b *Bus) SubscribeChan(name string, buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+
+	var once sync.Once
+	var mu sync.Mutex
+	closed := false
+	dropped := 0
+
+	unsubscribe := b.Subscribe(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- event:
+		default:
+			dropped++
+			b.recordDrop(name, event, dropped)
+		}
+	})
+
+	return ch, func() {
+		once.Do(func() {
+			unsubscribe()
+			mu.Lock()
+			closed = true
+			close(ch)
+			mu.Unlock()
+		})
+	}
+}
+
+// recordDrop logs and counts an event the subscriber name had no room for.
+// total is the number the subscriber has dropped so far.
+func (b *Bus) recordDrop(name string, event Event, total int) {
+	b.mu.RLock()
+	logger, dropped := b.logger, b.dropped
+	b.mu.RUnlock()
+
+	if dropped != nil {
+		dropped.Inc(name)
+	}
+	if logger != nil {
+		logger.Warnf("Dropped %s event for %s: its buffer is full (%d dropped so far)", event.EventType(), name, total)
+	}
+}
+
+// Publish delivers an event to every subscriber.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		sub.handler(event)
+	}
+}
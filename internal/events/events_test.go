@@ -0,0 +1,112 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/pkg/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBusSubscribe(t *testing.T) {
+	bus := NewBus()
+
+	var order []string
+	unsubscribeFirst := bus.Subscribe(func(event Event) { order = append(order, "first:"+string(event.EventType())) })
+	bus.Subscribe(func(event Event) { order = append(order, "second:"+string(event.EventType())) })
+
+	bus.Publish(&SnapshotSaved{Time: time.Now(), SnapshotID: "route-1"})
+	unsubscribeFirst()
+	bus.Publish(&CheckFailed{Time: time.Now(), Action: "check", Error: "boom"})
+
+	want := []string{"first:snapshot_saved", "second:snapshot_saved", "second:check_failed"}
+	if len(order) != len(want) {
+		t.Fatalf("got events %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, order[i], want[i])
+		}
+	}
+}
+
+func TestBusSubscribeChan(t *testing.T) {
+	bus := NewBus()
+	ch, unsubscribe := bus.SubscribeChan("test", 1)
+
+	bus.Publish(&SnapshotSaved{SnapshotID: "route-1"})
+	bus.Publish(&SnapshotSaved{SnapshotID: "route-2"}) // dropped: buffer full
+
+	event := <-ch
+	saved, ok := event.(*SnapshotSaved)
+	if !ok || saved.SnapshotID != "route-1" {
+		t.Fatalf("got %#v, want the first SnapshotSaved", event)
+	}
+
+	unsubscribe()
+	unsubscribe() // safe to call twice
+	bus.Publish(&SnapshotSaved{SnapshotID: "route-3"})
+	if _, open := <-ch; open {
+		t.Error("channel still open after unsubscribe")
+	}
+}
+
+func TestBusSubscribeChanOverflow(t *testing.T) {
+	var logs strings.Builder
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	reg := metrics.NewRegistry()
+
+	bus := NewBus()
+	bus.SetLogger(logger)
+	bus.SetMetrics(reg)
+	slow, unsubscribeSlow := bus.SubscribeChan("slow", 2)
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := bus.SubscribeChan("fast", 10)
+	defer unsubscribeFast()
+
+	for i := range 5 {
+		bus.Publish(&SnapshotSaved{SnapshotID: fmt.Sprintf("route-%d", i)})
+	}
+
+	// The slow subscriber keeps the first events; the fast one gets them all
+	for _, want := range []string{"route-0", "route-1"} {
+		if saved := (<-slow).(*SnapshotSaved); saved.SnapshotID != want {
+			t.Errorf("slow subscriber got %s, want %s", saved.SnapshotID, want)
+		}
+	}
+	if len(fast) != 5 {
+		t.Errorf("fast subscriber holds %d events, want 5", len(fast))
+	}
+
+	var b strings.Builder
+	if err := reg.WriteText(&b); err != nil {
+		t.Fatalf("WriteText() failed: %v", err)
+	}
+	if !strings.Contains(b.String(), `radb_events_dropped_total{subscriber="slow"} 3`) {
+		t.Errorf("metrics missing 3 drops for the slow subscriber:\n%s", b.String())
+	}
+	if strings.Contains(b.String(), `subscriber="fast"`) {
+		t.Errorf("metrics count drops for the fast subscriber:\n%s", b.String())
+	}
+
+	if got := strings.Count(logs.String(), "Dropped snapshot_saved event for slow"); got != 3 {
+		t.Errorf("logged %d drops, want 3:\n%s", got, logs.String())
+	}
+	if !strings.Contains(logs.String(), "3 dropped so far") {
+		t.Errorf("log missing the running total:\n%s", logs.String())
+	}
+
+	// Room freed by the reader is used again
+	bus.Publish(&SnapshotSaved{SnapshotID: "route-5"})
+	if saved := (<-slow).(*SnapshotSaved); saved.SnapshotID != "route-5" {
+		t.Errorf("slow subscriber got %s after draining, want route-5", saved.SnapshotID)
+	}
+}
+
+func TestNilBusDiscards(t *testing.T) {
+	var bus *Bus
+	bus.Publish(&CheckFailed{Error: "ignored"})
+}
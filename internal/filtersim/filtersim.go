@@ -0,0 +1,286 @@
+// Package filtersim predicts how IRR-based filter generators, such as
+// bgpq4 or a route server's import policy, will treat a route object before
+// it is registered.
+package filtersim
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Longest prefixes most networks accept; longer ones are filtered as too
+// specific regardless of their IRR objects.
+const (
+	MaxIPv4Length = 24
+	MaxIPv6Length = 48
+)
+
+// maxSetDepth bounds as-set recursion, well past any real hierarchy.
+const maxSetDepth = 20
+
+// Check names.
+const (
+	CheckPrefixLength = "prefix-length"
+	CheckASSet        = "as-set"
+	CheckRPKI         = "rpki"
+	CheckCustomerCone = "customer-cone"
+)
+
+// CheckResult is the outcome of a check.
+type CheckResult string
+
+const (
+	CheckPass CheckResult = "pass" // Filters of this kind accept the route
+	CheckFail CheckResult = "fail" // Filters of this kind drop the route
+	CheckSkip CheckResult = "skip" // The check had no data to run on
+)
+
+// Check is one filter a proposed route was evaluated against.
+type Check struct {
+	Name   string      `json:"name"`
+	Result CheckResult `json:"result"`
+	Detail string      `json:"detail"`
+}
+
+// Result is the predicted treatment of a proposed route.
+type Result struct {
+	Route    string   `json:"route"`
+	Origin   string   `json:"origin"`
+	Accepted bool     `json:"accepted"`
+	Checks   []Check  `json:"checks"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SetResolver returns the members of an as-set.
+type SetResolver func(ctx context.Context, name string) ([]string, error)
+
+// Options holds the data the checks run on. A check whose data is missing
+// is skipped.
+type Options struct {
+	// ASSets are the as-sets the filters are generated from, such as an
+	// upstream's customer set. Resolve must be set when they are.
+	ASSets  []string
+	Resolve SetResolver
+
+	// ROAs are validated ROAs for origin validation
+	ROAs []ROA
+
+	// Routes are registered route objects; those originated inside the
+	// as-sets make up the customer cone's address space
+	Routes []models.RouteObject
+}
+
+// Simulate evaluates a proposed route with the given prefix and origin.
+// The route is accepted when no check fails.
+func Simulate(ctx context.Context, prefix, origin string, opts Options) (*Result, error) {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix %q: %w", prefix, err)
+	}
+	p = p.Masked()
+	origin = NormalizeASN(origin)
+
+	result := &Result{Route: p.String(), Origin: origin, Checks: make([]Check, 0, 4)}
+	result.Checks = append(result.Checks, checkPrefixLength(p))
+
+	cone := make(map[string][]string)
+	if len(opts.ASSets) > 0 {
+		if opts.Resolve == nil {
+			return nil, fmt.Errorf("as-sets given without a resolver")
+		}
+		for _, set := range opts.ASSets {
+			members, unresolved, err := expand(ctx, opts.Resolve, set)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range unresolved {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("could not resolve %s (member of %s); its ASNs are not counted", name, set))
+			}
+			for asn, path := range members {
+				if _, seen := cone[asn]; !seen {
+					cone[asn] = path
+				}
+			}
+		}
+	}
+
+	result.Checks = append(result.Checks,
+		checkASSet(origin, opts.ASSets, cone),
+		checkRPKI(p, origin, opts.ROAs),
+		checkCustomerCone(p, opts.ASSets, cone, opts.Routes),
+	)
+
+	result.Accepted = true
+	for _, check := range result.Checks {
+		if check.Result == CheckFail {
+			result.Accepted = false
+		}
+	}
+	return result, nil
+}
+
+// checkPrefixLength drops prefixes longer than most networks accept.
+func checkPrefixLength(p netip.Prefix) Check {
+	check := Check{Name: CheckPrefixLength, Result: CheckPass}
+	limit := MaxIPv4Length
+	if p.Addr().Is6() {
+		limit = MaxIPv6Length
+	}
+	if p.Bits() > limit {
+		check.Result = CheckFail
+		check.Detail = fmt.Sprintf("/%d is longer than /%d, which most networks filter", p.Bits(), limit)
+	} else {
+		check.Detail = fmt.Sprintf("/%d is within /%d", p.Bits(), limit)
+	}
+	return check
+}
+
+// checkASSet reports whether the origin is a member of the as-sets, as a
+// filter generated from them only includes routes of member ASNs.
+func checkASSet(origin string, sets []string, cone map[string][]string) Check {
+	check := Check{Name: CheckASSet}
+	switch path, ok := cone[origin]; {
+	case len(sets) == 0:
+		check.Result = CheckSkip
+		check.Detail = "no as-set given"
+	case ok:
+		check.Result = CheckPass
+		check.Detail = fmt.Sprintf("%s is reached by %s", origin, strings.Join(append(path, origin), " > "))
+	default:
+		check.Result = CheckFail
+		check.Detail = fmt.Sprintf("%s is not a member of %s (%d ASNs)", origin, strings.Join(sets, ", "), len(cone))
+	}
+	return check
+}
+
+// checkCustomerCone reports whether the prefix lies within address space
+// already registered by the cone, which filters that only accept more
+// specifics of known customer space require.
+func checkCustomerCone(p netip.Prefix, sets []string, cone map[string][]string, routes []models.RouteObject) Check {
+	check := Check{Name: CheckCustomerCone}
+	if len(sets) == 0 || routes == nil {
+		check.Result = CheckSkip
+		check.Detail = "no as-set or registered routes given"
+		return check
+	}
+
+	var best *models.RouteObject
+	var bestPrefix netip.Prefix
+	for i := range routes {
+		route := &routes[i]
+		if _, ok := cone[NormalizeASN(route.Origin)]; !ok {
+			continue
+		}
+		registered, err := netip.ParsePrefix(route.Route)
+		if err != nil || !covers(registered.Masked(), p) {
+			continue
+		}
+		// Report the most specific covering route
+		if best == nil || registered.Bits() > bestPrefix.Bits() {
+			best, bestPrefix = route, registered.Masked()
+		}
+	}
+
+	if best == nil {
+		check.Result = CheckFail
+		check.Detail = fmt.Sprintf("no route object originated inside %s covers %s", strings.Join(sets, ", "), p)
+		return check
+	}
+	check.Result = CheckPass
+	check.Detail = fmt.Sprintf("covered by %s %s", bestPrefix, NormalizeASN(best.Origin))
+	return check
+}
+
+// covers reports whether outer equals or contains inner.
+func covers(outer, inner netip.Prefix) bool {
+	return outer.Addr().Is4() == inner.Addr().Is4() && outer.Bits() <= inner.Bits() && outer.Contains(inner.Addr())
+}
+
+// expand flattens an as-set into its member ASNs, each with the chain of
+// sets through which it was first reached. Nested sets that cannot be
+// resolved are returned rather than failing the expansion; the root set
+// must resolve.
+func expand(ctx context.Context, resolve SetResolver, root string) (map[string][]string, []string, error) {
+	root = strings.ToUpper(strings.TrimSpace(root))
+	members := make(map[string][]string)
+	visited := map[string]bool{root: true}
+	var unresolved []string
+
+	type pending struct {
+		name string
+		path []string
+	}
+	queue := []pending{{name: root, path: []string{root}}}
+	for len(queue) > 0 {
+		set := queue[0]
+		queue = queue[1:]
+
+		names, err := resolve(ctx, set.name)
+		if err != nil {
+			if set.name == root {
+				return nil, nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+			}
+			unresolved = append(unresolved, set.name)
+			continue
+		}
+
+		for _, name := range names {
+			name = strings.ToUpper(strings.TrimSpace(name))
+			switch {
+			case IsASSet(name):
+				if visited[name] || len(set.path) >= maxSetDepth {
+					continue
+				}
+				visited[name] = true
+				queue = append(queue, pending{name: name, path: append(append([]string(nil), set.path...), name)})
+			case isASN(name):
+				asn := NormalizeASN(name)
+				if _, seen := members[asn]; !seen {
+					members[asn] = set.path
+				}
+			}
+		}
+	}
+
+	sort.Strings(unresolved)
+	return members, unresolved, nil
+}
+
+// IsASSet reports whether name is an as-set name, such as AS-EXAMPLE or
+// the hierarchical AS64500:AS-CUSTOMERS.
+func IsASSet(name string) bool {
+	for _, part := range strings.Split(strings.ToUpper(name), ":") {
+		if strings.HasPrefix(part, "AS-") {
+			return true
+		}
+	}
+	return false
+}
+
+// isASN reports whether name is an AS number, with or without the AS prefix.
+func isASN(name string) bool {
+	digits := strings.TrimPrefix(strings.ToUpper(name), "AS")
+	if digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeASN returns an AS number in the AS64500 form.
+func NormalizeASN(asn string) string {
+	asn = strings.ToUpper(strings.TrimSpace(asn))
+	if !strings.HasPrefix(asn, "AS") {
+		asn = "AS" + asn
+	}
+	return asn
+}
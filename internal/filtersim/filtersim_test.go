@@ -0,0 +1,154 @@
+package filtersim
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// sets resolves as-sets from a map.
+func sets(members map[string][]string) SetResolver {
+	return func(ctx context.Context, name string) ([]string, error) {
+		if m, ok := members[name]; ok {
+			return m, nil
+		}
+		return nil, errors.New("not found")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	resolve := sets(map[string][]string{
+		"AS-UPSTREAM":     {"AS64496", "AS-CUSTOMERS", "AS-MISSING"},
+		"AS-CUSTOMERS":    {"as64500", "AS64496:AS-LOOP", "AS-UPSTREAM"},
+		"AS64496:AS-LOOP": {"AS-CUSTOMERS", "64501"},
+	})
+	roas := []ROA{
+		{ASN: "AS64500", Prefix: netip.MustParsePrefix("192.0.2.0/23"), MaxLength: 24},
+		{ASN: "AS64511", Prefix: netip.MustParsePrefix("198.51.100.0/24"), MaxLength: 24},
+	}
+	routes := []models.RouteObject{
+		{Route: "192.0.0.0/16", Origin: "AS64496"},
+		{Route: "192.0.2.0/23", Origin: "AS64500"},
+		{Route: "203.0.113.0/24", Origin: "AS64999"},
+	}
+	opts := Options{ASSets: []string{"AS-UPSTREAM"}, Resolve: resolve, ROAs: roas, Routes: routes}
+
+	tests := []struct {
+		name, prefix, origin string
+		accepted             bool
+		results              map[string]CheckResult
+		detail               string
+	}{
+		{
+			name: "accepted", prefix: "192.0.2.0/24", origin: "AS64500", accepted: true,
+			results: map[string]CheckResult{CheckPrefixLength: CheckPass, CheckASSet: CheckPass, CheckRPKI: CheckPass, CheckCustomerCone: CheckPass},
+			detail:  "covered by 192.0.2.0/23 AS64500",
+		},
+		{
+			name: "beyond max length", prefix: "192.0.2.0/25", origin: "AS64500",
+			results: map[string]CheckResult{CheckPrefixLength: CheckFail, CheckRPKI: CheckFail},
+			detail:  "longer than the maxLength",
+		},
+		{
+			name: "wrong origin", prefix: "198.51.100.0/24", origin: "AS64501",
+			results: map[string]CheckResult{CheckASSet: CheckPass, CheckRPKI: CheckFail, CheckCustomerCone: CheckFail},
+			detail:  "authorize AS64511, not AS64501",
+		},
+		{
+			name: "outside the set", prefix: "203.0.113.0/24", origin: "AS64999",
+			results: map[string]CheckResult{CheckASSet: CheckFail, CheckRPKI: CheckPass, CheckCustomerCone: CheckFail},
+			detail:  "not-found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Simulate(context.Background(), tt.prefix, tt.origin, opts)
+			if err != nil {
+				t.Fatalf("Simulate() failed: %v", err)
+			}
+			if result.Accepted != tt.accepted {
+				t.Errorf("Accepted = %v, want %v: %+v", result.Accepted, tt.accepted, result.Checks)
+			}
+
+			var details []string
+			for _, check := range result.Checks {
+				details = append(details, check.Detail)
+				if want, ok := tt.results[check.Name]; ok && check.Result != want {
+					t.Errorf("%s = %s (%s), want %s", check.Name, check.Result, check.Detail, want)
+				}
+			}
+			if !strings.Contains(strings.Join(details, "\n"), tt.detail) {
+				t.Errorf("no check detail contains %q: %v", tt.detail, details)
+			}
+
+			if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "AS-MISSING") {
+				t.Errorf("Warnings = %v, want the unresolved AS-MISSING", result.Warnings)
+			}
+		})
+	}
+
+	t.Run("SetPath", func(t *testing.T) {
+		result, err := Simulate(context.Background(), "192.0.2.0/24", "AS64501", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "AS64501 is reached by AS-UPSTREAM > AS-CUSTOMERS > AS64496:AS-LOOP > AS64501"
+		if got := result.Checks[1].Detail; got != want {
+			t.Errorf("as-set detail = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("WithoutData", func(t *testing.T) {
+		result, err := Simulate(context.Background(), "2001:db8::/48", "AS64500", Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Accepted {
+			t.Errorf("Expected acceptance when only the length is checked: %+v", result.Checks)
+		}
+		for _, check := range result.Checks[1:] {
+			if check.Result != CheckSkip {
+				t.Errorf("%s = %s, want skip", check.Name, check.Result)
+			}
+		}
+	})
+
+	t.Run("UnresolvableRoot", func(t *testing.T) {
+		if _, err := Simulate(context.Background(), "192.0.2.0/24", "AS64500", Options{ASSets: []string{"AS-NONE"}, Resolve: resolve}); err == nil {
+			t.Error("Expected an error for an unresolvable as-set")
+		}
+	})
+}
+
+func TestLoadROAs(t *testing.T) {
+	input := `{"metadata": {}, "roas": [
+		{"asn": "AS64500", "prefix": "192.0.2.0/23", "maxLength": 24, "ta": "arin"},
+		{"asn": 64501, "prefix": "2001:db8::/32", "maxLength": 48}
+	]}`
+	roas, err := LoadROAs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("LoadROAs() failed: %v", err)
+	}
+	want := []ROA{
+		{ASN: "AS64500", Prefix: netip.MustParsePrefix("192.0.2.0/23"), MaxLength: 24},
+		{ASN: "AS64501", Prefix: netip.MustParsePrefix("2001:db8::/32"), MaxLength: 48},
+	}
+	if len(roas) != len(want) || roas[0] != want[0] || roas[1] != want[1] {
+		t.Errorf("LoadROAs() = %+v, want %+v", roas, want)
+	}
+
+	for _, bad := range []string{
+		`{"roas": [{"asn": "AS64500", "prefix": "192.0.2.0", "maxLength": 24}]}`,
+		`{"roas": [{"asn": "ASX", "prefix": "192.0.2.0/24", "maxLength": 24}]}`,
+		`{"roas": [{"asn": 64500, "prefix": "192.0.2.0/24", "maxLength": 23}]}`,
+	} {
+		if _, err := LoadROAs(strings.NewReader(bad)); err == nil {
+			t.Errorf("LoadROAs(%s) should fail", bad)
+		}
+	}
+}
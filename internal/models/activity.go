@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Kinds of notable events in an activity summary.
+const (
+	ActivityOriginChange = "origin-change"
+	ActivityDeleted      = "deleted"
+	ActivityWriteFailed  = "write-failed"
+)
+
+// ActivitySummary describes what changed in local state over a period:
+// snapshots taken, route counts, changes by maintainer, and writes made.
+type ActivitySummary struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+
+	Snapshots       int                  `json:"snapshots"`
+	SnapshotsByType map[SnapshotType]int `json:"snapshots_by_type"`
+
+	// RoutesBefore and RoutesAfter are the route counts of the last route
+	// snapshot before the period (or its first, if none) and of its last;
+	// both are nil when no route snapshot was taken
+	RoutesBefore *int `json:"routes_before,omitempty"`
+	RoutesAfter  *int `json:"routes_after,omitempty"`
+
+	Changes        map[ChangeType]int   `json:"changes"`
+	TopMaintainers []MaintainerActivity `json:"top_maintainers"`
+
+	WritesSucceeded int `json:"writes_succeeded"`
+	WritesFailed    int `json:"writes_failed"`
+
+	Events []ActivityEvent `json:"events"`
+}
+
+// NetRoutes returns the change in route count over the period, and false
+// when no route snapshot was taken.
+func (s *ActivitySummary) NetRoutes() (int, bool) {
+	if s.RoutesBefore == nil || s.RoutesAfter == nil {
+		return 0, false
+	}
+	return *s.RoutesAfter - *s.RoutesBefore, true
+}
+
+// MaintainerActivity is the number of changes to one maintainer's objects.
+type MaintainerActivity struct {
+	Maintainer string `json:"maintainer"`
+	Changes    int    `json:"changes"`
+}
+
+// ActivityEvent is a change worth calling out: an origin change, a
+// deleted route, or a failed write.
+type ActivityEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	ObjectID  string    `json:"object_id"`
+	Detail    string    `json:"detail,omitempty"`
+}
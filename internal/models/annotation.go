@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AnnotationTypeChange is the object type of annotations attached to
+// changelog entries, whose object ID is the entry ID.
+const AnnotationTypeChange = "change"
+
+// Annotation is a local note attached to a route or contact object, or to
+// a changelog entry.
+// Annotations are stored alongside snapshots and never sent to RADb.
+type Annotation struct {
+	// ObjectType is the kind of object annotated (route, contact, change)
+	ObjectType string `json:"object_type"`
+
+	// ObjectID identifies the object (RouteObject.ID(), Contact.ID, or ChangelogEntry.ID)
+	ObjectID string `json:"object_id"`
+
+	// Note is free-form operator text
+	Note string `json:"note,omitempty"`
+
+	// Labels are short tags such as "legacy" or "customer"
+	Labels []string `json:"labels,omitempty"`
+
+	// UpdatedAt is when the annotation last changed
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Key returns the lookup key for this annotation.
+func (a *Annotation) Key() string {
+	return AnnotationKey(a.ObjectType, a.ObjectID)
+}
+
+// AddLabels adds labels that are not already present, keeping them sorted.
+func (a *Annotation) AddLabels(labels ...string) {
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || containsLabel(a.Labels, label) {
+			continue
+		}
+		a.Labels = append(a.Labels, label)
+	}
+	sort.Strings(a.Labels)
+}
+
+// RemoveLabels removes the given labels.
+func (a *Annotation) RemoveLabels(labels ...string) {
+	kept := a.Labels[:0]
+	for _, existing := range a.Labels {
+		if !containsLabel(labels, existing) {
+			kept = append(kept, existing)
+		}
+	}
+	a.Labels = kept
+}
+
+// IsEmpty returns true if the annotation carries no note or labels.
+func (a *Annotation) IsEmpty() bool {
+	return a.Note == "" && len(a.Labels) == 0
+}
+
+// String returns a compact single-line form, e.g. "[legacy] do not delete".
+func (a *Annotation) String() string {
+	var parts []string
+	if len(a.Labels) > 0 {
+		parts = append(parts, fmt.Sprintf("[%s]", strings.Join(a.Labels, ", ")))
+	}
+	if a.Note != "" {
+		parts = append(parts, a.Note)
+	}
+	return strings.Join(parts, " ")
+}
+
+// AnnotationKey returns the lookup key for an object.
+func AnnotationKey(objectType, objectID string) string {
+	return objectType + ":" + objectID
+}
+
+// Annotations indexes annotations by object.
+type Annotations map[string]Annotation
+
+// For returns the annotation for an object, if any.
+func (a Annotations) For(objectType, objectID string) (Annotation, bool) {
+	annotation, ok := a[AnnotationKey(objectType, objectID)]
+	return annotation, ok
+}
+
+// Label returns the compact form of an object's annotation, or "" if none.
+func (a Annotations) Label(objectType, objectID string) string {
+	annotation, ok := a.For(objectType, objectID)
+	if !ok {
+		return ""
+	}
+	return annotation.String()
+}
+
+func containsLabel(labels []string, target string) bool {
+	for _, label := range labels {
+		if strings.EqualFold(label, target) {
+			return true
+		}
+	}
+	return false
+}
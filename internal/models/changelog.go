@@ -1,13 +1,19 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"strings"
 	"time"
 )
 
 // ChangelogEntry represents a single entry in the changelog file.
 // Changelog entries are stored in JSONL format (one JSON object per line).
 type ChangelogEntry struct {
+	// ID is a stable identifier derived from the entry's content
+	ID string `json:"id,omitempty"`
+
 	// Timestamp is when this change was recorded
 	Timestamp time.Time `json:"timestamp"`
 
@@ -20,6 +26,9 @@ type ChangelogEntry struct {
 	// ObjectID uniquely identifies the changed object
 	ObjectID string `json:"object_id"`
 
+	// URN is the canonical reference to the changed object
+	URN string `json:"urn,omitempty"`
+
 	// SnapshotID references the snapshot where this change was detected
 	SnapshotID string `json:"snapshot_id"`
 
@@ -48,9 +57,11 @@ func NewChangelogEntry(change Change, snapshotID string) (*ChangelogEntry, error
 		ChangeType: change.Type,
 		ObjectType: change.ObjectType,
 		ObjectID:   change.ObjectID,
+		URN:        ObjectURN(change.ObjectType, change.ObjectID),
 		SnapshotID: snapshotID,
 		Metadata:   make(map[string]string),
 	}
+	entry.ID = entry.EntryID()
 
 	// Serialize before/after states
 	if change.Before != nil {
@@ -77,6 +88,24 @@ func NewChangelogEntry(change Change, snapshotID string) (*ChangelogEntry, error
 	return entry, nil
 }
 
+// EntryID returns the entry's ID. Entries recorded before IDs were
+// introduced get the ID they would have been given, so an entry's ID is the
+// same however it is read.
+func (e *ChangelogEntry) EntryID() string {
+	if e.ID != "" {
+		return e.ID
+	}
+	key := strings.Join([]string{
+		e.Timestamp.UTC().Format(time.RFC3339Nano),
+		string(e.ChangeType),
+		e.ObjectType,
+		e.ObjectID,
+		e.SnapshotID,
+	}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:6])
+}
+
 // ToChange converts a changelog entry back to a Change object.
 func (e *ChangelogEntry) ToChange() Change {
 	change := Change{
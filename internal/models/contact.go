@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -80,11 +82,53 @@ func (c *Contact) Validate() error {
 	return nil
 }
 
+// ToRPSL returns the contact in RPSL attribute form, one "name: value"
+// line per value. Contacts are account records rather than registry
+// objects, so the attribute names follow the contact's fields, with any
+// raw attributes after them in name order.
+func (c *Contact) ToRPSL() string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("contact: %s\n", c.ID))
+	b.WriteString(fmt.Sprintf("name: %s\n", c.Name))
+	b.WriteString(fmt.Sprintf("email: %s\n", c.Email))
+	if c.Phone != "" {
+		b.WriteString(fmt.Sprintf("phone: %s\n", c.Phone))
+	}
+	b.WriteString(fmt.Sprintf("role: %s\n", c.Role))
+	if c.Organization != "" {
+		b.WriteString(fmt.Sprintf("organization: %s\n", c.Organization))
+	}
+	for _, line := range c.Address {
+		b.WriteString(fmt.Sprintf("address: %s\n", line))
+	}
+
+	names := make([]string, 0, len(c.RawAttributes))
+	for name := range c.RawAttributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range c.RawAttributes[name] {
+			b.WriteString(fmt.Sprintf("%s: %s\n", name, value))
+		}
+	}
+
+	return b.String()
+}
+
+// ToRPSLDelete returns the contact as an RPSL deletion for mail submission,
+// in the same form as RouteObject.ToRPSLDelete.
+func (c *Contact) ToRPSLDelete(reason string) string {
+	return c.ToRPSL() + rpslDeleteAttribute(reason)
+}
+
 // ContactList is a collection of contacts.
 type ContactList struct {
 	Contacts  []Contact `json:"contacts"`
 	Timestamp time.Time `json:"timestamp"`
 	Count     int       `json:"count"`
+	Truncated bool      `json:"truncated,omitempty"` // More contacts existed than the result cap allowed
 }
 
 // NewContactList creates a new contact list with the current timestamp.
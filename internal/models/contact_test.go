@@ -0,0 +1,30 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContactToRPSLDelete(t *testing.T) {
+	contact := Contact{ID: "ADMIN-1", Name: "Example NOC", Email: "noc@example.com", Role: ContactRoleAdmin}
+
+	tests := []struct {
+		reason string
+		want   string
+	}{
+		{"left the company", "delete: left the company\n"},
+		{"  left\n the\tcompany ", "delete: left the company\n"},
+		{"", "delete: no longer in use\n"},
+	}
+
+	for _, tt := range tests {
+		got := contact.ToRPSLDelete(tt.reason)
+		body := contact.ToRPSL()
+		if !strings.HasPrefix(got, body) {
+			t.Fatalf("ToRPSLDelete(%q) = %q, want it to start with the ToRPSL body", tt.reason, got)
+		}
+		if tail := strings.TrimPrefix(got, body); tail != tt.want {
+			t.Errorf("ToRPSLDelete(%q) ends with %q, want %q", tt.reason, tail, tt.want)
+		}
+	}
+}
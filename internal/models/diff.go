@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/json"
 	"reflect"
+
+	"github.com/bss/radb-client/pkg/textdiff"
 )
 
 // DiffResult contains the results of comparing two snapshots.
@@ -100,6 +102,88 @@ func (dr *DiffResult) ComputeSummary() {
 	dr.Summary.TotalChanges = dr.Summary.AddedCount + dr.Summary.RemovedCount + dr.Summary.ModifiedCount
 }
 
+// RPSLDiff returns the unified diff of the item's RPSL form before and
+// after the change, or "" if the item is not a route or contact.
+func (mi *ModifiedItem) RPSLDiff() string {
+	return RPSLDiff(mi.ObjectType, mi.ID, mi.Before, mi.After)
+}
+
+// RPSLDiff returns the unified diff of the RPSL form of an object before and
+// after a change, or "" if the object is not a route or contact. before and
+// after may be typed pointers or generic maps read back from JSON; a nil
+// state diffs as empty text.
+func RPSLDiff(objectType, objectID string, before, after interface{}) string {
+	from, ok := ObjectRPSL(objectType, before)
+	if !ok {
+		return ""
+	}
+	to, ok := ObjectRPSL(objectType, after)
+	if !ok {
+		return ""
+	}
+	return textdiff.Unified(objectID+" (before)", objectID+" (after)", from, to, textdiff.DefaultContext)
+}
+
+// ObjectRPSL returns the RPSL form of a route or contact given as a typed
+// value, pointer, or generic map, and whether the object could be rendered.
+// A nil object renders as empty text.
+func ObjectRPSL(objectType string, object interface{}) (string, bool) {
+	if object == nil {
+		return "", true
+	}
+
+	switch objectType {
+	case "route":
+		var route RouteObject
+		if !DecodeObject(object, &route) {
+			return "", false
+		}
+		return route.ToRPSL(), true
+	case "contact":
+		var contact Contact
+		if !DecodeObject(object, &contact) {
+			return "", false
+		}
+		return contact.ToRPSL(), true
+	default:
+		return "", false
+	}
+}
+
+// DecodeObject converts a change's before or after state to out. Live change
+// sets hold typed values or pointers; changes read back from JSON hold
+// generic maps.
+func DecodeObject(object interface{}, out interface{}) bool {
+	switch v := object.(type) {
+	case *RouteObject:
+		if route, ok := out.(*RouteObject); ok && v != nil {
+			*route = *v
+			return true
+		}
+	case RouteObject:
+		if route, ok := out.(*RouteObject); ok {
+			*route = v
+			return true
+		}
+	case *Contact:
+		if contact, ok := out.(*Contact); ok && v != nil {
+			*contact = *v
+			return true
+		}
+	case Contact:
+		if contact, ok := out.(*Contact); ok {
+			*contact = v
+			return true
+		}
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
+
 // DetectFieldChanges compares two objects and returns the list of changed fields.
 func DetectFieldChanges(before, after interface{}) []FieldChange {
 	changes := make([]FieldChange, 0)
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"lukechampine.com/blake3"
+)
+
+// Checksum algorithms for snapshot integrity.
+const (
+	// ChecksumSHA256 is the default algorithm
+	ChecksumSHA256 = "sha256"
+
+	// ChecksumBLAKE3 is faster on large snapshots
+	ChecksumBLAKE3 = "blake3"
+)
+
+// ValidChecksumAlgorithm reports whether algorithm is supported.
+func ValidChecksumAlgorithm(algorithm string) bool {
+	switch algorithm {
+	case "", ChecksumSHA256, ChecksumBLAKE3:
+		return true
+	}
+	return false
+}
+
+// hashHex hashes data with algorithm; empty means sha256.
+func hashHex(algorithm string, data []byte) (string, error) {
+	switch algorithm {
+	case "", ChecksumSHA256:
+		hash := sha256.Sum256(data)
+		return hex.EncodeToString(hash[:]), nil
+	case ChecksumBLAKE3:
+		hash := blake3.Sum256(data)
+		return hex.EncodeToString(hash[:]), nil
+	}
+	return "", fmt.Errorf("unsupported checksum algorithm %q", algorithm)
+}
+
+// IntegrityError describes a snapshot whose data no longer matches its
+// checksum. With per-object hashes it names the objects that differ.
+type IntegrityError struct {
+	Expected string
+	Actual   string
+
+	// PerObject is set when the snapshot recorded per-object hashes, and
+	// the fields below list the differing objects by URN
+	PerObject bool
+	Changed   []string
+	Missing   []string // Recorded but no longer present
+	Added     []string // Present but not recorded
+}
+
+// Error implements the error interface.
+func (e *IntegrityError) Error() string {
+	msg := fmt.Sprintf("checksum mismatch: expected %s, got %s", e.Expected, e.Actual)
+	if !e.PerObject {
+		return msg
+	}
+
+	var parts []string
+	for _, group := range []struct {
+		label string
+		urns  []string
+	}{{"changed", e.Changed}, {"missing", e.Missing}, {"added", e.Added}} {
+		if len(group.urns) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", group.label, strings.Join(group.urns, ", ")))
+		}
+	}
+	if len(parts) == 0 {
+		return msg + " (every object matches; only list timestamps or counts differ)"
+	}
+	return msg + " (" + strings.Join(parts, "; ") + ")"
+}
+
+// SetIntegrity selects the checksum algorithm and whether per-object hashes
+// are recorded the next time the checksum is computed.
+func (s *Snapshot) SetIntegrity(algorithm string, perObject bool) {
+	if algorithm == ChecksumSHA256 {
+		algorithm = "" // The default, left implicit as in older snapshots
+	}
+	s.ChecksumAlgorithm = algorithm
+
+	s.ObjectChecksums = nil
+	if perObject {
+		s.ObjectChecksums = make(map[string]string)
+	}
+}
+
+// objectChecksums hashes each route and contact, keyed by URN.
+func (s *Snapshot) objectChecksums() (map[string]string, error) {
+	checksums := make(map[string]string)
+	add := func(urn string, object interface{}) error {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s for checksum: %w", urn, err)
+		}
+		sum, err := hashHex(s.ChecksumAlgorithm, data)
+		if err != nil {
+			return err
+		}
+		checksums[urn] = sum
+		return nil
+	}
+
+	if s.Routes != nil {
+		for i := range s.Routes.Routes {
+			if err := add(s.Routes.Routes[i].URN(), &s.Routes.Routes[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if s.Contacts != nil {
+		for i := range s.Contacts.Contacts {
+			if err := add(s.Contacts.Contacts[i].URN(), &s.Contacts.Contacts[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return checksums, nil
+}
+
+// integrityError compares recorded per-object hashes with the current data.
+func (s *Snapshot) integrityError(expected, actual string) *IntegrityError {
+	e := &IntegrityError{Expected: expected, Actual: actual}
+	if s.ObjectChecksums == nil {
+		return e
+	}
+
+	current, err := s.objectChecksums()
+	if err != nil {
+		return e
+	}
+
+	e.PerObject = true
+	for urn, sum := range s.ObjectChecksums {
+		now, ok := current[urn]
+		switch {
+		case !ok:
+			e.Missing = append(e.Missing, urn)
+		case now != sum:
+			e.Changed = append(e.Changed, urn)
+		}
+	}
+	for urn := range current {
+		if _, ok := s.ObjectChecksums[urn]; !ok {
+			e.Added = append(e.Added, urn)
+		}
+	}
+	sort.Strings(e.Changed)
+	sort.Strings(e.Missing)
+	sort.Strings(e.Added)
+	return e
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// BulkOperation identifies the operation recorded in a bulk journal.
+type BulkOperation string
+
+const (
+	BulkCreateRoutes   BulkOperation = "create-routes"
+	BulkUpdateRoutes   BulkOperation = "update-routes"
+	BulkDeleteRoutes   BulkOperation = "delete-routes"
+	BulkCreateContacts BulkOperation = "create-contacts"
+	BulkUpdateContacts BulkOperation = "update-contacts"
+	BulkDeleteContacts BulkOperation = "delete-contacts"
+)
+
+// JournalItemStatus is the state of a single item in a bulk journal.
+type JournalItemStatus string
+
+const (
+	JournalPending   JournalItemStatus = "pending"
+	JournalSucceeded JournalItemStatus = "succeeded"
+	JournalFailed    JournalItemStatus = "failed"
+)
+
+// JournalItem is one object in a bulk operation. Route operations carry a
+// Route and contact operations a Contact; deletes only need their identifiers.
+type JournalItem struct {
+	ID       string            `json:"id"`
+	Status   JournalItemStatus `json:"status"`
+	Attempts int               `json:"attempts"`
+	Error    string            `json:"error,omitempty"`
+	Route    *RouteObject      `json:"route,omitempty"`
+	Contact  *Contact          `json:"contact,omitempty"`
+}
+
+// BulkJournal records every item of a bulk operation and its outcome, so an
+// interrupted or partially failed operation can be resumed.
+type BulkJournal struct {
+	ID        string        `json:"id"`
+	Operation BulkOperation `json:"operation"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Items     []JournalItem `json:"items"`
+	Runs      []BulkRun     `json:"runs,omitempty"`
+}
+
+// BulkRun is the outcome of one run over some of a journal's items: the
+// initial run, and each resume or retry.
+type BulkRun struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Total      int       `json:"total"`
+	Succeeded  int       `json:"succeeded"`
+	Failed     int       `json:"failed"`
+}
+
+// NewBulkJournal creates an empty journal for an operation.
+func NewBulkJournal(operation BulkOperation) *BulkJournal {
+	now := time.Now()
+	return &BulkJournal{
+		ID:        fmt.Sprintf("%s-%d", operation, now.UnixNano()),
+		Operation: operation,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Items:     make([]JournalItem, 0),
+	}
+}
+
+// AddRoute appends a pending route item.
+func (j *BulkJournal) AddRoute(route *RouteObject) {
+	j.Items = append(j.Items, JournalItem{ID: route.ID(), Status: JournalPending, Route: route})
+}
+
+// AddContact appends a pending contact item. Contacts without an ID yet
+// are identified by email.
+func (j *BulkJournal) AddContact(contact *Contact) {
+	id := contact.ID
+	if id == "" {
+		id = contact.Email
+	}
+	j.Items = append(j.Items, JournalItem{ID: id, Status: JournalPending, Contact: contact})
+}
+
+// Record stores the outcome of an attempt at the item with the given index.
+func (j *BulkJournal) Record(index int, err error) {
+	item := &j.Items[index]
+	item.Attempts++
+	if err != nil {
+		item.Status = JournalFailed
+		item.Error = err.Error()
+	} else {
+		item.Status = JournalSucceeded
+		item.Error = ""
+	}
+	j.UpdatedAt = time.Now()
+}
+
+// Remaining returns the indexes of items that are pending or failed.
+func (j *BulkJournal) Remaining() []int {
+	var remaining []int
+	for i, item := range j.Items {
+		if item.Status != JournalSucceeded {
+			remaining = append(remaining, i)
+		}
+	}
+	return remaining
+}
+
+// Failed returns the indexes of items whose last attempt failed.
+func (j *BulkJournal) Failed() []int {
+	var failed []int
+	for i, item := range j.Items {
+		if item.Status == JournalFailed {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+// AddRun records the outcome of a run.
+func (j *BulkJournal) AddRun(run BulkRun) {
+	j.Runs = append(j.Runs, run)
+	j.UpdatedAt = time.Now()
+}
+
+// Counts returns the number of items in each state.
+func (j *BulkJournal) Counts() (pending, succeeded, failed int) {
+	for _, item := range j.Items {
+		switch item.Status {
+		case JournalSucceeded:
+			succeeded++
+		case JournalFailed:
+			failed++
+		default:
+			pending++
+		}
+	}
+	return pending, succeeded, failed
+}
+
+// Complete reports whether every item succeeded.
+func (j *BulkJournal) Complete() bool {
+	return len(j.Remaining()) == 0
+}
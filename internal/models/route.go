@@ -3,6 +3,7 @@ package models
 
 import (
 	"fmt"
+	"net/netip"
 	"strings"
 	"time"
 )
@@ -118,11 +119,42 @@ func (r *RouteObject) ToRPSL() string {
 	return b.String()
 }
 
+// ToRPSLDelete returns the route as an RPSL deletion for mail submission:
+// the object as registered followed by the delete pseudo-attribute, which
+// carries the reason. The registry only deletes an object whose submitted
+// attributes match the stored ones. Use WithRPSLPassword to authenticate it.
+func (r *RouteObject) ToRPSLDelete(reason string) string {
+	return r.ToRPSL() + rpslDeleteAttribute(reason)
+}
+
+// rpslDeleteAttribute returns the delete pseudo-attribute line carrying
+// reason on one line, or a default reason if it is blank.
+func rpslDeleteAttribute(reason string) string {
+	reason = strings.Join(strings.Fields(reason), " ")
+	if reason == "" {
+		reason = "no longer in use"
+	}
+	return fmt.Sprintf("delete: %s\n", reason)
+}
+
+// WithRPSLPassword appends the password pseudo-attribute to an RPSL
+// submission, authenticating it against a maintainer's password auth.
+func WithRPSLPassword(rpsl, password string) (string, error) {
+	if password == "" {
+		return "", fmt.Errorf("password must not be empty")
+	}
+	if strings.ContainsAny(password, "\r\n") {
+		return "", fmt.Errorf("password must be a single line")
+	}
+	return rpsl + fmt.Sprintf("password: %s\n", password), nil
+}
+
 // RouteList is a collection of route objects.
 type RouteList struct {
 	Routes    []RouteObject `json:"routes"`
 	Timestamp time.Time     `json:"timestamp"`
 	Count     int           `json:"count"`
+	Truncated bool          `json:"truncated,omitempty"` // More routes matched than the result cap allowed
 }
 
 // NewRouteList creates a new route list with the current timestamp.
@@ -143,3 +175,63 @@ func (rl *RouteList) ByID() map[string]*RouteObject {
 	}
 	return m
 }
+
+// FilterPrefixes is the listing filter holding a comma-separated prefix
+// set; it matches routes equal to or more specific than any of them. The
+// API has no such filter, so clients apply it to the routes they receive.
+const FilterPrefixes = "prefixes"
+
+// Filter returns a new list holding the routes that match listing filters
+// (prefix, prefixes, origin, mnt-by), applied the same way as the API
+// applies them.
+func (rl *RouteList) Filter(filters map[string]string) *RouteList {
+	var routes []RouteObject
+	for _, route := range rl.Routes {
+		if route.MatchesFilters(filters) {
+			routes = append(routes, route)
+		}
+	}
+	return NewRouteList(routes)
+}
+
+// MatchesFilters reports whether the route matches listing filters.
+// Unknown filter keys are ignored.
+func (r *RouteObject) MatchesFilters(filters map[string]string) bool {
+	if prefix := filters["prefix"]; prefix != "" && r.Route != prefix {
+		return false
+	}
+	if set := filters[FilterPrefixes]; set != "" && !r.WithinPrefixes(set) {
+		return false
+	}
+	if origin := filters["origin"]; origin != "" && !strings.EqualFold(r.Origin, origin) {
+		return false
+	}
+	if mntBy := filters["mnt-by"]; mntBy != "" {
+		for _, mnt := range r.MntBy {
+			if strings.EqualFold(mnt, mntBy) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// WithinPrefixes reports whether the route's prefix equals or is more
+// specific than any prefix in a comma-separated set.
+func (r *RouteObject) WithinPrefixes(set string) bool {
+	route, err := netip.ParsePrefix(r.Route)
+	if err != nil {
+		return false
+	}
+	for _, entry := range strings.Split(set, ",") {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(entry))
+		if err != nil {
+			continue
+		}
+		if route.Bits() >= prefix.Bits() && prefix.Masked().Contains(route.Addr()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRouteMatchesFilters(t *testing.T) {
+	route := RouteObject{Route: "192.0.2.128/25", Origin: "AS64500", MntBy: []string{"MAINT-A", "MAINT-B"}}
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		want    bool
+	}{
+		{"no filters", nil, true},
+		{"exact prefix", map[string]string{"prefix": "192.0.2.128/25"}, true},
+		{"other prefix", map[string]string{"prefix": "192.0.2.0/24"}, false},
+		{"prefix set covering", map[string]string{FilterPrefixes: "198.51.100.0/24, 192.0.2.0/24"}, true},
+		{"prefix set equal", map[string]string{FilterPrefixes: "192.0.2.128/25"}, true},
+		{"prefix set more specific", map[string]string{FilterPrefixes: "192.0.2.128/26"}, false},
+		{"prefix set elsewhere", map[string]string{FilterPrefixes: "198.51.100.0/24"}, false},
+		{"prefix set with invalid entry", map[string]string{FilterPrefixes: "bogus, 192.0.2.0/24"}, true},
+		{"origin", map[string]string{"origin": "AS64500"}, true},
+		{"origin case", map[string]string{"origin": "as64500"}, true},
+		{"other origin", map[string]string{"origin": "AS64501"}, false},
+		{"maintainer", map[string]string{"mnt-by": "MAINT-B"}, true},
+		{"maintainer case", map[string]string{"mnt-by": "maint-a"}, true},
+		{"other maintainer", map[string]string{"mnt-by": "MAINT-C"}, false},
+		{"prefix and origin", map[string]string{"prefix": "192.0.2.128/25", "origin": "AS64500"}, true},
+		{"prefix and other origin", map[string]string{"prefix": "192.0.2.128/25", "origin": "AS64501"}, false},
+		{"origin and maintainer", map[string]string{"origin": "AS64500", "mnt-by": "MAINT-A"}, true},
+		{"origin and other maintainer", map[string]string{"origin": "AS64500", "mnt-by": "MAINT-C"}, false},
+		{"all filters", map[string]string{"prefix": "192.0.2.128/25", FilterPrefixes: "192.0.2.0/24", "origin": "AS64500", "mnt-by": "MAINT-B"}, true},
+		{"all filters but prefix set", map[string]string{"prefix": "192.0.2.128/25", FilterPrefixes: "203.0.113.0/24", "origin": "AS64500", "mnt-by": "MAINT-B"}, false},
+		{"empty values", map[string]string{"prefix": "", "origin": "", "mnt-by": ""}, true},
+		{"unknown key", map[string]string{"source": "RIPE"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := route.MatchesFilters(tt.filters); got != tt.want {
+				t.Errorf("MatchesFilters(%v) = %v, want %v", tt.filters, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteListFilter(t *testing.T) {
+	list := NewRouteList([]RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}},
+		{Route: "192.0.2.128/25", Origin: "AS64500", MntBy: []string{"MAINT-B"}},
+		{Route: "198.51.100.0/24", Origin: "AS64501", MntBy: []string{"MAINT-A"}},
+		{Route: "2001:db8::/32", Origin: "AS64500", MntBy: []string{"MAINT-A"}},
+	})
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		want    []string
+	}{
+		{"origin", map[string]string{"origin": "AS64500"}, []string{"192.0.2.0/24", "192.0.2.128/25", "2001:db8::/32"}},
+		{"maintainer", map[string]string{"mnt-by": "MAINT-A"}, []string{"192.0.2.0/24", "198.51.100.0/24", "2001:db8::/32"}},
+		{"origin and maintainer", map[string]string{"origin": "AS64500", "mnt-by": "MAINT-B"}, []string{"192.0.2.128/25"}},
+		{"prefix set", map[string]string{FilterPrefixes: "192.0.2.0/24,2001:db8::/16"}, []string{"192.0.2.0/24", "192.0.2.128/25", "2001:db8::/32"}},
+		{"prefix", map[string]string{"prefix": "198.51.100.0/24"}, []string{"198.51.100.0/24"}},
+		{"none match", map[string]string{"origin": "AS64502"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := list.Filter(tt.filters)
+
+			var got []string
+			for _, route := range filtered.Routes {
+				got = append(got, route.Route)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter(%v) = %v, want %v", tt.filters, got, tt.want)
+			}
+			if filtered.Count != len(tt.want) {
+				t.Errorf("Count = %d, want %d", filtered.Count, len(tt.want))
+			}
+		})
+	}
+
+	if list.Count != 4 {
+		t.Errorf("Filter() changed the original list to %d routes", list.Count)
+	}
+}
+
+func TestRouteToRPSLDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  RouteObject
+		reason string
+		want   string
+	}{
+		{"reason", RouteObject{Route: "192.0.2.0/24"}, "customer left", "delete: customer left\n"},
+		{"whitespace collapsed", RouteObject{Route: "192.0.2.0/24"}, "  customer\tleft \n  2024 ", "delete: customer left 2024\n"},
+		{"default reason", RouteObject{Route: "192.0.2.0/24"}, "", "delete: no longer in use\n"},
+		{"blank reason", RouteObject{Route: "192.0.2.0/24"}, " \n\t", "delete: no longer in use\n"},
+		{"route6", RouteObject{Route: "2001:db8::/32"}, "renumbered", "delete: renumbered\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.route.Origin = "AS64500"
+			tt.route.Descr = []string{"Example"}
+			tt.route.MntBy = []string{"MAINT-A"}
+			tt.route.Source = "RADB"
+
+			got := tt.route.ToRPSLDelete(tt.reason)
+			body := tt.route.ToRPSL()
+			if !strings.HasPrefix(got, body) {
+				t.Fatalf("ToRPSLDelete() = %q, want it to start with the ToRPSL body %q", got, body)
+			}
+			if tail := strings.TrimPrefix(got, body); tail != tt.want {
+				t.Errorf("ToRPSLDelete() ends with %q, want %q", tail, tt.want)
+			}
+			if strings.Count(got, "delete:") != 1 || !strings.HasSuffix(got, "\n") {
+				t.Errorf("ToRPSLDelete() = %q, want a single trailing delete line", got)
+			}
+		})
+	}
+}
+
+func TestWithRPSLPassword(t *testing.T) {
+	deletion := (&RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"}).ToRPSLDelete("returned")
+
+	got, err := WithRPSLPassword(deletion, "s3cret pass")
+	if err != nil {
+		t.Fatalf("WithRPSLPassword() failed: %v", err)
+	}
+	if want := deletion + "password: s3cret pass\n"; got != want {
+		t.Errorf("WithRPSLPassword() = %q, want %q", got, want)
+	}
+
+	for _, password := range []string{"", "line\nsource: RIPE", "carriage\rreturn"} {
+		if _, err := WithRPSLPassword(deletion, password); err == nil {
+			t.Errorf("WithRPSLPassword(%q) succeeded, want an error", password)
+		}
+	}
+}
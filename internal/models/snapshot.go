@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -22,6 +24,16 @@ const (
 	SnapshotTypeFull SnapshotType = "full"
 )
 
+const (
+	// MetadataScope is the metadata key holding the canonical filter scope
+	// of a snapshot. It is absent for full-account captures.
+	MetadataScope = "scope"
+
+	// MetadataFilterPrefix prefixes the metadata keys recording each filter
+	// used to capture a scoped snapshot (e.g. "filter.mnt-by").
+	MetadataFilterPrefix = "filter."
+)
+
 // Snapshot represents a point-in-time capture of data.
 // Snapshots are used for change detection and history tracking.
 type Snapshot struct {
@@ -37,9 +49,17 @@ type Snapshot struct {
 	// Note is an optional user-provided description
 	Note string `json:"note,omitempty"`
 
-	// Checksum is a SHA-256 hash of the data for integrity verification
+	// Checksum is a hash of the data for integrity verification
 	Checksum string `json:"checksum"`
 
+	// ChecksumAlgorithm is the hash used for Checksum and ObjectChecksums;
+	// empty means sha256
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"`
+
+	// ObjectChecksums holds a hash of each route and contact, keyed by URN,
+	// when per-object integrity is enabled
+	ObjectChecksums map[string]string `json:"object_checksums,omitempty"`
+
 	// Version is the snapshot format version
 	Version int `json:"version"`
 
@@ -51,13 +71,36 @@ type Snapshot struct {
 
 	// Metadata contains additional snapshot information
 	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Tags mark snapshots such as "baseline" or "pre-migration"; tagged
+	// snapshots are never removed by cleanup
+	Tags []string `json:"tags,omitempty"`
+
+	// ContentHash is a SHA-256 hash of the routes and contacts alone, without
+	// listing timestamps, so unchanged data hashes the same between captures
+	ContentHash string `json:"content_hash,omitempty"`
+
+	// AliasOf names the snapshot holding this snapshot's data. Snapshots
+	// whose content is unchanged from the previous one of the same type and
+	// scope are stored as alias records without data of their own.
+	AliasOf string `json:"alias_of,omitempty"`
+
+	// Counts holds the number of routes and contacts of a listed snapshot,
+	// whose routes and contacts are not loaded. It is never stored.
+	Counts *SnapshotCounts `json:"counts,omitempty"`
+}
+
+// SnapshotCounts is the number of objects in a snapshot.
+type SnapshotCounts struct {
+	Routes   int `json:"routes"`
+	Contacts int `json:"contacts"`
 }
 
 // NewSnapshot creates a new snapshot with the current timestamp.
 func NewSnapshot(snapshotType SnapshotType, note string) *Snapshot {
 	now := time.Now().UTC()
 	return &Snapshot{
-		ID:        fmt.Sprintf("%s-%d", snapshotType, now.Unix()),
+		ID:        fmt.Sprintf("%s-%d", snapshotType, now.UnixNano()),
 		Timestamp: now,
 		Type:      snapshotType,
 		Note:      note,
@@ -66,10 +109,158 @@ func NewSnapshot(snapshotType SnapshotType, note string) *Snapshot {
 	}
 }
 
+// NewScopedSnapshot creates a snapshot of data captured with the given filters.
+// The filters are recorded in metadata and the scope is embedded in the ID,
+// so filtered captures are never mistaken for full-account snapshots.
+// With no filters it is equivalent to NewSnapshot.
+func NewScopedSnapshot(snapshotType SnapshotType, note string, filters map[string]string) *Snapshot {
+	snapshot := NewSnapshot(snapshotType, note)
+
+	scope := FilterScope(filters)
+	if scope == "" {
+		return snapshot
+	}
+
+	snapshot.ID = fmt.Sprintf("%s-%s-%d", snapshotType, scopeSlug(scope), snapshot.Timestamp.UnixNano())
+	snapshot.Metadata[MetadataScope] = scope
+	for key, value := range filters {
+		if value != "" {
+			snapshot.Metadata[MetadataFilterPrefix+key] = value
+		}
+	}
+
+	return snapshot
+}
+
+// FilterScope returns a canonical representation of a filter set, with keys
+// sorted and empty values dropped. An empty string denotes a full capture.
+func FilterScope(filters map[string]string) string {
+	keys := make([]string, 0, len(filters))
+	for key, value := range filters {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + filters[key]
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// scopeSlug converts a scope into a string safe for use in IDs and file names.
+func scopeSlug(scope string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, scope)
+
+	if len(slug) > 64 {
+		slug = slug[:64]
+	}
+	return slug
+}
+
+// Scope returns the filter scope the snapshot was captured with,
+// or an empty string for a full-account capture.
+func (s *Snapshot) Scope() string {
+	return s.Metadata[MetadataScope]
+}
+
+// Filters returns the filters the snapshot was captured with, or nil for a
+// full-account capture.
+func (s *Snapshot) Filters() map[string]string {
+	var filters map[string]string
+	for key, value := range s.Metadata {
+		if name, ok := strings.CutPrefix(key, MetadataFilterPrefix); ok {
+			if filters == nil {
+				filters = make(map[string]string)
+			}
+			filters[name] = value
+		}
+	}
+	return filters
+}
+
+// IsFullScope returns true if the snapshot captured unfiltered data.
+func (s *Snapshot) IsFullScope() bool {
+	return s.Scope() == ""
+}
+
+// AddTags adds the given tags, ignoring duplicates and case.
+func (s *Snapshot) AddTags(tags ...string) {
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || s.HasTag(tag) {
+			continue
+		}
+		s.Tags = append(s.Tags, tag)
+	}
+	sort.Strings(s.Tags)
+}
+
+// RemoveTags removes the given tags.
+func (s *Snapshot) RemoveTags(tags ...string) {
+	kept := s.Tags[:0]
+	for _, existing := range s.Tags {
+		if !containsLabel(tags, existing) {
+			kept = append(kept, existing)
+		}
+	}
+	s.Tags = kept
+	if len(s.Tags) == 0 {
+		s.Tags = nil
+	}
+}
+
+// HasTag reports whether the snapshot carries tag, ignoring case.
+func (s *Snapshot) HasTag(tag string) bool {
+	return containsLabel(s.Tags, tag)
+}
+
+// ItemCount returns the number of routes and contacts in the snapshot.
+func (s *Snapshot) ItemCount() int {
+	if s.Counts != nil {
+		return s.Counts.Routes + s.Counts.Contacts
+	}
+	count := 0
+	if s.Routes != nil {
+		count += s.Routes.Count
+	}
+	if s.Contacts != nil {
+		count += s.Contacts.Count
+	}
+	return count
+}
+
 // ComputeChecksum calculates and updates the checksum for this snapshot.
-// The checksum is computed over the data content (routes/contacts).
+// The checksum is computed over the data content (routes/contacts), along
+// with per-object hashes if they are enabled.
 func (s *Snapshot) ComputeChecksum() error {
-	// Create a consistent representation of the data
+	checksum, err := s.dataChecksum()
+	if err != nil {
+		return err
+	}
+	s.Checksum = checksum
+
+	if s.ObjectChecksums != nil {
+		if s.ObjectChecksums, err = s.objectChecksums(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dataChecksum hashes a consistent representation of the data.
+func (s *Snapshot) dataChecksum() (string, error) {
 	data := struct {
 		Routes   *RouteList   `json:"routes,omitempty"`
 		Contacts *ContactList `json:"contacts,omitempty"`
@@ -80,30 +271,50 @@ func (s *Snapshot) ComputeChecksum() error {
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data for checksum: %w", err)
+		return "", fmt.Errorf("failed to marshal data for checksum: %w", err)
+	}
+
+	return hashHex(s.ChecksumAlgorithm, jsonData)
+}
+
+// ComputeContentHash calculates and updates the content hash for this snapshot.
+func (s *Snapshot) ComputeContentHash() error {
+	data := struct {
+		Routes   []RouteObject `json:"routes,omitempty"`
+		Contacts []Contact     `json:"contacts,omitempty"`
+	}{}
+	if s.Routes != nil {
+		data.Routes = s.Routes.Routes
+	}
+	if s.Contacts != nil {
+		data.Contacts = s.Contacts.Contacts
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data for content hash: %w", err)
 	}
 
 	hash := sha256.Sum256(jsonData)
-	s.Checksum = hex.EncodeToString(hash[:])
+	s.ContentHash = hex.EncodeToString(hash[:])
 
 	return nil
 }
 
-// VerifyChecksum verifies the integrity of the snapshot.
+// VerifyChecksum verifies the integrity of the snapshot. A mismatch is
+// reported as an *IntegrityError.
 func (s *Snapshot) VerifyChecksum() error {
 	if s.Checksum == "" {
 		return fmt.Errorf("no checksum present")
 	}
 
-	originalChecksum := s.Checksum
-	s.Checksum = "" // Clear for recomputation
-
-	if err := s.ComputeChecksum(); err != nil {
+	checksum, err := s.dataChecksum()
+	if err != nil {
 		return fmt.Errorf("failed to compute checksum: %w", err)
 	}
 
-	if s.Checksum != originalChecksum {
-		return fmt.Errorf("checksum mismatch: expected %s, got %s", originalChecksum, s.Checksum)
+	if checksum != s.Checksum {
+		return s.integrityError(s.Checksum, checksum)
 	}
 
 	return nil
@@ -140,6 +351,15 @@ func (s *Snapshot) Validate() error {
 		return fmt.Errorf("invalid snapshot type: %s", s.Type)
 	}
 
+	// A capped listing is not the registry's state; diffs against it would
+	// report the objects past the cap as removed
+	if s.Routes != nil && s.Routes.Truncated {
+		return fmt.Errorf("snapshot routes were truncated by api.max_results")
+	}
+	if s.Contacts != nil && s.Contacts.Truncated {
+		return fmt.Errorf("snapshot contacts were truncated by api.max_results")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,128 @@
+package models
+
+import "time"
+
+// daemonHeartbeatTimeout is how long a daemon may go without a heartbeat
+// before it is considered dead.
+const daemonHeartbeatTimeout = 3 * time.Minute
+
+// ClientStatus records the API client's most recent health observations.
+type ClientStatus struct {
+	// RequestsPerMinute is the client's current request rate limit, which
+	// adapts to rate limit responses from the API
+	RequestsPerMinute int `json:"requests_per_minute"`
+
+	// ConfiguredRequestsPerMinute is the configured request rate limit
+	ConfiguredRequestsPerMinute int `json:"configured_requests_per_minute"`
+
+	// EndpointRequestsPerMinute is the current rate limit of each endpoint
+	// class with a limit of its own
+	EndpointRequestsPerMinute map[string]int `json:"endpoint_requests_per_minute,omitempty"`
+
+	// RateLimit is the server-reported request budget, if provided
+	RateLimit *RateLimitStatus `json:"rate_limit,omitempty"`
+
+	// Requests is the number of HTTP requests sent, including retries
+	Requests int `json:"requests"`
+
+	// ConsecutiveFailures counts failed requests since the last success
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// LastRequestAt is when the last request completed
+	LastRequestAt *time.Time `json:"last_request_at,omitempty"`
+
+	// LastError describes the most recent failed request
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorAt is when the most recent failure happened
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+
+	// CredentialsRejected is set when the API answered 401 Unauthorized and
+	// cleared by the next successful request
+	CredentialsRejected bool `json:"credentials_rejected,omitempty"`
+}
+
+// RateLimitStatus is the request budget reported by the server in
+// X-RateLimit-* or RateLimit-* response headers.
+type RateLimitStatus struct {
+	Limit     int        `json:"limit,omitempty"`
+	Remaining int        `json:"remaining"`
+	Reset     *time.Time `json:"reset,omitempty"`
+}
+
+// DaemonStatus is the heartbeat written by a running daemon or serve process.
+type DaemonStatus struct {
+	// PID is the process ID of the daemon
+	PID int `json:"pid"`
+
+	// Command is the command running the daemon (daemon or serve)
+	Command string `json:"command"`
+
+	// Interval is the current check interval in seconds (0 if checks are not
+	// scheduled); it changes over time with an adaptive schedule
+	Interval int `json:"interval"`
+
+	// StartedAt is when the daemon started
+	StartedAt time.Time `json:"started_at"`
+
+	// HeartbeatAt is when the daemon last reported in
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+
+	// LastCheckAt is when the last check cycle finished
+	LastCheckAt *time.Time `json:"last_check_at,omitempty"`
+
+	// LastCheckError is the error from the last check cycle, if it failed
+	LastCheckError string `json:"last_check_error,omitempty"`
+
+	// RequestsPerMinute is the daemon client's current request rate limit
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+
+	// StoppedAt is set when the daemon shuts down cleanly
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+// Alive reports whether the daemon is running and has sent a recent heartbeat.
+func (s *DaemonStatus) Alive(now time.Time) bool {
+	return s.StoppedAt == nil && now.Sub(s.HeartbeatAt) < daemonHeartbeatTimeout
+}
+
+// DaemonState is the runtime state a daemon or serve process keeps in the
+// state directory, so that after a restart it resumes where it left off.
+type DaemonState struct {
+	// LastCheckAt is when the last route check finished
+	LastCheckAt *time.Time `json:"last_check_at,omitempty"`
+
+	// LastSuccessAt is when the last successful route check finished
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+
+	// LastError is the error from the last route check, if it failed
+	LastError string `json:"last_error,omitempty"`
+
+	// ConsecutiveFailures counts the daemon loop's failed checks since the
+	// last success, which its failure backoff continues from
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+
+	// FailingSince is when the first of those failures happened
+	FailingSince *time.Time `json:"failing_since,omitempty"`
+
+	// Scopes holds the state of the whole account's checks under "" and of
+	// each watch target's under its snapshot scope
+	Scopes map[string]*CheckState `json:"scopes,omitempty"`
+}
+
+// CheckState is the state of the checks of the whole account or of one
+// watch target.
+type CheckState struct {
+	// Target is the watch target's name, empty for the whole account
+	Target string `json:"target,omitempty"`
+
+	// LastSnapshotID is the snapshot the last successful check saved, which
+	// the next check compares against
+	LastSnapshotID string `json:"last_snapshot_id,omitempty"`
+
+	// LastCheckAt is when the last check finished
+	LastCheckAt *time.Time `json:"last_check_at,omitempty"`
+
+	// ConsecutiveFailures counts failed checks since the last success
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
+}
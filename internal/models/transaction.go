@@ -0,0 +1,157 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// TransactionStep names one step of a multi-step command.
+type TransactionStep string
+
+const (
+	StepSnapshot  TransactionStep = "snapshot"  // Capture the object before the write
+	StepWrite     TransactionStep = "write"     // Send the write to the API
+	StepChangelog TransactionStep = "changelog" // Logged by earlier versions; completes without writing
+)
+
+// TransactionStatus is the state of a command transaction.
+type TransactionStatus string
+
+const (
+	TransactionPending    TransactionStatus = "pending"
+	TransactionCommitted  TransactionStatus = "committed"
+	TransactionRolledBack TransactionStatus = "rolled-back"
+)
+
+// TransactionStepRecord records whether a step has completed.
+type TransactionStepRecord struct {
+	Name        TransactionStep `json:"name"`
+	Done        bool            `json:"done"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// Transaction records the progress of a multi-step command that changes a
+// single route, so a command interrupted between steps can be resumed or
+// rolled back instead of leaving local state inconsistent with the API.
+// Before is nil for creates and After is nil for deletes.
+type Transaction struct {
+	ID         string                  `json:"id"`
+	Operation  ChangeType              `json:"operation"`
+	ObjectID   string                  `json:"object_id"`
+	Before     *RouteObject            `json:"before,omitempty"`
+	After      *RouteObject            `json:"after,omitempty"`
+	SnapshotID string                  `json:"snapshot_id,omitempty"`
+	Status     TransactionStatus       `json:"status"`
+	Steps      []TransactionStepRecord `json:"steps"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+// NewRouteTransaction creates a pending transaction for a route change.
+// Creates have nothing to snapshot, so they skip the snapshot step.
+func NewRouteTransaction(operation ChangeType, before, after *RouteObject) *Transaction {
+	now := time.Now()
+	tx := &Transaction{
+		Operation: operation,
+		Before:    before,
+		After:     after,
+		Status:    TransactionPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if after != nil {
+		tx.ObjectID = after.ID()
+	} else if before != nil {
+		tx.ObjectID = before.ID()
+	}
+	tx.ID = fmt.Sprintf("route-%s-%d", operation, now.UnixNano())
+
+	if before != nil {
+		tx.Steps = append(tx.Steps, TransactionStepRecord{Name: StepSnapshot})
+	}
+	tx.Steps = append(tx.Steps, TransactionStepRecord{Name: StepWrite})
+	return tx
+}
+
+// StepDone reports whether a step has completed. Steps the transaction
+// does not have count as done.
+func (t *Transaction) StepDone(step TransactionStep) bool {
+	for _, record := range t.Steps {
+		if record.Name == step {
+			return record.Done
+		}
+	}
+	return true
+}
+
+// CompleteStep marks a step as done.
+func (t *Transaction) CompleteStep(step TransactionStep) {
+	now := time.Now()
+	for i := range t.Steps {
+		if t.Steps[i].Name == step {
+			t.Steps[i].Done = true
+			t.Steps[i].CompletedAt = &now
+			t.Steps[i].Error = ""
+		}
+	}
+	t.UpdatedAt = now
+}
+
+// FailStep records the error that stopped a step.
+func (t *Transaction) FailStep(step TransactionStep, err error) {
+	for i := range t.Steps {
+		if t.Steps[i].Name == step {
+			t.Steps[i].Error = err.Error()
+		}
+	}
+	t.UpdatedAt = time.Now()
+}
+
+// NextStep returns the first step that has not completed, or "" when all have.
+func (t *Transaction) NextStep() TransactionStep {
+	for _, record := range t.Steps {
+		if !record.Done {
+			return record.Name
+		}
+	}
+	return ""
+}
+
+// Change returns the change the transaction applies, or with reverse the
+// change that rolls it back.
+func (t *Transaction) Change(reverse bool) Change {
+	before, after := t.Before, t.After
+	changeType := t.Operation
+	if reverse {
+		before, after = after, before
+		switch changeType {
+		case ChangeTypeAdded:
+			changeType = ChangeTypeRemoved
+		case ChangeTypeRemoved:
+			changeType = ChangeTypeAdded
+		}
+	}
+
+	change := Change{
+		Type:       changeType,
+		ObjectType: "route",
+		ObjectID:   t.ObjectID,
+		Timestamp:  time.Now().UTC(),
+		Details:    map[string]interface{}{"transaction": t.ID},
+	}
+	if before != nil {
+		change.Before = before
+	}
+	if after != nil {
+		change.After = after
+	}
+	if before != nil && after != nil {
+		var fields []string
+		for _, field := range DetectFieldChanges(before, after) {
+			fields = append(fields, field.Field)
+		}
+		change.Details["field_changes"] = fields
+	}
+	return change
+}
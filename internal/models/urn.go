@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// URNScheme prefixes canonical object references such as
+// radb:route:192.0.2.0/24:AS64500 and radb:contact:1234.
+const URNScheme = "radb:"
+
+// ObjectRef identifies a route or contact independently of the command
+// that names it.
+type ObjectRef struct {
+	// ObjectType is "route" or "contact"
+	ObjectType string
+
+	// Prefix and Origin identify a route
+	Prefix string
+	Origin string
+
+	// ContactID identifies a contact
+	ContactID string
+}
+
+// RouteURN returns the canonical reference to a route.
+func RouteURN(prefix, origin string) string {
+	return fmt.Sprintf("%sroute:%s:%s", URNScheme, prefix, origin)
+}
+
+// ContactURN returns the canonical reference to a contact.
+func ContactURN(id string) string {
+	return fmt.Sprintf("%scontact:%s", URNScheme, id)
+}
+
+// URN returns the canonical reference to the route.
+func (r *RouteObject) URN() string {
+	return RouteURN(r.Route, r.Origin)
+}
+
+// URN returns the canonical reference to the contact.
+func (c *Contact) URN() string {
+	return ContactURN(c.ID)
+}
+
+// ObjectURN returns the canonical reference for an object type and the
+// object ID used in the changelog and annotations, or "" if the ID cannot
+// be mapped.
+func ObjectURN(objectType, objectID string) string {
+	switch objectType {
+	case "route":
+		// Route IDs are "<prefix>-<origin>"; prefixes never contain a hyphen
+		i := strings.LastIndex(objectID, "-")
+		if i <= 0 {
+			return ""
+		}
+		return RouteURN(objectID[:i], objectID[i+1:])
+	case "contact":
+		return ContactURN(objectID)
+	}
+	return ""
+}
+
+// IsURN reports whether s uses the canonical reference syntax.
+func IsURN(s string) bool {
+	return strings.HasPrefix(s, URNScheme)
+}
+
+// ParseURN parses a canonical object reference. Route origins are
+// normalized to the AS-prefixed, upper-case form.
+func ParseURN(s string) (ObjectRef, error) {
+	if !IsURN(s) {
+		return ObjectRef{}, fmt.Errorf("invalid object reference %q: must start with %s", s, URNScheme)
+	}
+
+	objectType, rest, ok := strings.Cut(strings.TrimPrefix(s, URNScheme), ":")
+	if !ok || rest == "" {
+		return ObjectRef{}, fmt.Errorf("invalid object reference %q: missing object identity", s)
+	}
+
+	switch objectType {
+	case "route":
+		// IPv6 prefixes contain colons, so the origin follows the last one
+		i := strings.LastIndex(rest, ":")
+		if i <= 0 || i == len(rest)-1 {
+			return ObjectRef{}, fmt.Errorf("invalid route reference %q: want %sroute:<prefix>:<asn>", s, URNScheme)
+		}
+		origin := strings.ToUpper(rest[i+1:])
+		if !strings.HasPrefix(origin, "AS") {
+			origin = "AS" + origin
+		}
+		return ObjectRef{ObjectType: "route", Prefix: rest[:i], Origin: origin}, nil
+	case "contact":
+		return ObjectRef{ObjectType: "contact", ContactID: rest}, nil
+	}
+
+	return ObjectRef{}, fmt.Errorf("invalid object reference %q: unknown object type %q", s, objectType)
+}
+
+// ObjectID returns the ID the changelog and annotations use for the object.
+func (ref ObjectRef) ObjectID() string {
+	if ref.ObjectType == "route" {
+		return fmt.Sprintf("%s-%s", ref.Prefix, ref.Origin)
+	}
+	return ref.ContactID
+}
+
+// String returns the canonical reference.
+func (ref ObjectRef) String() string {
+	if ref.ObjectType == "route" {
+		return RouteURN(ref.Prefix, ref.Origin)
+	}
+	return ContactURN(ref.ContactID)
+}
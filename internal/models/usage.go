@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Command results recorded in usage records.
+const (
+	UsageResultOK          = "ok"
+	UsageResultError       = "error"
+	UsageResultInterrupted = "interrupted"
+)
+
+// UsageRecord is one command run recorded by opt-in local telemetry. It
+// holds no arguments, flag values, or object data.
+type UsageRecord struct {
+	Command    string    `json:"command"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Result     string    `json:"result"`
+	Version    string    `json:"version,omitempty"`
+}
+
+// UsageSummary aggregates the recorded runs of one command.
+type UsageSummary struct {
+	Command     string    `json:"command"`
+	Runs        int       `json:"runs"`
+	Errors      int       `json:"errors"`
+	Interrupted int       `json:"interrupted"`
+	MeanMs      int64     `json:"mean_ms"`
+	P95Ms       int64     `json:"p95_ms"`
+	MaxMs       int64     `json:"max_ms"`
+	LastRunAt   time.Time `json:"last_run_at"`
+}
+
+// ErrorRate returns the fraction of runs that failed.
+func (s UsageSummary) ErrorRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Runs)
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// chatMaxLines caps the changes and violations listed in a chat message;
+// the rest are counted.
+const chatMaxLines = 20
+
+// SlackNotifier posts notifications to a Slack incoming webhook.
+type SlackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// Ensure SlackNotifier implements Notifier.
+var _ Notifier = (*SlackNotifier)(nil)
+
+// NewSlackNotifier creates a notifier that posts to a Slack incoming
+// webhook URL. The webhook decides the channel.
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Notify posts the notification as a message listing its changes.
+func (s *SlackNotifier) Notify(ctx context.Context, notification *Notification) error {
+	title, lines := chatMessage(notification)
+
+	text := "*" + slackEscape(title) + "*"
+	if len(lines) > 0 {
+		text += "\n```\n" + slackEscape(strings.Join(lines, "\n")) + "\n```"
+	}
+	return postJSON(ctx, s.client, s.url, nil, map[string]string{"text": text})
+}
+
+// slackEscape escapes the characters Slack treats as markup.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// TeamsNotifier posts notifications to a Microsoft Teams incoming webhook
+// as an Adaptive Card.
+type TeamsNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// Ensure TeamsNotifier implements Notifier.
+var _ Notifier = (*TeamsNotifier)(nil)
+
+// NewTeamsNotifier creates a notifier that posts to a Teams incoming
+// webhook or workflow URL.
+func NewTeamsNotifier(url string) *TeamsNotifier {
+	return &TeamsNotifier{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Notify posts the notification as a card listing its changes.
+func (t *TeamsNotifier) Notify(ctx context.Context, notification *Notification) error {
+	title, lines := chatMessage(notification)
+
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": title, "weight": "Bolder", "wrap": true},
+	}
+	// One block per line, since Teams does not reliably keep single line
+	// breaks within a block
+	for _, line := range lines {
+		body = append(body, map[string]interface{}{
+			"type": "TextBlock", "text": line, "fontType": "Monospace", "spacing": "None", "wrap": true,
+		})
+	}
+
+	card := map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+	return postJSON(ctx, t.client, t.url, nil, card)
+}
+
+// chatMessage returns a one-line title for a notification and a line per
+// change or violation, up to chatMaxLines.
+func chatMessage(notification *Notification) (string, []string) {
+	var title strings.Builder
+	if len(notification.Violations) > 0 {
+		fmt.Fprintf(&title, "RADb: %d route assertion violations", len(notification.Violations))
+	} else {
+		fmt.Fprintf(&title, "RADb: %d changes", len(notification.Changes))
+	}
+	if notification.Team != "" {
+		fmt.Fprintf(&title, " for %s", notification.Team)
+	}
+	fmt.Fprintf(&title, " in %s", notification.SnapshotID)
+	switch {
+	case notification.Baseline != "":
+		fmt.Fprintf(&title, " since baseline %s", notification.Baseline)
+	case notification.PreviousID != "":
+		fmt.Fprintf(&title, " since %s", notification.PreviousID)
+	}
+
+	var lines []string
+	for _, change := range notification.Changes {
+		lines = append(lines, fmt.Sprintf("%s %s %s", changeMarker(change.Type), change.ObjectType, change.ObjectID))
+	}
+	for _, v := range notification.Violations {
+		lines = append(lines, fmt.Sprintf("! %s %s (%s): %s", v.Route, v.Origin, v.Assertion, v.Detail))
+	}
+	if len(lines) > chatMaxLines {
+		more := len(lines) - chatMaxLines
+		lines = append(lines[:chatMaxLines], fmt.Sprintf("... and %d more", more))
+	}
+	return title.String(), lines
+}
+
+// changeMarker returns the diff-style marker of a change type.
+func changeMarker(changeType models.ChangeType) string {
+	switch changeType {
+	case models.ChangeTypeAdded:
+		return "+"
+	case models.ChangeTypeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// chatServer records the JSON bodies, and the headers, posted to it.
+func chatServer(t *testing.T) (*httptest.Server, *[]map[string]interface{}, *[]http.Header) {
+	t.Helper()
+
+	var (
+		received []map[string]interface{}
+		headers  []http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, body)
+		headers = append(headers, r.Header)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received, &headers
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, received, _ := chatServer(t)
+
+	var changes []models.Change
+	for i := 0; i < chatMaxLines+5; i++ {
+		route := &models.RouteObject{Route: fmt.Sprintf("10.0.%d.0/24", i), Origin: "AS64500", Source: "RADB"}
+		changes = append(changes, routeChange(models.ChangeTypeAdded, nil, route))
+	}
+	notification := &Notification{Team: "noc<ops>", SnapshotID: "route-2", PreviousID: "route-1", Changes: changes}
+
+	if err := NewSlackNotifier(server.URL).Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	if len(*received) != 1 {
+		t.Fatalf("Slack received %d messages, want 1", len(*received))
+	}
+
+	text, _ := (*received)[0]["text"].(string)
+	for _, want := range []string{
+		"*RADb: 25 changes for noc&lt;ops&gt; in route-2 since route-1*",
+		"+ route 10.0.0.0/24-AS64500",
+		"... and 5 more",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("message is missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "10.0.20.0/24") {
+		t.Errorf("message lists more than %d changes:\n%s", chatMaxLines, text)
+	}
+}
+
+func TestTeamsNotifier(t *testing.T) {
+	server, received, _ := chatServer(t)
+
+	removed := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"}
+	notification := &Notification{
+		SnapshotID: "route-2",
+		PreviousID: "route-1",
+		Baseline:   "golden",
+		Changes:    []models.Change{routeChange(models.ChangeTypeRemoved, removed, nil)},
+	}
+
+	if err := NewTeamsNotifier(server.URL).Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	if len(*received) != 1 {
+		t.Fatalf("Teams received %d messages, want 1", len(*received))
+	}
+
+	data, _ := json.Marshal((*received)[0])
+	var card struct {
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Body []struct {
+					Text string `json:"text"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(data, &card); err != nil {
+		t.Fatal(err)
+	}
+	if len(card.Attachments) != 1 || card.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("Teams message is not an Adaptive Card: %s", data)
+	}
+	body := card.Attachments[0].Content.Body
+	if len(body) != 2 || body[0].Text != "RADb: 1 changes in route-2 since baseline golden" || body[1].Text != "- route 192.0.2.0/24-AS64500" {
+		t.Errorf("card body = %+v", body)
+	}
+}
@@ -0,0 +1,271 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// emailTimeout bounds a single email delivery, from connecting to QUIT.
+const emailTimeout = 30 * time.Second
+
+// DefaultEmailSubject is the subject template used when a sink sets none.
+const DefaultEmailSubject = `[radb] {{if .Violations}}{{len .Violations}} assertion violations{{else}}{{len .Changes}} changes{{end}}{{if .Team}} for {{.Team}}{{end}} in {{.SnapshotID}}`
+
+// DefaultEmailBody is the body template used when a sink sets none.
+const DefaultEmailBody = `{{if .Changes -}}
+Changes detected between {{if .Baseline}}baseline {{.Baseline}} ({{.PreviousID}}){{else}}{{.PreviousID}}{{end}} and {{.SnapshotID}}{{if .Team}} for team {{.Team}}{{end}}:
+{{- with .Added}}
+
+Added ({{len .}}):
+{{- range .}}
+  + {{.ObjectType}} {{.ObjectID}}
+{{- end}}
+{{- end}}
+{{- with .Removed}}
+
+Removed ({{len .}}):
+{{- range .}}
+  - {{.ObjectType}} {{.ObjectID}}
+{{- end}}
+{{- end}}
+{{- with .Modified}}
+
+Modified ({{len .}}):
+{{- range .}}
+  ~ {{.ObjectType}} {{.ObjectID}}
+{{- end}}
+{{- end}}
+{{- range .Diffs}}
+
+{{trimSpace .Diff}}
+{{- end}}
+{{- end}}
+{{- if .Violations}}
+Route assertions violated in {{.SnapshotID}}{{if .Team}} for team {{.Team}}{{end}}:
+{{range .Violations}}
+  ! {{.Route}} {{.Origin}} ({{.Assertion}}): {{.Detail}}
+{{- end}}
+{{- end}}
+
+Detected {{.Time.UTC.Format "2006-01-02 15:04:05 MST"}}.
+`
+
+// emailFuncs are the functions available to email templates.
+var emailFuncs = template.FuncMap{
+	"join":      strings.Join,
+	"trimSpace": strings.TrimSpace,
+}
+
+// EmailNotifier sends notifications as plain-text email over SMTP.
+type EmailNotifier struct {
+	server  *url.URL
+	from    string
+	to      []string
+	subject *template.Template
+	body    *template.Template
+}
+
+// Ensure EmailNotifier implements Notifier.
+var _ Notifier = (*EmailNotifier)(nil)
+
+// NewEmailNotifier creates a notifier that mails from and to the given
+// addresses through the server at rawURL: smtp://[user[:password]@]host[:port]
+// uses STARTTLS when the server offers it (port 587 by default), smtps://
+// connects over TLS (port 465 by default). The subject and body are Go
+// text/template templates over the notification, with its changes also
+// grouped as Added, Removed, and Modified; empty strings select
+// DefaultEmailSubject and DefaultEmailBody.
+func NewEmailNotifier(rawURL, from string, to []string, subject, body string) (*EmailNotifier, error) {
+	server, err := url.Parse(rawURL)
+	if err != nil || (server.Scheme != "smtp" && server.Scheme != "smtps") || server.Hostname() == "" {
+		return nil, fmt.Errorf("email server must be an smtp or smtps URL")
+	}
+	if from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("email sender and recipients are required")
+	}
+
+	if subject == "" {
+		subject = DefaultEmailSubject
+	}
+	if body == "" {
+		body = DefaultEmailBody
+	}
+	subjectTmpl, err := template.New("subject").Funcs(emailFuncs).Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject template: %w", err)
+	}
+	bodyTmpl, err := template.New("body").Funcs(emailFuncs).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+
+	return &EmailNotifier{
+		server:  server,
+		from:    from,
+		to:      to,
+		subject: subjectTmpl,
+		body:    bodyTmpl,
+	}, nil
+}
+
+// emailData is the notification as seen by the templates, with its changes
+// grouped by type.
+type emailData struct {
+	*Notification
+	Added    []models.Change
+	Removed  []models.Change
+	Modified []models.Change
+}
+
+func newEmailData(notification *Notification) *emailData {
+	data := &emailData{Notification: notification}
+	for _, change := range notification.Changes {
+		switch change.Type {
+		case models.ChangeTypeAdded:
+			data.Added = append(data.Added, change)
+		case models.ChangeTypeRemoved:
+			data.Removed = append(data.Removed, change)
+		case models.ChangeTypeModified:
+			data.Modified = append(data.Modified, change)
+		}
+	}
+	return data
+}
+
+// Notify renders and sends the notification.
+func (e *EmailNotifier) Notify(ctx context.Context, notification *Notification) error {
+	message, err := e.message(notification)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, emailTimeout)
+	defer cancel()
+
+	client, err := e.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := e.send(client, message); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message renders the notification as an RFC 5322 message.
+func (e *EmailNotifier) message(notification *Notification) ([]byte, error) {
+	data := newEmailData(notification)
+
+	var subject, body bytes.Buffer
+	if err := e.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := e.body.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("failed to render email body: %w", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(subject.String()), " ")))
+	fmt.Fprintf(&msg, "Date: %s\r\n", notification.Time.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n")
+	fmt.Fprintf(&msg, "Content-Transfer-Encoding: 8bit\r\n")
+	fmt.Fprintf(&msg, "\r\n")
+	// The DATA writer converts line endings and escapes leading dots
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// dial connects to the server, over TLS for smtps, and authenticates when
+// the URL carries credentials.
+func (e *EmailNotifier) dial(ctx context.Context) (*smtp.Client, error) {
+	host := e.server.Hostname()
+	port := e.server.Port()
+	if port == "" {
+		port = "587"
+		if e.server.Scheme == "smtps" {
+			port = "465"
+		}
+	}
+	addr := net.JoinHostPort(host, port)
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	tlsConfig := &tls.Config{ServerName: host}
+	if e.server.Scheme == "smtps" {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	if e.server.Scheme == "smtp" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("STARTTLS failed: %w", err)
+			}
+		}
+	}
+
+	if user := e.server.User; user != nil {
+		password, _ := user.Password()
+		if err := client.Auth(smtp.PlainAuth("", user.Username(), password, host)); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// send transmits the message to every recipient.
+func (e *EmailNotifier) send(client *smtp.Client, message []byte) error {
+	if err := client.Mail(e.from); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	for _, to := range e.to {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected message: %w", err)
+	}
+	return nil
+}
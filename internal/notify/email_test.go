@@ -0,0 +1,164 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// smtpMessage is a message received by fakeSMTPServer.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer accepts one SMTP session without extensions and sends the
+// message it receives on the returned channel.
+func fakeSMTPServer(t *testing.T) (string, <-chan smtpMessage) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		var msg smtpMessage
+		tp.PrintfLine("220 test ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 test")
+			case "MAIL":
+				msg.from = arg
+				tp.PrintfLine("250 OK")
+			case "RCPT":
+				msg.to = append(msg.to, arg)
+				tp.PrintfLine("250 OK")
+			case "DATA":
+				tp.PrintfLine("354 Go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				msg.data = string(data)
+				tp.PrintfLine("250 Queued")
+			case "QUIT":
+				tp.PrintfLine("221 Bye")
+				messages <- msg
+				return
+			default:
+				tp.PrintfLine("502 Unsupported")
+			}
+		}
+	}()
+
+	return "smtp://" + listener.Addr().String(), messages
+}
+
+func TestEmailNotifier(t *testing.T) {
+	server, messages := fakeSMTPServer(t)
+
+	notifier, err := NewEmailNotifier(server, "radb@example.com", []string{"noc@example.com", "peering@example.com"}, "", "")
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() failed: %v", err)
+	}
+
+	before := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"Old"}, Source: "RADB"}
+	after := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"New"}, Source: "RADB"}
+	added := &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64501", Source: "RADB"}
+	changes := []models.Change{
+		routeChange(models.ChangeTypeModified, before, after),
+		routeChange(models.ChangeTypeAdded, nil, added),
+	}
+	notification := &Notification{
+		Time:       time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Team:       "noc",
+		SnapshotID: "route-2",
+		PreviousID: "route-1",
+		Changes:    changes,
+		Diffs:      rpslDiffs(changes),
+	}
+
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+
+	var msg smtpMessage
+	select {
+	case msg = <-messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	if msg.from != "FROM:<radb@example.com>" || len(msg.to) != 2 {
+		t.Errorf("envelope = %s to %v, want radb@example.com to two recipients", msg.from, msg.to)
+	}
+	if subject := readHeader(t, msg.data, "Subject"); subject != "[radb] 2 changes for noc in route-2" {
+		t.Errorf("Subject = %q", subject)
+	}
+	for _, want := range []string{
+		"Changes detected between route-1 and route-2 for team noc:",
+		"Added (1):\n  + route 198.51.100.0/24-AS64501",
+		"Modified (1):\n  ~ route 192.0.2.0/24-AS64500",
+		"-descr: Old\n+descr: New",
+	} {
+		if !strings.Contains(msg.data, want) {
+			t.Errorf("message is missing %q:\n%s", want, msg.data)
+		}
+	}
+	if strings.Contains(msg.data, "Removed") {
+		t.Errorf("message lists removals when there are none:\n%s", msg.data)
+	}
+}
+
+func TestNewEmailNotifierRejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		to      []string
+		subject string
+	}{
+		{"http url", "https://mail.example.com", []string{"noc@example.com"}, ""},
+		{"no recipients", "smtp://mail.example.com", nil, ""},
+		{"invalid template", "smtp://mail.example.com", []string{"noc@example.com"}, "{{.Missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEmailNotifier(tt.url, "radb@example.com", tt.to, tt.subject, ""); err == nil {
+				t.Error("NewEmailNotifier() succeeded")
+			}
+		})
+	}
+}
+
+// readHeader returns a header from a received message.
+func readHeader(t *testing.T, data, name string) string {
+	t.Helper()
+	header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header.Get(name)
+}
@@ -0,0 +1,53 @@
+// Package notify delivers detected changes to the teams responsible for
+// the changed objects.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Notification is a set of changes, or of assertion violations, delivered
+// to one sink.
+type Notification struct {
+	Time       time.Time                 `json:"time"`
+	Team       string                    `json:"team,omitempty"`   // Empty for changes no team owns
+	Target     string                    `json:"target,omitempty"` // Watch target whose check found the changes
+	SnapshotID string                    `json:"snapshot_id"`
+	PreviousID string                    `json:"previous_id"`
+	Baseline   string                    `json:"baseline,omitempty"` // Set when PreviousID is a named baseline
+	Severity   string                    `json:"severity,omitempty"` // Highest severity among Changes
+	Summary    map[models.ChangeType]int `json:"summary"`
+	Changes    []models.Change           `json:"changes"`
+	Diffs      []ObjectDiff              `json:"diffs,omitempty"` // RPSL diffs of the modified objects
+	Violations []audit.Violation         `json:"violations,omitempty"`
+}
+
+// ObjectDiff is the unified diff of a modified object's RPSL form.
+type ObjectDiff struct {
+	ObjectType string `json:"object_type"`
+	ObjectID   string `json:"object_id"`
+	Diff       string `json:"diff"`
+}
+
+// rpslDiffs returns the RPSL diffs of the modified objects among changes.
+func rpslDiffs(changes []models.Change) []ObjectDiff {
+	var diffs []ObjectDiff
+	for _, change := range changes {
+		if change.Type != models.ChangeTypeModified {
+			continue
+		}
+		if diff := models.RPSLDiff(change.ObjectType, change.ObjectID, change.Before, change.After); diff != "" {
+			diffs = append(diffs, ObjectDiff{ObjectType: change.ObjectType, ObjectID: change.ObjectID, Diff: diff})
+		}
+	}
+	return diffs
+}
+
+// Notifier delivers notifications to a single destination.
+type Notifier interface {
+	Notify(ctx context.Context, notification *Notification) error
+}
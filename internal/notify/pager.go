@@ -0,0 +1,234 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Default endpoints of the paging services.
+const (
+	DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	DefaultOpsgenieURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+// pagerEvent is one alert: a changed object or a violating route. Its key
+// is the same for every alert about that object, so the paging service
+// folds repeated alerts for a flapping object into the one already open.
+// Changes an alert rule classified carry its severity; other events use the
+// sink's.
+type pagerEvent struct {
+	key      string
+	summary  string
+	severity Severity
+	details  map[string]interface{}
+}
+
+// pagerEvents returns an event per change and per violation in a
+// notification.
+func pagerEvents(notification *Notification) []pagerEvent {
+	var alerts []pagerEvent
+	for _, change := range notification.Changes {
+		var severity Severity
+		if _, ok := change.Details["rule"]; ok {
+			severity = ChangeSeverity(change)
+		}
+		alerts = append(alerts, pagerEvent{
+			key:      fmt.Sprintf("radb:%s:%s", change.ObjectType, change.ObjectID),
+			summary:  fmt.Sprintf("RADb %s %s %s", change.ObjectType, change.ObjectID, change.Type),
+			severity: severity,
+			details: map[string]interface{}{
+				"change_type": change.Type,
+				"object_type": change.ObjectType,
+				"object_id":   change.ObjectID,
+				"snapshot_id": notification.SnapshotID,
+				"previous_id": notification.PreviousID,
+				"team":        notification.Team,
+				"diff":        objectDiff(notification.Diffs, change),
+			},
+		})
+	}
+	for _, violation := range notification.Violations {
+		alerts = append(alerts, pagerEvent{
+			key:     fmt.Sprintf("radb:violation:%s-%s:%s", violation.Route, violation.Origin, violation.Assertion),
+			summary: fmt.Sprintf("RADb route %s %s violates %s: %s", violation.Route, violation.Origin, violation.Assertion, violation.Detail),
+			details: map[string]interface{}{
+				"route":       violation.Route,
+				"origin":      violation.Origin,
+				"assertion":   violation.Assertion,
+				"detail":      violation.Detail,
+				"snapshot_id": notification.SnapshotID,
+				"team":        notification.Team,
+			},
+		})
+	}
+	return alerts
+}
+
+// objectDiff returns the RPSL diff of a modified object, or "".
+func objectDiff(diffs []ObjectDiff, change models.Change) string {
+	for _, diff := range diffs {
+		if diff.ObjectType == change.ObjectType && diff.ObjectID == change.ObjectID {
+			return diff.Diff
+		}
+	}
+	return ""
+}
+
+// PagerDutyNotifier triggers a PagerDuty Events API v2 alert per changed
+// object and per violating route.
+type PagerDutyNotifier struct {
+	url        string
+	routingKey string
+	severity   string
+	client     *http.Client
+}
+
+// Ensure PagerDutyNotifier implements Notifier.
+var _ Notifier = (*PagerDutyNotifier)(nil)
+
+// NewPagerDutyNotifier creates a notifier that triggers alerts with the
+// integration's routing key. An empty url selects DefaultPagerDutyURL and
+// an empty severity "critical".
+func NewPagerDutyNotifier(url, routingKey, severity string) (*PagerDutyNotifier, error) {
+	if routingKey == "" {
+		return nil, errors.New("PagerDuty routing key is required")
+	}
+	if url == "" {
+		url = DefaultPagerDutyURL
+	}
+	switch severity {
+	case "":
+		severity = "critical"
+	case "critical", "error", "warning", "info":
+	default:
+		return nil, fmt.Errorf("invalid PagerDuty severity %q (want critical, error, warning, or info)", severity)
+	}
+
+	return &PagerDutyNotifier{
+		url:        url,
+		routingKey: routingKey,
+		severity:   severity,
+		client:     &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// Notify triggers an alert per event in the notification. Every alert is
+// attempted; the errors of those that failed are joined.
+func (p *PagerDutyNotifier) Notify(ctx context.Context, notification *Notification) error {
+	var errs []error
+	for _, event := range pagerEvents(notification) {
+		severity := p.severity
+		if event.severity > SeverityNone {
+			severity = event.severity.String()
+		}
+		payload := map[string]interface{}{
+			"routing_key":  p.routingKey,
+			"event_action": "trigger",
+			"dedup_key":    event.key,
+			"payload": map[string]interface{}{
+				"summary":        event.summary,
+				"source":         "radb-client",
+				"severity":       severity,
+				"timestamp":      notification.Time.UTC().Format("2006-01-02T15:04:05Z"),
+				"component":      "radb",
+				"custom_details": event.details,
+			},
+		}
+		if err := postJSON(ctx, p.client, p.url, nil, payload); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", event.key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OpsgenieNotifier creates an Opsgenie alert per changed object and per
+// violating route.
+type OpsgenieNotifier struct {
+	url      string
+	apiKey   string
+	priority string
+	client   *http.Client
+}
+
+// Ensure OpsgenieNotifier implements Notifier.
+var _ Notifier = (*OpsgenieNotifier)(nil)
+
+// opsgeniePriorities are the Opsgenie priorities of alert rule severities.
+var opsgeniePriorities = map[Severity]string{
+	SeverityCritical: "P1",
+	SeverityError:    "P2",
+	SeverityWarning:  "P3",
+	SeverityInfo:     "P4",
+}
+
+// NewOpsgenieNotifier creates a notifier that creates alerts with an API
+// integration key. An empty url selects DefaultOpsgenieURL (use
+// https://api.eu.opsgenie.com/v2/alerts for the EU instance) and an empty
+// priority P1.
+func NewOpsgenieNotifier(url, apiKey, priority string) (*OpsgenieNotifier, error) {
+	if apiKey == "" {
+		return nil, errors.New("Opsgenie API key is required")
+	}
+	if url == "" {
+		url = DefaultOpsgenieURL
+	}
+	switch priority {
+	case "":
+		priority = "P1"
+	case "P1", "P2", "P3", "P4", "P5":
+	default:
+		return nil, fmt.Errorf("invalid Opsgenie priority %q (want P1 to P5)", priority)
+	}
+
+	return &OpsgenieNotifier{
+		url:      url,
+		apiKey:   apiKey,
+		priority: priority,
+		client:   &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// Notify creates an alert per event in the notification. Every alert is
+// attempted; the errors of those that failed are joined.
+func (o *OpsgenieNotifier) Notify(ctx context.Context, notification *Notification) error {
+	headers := map[string]string{"Authorization": "GenieKey " + o.apiKey}
+
+	var errs []error
+	for _, event := range pagerEvents(notification) {
+		details := make(map[string]string, len(event.details))
+		for key, value := range event.details {
+			if s := strings.TrimSpace(fmt.Sprint(value)); s != "" {
+				details[key] = s
+			}
+		}
+		priority := o.priority
+		if p, ok := opsgeniePriorities[event.severity]; ok {
+			priority = p
+		}
+		payload := map[string]interface{}{
+			"message":  truncate(event.summary, 130),
+			"alias":    event.key,
+			"priority": priority,
+			"source":   "radb-client",
+			"tags":     []string{"radb"},
+			"details":  details,
+		}
+		if err := postJSON(ctx, o.client, o.url, headers, payload); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", event.key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// truncate shortens s to at most n bytes, the limit a field accepts.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
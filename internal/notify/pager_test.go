@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/models"
+)
+
+// flappingNotifications returns the same route removed and then re-added
+// and removed again, as a flapping object produces.
+func flappingNotifications() []*Notification {
+	route := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"}
+	other := &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500", Source: "RADB"}
+	return []*Notification{
+		{Time: time.Now(), SnapshotID: "route-2", Changes: []models.Change{
+			routeChange(models.ChangeTypeRemoved, route, nil),
+			routeChange(models.ChangeTypeRemoved, other, nil),
+		}},
+		{Time: time.Now(), SnapshotID: "route-4", Changes: []models.Change{
+			routeChange(models.ChangeTypeRemoved, route, nil),
+		}},
+	}
+}
+
+func TestPagerDutyNotifier(t *testing.T) {
+	server, received, _ := chatServer(t)
+
+	notifier, err := NewPagerDutyNotifier(server.URL, "routing-key", "")
+	if err != nil {
+		t.Fatalf("NewPagerDutyNotifier() failed: %v", err)
+	}
+	for _, notification := range flappingNotifications() {
+		if err := notifier.Notify(context.Background(), notification); err != nil {
+			t.Fatalf("Notify() failed: %v", err)
+		}
+	}
+
+	if len(*received) != 3 {
+		t.Fatalf("PagerDuty received %d events, want one per changed object", len(*received))
+	}
+	first, last := (*received)[0], (*received)[2]
+	if first["routing_key"] != "routing-key" || first["event_action"] != "trigger" {
+		t.Errorf("event = %v, want a trigger with the routing key", first)
+	}
+	if payload, _ := first["payload"].(map[string]interface{}); payload["severity"] != "critical" {
+		t.Errorf("payload = %v, want critical severity", payload)
+	}
+	if first["dedup_key"] != "radb:route:192.0.2.0/24-AS64500" || last["dedup_key"] != first["dedup_key"] {
+		t.Errorf("dedup keys = %v and %v, want the same key for the same route", first["dedup_key"], last["dedup_key"])
+	}
+	if (*received)[1]["dedup_key"] == first["dedup_key"] {
+		t.Error("different routes share a dedup key")
+	}
+
+	if _, err := NewPagerDutyNotifier("", "", ""); err == nil {
+		t.Error("NewPagerDutyNotifier() accepted an empty routing key")
+	}
+	if _, err := NewPagerDutyNotifier("", "key", "P1"); err == nil {
+		t.Error("NewPagerDutyNotifier() accepted an Opsgenie priority as severity")
+	}
+}
+
+func TestOpsgenieNotifier(t *testing.T) {
+	server, received, headers := chatServer(t)
+
+	notifier, err := NewOpsgenieNotifier(server.URL, "api-key", "P2")
+	if err != nil {
+		t.Fatalf("NewOpsgenieNotifier() failed: %v", err)
+	}
+	notification := flappingNotifications()[1]
+	notification.Violations = []audit.Violation{{Route: "192.0.2.0/24", Origin: "AS64500", Assertion: "origin", Detail: "unexpected origin"}}
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+
+	if len(*received) != 2 {
+		t.Fatalf("Opsgenie received %d alerts, want 2", len(*received))
+	}
+	if auth := (*headers)[0].Get("Authorization"); auth != "GenieKey api-key" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	alert := (*received)[0]
+	if alert["alias"] != "radb:route:192.0.2.0/24-AS64500" || alert["priority"] != "P2" {
+		t.Errorf("alert = %v", alert)
+	}
+	if alias := (*received)[1]["alias"]; alias != "radb:violation:192.0.2.0/24-AS64500:origin" {
+		t.Errorf("violation alias = %v", alias)
+	}
+}
+
+func TestSinkFilterPrefixes(t *testing.T) {
+	filter := SinkFilter{
+		ChangeTypes: []models.ChangeType{models.ChangeTypeRemoved},
+		Prefixes:    []netip.Prefix{netip.MustParsePrefix("192.0.2.0/23")},
+	}
+
+	inside := &models.RouteObject{Route: "192.0.3.0/24", Origin: "AS64500"}
+	outside := &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500"}
+	tests := []struct {
+		name   string
+		change models.Change
+		want   bool
+	}{
+		{"removed inside", routeChange(models.ChangeTypeRemoved, inside, nil), true},
+		{"added inside", routeChange(models.ChangeTypeAdded, nil, inside), false},
+		{"removed outside", routeChange(models.ChangeTypeRemoved, outside, nil), false},
+		{"contact", models.Change{Type: models.ChangeTypeRemoved, ObjectType: "contact", ObjectID: "C1"}, false},
+	}
+	for _, tt := range tests {
+		if got := filter.Matches(tt.change); got != tt.want {
+			t.Errorf("%s: Matches() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/sirupsen/logrus"
+)
+
+// queueDir holds the retry queue within the state directory, apart from
+// the snapshots.
+const queueDir = "notifications"
+
+// Retry defaults, used for zero RetryPolicy fields.
+const (
+	DefaultRetryInitialDelay = 30 * time.Second
+	DefaultRetryMaxDelay     = time.Hour
+	DefaultRetryMaxAttempts  = 12
+)
+
+// RetryPolicy controls how failed deliveries are retried. The delay doubles
+// after each failed attempt, up to MaxDelay. A notification is dropped after
+// MaxAttempts failed attempts.
+type RetryPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	MaxAttempts  int
+}
+
+// delay returns the wait after the given number of failed attempts.
+func (p RetryPolicy) delay(attempts int) time.Duration {
+	delay := p.InitialDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// QueuedNotification is a notification waiting to be redelivered to a sink.
+type QueuedNotification struct {
+	ID           string        `json:"id"`
+	Sink         string        `json:"sink"`
+	Notification *Notification `json:"notification"`
+	Attempts     int           `json:"attempts"`
+	LastError    string        `json:"last_error"`
+	QueuedAt     time.Time     `json:"queued_at"`
+	NextAttempt  time.Time     `json:"next_attempt"`
+}
+
+// FlushResult summarizes a pass over the retry queue.
+type FlushResult struct {
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`  // Still queued for a later attempt
+	Dropped   int `json:"dropped"` // Gave up after the last attempt
+	Pending   int `json:"pending"` // Left in the queue afterwards
+}
+
+// Queue persists notifications that could not be delivered, so a sink
+// outage delays change alerts rather than losing them.
+type Queue struct {
+	path   string
+	lock   *flock.Flock
+	policy RetryPolicy
+	logger *logrus.Logger
+}
+
+// NewQueue creates a retry queue in the given state directory.
+func NewQueue(stateDir string, policy RetryPolicy, logger *logrus.Logger) *Queue {
+	if policy.InitialDelay <= 0 {
+		policy.InitialDelay = DefaultRetryInitialDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultRetryMaxDelay
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+
+	path := filepath.Join(stateDir, queueDir, "queue.json")
+	return &Queue{
+		path:   path,
+		lock:   flock.New(path + ".lock"),
+		policy: policy,
+		logger: logger,
+	}
+}
+
+// Add queues a notification whose first delivery to sink failed with cause.
+func (q *Queue) Add(ctx context.Context, sink string, notification *Notification, cause error) error {
+	now := time.Now()
+	entry := QueuedNotification{
+		ID:           fmt.Sprintf("%s-%d", sink, now.UnixNano()),
+		Sink:         sink,
+		Notification: notification,
+		Attempts:     1,
+		LastError:    cause.Error(),
+		QueuedAt:     now,
+		NextAttempt:  now.Add(q.policy.delay(1)),
+	}
+
+	return q.update(ctx, func(entries []QueuedNotification) []QueuedNotification {
+		return append(entries, entry)
+	})
+}
+
+// List returns the queued notifications, oldest first.
+func (q *Queue) List(ctx context.Context) ([]QueuedNotification, error) {
+	if err := q.acquire(ctx, true); err != nil {
+		return nil, err
+	}
+	defer q.lock.Unlock()
+
+	return q.read()
+}
+
+// Flush retries the queued notifications that are due, or every queued
+// notification when all is set. Deliveries run without holding the lock,
+// so notifications queued meanwhile are kept.
+func (q *Queue) Flush(ctx context.Context, sinks map[string]Notifier, all bool) (*FlushResult, error) {
+	entries, err := q.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivered := make(map[string]bool)
+	retried := make(map[string]QueuedNotification)
+	result := &FlushResult{}
+
+	for _, entry := range entries {
+		if !all && entry.NextAttempt.After(now) {
+			continue
+		}
+
+		err := errors.New("sink is no longer configured")
+		if sink, ok := sinks[entry.Sink]; ok {
+			err = sink.Notify(ctx, entry.Notification)
+		}
+		if err == nil {
+			delivered[entry.ID] = true
+			result.Delivered++
+			q.logger.Debugf("Redelivered queued notification %s to %s", entry.ID, entry.Sink)
+			continue
+		}
+
+		entry.Attempts++
+		entry.LastError = err.Error()
+		entry.NextAttempt = time.Now().Add(q.policy.delay(entry.Attempts))
+		retried[entry.ID] = entry
+	}
+
+	err = q.update(ctx, func(current []QueuedNotification) []QueuedNotification {
+		kept := current[:0]
+		for _, entry := range current {
+			if delivered[entry.ID] {
+				continue
+			}
+			if updated, ok := retried[entry.ID]; ok {
+				if updated.Attempts >= q.policy.MaxAttempts {
+					q.logger.Warnf("Dropping notification %s for %s after %d attempts: %s",
+						updated.ID, updated.Sink, updated.Attempts, updated.LastError)
+					result.Dropped++
+					continue
+				}
+				entry = updated
+				result.Failed++
+			}
+			kept = append(kept, entry)
+		}
+		result.Pending = len(kept)
+		return kept
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// update applies fn to the queued notifications under the lock and saves
+// the result.
+func (q *Queue) update(ctx context.Context, fn func([]QueuedNotification) []QueuedNotification) error {
+	if err := q.acquire(ctx, false); err != nil {
+		return err
+	}
+	defer q.lock.Unlock()
+
+	entries, err := q.read()
+	if err != nil {
+		return err
+	}
+	return q.write(fn(entries))
+}
+
+// acquire takes the queue lock, shared for reads.
+func (q *Queue) acquire(ctx context.Context, shared bool) error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	lock := q.lock.TryLockContext
+	if shared {
+		lock = q.lock.TryRLockContext
+	}
+	locked, err := lock(ctx, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return errors.New("could not acquire lock: timeout")
+	}
+	return nil
+}
+
+// read loads the queue from disk. A missing file is an empty queue.
+func (q *Queue) read() ([]QueuedNotification, error) {
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification queue: %w", err)
+	}
+
+	var entries []QueuedNotification
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse notification queue: %w", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].QueuedAt.Before(entries[j].QueuedAt)
+	})
+	return entries, nil
+}
+
+// write saves the queue atomically, removing the file once it is empty.
+func (q *Queue) write(entries []QueuedNotification) error {
+	if len(entries) == 0 {
+		if err := os.Remove(q.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear notification queue: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification queue: %w", err)
+	}
+
+	tmpPath := q.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write notification queue: %w", err)
+	}
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save notification queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// flakyNotifier fails until it is told to recover.
+type flakyNotifier struct {
+	mu       sync.Mutex
+	down     bool
+	received int
+}
+
+func (n *flakyNotifier) Notify(ctx context.Context, notification *Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.down {
+		return errors.New("503 Service Unavailable")
+	}
+	n.received++
+	return nil
+}
+
+func (n *flakyNotifier) set(down bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.down = down
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{InitialDelay: 30 * time.Second, MaxDelay: 5 * time.Minute}
+	for attempts, want := range map[int]time.Duration{
+		1: 30 * time.Second,
+		2: time.Minute,
+		4: 4 * time.Minute,
+		5: 5 * time.Minute,
+		9: 5 * time.Minute,
+	} {
+		if got := policy.delay(attempts); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestRouterQueuesFailedDeliveries(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	sink := &flakyNotifier{down: true}
+	router, err := NewRouter(map[string]Notifier{"noc": sink}, nil, []string{"noc"}, logger)
+	if err != nil {
+		t.Fatalf("NewRouter() failed: %v", err)
+	}
+	queue := NewQueue(t.TempDir(), RetryPolicy{MaxAttempts: 3}, logger)
+	router.SetQueue(queue)
+
+	route := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500"}
+	event := &events.ChangesDetected{
+		SnapshotID: "snap-2",
+		Changes:    &models.ChangeSet{Changes: []models.Change{routeChange(models.ChangeTypeAdded, nil, route)}},
+	}
+	if err := router.Deliver(ctx, event); err == nil {
+		t.Fatal("Expected Deliver() to report the failed sink")
+	}
+
+	queued, err := queue.List(ctx)
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(queued) != 1 || queued[0].Sink != "noc" || queued[0].Attempts != 1 {
+		t.Fatalf("Expected one queued notification for noc, got %+v", queued)
+	}
+	if !queued[0].NextAttempt.After(time.Now()) {
+		t.Errorf("Expected the retry to wait for the backoff, got %v", queued[0].NextAttempt)
+	}
+
+	t.Run("NotDueYet", func(t *testing.T) {
+		result, err := router.Retry(ctx, false)
+		if err != nil {
+			t.Fatalf("Retry() failed: %v", err)
+		}
+		if result.Delivered != 0 || result.Failed != 0 || result.Pending != 1 {
+			t.Errorf("Expected nothing retried before the backoff, got %+v", result)
+		}
+	})
+
+	t.Run("StillDown", func(t *testing.T) {
+		result, err := router.Retry(ctx, true)
+		if err != nil {
+			t.Fatalf("Retry() failed: %v", err)
+		}
+		if result.Failed != 1 || result.Pending != 1 {
+			t.Errorf("Expected the notification to stay queued, got %+v", result)
+		}
+		queued, _ := queue.List(ctx)
+		if queued[0].Attempts != 2 || queued[0].LastError == "" {
+			t.Errorf("Expected a second recorded attempt, got %+v", queued[0])
+		}
+	})
+
+	t.Run("Recovered", func(t *testing.T) {
+		sink.set(false)
+		result, err := router.Retry(ctx, true)
+		if err != nil {
+			t.Fatalf("Retry() failed: %v", err)
+		}
+		if result.Delivered != 1 || result.Pending != 0 || sink.received != 1 {
+			t.Errorf("Expected the notification to be redelivered, got %+v", result)
+		}
+	})
+}
+
+func TestQueueDropsAfterMaxAttempts(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	queue := NewQueue(t.TempDir(), RetryPolicy{MaxAttempts: 2}, logger)
+	if err := queue.Add(ctx, "noc", &Notification{SnapshotID: "snap-1"}, errors.New("timeout")); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	sinks := map[string]Notifier{"noc": &flakyNotifier{down: true}}
+	result, err := queue.Flush(ctx, sinks, true)
+	if err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+	if result.Dropped != 1 || result.Pending != 0 {
+		t.Errorf("Expected the notification to be dropped, got %+v", result)
+	}
+
+	t.Run("UnknownSink", func(t *testing.T) {
+		if err := queue.Add(ctx, "removed", &Notification{SnapshotID: "snap-2"}, errors.New("timeout")); err != nil {
+			t.Fatalf("Add() failed: %v", err)
+		}
+		result, err := queue.Flush(ctx, sinks, true)
+		if err != nil {
+			t.Fatalf("Flush() failed: %v", err)
+		}
+		if result.Delivered != 0 || result.Dropped != 1 {
+			t.Errorf("Expected a notification for a removed sink to fail, got %+v", result)
+		}
+	})
+}
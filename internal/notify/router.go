@@ -0,0 +1,499 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// Team owns the routes maintained by any of its maintainers or within any of
+// its prefixes, and contacts maintained by any of its maintainers.
+type Team struct {
+	Name        string
+	Maintainers []string
+	Prefixes    []netip.Prefix
+	Sinks       []string
+}
+
+// SinkFilter narrows the changes a sink receives, such as only route
+// removals, or only changes to routes within production prefixes for a
+// paging sink. Empty lists allow everything.
+type SinkFilter struct {
+	ObjectTypes []string            // route and/or contact
+	ChangeTypes []models.ChangeType // added, removed, and/or modified
+	Prefixes    []netip.Prefix      // Routes within any of these; excludes contacts
+	MinSeverity Severity            // Changes at least this severe (see Router.SetRules)
+}
+
+// Matches reports whether the filter allows a change.
+func (f SinkFilter) Matches(change models.Change) bool {
+	if f.MinSeverity > SeverityNone && ChangeSeverity(change) < f.MinSeverity {
+		return false
+	}
+	if len(f.ObjectTypes) > 0 && !containsFold(f.ObjectTypes, change.ObjectType) {
+		return false
+	}
+	if len(f.Prefixes) > 0 && !f.changeWithinPrefixes(change) {
+		return false
+	}
+	if len(f.ChangeTypes) == 0 {
+		return true
+	}
+	for _, changeType := range f.ChangeTypes {
+		if changeType == change.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// changeWithinPrefixes reports whether a change is to a route within the
+// filter's prefixes, before or after the change.
+func (f SinkFilter) changeWithinPrefixes(change models.Change) bool {
+	if change.ObjectType != "route" {
+		return false
+	}
+	for _, object := range []interface{}{change.Before, change.After} {
+		var route models.RouteObject
+		if object != nil && models.DecodeObject(object, &route) && withinPrefixes(route.Route, f.Prefixes) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesViolation reports whether the filter lets an assertion violation,
+// which concerns a route and is not a change, through.
+func (f SinkFilter) matchesViolation(violation audit.Violation) bool {
+	if len(f.ChangeTypes) > 0 || (len(f.ObjectTypes) > 0 && !containsFold(f.ObjectTypes, "route")) {
+		return false
+	}
+	if len(f.Prefixes) == 0 {
+		return true
+	}
+	return withinPrefixes(violation.Route, f.Prefixes)
+}
+
+// Router splits change sets by owning team and delivers each team's changes
+// only to that team's sinks. Changes no team owns go to the default sinks,
+// and changes found by a watch target with its own sinks go to those.
+// Alert rules decide which changes are delivered and at what severity, and
+// sink filters further narrow what each sink receives.
+type Router struct {
+	sinks        map[string]Notifier
+	filters      map[string]SinkFilter
+	rules        *Rules
+	teams        []Team
+	targetSinks  map[string][]string
+	defaultSinks []string
+	queue        *Queue
+	logger       *logrus.Logger
+}
+
+// NewRouter creates a router over named sinks. Every sink a team or the
+// defaults refer to must exist.
+func NewRouter(sinks map[string]Notifier, teams []Team, defaultSinks []string, logger *logrus.Logger) (*Router, error) {
+	for _, team := range teams {
+		for _, name := range team.Sinks {
+			if _, ok := sinks[name]; !ok {
+				return nil, fmt.Errorf("team %s uses unknown sink %s", team.Name, name)
+			}
+		}
+	}
+	for _, name := range defaultSinks {
+		if _, ok := sinks[name]; !ok {
+			return nil, fmt.Errorf("unknown default sink %s", name)
+		}
+	}
+
+	return &Router{
+		sinks:        sinks,
+		filters:      make(map[string]SinkFilter),
+		targetSinks:  make(map[string][]string),
+		teams:        teams,
+		defaultSinks: defaultSinks,
+		logger:       logger,
+	}, nil
+}
+
+// SetQueue makes failed deliveries persist to queue for later retries.
+// Without a queue, failed deliveries are only reported.
+func (r *Router) SetQueue(queue *Queue) {
+	r.queue = queue
+}
+
+// SetSinkFilter makes the named sink receive only the changes filter
+// allows, so for example route removals can go to one channel and contact
+// changes to another.
+func (r *Router) SetSinkFilter(sink string, filter SinkFilter) {
+	r.filters[sink] = filter
+}
+
+// SetRules classifies every change before it is routed: changes the rules
+// classify as SeverityNone are not delivered, and the others carry their
+// severity for sink filters and paging sinks. Without rules every change is
+// delivered at SeverityInfo.
+func (r *Router) SetRules(rules *Rules) {
+	r.rules = rules
+}
+
+// SetTargetSinks sends every change and violation a watch target's checks
+// find to the named sinks, bypassing team routing.
+func (r *Router) SetTargetSinks(target string, sinks []string) error {
+	for _, name := range sinks {
+		if _, ok := r.sinks[name]; !ok {
+			return fmt.Errorf("target %s uses unknown sink %s", target, name)
+		}
+	}
+	r.targetSinks[target] = sinks
+	return nil
+}
+
+// Route groups changes by the name of every team that owns them. Changes no
+// team owns are grouped under the empty name. A change that moves an object
+// between teams, such as a maintainer change, belongs to both.
+func (r *Router) Route(changes []models.Change) map[string][]models.Change {
+	routed := make(map[string][]models.Change)
+	for _, change := range changes {
+		owned := false
+		for _, team := range r.teams {
+			if team.Owns(change) {
+				routed[team.Name] = append(routed[team.Name], change)
+				owned = true
+			}
+		}
+		if !owned {
+			routed[""] = append(routed[""], change)
+		}
+	}
+	return routed
+}
+
+// Deliver sends the changes in event to the sinks of the teams that own
+// them. Every sink is attempted; the errors of those that failed are joined.
+func (r *Router) Deliver(ctx context.Context, event *events.ChangesDetected) error {
+	if event.Changes == nil {
+		return nil
+	}
+	changes := event.Changes.Changes
+	if r.rules != nil {
+		if changes = r.rules.Apply(changes); len(changes) == 0 {
+			r.logger.Debugf("Alert rules suppressed all %d changes in %s", len(event.Changes.Changes), event.SnapshotID)
+			return nil
+		}
+	}
+
+	if sinks, ok := r.targetSinks[event.Target]; ok && event.Target != "" {
+		return r.notify(ctx, sinks, &Notification{
+			Time:       event.Time,
+			Target:     event.Target,
+			SnapshotID: event.SnapshotID,
+			PreviousID: event.PreviousID,
+			Severity:   highestSeverity(changes),
+			Summary:    summarize(changes),
+			Changes:    changes,
+			Diffs:      rpslDiffs(changes),
+		})
+	}
+
+	routed := r.Route(changes)
+	teams := make([]string, 0, len(routed))
+	for team := range routed {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	var errs []error
+	for _, team := range teams {
+		notification := &Notification{
+			Time:       event.Time,
+			Team:       team,
+			Target:     event.Target,
+			SnapshotID: event.SnapshotID,
+			PreviousID: event.PreviousID,
+			Baseline:   event.Baseline,
+			Severity:   highestSeverity(routed[team]),
+			Summary:    summarize(routed[team]),
+			Changes:    routed[team],
+			Diffs:      rpslDiffs(routed[team]),
+		}
+		errs = append(errs, r.notify(ctx, r.sinksFor(team), notification))
+	}
+	return errors.Join(errs...)
+}
+
+// DeliverViolations sends the assertion violations in event to the sinks of
+// the teams that own the violating routes, like Deliver does for changes.
+func (r *Router) DeliverViolations(ctx context.Context, event *events.AssertionsViolated) error {
+	if sinks, ok := r.targetSinks[event.Target]; ok && event.Target != "" {
+		return r.notify(ctx, sinks, &Notification{
+			Time:       event.Time,
+			Target:     event.Target,
+			SnapshotID: event.SnapshotID,
+			Summary:    map[models.ChangeType]int{},
+			Changes:    []models.Change{},
+			Violations: event.Violations,
+		})
+	}
+
+	routed := make(map[string][]audit.Violation)
+	for _, violation := range event.Violations {
+		route := &models.RouteObject{Route: violation.Route, Origin: violation.Origin, MntBy: violation.MntBy}
+		owned := false
+		for _, team := range r.teams {
+			if team.ownsRoute(route) {
+				routed[team.Name] = append(routed[team.Name], violation)
+				owned = true
+			}
+		}
+		if !owned {
+			routed[""] = append(routed[""], violation)
+		}
+	}
+
+	teams := make([]string, 0, len(routed))
+	for team := range routed {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	var errs []error
+	for _, team := range teams {
+		notification := &Notification{
+			Time:       event.Time,
+			Team:       team,
+			Target:     event.Target,
+			SnapshotID: event.SnapshotID,
+			Summary:    map[models.ChangeType]int{},
+			Changes:    []models.Change{},
+			Violations: routed[team],
+		}
+		errs = append(errs, r.notify(ctx, r.sinksFor(team), notification))
+	}
+	return errors.Join(errs...)
+}
+
+// notify sends a notification to every named sink, joining the errors of
+// those that failed. Failed deliveries are queued for retry when a queue is
+// set.
+func (r *Router) notify(ctx context.Context, sinks []string, notification *Notification) error {
+	team := notification.Team
+	var errs []error
+	for _, name := range sinks {
+		notification, ok := r.filter(name, notification)
+		if !ok {
+			continue
+		}
+		if err := r.sinks[name].Notify(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("sink %s: %w", name, err))
+			if r.queue != nil {
+				if qerr := r.queue.Add(ctx, name, notification, err); qerr != nil {
+					errs = append(errs, fmt.Errorf("sink %s: failed to queue notification for retry: %w", name, qerr))
+				} else {
+					r.logger.Infof("Queued notification for team %q to %s for retry", team, name)
+				}
+			}
+			continue
+		}
+		r.logger.Debugf("Delivered %d changes and %d violations for team %q to %s",
+			len(notification.Changes), len(notification.Violations), team, name)
+	}
+	return errors.Join(errs...)
+}
+
+// filter returns the part of a notification the named sink receives, and
+// false when nothing is left for it.
+func (r *Router) filter(sink string, notification *Notification) (*Notification, bool) {
+	filter, ok := r.filters[sink]
+	if !ok {
+		return notification, true
+	}
+
+	filtered := *notification
+	filtered.Changes = make([]models.Change, 0, len(notification.Changes))
+	for _, change := range notification.Changes {
+		if filter.Matches(change) {
+			filtered.Changes = append(filtered.Changes, change)
+		}
+	}
+	filtered.Violations = nil
+	for _, violation := range notification.Violations {
+		if filter.matchesViolation(violation) {
+			filtered.Violations = append(filtered.Violations, violation)
+		}
+	}
+	if len(filtered.Changes) == 0 && len(filtered.Violations) == 0 {
+		return nil, false
+	}
+
+	filtered.Severity = highestSeverity(filtered.Changes)
+	filtered.Summary = summarize(filtered.Changes)
+	filtered.Diffs = rpslDiffs(filtered.Changes)
+	return &filtered, true
+}
+
+// Retry redelivers the queued notifications that are due, or all of them
+// when all is set. Without a queue it does nothing.
+func (r *Router) Retry(ctx context.Context, all bool) (*FlushResult, error) {
+	if r.queue == nil {
+		return &FlushResult{}, nil
+	}
+	return r.queue.Flush(ctx, r.sinks, all)
+}
+
+// sinksFor returns the sinks of a team, or the default sinks for unowned changes.
+func (r *Router) sinksFor(team string) []string {
+	if team == "" {
+		return r.defaultSinks
+	}
+	for _, t := range r.teams {
+		if t.Name == team {
+			return t.Sinks
+		}
+	}
+	return nil
+}
+
+// retryInterval is how often a subscribed router checks its queue for
+// notifications due for redelivery.
+const retryInterval = 30 * time.Second
+
+// Subscribe delivers every ChangesDetected and AssertionsViolated event
+// published on bus in the background, so slow sinks never hold up
+// monitoring. Events are dropped, and the drop logged and counted, when more
+// than buffer are already waiting.
+// Queued notifications are retried as they fall due. The returned function
+// unsubscribes and waits for pending deliveries to finish.
+func (r *Router) Subscribe(bus *events.Bus, buffer int) (stop func()) {
+	ch, unsubscribe := bus.SubscribeChan("notifications", buffer)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		var retry <-chan time.Time
+		if r.queue != nil {
+			ticker := time.NewTicker(retryInterval)
+			defer ticker.Stop()
+			retry = ticker.C
+		}
+
+		for {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				switch e := event.(type) {
+				case *events.ChangesDetected:
+					if err := r.Deliver(ctx, e); err != nil {
+						r.logger.Warnf("Failed to deliver notifications for %s: %v", e.SnapshotID, err)
+					}
+				case *events.AssertionsViolated:
+					if err := r.DeliverViolations(ctx, e); err != nil {
+						r.logger.Warnf("Failed to deliver assertion violations for %s: %v", e.SnapshotID, err)
+					}
+				}
+				cancel()
+			case <-retry:
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				result, err := r.Retry(ctx, false)
+				cancel()
+				if err != nil {
+					r.logger.Warnf("Failed to retry queued notifications: %v", err)
+				} else if result.Delivered > 0 || result.Dropped > 0 {
+					r.logger.Infof("Retried queued notifications: %d delivered, %d dropped, %d pending",
+						result.Delivered, result.Dropped, result.Pending)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		unsubscribe()
+		<-done
+	}
+}
+
+// Owns reports whether the team owns the object before or after a change.
+func (t *Team) Owns(change models.Change) bool {
+	for _, object := range []interface{}{change.Before, change.After} {
+		if object == nil {
+			continue
+		}
+		switch change.ObjectType {
+		case "route":
+			var route models.RouteObject
+			if models.DecodeObject(object, &route) && t.ownsRoute(&route) {
+				return true
+			}
+		case "contact":
+			var contact models.Contact
+			if models.DecodeObject(object, &contact) && t.ownsMaintainer(contact.RawAttributes["mnt-by"]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ownsRoute reports whether the route has one of the team's maintainers or
+// lies within one of its prefixes.
+func (t *Team) ownsRoute(route *models.RouteObject) bool {
+	return t.ownsMaintainer(route.MntBy) || withinPrefixes(route.Route, t.Prefixes)
+}
+
+// withinPrefixes reports whether route is one of prefixes or more specific.
+func withinPrefixes(route string, prefixes []netip.Prefix) bool {
+	prefix, err := netip.ParsePrefix(route)
+	if err != nil {
+		return false
+	}
+	for _, owned := range prefixes {
+		if owned.Bits() <= prefix.Bits() && owned.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsMaintainer reports whether any of maintainers belongs to the team.
+func (t *Team) ownsMaintainer(maintainers []string) bool {
+	for _, maintainer := range maintainers {
+		for _, owned := range t.Maintainers {
+			if strings.EqualFold(maintainer, owned) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values holds s, ignoring case.
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarize counts changes by type.
+func summarize(changes []models.Change) map[models.ChangeType]int {
+	summary := make(map[models.ChangeType]int)
+	for _, change := range changes {
+		summary[change.Type]++
+	}
+	return summary
+}
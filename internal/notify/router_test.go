@@ -0,0 +1,306 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/audit"
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// recordingNotifier collects the notifications it receives.
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []*Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+// changeIDs returns the object IDs in a sink's notifications, by team.
+func (n *recordingNotifier) changeIDs() map[string][]string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	ids := make(map[string][]string)
+	for _, notification := range n.notifications {
+		for _, change := range notification.Changes {
+			ids[notification.Team] = append(ids[notification.Team], change.ObjectID)
+		}
+	}
+	return ids
+}
+
+func routeChange(changeType models.ChangeType, before, after *models.RouteObject) models.Change {
+	change := models.Change{Type: changeType, ObjectType: "route", Timestamp: time.Now()}
+	if before != nil {
+		change.ObjectID = before.ID()
+		change.Before = before
+	}
+	if after != nil {
+		change.ObjectID = after.ID()
+		change.After = after
+	}
+	return change
+}
+
+func TestRouterDeliver(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	noc, peering, fallback := &recordingNotifier{}, &recordingNotifier{}, &recordingNotifier{}
+	router, err := NewRouter(
+		map[string]Notifier{"noc": noc, "peering": peering, "fallback": fallback},
+		[]Team{
+			{Name: "noc", Maintainers: []string{"MAINT-NOC"}, Sinks: []string{"noc"}},
+			{Name: "peering", Prefixes: []netip.Prefix{netip.MustParsePrefix("198.51.100.0/23")}, Sinks: []string{"peering"}},
+		},
+		[]string{"fallback"},
+		logger,
+	)
+	if err != nil {
+		t.Fatalf("NewRouter() failed: %v", err)
+	}
+
+	nocRoute := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"maint-noc"}, Source: "RADB"}
+	peeringRoute := &models.RouteObject{Route: "198.51.101.0/24", Origin: "AS64501", MntBy: []string{"MAINT-OTHER"}, Source: "RADB"}
+	otherRoute := &models.RouteObject{Route: "203.0.113.0/24", Origin: "AS64502", MntBy: []string{"MAINT-OTHER"}, Source: "RADB"}
+	handedOver := *nocRoute
+	handedOver.Route = "198.51.100.0/24"
+	handedOver.MntBy = []string{"MAINT-OTHER"}
+	handedOverBefore := handedOver
+	handedOverBefore.MntBy = []string{"MAINT-NOC"}
+
+	// Changes read back from the changelog hold generic maps rather than pointers
+	var decoded map[string]interface{}
+	data, _ := json.Marshal(otherRoute)
+	json.Unmarshal(data, &decoded)
+
+	event := &events.ChangesDetected{
+		Time:       time.Now(),
+		SnapshotID: "route-2",
+		PreviousID: "route-1",
+		Changes: &models.ChangeSet{Changes: []models.Change{
+			routeChange(models.ChangeTypeAdded, nil, nocRoute),
+			routeChange(models.ChangeTypeRemoved, peeringRoute, nil),
+			{Type: models.ChangeTypeAdded, ObjectType: "route", ObjectID: otherRoute.ID(), After: decoded},
+			routeChange(models.ChangeTypeModified, &handedOverBefore, &handedOver),
+		}},
+	}
+
+	if err := router.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("Deliver() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		sink *recordingNotifier
+		team string
+		want []string
+	}{
+		{"maintainer", noc, "noc", []string{nocRoute.ID(), handedOver.ID()}},
+		{"prefix", peering, "peering", []string{peeringRoute.ID(), handedOver.ID()}},
+		{"unowned", fallback, "", []string{otherRoute.ID()}},
+	}
+	for _, tt := range tests {
+		got := tt.sink.changeIDs()
+		if len(got) != 1 || len(got[tt.team]) != len(tt.want) {
+			t.Errorf("%s: sink received %v, want %v for team %q", tt.name, got, tt.want, tt.team)
+			continue
+		}
+		for i, id := range tt.want {
+			if got[tt.team][i] != id {
+				t.Errorf("%s: change %d = %s, want %s", tt.name, i, got[tt.team][i], id)
+			}
+		}
+	}
+
+	// Modified objects carry a diff of their RPSL form
+	diffs := noc.notifications[0].Diffs
+	if len(diffs) != 1 || diffs[0].ObjectID != handedOver.ID() ||
+		!strings.Contains(diffs[0].Diff, "-mnt-by: MAINT-NOC\n+mnt-by: MAINT-OTHER\n") {
+		t.Errorf("Expected the RPSL diff of the handed over route, got %+v", diffs)
+	}
+
+	if _, err := NewRouter(map[string]Notifier{}, nil, []string{"missing"}, logger); err == nil {
+		t.Error("NewRouter() accepted an unknown default sink")
+	}
+}
+
+func TestRouterTargetSinks(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	noc, customers := &recordingNotifier{}, &recordingNotifier{}
+	router, err := NewRouter(
+		map[string]Notifier{"noc": noc, "customers": customers},
+		[]Team{{Name: "noc", Maintainers: []string{"MAINT-NOC"}, Sinks: []string{"noc"}}},
+		nil,
+		logger,
+	)
+	if err != nil {
+		t.Fatalf("NewRouter() failed: %v", err)
+	}
+	if err := router.SetTargetSinks("customers", []string{"customers"}); err != nil {
+		t.Fatalf("SetTargetSinks() failed: %v", err)
+	}
+	if err := router.SetTargetSinks("other", []string{"missing"}); err == nil {
+		t.Error("SetTargetSinks() accepted an unknown sink")
+	}
+
+	route := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-NOC"}, Source: "RADB"}
+	changes := &models.ChangeSet{Changes: []models.Change{routeChange(models.ChangeTypeAdded, nil, route)}}
+
+	// Changes found by the target bypass team routing
+	if err := router.Deliver(context.Background(), &events.ChangesDetected{Target: "customers", Changes: changes}); err != nil {
+		t.Fatalf("Deliver() failed: %v", err)
+	}
+	if len(noc.notifications) != 0 || len(customers.notifications) != 1 || customers.notifications[0].Target != "customers" {
+		t.Errorf("target changes reached noc %d times and customers %+v", len(noc.notifications), customers.notifications)
+	}
+
+	// Targets without sinks of their own use team routing
+	if err := router.Deliver(context.Background(), &events.ChangesDetected{Target: "transit", Changes: changes}); err != nil {
+		t.Fatalf("Deliver() failed: %v", err)
+	}
+	if len(noc.notifications) != 1 || noc.notifications[0].Target != "transit" {
+		t.Errorf("noc received %+v, want the transit target's changes", noc.notifications)
+	}
+}
+
+func TestRouterDeliverViolations(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	noc, fallback := &recordingNotifier{}, &recordingNotifier{}
+	router, err := NewRouter(
+		map[string]Notifier{"noc": noc, "fallback": fallback},
+		[]Team{{Name: "noc", Maintainers: []string{"MAINT-NOC"}, Sinks: []string{"noc"}}},
+		[]string{"fallback"},
+		logger,
+	)
+	if err != nil {
+		t.Fatalf("NewRouter() failed: %v", err)
+	}
+
+	event := &events.AssertionsViolated{
+		Time:       time.Now(),
+		SnapshotID: "route-2",
+		Violations: []audit.Violation{
+			{Assertion: "descr", Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-NOC"}, Detail: "no descr matches ^ACME"},
+			{Assertion: "descr", Route: "203.0.113.0/24", Origin: "AS64502", MntBy: []string{"MAINT-OTHER"}, Detail: "no descr matches ^ACME"},
+		},
+	}
+	if err := router.DeliverViolations(context.Background(), event); err != nil {
+		t.Fatalf("DeliverViolations() failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		sink  *recordingNotifier
+		team  string
+		route string
+	}{
+		{noc, "noc", "192.0.2.0/24"},
+		{fallback, "", "203.0.113.0/24"},
+	} {
+		if len(tt.sink.notifications) != 1 {
+			t.Fatalf("team %q received %d notifications, want 1", tt.team, len(tt.sink.notifications))
+		}
+		notification := tt.sink.notifications[0]
+		if notification.Team != tt.team || len(notification.Violations) != 1 || notification.Violations[0].Route != tt.route {
+			t.Errorf("team %q received %+v, want a violation for %s", tt.team, notification, tt.route)
+		}
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received Notification
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if received.Team == "broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, map[string]string{"Authorization": "Bearer abc"})
+	notification := &Notification{Team: "noc", SnapshotID: "route-2", Changes: []models.Change{{Type: models.ChangeTypeAdded, ObjectID: "192.0.2.0/24-AS64500"}}}
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	if received.Team != "noc" || len(received.Changes) != 1 || auth != "Bearer abc" {
+		t.Errorf("webhook received %+v with Authorization %q", received, auth)
+	}
+
+	notification.Team = "broken"
+	if err := notifier.Notify(context.Background(), notification); err == nil {
+		t.Error("Notify() succeeded on a 503 response")
+	}
+}
+
+func TestRouterSinkFilters(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	noc, admin, all := &recordingNotifier{}, &recordingNotifier{}, &recordingNotifier{}
+	router, err := NewRouter(map[string]Notifier{"noc": noc, "admin": admin, "all": all}, nil, []string{"noc", "admin", "all"}, logger)
+	if err != nil {
+		t.Fatalf("NewRouter() failed: %v", err)
+	}
+	router.SetSinkFilter("noc", SinkFilter{ObjectTypes: []string{"route"}, ChangeTypes: []models.ChangeType{models.ChangeTypeRemoved}})
+	router.SetSinkFilter("admin", SinkFilter{ObjectTypes: []string{"contact"}})
+
+	removed := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"}
+	added := &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500", Source: "RADB"}
+	event := &events.ChangesDetected{
+		Time:       time.Now(),
+		SnapshotID: "route-2",
+		Changes: &models.ChangeSet{Changes: []models.Change{
+			routeChange(models.ChangeTypeRemoved, removed, nil),
+			routeChange(models.ChangeTypeAdded, nil, added),
+			{Type: models.ChangeTypeModified, ObjectType: "contact", ObjectID: "C1"},
+		}},
+	}
+	if err := router.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("Deliver() failed: %v", err)
+	}
+
+	if got := noc.changeIDs()[""]; len(got) != 1 || got[0] != removed.ID() {
+		t.Errorf("noc received %v, want only the removal", got)
+	}
+	if noc.notifications[0].Summary[models.ChangeTypeRemoved] != 1 || len(noc.notifications[0].Summary) != 1 {
+		t.Errorf("noc summary = %v, want one removal", noc.notifications[0].Summary)
+	}
+	if got := admin.changeIDs()[""]; len(got) != 1 || got[0] != "C1" {
+		t.Errorf("admin received %v, want only the contact change", got)
+	}
+	if got := all.changeIDs()[""]; len(got) != 3 {
+		t.Errorf("unfiltered sink received %v, want every change", got)
+	}
+
+	// A sink whose filter leaves nothing is not notified
+	violations := &events.AssertionsViolated{Time: time.Now(), SnapshotID: "route-2", Violations: []audit.Violation{{Route: "192.0.2.0/24", Origin: "AS64500"}}}
+	if err := router.DeliverViolations(context.Background(), violations); err != nil {
+		t.Fatalf("DeliverViolations() failed: %v", err)
+	}
+	if len(noc.notifications) != 1 || len(admin.notifications) != 1 || len(all.notifications) != 2 {
+		t.Errorf("violations reached %d, %d, and %d notifications, want only the unfiltered sink", len(noc.notifications)-1, len(admin.notifications)-1, len(all.notifications)-1)
+	}
+}
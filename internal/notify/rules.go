@@ -0,0 +1,213 @@
+package notify
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Severity ranks how urgent a change is. Changes of SeverityNone are not
+// delivered at all.
+type Severity int
+
+// Severities from least to most urgent.
+const (
+	SeverityNone Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// severityNames are the configuration names of the severities.
+var severityNames = []string{"none", "info", "warning", "error", "critical"}
+
+// ParseSeverity parses a severity name: none, info, warning, error, or
+// critical.
+func ParseSeverity(name string) (Severity, error) {
+	for i, severityName := range severityNames {
+		if strings.EqualFold(name, severityName) {
+			return Severity(i), nil
+		}
+	}
+	return SeverityNone, fmt.Errorf("invalid severity %q (want none, info, warning, error, or critical)", name)
+}
+
+// String returns the severity's name.
+func (s Severity) String() string {
+	if s < SeverityNone || int(s) >= len(severityNames) {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// Rule classifies the changes it matches at a severity. Every condition
+// that is set must hold; empty lists match everything.
+type Rule struct {
+	Name        string
+	ObjectTypes []string            // route and/or contact
+	ChangeTypes []models.ChangeType // added, removed, and/or modified
+	Prefixes    []netip.Prefix      // Routes within any of these; excludes contacts
+	Origins     []string            // Routes with any of these origins; excludes contacts
+	Fields      []string            // Modifications changing any of these fields
+	OnlyFields  []string            // Modifications changing none but these fields
+	Severity    Severity
+}
+
+// Matches reports whether the rule applies to a change. Routes match
+// Prefixes and Origins before or after the change.
+func (r Rule) Matches(change models.Change) bool {
+	filter := SinkFilter{ObjectTypes: r.ObjectTypes, ChangeTypes: r.ChangeTypes, Prefixes: r.Prefixes}
+	if !filter.Matches(change) {
+		return false
+	}
+	if len(r.Origins) > 0 && !r.matchesOrigin(change) {
+		return false
+	}
+	if len(r.Fields) == 0 && len(r.OnlyFields) == 0 {
+		return true
+	}
+
+	fields := changedFields(change)
+	if len(fields) == 0 {
+		return false
+	}
+	if len(r.Fields) > 0 && !anyField(fields, r.Fields) {
+		return false
+	}
+	if len(r.OnlyFields) > 0 {
+		for _, field := range fields {
+			if !anyField([]string{field}, r.OnlyFields) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesOrigin reports whether a change is to a route with one of the
+// rule's origins, before or after the change.
+func (r Rule) matchesOrigin(change models.Change) bool {
+	if change.ObjectType != "route" {
+		return false
+	}
+	for _, object := range []interface{}{change.Before, change.After} {
+		var route models.RouteObject
+		if object != nil && models.DecodeObject(object, &route) && containsFold(r.Origins, route.Origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules classify changes by the first rule that matches each of them.
+// Changes no rule matches get the default severity.
+type Rules struct {
+	rules    []Rule
+	fallback Severity
+}
+
+// NewRules creates rules evaluated in order, with fallback as the
+// severity of changes none of them match.
+func NewRules(rules []Rule, fallback Severity) *Rules {
+	return &Rules{rules: rules, fallback: fallback}
+}
+
+// Classify returns the severity of a change and the name of the rule that
+// decided it, or "" when no rule matched.
+func (r *Rules) Classify(change models.Change) (Severity, string) {
+	for _, rule := range r.rules {
+		if rule.Matches(change) {
+			return rule.Severity, rule.Name
+		}
+	}
+	return r.fallback, ""
+}
+
+// Apply returns the changes that are to be delivered, each with its
+// severity, and the deciding rule if any, recorded in its details.
+// Changes classified as SeverityNone are dropped. The input changes are
+// left untouched, since other subscribers share them.
+func (r *Rules) Apply(changes []models.Change) []models.Change {
+	applied := make([]models.Change, 0, len(changes))
+	for _, change := range changes {
+		severity, rule := r.Classify(change)
+		if severity == SeverityNone {
+			continue
+		}
+		details := make(map[string]interface{}, len(change.Details)+2)
+		for key, value := range change.Details {
+			details[key] = value
+		}
+		details["severity"] = severity.String()
+		if rule != "" {
+			details["rule"] = rule
+		}
+		change.Details = details
+		applied = append(applied, change)
+	}
+	return applied
+}
+
+// ChangeSeverity returns the severity a router recorded for a change, and
+// SeverityInfo for changes it did not classify.
+func ChangeSeverity(change models.Change) Severity {
+	if name, ok := change.Details["severity"].(string); ok {
+		if severity, err := ParseSeverity(name); err == nil {
+			return severity
+		}
+	}
+	return SeverityInfo
+}
+
+// highestSeverity returns the highest severity among changes, or "" when
+// there are none.
+func highestSeverity(changes []models.Change) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	highest := SeverityNone
+	for _, change := range changes {
+		highest = max(highest, ChangeSeverity(change))
+	}
+	return highest.String()
+}
+
+// changedFields returns the fields a modification changed. Details decoded
+// from JSON, such as of queued notifications, hold them as []interface{}.
+func changedFields(change models.Change) []string {
+	switch fields := change.Details["field_changes"].(type) {
+	case []string:
+		return fields
+	case []interface{}:
+		names := make([]string, 0, len(fields))
+		for _, field := range fields {
+			if name, ok := field.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// anyField reports whether any of fields is one of names. Field names may
+// be given as RPSL attributes (mnt-by), JSON keys (mnt_by), or Go fields
+// (MntBy).
+func anyField(fields, names []string) bool {
+	for _, field := range fields {
+		for _, name := range names {
+			if fieldKey(field) == fieldKey(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldKey normalizes a field name, so mnt-by, mnt_by, and MntBy match.
+func fieldKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+}
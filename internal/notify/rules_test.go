@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// modifiedRoute returns a modification of route that changed fields.
+func modifiedRoute(route *models.RouteObject, fields ...string) models.Change {
+	change := routeChange(models.ChangeTypeModified, route, route)
+	change.Details = map[string]interface{}{"field_changes": fields}
+	return change
+}
+
+func TestRulesClassify(t *testing.T) {
+	production := &models.RouteObject{Route: "192.0.2.0/25", Origin: "AS64500", Source: "RADB"}
+	other := &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64501", Source: "RADB"}
+
+	rules := NewRules([]Rule{
+		{Name: "remarks", ChangeTypes: []models.ChangeType{models.ChangeTypeModified}, OnlyFields: []string{"remarks", "descr"}, Severity: SeverityNone},
+		{Name: "production", ObjectTypes: []string{"route"}, Prefixes: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}, ChangeTypes: []models.ChangeType{models.ChangeTypeRemoved}, Severity: SeverityCritical},
+		{Name: "origin", Origins: []string{"as64501"}, Severity: SeverityWarning},
+		{Name: "maintainers", Fields: []string{"mnt-by"}, Severity: SeverityError},
+	}, SeverityInfo)
+
+	tests := []struct {
+		name     string
+		change   models.Change
+		severity Severity
+		rule     string
+	}{
+		{"remark only", modifiedRoute(production, "Remarks"), SeverityNone, "remarks"},
+		{"remark and origin", modifiedRoute(production, "Remarks", "Origin"), SeverityInfo, ""},
+		{"production removal", routeChange(models.ChangeTypeRemoved, production, nil), SeverityCritical, "production"},
+		{"production addition", routeChange(models.ChangeTypeAdded, nil, production), SeverityInfo, ""},
+		{"origin", routeChange(models.ChangeTypeRemoved, other, nil), SeverityWarning, "origin"},
+		{"maintainer change", modifiedRoute(production, "MntBy", "Remarks"), SeverityError, "maintainers"},
+		{"contact", models.Change{Type: models.ChangeTypeAdded, ObjectType: "contact", ObjectID: "C1"}, SeverityInfo, ""},
+		{"fields decoded from JSON", models.Change{Type: models.ChangeTypeModified, ObjectType: "contact", ObjectID: "C1",
+			Details: map[string]interface{}{"field_changes": []interface{}{"Descr"}}}, SeverityNone, "remarks"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			severity, rule := rules.Classify(tt.change)
+			if severity != tt.severity || rule != tt.rule {
+				t.Errorf("Classify() = %s, %q; want %s, %q", severity, rule, tt.severity, tt.rule)
+			}
+		})
+	}
+}
+
+func TestRouterRules(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	email, pager := &recordingNotifier{}, &recordingNotifier{}
+	router, err := NewRouter(map[string]Notifier{"email": email, "pager": pager}, nil, []string{"email", "pager"}, logger)
+	if err != nil {
+		t.Fatalf("NewRouter() failed: %v", err)
+	}
+	router.SetSinkFilter("pager", SinkFilter{MinSeverity: SeverityCritical})
+	router.SetRules(NewRules([]Rule{
+		{Name: "remarks", OnlyFields: []string{"remarks"}, Severity: SeverityNone},
+		{Name: "removals", ChangeTypes: []models.ChangeType{models.ChangeTypeRemoved}, Severity: SeverityCritical},
+	}, SeverityInfo))
+
+	removed := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"}
+	remarked := &models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500", Source: "RADB"}
+	added := &models.RouteObject{Route: "203.0.113.0/24", Origin: "AS64500", Source: "RADB"}
+	changes := []models.Change{
+		routeChange(models.ChangeTypeRemoved, removed, nil),
+		modifiedRoute(remarked, "Remarks"),
+		routeChange(models.ChangeTypeAdded, nil, added),
+	}
+	event := &events.ChangesDetected{Time: time.Now(), SnapshotID: "route-2", Changes: &models.ChangeSet{Changes: changes}}
+	if err := router.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("Deliver() failed: %v", err)
+	}
+
+	if got := email.changeIDs()[""]; len(got) != 2 || got[0] != removed.ID() || got[1] != added.ID() {
+		t.Errorf("email received %v, want all but the remark change", got)
+	}
+	if email.notifications[0].Severity != "critical" {
+		t.Errorf("email severity = %q, want the highest, critical", email.notifications[0].Severity)
+	}
+	if got := pager.changeIDs()[""]; len(got) != 1 || got[0] != removed.ID() {
+		t.Errorf("pager received %v, want only the critical removal", got)
+	}
+	if change := pager.notifications[0].Changes[0]; change.Details["rule"] != "removals" || change.Details["severity"] != "critical" {
+		t.Errorf("change details = %v, want the deciding rule and severity", change.Details)
+	}
+	if changes[0].Details != nil {
+		t.Error("Deliver() modified the event's changes")
+	}
+
+	// Nothing is delivered when every change is suppressed
+	event = &events.ChangesDetected{Time: time.Now(), SnapshotID: "route-3", Changes: &models.ChangeSet{Changes: changes[1:2]}}
+	if err := router.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("Deliver() failed: %v", err)
+	}
+	if len(email.notifications) != 1 {
+		t.Errorf("email received %d notifications, want no notification of suppressed changes", len(email.notifications))
+	}
+}
+
+func TestPagerRuleSeverity(t *testing.T) {
+	server, received, _ := chatServer(t)
+
+	notifier, err := NewPagerDutyNotifier(server.URL, "routing-key", "critical")
+	if err != nil {
+		t.Fatalf("NewPagerDutyNotifier() failed: %v", err)
+	}
+	route := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Source: "RADB"}
+	classified := routeChange(models.ChangeTypeAdded, nil, route)
+	classified.Details = map[string]interface{}{"severity": "warning", "rule": "additions"}
+	unclassified := routeChange(models.ChangeTypeRemoved, route, nil)
+	unclassified.Details = map[string]interface{}{"severity": "info"}
+	notification := &Notification{Time: time.Now(), SnapshotID: "route-2", Changes: []models.Change{classified, unclassified}}
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+
+	var severities []interface{}
+	for _, event := range *received {
+		payload, _ := event["payload"].(map[string]interface{})
+		severities = append(severities, payload["severity"])
+	}
+	if len(severities) != 2 || severities[0] != "warning" || severities[1] != "critical" {
+		t.Errorf("severities = %v, want the rule's and then the sink's", severities)
+	}
+}
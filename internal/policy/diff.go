@@ -0,0 +1,171 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ChangeKind is the kind of a policy change.
+type ChangeKind string
+
+const (
+	// RuleAdded means a peer gained a rule.
+	RuleAdded ChangeKind = "added"
+
+	// RuleRemoved means a peer lost a rule.
+	RuleRemoved ChangeKind = "removed"
+
+	// RuleModified means a peer's rule changed.
+	RuleModified ChangeKind = "modified"
+)
+
+// Change is one difference between two policies: a rule towards a peer
+// that was added, removed, or modified.
+type Change struct {
+	Kind      ChangeKind `json:"kind"`
+	Direction Direction  `json:"direction"`
+	Family    string     `json:"family"`
+	Peer      string     `json:"peer"`
+	Fields    []string   `json:"fields,omitempty"` // Modified fields: filter, actions, peer_router, local_router
+	Before    *Rule      `json:"before,omitempty"`
+	After     *Rule      `json:"after,omitempty"`
+}
+
+// String describes the change, e.g. "added peer AS64501 import".
+func (c Change) String() string {
+	subject := fmt.Sprintf("peer %s %s", c.Peer, c.Direction)
+	if c.Family != "ipv4.unicast" {
+		subject += " (" + c.Family + ")"
+	}
+
+	switch c.Kind {
+	case RuleAdded:
+		return "added " + subject
+	case RuleRemoved:
+		return "removed " + subject
+	}
+
+	details := make([]string, 0, len(c.Fields))
+	for _, field := range c.Fields {
+		var before, after string
+		switch field {
+		case "filter":
+			before, after = c.Before.Filter, c.After.Filter
+		case "actions":
+			before, after = strings.Join(c.Before.Actions, "; "), strings.Join(c.After.Actions, "; ")
+		case "peer_router":
+			before, after = c.Before.PeerRouter, c.After.PeerRouter
+		case "local_router":
+			before, after = c.Before.LocalRouter, c.After.LocalRouter
+		}
+		details = append(details, fmt.Sprintf("%s %s -> %s", field, orNone(before), orNone(after)))
+	}
+	return fmt.Sprintf("changed %s: %s", subject, strings.Join(details, ", "))
+}
+
+// orNone returns value, or "(none)" if it is empty.
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// ruleKey identifies the rules that are compared with each other: those in
+// the same direction and address families towards the same peer.
+func ruleKey(r *Rule) string {
+	return string(r.Direction) + "|" + r.Family() + "|" + r.Peer
+}
+
+// Diff compares two policies peer by peer. Rules for the same peer,
+// direction, and address families are paired in order. Changes are sorted
+// by direction, peer, and family.
+func Diff(before, after *Policy) []Change {
+	group := func(policy *Policy) map[string][]*Rule {
+		groups := make(map[string][]*Rule)
+		if policy == nil {
+			return groups
+		}
+		for i := range policy.Rules {
+			key := ruleKey(&policy.Rules[i])
+			groups[key] = append(groups[key], &policy.Rules[i])
+		}
+		return groups
+	}
+	old, cur := group(before), group(after)
+
+	keys := make(map[string]bool, len(old)+len(cur))
+	for key := range old {
+		keys[key] = true
+	}
+	for key := range cur {
+		keys[key] = true
+	}
+
+	changes := make([]Change, 0)
+	for key := range keys {
+		oldRules, newRules := old[key], cur[key]
+		for i := 0; i < max(len(oldRules), len(newRules)); i++ {
+			var b, a *Rule
+			if i < len(oldRules) {
+				b = oldRules[i]
+			}
+			if i < len(newRules) {
+				a = newRules[i]
+			}
+			if change, ok := compareRules(b, a); ok {
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Direction != changes[j].Direction {
+			return changes[i].Direction < changes[j].Direction
+		}
+		if changes[i].Peer != changes[j].Peer {
+			return changes[i].Peer < changes[j].Peer
+		}
+		return changes[i].Family < changes[j].Family
+	})
+	return changes
+}
+
+// compareRules returns the change between two paired rules, either of
+// which may be nil, and whether there is one.
+func compareRules(before, after *Rule) (Change, bool) {
+	rule := after
+	if rule == nil {
+		rule = before
+	}
+	change := Change{Direction: rule.Direction, Family: rule.Family(), Peer: rule.Peer, Before: before, After: after}
+
+	switch {
+	case before == nil:
+		change.Kind = RuleAdded
+		return change, true
+	case after == nil:
+		change.Kind = RuleRemoved
+		return change, true
+	}
+
+	if before.Filter != after.Filter {
+		change.Fields = append(change.Fields, "filter")
+	}
+	if !slices.Equal(before.Actions, after.Actions) {
+		change.Fields = append(change.Fields, "actions")
+	}
+	if before.PeerRouter != after.PeerRouter {
+		change.Fields = append(change.Fields, "peer_router")
+	}
+	if before.LocalRouter != after.LocalRouter {
+		change.Fields = append(change.Fields, "local_router")
+	}
+	if len(change.Fields) == 0 {
+		return change, false
+	}
+	change.Kind = RuleModified
+	return change, true
+}
@@ -0,0 +1,228 @@
+// Package policy parses the routing policy of RPSL aut-num objects (import,
+// export, mp-import, and mp-export attributes) into typed rules, so policy
+// changes can be compared peer by peer instead of as changed text.
+package policy
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Direction is the direction of a policy rule.
+type Direction string
+
+const (
+	// Import rules select the routes accepted from a peer.
+	Import Direction = "import"
+
+	// Export rules select the routes announced to a peer.
+	Export Direction = "export"
+)
+
+// Attributes lists the aut-num policy attributes, in the order rules are
+// parsed.
+var Attributes = []string{"import", "export", "mp-import", "mp-export"}
+
+// Rule is the policy towards one peer from one policy attribute. An
+// attribute with several peerings yields one rule per peering, sharing the
+// filter.
+type Rule struct {
+	Attribute   string    `json:"attribute"` // import, export, mp-import, or mp-export
+	Direction   Direction `json:"direction"`
+	AFI         []string  `json:"afi,omitempty"`          // Address families of mp- rules
+	Peer        string    `json:"peer"`                   // AS expression, e.g. AS64501 or AS-PEERS
+	PeerRouter  string    `json:"peer_router,omitempty"`  // Peer's router, if restricted
+	LocalRouter string    `json:"local_router,omitempty"` // Our router, after "at"
+	Actions     []string  `json:"actions,omitempty"`      // e.g. pref=100, community.append(64500:1)
+	Filter      string    `json:"filter"`                 // Accept or announce expression
+}
+
+// Family returns the address families the rule applies to: IPv4 unicast
+// for import and export, the listed families or any for mp- rules.
+func (r *Rule) Family() string {
+	switch {
+	case len(r.AFI) > 0:
+		return strings.Join(r.AFI, ",")
+	case strings.HasPrefix(r.Attribute, "mp-"):
+		return "any"
+	default:
+		return "ipv4.unicast"
+	}
+}
+
+// Policy is the parsed routing policy of an aut-num.
+type Policy struct {
+	Rules    []Rule   `json:"rules"`
+	Unparsed []string `json:"unparsed,omitempty"` // Values that could not be parsed, as "attribute: value"
+}
+
+// Parse parses the policy attributes of an aut-num, given by lowercase
+// attribute name. Values that use unsupported syntax, such as refine and
+// except expressions, are kept in Unparsed.
+func Parse(attrs map[string][]string) *Policy {
+	policy := &Policy{Rules: make([]Rule, 0)}
+	for _, attribute := range Attributes {
+		for _, value := range attrs[attribute] {
+			rules, err := ParseRule(attribute, value)
+			if err != nil {
+				policy.Unparsed = append(policy.Unparsed, attribute+": "+value)
+				continue
+			}
+			policy.Rules = append(policy.Rules, rules...)
+		}
+	}
+	return policy
+}
+
+// word is a whitespace-separated token with its position in the value.
+type word struct {
+	text       string
+	start, end int
+}
+
+// splitWords splits value at whitespace, keeping each word's position.
+func splitWords(value string) []word {
+	var words []word
+	start := -1
+	for i := 0; i <= len(value); i++ {
+		if i == len(value) || value[i] == ' ' || value[i] == '\t' {
+			if start >= 0 {
+				words = append(words, word{text: value[start:i], start: start, end: i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	return words
+}
+
+// ParseRule parses one policy attribute value, such as
+// "from AS64501 action pref=100; accept AS64501", into a rule per peering.
+func ParseRule(attribute, value string) ([]Rule, error) {
+	attribute = strings.ToLower(attribute)
+	var (
+		direction          Direction
+		peerKey, filterKey string
+	)
+	switch strings.TrimPrefix(attribute, "mp-") {
+	case "import":
+		direction, peerKey, filterKey = Import, "from", "accept"
+	case "export":
+		direction, peerKey, filterKey = Export, "to", "announce"
+	default:
+		return nil, fmt.Errorf("%s is not a policy attribute", attribute)
+	}
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") {
+		return nil, fmt.Errorf("structured policy expressions are not supported")
+	}
+
+	words := splitWords(value)
+	is := func(i int, keyword string) bool {
+		return i < len(words) && strings.EqualFold(words[i].text, keyword)
+	}
+
+	var afi []string
+	i := 0
+	for i < len(words) && !is(i, peerKey) {
+		switch {
+		case is(i, "protocol"), is(i, "into"):
+			i += 2
+		case is(i, "afi") && strings.HasPrefix(attribute, "mp-"):
+			i++
+			var list []string
+			for i < len(words) && !is(i, peerKey) {
+				list = append(list, words[i].text)
+				i++
+			}
+			for _, family := range strings.Split(strings.Join(list, ""), ",") {
+				if family != "" {
+					afi = append(afi, strings.ToLower(family))
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unexpected %q before %s", words[i].text, peerKey)
+		}
+	}
+
+	// One or more peerings, each with optional actions
+	var rules []Rule
+	for is(i, peerKey) {
+		i++
+		rule := Rule{Attribute: attribute, Direction: direction, AFI: afi}
+
+		var peer, router, at []string
+		target := &peer
+		for i < len(words) && !is(i, peerKey) && !is(i, "action") && !is(i, filterKey) {
+			switch {
+			case is(i, "at"):
+				target = &at
+			case target == &peer && len(peer) > 0 && isRouter(words[i].text):
+				target = &router
+				router = append(router, words[i].text)
+			default:
+				*target = append(*target, words[i].text)
+			}
+			i++
+		}
+		if len(peer) == 0 {
+			return nil, fmt.Errorf("%s without a peer", peerKey)
+		}
+		rule.Peer = strings.ToUpper(strings.Join(peer, " "))
+		rule.PeerRouter = strings.Join(router, " ")
+		rule.LocalRouter = strings.Join(at, " ")
+
+		if is(i, "action") {
+			start := words[i].end
+			i++
+			for i < len(words) && !is(i, peerKey) && !is(i, filterKey) {
+				i++
+			}
+			end := len(value)
+			if i < len(words) {
+				end = words[i].start
+			}
+			for _, action := range strings.Split(value[start:end], ";") {
+				if action = strings.TrimSpace(action); action != "" {
+					rule.Actions = append(rule.Actions, action)
+				}
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("missing %s", peerKey)
+	}
+	if !is(i, filterKey) {
+		return nil, fmt.Errorf("missing %s", filterKey)
+	}
+
+	filter := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value[words[i].end:]), ";"))
+	if filter == "" {
+		return nil, fmt.Errorf("%s without a filter", filterKey)
+	}
+	for _, w := range splitWords(filter) {
+		if strings.EqualFold(w.text, "refine") || strings.EqualFold(w.text, "except") {
+			return nil, fmt.Errorf("structured policy expressions are not supported")
+		}
+	}
+	for j := range rules {
+		rules[j].Filter = filter
+	}
+	return rules, nil
+}
+
+// isRouter reports whether a peering word names a router: an IP address or
+// an inet-rtr name.
+func isRouter(text string) bool {
+	if _, err := netip.ParseAddr(text); err == nil {
+		return true
+	}
+	return strings.Contains(text, ".") && !strings.Contains(text, ":")
+}
@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		attribute string
+		value     string
+		want      []Rule
+		wantErr   string
+	}{
+		{
+			name:      "simple import",
+			attribute: "import",
+			value:     "from AS64501 accept AS64501",
+			want:      []Rule{{Attribute: "import", Direction: Import, Peer: "AS64501", Filter: "AS64501"}},
+		},
+		{
+			name:      "actions and routers",
+			attribute: "import",
+			value:     "from as64501 192.0.2.1 at 192.0.2.2 action pref=100; community.append(64500:1); accept AS-PEER AND NOT {0.0.0.0/0};",
+			want: []Rule{{
+				Attribute: "import", Direction: Import, Peer: "AS64501", PeerRouter: "192.0.2.1", LocalRouter: "192.0.2.2",
+				Actions: []string{"pref=100", "community.append(64500:1)"}, Filter: "AS-PEER AND NOT {0.0.0.0/0}",
+			}},
+		},
+		{
+			name:      "several peerings share the filter",
+			attribute: "export",
+			value:     "to AS64501 action med=10; to AS64502 announce AS-EXAMPLE",
+			want: []Rule{
+				{Attribute: "export", Direction: Export, Peer: "AS64501", Actions: []string{"med=10"}, Filter: "AS-EXAMPLE"},
+				{Attribute: "export", Direction: Export, Peer: "AS64502", Filter: "AS-EXAMPLE"},
+			},
+		},
+		{
+			name:      "multiprotocol with address families",
+			attribute: "mp-import",
+			value:     "afi ipv6.unicast, ipv4.unicast from AS64501 accept ANY",
+			want: []Rule{{
+				Attribute: "mp-import", Direction: Import, AFI: []string{"ipv6.unicast", "ipv4.unicast"}, Peer: "AS64501", Filter: "ANY",
+			}},
+		},
+		{name: "refine", attribute: "import", value: "from AS64501 accept ANY refine from AS64502 accept AS64502", wantErr: "not supported"},
+		{name: "missing filter", attribute: "export", value: "to AS64501", wantErr: "missing announce"},
+		{name: "wrong keyword", attribute: "export", value: "from AS64501 accept ANY", wantErr: "unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseRule(tt.attribute, tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseRule() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule() failed: %v", err)
+			}
+			if !reflect.DeepEqual(rules, tt.want) {
+				t.Errorf("ParseRule() = %+v, want %+v", rules, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := Parse(map[string][]string{
+		"import": {
+			"from AS64501 action pref=100; accept AS64501",
+			"from AS64502 accept AS-CUSTOMER",
+		},
+		"export":    {"to AS64501 announce AS-EXAMPLE"},
+		"mp-import": {"afi ipv6.unicast from AS64501 accept ANY"},
+	})
+	after := Parse(map[string][]string{
+		"import": {
+			"from AS64501 action pref=200; accept AS64501",
+			"from AS64503 accept AS64503",
+			"{ from AS64504 accept ANY }",
+		},
+		"export":    {"to AS64501 announce AS-EXAMPLE"},
+		"mp-import": {"afi ipv6.unicast from AS64501 accept ANY"},
+	})
+
+	if len(after.Unparsed) != 1 {
+		t.Errorf("Unparsed = %v, want the structured expression", after.Unparsed)
+	}
+
+	var got []string
+	for _, change := range Diff(before, after) {
+		got = append(got, change.String())
+	}
+	want := []string{
+		"changed peer AS64501 import: actions pref=100 -> pref=200",
+		"removed peer AS64502 import",
+		"added peer AS64503 import",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+
+	if changes := Diff(before, before); len(changes) != 0 {
+		t.Errorf("Diff() of identical policies = %v, want none", changes)
+	}
+}
@@ -0,0 +1,210 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bss/radb-client/internal/events"
+)
+
+// KafkaPublisher publishes events to a Kafka topic through a Kafka REST
+// Proxy (v2 API), keyed by event type so events of one type stay ordered
+// within a partition. Credentials in the URL are sent as Basic Auth.
+type KafkaPublisher struct {
+	endpoint string
+	headers  map[string]string
+	user     *url.Userinfo
+	client   *http.Client
+}
+
+// Ensure KafkaPublisher implements Publisher.
+var _ Publisher = (*KafkaPublisher)(nil)
+
+// NewKafkaPublisher creates a publisher for topic on the REST Proxy at
+// rawURL, sending the given extra headers.
+func NewKafkaPublisher(rawURL, topic string, headers map[string]string) (*KafkaPublisher, error) {
+	base, user, err := parseHTTPURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kafka REST Proxy URL: %w", err)
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("kafka topic is required")
+	}
+
+	return &KafkaPublisher{
+		endpoint: base + "/topics/" + url.PathEscape(topic),
+		headers:  headers,
+		user:     user,
+		client:   &http.Client{Timeout: publishTimeout},
+	}, nil
+}
+
+// Publish produces one record.
+func (p *KafkaPublisher) Publish(ctx context.Context, eventType events.Type, payload []byte) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"records": []map[string]interface{}{
+			{"key": string(eventType), "value": json.RawMessage(payload)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Kafka record: %w", err)
+	}
+
+	resp, err := postJSON(ctx, p.client, p.endpoint, "application/vnd.kafka.json.v2+json", body, p.user, p.headers)
+	if err != nil {
+		return fmt.Errorf("failed to produce to Kafka: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The proxy reports per-record failures in a 200 response
+	var result struct {
+		Offsets []struct {
+			Error string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result); err == nil {
+		for _, offset := range result.Offsets {
+			if offset.Error != "" {
+				return fmt.Errorf("kafka rejected record: %s", offset.Error)
+			}
+		}
+	}
+	return nil
+}
+
+// Close releases idle connections.
+func (p *KafkaPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// RabbitMQManagementPublisher publishes events to a RabbitMQ exchange through
+// the management HTTP API, with the routing key "<routing key>.<event type>".
+// Messages are persistent. Credentials in the URL are sent as Basic Auth.
+//
+// It does not speak AMQP: each message is one HTTP request to the management
+// plugin, which RabbitMQ documents as meant for testing and diagnostics, not
+// production publishing. Use it for development setups and low event volumes.
+type RabbitMQManagementPublisher struct {
+	endpoint   string
+	routingKey string
+	headers    map[string]string
+	user       *url.Userinfo
+	client     *http.Client
+}
+
+// Ensure RabbitMQManagementPublisher implements Publisher.
+var _ Publisher = (*RabbitMQManagementPublisher)(nil)
+
+// NewRabbitMQManagementPublisher creates a publisher for exchange in vhost on the
+// management API at rawURL, sending the given extra headers.
+func NewRabbitMQManagementPublisher(rawURL, vhost, exchange, routingKey string, headers map[string]string) (*RabbitMQManagementPublisher, error) {
+	base, user, err := parseHTTPURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RabbitMQ management URL: %w", err)
+	}
+	if exchange == "" {
+		return nil, fmt.Errorf("rabbitmq exchange is required")
+	}
+	if vhost == "" {
+		vhost = "/"
+	}
+
+	return &RabbitMQManagementPublisher{
+		endpoint:   fmt.Sprintf("%s/api/exchanges/%s/%s/publish", base, url.PathEscape(vhost), url.PathEscape(exchange)),
+		routingKey: routingKey,
+		headers:    headers,
+		user:       user,
+		client:     &http.Client{Timeout: publishTimeout},
+	}, nil
+}
+
+// Publish sends one message. A message the exchange routes to no queue is
+// reported as an error, since it would otherwise be silently dropped.
+func (p *RabbitMQManagementPublisher) Publish(ctx context.Context, eventType events.Type, payload []byte) error {
+	routingKey := string(eventType)
+	if p.routingKey != "" {
+		routingKey = p.routingKey + "." + routingKey
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"properties":       map[string]interface{}{"content_type": "application/json", "delivery_mode": 2},
+		"routing_key":      routingKey,
+		"payload":          string(payload),
+		"payload_encoding": "string",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal RabbitMQ message: %w", err)
+	}
+
+	resp, err := postJSON(ctx, p.client, p.endpoint, "application/json", body, p.user, p.headers)
+	if err != nil {
+		return fmt.Errorf("failed to publish to RabbitMQ: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Routed bool `json:"routed"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&result); err != nil {
+		return fmt.Errorf("invalid RabbitMQ publish response: %w", err)
+	}
+	if !result.Routed {
+		return fmt.Errorf("message with routing key %s was not routed to any queue", routingKey)
+	}
+	return nil
+}
+
+// Close releases idle connections.
+func (p *RabbitMQManagementPublisher) Close() error {
+	p.client.CloseIdleConnections()
+	return nil
+}
+
+// parseHTTPURL validates an http(s) URL and splits off its credentials.
+func parseHTTPURL(rawURL string) (base string, user *url.Userinfo, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", nil, fmt.Errorf("%q must be an http or https URL", rawURL)
+	}
+
+	user = u.User
+	u.User = nil
+	return strings.TrimSuffix(u.String(), "/"), user, nil
+}
+
+// postJSON posts body and returns the response if its status is 2xx.
+func postJSON(ctx context.Context, client *http.Client, endpoint, contentType string, body []byte, user *url.Userinfo, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
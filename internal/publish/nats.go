@@ -0,0 +1,186 @@
+package publish
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+)
+
+// natsDefaultPort is used when a NATS URL has no port.
+const natsDefaultPort = "4222"
+
+// natsDialTimeout bounds connecting to the NATS server.
+const natsDialTimeout = 10 * time.Second
+
+// NATSPublisher publishes events to a NATS server using the core text
+// protocol, on the subject "<subject>.<event type>". Each publish is
+// followed by a PING, so an error from the server is reported to the caller
+// rather than lost. A broken connection is re-established on the next publish.
+//
+// URLs take the form nats://[user:pass@|token@]host[:port]; tls:// connects
+// with TLS.
+type NATSPublisher struct {
+	url     *url.URL
+	subject string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// Ensure NATSPublisher implements Publisher.
+var _ Publisher = (*NATSPublisher)(nil)
+
+// NewNATSPublisher creates a publisher for the server at rawURL.
+func NewNATSPublisher(rawURL, subject string) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("invalid NATS URL %q: scheme must be nats or tls", rawURL)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q: host is required", rawURL)
+	}
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n") {
+		return nil, fmt.Errorf("invalid NATS subject %q", subject)
+	}
+
+	return &NATSPublisher{url: u, subject: subject}, nil
+}
+
+// Publish sends payload and waits for the server to acknowledge it.
+func (p *NATSPublisher) Publish(ctx context.Context, eventType events.Type, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := p.publish(ctx, p.subject+"."+string(eventType), payload)
+	if err != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+	return err
+}
+
+// Close closes the connection, if one is open.
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+// connect dials the server, reads its INFO, and sends CONNECT.
+func (p *NATSPublisher) connect(ctx context.Context) error {
+	address := p.url.Host
+	if p.url.Port() == "" {
+		address = net.JoinHostPort(p.url.Hostname(), natsDefaultPort)
+	}
+
+	dialer := &net.Dialer{Timeout: natsDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS at %s: %w", address, err)
+	}
+	if p.url.Scheme == "tls" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: p.url.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed TLS handshake with NATS at %s: %w", address, err)
+		}
+		conn = tlsConn
+	}
+
+	p.conn = conn
+	p.reader = bufio.NewReader(conn)
+	setDeadline(ctx, conn)
+
+	line, err := p.reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		p.conn = nil
+		return fmt.Errorf("unexpected NATS greeting %q: %v", strings.TrimSpace(line), err)
+	}
+
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "radb-client",
+		"lang":     "go",
+	}
+	if user := p.url.User; user != nil {
+		if password, ok := user.Password(); ok {
+			options["user"] = user.Username()
+			options["pass"] = password
+		} else {
+			options["auth_token"] = user.Username()
+		}
+	}
+	data, _ := json.Marshal(options)
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", data); err != nil {
+		conn.Close()
+		p.conn = nil
+		return fmt.Errorf("failed to send NATS CONNECT: %w", err)
+	}
+	return nil
+}
+
+// publish writes one message followed by a PING and reads until the PONG.
+func (p *NATSPublisher) publish(ctx context.Context, subject string, payload []byte) error {
+	setDeadline(ctx, p.conn)
+
+	message := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload)
+	if _, err := p.conn.Write([]byte(message)); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+
+	for {
+		line, err := p.reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read NATS acknowledgement: %w", err)
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := p.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("failed to answer NATS PING: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS server rejected publish: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+		// +OK and INFO updates need no action
+	}
+}
+
+// setDeadline applies the context's deadline, or a default, to conn.
+func setDeadline(ctx context.Context, conn net.Conn) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(publishTimeout)
+	}
+	conn.SetDeadline(deadline)
+}
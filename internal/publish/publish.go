@@ -0,0 +1,102 @@
+// Package publish exports monitoring events to message brokers, so
+// downstream systems such as provisioning, billing, or security tooling can
+// consume IRR changes as they are detected.
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/sirupsen/logrus"
+)
+
+// publishTimeout bounds the delivery of one event to one broker.
+const publishTimeout = 30 * time.Second
+
+// Message is the envelope published for every event.
+type Message struct {
+	Type   events.Type  `json:"type"`
+	Time   time.Time    `json:"time"`
+	Source string       `json:"source"` // IRR database the event concerns, e.g. RADB
+	Event  events.Event `json:"event"`
+}
+
+// Publisher sends encoded messages to one broker. Each publisher derives
+// its subject, topic, or routing key from the event type.
+type Publisher interface {
+	Publish(ctx context.Context, eventType events.Type, payload []byte) error
+	Close() error
+}
+
+// Exporter publishes the events of a bus to every configured publisher.
+type Exporter struct {
+	publishers map[string]Publisher
+	source     string
+	logger     *logrus.Logger
+}
+
+// NewExporter creates an exporter over named publishers. source is the IRR
+// database recorded in each message.
+func NewExporter(publishers map[string]Publisher, source string, logger *logrus.Logger) *Exporter {
+	return &Exporter{
+		publishers: publishers,
+		source:     source,
+		logger:     logger,
+	}
+}
+
+// Export publishes one event to every publisher, returning the first error.
+// A failing publisher does not stop delivery to the others.
+func (e *Exporter) Export(ctx context.Context, event events.Event) error {
+	payload, err := json.Marshal(Message{
+		Type:   event.EventType(),
+		Time:   event.OccurredAt(),
+		Source: e.source,
+		Event:  event,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", event.EventType(), err)
+	}
+
+	var firstErr error
+	for name, publisher := range e.publishers {
+		if err := publisher.Publish(ctx, event.EventType(), payload); err != nil {
+			err = fmt.Errorf("publisher %s: %w", name, err)
+			e.logger.Warnf("Failed to publish %s event: %v", event.EventType(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// Subscribe exports the bus's events in the background, holding up to
+// buffer events while brokers are slow. The returned function unsubscribes,
+// waits for pending exports, and closes the publishers.
+func (e *Exporter) Subscribe(bus *events.Bus, buffer int) (stop func()) {
+	ch, unsubscribe := bus.SubscribeChan("publish", buffer)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for event := range ch {
+			ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+			e.Export(ctx, event)
+			cancel()
+		}
+	}()
+
+	return func() {
+		unsubscribe()
+		<-done
+		for name, publisher := range e.publishers {
+			if err := publisher.Close(); err != nil {
+				e.logger.Warnf("Failed to close publisher %s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package publish
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/events"
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeNATS accepts one connection and records each published subject and
+// payload, rejecting subjects that start with "denied".
+func fakeNATS(t *testing.T) (addr string, published chan [2]string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	published = make(chan [2]string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) == 0:
+			case fields[0] == "CONNECT":
+				if !strings.Contains(line, `"auth_token":"secret"`) {
+					fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+					return
+				}
+			case fields[0] == "PUB" && len(fields) == 3:
+				size, _ := strconv.Atoi(fields[2])
+				payload := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, payload); err != nil {
+					return
+				}
+				if strings.HasPrefix(fields[1], "denied") {
+					fmt.Fprint(conn, "-ERR 'Permissions Violation for Publish'\r\n")
+					continue
+				}
+				published <- [2]string{fields[1], string(payload[:size])}
+			case fields[0] == "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), published
+}
+
+func TestNATSPublisher(t *testing.T) {
+	addr, published := fakeNATS(t)
+
+	publisher, err := NewNATSPublisher("nats://secret@"+addr, "radb.events")
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() failed: %v", err)
+	}
+	defer publisher.Close()
+
+	if err := publisher.Publish(context.Background(), events.TypeChangesDetected, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	got := <-published
+	if got[0] != "radb.events.changes_detected" || got[1] != `{"a":1}` {
+		t.Errorf("published %v, want radb.events.changes_detected {\"a\":1}", got)
+	}
+
+	denied, _ := NewNATSPublisher("nats://secret@"+addr, "denied")
+	publisher.subject = denied.subject
+	if err := publisher.Publish(context.Background(), events.TypeChangesDetected, []byte(`{}`)); err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Errorf("Publish() to a denied subject = %v, want the server's error", err)
+	}
+
+	if _, err := NewNATSPublisher("http://"+addr, "radb.events"); err == nil {
+		t.Error("NewNATSPublisher() accepted an http URL")
+	}
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	var record map[string][]map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if r.URL.Path != "/topics/irr-changes" || user != "producer" || pass != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&record)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":42}]}`))
+	}))
+	defer server.Close()
+
+	endpoint := strings.Replace(server.URL, "http://", "http://producer:secret@", 1)
+	publisher, err := NewKafkaPublisher(endpoint, "irr-changes", nil)
+	if err != nil {
+		t.Fatalf("NewKafkaPublisher() failed: %v", err)
+	}
+	if err := publisher.Publish(context.Background(), events.TypeSnapshotSaved, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if got := record["records"]; len(got) != 1 || string(got[0]["key"]) != `"snapshot_saved"` || string(got[0]["value"]) != `{"a":1}` {
+		t.Errorf("produced %v, want one record keyed snapshot_saved", record)
+	}
+}
+
+func TestRabbitMQManagementPublisher(t *testing.T) {
+	routed := true
+	var message struct {
+		RoutingKey string `json:"routing_key"`
+		Payload    string `json:"payload"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/exchanges/%2F/amq.topic/publish" {
+			t.Errorf("published to %s", r.URL.EscapedPath())
+		}
+		json.NewDecoder(r.Body).Decode(&message)
+		fmt.Fprintf(w, `{"routed":%t}`, routed)
+	}))
+	defer server.Close()
+
+	publisher, err := NewRabbitMQManagementPublisher(server.URL, "", "amq.topic", "radb", nil)
+	if err != nil {
+		t.Fatalf("NewRabbitMQManagementPublisher() failed: %v", err)
+	}
+	if err := publisher.Publish(context.Background(), events.TypeCheckFailed, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("Publish() failed: %v", err)
+	}
+	if message.RoutingKey != "radb.check_failed" || message.Payload != `{"a":1}` {
+		t.Errorf("published %+v, want routing key radb.check_failed", message)
+	}
+
+	routed = false
+	if err := publisher.Publish(context.Background(), events.TypeCheckFailed, []byte(`{}`)); err == nil {
+		t.Error("Publish() succeeded for an unroutable message")
+	}
+}
+
+// recordingPublisher keeps every published message.
+type recordingPublisher struct {
+	messages []Message
+	closed   bool
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, eventType events.Type, payload []byte) error {
+	var message struct {
+		Message
+		Event json.RawMessage `json:"event"`
+	}
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return err
+	}
+	p.messages = append(p.messages, message.Message)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestExporterSubscribe(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	recorder := &recordingPublisher{}
+	exporter := NewExporter(map[string]Publisher{"test": recorder}, "RADB", logger)
+
+	bus := events.NewBus()
+	stop := exporter.Subscribe(bus, 4)
+	now := time.Now()
+	bus.Publish(&events.ChangesDetected{Time: now, SnapshotID: "route-2", PreviousID: "route-1", Summary: map[models.ChangeType]int{models.ChangeTypeAdded: 1}})
+	bus.Publish(&events.CheckFailed{Time: now, Action: "check", Error: "timeout"})
+	stop()
+
+	if len(recorder.messages) != 2 || !recorder.closed {
+		t.Fatalf("exported %d messages, closed=%v; want 2 and closed", len(recorder.messages), recorder.closed)
+	}
+	if got := recorder.messages[0]; got.Type != events.TypeChangesDetected || got.Source != "RADB" {
+		t.Errorf("first message = %+v, want changes_detected from RADB", got)
+	}
+}
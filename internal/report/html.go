@@ -0,0 +1,106 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// htmlTemplate is a self-contained page: styles are inline and nothing is
+// loaded from elsewhere, so the file can be attached or mailed as is.
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lines":     lines,
+	"lineClass": diffLineClass,
+	"time":      func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.3em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; margin-top: 2em; }
+h3 { font-size: 1.05em; }
+table { border-collapse: collapse; margin: .5em 0 1em; }
+th, td { border: 1px solid #d0d7de; padding: .3em .7em; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.num { text-align: right; }
+pre { background: #f6f8fa; padding: .6em; margin: 0; overflow-x: auto; font-size: .9em; }
+code, pre { font-family: SFMono-Regular, Consolas, "Liberation Mono", monospace; }
+.meta { color: #59636e; }
+.added { color: #1a7f37; }
+.removed { color: #cf222e; }
+.modified { color: #9a6700; }
+.sides { display: flex; gap: 1em; }
+.sides > div { flex: 1; min-width: 0; }
+.diff span { display: block; white-space: pre; }
+.diff .add { background: #dafbe1; }
+.diff .del { background: #ffebe9; }
+.diff .hunk { color: #0969da; }
+.diff .file { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">From <code>{{.FromID}}</code> ({{time .FromTime}}) to <code>{{.ToID}}</code> ({{time .ToTime}}).
+Generated {{time .Generated}}.</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Type</th><th>Added</th><th>Removed</th><th>Modified</th></tr>
+{{- range .Types}}
+<tr><td>{{.ObjectType}}</td><td class="num">{{.Added}}</td><td class="num">{{.Removed}}</td><td class="num">{{.Modified}}</td></tr>
+{{- end}}
+<tr><th>Total</th><th class="added">{{.Summary.AddedCount}}</th><th class="removed">{{.Summary.RemovedCount}}</th><th class="modified">{{.Summary.ModifiedCount}}</th></tr>
+</table>
+{{- if eq .Summary.TotalChanges 0}}
+<p>No changes.</p>
+{{- end}}
+
+{{- if .Added}}
+<h2 class="added">Added</h2>
+{{- range .Added}}
+<h3>{{.ObjectType}} <code>{{.ID}}</code></h3>
+<pre>{{.RPSL}}</pre>
+{{- end}}
+{{- end}}
+
+{{- if .Removed}}
+<h2 class="removed">Removed</h2>
+{{- range .Removed}}
+<h3>{{.ObjectType}} <code>{{.ID}}</code></h3>
+<pre>{{.RPSL}}</pre>
+{{- end}}
+{{- end}}
+
+{{- if .Modified}}
+<h2 class="modified">Modified</h2>
+{{- range .Modified}}
+<h3>{{.ObjectType}} <code>{{.ID}}</code></h3>
+{{- if .Fields}}
+<table>
+<tr><th>Field</th><th>Before</th><th>After</th></tr>
+{{- range .Fields}}
+<tr><td>{{.Field}}</td><td><code>{{.OldValue}}</code></td><td><code>{{.NewValue}}</code></td></tr>
+{{- end}}
+</table>
+{{- end}}
+<div class="sides">
+<div><strong>Before</strong><pre>{{.Before}}</pre></div>
+<div><strong>After</strong><pre>{{.After}}</pre></div>
+</div>
+{{- if .Diff}}
+<p><strong>Diff</strong></p>
+<pre class="diff">{{range lines .Diff}}<span class="{{lineClass .}}">{{.}}</span>{{end}}</pre>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML renders the report as a self-contained HTML page.
+func (r *DiffReport) WriteHTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, r)
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteMarkdown renders the report as Markdown, with RPSL and diffs in
+// fenced code blocks.
+func (r *DiffReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	fmt.Fprintf(&b, "- From: `%s` (%s)\n", r.FromID, r.FromTime.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- To: `%s` (%s)\n", r.ToID, r.ToTime.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Generated: %s\n", r.Generated.UTC().Format(time.RFC3339))
+
+	fmt.Fprintf(&b, "\n## Summary\n\n")
+	fmt.Fprintf(&b, "| Type | Added | Removed | Modified |\n")
+	fmt.Fprintf(&b, "|------|------:|--------:|---------:|\n")
+	for _, t := range r.Types {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", t.ObjectType, t.Added, t.Removed, t.Modified)
+	}
+	fmt.Fprintf(&b, "| **Total** | **%d** | **%d** | **%d** |\n",
+		r.Summary.AddedCount, r.Summary.RemovedCount, r.Summary.ModifiedCount)
+
+	if r.Summary.TotalChanges == 0 {
+		fmt.Fprintf(&b, "\nNo changes.\n")
+	}
+
+	writeObjects := func(title string, objects []Object) {
+		if len(objects) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s\n", title)
+		for _, object := range objects {
+			fmt.Fprintf(&b, "\n### %s `%s`\n\n", object.ObjectType, object.ID)
+			fmt.Fprintf(&b, "```\n%s```\n", object.RPSL)
+		}
+	}
+	writeObjects("Added", r.Added)
+	writeObjects("Removed", r.Removed)
+
+	if len(r.Modified) > 0 {
+		fmt.Fprintf(&b, "\n## Modified\n")
+		for _, object := range r.Modified {
+			fmt.Fprintf(&b, "\n### %s `%s`\n\n", object.ObjectType, object.ID)
+			if len(object.Fields) > 0 {
+				fmt.Fprintf(&b, "| Field | Before | After |\n")
+				fmt.Fprintf(&b, "|-------|--------|-------|\n")
+				for _, field := range object.Fields {
+					fmt.Fprintf(&b, "| %s | %s | %s |\n", field.Field, markdownCode(field.OldValue), markdownCode(field.NewValue))
+				}
+				fmt.Fprintln(&b)
+			}
+			if object.Diff != "" {
+				fmt.Fprintf(&b, "```diff\n%s```\n", object.Diff)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCode formats a value as inline code safe inside a table cell.
+func markdownCode(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "`" + strings.ReplaceAll(value, "|", `\|`) + "`"
+}
@@ -0,0 +1,193 @@
+// Package report renders snapshot diffs as self-contained HTML and Markdown
+// documents for change tickets and email.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Report formats.
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+// DiffReport is a diff between two snapshots prepared for rendering.
+type DiffReport struct {
+	Title     string
+	FromID    string
+	ToID      string
+	FromTime  time.Time
+	ToTime    time.Time
+	Generated time.Time
+	Summary   models.DiffSummary
+	Types     []TypeCount // Changes per object type, in name order
+	Added     []Object
+	Removed   []Object
+	Modified  []ModifiedObject
+}
+
+// TypeCount counts the changes to one object type.
+type TypeCount struct {
+	ObjectType string
+	models.TypeSummary
+}
+
+// Object is an added or removed object.
+type Object struct {
+	ObjectType string
+	ID         string
+	RPSL       string
+}
+
+// ModifiedObject is an object changed between the snapshots.
+type ModifiedObject struct {
+	ObjectType string
+	ID         string
+	Fields     []FieldChange
+	Before     string // RPSL before the change
+	After      string // RPSL after the change
+	Diff       string // Unified diff of Before and After
+}
+
+// FieldChange is a changed field with its old and new values as JSON.
+type FieldChange struct {
+	Field    string
+	OldValue string
+	NewValue string
+}
+
+// NewDiffReport prepares the diff between two snapshots for rendering.
+func NewDiffReport(from, to *models.Snapshot, diff *models.DiffResult) *DiffReport {
+	r := &DiffReport{
+		Title:     fmt.Sprintf("Changes from %s to %s", from.ID, to.ID),
+		FromID:    from.ID,
+		ToID:      to.ID,
+		FromTime:  from.Timestamp,
+		ToTime:    to.Timestamp,
+		Generated: time.Now().UTC(),
+		Summary:   diff.Summary,
+	}
+
+	counts := make(map[string]*models.TypeSummary)
+	count := func(objectType string) *models.TypeSummary {
+		if counts[objectType] == nil {
+			counts[objectType] = &models.TypeSummary{}
+		}
+		return counts[objectType]
+	}
+
+	for _, item := range diff.Added {
+		object := newObject(item)
+		count(object.ObjectType).Added++
+		r.Added = append(r.Added, object)
+	}
+	for _, item := range diff.Removed {
+		object := newObject(item)
+		count(object.ObjectType).Removed++
+		r.Removed = append(r.Removed, object)
+	}
+	for _, item := range diff.Modified {
+		count(item.ObjectType).Modified++
+		before, _ := models.ObjectRPSL(item.ObjectType, item.Before)
+		after, _ := models.ObjectRPSL(item.ObjectType, item.After)
+		modified := ModifiedObject{
+			ObjectType: item.ObjectType,
+			ID:         item.ID,
+			Before:     before,
+			After:      after,
+			Diff:       item.RPSLDiff(),
+		}
+		for _, fc := range item.FieldChanges {
+			modified.Fields = append(modified.Fields, FieldChange{
+				Field:    fc.Field,
+				OldValue: string(fc.OldValue),
+				NewValue: string(fc.NewValue),
+			})
+		}
+		r.Modified = append(r.Modified, modified)
+	}
+
+	sortObjects(r.Added)
+	sortObjects(r.Removed)
+	sort.Slice(r.Modified, func(i, j int) bool {
+		if r.Modified[i].ObjectType != r.Modified[j].ObjectType {
+			return r.Modified[i].ObjectType < r.Modified[j].ObjectType
+		}
+		return r.Modified[i].ID < r.Modified[j].ID
+	})
+
+	for objectType, summary := range counts {
+		r.Types = append(r.Types, TypeCount{ObjectType: objectType, TypeSummary: *summary})
+	}
+	sort.Slice(r.Types, func(i, j int) bool { return r.Types[i].ObjectType < r.Types[j].ObjectType })
+
+	return r
+}
+
+// Write renders the report in the given format.
+func (r *DiffReport) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatHTML:
+		return r.WriteHTML(w)
+	case FormatMarkdown:
+		return r.WriteMarkdown(w)
+	default:
+		return fmt.Errorf("unsupported report format %q (want html or markdown)", format)
+	}
+}
+
+// newObject describes an added or removed diff item.
+func newObject(item interface{}) Object {
+	var object Object
+	switch v := item.(type) {
+	case *models.RouteObject:
+		object = Object{ObjectType: "route", ID: v.ID()}
+	case *models.Contact:
+		object = Object{ObjectType: "contact", ID: v.ID}
+	default:
+		return Object{ObjectType: "unknown", ID: "unknown"}
+	}
+	object.RPSL, _ = models.ObjectRPSL(object.ObjectType, item)
+	return object
+}
+
+// sortObjects orders objects by type and ID.
+func sortObjects(objects []Object) {
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].ObjectType != objects[j].ObjectType {
+			return objects[i].ObjectType < objects[j].ObjectType
+		}
+		return objects[i].ID < objects[j].ID
+	})
+}
+
+// diffLineClass classifies a unified diff line for styling.
+func diffLineClass(line string) string {
+	switch {
+	case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+		return "file"
+	case strings.HasPrefix(line, "@@"):
+		return "hunk"
+	case strings.HasPrefix(line, "+"):
+		return "add"
+	case strings.HasPrefix(line, "-"):
+		return "del"
+	default:
+		return "ctx"
+	}
+}
+
+// lines splits text into lines without the trailing newline.
+func lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,95 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+)
+
+func testReport(t *testing.T) *DiffReport {
+	t.Helper()
+
+	route := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"Old <descr>"}, MntBy: []string{"MAINT-A"}, Source: "RADB"}
+	changed := route
+	changed.Descr = []string{"New <descr>"}
+	added := models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64501", MntBy: []string{"MAINT-A"}, Source: "RADB"}
+
+	from := &models.Snapshot{ID: "route-1", Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Routes: &models.RouteList{Routes: []models.RouteObject{route}}}
+	to := &models.Snapshot{ID: "route-2", Timestamp: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		Routes:   &models.RouteList{Routes: []models.RouteObject{changed, added}},
+		Contacts: &models.ContactList{Contacts: []models.Contact{{ID: "C1", Name: "NOC", Email: "noc@example.com", Role: models.ContactRoleTech}}}}
+
+	diff, err := state.ComputeDiff(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("ComputeDiff() failed: %v", err)
+	}
+	return NewDiffReport(from, to, diff)
+}
+
+func TestNewDiffReport(t *testing.T) {
+	r := testReport(t)
+
+	if len(r.Added) != 2 || r.Added[0].ObjectType != "contact" || r.Added[1].ID != "198.51.100.0/24-AS64501" {
+		t.Errorf("Added = %+v, want the contact and the new route in order", r.Added)
+	}
+	if len(r.Types) != 2 || r.Types[1].ObjectType != "route" || r.Types[1].Added != 1 || r.Types[1].Modified != 1 {
+		t.Errorf("Types = %+v, want an added contact and 1 added and 1 modified route", r.Types)
+	}
+	if len(r.Modified) != 1 || !strings.Contains(r.Modified[0].Diff, "+descr: New <descr>") {
+		t.Errorf("Modified = %+v, want the descr change", r.Modified)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport(t).Write(&buf, FormatMarkdown); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# Changes from route-1 to route-2",
+		"| route | 1 | 0 | 1 |",
+		"### route `198.51.100.0/24-AS64501`\n\n```\nroute: 198.51.100.0/24\n",
+		"```diff\n--- 192.0.2.0/24-AS64500 (before)\n",
+		"-descr: Old <descr>\n+descr: New <descr>\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Markdown report is missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport(t).Write(&buf, FormatHTML); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>Changes from route-1 to route-2</title>",
+		`<span class="del">-descr: Old &lt;descr&gt;</span>`,
+		`<span class="add">&#43;descr: New &lt;descr&gt;</span>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML report is missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<descr>") {
+		t.Error("HTML report contains unescaped object text")
+	}
+	if strings.Contains(out, "<link") || strings.Contains(out, "<script") {
+		t.Error("HTML report is not self-contained")
+	}
+
+	if err := testReport(t).Write(&buf, "pdf"); err == nil {
+		t.Error("Write() accepted an unsupported format")
+	}
+}
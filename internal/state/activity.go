@@ -0,0 +1,222 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// topMaintainers is how many maintainers an activity summary ranks.
+const topMaintainers = 5
+
+// SummarizeActivity pulls together the snapshots, changelog entries,
+// transactions, and bulk journals of the period from since to until into
+// one summary. Snapshots are listed newest first, as ListSnapshots returns
+// them; snapshots before the period only supply the starting route count.
+func SummarizeActivity(since, until time.Time, snapshots []models.Snapshot, entries []models.ChangelogEntry, transactions []*models.Transaction, journals []*models.BulkJournal) *models.ActivitySummary {
+	summary := &models.ActivitySummary{
+		Since:           since,
+		Until:           until,
+		SnapshotsByType: make(map[models.SnapshotType]int),
+		Changes:         make(map[models.ChangeType]int),
+		TopMaintainers:  []models.MaintainerActivity{},
+		Events:          []models.ActivityEvent{},
+	}
+
+	summarizeSnapshots(summary, snapshots)
+	summarizeChanges(summary, entries)
+	summarizeWrites(summary, transactions, journals)
+
+	sort.SliceStable(summary.Events, func(i, j int) bool {
+		a, b := summary.Events[i], summary.Events[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return a.ObjectID < b.ObjectID
+	})
+	return summary
+}
+
+// within reports whether t falls in the summary's period.
+func within(summary *models.ActivitySummary, t time.Time) bool {
+	return !t.Before(summary.Since) && !t.After(summary.Until)
+}
+
+// summarizeSnapshots counts the snapshots taken and the route counts at
+// either end of the period.
+func summarizeSnapshots(summary *models.ActivitySummary, snapshots []models.Snapshot) {
+	// Walk oldest first so the last route count seen is the newest
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		snapshot := snapshots[i]
+		if snapshot.Timestamp.After(summary.Until) {
+			continue
+		}
+
+		inPeriod := within(summary, snapshot.Timestamp)
+		if inPeriod {
+			summary.Snapshots++
+			summary.SnapshotsByType[snapshot.Type]++
+		}
+
+		if snapshot.Type == models.SnapshotTypeContact || snapshot.Counts == nil {
+			continue
+		}
+		routes := snapshot.Counts.Routes
+		if !inPeriod || summary.RoutesBefore == nil {
+			summary.RoutesBefore = &routes
+		}
+		if inPeriod {
+			summary.RoutesAfter = &routes
+		}
+	}
+
+	// Only snapshots before the period: nothing changed within it
+	if summary.RoutesAfter == nil {
+		summary.RoutesBefore = nil
+	}
+}
+
+// summarizeChanges counts changes by type and maintainer, and picks out
+// origin changes and deleted routes. An origin change shows up as a route
+// removed and re-added with another origin in the same snapshot.
+func summarizeChanges(summary *models.ActivitySummary, entries []models.ChangelogEntry) {
+	byMaintainer := make(map[string]int)
+	type snapshotRoutes struct {
+		removed map[string][]models.ChangelogEntry
+		added   map[string][]string
+	}
+	bySnapshot := make(map[string]*snapshotRoutes)
+	var snapshotOrder []string
+
+	for _, entry := range entries {
+		if !within(summary, entry.Timestamp) {
+			continue
+		}
+		summary.Changes[entry.ChangeType]++
+		if entry.ObjectType != "route" {
+			continue
+		}
+
+		route := changelogRoute(entry)
+		if route == nil {
+			continue
+		}
+		for _, maintainer := range route.MntBy {
+			byMaintainer[strings.ToUpper(maintainer)]++
+		}
+
+		routes := bySnapshot[entry.SnapshotID]
+		if routes == nil {
+			routes = &snapshotRoutes{removed: make(map[string][]models.ChangelogEntry), added: make(map[string][]string)}
+			bySnapshot[entry.SnapshotID] = routes
+			snapshotOrder = append(snapshotOrder, entry.SnapshotID)
+		}
+		switch entry.ChangeType {
+		case models.ChangeTypeRemoved:
+			routes.removed[route.Route] = append(routes.removed[route.Route], entry)
+		case models.ChangeTypeAdded:
+			routes.added[route.Route] = append(routes.added[route.Route], route.Origin)
+		}
+	}
+
+	for _, snapshotID := range snapshotOrder {
+		routes := bySnapshot[snapshotID]
+		for prefix, removed := range routes.removed {
+			added := routes.added[prefix]
+			for _, entry := range removed {
+				event := models.ActivityEvent{Timestamp: entry.Timestamp, Kind: models.ActivityDeleted, ObjectID: entry.ObjectID}
+				if len(added) > 0 {
+					from := changelogRoute(entry).Origin
+					event.Kind = models.ActivityOriginChange
+					event.ObjectID = prefix
+					event.Detail = fmt.Sprintf("%s -> %s", from, added[0])
+					added = added[1:]
+				}
+				summary.Events = append(summary.Events, event)
+			}
+		}
+	}
+
+	for maintainer, changes := range byMaintainer {
+		summary.TopMaintainers = append(summary.TopMaintainers, models.MaintainerActivity{Maintainer: maintainer, Changes: changes})
+	}
+	sort.Slice(summary.TopMaintainers, func(i, j int) bool {
+		a, b := summary.TopMaintainers[i], summary.TopMaintainers[j]
+		if a.Changes != b.Changes {
+			return a.Changes > b.Changes
+		}
+		return a.Maintainer < b.Maintainer
+	})
+	if len(summary.TopMaintainers) > topMaintainers {
+		summary.TopMaintainers = summary.TopMaintainers[:topMaintainers]
+	}
+}
+
+// changelogRoute decodes the route a changelog entry describes: its state
+// after the change, or before it for removals.
+func changelogRoute(entry models.ChangelogEntry) *models.RouteObject {
+	data := entry.After
+	if len(data) == 0 {
+		data = entry.Before
+	}
+	var route models.RouteObject
+	if len(data) == 0 || json.Unmarshal(data, &route) != nil {
+		return nil
+	}
+	return &route
+}
+
+// summarizeWrites counts the writes made by single-route commands and
+// bulk operations, and picks out the ones that failed.
+func summarizeWrites(summary *models.ActivitySummary, transactions []*models.Transaction, journals []*models.BulkJournal) {
+	for _, tx := range transactions {
+		if !within(summary, tx.UpdatedAt) {
+			continue
+		}
+
+		var failure string
+		for _, step := range tx.Steps {
+			if step.Error != "" {
+				failure = step.Error
+			}
+		}
+		switch {
+		case tx.Status == models.TransactionCommitted:
+			summary.WritesSucceeded++
+		case tx.Status == models.TransactionRolledBack || failure != "":
+			summary.WritesFailed++
+			summary.Events = append(summary.Events, models.ActivityEvent{
+				Timestamp: tx.UpdatedAt,
+				Kind:      models.ActivityWriteFailed,
+				ObjectID:  tx.ObjectID,
+				Detail:    strings.TrimSpace(fmt.Sprintf("%s %s", tx.Operation, failure)),
+			})
+		}
+	}
+
+	for _, journal := range journals {
+		for _, run := range journal.Runs {
+			if within(summary, run.StartedAt) {
+				summary.WritesSucceeded += run.Succeeded
+				summary.WritesFailed += run.Failed
+			}
+		}
+		if !within(summary, journal.UpdatedAt) {
+			continue
+		}
+		for _, item := range journal.Items {
+			if item.Status == models.JournalFailed {
+				summary.Events = append(summary.Events, models.ActivityEvent{
+					Timestamp: journal.UpdatedAt,
+					Kind:      models.ActivityWriteFailed,
+					ObjectID:  item.ID,
+					Detail:    fmt.Sprintf("%s: %s", journal.Operation, item.Error),
+				})
+			}
+		}
+	}
+}
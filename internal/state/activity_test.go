@@ -0,0 +1,107 @@
+package state
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestSummarizeActivity(t *testing.T) {
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := since.Add(7 * 24 * time.Hour)
+
+	// Newest first, as ListSnapshots returns them
+	snapshots := []models.Snapshot{
+		{ID: "late", Type: models.SnapshotTypeRoute, Timestamp: until.Add(time.Hour), Counts: &models.SnapshotCounts{Routes: 99}},
+		{ID: "r3", Type: models.SnapshotTypeRoute, Timestamp: since.Add(72 * time.Hour), Counts: &models.SnapshotCounts{Routes: 12}},
+		{ID: "c1", Type: models.SnapshotTypeContact, Timestamp: since.Add(48 * time.Hour), Counts: &models.SnapshotCounts{Contacts: 4}},
+		{ID: "r2", Type: models.SnapshotTypeRoute, Timestamp: since.Add(24 * time.Hour), Counts: &models.SnapshotCounts{Routes: 11}},
+		{ID: "r1", Type: models.SnapshotTypeRoute, Timestamp: since.Add(-time.Hour), Counts: &models.SnapshotCounts{Routes: 10}},
+	}
+
+	route := func(prefix, origin string, maintainers ...string) json.RawMessage {
+		data, _ := json.Marshal(models.RouteObject{Route: prefix, Origin: origin, MntBy: maintainers})
+		return data
+	}
+	at := since.Add(72 * time.Hour)
+	entries := []models.ChangelogEntry{
+		{Timestamp: since.Add(-time.Minute), ChangeType: models.ChangeTypeAdded, ObjectType: "route", ObjectID: "old", After: route("203.0.113.0/24", "AS64500", "MAINT-OLD")},
+		{Timestamp: at, SnapshotID: "r3", ChangeType: models.ChangeTypeRemoved, ObjectType: "route", ObjectID: "192.0.2.0/24-AS64500", Before: route("192.0.2.0/24", "AS64500", "MAINT-A")},
+		{Timestamp: at, SnapshotID: "r3", ChangeType: models.ChangeTypeAdded, ObjectType: "route", ObjectID: "192.0.2.0/24-AS64501", After: route("192.0.2.0/24", "AS64501", "maint-a")},
+		{Timestamp: at, SnapshotID: "r3", ChangeType: models.ChangeTypeRemoved, ObjectType: "route", ObjectID: "198.51.100.0/24-AS64500", Before: route("198.51.100.0/24", "AS64500", "MAINT-A", "MAINT-B")},
+		{Timestamp: at, SnapshotID: "r3", ChangeType: models.ChangeTypeModified, ObjectType: "route", ObjectID: "203.0.113.0/24-AS64500", After: route("203.0.113.0/24", "AS64500", "MAINT-B")},
+		{Timestamp: at, SnapshotID: "c1", ChangeType: models.ChangeTypeModified, ObjectType: "contact", ObjectID: "CONTACT-1"},
+	}
+
+	transactions := []*models.Transaction{
+		{ObjectID: "192.0.2.0/25-AS64500", Operation: models.ChangeTypeAdded, Status: models.TransactionCommitted, UpdatedAt: since.Add(time.Hour)},
+		{ObjectID: "192.0.2.128/25-AS64500", Operation: models.ChangeTypeRemoved, Status: models.TransactionRolledBack, UpdatedAt: since.Add(2 * time.Hour),
+			Steps: []models.TransactionStepRecord{{Name: models.StepWrite, Error: "permission denied"}}},
+		{ObjectID: "outside", Status: models.TransactionCommitted, UpdatedAt: since.Add(-time.Hour)},
+	}
+	journals := []*models.BulkJournal{{
+		Operation: models.BulkDeleteRoutes,
+		UpdatedAt: since.Add(3 * time.Hour),
+		Items:     []models.JournalItem{{ID: "a", Status: models.JournalSucceeded}, {ID: "b", Status: models.JournalFailed, Error: "timeout"}},
+		Runs:      []models.BulkRun{{StartedAt: since.Add(3 * time.Hour), Total: 2, Succeeded: 1, Failed: 1}},
+	}}
+
+	summary := SummarizeActivity(since, until, snapshots, entries, transactions, journals)
+
+	if summary.Snapshots != 3 || summary.SnapshotsByType[models.SnapshotTypeRoute] != 2 || summary.SnapshotsByType[models.SnapshotTypeContact] != 1 {
+		t.Errorf("snapshots = %d %v, want 3 (2 route, 1 contact)", summary.Snapshots, summary.SnapshotsByType)
+	}
+	if net, ok := summary.NetRoutes(); !ok || net != 2 || *summary.RoutesBefore != 10 || *summary.RoutesAfter != 12 {
+		t.Errorf("NetRoutes() = %d, %v; want 10 -> 12", net, ok)
+	}
+
+	if summary.Changes[models.ChangeTypeRemoved] != 2 || summary.Changes[models.ChangeTypeAdded] != 1 || summary.Changes[models.ChangeTypeModified] != 2 {
+		t.Errorf("changes = %v, want 1 added, 2 removed, 2 modified", summary.Changes)
+	}
+	wantMaintainers := []models.MaintainerActivity{{Maintainer: "MAINT-A", Changes: 3}, {Maintainer: "MAINT-B", Changes: 2}}
+	if len(summary.TopMaintainers) != len(wantMaintainers) {
+		t.Fatalf("top maintainers = %v, want %v", summary.TopMaintainers, wantMaintainers)
+	}
+	for i, want := range wantMaintainers {
+		if summary.TopMaintainers[i] != want {
+			t.Errorf("top maintainer %d = %v, want %v", i, summary.TopMaintainers[i], want)
+		}
+	}
+
+	if summary.WritesSucceeded != 2 || summary.WritesFailed != 2 {
+		t.Errorf("writes = %d succeeded, %d failed; want 2 and 2", summary.WritesSucceeded, summary.WritesFailed)
+	}
+
+	wantEvents := []models.ActivityEvent{
+		{Kind: models.ActivityWriteFailed, ObjectID: "192.0.2.128/25-AS64500"},
+		{Kind: models.ActivityWriteFailed, ObjectID: "b"},
+		{Kind: models.ActivityOriginChange, ObjectID: "192.0.2.0/24", Detail: "AS64500 -> AS64501"},
+		{Kind: models.ActivityDeleted, ObjectID: "198.51.100.0/24-AS64500"},
+	}
+	if len(summary.Events) != len(wantEvents) {
+		t.Fatalf("events = %+v, want %d", summary.Events, len(wantEvents))
+	}
+	for i, want := range wantEvents {
+		got := summary.Events[i]
+		if got.Kind != want.Kind || got.ObjectID != want.ObjectID || (want.Detail != "" && got.Detail != want.Detail) {
+			t.Errorf("event %d = %+v, want %+v", i, got, want)
+		}
+	}
+}
+
+func TestSummarizeActivityWithoutRouteSnapshots(t *testing.T) {
+	since := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []models.Snapshot{
+		{Type: models.SnapshotTypeRoute, Timestamp: since.Add(-time.Hour), Counts: &models.SnapshotCounts{Routes: 10}},
+	}
+
+	summary := SummarizeActivity(since, since.Add(time.Hour), snapshots, nil, nil, nil)
+	if _, ok := summary.NetRoutes(); ok {
+		t.Error("NetRoutes() reported a change without a route snapshot in the period")
+	}
+	if summary.Snapshots != 0 {
+		t.Errorf("snapshots = %d, want 0", summary.Snapshots)
+	}
+}
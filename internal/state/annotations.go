@@ -0,0 +1,188 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/gofrs/flock"
+	"github.com/sirupsen/logrus"
+)
+
+// annotationsFile is the name of the annotation store within the state directory.
+const annotationsFile = "annotations.json"
+
+// AnnotationStore persists local notes and labels attached to objects.
+type AnnotationStore struct {
+	path   string
+	lock   *flock.Flock
+	logger *logrus.Logger
+}
+
+// NewAnnotationStore creates an annotation store in the given state directory.
+func NewAnnotationStore(stateDir string, logger *logrus.Logger) *AnnotationStore {
+	path := filepath.Join(stateDir, annotationsFile)
+	return &AnnotationStore{
+		path:   path,
+		lock:   flock.New(path + ".lock"),
+		logger: logger,
+	}
+}
+
+// All returns every annotation, indexed by object.
+func (s *AnnotationStore) All(ctx context.Context) (models.Annotations, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	locked, err := s.lock.TryRLockContext(ctx, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return nil, errors.New("could not acquire lock: timeout")
+	}
+	defer s.lock.Unlock()
+
+	return s.read()
+}
+
+// List returns every annotation sorted by object type and ID.
+func (s *AnnotationStore) List(ctx context.Context) ([]models.Annotation, error) {
+	all, err := s.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Annotation, 0, len(all))
+	for _, annotation := range all {
+		list = append(list, annotation)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key() < list[j].Key()
+	})
+
+	return list, nil
+}
+
+// Get returns the annotation for an object, or nil if it has none.
+func (s *AnnotationStore) Get(ctx context.Context, objectType, objectID string) (*models.Annotation, error) {
+	all, err := s.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, ok := all.For(objectType, objectID)
+	if !ok {
+		return nil, nil
+	}
+	return &annotation, nil
+}
+
+// Update applies fn to an object's annotation and saves the result.
+// fn receives an empty annotation if the object has none yet. Annotations
+// left without a note or labels are removed.
+func (s *AnnotationStore) Update(ctx context.Context, objectType, objectID string, fn func(*models.Annotation)) (*models.Annotation, error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	locked, err := s.lock.TryLockContext(ctx, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return nil, errors.New("could not acquire lock: timeout")
+	}
+	defer s.lock.Unlock()
+
+	all, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, ok := all.For(objectType, objectID)
+	if !ok {
+		annotation = models.Annotation{ObjectType: objectType, ObjectID: objectID}
+	}
+
+	fn(&annotation)
+	annotation.UpdatedAt = time.Now()
+
+	if annotation.IsEmpty() {
+		delete(all, annotation.Key())
+	} else {
+		all[annotation.Key()] = annotation
+	}
+
+	if err := s.write(all); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debugf("Updated annotation for %s", annotation.Key())
+	return &annotation, nil
+}
+
+// Remove deletes an object's annotation.
+func (s *AnnotationStore) Remove(ctx context.Context, objectType, objectID string) error {
+	_, err := s.Update(ctx, objectType, objectID, func(a *models.Annotation) {
+		a.Note = ""
+		a.Labels = nil
+	})
+	return err
+}
+
+// read loads the store from disk. A missing file is an empty store.
+func (s *AnnotationStore) read() (models.Annotations, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return models.Annotations{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations: %w", err)
+	}
+
+	var list []models.Annotation
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse annotations: %w", err)
+	}
+
+	all := make(models.Annotations, len(list))
+	for _, annotation := range list {
+		all[annotation.Key()] = annotation
+	}
+	return all, nil
+}
+
+// write saves the store atomically.
+func (s *AnnotationStore) write(all models.Annotations) error {
+	list := make([]models.Annotation, 0, len(all))
+	for _, annotation := range all {
+		list = append(list, annotation)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key() < list[j].Key()
+	})
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal annotations: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save annotations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestAnnotationStore(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	store := NewAnnotationStore(tmpDir, logger)
+	ctx := context.Background()
+
+	t.Run("GetMissing", func(t *testing.T) {
+		annotation, err := store.Get(ctx, "route", "192.0.2.0/24-AS64500")
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if annotation != nil {
+			t.Errorf("Expected no annotation, got %+v", annotation)
+		}
+	})
+
+	t.Run("UpdateAndGet", func(t *testing.T) {
+		_, err := store.Update(ctx, "route", "192.0.2.0/24-AS64500", func(a *models.Annotation) {
+			a.Note = "legacy, do not delete"
+			a.AddLabels("legacy", "customer-x", "legacy")
+		})
+		if err != nil {
+			t.Fatalf("Update() failed: %v", err)
+		}
+
+		annotation, err := store.Get(ctx, "route", "192.0.2.0/24-AS64500")
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if annotation.Note != "legacy, do not delete" {
+			t.Errorf("Expected note to persist, got %q", annotation.Note)
+		}
+		if len(annotation.Labels) != 2 || annotation.Labels[0] != "customer-x" {
+			t.Errorf("Expected sorted, deduplicated labels, got %v", annotation.Labels)
+		}
+	})
+
+	t.Run("AllIndexesByObject", func(t *testing.T) {
+		if _, err := store.Update(ctx, "contact", "CONTACT-1", func(a *models.Annotation) {
+			a.Note = "NOC escalation"
+		}); err != nil {
+			t.Fatalf("Update() failed: %v", err)
+		}
+
+		all, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All() failed: %v", err)
+		}
+		if got := all.Label("contact", "CONTACT-1"); got != "NOC escalation" {
+			t.Errorf("Label() = %q, want %q", got, "NOC escalation")
+		}
+		if got := all.Label("route", "192.0.2.0/24-AS64500"); got != "[customer-x, legacy] legacy, do not delete" {
+			t.Errorf("Label() = %q", got)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		if err := store.Remove(ctx, "contact", "CONTACT-1"); err != nil {
+			t.Fatalf("Remove() failed: %v", err)
+		}
+
+		list, err := store.List(ctx)
+		if err != nil {
+			t.Fatalf("List() failed: %v", err)
+		}
+		if len(list) != 1 || list[0].ObjectType != "route" {
+			t.Errorf("Expected only the route annotation to remain, got %+v", list)
+		}
+	})
+
+	t.Run("IgnoredBySnapshotListing", func(t *testing.T) {
+		mgr, err := NewFileManager(tmpDir, logger)
+		if err != nil {
+			t.Fatalf("NewFileManager() failed: %v", err)
+		}
+		defer mgr.Close()
+
+		snapshots, err := mgr.ListSnapshots(ctx)
+		if err != nil {
+			t.Fatalf("ListSnapshots() failed: %v", err)
+		}
+		if len(snapshots) != 0 {
+			t.Errorf("Expected annotation store not to be listed as a snapshot, got %d", len(snapshots))
+		}
+	})
+}
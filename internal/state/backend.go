@@ -0,0 +1,204 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// Backend stores snapshot documents by name. Implementations must make Put
+// atomic, so readers see either the previous or the new document.
+type Backend interface {
+	// Put stores data under name, replacing any existing document.
+	Put(ctx context.Context, name string, data []byte) error
+
+	// Get returns the document stored under name. A missing document yields
+	// an error wrapping fs.ErrNotExist.
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Delete removes the document stored under name. A missing document
+	// yields an error wrapping fs.ErrNotExist.
+	Delete(ctx context.Context, name string) error
+
+	// List returns the size of every stored document, keyed by name.
+	List(ctx context.Context) (map[string]int64, error)
+}
+
+// BackendManager implements the Manager interface over any Backend, such as
+// an object store shared by several hosts. Only snapshots are kept in the
+// backend; the changelog, bulk journals, and status files stay in the local
+// state directory.
+type BackendManager struct {
+	backend   Backend
+	logger    *logrus.Logger
+	integrity Integrity
+}
+
+// Ensure BackendManager implements Manager.
+var _ Manager = (*BackendManager)(nil)
+
+// NewBackendManager creates a state manager that stores snapshots in backend.
+func NewBackendManager(backend Backend, logger *logrus.Logger) *BackendManager {
+	return &BackendManager{
+		backend: backend,
+		logger:  logger,
+	}
+}
+
+// SetIntegrity configures the checksums recorded for saved snapshots.
+func (bm *BackendManager) SetIntegrity(integrity Integrity) {
+	bm.integrity = integrity
+}
+
+// SaveSnapshot validates, checksums, and stores a snapshot.
+func (bm *BackendManager) SaveSnapshot(ctx context.Context, snapshot *models.Snapshot) (err error) {
+	ctx, span := tracing.Start(ctx, "state.SaveSnapshot",
+		tracing.String("snapshot.id", snapshot.ID),
+		tracing.String("snapshot.type", string(snapshot.Type)))
+	defer func() { span.EndErr(err) }()
+
+	if err := snapshot.Validate(); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	snapshot.SetIntegrity(bm.integrity.Algorithm, bm.integrity.PerObject)
+	if err := snapshot.ComputeChecksum(); err != nil {
+		return fmt.Errorf("failed to compute checksum: %w", err)
+	}
+
+	size, err := storeSnapshot(ctx, bm, snapshot)
+	if err != nil {
+		return err
+	}
+
+	if snapshot.AliasOf != "" {
+		bm.logger.Infof("Saved snapshot %s as an alias of unchanged snapshot %s (%d bytes)", snapshot.ID, snapshot.AliasOf, size)
+		return nil
+	}
+	bm.logger.Infof("Saved snapshot %s (%d bytes)", snapshot.ID, size)
+	return nil
+}
+
+// LoadSnapshot fetches a snapshot and verifies its integrity. The ID may
+// name a baseline, as in "baseline:golden".
+func (bm *BackendManager) LoadSnapshot(ctx context.Context, id string) (_ *models.Snapshot, err error) {
+	ctx, span := tracing.Start(ctx, "state.LoadSnapshot", tracing.String("snapshot.id", id))
+	defer func() { span.EndErr(err) }()
+
+	if name, ok := BaselineName(id); ok {
+		if id, err = ResolveBaseline(ctx, bm, name); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot, err := readSnapshot(ctx, bm, id)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("snapshot not found: %s", id)
+		}
+		return nil, err
+	}
+
+	if err := snapshot.VerifyChecksum(); err != nil {
+		bm.logger.Warnf("Snapshot %s failed integrity check: %v", id, err)
+		return nil, fmt.Errorf("snapshot integrity check failed: %w", err)
+	}
+
+	bm.logger.Debugf("Loaded snapshot %s", id)
+	return snapshot, nil
+}
+
+// GetLatestSnapshot retrieves the most recent full-scope snapshot of a given type.
+func (bm *BackendManager) GetLatestSnapshot(ctx context.Context, snapshotType models.SnapshotType) (*models.Snapshot, error) {
+	return latestSnapshot(ctx, bm, snapshotType)
+}
+
+// ListSnapshots lists all stored snapshots, newest first, from the
+// catalog. Listed snapshots carry counts but no routes or contacts.
+func (bm *BackendManager) ListSnapshots(ctx context.Context) ([]models.Snapshot, error) {
+	sizes, err := bm.backend.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state backend: %w", err)
+	}
+
+	var names []string
+	for name := range sizes {
+		if strings.Contains(name, "/") || path.Ext(name) != ".json" || isStateFile(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+
+	snapshots, _ := listSnapshots(ctx, bm, names, bm.logger, true)
+	return snapshots, nil
+}
+
+// QuerySnapshots lists the snapshots matching query, newest first, from
+// the catalog.
+func (bm *BackendManager) QuerySnapshots(ctx context.Context, query SnapshotQuery) ([]models.Snapshot, error) {
+	return querySnapshots(ctx, bm, query)
+}
+
+// DeleteSnapshot removes a snapshot from the backend. If later unchanged
+// snapshots alias it, its data moves to the newest of them.
+func (bm *BackendManager) DeleteSnapshot(ctx context.Context, id string) error {
+	if err := removeSnapshot(ctx, bm, id); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("snapshot not found: %s", id)
+		}
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+
+	bm.logger.Infof("Deleted snapshot %s", id)
+	return nil
+}
+
+// TagSnapshot adds and removes tags on a snapshot and returns its tags.
+func (bm *BackendManager) TagSnapshot(ctx context.Context, id string, add, remove []string) ([]string, error) {
+	tags, err := retagSnapshot(ctx, bm, id, add, remove)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("snapshot not found: %s", id)
+	}
+	return tags, err
+}
+
+// ComputeChanges computes the differences between two snapshots.
+func (bm *BackendManager) ComputeChanges(ctx context.Context, from, to *models.Snapshot) (*models.ChangeSet, error) {
+	return computeChanges(ctx, bm.logger, from, to)
+}
+
+// Cleanup removes old snapshots based on retention policies.
+func (bm *BackendManager) Cleanup(ctx context.Context, options CleanupOptions) (*CleanupResult, error) {
+	sizes, err := bm.backend.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state backend: %w", err)
+	}
+
+	return runCleanup(ctx, bm, bm.logger, options, func(id string) int64 {
+		return sizes[id+".json"]
+	})
+}
+
+func (bm *BackendManager) readDocument(ctx context.Context, name string) ([]byte, error) {
+	return bm.backend.Get(ctx, name)
+}
+
+func (bm *BackendManager) writeDocument(ctx context.Context, name string, data []byte) error {
+	return bm.backend.Put(ctx, name, data)
+}
+
+func (bm *BackendManager) removeDocument(ctx context.Context, name string) error {
+	return bm.backend.Delete(ctx, name)
+}
+
+// Close releases resources. Backends hold no open handles, so it is a no-op.
+func (bm *BackendManager) Close() error {
+	return nil
+}
@@ -0,0 +1,115 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// BaselinePrefix marks a snapshot reference as a named baseline, as in
+// "baseline:golden". LoadSnapshot resolves these references, so anything
+// that loads snapshots by ID also accepts baselines.
+const BaselinePrefix = "baseline:"
+
+// Baseline is a named snapshot that comparisons can use as the approved
+// state. It is stored as the tag "baseline:<name>" on the snapshot, which
+// also protects the snapshot from cleanup.
+type Baseline struct {
+	Name       string              `json:"name"`
+	SnapshotID string              `json:"snapshot_id"`
+	Type       models.SnapshotType `json:"type"`
+	Timestamp  time.Time           `json:"timestamp"`
+}
+
+// BaselineName returns the baseline a snapshot reference names, if any.
+func BaselineName(ref string) (string, bool) {
+	return strings.CutPrefix(ref, BaselinePrefix)
+}
+
+// validateBaselineName rejects names that cannot be used in a reference.
+func validateBaselineName(name string) error {
+	if name == "" || strings.ContainsAny(name, ": \t\n") {
+		return fmt.Errorf("invalid baseline name %q: it must be non-empty without colons or spaces", name)
+	}
+	return nil
+}
+
+// SetBaseline makes the snapshot id the named baseline, moving the name
+// from any snapshot that held it before.
+func SetBaseline(ctx context.Context, mgr Manager, name, id string) error {
+	if err := validateBaselineName(name); err != nil {
+		return err
+	}
+	tag := BaselinePrefix + name
+
+	previous, err := mgr.QuerySnapshots(ctx, SnapshotQuery{Tags: []string{tag}})
+	if err != nil {
+		return fmt.Errorf("failed to look up baseline %s: %w", name, err)
+	}
+	if _, err := mgr.TagSnapshot(ctx, id, []string{tag}, nil); err != nil {
+		return err
+	}
+	for _, snap := range previous {
+		if snap.ID == id {
+			continue
+		}
+		if _, err := mgr.TagSnapshot(ctx, snap.ID, nil, []string{tag}); err != nil {
+			return fmt.Errorf("failed to move baseline %s from %s: %w", name, snap.ID, err)
+		}
+	}
+	return nil
+}
+
+// UnsetBaseline removes the named baseline. The snapshot itself is kept.
+func UnsetBaseline(ctx context.Context, mgr Manager, name string) error {
+	id, err := ResolveBaseline(ctx, mgr, name)
+	if err != nil {
+		return err
+	}
+	_, err = mgr.TagSnapshot(ctx, id, nil, []string{BaselinePrefix + name})
+	return err
+}
+
+// ResolveBaseline returns the ID of the snapshot the named baseline marks.
+func ResolveBaseline(ctx context.Context, mgr Manager, name string) (string, error) {
+	if err := validateBaselineName(name); err != nil {
+		return "", err
+	}
+
+	snapshots, err := mgr.QuerySnapshots(ctx, SnapshotQuery{Tags: []string{BaselinePrefix + name}, Limit: 1})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up baseline %s: %w", name, err)
+	}
+	if len(snapshots) == 0 {
+		return "", fmt.Errorf("baseline not set: %s", name)
+	}
+	return snapshots[0].ID, nil
+}
+
+// ListBaselines returns every baseline, sorted by name.
+func ListBaselines(ctx context.Context, mgr Manager) ([]Baseline, error) {
+	snapshots, err := mgr.ListSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	baselines := []Baseline{}
+	for _, snap := range snapshots {
+		for _, tag := range snap.Tags {
+			if name, ok := BaselineName(tag); ok {
+				baselines = append(baselines, Baseline{
+					Name:       name,
+					SnapshotID: snap.ID,
+					Type:       snap.Type,
+					Timestamp:  snap.Timestamp,
+				})
+			}
+		}
+	}
+	sort.Slice(baselines, func(i, j int) bool { return baselines[i].Name < baselines[j].Name })
+	return baselines, nil
+}
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestBaselines(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	mgr, err := NewFileManager(t.TempDir(), logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer mgr.Close()
+
+	ctx := context.Background()
+	var ids []string
+	for _, origin := range []string{"AS64500", "AS64501"} {
+		snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "")
+		snapshot.Routes = models.NewRouteList([]models.RouteObject{{Route: "192.0.2.0/24", Origin: origin, Source: "RADB"}})
+		if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+			t.Fatalf("SaveSnapshot() failed: %v", err)
+		}
+		ids = append(ids, snapshot.ID)
+	}
+
+	if _, err := mgr.LoadSnapshot(ctx, "baseline:golden"); err == nil {
+		t.Error("LoadSnapshot() resolved a baseline that is not set")
+	}
+	if err := SetBaseline(ctx, mgr, "bad name", ids[0]); err == nil {
+		t.Error("SetBaseline() accepted a name with a space")
+	}
+
+	if err := SetBaseline(ctx, mgr, "golden", ids[0]); err != nil {
+		t.Fatalf("SetBaseline() failed: %v", err)
+	}
+	loaded, err := mgr.LoadSnapshot(ctx, "baseline:golden")
+	if err != nil {
+		t.Fatalf("LoadSnapshot(baseline:golden) failed: %v", err)
+	}
+	if loaded.ID != ids[0] {
+		t.Errorf("baseline:golden = %s, want %s", loaded.ID, ids[0])
+	}
+
+	// Setting the baseline again moves it
+	if err := SetBaseline(ctx, mgr, "golden", ids[1]); err != nil {
+		t.Fatalf("SetBaseline() failed: %v", err)
+	}
+	baselines, err := ListBaselines(ctx, mgr)
+	if err != nil {
+		t.Fatalf("ListBaselines() failed: %v", err)
+	}
+	if len(baselines) != 1 || baselines[0].SnapshotID != ids[1] {
+		t.Errorf("ListBaselines() = %+v, want golden on %s only", baselines, ids[1])
+	}
+
+	if err := UnsetBaseline(ctx, mgr, "golden"); err != nil {
+		t.Fatalf("UnsetBaseline() failed: %v", err)
+	}
+	if _, err := ResolveBaseline(ctx, mgr, "golden"); err == nil {
+		t.Error("ResolveBaseline() found a removed baseline")
+	}
+	if _, err := mgr.LoadSnapshot(ctx, ids[1]); err != nil {
+		t.Errorf("UnsetBaseline() removed the snapshot: %v", err)
+	}
+}
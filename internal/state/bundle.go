@@ -0,0 +1,214 @@
+package state
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Snapshot bundles are gzipped tar archives holding a manifest and one
+// self-contained JSON file per snapshot:
+//
+//	manifest.json
+//	snapshots/<id>.json
+const (
+	bundleFormatVersion = 1
+	bundleManifestFile  = "manifest.json"
+	bundleSnapshotDir   = "snapshots/"
+
+	// maxBundleFileSize bounds each file read from a bundle
+	maxBundleFileSize = 1 << 30
+)
+
+// BundleManifest describes the snapshots in a bundle.
+type BundleManifest struct {
+	FormatVersion int           `json:"format_version"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Producer      string        `json:"producer"`
+	Snapshots     []BundleEntry `json:"snapshots"`
+}
+
+// BundleEntry describes one snapshot in a bundle. SHA256 covers the
+// snapshot's file; Checksum is the snapshot's own data checksum.
+type BundleEntry struct {
+	ID                string              `json:"id"`
+	Type              models.SnapshotType `json:"type"`
+	Timestamp         time.Time           `json:"timestamp"`
+	Note              string              `json:"note,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	Items             int                 `json:"items"`
+	Checksum          string              `json:"checksum"`
+	ChecksumAlgorithm string              `json:"checksum_algorithm,omitempty"`
+	File              string              `json:"file"`
+	SHA256            string              `json:"sha256"`
+}
+
+// WriteBundle writes snapshots to w as a bundle. producer identifies the
+// writing client in the manifest. Aliases must already be resolved.
+func WriteBundle(w io.Writer, snapshots []*models.Snapshot, producer string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	add := func(name string, data []byte) error {
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+		return nil
+	}
+
+	manifest := BundleManifest{
+		FormatVersion: bundleFormatVersion,
+		CreatedAt:     now.UTC(),
+		Producer:      producer,
+		Snapshots:     make([]BundleEntry, 0, len(snapshots)),
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.AliasOf != "" && snapshot.Routes == nil && snapshot.Contacts == nil {
+			return fmt.Errorf("snapshot %s is an unresolved alias of %s", snapshot.ID, snapshot.AliasOf)
+		}
+
+		// Bundles are self-contained, so aliases carry their data
+		record := *snapshot
+		record.AliasOf = ""
+
+		data, err := json.MarshalIndent(&record, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot %s: %w", snapshot.ID, err)
+		}
+
+		name := bundleSnapshotDir + snapshot.ID + ".json"
+		if err := add(name, data); err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		manifest.Snapshots = append(manifest.Snapshots, BundleEntry{
+			ID:                snapshot.ID,
+			Type:              snapshot.Type,
+			Timestamp:         snapshot.Timestamp,
+			Note:              snapshot.Note,
+			Tags:              snapshot.Tags,
+			Items:             snapshot.ItemCount(),
+			Checksum:          snapshot.Checksum,
+			ChecksumAlgorithm: snapshot.ChecksumAlgorithm,
+			File:              name,
+			SHA256:            hex.EncodeToString(sum[:]),
+		})
+	}
+
+	data, err := json.MarshalIndent(&manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := add(bundleManifestFile, data); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return nil
+}
+
+// ReadBundle reads a bundle and verifies every snapshot against the
+// manifest and its own checksum.
+func ReadBundle(r io.Reader) (*BundleManifest, []*models.Snapshot, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := header.Name
+		if name != bundleManifestFile && (!strings.HasPrefix(name, bundleSnapshotDir) || path.Clean(name) != name || path.Ext(name) != ".json") {
+			return nil, nil, fmt.Errorf("unexpected file %q in bundle", name)
+		}
+		if header.Size > maxBundleFileSize {
+			return nil, nil, fmt.Errorf("bundle file %s is too large (%d bytes)", name, header.Size)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s from bundle: %w", name, err)
+		}
+		files[name] = data
+	}
+
+	data, ok := files[bundleManifestFile]
+	if !ok {
+		return nil, nil, fmt.Errorf("bundle has no %s", bundleManifestFile)
+	}
+	var manifest BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > bundleFormatVersion {
+		return nil, nil, fmt.Errorf("unsupported bundle format version %d", manifest.FormatVersion)
+	}
+
+	snapshots := make([]*models.Snapshot, 0, len(manifest.Snapshots))
+	for _, entry := range manifest.Snapshots {
+		data, ok := files[entry.File]
+		if !ok {
+			return nil, nil, fmt.Errorf("bundle is missing %s for snapshot %s", entry.File, entry.ID)
+		}
+
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != entry.SHA256 {
+			return nil, nil, fmt.Errorf("snapshot %s does not match the bundle manifest", entry.ID)
+		}
+
+		var snapshot models.Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse snapshot %s: %w", entry.ID, err)
+		}
+		if snapshot.ID != entry.ID {
+			return nil, nil, fmt.Errorf("bundle file %s holds snapshot %s, not %s", entry.File, snapshot.ID, entry.ID)
+		}
+		if err := snapshot.VerifyChecksum(); err != nil {
+			return nil, nil, fmt.Errorf("snapshot %s integrity check failed: %w", entry.ID, err)
+		}
+
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	return &manifest, snapshots, nil
+}
@@ -0,0 +1,145 @@
+package state
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	routes := models.NewSnapshot(models.SnapshotTypeRoute, "pre-migration")
+	routes.Routes = models.NewRouteList([]models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+	})
+	routes.AddTags("baseline")
+	routes.AliasOf = "route-older"
+
+	contacts := models.NewSnapshot(models.SnapshotTypeContact, "")
+	contacts.ID = "contact-1"
+	contacts.Contacts = models.NewContactList([]models.Contact{{ID: "C1", Name: "NOC", Email: "noc@example.com"}})
+
+	for _, snapshot := range []*models.Snapshot{routes, contacts} {
+		if err := snapshot.ComputeChecksum(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, []*models.Snapshot{routes, contacts}, "radb-client test"); err != nil {
+		t.Fatalf("WriteBundle() failed: %v", err)
+	}
+
+	manifest, snapshots, err := ReadBundle(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadBundle() failed: %v", err)
+	}
+
+	if manifest.Producer != "radb-client test" || len(manifest.Snapshots) != 2 {
+		t.Fatalf("Unexpected manifest: %+v", manifest)
+	}
+	if len(snapshots) != 2 || snapshots[0].ID != routes.ID || snapshots[1].ID != contacts.ID {
+		t.Fatalf("Unexpected snapshots: %v", snapshots)
+	}
+	if snapshots[0].AliasOf != "" || !snapshots[0].HasTag("baseline") || snapshots[0].Checksum != routes.Checksum {
+		t.Errorf("Route snapshot not preserved: %+v", snapshots[0])
+	}
+	if snapshots[1].Contacts == nil || len(snapshots[1].Contacts.Contacts) != 1 {
+		t.Error("Contacts not preserved")
+	}
+}
+
+func TestReadBundleRejectsTampering(t *testing.T) {
+	snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "")
+	snapshot.Routes = models.NewRouteList([]models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+	})
+	if err := snapshot.ComputeChecksum(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, []*models.Snapshot{snapshot}, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		rewrite func(name string, data []byte) (string, []byte)
+		wantErr string
+	}{
+		{
+			name: "modified snapshot",
+			rewrite: func(name string, data []byte) (string, []byte) {
+				return name, bytes.Replace(data, []byte("AS64500"), []byte("AS64999"), 1)
+			},
+			wantErr: "does not match the bundle manifest",
+		},
+		{
+			name: "path traversal",
+			rewrite: func(name string, data []byte) (string, []byte) {
+				if strings.HasPrefix(name, "snapshots/") {
+					return "snapshots/../../evil.json", data
+				}
+				return name, data
+			},
+			wantErr: "unexpected file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := rewriteBundle(t, buf.Bytes(), tt.rewrite)
+			if _, _, err := ReadBundle(bytes.NewReader(tampered)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadBundle() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// rewriteBundle copies a bundle, passing each file through rewrite.
+func rewriteBundle(t *testing.T, bundle []byte, rewrite func(name string, data []byte) (string, []byte)) []byte {
+	t.Helper()
+
+	gr, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name, data := rewrite(header.Name, data)
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,213 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// snapshotCatalogFile indexes the metadata of every stored snapshot, so
+// listings do not read the snapshots themselves.
+const snapshotCatalogFile = "snapshot-catalog.json"
+
+// snapshotCatalog maps snapshot IDs to their catalog entries.
+type snapshotCatalog struct {
+	Snapshots map[string]catalogEntry `json:"snapshots"`
+}
+
+// catalogEntry is the metadata of a stored snapshot.
+type catalogEntry struct {
+	ID                string              `json:"id"`
+	Timestamp         time.Time           `json:"timestamp"`
+	Type              models.SnapshotType `json:"type"`
+	Note              string              `json:"note,omitempty"`
+	Checksum          string              `json:"checksum"`
+	ChecksumAlgorithm string              `json:"checksum_algorithm,omitempty"`
+	Version           int                 `json:"version"`
+	Metadata          map[string]string   `json:"metadata,omitempty"`
+	Tags              []string            `json:"tags,omitempty"`
+	ContentHash       string              `json:"content_hash,omitempty"`
+	AliasOf           string              `json:"alias_of,omitempty"`
+	Routes            int                 `json:"routes"`
+	Contacts          int                 `json:"contacts"`
+}
+
+// newCatalogEntry returns the catalog entry of a snapshot. Counts are taken
+// from the snapshot's data, so an alias record yields zero counts.
+func newCatalogEntry(snapshot *models.Snapshot) catalogEntry {
+	entry := catalogEntry{
+		ID:                snapshot.ID,
+		Timestamp:         snapshot.Timestamp,
+		Type:              snapshot.Type,
+		Note:              snapshot.Note,
+		Checksum:          snapshot.Checksum,
+		ChecksumAlgorithm: snapshot.ChecksumAlgorithm,
+		Version:           snapshot.Version,
+		Metadata:          snapshot.Metadata,
+		Tags:              snapshot.Tags,
+		ContentHash:       snapshot.ContentHash,
+		AliasOf:           snapshot.AliasOf,
+	}
+	if snapshot.Routes != nil {
+		entry.Routes = snapshot.Routes.Count
+	}
+	if snapshot.Contacts != nil {
+		entry.Contacts = snapshot.Contacts.Count
+	}
+	return entry
+}
+
+// snapshot returns the listed form of the entry: its metadata and counts,
+// without routes or contacts.
+func (e catalogEntry) snapshot() models.Snapshot {
+	return models.Snapshot{
+		ID:                e.ID,
+		Timestamp:         e.Timestamp,
+		Type:              e.Type,
+		Note:              e.Note,
+		Checksum:          e.Checksum,
+		ChecksumAlgorithm: e.ChecksumAlgorithm,
+		Version:           e.Version,
+		Metadata:          e.Metadata,
+		Tags:              e.Tags,
+		ContentHash:       e.ContentHash,
+		AliasOf:           e.AliasOf,
+		Counts:            &models.SnapshotCounts{Routes: e.Routes, Contacts: e.Contacts},
+	}
+}
+
+// loadCatalog reads the snapshot catalog. A missing catalog yields an error
+// wrapping fs.ErrNotExist.
+func loadCatalog(ctx context.Context, store documentStore) (*snapshotCatalog, error) {
+	data, err := store.readDocument(ctx, snapshotCatalogFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read snapshot catalog: %w", err)
+	}
+
+	var catalog snapshotCatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot catalog: %w", err)
+	}
+	if catalog.Snapshots == nil {
+		catalog.Snapshots = make(map[string]catalogEntry)
+	}
+	return &catalog, nil
+}
+
+func (c *snapshotCatalog) save(ctx context.Context, store documentStore) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot catalog: %w", err)
+	}
+	if err := store.writeDocument(ctx, snapshotCatalogFile, data); err != nil {
+		return fmt.Errorf("failed to save snapshot catalog: %w", err)
+	}
+	return nil
+}
+
+// updateCatalog applies update to the stored catalog. Without a catalog it
+// does nothing: the next listing rebuilds it from the snapshots.
+func updateCatalog(ctx context.Context, store documentStore, update func(*snapshotCatalog)) error {
+	catalog, err := loadCatalog(ctx, store)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		// An unreadable catalog is rebuilt rather than patched
+		if err := store.removeDocument(ctx, snapshotCatalogFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove snapshot catalog: %w", err)
+		}
+		return nil
+	}
+
+	update(catalog)
+	return catalog.save(ctx, store)
+}
+
+// listSnapshots lists the snapshots stored under names, newest first, from
+// the catalog. Snapshots missing from the catalog, such as those copied in
+// by hand, are read and added, and entries whose document is gone are
+// dropped. A missing or unreadable catalog is rebuilt by reading every
+// snapshot, and saved. Without repair, the listing instead fails with ok
+// false when the catalog is out of date, so the caller can take the write
+// lock and list again with repair.
+func listSnapshots(ctx context.Context, store documentStore, names []string, logger *logrus.Logger, repair bool) ([]models.Snapshot, bool) {
+	catalog, err := loadCatalog(ctx, store)
+	dirty := false
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warnf("Rebuilding snapshot catalog: %v", err)
+		}
+		catalog = &snapshotCatalog{Snapshots: make(map[string]catalogEntry)}
+		dirty = true
+	}
+
+	stored := make(map[string]bool, len(names))
+	var added []string
+	for _, name := range names {
+		id := strings.TrimSuffix(name, ".json")
+		stored[id] = true
+		if _, ok := catalog.Snapshots[id]; !ok {
+			added = append(added, id)
+		}
+	}
+
+	for id := range catalog.Snapshots {
+		if !stored[id] {
+			delete(catalog.Snapshots, id)
+			dirty = true
+		}
+	}
+	if (dirty || len(added) > 0) && !repair {
+		return nil, false
+	}
+
+	for _, id := range added {
+		record, err := readRecord(ctx, store, id)
+		if err != nil {
+			logger.Warnf("Failed to read %s.json: %v", id, err)
+			continue
+		}
+		catalog.Snapshots[id] = newCatalogEntry(record)
+		dirty = true
+	}
+
+	// Alias records carry no data, so they take the counts of their target
+	for _, id := range added {
+		entry, ok := catalog.Snapshots[id]
+		if !ok || entry.AliasOf == "" {
+			continue
+		}
+		if target, ok := catalog.Snapshots[entry.AliasOf]; ok {
+			entry.Routes = target.Routes
+			entry.Contacts = target.Contacts
+			catalog.Snapshots[id] = entry
+		}
+	}
+
+	if dirty {
+		if err := catalog.save(ctx, store); err != nil {
+			logger.Warnf("Failed to update snapshot catalog: %v", err)
+		}
+	}
+
+	snapshots := make([]models.Snapshot, 0, len(catalog.Snapshots))
+	for _, entry := range catalog.Snapshots {
+		snapshots = append(snapshots, entry.snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.After(snapshots[j].Timestamp)
+	})
+	return snapshots, true
+}
@@ -3,10 +3,14 @@ package state
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
 )
 
 // CleanupOptions configures snapshot cleanup behavior.
@@ -22,21 +26,66 @@ type CleanupOptions struct {
 
 	// DryRun if true, only reports what would be deleted without actually deleting
 	DryRun bool
+
+	// Scopes limits cleanup to snapshots captured with one of these filter
+	// scopes; "" names full-account snapshots
+	Scopes []string
+
+	// ExcludeScopes leaves snapshots captured with these filter scopes alone
+	ExcludeScopes []string
 }
 
 // CleanupResult contains the results of a cleanup operation.
 type CleanupResult struct {
-	TotalSnapshots   int      `json:"total_snapshots"`
-	Kept             int      `json:"kept"`
-	Deleted          int      `json:"deleted"`
-	DeletedIDs       []string `json:"deleted_ids,omitempty"`
-	Errors           []string `json:"errors,omitempty"`
-	DryRun           bool     `json:"dry_run"`
+	TotalSnapshots int      `json:"total_snapshots"`
+	Kept           int      `json:"kept"`
+	Deleted        int      `json:"deleted"`
+	DeletedIDs     []string `json:"deleted_ids,omitempty"`
+	Errors         []string `json:"errors,omitempty"`
+	DryRun         bool     `json:"dry_run"`
+
+	// ProtectedIDs are tagged snapshots the retention policy would have
+	// deleted, and which were kept
+	ProtectedIDs []string `json:"protected_ids,omitempty"`
+
+	// BytesReclaimed is the on-disk size of the deleted snapshots
+	BytesReclaimed int64 `json:"bytes_reclaimed"`
+
+	// ByType breaks the counts down per snapshot type
+	ByType map[models.SnapshotType]*CleanupTypeSummary `json:"by_type,omitempty"`
+
+	// OldestKept is the oldest snapshot that survives the cleanup
+	OldestKept *CleanupEntry `json:"oldest_kept,omitempty"`
+
+	// DeletedSnapshots describes each deleted snapshot, newest first
+	DeletedSnapshots []CleanupEntry `json:"deleted_snapshots,omitempty"`
+}
+
+// CleanupTypeSummary contains cleanup counts for one snapshot type.
+type CleanupTypeSummary struct {
+	Total          int   `json:"total"`
+	Kept           int   `json:"kept"`
+	Deleted        int   `json:"deleted"`
+	BytesReclaimed int64 `json:"bytes_reclaimed"`
+}
+
+// CleanupEntry describes a single snapshot in a cleanup report.
+type CleanupEntry struct {
+	ID        string              `json:"id"`
+	Type      models.SnapshotType `json:"type"`
+	Timestamp time.Time           `json:"timestamp"`
+	Size      int64               `json:"size"`
 }
 
 // Cleanup removes old snapshots based on retention policies.
 func (m *FileManager) Cleanup(ctx context.Context, options CleanupOptions) (*CleanupResult, error) {
-	m.logger.Info("Starting snapshot cleanup")
+	return runCleanup(ctx, m, m.logger, options, m.snapshotSize)
+}
+
+// runCleanup applies options to mgr's snapshots. size reports the stored
+// size of a snapshot for the report.
+func runCleanup(ctx context.Context, mgr Manager, logger *logrus.Logger, options CleanupOptions, size func(id string) int64) (*CleanupResult, error) {
+	logger.Info("Starting snapshot cleanup")
 
 	result := &CleanupResult{
 		DeletedIDs: make([]string, 0),
@@ -45,11 +94,12 @@ func (m *FileManager) Cleanup(ctx context.Context, options CleanupOptions) (*Cle
 	}
 
 	// List all snapshots
-	snapshots, err := m.ListSnapshots(ctx)
+	snapshots, err := mgr.ListSnapshots(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list snapshots: %w", err)
 	}
 
+	snapshots = filterScopes(snapshots, options)
 	result.TotalSnapshots = len(snapshots)
 
 	// Sort snapshots by timestamp (newest first)
@@ -61,38 +111,119 @@ func (m *FileManager) Cleanup(ctx context.Context, options CleanupOptions) (*Cle
 	var toDelete []string
 
 	if len(options.KeepByType) > 0 {
-		toDelete = m.cleanupByType(snapshots, options)
+		toDelete = cleanupByType(snapshots, options)
 	} else if options.KeepCount > 0 {
-		toDelete = m.cleanupByCount(snapshots, options.KeepCount)
+		toDelete = cleanupByCount(snapshots, options.KeepCount)
 	} else if !options.KeepAfter.IsZero() {
-		toDelete = m.cleanupByAge(snapshots, options.KeepAfter)
+		toDelete = cleanupByAge(snapshots, options.KeepAfter)
 	} else {
 		return nil, fmt.Errorf("no cleanup criteria specified")
 	}
 
+	// Tagged snapshots are never deleted
+	protected := tagged(snapshots)
+	kept := toDelete[:0]
+	for _, id := range toDelete {
+		if protected[id] {
+			result.ProtectedIDs = append(result.ProtectedIDs, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	toDelete = kept
+
 	result.Deleted = len(toDelete)
 	result.Kept = result.TotalSnapshots - result.Deleted
 	result.DeletedIDs = toDelete
+	summarizeCleanup(result, snapshots, toDelete, size)
 
 	// Delete snapshots if not a dry run
 	if !options.DryRun {
 		for _, id := range toDelete {
-			if err := m.DeleteSnapshot(ctx, id); err != nil {
+			if err := mgr.DeleteSnapshot(ctx, id); err != nil {
 				errMsg := fmt.Sprintf("failed to delete snapshot %s: %v", id, err)
 				result.Errors = append(result.Errors, errMsg)
-				m.logger.Warn(errMsg)
+				logger.Warn(errMsg)
 			}
 		}
 	}
 
-	m.logger.Infof("Cleanup completed: kept %d, deleted %d (dry_run=%v)",
+	logger.Infof("Cleanup completed: kept %d, deleted %d (dry_run=%v)",
 		result.Kept, result.Deleted, result.DryRun)
 
 	return result, nil
 }
 
+// filterScopes drops the snapshots options.Scopes and
+// options.ExcludeScopes put out of reach.
+func filterScopes(snapshots []models.Snapshot, options CleanupOptions) []models.Snapshot {
+	if len(options.Scopes) == 0 && len(options.ExcludeScopes) == 0 {
+		return snapshots
+	}
+
+	kept := snapshots[:0]
+	for _, snap := range snapshots {
+		if len(options.Scopes) > 0 && !slices.Contains(options.Scopes, snap.Scope()) {
+			continue
+		}
+		if slices.Contains(options.ExcludeScopes, snap.Scope()) {
+			continue
+		}
+		kept = append(kept, snap)
+	}
+	return kept
+}
+
+// summarizeCleanup fills in the per-type counts, sizes, and oldest kept
+// snapshot. snapshots must be sorted newest first.
+func summarizeCleanup(result *CleanupResult, snapshots []models.Snapshot, toDelete []string, size func(id string) int64) {
+	deleted := make(map[string]bool, len(toDelete))
+	for _, id := range toDelete {
+		deleted[id] = true
+	}
+
+	result.ByType = make(map[models.SnapshotType]*CleanupTypeSummary)
+	result.DeletedSnapshots = make([]CleanupEntry, 0, len(toDelete))
+
+	for _, snap := range snapshots {
+		summary, ok := result.ByType[snap.Type]
+		if !ok {
+			summary = &CleanupTypeSummary{}
+			result.ByType[snap.Type] = summary
+		}
+		summary.Total++
+
+		entry := CleanupEntry{
+			ID:        snap.ID,
+			Type:      snap.Type,
+			Timestamp: snap.Timestamp,
+			Size:      size(snap.ID),
+		}
+
+		if deleted[snap.ID] {
+			summary.Deleted++
+			summary.BytesReclaimed += entry.Size
+			result.BytesReclaimed += entry.Size
+			result.DeletedSnapshots = append(result.DeletedSnapshots, entry)
+			continue
+		}
+
+		summary.Kept++
+		result.OldestKept = &entry
+	}
+}
+
+// snapshotSize returns the on-disk size of a snapshot, or 0 if unknown.
+func (m *FileManager) snapshotSize(id string) int64 {
+	info, err := os.Stat(filepath.Join(m.stateDir, id+".json"))
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
 // cleanupByCount keeps the N most recent snapshots.
-func (m *FileManager) cleanupByCount(snapshots []models.Snapshot, keepCount int) []string {
+func cleanupByCount(snapshots []models.Snapshot, keepCount int) []string {
 	if keepCount >= len(snapshots) {
 		return []string{}
 	}
@@ -106,7 +237,7 @@ func (m *FileManager) cleanupByCount(snapshots []models.Snapshot, keepCount int)
 }
 
 // cleanupByAge keeps snapshots after a certain date.
-func (m *FileManager) cleanupByAge(snapshots []models.Snapshot, keepAfter time.Time) []string {
+func cleanupByAge(snapshots []models.Snapshot, keepAfter time.Time) []string {
 	toDelete := make([]string, 0)
 
 	for _, snap := range snapshots {
@@ -119,7 +250,7 @@ func (m *FileManager) cleanupByAge(snapshots []models.Snapshot, keepAfter time.T
 }
 
 // cleanupByType keeps different numbers of snapshots per type.
-func (m *FileManager) cleanupByType(snapshots []models.Snapshot, options CleanupOptions) []string {
+func cleanupByType(snapshots []models.Snapshot, options CleanupOptions) []string {
 	// Group snapshots by type
 	byType := make(map[models.SnapshotType][]models.Snapshot)
 	for _, snap := range snapshots {
@@ -165,18 +296,21 @@ func (m *FileManager) CleanupByCount(ctx context.Context, keepCount int, dryRun
 	return m.Cleanup(ctx, options)
 }
 
+// DefaultRetention is the number of snapshots kept per type by AutoCleanup.
+var DefaultRetention = map[models.SnapshotType]int{
+	models.SnapshotTypeRoute:   30,
+	models.SnapshotTypeContact: 10,
+	models.SnapshotTypeFull:    5,
+}
+
 // AutoCleanup runs cleanup based on default policies.
 // Keeps 30 route snapshots, 10 contact snapshots, and 5 full snapshots.
 func (m *FileManager) AutoCleanup(ctx context.Context, dryRun bool) (*CleanupResult, error) {
 	m.logger.Info("Running auto-cleanup with default policies")
 
 	options := CleanupOptions{
-		KeepByType: map[models.SnapshotType]int{
-			models.SnapshotTypeRoute:   30,
-			models.SnapshotTypeContact: 10,
-			models.SnapshotTypeFull:    5,
-		},
-		DryRun: dryRun,
+		KeepByType: DefaultRetention,
+		DryRun:     dryRun,
 	}
 
 	return m.Cleanup(ctx, options)
@@ -0,0 +1,130 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCleanupReport(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	mgr, err := NewFileManager(t.TempDir(), logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer mgr.Close()
+
+	ctx := context.Background()
+
+	var ids []string
+	for _, snapshotType := range []models.SnapshotType{
+		models.SnapshotTypeRoute, models.SnapshotTypeContact, models.SnapshotTypeRoute, models.SnapshotTypeRoute,
+	} {
+		snapshot := models.NewSnapshot(snapshotType, "cleanup report")
+		snapshot.Routes = models.NewRouteList(nil)
+		snapshot.Contacts = models.NewContactList(nil)
+		if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+			t.Fatalf("SaveSnapshot() failed: %v", err)
+		}
+		ids = append(ids, snapshot.ID)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	result, err := mgr.Cleanup(ctx, CleanupOptions{
+		KeepByType: map[models.SnapshotType]int{models.SnapshotTypeRoute: 1, models.SnapshotTypeContact: 1},
+		DryRun:     true,
+	})
+	if err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+
+	if result.Deleted != 2 || result.Kept != 2 {
+		t.Errorf("Expected 2 deleted and 2 kept, got %d and %d", result.Deleted, result.Kept)
+	}
+
+	route := result.ByType[models.SnapshotTypeRoute]
+	if route == nil || route.Total != 3 || route.Deleted != 2 || route.Kept != 1 {
+		t.Errorf("Unexpected route summary: %+v", route)
+	}
+	contact := result.ByType[models.SnapshotTypeContact]
+	if contact == nil || contact.Deleted != 0 || contact.Kept != 1 {
+		t.Errorf("Unexpected contact summary: %+v", contact)
+	}
+
+	if result.BytesReclaimed <= 0 || result.BytesReclaimed != route.BytesReclaimed {
+		t.Errorf("Expected reclaimed bytes to match route deletions, got %d and %d", result.BytesReclaimed, route.BytesReclaimed)
+	}
+
+	// The contact snapshot is the oldest one that survives
+	if result.OldestKept == nil || result.OldestKept.ID != ids[1] {
+		t.Errorf("Expected oldest kept %s, got %+v", ids[1], result.OldestKept)
+	}
+
+	if len(result.DeletedSnapshots) != 2 || result.DeletedSnapshots[0].ID != ids[2] {
+		t.Errorf("Expected deleted snapshots newest first, got %+v", result.DeletedSnapshots)
+	}
+
+	// Dry run leaves everything in place
+	snapshots, err := mgr.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListSnapshots() failed: %v", err)
+	}
+	if len(snapshots) != 4 {
+		t.Errorf("Expected dry run to keep all 4 snapshots, found %d", len(snapshots))
+	}
+}
+
+func TestCleanupScopes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	mgr, err := NewFileManager(t.TempDir(), logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer mgr.Close()
+
+	ctx := context.Background()
+	target := map[string]string{"mnt-by": "MAINT-EXAMPLE"}
+	scope := models.FilterScope(target)
+
+	for i := 0; i < 3; i++ {
+		for _, filters := range []map[string]string{nil, target} {
+			snapshot := models.NewScopedSnapshot(models.SnapshotTypeRoute, "cleanup scopes", filters)
+			snapshot.Routes = models.NewRouteList(nil)
+			if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+				t.Fatalf("SaveSnapshot() failed: %v", err)
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	result, err := mgr.Cleanup(ctx, CleanupOptions{KeepCount: 1, Scopes: []string{scope}, DryRun: true})
+	if err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+	if result.TotalSnapshots != 3 || result.Deleted != 2 {
+		t.Errorf("Expected 2 of 3 scoped snapshots deleted, got %d of %d", result.Deleted, result.TotalSnapshots)
+	}
+
+	result, err = mgr.Cleanup(ctx, CleanupOptions{KeepCount: 1, ExcludeScopes: []string{scope}, DryRun: true})
+	if err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+	if result.TotalSnapshots != 3 || result.Deleted != 2 {
+		t.Errorf("Expected 2 of 3 full snapshots deleted, got %d of %d", result.Deleted, result.TotalSnapshots)
+	}
+
+	matches, err := mgr.QuerySnapshots(ctx, SnapshotQuery{Scope: scope})
+	if err != nil {
+		t.Fatalf("QuerySnapshots() failed: %v", err)
+	}
+	if len(matches) != 3 || matches[0].Scope() != scope {
+		t.Errorf("Expected 3 snapshots with scope %q, got %d", scope, len(matches))
+	}
+}
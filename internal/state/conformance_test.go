@@ -0,0 +1,99 @@
+package state_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sync"
+	"testing"
+
+	"github.com/bss/radb-client/internal/state"
+	"github.com/bss/radb-client/internal/state/statetest"
+	"github.com/sirupsen/logrus"
+)
+
+func TestFileManagerConformance(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	statetest.RunManagerTests(t, func(t *testing.T) state.Manager {
+		mgr, err := state.NewFileManager(t.TempDir(), logger)
+		if err != nil {
+			t.Fatalf("NewFileManager() failed: %v", err)
+		}
+		t.Cleanup(func() { mgr.Close() })
+		return mgr
+	})
+}
+
+// memoryBackend is an in-memory state.Backend.
+type memoryBackend struct {
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+func (b *memoryBackend) Put(ctx context.Context, name string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.docs[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (b *memoryBackend) Get(ctx context.Context, name string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.docs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return data, nil
+}
+
+func (b *memoryBackend) Delete(ctx context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.docs[name]; !ok {
+		return fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	delete(b.docs, name)
+	return nil
+}
+
+func (b *memoryBackend) List(ctx context.Context) (map[string]int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sizes := make(map[string]int64, len(b.docs))
+	for name, data := range b.docs {
+		sizes[name] = int64(len(data))
+	}
+	return sizes, nil
+}
+
+func TestBackendManagerConformance(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	statetest.RunManagerTests(t, func(t *testing.T) state.Manager {
+		return state.NewBackendManager(&memoryBackend{docs: make(map[string][]byte)}, logger)
+	})
+}
+
+func TestBackendManagerSkipsStateFiles(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	backend := &memoryBackend{docs: map[string][]byte{
+		"daemon-status.json":   []byte(`{"pid": 1}`),
+		"journals/bulk-1.json": []byte(`{}`),
+		"route-1.json.tmp":     []byte(`{}`),
+	}}
+	mgr := state.NewBackendManager(backend, logger)
+
+	snapshots, err := mgr.ListSnapshots(context.Background())
+	if err != nil {
+		t.Fatalf("ListSnapshots() failed: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots, got %d", len(snapshots))
+	}
+}
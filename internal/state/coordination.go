@@ -0,0 +1,102 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/flock"
+)
+
+// Coordination files within the state directory.
+const (
+	// daemonLockFile is held exclusively by a running daemon
+	daemonLockFile = "daemon.lock"
+
+	// daemonSocketFile is the daemon's local socket for CLI commands
+	daemonSocketFile = "daemon.sock"
+
+	// daemonPIDFile holds the process ID of the daemon holding the lock
+	daemonPIDFile = "daemon.pid"
+)
+
+// ErrDaemonRunning is returned by LockDaemon when another daemon holds the
+// state directory.
+var ErrDaemonRunning = errors.New("another daemon is running on this state directory")
+
+// ErrDaemonNotRunning is returned by DaemonPID when no daemon holds the
+// state directory.
+var ErrDaemonNotRunning = errors.New("no daemon is running on this state directory")
+
+// LockDaemon takes the advisory lock marking a daemon as active on the state
+// directory, records the process ID in the PID file, and returns the
+// function that releases both. The lock is released by the operating system
+// if the process dies, so unlike the heartbeat it never reports a crashed
+// daemon as running; a PID file left behind is ignored.
+func LockDaemon(stateDir string) (func(), error) {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	lock := flock.New(filepath.Join(stateDir, daemonLockFile))
+	locked, err := lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire daemon lock: %w", err)
+	}
+	if !locked {
+		lock.Close()
+		if pid, err := DaemonPID(stateDir); err == nil {
+			return nil, fmt.Errorf("%w (pid %d)", ErrDaemonRunning, pid)
+		}
+		return nil, ErrDaemonRunning
+	}
+
+	pidPath := filepath.Join(stateDir, daemonPIDFile)
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("failed to write daemon PID file: %w", err)
+	}
+	return func() {
+		// Removed before unlocking, so it never outlives the lock
+		os.Remove(pidPath)
+		lock.Close()
+	}, nil
+}
+
+// DaemonPID returns the process ID of the daemon holding the state
+// directory, or ErrDaemonNotRunning.
+func DaemonPID(stateDir string) (int, error) {
+	if !DaemonRunning(stateDir) {
+		return 0, ErrDaemonNotRunning
+	}
+	data, err := os.ReadFile(filepath.Join(stateDir, daemonPIDFile))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read daemon PID file: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid daemon PID file %q", strings.TrimSpace(string(data)))
+	}
+	return pid, nil
+}
+
+// DaemonRunning reports whether a daemon holds the state directory's daemon lock.
+func DaemonRunning(stateDir string) bool {
+	path := filepath.Join(stateDir, daemonLockFile)
+	if _, err := os.Stat(path); err != nil {
+		return false
+	}
+
+	lock := flock.New(path)
+	defer lock.Close()
+	locked, err := lock.TryRLock()
+	return err == nil && !locked
+}
+
+// DaemonSocket returns the path of the daemon's local socket in the state directory.
+func DaemonSocket(stateDir string) string {
+	return filepath.Join(stateDir, daemonSocketFile)
+}
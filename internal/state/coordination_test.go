@@ -0,0 +1,52 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLockDaemon(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if DaemonRunning(tmpDir) {
+		t.Fatal("DaemonRunning() = true before any daemon started")
+	}
+
+	unlock, err := LockDaemon(tmpDir)
+	if err != nil {
+		t.Fatalf("LockDaemon() failed: %v", err)
+	}
+	if !DaemonRunning(tmpDir) {
+		t.Error("DaemonRunning() = false while the daemon lock is held")
+	}
+	if pid, err := DaemonPID(tmpDir); err != nil || pid != os.Getpid() {
+		t.Errorf("DaemonPID() = %d, %v; want %d", pid, err, os.Getpid())
+	}
+	_, err = LockDaemon(tmpDir)
+	if !errors.Is(err, ErrDaemonRunning) {
+		t.Errorf("second LockDaemon() error = %v, want ErrDaemonRunning", err)
+	} else if want := fmt.Sprintf("(pid %d)", os.Getpid()); !strings.Contains(err.Error(), want) {
+		t.Errorf("second LockDaemon() error = %q, want it to name %s", err, want)
+	}
+
+	unlock()
+	if DaemonRunning(tmpDir) {
+		t.Error("DaemonRunning() = true after the daemon lock was released")
+	}
+	if _, err := DaemonPID(tmpDir); !errors.Is(err, ErrDaemonNotRunning) {
+		t.Errorf("DaemonPID() after release error = %v, want ErrDaemonNotRunning", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, daemonPIDFile)); !os.IsNotExist(err) {
+		t.Errorf("PID file remains after release: %v", err)
+	}
+
+	unlock, err = LockDaemon(tmpDir)
+	if err != nil {
+		t.Fatalf("LockDaemon() after release failed: %v", err)
+	}
+	unlock()
+}
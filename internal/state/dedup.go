@@ -0,0 +1,340 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// dedupIndexFile records which snapshot holds the data that later, unchanged
+// snapshots alias.
+const dedupIndexFile = "dedup-index.json"
+
+// documentStore reads and writes named documents in a manager's storage.
+// A missing document yields an error wrapping fs.ErrNotExist.
+type documentStore interface {
+	readDocument(ctx context.Context, name string) ([]byte, error)
+	writeDocument(ctx context.Context, name string, data []byte) error
+	removeDocument(ctx context.Context, name string) error
+}
+
+// dedupIndex tracks the content of the latest snapshot of each type and
+// scope, and the aliases pointing at each data-holding snapshot.
+type dedupIndex struct {
+	Latest  map[string]dedupEntry `json:"latest"`
+	Aliases map[string][]string   `json:"aliases,omitempty"` // Target ID -> alias IDs, oldest first
+}
+
+// dedupEntry is the data-holding snapshot for the latest content of a type
+// and scope.
+type dedupEntry struct {
+	Target      string `json:"target"`
+	ContentHash string `json:"content_hash"`
+	Checksum    string `json:"checksum"`
+}
+
+// dedupKey groups snapshots that may alias one another.
+func dedupKey(snapshot *models.Snapshot) string {
+	return string(snapshot.Type) + "|" + snapshot.Scope()
+}
+
+func loadDedupIndex(ctx context.Context, store documentStore) (*dedupIndex, error) {
+	index := &dedupIndex{
+		Latest:  make(map[string]dedupEntry),
+		Aliases: make(map[string][]string),
+	}
+
+	data, err := store.readDocument(ctx, dedupIndexFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dedup index: %w", err)
+	}
+
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dedup index: %w", err)
+	}
+	if index.Latest == nil {
+		index.Latest = make(map[string]dedupEntry)
+	}
+	if index.Aliases == nil {
+		index.Aliases = make(map[string][]string)
+	}
+	return index, nil
+}
+
+func (idx *dedupIndex) save(ctx context.Context, store documentStore) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dedup index: %w", err)
+	}
+	if err := store.writeDocument(ctx, dedupIndexFile, data); err != nil {
+		return fmt.Errorf("failed to save dedup index: %w", err)
+	}
+	return nil
+}
+
+// storeSnapshot writes a checksummed snapshot. When its content matches the
+// latest snapshot of the same type and scope, only a small alias record is
+// written and snapshot.AliasOf names the snapshot holding the data. It
+// returns the number of bytes written.
+func storeSnapshot(ctx context.Context, store documentStore, snapshot *models.Snapshot) (int, error) {
+	if err := snapshot.ComputeContentHash(); err != nil {
+		return 0, err
+	}
+
+	index, err := loadDedupIndex(ctx, store)
+	if err != nil {
+		return 0, err
+	}
+
+	// A rewritten snapshot is no longer an alias of what it aliased before
+	for target, aliases := range index.Aliases {
+		if aliases = without(aliases, snapshot.ID); len(aliases) > 0 {
+			index.Aliases[target] = aliases
+		} else {
+			delete(index.Aliases, target)
+		}
+	}
+
+	// A snapshot that others alias must keep its data, even when rewritten
+	key := dedupKey(snapshot)
+	record := snapshot
+	snapshot.AliasOf = ""
+	if entry, ok := index.Latest[key]; ok && entry.ContentHash == snapshot.ContentHash && entry.Target != snapshot.ID && len(index.Aliases[snapshot.ID]) == 0 {
+		snapshot.AliasOf = entry.Target
+		record = aliasRecord(snapshot, entry.Target, entry.Checksum)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := store.writeDocument(ctx, snapshot.ID+".json", data); err != nil {
+		return 0, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	if snapshot.AliasOf != "" {
+		index.Aliases[snapshot.AliasOf] = append(index.Aliases[snapshot.AliasOf], snapshot.ID)
+	} else {
+		index.Latest[key] = dedupEntry{
+			Target:      snapshot.ID,
+			ContentHash: snapshot.ContentHash,
+			Checksum:    snapshot.Checksum,
+		}
+	}
+	if err := index.save(ctx, store); err != nil {
+		return 0, err
+	}
+
+	entry := newCatalogEntry(snapshot)
+	if err := updateCatalog(ctx, store, func(catalog *snapshotCatalog) {
+		catalog.Snapshots[snapshot.ID] = entry
+	}); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// aliasRecord returns the stored form of a snapshot whose data lives in
+// target: its own identity and metadata, and target's checksum.
+func aliasRecord(snapshot *models.Snapshot, target, checksum string) *models.Snapshot {
+	return &models.Snapshot{
+		ID:          snapshot.ID,
+		Timestamp:   snapshot.Timestamp,
+		Type:        snapshot.Type,
+		Note:        snapshot.Note,
+		Checksum:    checksum,
+		Version:     snapshot.Version,
+		Metadata:    snapshot.Metadata,
+		Tags:        snapshot.Tags,
+		ContentHash: snapshot.ContentHash,
+		AliasOf:     target,
+	}
+}
+
+// readSnapshot reads a snapshot and, if it is an alias, fills in its data
+// from the target. A missing snapshot yields an error wrapping
+// fs.ErrNotExist; the checksum is not verified.
+func readSnapshot(ctx context.Context, store documentStore, id string) (*models.Snapshot, error) {
+	for attempt := 1; ; attempt++ {
+		data, err := store.readDocument(ctx, id+".json")
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+
+		var snapshot models.Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+		}
+
+		if snapshot.AliasOf == "" {
+			return &snapshot, nil
+		}
+
+		data, err = store.readDocument(ctx, snapshot.AliasOf+".json")
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				// Readers do not lock the target, which may have been
+				// deleted after moving its data here, so read the record again
+				if attempt < 2 {
+					continue
+				}
+				return nil, fmt.Errorf("snapshot %s aliases missing snapshot %s", id, snapshot.AliasOf)
+			}
+			return nil, fmt.Errorf("failed to read snapshot %s: %w", snapshot.AliasOf, err)
+		}
+
+		var target models.Snapshot
+		if err := json.Unmarshal(data, &target); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal snapshot %s: %w", snapshot.AliasOf, err)
+		}
+
+		snapshot.Routes = target.Routes
+		snapshot.Contacts = target.Contacts
+		snapshot.ChecksumAlgorithm = target.ChecksumAlgorithm
+		snapshot.ObjectChecksums = target.ObjectChecksums
+		return &snapshot, nil
+	}
+}
+
+// removeSnapshot deletes a snapshot. Deleting a snapshot that others alias
+// moves its data to the newest alias, which the remaining aliases then
+// point at, so retention never loses the data of a kept snapshot.
+func removeSnapshot(ctx context.Context, store documentStore, id string) error {
+	data, err := store.readDocument(ctx, id+".json")
+	if err != nil {
+		return err
+	}
+
+	var snapshot models.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	index, err := loadDedupIndex(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	if snapshot.AliasOf != "" {
+		index.Aliases[snapshot.AliasOf] = without(index.Aliases[snapshot.AliasOf], id)
+		if len(index.Aliases[snapshot.AliasOf]) == 0 {
+			delete(index.Aliases, snapshot.AliasOf)
+		}
+	} else if aliases := index.Aliases[id]; len(aliases) > 0 {
+		heir := aliases[len(aliases)-1]
+		if err := promoteAlias(ctx, store, &snapshot, heir, aliases[:len(aliases)-1]); err != nil {
+			return err
+		}
+		delete(index.Aliases, id)
+		if len(aliases) > 1 {
+			index.Aliases[heir] = aliases[:len(aliases)-1]
+		}
+		for key, entry := range index.Latest {
+			if entry.Target == id {
+				entry.Target = heir
+				index.Latest[key] = entry
+			}
+		}
+	} else {
+		for key, entry := range index.Latest {
+			if entry.Target == id {
+				delete(index.Latest, key)
+			}
+		}
+	}
+
+	if err := store.removeDocument(ctx, id+".json"); err != nil {
+		return err
+	}
+
+	if err := index.save(ctx, store); err != nil {
+		return err
+	}
+
+	return updateCatalog(ctx, store, func(catalog *snapshotCatalog) {
+		delete(catalog.Snapshots, id)
+	})
+}
+
+// promoteAlias gives heir the data of target and repoints the other aliases
+// at heir.
+func promoteAlias(ctx context.Context, store documentStore, target *models.Snapshot, heir string, others []string) error {
+	promoted, err := readRecord(ctx, store, heir)
+	if err != nil {
+		return err
+	}
+	promoted.AliasOf = ""
+	promoted.Routes = target.Routes
+	promoted.Contacts = target.Contacts
+	promoted.ChecksumAlgorithm = target.ChecksumAlgorithm
+	promoted.ObjectChecksums = target.ObjectChecksums
+	if err := writeRecord(ctx, store, promoted); err != nil {
+		return err
+	}
+
+	for _, id := range others {
+		alias, err := readRecord(ctx, store, id)
+		if err != nil {
+			return err
+		}
+		alias.AliasOf = heir
+		if err := writeRecord(ctx, store, alias); err != nil {
+			return err
+		}
+	}
+
+	return updateCatalog(ctx, store, func(catalog *snapshotCatalog) {
+		catalog.Snapshots[heir] = newCatalogEntry(promoted)
+		for _, id := range others {
+			if entry, ok := catalog.Snapshots[id]; ok {
+				entry.AliasOf = heir
+				catalog.Snapshots[id] = entry
+			}
+		}
+	})
+}
+
+func readRecord(ctx context.Context, store documentStore, id string) (*models.Snapshot, error) {
+	data, err := store.readDocument(ctx, id+".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", id, err)
+	}
+	var snapshot models.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot %s: %w", id, err)
+	}
+	return &snapshot, nil
+}
+
+func writeRecord(ctx context.Context, store documentStore, snapshot *models.Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot %s: %w", snapshot.ID, err)
+	}
+	if err := store.writeDocument(ctx, snapshot.ID+".json", data); err != nil {
+		return fmt.Errorf("failed to save snapshot %s: %w", snapshot.ID, err)
+	}
+	return nil
+}
+
+// without returns ids with id removed.
+func without(ids []string, id string) []string {
+	kept := ids[:0]
+	for _, other := range ids {
+		if other != id {
+			kept = append(kept, other)
+		}
+	}
+	return kept
+}
@@ -5,20 +5,32 @@ import (
 	"fmt"
 
 	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/tracing"
 )
 
 // ComputeDiff calculates the differences between two snapshots using an O(n) algorithm.
 // It uses hash maps for efficient comparison and detects added, removed, and modified items.
 func ComputeDiff(ctx context.Context, from, to *models.Snapshot) (*models.DiffResult, error) {
+	return ComputeDiffWithOptions(ctx, from, to, DiffOptions{})
+}
+
+// ComputeDiffWithOptions calculates the differences between two snapshots,
+// counting only the differences that matter under opts as modifications.
+func ComputeDiffWithOptions(ctx context.Context, from, to *models.Snapshot, opts DiffOptions) (*models.DiffResult, error) {
 	if from == nil || to == nil {
 		return nil, fmt.Errorf("both snapshots must be non-nil")
 	}
 
+	_, span := tracing.Start(ctx, "state.ComputeDiff",
+		tracing.String("snapshot.from", from.ID),
+		tracing.String("snapshot.to", to.ID))
+	defer span.End()
+
 	result := models.NewDiffResult()
 
 	// Compare routes if present in both snapshots
 	if from.Routes != nil && to.Routes != nil {
-		routeDiff := compareRoutes(from.Routes, to.Routes)
+		routeDiff := compareRoutes(from.Routes, to.Routes, opts)
 		result.Added = append(result.Added, routeDiff.Added...)
 		result.Removed = append(result.Removed, routeDiff.Removed...)
 		result.Modified = append(result.Modified, routeDiff.Modified...)
@@ -36,7 +48,7 @@ func ComputeDiff(ctx context.Context, from, to *models.Snapshot) (*models.DiffRe
 
 	// Compare contacts if present in both snapshots
 	if from.Contacts != nil && to.Contacts != nil {
-		contactDiff := compareContacts(from.Contacts, to.Contacts)
+		contactDiff := compareContacts(from.Contacts, to.Contacts, opts)
 		result.Added = append(result.Added, contactDiff.Added...)
 		result.Removed = append(result.Removed, contactDiff.Removed...)
 		result.Modified = append(result.Modified, contactDiff.Modified...)
@@ -54,12 +66,16 @@ func ComputeDiff(ctx context.Context, from, to *models.Snapshot) (*models.DiffRe
 
 	// Compute summary statistics
 	result.ComputeSummary()
+	span.SetAttributes(
+		tracing.Int("diff.added", len(result.Added)),
+		tracing.Int("diff.removed", len(result.Removed)),
+		tracing.Int("diff.modified", len(result.Modified)))
 
 	return result, nil
 }
 
 // compareRoutes performs an O(n) comparison of two route lists.
-func compareRoutes(from, to *models.RouteList) *models.DiffResult {
+func compareRoutes(from, to *models.RouteList, opts DiffOptions) *models.DiffResult {
 	result := models.NewDiffResult()
 
 	// Build hash maps for O(1) lookup
@@ -72,6 +88,10 @@ func compareRoutes(from, to *models.RouteList) *models.DiffResult {
 		if !existsInFrom {
 			// Route was added
 			result.Added = append(result.Added, toRoute)
+		} else if !opts.IsZero() {
+			if modified, ok := opts.modifiedItem("route", id, fromRoute, toRoute, routeDiffFields); ok {
+				result.Modified = append(result.Modified, modified)
+			}
 		} else {
 			// Check if route was modified
 			if !routesEqual(fromRoute, toRoute) {
@@ -100,7 +120,7 @@ func compareRoutes(from, to *models.RouteList) *models.DiffResult {
 }
 
 // compareContacts performs an O(n) comparison of two contact lists.
-func compareContacts(from, to *models.ContactList) *models.DiffResult {
+func compareContacts(from, to *models.ContactList, opts DiffOptions) *models.DiffResult {
 	result := models.NewDiffResult()
 
 	// Build hash maps for O(1) lookup
@@ -113,6 +133,10 @@ func compareContacts(from, to *models.ContactList) *models.DiffResult {
 		if !existsInFrom {
 			// Contact was added
 			result.Added = append(result.Added, toContact)
+		} else if !opts.IsZero() {
+			if modified, ok := opts.modifiedItem("contact", id, fromContact, toContact, contactDiffFields); ok {
+				result.Modified = append(result.Modified, modified)
+			}
 		} else {
 			// Check if contact was modified
 			if !contactsEqual(fromContact, toContact) {
@@ -203,9 +227,9 @@ func DiffToChangeSet(diff *models.DiffResult, fromID, toID string) *models.Chang
 	// Convert added items to changes
 	for _, item := range diff.Added {
 		change := models.Change{
-			Type:       models.ChangeTypeAdded,
-			Timestamp:  cs.Timestamp,
-			After:      item,
+			Type:      models.ChangeTypeAdded,
+			Timestamp: cs.Timestamp,
+			After:     item,
 		}
 
 		// Determine object type and ID
@@ -224,9 +248,9 @@ func DiffToChangeSet(diff *models.DiffResult, fromID, toID string) *models.Chang
 	// Convert removed items to changes
 	for _, item := range diff.Removed {
 		change := models.Change{
-			Type:       models.ChangeTypeRemoved,
-			Timestamp:  cs.Timestamp,
-			Before:     item,
+			Type:      models.ChangeTypeRemoved,
+			Timestamp: cs.Timestamp,
+			Before:    item,
 		}
 
 		// Determine object type and ID
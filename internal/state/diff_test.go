@@ -2,6 +2,7 @@ package state
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -139,3 +140,151 @@ func TestDiffToChangeSet(t *testing.T) {
 		t.Errorf("Expected 1 added in summary")
 	}
 }
+
+func TestComputeDiffWithOptions(t *testing.T) {
+	ctx := context.Background()
+
+	route := models.RouteObject{
+		Route:   "192.0.2.0/24",
+		Origin:  "AS64496",
+		Descr:   []string{"Example", "Customer"},
+		MntBy:   []string{"MAINT-A", "MAINT-B"},
+		Remarks: []string{"Reviewed"},
+		Source:  "RADB",
+	}
+	cosmetic := route
+	cosmetic.Descr = []string{"Customer", "Example"}
+	cosmetic.MntBy = []string{"maint-b", "maint-a"}
+	cosmetic.Remarks = []string{"Reviewed 2024"}
+
+	snapshot := func(id string, routes ...models.RouteObject) *models.Snapshot {
+		return &models.Snapshot{
+			ID:     id,
+			Type:   models.SnapshotTypeRoute,
+			Routes: &models.RouteList{Routes: routes, Count: len(routes)},
+		}
+	}
+	from := snapshot("from", route)
+
+	opts := DiffOptions{
+		IgnoreFields:        []string{"remarks"},
+		UnorderedListFields: []string{"descr", "mnt-by"},
+		IgnoreCase:          true,
+	}
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+
+	diff, err := ComputeDiffWithOptions(ctx, from, snapshot("to", cosmetic), opts)
+	if err != nil {
+		t.Fatalf("ComputeDiffWithOptions() failed: %v", err)
+	}
+	if len(diff.Modified) != 0 {
+		t.Errorf("Expected cosmetic changes to be ignored, got %+v", diff.Modified)
+	}
+
+	// Without the options every cosmetic change counts
+	diff, err = ComputeDiff(ctx, from, snapshot("to", cosmetic))
+	if err != nil {
+		t.Fatalf("ComputeDiff() failed: %v", err)
+	}
+	if len(diff.Modified) != 1 || len(diff.Modified[0].FieldChanges) != 3 {
+		t.Errorf("Expected 1 route with 3 field changes, got %+v", diff.Modified)
+	}
+
+	// Field changes are limited to the fields that differ under the options
+	changed := cosmetic
+	changed.MntBy = []string{"MAINT-C"}
+	diff, err = ComputeDiffWithOptions(ctx, from, snapshot("to", changed), opts)
+	if err != nil {
+		t.Fatalf("ComputeDiffWithOptions() failed: %v", err)
+	}
+	if len(diff.Modified) != 1 {
+		t.Fatalf("Expected 1 modified route, got %d", len(diff.Modified))
+	}
+	if fcs := diff.Modified[0].FieldChanges; len(fcs) != 1 || fcs[0].Field != "MntBy" {
+		t.Errorf("Expected only the MntBy change, got %+v", fcs)
+	}
+
+	for _, invalid := range []DiffOptions{
+		{IgnoreFields: []string{"colour"}},
+		{UnorderedListFields: []string{"origin"}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want an error", invalid)
+		}
+	}
+}
+
+// liveSource serves fixed routes and contacts, marked truncated when
+// truncated names their type, and records the filters used.
+type liveSource struct {
+	routes    []models.RouteObject
+	contacts  []models.Contact
+	truncated string
+	filters   map[string]string
+	listed    []string
+}
+
+func (s *liveSource) ListRoutes(ctx context.Context, filters map[string]string) (*models.RouteList, error) {
+	s.filters = filters
+	s.listed = append(s.listed, "routes")
+	return &models.RouteList{Routes: s.routes, Count: len(s.routes), Truncated: s.truncated == "routes"}, nil
+}
+
+func (s *liveSource) ListContacts(ctx context.Context) (*models.ContactList, error) {
+	s.listed = append(s.listed, "contacts")
+	return &models.ContactList{Contacts: s.contacts, Count: len(s.contacts), Truncated: s.truncated == "contacts"}, nil
+}
+
+func TestDiffAgainstLive(t *testing.T) {
+	ctx := context.Background()
+
+	route := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}
+	changed := route
+	changed.MntBy = []string{"MAINT-B"}
+
+	snapshot := models.NewScopedSnapshot(models.SnapshotTypeRoute, "", map[string]string{"origin": "AS64500"})
+	snapshot.Routes = &models.RouteList{Routes: []models.RouteObject{route}, Count: 1}
+
+	source := &liveSource{routes: []models.RouteObject{changed}}
+	diff, live, err := DiffAgainstLive(ctx, snapshot, source, DiffOptions{})
+	if err != nil {
+		t.Fatalf("DiffAgainstLive() failed: %v", err)
+	}
+
+	if len(diff.Modified) != 1 || diff.Modified[0].ID != route.ID() {
+		t.Errorf("Expected the route to be modified, got %+v", diff.Modified)
+	}
+	if live.ID != LiveSnapshotID || live.Scope() != snapshot.Scope() {
+		t.Errorf("Live snapshot %s has scope %q, want %s with scope %q", live.ID, live.Scope(), LiveSnapshotID, snapshot.Scope())
+	}
+	if source.filters["origin"] != "AS64500" {
+		t.Errorf("Routes listed with filters %v, want the snapshot's", source.filters)
+	}
+	if len(source.listed) != 1 || source.listed[0] != "routes" {
+		t.Errorf("Listed %v, want only routes for a route snapshot", source.listed)
+	}
+}
+
+func TestDiffAgainstLiveTruncated(t *testing.T) {
+	route := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}
+	contact := models.Contact{ID: "ADMIN-1", Email: "noc@example.com"}
+
+	full := models.NewSnapshot(models.SnapshotTypeFull, "")
+	full.Routes = &models.RouteList{Routes: []models.RouteObject{route}, Count: 1}
+	full.Contacts = &models.ContactList{Contacts: []models.Contact{contact}, Count: 1}
+
+	for _, truncated := range []string{"routes", "contacts"} {
+		t.Run(truncated, func(t *testing.T) {
+			source := &liveSource{routes: []models.RouteObject{route}, contacts: []models.Contact{contact}, truncated: truncated}
+			diff, live, err := DiffAgainstLive(context.Background(), full, source, DiffOptions{})
+			if err == nil || !strings.Contains(err.Error(), "live "+truncated+" were truncated") {
+				t.Fatalf("DiffAgainstLive() error = %v, want the truncated %s refused", err, truncated)
+			}
+			if diff != nil || live != nil {
+				t.Errorf("DiffAgainstLive() returned a diff for truncated %s", truncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package state
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// DiffOptions control which differences between two versions of an object
+// count as a modification. The zero value compares every field exactly.
+// Field names may be given as RPSL attributes (mnt-by), JSON keys (mnt_by),
+// or Go fields (MntBy).
+type DiffOptions struct {
+	IgnoreFields        []string // Fields never compared, e.g. remarks
+	UnorderedListFields []string // List fields compared regardless of line order, e.g. descr, mnt-by
+	IgnoreCase          bool     // Compare values case-insensitively
+}
+
+// routeDiffFields and contactDiffFields are the fields compared between two
+// versions of a route or contact. Timestamps and raw attributes are not.
+var (
+	routeDiffFields   = []string{"Route", "Origin", "Source", "Descr", "MntBy", "Remarks", "MemberOf", "Holes"}
+	contactDiffFields = []string{"ID", "Name", "Email", "Phone", "Role", "Organization", "Address"}
+)
+
+// IsZero reports whether the options compare every field exactly.
+func (o DiffOptions) IsZero() bool {
+	return len(o.IgnoreFields) == 0 && len(o.UnorderedListFields) == 0 && !o.IgnoreCase
+}
+
+// Validate checks that every named field is compared, and that unordered
+// fields are lists.
+func (o DiffOptions) Validate() error {
+	for _, name := range o.IgnoreFields {
+		if len(diffFieldKinds(name)) == 0 {
+			return fmt.Errorf("unknown diff field %q", name)
+		}
+	}
+	for _, name := range o.UnorderedListFields {
+		kinds := diffFieldKinds(name)
+		if len(kinds) == 0 {
+			return fmt.Errorf("unknown diff field %q", name)
+		}
+		for _, kind := range kinds {
+			if kind != reflect.Slice {
+				return fmt.Errorf("diff field %q is not a list", name)
+			}
+		}
+	}
+	return nil
+}
+
+// fieldKey normalizes a field name, so mnt-by, mnt_by, and MntBy match.
+func fieldKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name))
+}
+
+// diffFieldKinds returns the kinds of the compared route and contact fields
+// with the given name.
+func diffFieldKinds(name string) []reflect.Kind {
+	var kinds []reflect.Kind
+	key := fieldKey(name)
+	for _, object := range []interface{}{models.RouteObject{}, models.Contact{}} {
+		t := reflect.TypeOf(object)
+		fields := routeDiffFields
+		if t == reflect.TypeOf(models.Contact{}) {
+			fields = contactDiffFields
+		}
+		for _, field := range fields {
+			if fieldKey(field) == key {
+				f, _ := t.FieldByName(field)
+				kinds = append(kinds, f.Type.Kind())
+			}
+		}
+	}
+	return kinds
+}
+
+// has reports whether names includes the field.
+func has(names []string, field string) bool {
+	key := fieldKey(field)
+	return slices.ContainsFunc(names, func(name string) bool { return fieldKey(name) == key })
+}
+
+// changedFields returns the Go names of the given fields of two structs of
+// the same type that differ under the options.
+func (o DiffOptions) changedFields(a, b interface{}, fields []string) []string {
+	av := reflect.Indirect(reflect.ValueOf(a))
+	bv := reflect.Indirect(reflect.ValueOf(b))
+
+	var changed []string
+	for _, field := range fields {
+		if has(o.IgnoreFields, field) {
+			continue
+		}
+		af, bf := av.FieldByName(field), bv.FieldByName(field)
+		switch af.Kind() {
+		case reflect.String:
+			if !o.equalValue(af.String(), bf.String()) {
+				changed = append(changed, field)
+			}
+		case reflect.Slice:
+			if !o.equalLists(af.Interface().([]string), bf.Interface().([]string), has(o.UnorderedListFields, field)) {
+				changed = append(changed, field)
+			}
+		}
+	}
+	return changed
+}
+
+// equalValue compares two values.
+func (o DiffOptions) equalValue(a, b string) bool {
+	if o.IgnoreCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// equalLists compares two lists, in order unless unordered.
+func (o DiffOptions) equalLists(a, b []string, unordered bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if unordered {
+		normalize := func(list []string) []string {
+			sorted := slices.Clone(list)
+			if o.IgnoreCase {
+				for i := range sorted {
+					sorted[i] = strings.ToLower(sorted[i])
+				}
+			}
+			slices.Sort(sorted)
+			return sorted
+		}
+		a, b = normalize(a), normalize(b)
+	}
+	for i := range a {
+		if !o.equalValue(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// modifiedItem returns the modification between two versions of an object
+// under the options, and whether there is one. Field changes are limited to
+// the fields that differ under the options.
+func (o DiffOptions) modifiedItem(objectType, id string, before, after interface{}, fields []string) (models.ModifiedItem, bool) {
+	changed := o.changedFields(before, after, fields)
+	if len(changed) == 0 {
+		return models.ModifiedItem{}, false
+	}
+
+	fieldChanges := make([]models.FieldChange, 0, len(changed))
+	for _, fc := range models.DetectFieldChanges(before, after) {
+		if slices.Contains(changed, fc.Field) {
+			fieldChanges = append(fieldChanges, fc)
+		}
+	}
+	return models.ModifiedItem{
+		ID:           id,
+		ObjectType:   objectType,
+		Before:       before,
+		After:        after,
+		FieldChanges: fieldChanges,
+	}, true
+}
@@ -104,6 +104,7 @@ func (h *HistoryManager) QueryChanges(ctx context.Context, from, to time.Time, o
 			continue
 		}
 
+		entry.ID = entry.EntryID()
 		entries = append(entries, entry)
 	}
 
@@ -119,6 +120,21 @@ func (h *HistoryManager) GetChangesSince(ctx context.Context, since time.Time) (
 	return h.QueryChanges(ctx, since, time.Now(), "")
 }
 
+// ChurnScores counts the changes recorded for each object of objectType
+// since a point in time. Objects without changes are absent from the map.
+func (h *HistoryManager) ChurnScores(ctx context.Context, objectType string, since time.Time) (map[string]int, error) {
+	entries, err := h.QueryChanges(ctx, since, time.Now(), objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]int)
+	for _, entry := range entries {
+		scores[entry.ObjectID]++
+	}
+	return scores, nil
+}
+
 // GetRecentChanges retrieves the most recent N changes.
 func (h *HistoryManager) GetRecentChanges(ctx context.Context, limit int) ([]models.ChangelogEntry, error) {
 	if _, err := os.Stat(h.changelogPath); os.IsNotExist(err) {
@@ -140,6 +156,7 @@ func (h *HistoryManager) GetRecentChanges(ctx context.Context, limit int) ([]mod
 			h.logger.Warnf("Failed to parse changelog entry: %v", err)
 			continue
 		}
+		entry.ID = entry.EntryID()
 		allEntries = append(allEntries, entry)
 	}
 
@@ -189,12 +206,12 @@ func (h *HistoryManager) GetStatistics(ctx context.Context, from, to time.Time)
 
 // HistoryStatistics provides aggregate statistics about changes.
 type HistoryStatistics struct {
-	TotalChanges int                         `json:"total_changes"`
-	ByType       map[models.ChangeType]int   `json:"by_type"`
-	ByObjectType map[string]int              `json:"by_object_type"`
-	FirstChange  time.Time                   `json:"first_change"`
-	LastChange   time.Time                   `json:"last_change"`
-	TimeRange    TimeRange                   `json:"time_range"`
+	TotalChanges int                       `json:"total_changes"`
+	ByType       map[models.ChangeType]int `json:"by_type"`
+	ByObjectType map[string]int            `json:"by_object_type"`
+	FirstChange  time.Time                 `json:"first_change"`
+	LastChange   time.Time                 `json:"last_change"`
+	TimeRange    TimeRange                 `json:"time_range"`
 }
 
 // TimeRange represents a time range for queries.
@@ -0,0 +1,208 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestChurnScores(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+	history := NewHistoryManager(t.TempDir(), logger)
+
+	now := time.Now().UTC()
+	flapping := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500"}
+	stable := models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500"}
+
+	changes := &models.ChangeSet{Changes: []models.Change{
+		{Type: models.ChangeTypeModified, ObjectType: "route", ObjectID: stable.ID(), Timestamp: now.Add(-60 * 24 * time.Hour), After: stable},
+		{Type: models.ChangeTypeModified, ObjectType: "route", ObjectID: flapping.ID(), Timestamp: now.Add(-3 * 24 * time.Hour), After: flapping},
+		{Type: models.ChangeTypeRemoved, ObjectType: "route", ObjectID: flapping.ID(), Timestamp: now.Add(-2 * 24 * time.Hour), Before: flapping},
+		{Type: models.ChangeTypeAdded, ObjectType: "route", ObjectID: flapping.ID(), Timestamp: now.Add(-time.Hour), After: flapping},
+		{Type: models.ChangeTypeAdded, ObjectType: "contact", ObjectID: "C-1", Timestamp: now.Add(-time.Hour)},
+	}}
+	if err := history.AppendChanges(ctx, changes); err != nil {
+		t.Fatalf("AppendChanges() failed: %v", err)
+	}
+
+	scores, err := history.ChurnScores(ctx, "route", now.Add(-30*24*time.Hour))
+	if err != nil {
+		t.Fatalf("ChurnScores() failed: %v", err)
+	}
+	if scores[flapping.ID()] != 3 {
+		t.Errorf("churn of %s = %d, want 3", flapping.ID(), scores[flapping.ID()])
+	}
+	if _, ok := scores[stable.ID()]; ok {
+		t.Errorf("churn includes %s, changed outside the window", stable.ID())
+	}
+	if len(scores) != 1 {
+		t.Errorf("scores = %v, want only %s", scores, flapping.ID())
+	}
+}
+
+func TestObjectHistory(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+	history := NewHistoryManager(t.TempDir(), logger)
+
+	now := time.Now().UTC()
+	v1 := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-A"}, Source: "RADB"}
+	v2 := v1
+	v2.Descr = []string{"Moved to the new PoP"}
+	other := models.RouteObject{Route: "198.51.100.0/24", Origin: "AS64500"}
+
+	changes := &models.ChangeSet{ToSnapshot: "route-2", Changes: []models.Change{
+		{Type: models.ChangeTypeAdded, ObjectType: "route", ObjectID: v1.ID(), Timestamp: now.Add(-72 * time.Hour), After: v1},
+		{Type: models.ChangeTypeAdded, ObjectType: "route", ObjectID: other.ID(), Timestamp: now.Add(-48 * time.Hour), After: other},
+		{Type: models.ChangeTypeModified, ObjectType: "route", ObjectID: v1.ID(), Timestamp: now.Add(-24 * time.Hour), Before: v1, After: v2},
+		{Type: models.ChangeTypeRemoved, ObjectType: "route", ObjectID: v1.ID(), Timestamp: now.Add(-time.Hour), Before: v2},
+	}}
+	if err := history.AppendChanges(ctx, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := history.QueryByObjectID(ctx, "192.0.2.0/24-as64500")
+	if err != nil {
+		t.Fatalf("QueryByObjectID() failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("QueryByObjectID() returned %d entries, want 3", len(entries))
+	}
+
+	lifecycle := BuildObjectHistory(entries)
+	if lifecycle.Exists || lifecycle.URN != "radb:route:192.0.2.0/24:AS64500" {
+		t.Errorf("history = exists %v, URN %q; want removed, radb:route:192.0.2.0/24:AS64500", lifecycle.Exists, lifecycle.URN)
+	}
+
+	fieldNames := func(event ObjectEvent) []string {
+		var names []string
+		for _, field := range event.Fields {
+			names = append(names, field.Field)
+		}
+		return names
+	}
+	if got := fieldNames(lifecycle.Events[0]); strings.Join(got, ",") != "Route,Origin,MntBy,Source" {
+		t.Errorf("added fields = %v, want Route, Origin, MntBy, Source", got)
+	}
+	modified := lifecycle.Events[1]
+	if len(modified.Fields) != 1 || modified.Fields[0].Field != "Descr" ||
+		string(modified.Fields[0].OldValue) != "null" || string(modified.Fields[0].NewValue) != `["Moved to the new PoP"]` {
+		t.Errorf("modified fields = %+v, want Descr null -> [Moved to the new PoP]", modified.Fields)
+	}
+	if got := fieldNames(lifecycle.Events[2]); len(got) != 5 {
+		t.Errorf("removed fields = %v, want every set field", got)
+	}
+
+	if BuildObjectHistory(nil) != nil {
+		t.Error("BuildObjectHistory(nil) should return nil")
+	}
+}
+
+func TestWriteChangelogExports(t *testing.T) {
+	route := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500"}
+	after, _ := json.Marshal(route)
+	entries := []models.ChangelogEntry{
+		{
+			Timestamp:    time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+			ChangeType:   models.ChangeTypeModified,
+			ObjectType:   "route",
+			ObjectID:     route.ID(),
+			SnapshotID:   "2024-06-01T12-00-00",
+			After:        after,
+			FieldChanges: []string{"Descr", "Remarks"},
+			Note:         "moved, per ticket 42",
+		},
+		{ChangeType: models.ChangeTypeAdded, ObjectType: "contact", ObjectID: "C-1"},
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteChangelogCSV(&csvOut, entries); err != nil {
+		t.Fatalf("WriteChangelogCSV() failed: %v", err)
+	}
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d CSV records, want header and 2 rows", len(records))
+	}
+	row := records[1]
+	want := []string{entries[0].EntryID(), "2024-06-01T12:00:00Z", "modified", "route", route.ID(), "radb:route:192.0.2.0/24:AS64500", "2024-06-01T12-00-00", "Descr;Remarks", "moved, per ticket 42", "", string(after)}
+	for i := range want {
+		if row[i] != want[i] {
+			t.Errorf("column %s = %q, want %q", records[0][i], row[i], want[i])
+		}
+	}
+	if records[2][5] != "radb:contact:C-1" {
+		t.Errorf("missing URN not filled in: %q", records[2][5])
+	}
+
+	var jsonlOut bytes.Buffer
+	if err := WriteChangelogJSONL(&jsonlOut, entries); err != nil {
+		t.Fatalf("WriteChangelogJSONL() failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(jsonlOut.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d JSON lines, want 2", len(lines))
+	}
+	var decoded models.ChangelogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("line is not a changelog entry: %v", err)
+	}
+	if decoded.ObjectID != route.ID() || decoded.Note != entries[0].Note {
+		t.Errorf("decoded entry = %+v", decoded)
+	}
+}
+
+func TestChangelogEntryIDs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+	history := NewHistoryManager(t.TempDir(), logger)
+
+	now := time.Now().UTC()
+	route := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500"}
+	changes := &models.ChangeSet{Changes: []models.Change{
+		{Type: models.ChangeTypeAdded, ObjectType: "route", ObjectID: route.ID(), Timestamp: now.Add(-time.Hour), After: route},
+		{Type: models.ChangeTypeRemoved, ObjectType: "route", ObjectID: route.ID(), Timestamp: now, Before: route},
+	}}
+	if err := history.AppendChanges(ctx, changes); err != nil {
+		t.Fatalf("AppendChanges() failed: %v", err)
+	}
+
+	entries, err := history.QueryChanges(ctx, time.Time{}, time.Now(), "")
+	if err != nil {
+		t.Fatalf("QueryChanges() failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID == "" || entries[0].ID == entries[1].ID {
+		t.Fatalf("entries lack distinct IDs: %+v", entries)
+	}
+
+	// Entries written before IDs existed get the same ID when read
+	legacy := entries[1]
+	legacy.ID = ""
+	if got := legacy.EntryID(); got != entries[1].ID {
+		t.Errorf("derived ID = %s, want %s", got, entries[1].ID)
+	}
+
+	entry, err := history.GetEntry(ctx, entries[1].ID[:8])
+	if err != nil {
+		t.Fatalf("GetEntry() by prefix failed: %v", err)
+	}
+	if entry.ChangeType != models.ChangeTypeRemoved {
+		t.Errorf("GetEntry() = %s entry, want removed", entry.ChangeType)
+	}
+	if _, err := history.GetEntry(ctx, "ffffffffffff0"); err == nil {
+		t.Error("GetEntry() of an unknown ID succeeded")
+	}
+}
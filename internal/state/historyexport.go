@@ -0,0 +1,66 @@
+package state
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// changelogCSVHeader is the header row of a CSV changelog export.
+var changelogCSVHeader = []string{
+	"id", "timestamp", "change_type", "object_type", "object_id", "urn",
+	"snapshot_id", "field_changes", "note", "before", "after",
+}
+
+// WriteChangelogCSV writes changelog entries as CSV with a header row, one
+// entry per row. Changed fields are joined with semicolons, and object
+// states are written as compact JSON.
+func WriteChangelogCSV(w io.Writer, entries []models.ChangelogEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(changelogCSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, entry := range entries {
+		urn := entry.URN
+		if urn == "" {
+			urn = models.ObjectURN(entry.ObjectType, entry.ObjectID)
+		}
+		record := []string{
+			entry.EntryID(),
+			entry.Timestamp.UTC().Format(time.RFC3339),
+			string(entry.ChangeType),
+			entry.ObjectType,
+			entry.ObjectID,
+			urn,
+			entry.SnapshotID,
+			strings.Join(entry.FieldChanges, ";"),
+			entry.Note,
+			string(entry.Before),
+			string(entry.After),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteChangelogJSONL writes changelog entries as JSON Lines, one entry per
+// line, in the same form as the changelog itself.
+func WriteChangelogJSONL(w io.Writer, entries []models.ChangelogEntry) error {
+	enc := json.NewEncoder(w)
+	for i := range entries {
+		if err := enc.Encode(&entries[i]); err != nil {
+			return fmt.Errorf("failed to write changelog entry: %w", err)
+		}
+	}
+	return nil
+}
@@ -7,14 +7,22 @@ import (
 	"github.com/bss/radb-client/internal/models"
 )
 
-// Manager defines the interface for state management operations.
+// Manager defines the interface for state management operations. It is the
+// only state API: CLI commands and the daemon both use it, through
+// FileManager or BackendManager.
 type Manager interface {
 	// Snapshot operations
 	SaveSnapshot(ctx context.Context, snapshot *models.Snapshot) error
 	LoadSnapshot(ctx context.Context, id string) (*models.Snapshot, error)
 	GetLatestSnapshot(ctx context.Context, snapshotType models.SnapshotType) (*models.Snapshot, error)
+	// ListSnapshots returns snapshot metadata and counts, newest first,
+	// without routes or contacts
 	ListSnapshots(ctx context.Context) ([]models.Snapshot, error)
+	// QuerySnapshots lists the snapshots matching a metadata query, newest
+	// first, like ListSnapshots
+	QuerySnapshots(ctx context.Context, query SnapshotQuery) ([]models.Snapshot, error)
 	DeleteSnapshot(ctx context.Context, id string) error
+	TagSnapshot(ctx context.Context, id string, add, remove []string) ([]string, error)
 
 	// Change detection
 	ComputeChanges(ctx context.Context, from, to *models.Snapshot) (*models.ChangeSet, error)
@@ -23,3 +31,12 @@ type Manager interface {
 	Cleanup(ctx context.Context, options CleanupOptions) (*CleanupResult, error)
 	Close() error
 }
+
+// Ensure FileManager implements Manager.
+var _ Manager = (*FileManager)(nil)
+
+// Integrity configures how snapshots are checksummed when saved.
+type Integrity struct {
+	Algorithm string // models.ChecksumSHA256 (default) or models.ChecksumBLAKE3
+	PerObject bool   // Also record a hash of each route and contact
+}
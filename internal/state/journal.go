@@ -0,0 +1,123 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// journalsDir is the directory of bulk operation journals within the state directory.
+const journalsDir = "journals"
+
+// JournalStore persists bulk operation journals, one file per operation.
+type JournalStore struct {
+	dir    string
+	logger *logrus.Logger
+}
+
+// NewJournalStore creates a journal store in the given state directory.
+func NewJournalStore(stateDir string, logger *logrus.Logger) *JournalStore {
+	return &JournalStore{
+		dir:    filepath.Join(stateDir, journalsDir),
+		logger: logger,
+	}
+}
+
+// Save writes a journal atomically.
+func (s *JournalStore) Save(journal *models.BulkJournal) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+
+	path := s.path(journal.ID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save journal: %w", err)
+	}
+
+	s.logger.Debugf("Saved journal %s", journal.ID)
+	return nil
+}
+
+// Load reads a journal by ID.
+func (s *JournalStore) Load(id string) (*models.BulkJournal, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid journal ID: %q", id)
+	}
+
+	data, err := os.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("journal not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var journal models.BulkJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", id, err)
+	}
+	return &journal, nil
+}
+
+// List returns all journals, most recently updated first.
+func (s *JournalStore) List() ([]*models.BulkJournal, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal directory: %w", err)
+	}
+
+	var journals []*models.BulkJournal
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		journal, err := s.Load(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			s.logger.Warnf("Skipping journal %s: %v", entry.Name(), err)
+			continue
+		}
+		journals = append(journals, journal)
+	}
+
+	sort.Slice(journals, func(i, j int) bool {
+		return journals[i].UpdatedAt.After(journals[j].UpdatedAt)
+	})
+
+	return journals, nil
+}
+
+// Delete removes a journal.
+func (s *JournalStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("journal not found: %s", id)
+		}
+		return fmt.Errorf("failed to delete journal: %w", err)
+	}
+	return nil
+}
+
+// path returns the file path of a journal.
+func (s *JournalStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
@@ -0,0 +1,96 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestJournalStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	store := NewJournalStore(tmpDir, logger)
+
+	journals, err := store.List()
+	if err != nil || len(journals) != 0 {
+		t.Fatalf("List() = %v, %v; want no journals", journals, err)
+	}
+
+	journal := models.NewBulkJournal(models.BulkCreateRoutes)
+	for _, prefix := range []string{"192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24"} {
+		journal.AddRoute(&models.RouteObject{Route: prefix, Origin: "AS64500"})
+	}
+	journal.Record(0, nil)
+	journal.Record(1, errors.New("status 500"))
+
+	if err := store.Save(journal); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	loaded, err := store.Load(journal.ID)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loaded.Operation != models.BulkCreateRoutes || len(loaded.Items) != 3 {
+		t.Fatalf("loaded journal = %+v, want 3 create-routes items", loaded)
+	}
+
+	pending, succeeded, failed := loaded.Counts()
+	if pending != 1 || succeeded != 1 || failed != 1 {
+		t.Errorf("Counts() = %d, %d, %d; want 1, 1, 1", pending, succeeded, failed)
+	}
+	if remaining := loaded.Remaining(); len(remaining) != 2 || remaining[0] != 1 || remaining[1] != 2 {
+		t.Errorf("Remaining() = %v, want [1 2]", remaining)
+	}
+	if loaded.Items[1].Error != "status 500" || loaded.Items[1].Route.Route != "198.51.100.0/24" {
+		t.Errorf("failed item = %+v", loaded.Items[1])
+	}
+
+	if failedItems := loaded.Failed(); len(failedItems) != 1 || failedItems[0] != 1 {
+		t.Errorf("Failed() = %v, want [1]", failedItems)
+	}
+
+	loaded.AddRun(models.BulkRun{Total: 2, Succeeded: 1, Failed: 1})
+	if err := store.Save(loaded); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if reloaded, err := store.Load(journal.ID); err != nil || len(reloaded.Runs) != 1 || reloaded.Runs[0].Failed != 1 {
+		t.Errorf("reloaded runs = %+v, %v; want one run with one failure", reloaded.Runs, err)
+	}
+
+	loaded.Record(1, nil)
+	loaded.Record(2, nil)
+	if !loaded.Complete() || loaded.Items[1].Attempts != 2 || loaded.Items[1].Error != "" {
+		t.Errorf("journal after retry = %+v, want complete", loaded)
+	}
+
+	if _, err := store.Load("../annotations"); err == nil {
+		t.Error("Load() accepted a path outside the journal directory")
+	}
+
+	// Journals must not be mistaken for snapshots
+	fm, err := NewFileManager(tmpDir, logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer fm.Close()
+
+	snapshots, err := fm.ListSnapshots(context.Background())
+	if err != nil {
+		t.Fatalf("ListSnapshots() failed: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("ListSnapshots() = %d snapshots, want 0", len(snapshots))
+	}
+
+	if err := store.Delete(journal.ID); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := store.Load(journal.ID); err == nil {
+		t.Error("Load() succeeded after Delete()")
+	}
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/tracing"
+)
+
+// LiveSnapshotID identifies the unsaved snapshot of live objects compared by
+// DiffAgainstLive.
+const LiveSnapshotID = "live"
+
+// LiveSource lists the objects currently registered, as api.Client does.
+type LiveSource interface {
+	ListRoutes(ctx context.Context, filters map[string]string) (*models.RouteList, error)
+	ListContacts(ctx context.Context) (*models.ContactList, error)
+}
+
+// DiffAgainstLive compares a stored snapshot with the objects currently
+// registered, without saving a new snapshot. Only the object types in the
+// snapshot are fetched, routes with the snapshot's filters, so a scoped
+// snapshot is compared with the same scope. The live objects are returned
+// as an unsaved snapshot with the ID LiveSnapshotID. Listings truncated by
+// the client's result cap are refused, since every object past the cap
+// would be reported as removed.
+func DiffAgainstLive(ctx context.Context, snapshot *models.Snapshot, source LiveSource, opts DiffOptions) (*models.DiffResult, *models.Snapshot, error) {
+	if snapshot == nil {
+		return nil, nil, fmt.Errorf("snapshot must be non-nil")
+	}
+
+	ctx, span := tracing.Start(ctx, "state.DiffAgainstLive", tracing.String("snapshot.from", snapshot.ID))
+	defer span.End()
+
+	filters := snapshot.Filters()
+	live := models.NewScopedSnapshot(snapshot.Type, "Live objects", filters)
+	live.ID = LiveSnapshotID
+
+	if snapshot.Routes != nil {
+		routes, err := source.ListRoutes(ctx, filters)
+		if err != nil {
+			span.RecordError(err)
+			return nil, nil, fmt.Errorf("failed to list routes: %w", err)
+		}
+		if routes.Truncated {
+			return nil, nil, fmt.Errorf("live routes were truncated by api.max_results; raise or unset it to compare")
+		}
+		live.Routes = routes
+	}
+	if snapshot.Contacts != nil {
+		contacts, err := source.ListContacts(ctx)
+		if err != nil {
+			span.RecordError(err)
+			return nil, nil, fmt.Errorf("failed to list contacts: %w", err)
+		}
+		if contacts.Truncated {
+			return nil, nil, fmt.Errorf("live contacts were truncated by api.max_results; raise or unset it to compare")
+		}
+		live.Contacts = contacts
+	}
+
+	diff, err := ComputeDiffWithOptions(ctx, snapshot, live, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return diff, live, nil
+}
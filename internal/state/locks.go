@@ -0,0 +1,149 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+const (
+	// locksDir holds the per-snapshot lock files under the state directory
+	locksDir = "locks"
+
+	// lockStripes is the number of per-snapshot lock files. Snapshots are
+	// hashed onto them, so the files never need deleting.
+	lockStripes = 64
+
+	// lockRetryDelay is how often a contended lock is retried
+	lockRetryDelay = 5 * time.Second
+)
+
+// fileLock is a reader/writer lock held across processes with a lock file
+// and across goroutines in memory. A flock alone is held per process: it
+// neither orders goroutines nor survives one goroutine unlocking while
+// another still reads. Waits, in memory and on the file, end with ctx.
+type fileLock struct {
+	mu        sync.Mutex
+	readers   int
+	writer    bool
+	acquiring bool          // The first reader is taking the shared flock
+	released  chan struct{} // Closed and replaced whenever the state changes
+	file      *flock.Flock
+}
+
+func newFileLock(path string) *fileLock {
+	return &fileLock{released: make(chan struct{}), file: flock.New(path)}
+}
+
+// lock acquires the lock, shared unless exclusive, and returns the function
+// that releases it.
+func (l *fileLock) lock(ctx context.Context, exclusive bool) (func(), error) {
+	for {
+		l.mu.Lock()
+		switch {
+		case exclusive && !l.writer && l.readers == 0:
+			l.writer = true
+			l.mu.Unlock()
+			if err := acquire(ctx, l.file.TryLockContext); err != nil {
+				l.update(func() { l.writer = false })
+				return nil, err
+			}
+			return func() {
+				l.file.Unlock()
+				l.update(func() { l.writer = false })
+			}, nil
+
+		case !exclusive && !l.writer && !l.acquiring:
+			l.readers++
+			if l.readers > 1 {
+				l.mu.Unlock()
+				return l.unlockReader, nil
+			}
+			l.acquiring = true
+			l.mu.Unlock()
+			err := acquire(ctx, l.file.TryRLockContext)
+			l.update(func() {
+				l.acquiring = false
+				if err != nil {
+					l.readers--
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+			return l.unlockReader, nil
+		}
+
+		released := l.released
+		l.mu.Unlock()
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire lock: %w", ctx.Err())
+		}
+	}
+}
+
+// unlockReader releases a shared hold, and the shared flock with the last.
+func (l *fileLock) unlockReader() {
+	l.update(func() {
+		l.readers--
+		if l.readers == 0 {
+			l.file.Unlock()
+		}
+	})
+}
+
+// update changes the lock state and wakes the waiters.
+func (l *fileLock) update(change func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	change()
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// acquire takes a flock with one of its TryLockContext methods.
+func acquire(ctx context.Context, try func(context.Context, time.Duration) (bool, error)) error {
+	locked, err := try(ctx, lockRetryDelay)
+	if err != nil {
+		return fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return errors.New("could not acquire lock: timeout")
+	}
+	return nil
+}
+
+// snapshotLocks stripes per-snapshot locks over a fixed set of lock files.
+type snapshotLocks [lockStripes]*fileLock
+
+func newSnapshotLocks(stateDir string) *snapshotLocks {
+	var locks snapshotLocks
+	for i := range locks {
+		locks[i] = newFileLock(filepath.Join(stateDir, locksDir, fmt.Sprintf("%02x.lock", i)))
+	}
+	return &locks
+}
+
+// lock acquires the lock of the snapshot with the given ID.
+func (s *snapshotLocks) lock(ctx context.Context, id string, exclusive bool) (func(), error) {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return s[h.Sum32()%lockStripes].lock(ctx, exclusive)
+}
+
+// close releases the lock files.
+func (s *snapshotLocks) close() error {
+	var errs []error
+	for _, l := range s {
+		errs = append(errs, l.file.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,143 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestFileLock(t *testing.T) {
+	lock := newFileLock(filepath.Join(t.TempDir(), "test.lock"))
+	ctx := context.Background()
+
+	// expectBlocked checks that a lock cannot be taken while another is held
+	expectBlocked := func(t *testing.T, exclusive bool) {
+		t.Helper()
+		waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if unlock, err := lock.lock(waitCtx, exclusive); err == nil {
+			unlock()
+			t.Fatalf("Expected lock(exclusive=%v) to wait", exclusive)
+		}
+	}
+
+	first, err := lock.lock(ctx, false)
+	if err != nil {
+		t.Fatalf("lock() failed: %v", err)
+	}
+	second, err := lock.lock(ctx, false)
+	if err != nil {
+		t.Fatalf("Expected readers to share the lock: %v", err)
+	}
+	expectBlocked(t, true)
+
+	first()
+	expectBlocked(t, true)
+	second()
+
+	unlock, err := lock.lock(ctx, true)
+	if err != nil {
+		t.Fatalf("Expected the writer to lock once readers left: %v", err)
+	}
+	expectBlocked(t, false)
+	expectBlocked(t, true)
+	unlock()
+
+	if unlock, err = lock.lock(ctx, false); err != nil {
+		t.Fatalf("Expected a reader to lock once the writer left: %v", err)
+	}
+	unlock()
+}
+
+func TestFileManagerReadsDuringWrites(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	mgr, err := NewFileManager(t.TempDir(), logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer mgr.Close()
+	ctx := context.Background()
+
+	newSnapshot := func(prefix string) *models.Snapshot {
+		snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "locking")
+		snapshot.Routes = models.NewRouteList([]models.RouteObject{
+			{Route: prefix, Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+		})
+		return snapshot
+	}
+
+	saved := newSnapshot("192.0.2.0/24")
+	if err := mgr.SaveSnapshot(ctx, saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mgr.ListSnapshots(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("OtherSnapshotWrite", func(t *testing.T) {
+		// Hold the locks of a write to a snapshot on another stripe
+		stripe := func(id string) uint32 {
+			h := fnv.New32a()
+			h.Write([]byte(id))
+			return h.Sum32() % lockStripes
+		}
+		other := "route-other"
+		for i := 0; stripe(other) == stripe(saved.ID); i++ {
+			other = fmt.Sprintf("route-other-%d", i)
+		}
+		unlock, err := mgr.lockForWrite(ctx, other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer unlock()
+
+		readCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		if _, err := mgr.LoadSnapshot(readCtx, saved.ID); err != nil {
+			t.Errorf("Expected the load not to wait for another snapshot's write: %v", err)
+		}
+		if _, err := mgr.ListSnapshots(readCtx); err != nil {
+			t.Errorf("Expected the listing not to wait for a write: %v", err)
+		}
+	})
+
+	t.Run("ConcurrentSavesAndLoads", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				errs <- mgr.SaveSnapshot(ctx, newSnapshot(fmt.Sprintf("198.51.100.%d/32", i)))
+			}(i)
+			go func() {
+				defer wg.Done()
+				_, err := mgr.LoadSnapshot(ctx, saved.ID)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Error(err)
+			}
+		}
+
+		snapshots, err := mgr.ListSnapshots(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(snapshots) != 11 {
+			t.Errorf("Expected 11 snapshots after concurrent saves, got %d", len(snapshots))
+		}
+	})
+}
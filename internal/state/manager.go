@@ -2,7 +2,6 @@ package state
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -12,16 +11,23 @@ import (
 	"time"
 
 	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/tracing"
 	"github.com/bss/radb-client/pkg/validator"
-	"github.com/gofrs/flock"
 	"github.com/sirupsen/logrus"
 )
 
 // FileManager implements the Manager interface with file-based storage.
+//
+// Writers hold the state directory's .lock, which guards the dedup index
+// and catalog, plus the lock of the snapshot they change. Readers hold only
+// the lock of the snapshot they load, and listings read the catalog without
+// locking, so a slow save never blocks reads of other snapshots.
 type FileManager struct {
-	stateDir string
-	logger   *logrus.Logger
-	lock     *flock.Flock
+	stateDir  string
+	logger    *logrus.Logger
+	lock      *fileLock
+	snapshots *snapshotLocks
+	integrity Integrity
 }
 
 // NewFileManager creates a new file-based state manager.
@@ -32,32 +38,35 @@ func NewFileManager(stateDir string, logger *logrus.Logger) (*FileManager, error
 	}
 
 	// Create directory if it doesn't exist
-	if err := os.MkdirAll(stateDir, 0700); err != nil {
+	if err := os.MkdirAll(filepath.Join(stateDir, locksDir), 0700); err != nil {
 		return nil, fmt.Errorf("failed to create state directory: %w", err)
 	}
 
-	// Initialize file lock
-	lockPath := filepath.Join(stateDir, ".lock")
-	lock := flock.New(lockPath)
-
 	return &FileManager{
-		stateDir: stateDir,
-		logger:   logger,
-		lock:     lock,
+		stateDir:  stateDir,
+		logger:    logger,
+		lock:      newFileLock(filepath.Join(stateDir, ".lock")),
+		snapshots: newSnapshotLocks(stateDir),
 	}, nil
 }
 
+// SetIntegrity configures the checksums recorded for saved snapshots.
+func (fm *FileManager) SetIntegrity(integrity Integrity) {
+	fm.integrity = integrity
+}
+
 // SaveSnapshot saves a snapshot to disk with file locking and checksumming.
-func (fm *FileManager) SaveSnapshot(ctx context.Context, snapshot *models.Snapshot) error {
-	// Acquire lock
-	locked, err := fm.lock.TryLockContext(ctx, 5*time.Second)
+func (fm *FileManager) SaveSnapshot(ctx context.Context, snapshot *models.Snapshot) (err error) {
+	ctx, span := tracing.Start(ctx, "state.SaveSnapshot",
+		tracing.String("snapshot.id", snapshot.ID),
+		tracing.String("snapshot.type", string(snapshot.Type)))
+	defer func() { span.EndErr(err) }()
+
+	unlock, err := fm.lockForWrite(ctx, snapshot.ID)
 	if err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	if !locked {
-		return errors.New("could not acquire lock: timeout")
+		return err
 	}
-	defer fm.lock.Unlock()
+	defer unlock()
 
 	// Validate snapshot
 	if err := snapshot.Validate(); err != nil {
@@ -65,64 +74,49 @@ func (fm *FileManager) SaveSnapshot(ctx context.Context, snapshot *models.Snapsh
 	}
 
 	// Compute checksum
+	snapshot.SetIntegrity(fm.integrity.Algorithm, fm.integrity.PerObject)
 	if err := snapshot.ComputeChecksum(); err != nil {
 		return fmt.Errorf("failed to compute checksum: %w", err)
 	}
 
-	// Marshal to JSON
-	data, err := json.MarshalIndent(snapshot, "", "  ")
+	size, err := storeSnapshot(ctx, fm, snapshot)
 	if err != nil {
-		return fmt.Errorf("failed to marshal snapshot: %w", err)
+		return err
 	}
 
-	// Write atomically
-	filename := fmt.Sprintf("%s.json", snapshot.ID)
-	path := filepath.Join(fm.stateDir, filename)
-	tmpPath := path + ".tmp"
-
-	// Write to temp file
-	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write snapshot: %w", err)
+	if snapshot.AliasOf != "" {
+		fm.logger.Infof("Saved snapshot %s as an alias of unchanged snapshot %s (%d bytes)", snapshot.ID, snapshot.AliasOf, size)
+		return nil
 	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, path); err != nil {
-		os.Remove(tmpPath) // Clean up
-		return fmt.Errorf("failed to save snapshot: %w", err)
-	}
-
-	fm.logger.Infof("Saved snapshot %s (%d bytes)", snapshot.ID, len(data))
+	fm.logger.Infof("Saved snapshot %s (%d bytes)", snapshot.ID, size)
 	return nil
 }
 
-// LoadSnapshot loads a snapshot from disk and verifies its integrity.
-func (fm *FileManager) LoadSnapshot(ctx context.Context, id string) (*models.Snapshot, error) {
-	// Acquire read lock
-	locked, err := fm.lock.TryRLockContext(ctx, 5*time.Second)
-	if err != nil {
-		return nil, fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	if !locked {
-		return nil, errors.New("could not acquire lock: timeout")
+// LoadSnapshot loads a snapshot from disk and verifies its integrity. The
+// ID may name a baseline, as in "baseline:golden".
+func (fm *FileManager) LoadSnapshot(ctx context.Context, id string) (_ *models.Snapshot, err error) {
+	ctx, span := tracing.Start(ctx, "state.LoadSnapshot", tracing.String("snapshot.id", id))
+	defer func() { span.EndErr(err) }()
+
+	if name, ok := BaselineName(id); ok {
+		if id, err = ResolveBaseline(ctx, fm, name); err != nil {
+			return nil, err
+		}
 	}
-	defer fm.lock.Unlock()
 
-	filename := fmt.Sprintf("%s.json", id)
-	path := filepath.Join(fm.stateDir, filename)
+	// Only this snapshot is locked; an alias's target is read as it stands
+	unlock, err := fm.snapshots.lock(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
 
-	// Read file
-	data, err := os.ReadFile(path)
+	snapshot, err := readSnapshot(ctx, fm, id)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("snapshot not found: %s", id)
 		}
-		return nil, fmt.Errorf("failed to read snapshot: %w", err)
-	}
-
-	// Unmarshal
-	var snapshot models.Snapshot
-	if err := json.Unmarshal(data, &snapshot); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+		return nil, err
 	}
 
 	// Verify checksum
@@ -132,20 +126,28 @@ func (fm *FileManager) LoadSnapshot(ctx context.Context, id string) (*models.Sna
 	}
 
 	fm.logger.Debugf("Loaded snapshot %s", id)
-	return &snapshot, nil
+	return snapshot, nil
 }
 
-// GetLatestSnapshot retrieves the most recent snapshot of a given type.
+// GetLatestSnapshot retrieves the most recent full-scope snapshot of a given type.
+// Snapshots captured from filtered listings are skipped so they are never
+// treated as a complete picture of the account.
 func (fm *FileManager) GetLatestSnapshot(ctx context.Context, snapshotType models.SnapshotType) (*models.Snapshot, error) {
-	snapshots, err := fm.ListSnapshots(ctx)
+	return latestSnapshot(ctx, fm, snapshotType)
+}
+
+// latestSnapshot finds the most recent full-scope snapshot of a type in
+// mgr's listing and loads it.
+func latestSnapshot(ctx context.Context, mgr Manager, snapshotType models.SnapshotType) (*models.Snapshot, error) {
+	snapshots, err := mgr.ListSnapshots(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter by type and sort by timestamp
+	// Filter by type and scope, then sort by timestamp
 	var filtered []models.Snapshot
 	for _, s := range snapshots {
-		if s.Type == snapshotType {
+		if s.Type == snapshotType && s.IsFullScope() {
 			filtered = append(filtered, s)
 		}
 	}
@@ -160,63 +162,86 @@ func (fm *FileManager) GetLatestSnapshot(ctx context.Context, snapshotType model
 	})
 
 	// Load the latest one
-	return fm.LoadSnapshot(ctx, filtered[0].ID)
+	return mgr.LoadSnapshot(ctx, filtered[0].ID)
 }
 
-// ListSnapshots lists all available snapshots.
+// ListSnapshots lists all available snapshots, newest first. Snapshots
+// are listed from the catalog with their counts but without routes or
+// contacts; use LoadSnapshot for their data.
 func (fm *FileManager) ListSnapshots(ctx context.Context) ([]models.Snapshot, error) {
+	names, err := fm.snapshotNames()
+	if err != nil {
+		return nil, err
+	}
+	if snapshots, ok := listSnapshots(ctx, fm, names, fm.logger, false); ok {
+		return snapshots, nil
+	}
+
+	// The catalog is out of date, so repair it under the write lock
+	unlock, err := fm.lock.lock(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if names, err = fm.snapshotNames(); err != nil {
+		return nil, err
+	}
+	snapshots, _ := listSnapshots(ctx, fm, names, fm.logger, true)
+	return snapshots, nil
+}
+
+// QuerySnapshots lists the snapshots matching query, newest first. It is
+// answered from the catalog, like ListSnapshots.
+func (fm *FileManager) QuerySnapshots(ctx context.Context, query SnapshotQuery) ([]models.Snapshot, error) {
+	return querySnapshots(ctx, fm, query)
+}
+
+// snapshotNames returns the file names of the stored snapshots.
+func (fm *FileManager) snapshotNames() ([]string, error) {
 	entries, err := os.ReadDir(fm.stateDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read state directory: %w", err)
 	}
 
-	var snapshots []models.Snapshot
+	var names []string
 	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
-			continue
-		}
-
-		// Load snapshot metadata only
-		path := filepath.Join(fm.stateDir, entry.Name())
-		data, err := os.ReadFile(path)
-		if err != nil {
-			fm.logger.Warnf("Failed to read %s: %v", entry.Name(), err)
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" || isStateFile(entry.Name()) {
 			continue
 		}
-
-		var snapshot models.Snapshot
-		if err := json.Unmarshal(data, &snapshot); err != nil {
-			fm.logger.Warnf("Failed to unmarshal %s: %v", entry.Name(), err)
-			continue
-		}
-
-		snapshots = append(snapshots, snapshot)
+		names = append(names, entry.Name())
 	}
-
-	// Sort by timestamp
-	sort.Slice(snapshots, func(i, j int) bool {
-		return snapshots[i].Timestamp.After(snapshots[j].Timestamp)
-	})
-
-	return snapshots, nil
+	return names, nil
 }
 
-// DeleteSnapshot deletes a snapshot from disk.
-func (fm *FileManager) DeleteSnapshot(ctx context.Context, id string) error {
-	// Acquire lock
-	locked, err := fm.lock.TryLockContext(ctx, 5*time.Second)
+// lockForWrite acquires the state directory's write lock and the lock of
+// the snapshot with the given ID, and returns the function releasing both.
+func (fm *FileManager) lockForWrite(ctx context.Context, id string) (func(), error) {
+	unlockState, err := fm.lock.lock(ctx, true)
 	if err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
+		return nil, err
 	}
-	if !locked {
-		return errors.New("could not acquire lock: timeout")
+	unlockSnapshot, err := fm.snapshots.lock(ctx, id, true)
+	if err != nil {
+		unlockState()
+		return nil, err
 	}
-	defer fm.lock.Unlock()
+	return func() {
+		unlockSnapshot()
+		unlockState()
+	}, nil
+}
 
-	filename := fmt.Sprintf("%s.json", id)
-	path := filepath.Join(fm.stateDir, filename)
+// DeleteSnapshot deletes a snapshot from disk. If later unchanged snapshots
+// alias it, its data moves to the newest of them.
+func (fm *FileManager) DeleteSnapshot(ctx context.Context, id string) error {
+	unlock, err := fm.lockForWrite(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	if err := os.Remove(path); err != nil {
+	if err := removeSnapshot(ctx, fm, id); err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return fmt.Errorf("snapshot not found: %s", id)
 		}
@@ -227,26 +252,83 @@ func (fm *FileManager) DeleteSnapshot(ctx context.Context, id string) error {
 	return nil
 }
 
+// TagSnapshot adds and removes tags on a snapshot and returns its tags.
+func (fm *FileManager) TagSnapshot(ctx context.Context, id string, add, remove []string) ([]string, error) {
+	unlock, err := fm.lockForWrite(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tags, err := retagSnapshot(ctx, fm, id, add, remove)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("snapshot not found: %s", id)
+	}
+	return tags, err
+}
+
+// readDocument reads a file in the state directory. Files are replaced by
+// atomic renames, so readers need no more than the snapshot's own lock.
+func (fm *FileManager) readDocument(ctx context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(fm.stateDir, name))
+}
+
+// writeDocument writes a file in the state directory atomically. Callers
+// hold the write lock.
+func (fm *FileManager) writeDocument(ctx context.Context, name string, data []byte) error {
+	path := filepath.Join(fm.stateDir, name)
+	tmpPath := path + ".tmp"
+
+	// Write to temp file
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+
+	// Atomic rename
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath) // Clean up
+		return err
+	}
+	return nil
+}
+
+// removeDocument removes a file from the state directory. Callers hold the
+// write lock.
+func (fm *FileManager) removeDocument(ctx context.Context, name string) error {
+	return os.Remove(filepath.Join(fm.stateDir, name))
+}
+
 // ComputeChanges computes the differences between two snapshots.
 func (fm *FileManager) ComputeChanges(ctx context.Context, from, to *models.Snapshot) (*models.ChangeSet, error) {
+	return computeChanges(ctx, fm.logger, from, to)
+}
+
+// computeChanges builds the change set between two snapshots.
+func computeChanges(ctx context.Context, logger *logrus.Logger, from, to *models.Snapshot) (*models.ChangeSet, error) {
+	_, span := tracing.Start(ctx, "state.ComputeChanges",
+		tracing.String("snapshot.from", from.ID),
+		tracing.String("snapshot.to", to.ID))
+	defer span.End()
+
 	changeset := models.NewChangeSet(from.ID, to.ID)
 
 	// Compare routes if present
 	if from.Routes != nil && to.Routes != nil {
-		fm.compareRoutes(from.Routes, to.Routes, changeset)
+		routeChanges(from.Routes, to.Routes, changeset)
 	}
 
 	// Compare contacts if present
 	if from.Contacts != nil && to.Contacts != nil {
-		fm.compareContacts(from.Contacts, to.Contacts, changeset)
+		contactChanges(from.Contacts, to.Contacts, changeset)
 	}
 
-	fm.logger.Debugf("Computed %d changes between %s and %s", len(changeset.Changes), from.ID, to.ID)
+	span.SetAttributes(tracing.Int("changes", len(changeset.Changes)))
+	logger.Debugf("Computed %d changes between %s and %s", len(changeset.Changes), from.ID, to.ID)
 	return changeset, nil
 }
 
-// compareRoutes detects route changes.
-func (fm *FileManager) compareRoutes(from, to *models.RouteList, changeset *models.ChangeSet) {
+// routeChanges adds the route changes between two lists to changeset.
+func routeChanges(from, to *models.RouteList, changeset *models.ChangeSet) {
 	fromMap := from.ByID()
 	toMap := to.ByID()
 
@@ -290,12 +372,12 @@ func (fm *FileManager) compareRoutes(from, to *models.RouteList, changeset *mode
 	}
 }
 
-// compareContacts detects contact changes.
-func (fm *FileManager) compareContacts(from, to *models.ContactList, changeset *models.ChangeSet) {
+// contactChanges adds the contact changes between two lists to changeset.
+func contactChanges(from, to *models.ContactList, changeset *models.ChangeSet) {
 	fromMap := from.ByID()
 	toMap := to.ByID()
 
-	// Similar logic to compareRoutes
+	// Similar logic to routeChanges
 	for id, oldContact := range fromMap {
 		if newContact, exists := toMap[id]; exists {
 			if !contactsEqual(oldContact, newContact) {
@@ -336,8 +418,5 @@ func (fm *FileManager) compareContacts(from, to *models.ContactList, changeset *
 
 // Close releases resources.
 func (fm *FileManager) Close() error {
-	if fm.lock != nil {
-		return fm.lock.Unlock()
-	}
-	return nil
+	return errors.Join(fm.lock.file.Close(), fm.snapshots.close())
 }
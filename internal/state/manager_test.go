@@ -2,7 +2,11 @@ package state
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -82,6 +86,49 @@ func TestFileManager(t *testing.T) {
 		}
 	})
 
+	t.Run("ListSnapshotsRebuildsCatalog", func(t *testing.T) {
+		dir := t.TempDir()
+		mgr, err := NewFileManager(dir, logger)
+		if err != nil {
+			t.Fatalf("NewFileManager() failed: %v", err)
+		}
+		defer mgr.Close()
+
+		snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "indexed")
+		snapshot.Routes = models.NewRouteList([]models.RouteObject{
+			{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+		})
+		if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+			t.Fatalf("SaveSnapshot() failed: %v", err)
+		}
+		if _, err := mgr.ListSnapshots(ctx); err != nil {
+			t.Fatalf("ListSnapshots() failed: %v", err)
+		}
+
+		// A snapshot copied in by hand is picked up without a catalog entry
+		copied := models.NewSnapshot(models.SnapshotTypeContact, "copied")
+		copied.Contacts = models.NewContactList([]models.Contact{})
+		if err := mgr.writeDocument(ctx, copied.ID+".json", mustMarshal(t, copied)); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Remove(filepath.Join(dir, snapshotCatalogFile)); err != nil {
+			t.Fatalf("Expected the listing to write a catalog: %v", err)
+		}
+
+		snapshots, err := mgr.ListSnapshots(ctx)
+		if err != nil {
+			t.Fatalf("ListSnapshots() failed: %v", err)
+		}
+		if len(snapshots) != 2 || snapshots[0].ID != copied.ID || snapshots[1].ItemCount() != 1 {
+			t.Errorf("Expected the rebuilt listing to hold both snapshots with counts, got %+v", snapshots)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, snapshotCatalogFile)); err != nil {
+			t.Errorf("Expected the rebuilt catalog to be saved: %v", err)
+		}
+	})
+
 	t.Run("DeleteSnapshot", func(t *testing.T) {
 		snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "to delete")
 		snapshot.Routes = models.NewRouteList([]models.RouteObject{})
@@ -100,6 +147,34 @@ func TestFileManager(t *testing.T) {
 		}
 	})
 
+	t.Run("GetLatestSnapshotSkipsScoped", func(t *testing.T) {
+		full := models.NewSnapshot(models.SnapshotTypeContact, "full")
+		full.Contacts = models.NewContactList([]models.Contact{})
+		if err := mgr.SaveSnapshot(ctx, full); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(time.Millisecond * 10)
+		scoped := models.NewScopedSnapshot(models.SnapshotTypeContact, "scoped", map[string]string{"mnt-by": "MAINT-TEST"})
+		scoped.Contacts = models.NewContactList([]models.Contact{})
+		if err := mgr.SaveSnapshot(ctx, scoped); err != nil {
+			t.Fatal(err)
+		}
+
+		if scoped.Scope() != "mnt-by=MAINT-TEST" {
+			t.Errorf("Expected scope mnt-by=MAINT-TEST, got %q", scoped.Scope())
+		}
+
+		latest, err := mgr.GetLatestSnapshot(ctx, models.SnapshotTypeContact)
+		if err != nil {
+			t.Fatalf("GetLatestSnapshot() failed: %v", err)
+		}
+
+		if latest.ID != full.ID {
+			t.Errorf("Expected latest full snapshot %s, got %s", full.ID, latest.ID)
+		}
+	})
+
 	t.Run("ComputeChanges", func(t *testing.T) {
 		// Create two snapshots with differences
 		snap1 := models.NewSnapshot(models.SnapshotTypeRoute, "snapshot 1")
@@ -143,6 +218,30 @@ func TestFileManager(t *testing.T) {
 	})
 }
 
+func TestSaveSnapshotRefusesTruncated(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	mgr, err := NewFileManager(t.TempDir(), logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer mgr.Close()
+
+	snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "capped")
+	snapshot.Routes = models.NewRouteList([]models.RouteObject{
+		{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+	})
+	snapshot.Routes.Truncated = true
+
+	if err := mgr.SaveSnapshot(context.Background(), snapshot); err == nil {
+		t.Fatal("SaveSnapshot() should refuse a truncated route list")
+	}
+	if _, err := mgr.LoadSnapshot(context.Background(), snapshot.ID); err == nil {
+		t.Error("A refused snapshot should not be stored")
+	}
+}
+
 func TestSnapshotIntegrity(t *testing.T) {
 	snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "test")
 	snapshot.Routes = models.NewRouteList([]models.RouteObject{
@@ -170,3 +269,67 @@ func TestSnapshotIntegrity(t *testing.T) {
 		t.Error("Expected checksum verification to fail after modification")
 	}
 }
+
+func TestSnapshotIntegrityOptions(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	for _, algorithm := range []string{models.ChecksumSHA256, models.ChecksumBLAKE3} {
+		t.Run(algorithm, func(t *testing.T) {
+			mgr, err := NewFileManager(t.TempDir(), logger)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mgr.Close()
+			mgr.SetIntegrity(Integrity{Algorithm: algorithm, PerObject: true})
+
+			snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "integrity")
+			snapshot.Routes = models.NewRouteList([]models.RouteObject{
+				{Route: "192.0.2.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+				{Route: "198.51.100.0/24", Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Source: "RADB"},
+			})
+			if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+				t.Fatalf("SaveSnapshot() failed: %v", err)
+			}
+
+			loaded, err := mgr.LoadSnapshot(ctx, snapshot.ID)
+			if err != nil {
+				t.Fatalf("LoadSnapshot() failed: %v", err)
+			}
+			if len(loaded.ObjectChecksums) != 2 {
+				t.Fatalf("Expected 2 object checksums, got %d", len(loaded.ObjectChecksums))
+			}
+
+			// Tamper with one route's attributes and move the other
+			loaded.Routes.Routes[0].Descr = []string{"tampered"}
+			loaded.Routes.Routes[1].Origin = "AS64999"
+
+			var integrityErr *models.IntegrityError
+			if err := loaded.VerifyChecksum(); !errors.As(err, &integrityErr) {
+				t.Fatalf("VerifyChecksum() = %v, want an IntegrityError", err)
+			}
+
+			want := &models.IntegrityError{
+				Expected:  integrityErr.Expected,
+				Actual:    integrityErr.Actual,
+				PerObject: true,
+				Changed:   []string{"radb:route:192.0.2.0/24:AS64500"},
+				Missing:   []string{"radb:route:198.51.100.0/24:AS64500"},
+				Added:     []string{"radb:route:198.51.100.0/24:AS64999"},
+			}
+			if !reflect.DeepEqual(integrityErr, want) {
+				t.Errorf("VerifyChecksum() =\n%+v\nwant\n%+v", integrityErr, want)
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
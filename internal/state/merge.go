@@ -0,0 +1,259 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/pkg/tracing"
+)
+
+// ConflictKind describes how two sides changed the same object.
+type ConflictKind string
+
+const (
+	// ConflictBothModified means both sides changed the object differently
+	ConflictBothModified ConflictKind = "both-modified"
+
+	// ConflictBothAdded means both sides added the object with different contents
+	ConflictBothAdded ConflictKind = "both-added"
+
+	// ConflictModifyDelete means one side changed the object and the other deleted it
+	ConflictModifyDelete ConflictKind = "modify-delete"
+)
+
+// Merge sides name where a change came from.
+const (
+	SideOurs   = "ours"
+	SideTheirs = "theirs"
+	SideBoth   = "both" // The same change was made on both sides
+)
+
+// MergeStrategy decides how conflicts are resolved.
+type MergeStrategy string
+
+const (
+	// MergeFail refuses to merge when there are conflicts
+	MergeFail MergeStrategy = "fail"
+
+	// MergeOurs resolves conflicts with our version
+	MergeOurs MergeStrategy = "ours"
+
+	// MergeTheirs resolves conflicts with their version
+	MergeTheirs MergeStrategy = "theirs"
+)
+
+// ThreeWayChange is an object changed on one or both sides since the base.
+// Base, Ours, and Theirs are nil where the object is absent.
+type ThreeWayChange struct {
+	ObjectType string       `json:"object_type"`
+	ID         string       `json:"id"`
+	Side       string       `json:"side,omitempty"`     // Set for changes that merge cleanly
+	Conflict   ConflictKind `json:"conflict,omitempty"` // Set for conflicts
+	Base       interface{}  `json:"base,omitempty"`
+	Ours       interface{}  `json:"ours,omitempty"`
+	Theirs     interface{}  `json:"theirs,omitempty"`
+}
+
+// ThreeWayDiff compares two snapshots that both descend from a base.
+type ThreeWayDiff struct {
+	BaseID    string           `json:"base_id"`
+	OursID    string           `json:"ours_id"`
+	TheirsID  string           `json:"theirs_id"`
+	Changes   []ThreeWayChange `json:"changes"`   // Changes that merge cleanly
+	Conflicts []ThreeWayChange `json:"conflicts"` // Objects changed differently on both sides
+}
+
+// HasConflicts reports whether any object was changed differently on both
+// sides.
+func (d *ThreeWayDiff) HasConflicts() bool {
+	return len(d.Conflicts) > 0
+}
+
+// ComputeThreeWayDiff finds the changes ours and theirs each made since base,
+// and the objects they changed in conflicting ways. The snapshots must have
+// the same type and scope.
+func ComputeThreeWayDiff(ctx context.Context, base, ours, theirs *models.Snapshot) (*ThreeWayDiff, error) {
+	diff, _, err := threeWayMerge(ctx, base, ours, theirs, MergeOurs)
+	return diff, err
+}
+
+// MergeSnapshots combines the changes ours and theirs made since base into a
+// new, unsaved snapshot. Conflicts are resolved by strategy; with MergeFail
+// the diff is returned with an error when there are any.
+func MergeSnapshots(ctx context.Context, base, ours, theirs *models.Snapshot, strategy MergeStrategy, note string) (*models.Snapshot, *ThreeWayDiff, error) {
+	if _, err := ParseMergeStrategy(string(strategy)); err != nil {
+		return nil, nil, err
+	}
+
+	diff, merged, err := threeWayMerge(ctx, base, ours, theirs, strategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if strategy == MergeFail && diff.HasConflicts() {
+		return nil, diff, fmt.Errorf("%d conflicting changes between %s and %s", len(diff.Conflicts), ours.ID, theirs.ID)
+	}
+
+	if note == "" {
+		note = fmt.Sprintf("Merge of %s and %s", ours.ID, theirs.ID)
+	}
+	snapshot := models.NewScopedSnapshot(ours.Type, note, ours.Filters())
+	snapshot.Routes = merged.Routes
+	snapshot.Contacts = merged.Contacts
+	snapshot.Metadata["merge.base"] = base.ID
+	snapshot.Metadata["merge.ours"] = ours.ID
+	snapshot.Metadata["merge.theirs"] = theirs.ID
+	return snapshot, diff, nil
+}
+
+// threeWayMerge computes the three-way diff and the data merged with
+// conflicts resolved by strategy, taking ours for MergeFail.
+func threeWayMerge(ctx context.Context, base, ours, theirs *models.Snapshot, strategy MergeStrategy) (*ThreeWayDiff, *models.Snapshot, error) {
+	if base == nil || ours == nil || theirs == nil {
+		return nil, nil, fmt.Errorf("base, ours, and theirs snapshots must be non-nil")
+	}
+	for _, s := range []*models.Snapshot{ours, theirs} {
+		if s.Type != base.Type || s.Scope() != base.Scope() {
+			return nil, nil, fmt.Errorf("snapshot %s (%s, scope %q) cannot be merged with base %s (%s, scope %q)",
+				s.ID, s.Type, s.Scope(), base.ID, base.Type, base.Scope())
+		}
+	}
+
+	_, span := tracing.Start(ctx, "state.ComputeThreeWayDiff",
+		tracing.String("snapshot.base", base.ID),
+		tracing.String("snapshot.ours", ours.ID),
+		tracing.String("snapshot.theirs", theirs.ID))
+	defer span.End()
+
+	diff := &ThreeWayDiff{
+		BaseID:    base.ID,
+		OursID:    ours.ID,
+		TheirsID:  theirs.ID,
+		Changes:   []ThreeWayChange{},
+		Conflicts: []ThreeWayChange{},
+	}
+	merged := &models.Snapshot{}
+
+	if base.Routes != nil || ours.Routes != nil || theirs.Routes != nil {
+		routes := mergeObjects(diff, "route", routesByID(base.Routes), routesByID(ours.Routes), routesByID(theirs.Routes), routesEqual, strategy)
+		list := make([]models.RouteObject, len(routes))
+		for i, route := range routes {
+			list[i] = *route
+		}
+		merged.Routes = models.NewRouteList(list)
+	}
+	if base.Contacts != nil || ours.Contacts != nil || theirs.Contacts != nil {
+		contacts := mergeObjects(diff, "contact", contactsByID(base.Contacts), contactsByID(ours.Contacts), contactsByID(theirs.Contacts), contactsEqual, strategy)
+		list := make([]models.Contact, len(contacts))
+		for i, contact := range contacts {
+			list[i] = *contact
+		}
+		merged.Contacts = models.NewContactList(list)
+	}
+
+	span.SetAttributes(
+		tracing.Int("merge.changes", len(diff.Changes)),
+		tracing.Int("merge.conflicts", len(diff.Conflicts)))
+
+	return diff, merged, nil
+}
+
+// mergeObjects merges one object type, recording changes and conflicts in
+// diff, and returns the merged objects sorted by ID.
+func mergeObjects[T any](diff *ThreeWayDiff, objectType string, base, ours, theirs map[string]*T, equal func(a, b *T) bool, strategy MergeStrategy) []*T {
+	same := func(a, b *T) bool {
+		if a == nil || b == nil {
+			return a == nil && b == nil
+		}
+		return equal(a, b)
+	}
+	// present converts a missing object to an untyped nil for the report
+	present := func(object *T) interface{} {
+		if object == nil {
+			return nil
+		}
+		return object
+	}
+
+	ids := make(map[string]bool)
+	for _, objects := range []map[string]*T{base, ours, theirs} {
+		for id := range objects {
+			ids[id] = true
+		}
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	var merged []*T
+	for _, id := range sorted {
+		b, o, t := base[id], ours[id], theirs[id]
+		change := ThreeWayChange{ObjectType: objectType, ID: id, Base: present(b), Ours: present(o), Theirs: present(t)}
+
+		var result *T
+		switch {
+		case same(o, t):
+			result = o
+			if !same(o, b) {
+				change.Side = SideBoth
+				diff.Changes = append(diff.Changes, change)
+			}
+		case same(o, b):
+			result = t
+			change.Side = SideTheirs
+			diff.Changes = append(diff.Changes, change)
+		case same(t, b):
+			result = o
+			change.Side = SideOurs
+			diff.Changes = append(diff.Changes, change)
+		default:
+			switch {
+			case b == nil:
+				change.Conflict = ConflictBothAdded
+			case o == nil || t == nil:
+				change.Conflict = ConflictModifyDelete
+			default:
+				change.Conflict = ConflictBothModified
+			}
+			diff.Conflicts = append(diff.Conflicts, change)
+
+			result = o
+			if strategy == MergeTheirs {
+				result = t
+			}
+		}
+
+		if result != nil {
+			merged = append(merged, result)
+		}
+	}
+	return merged
+}
+
+func routesByID(list *models.RouteList) map[string]*models.RouteObject {
+	if list == nil {
+		return nil
+	}
+	return list.ByID()
+}
+
+func contactsByID(list *models.ContactList) map[string]*models.Contact {
+	if list == nil {
+		return nil
+	}
+	return list.ByID()
+}
+
+// ParseMergeStrategy parses a merge strategy name.
+func ParseMergeStrategy(name string) (MergeStrategy, error) {
+	strategy := MergeStrategy(strings.ToLower(name))
+	switch strategy {
+	case MergeFail, MergeOurs, MergeTheirs:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown merge strategy %q (want fail, ours, or theirs)", name)
+}
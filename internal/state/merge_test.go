@@ -0,0 +1,119 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func routeSnapshot(id string, routes ...models.RouteObject) *models.Snapshot {
+	snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "")
+	snapshot.ID = id
+	snapshot.Routes = models.NewRouteList(routes)
+	return snapshot
+}
+
+func TestThreeWayMerge(t *testing.T) {
+	ctx := context.Background()
+
+	route := func(prefix string, descr ...string) models.RouteObject {
+		return models.RouteObject{Route: prefix, Origin: "AS64500", MntBy: []string{"MAINT-TEST"}, Descr: descr, Source: "RADB"}
+	}
+
+	base := routeSnapshot("base",
+		route("192.0.2.0/24", "original"),
+		route("198.51.100.0/24", "original"),
+		route("203.0.113.0/24", "original"),
+	)
+	ours := routeSnapshot("ours",
+		route("192.0.2.0/24", "changed by us"),    // Only ours changed it
+		route("198.51.100.0/24", "changed by us"), // Both changed it differently
+		route("203.0.113.0/24", "original"),       // Theirs deleted it
+		route("192.0.2.128/25", "added by both"),  // Both added it identically
+	)
+	theirs := routeSnapshot("theirs",
+		route("192.0.2.0/24", "original"),
+		route("198.51.100.0/24", "changed by them"),
+		route("192.0.2.128/25", "added by both"),
+		route("100.64.0.0/24", "added by them"), // Only theirs added it
+	)
+
+	diff, err := ComputeThreeWayDiff(ctx, base, ours, theirs)
+	if err != nil {
+		t.Fatalf("ComputeThreeWayDiff() failed: %v", err)
+	}
+
+	sides := make(map[string]string)
+	for _, change := range diff.Changes {
+		sides[change.ID] = change.Side
+	}
+	for id, want := range map[string]string{
+		"192.0.2.0/24-AS64500":   SideOurs,
+		"203.0.113.0/24-AS64500": SideTheirs,
+		"192.0.2.128/25-AS64500": SideBoth,
+		"100.64.0.0/24-AS64500":  SideTheirs,
+	} {
+		if sides[id] != want {
+			t.Errorf("Expected %s to be changed on side %q, got %q", id, want, sides[id])
+		}
+	}
+
+	if len(diff.Conflicts) != 1 || diff.Conflicts[0].ID != "198.51.100.0/24-AS64500" || diff.Conflicts[0].Conflict != ConflictBothModified {
+		t.Fatalf("Expected one both-modified conflict, got %+v", diff.Conflicts)
+	}
+
+	t.Run("FailOnConflict", func(t *testing.T) {
+		merged, diff, err := MergeSnapshots(ctx, base, ours, theirs, MergeFail, "")
+		if err == nil || merged != nil {
+			t.Fatal("Expected the merge to fail on the conflict")
+		}
+		if diff == nil || !diff.HasConflicts() {
+			t.Error("Expected the diff to be returned with the conflicts")
+		}
+	})
+
+	t.Run("ResolveTheirs", func(t *testing.T) {
+		merged, _, err := MergeSnapshots(ctx, base, ours, theirs, MergeTheirs, "")
+		if err != nil {
+			t.Fatalf("MergeSnapshots() failed: %v", err)
+		}
+
+		routes := merged.Routes.ByID()
+		if len(routes) != 4 {
+			t.Errorf("Expected 4 merged routes, got %d", len(routes))
+		}
+		if got := routes["192.0.2.0/24-AS64500"].Descr[0]; got != "changed by us" {
+			t.Errorf("Expected our clean change to be kept, got %q", got)
+		}
+		if got := routes["198.51.100.0/24-AS64500"].Descr[0]; got != "changed by them" {
+			t.Errorf("Expected the conflict resolved with theirs, got %q", got)
+		}
+		if _, ok := routes["203.0.113.0/24-AS64500"]; ok {
+			t.Error("Expected their deletion to be merged")
+		}
+		if merged.Metadata["merge.base"] != "base" {
+			t.Errorf("Expected the merge to record its base, got %v", merged.Metadata)
+		}
+	})
+
+	t.Run("ModifyDelete", func(t *testing.T) {
+		changed := routeSnapshot("changed", route("192.0.2.0/24", "changed"))
+		deleted := routeSnapshot("deleted")
+		diff, err := ComputeThreeWayDiff(ctx, routeSnapshot("base", route("192.0.2.0/24", "original")), changed, deleted)
+		if err != nil {
+			t.Fatalf("ComputeThreeWayDiff() failed: %v", err)
+		}
+		if len(diff.Conflicts) != 1 || diff.Conflicts[0].Conflict != ConflictModifyDelete {
+			t.Errorf("Expected a modify-delete conflict, got %+v", diff.Conflicts)
+		}
+	})
+
+	t.Run("ScopeMismatch", func(t *testing.T) {
+		scoped := models.NewScopedSnapshot(models.SnapshotTypeRoute, "", map[string]string{"origin": "AS64500"})
+		scoped.Routes = models.NewRouteList(nil)
+		if _, err := ComputeThreeWayDiff(ctx, base, ours, scoped); err == nil {
+			t.Error("Expected snapshots with different scopes to be rejected")
+		}
+	})
+}
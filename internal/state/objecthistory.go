@@ -0,0 +1,165 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// ObjectHistory is the lifecycle of one route or contact as recorded in the
+// changelog: every snapshot comparison that added, modified, or removed it.
+type ObjectHistory struct {
+	ObjectType string        `json:"object_type"`
+	ObjectID   string        `json:"object_id"`
+	URN        string        `json:"urn,omitempty"`
+	Exists     bool          `json:"exists"` // Present after the latest change
+	Events     []ObjectEvent `json:"events"`
+}
+
+// ObjectEvent is one change in an object's lifecycle. Fields lists the
+// values that changed: for additions every set field with its new value,
+// for removals every set field with its old value.
+type ObjectEvent struct {
+	EntryID    string               `json:"entry_id"`
+	Timestamp  time.Time            `json:"timestamp"`
+	ChangeType models.ChangeType    `json:"change_type"`
+	SnapshotID string               `json:"snapshot_id"`
+	Note       string               `json:"note,omitempty"`
+	Fields     []models.FieldChange `json:"fields"`
+}
+
+// QueryByObjectID returns every changelog entry for the object with the
+// given ID, oldest first. IDs match case-insensitively, so route origins
+// may be given in either case.
+func (h *HistoryManager) QueryByObjectID(ctx context.Context, objectID string) ([]models.ChangelogEntry, error) {
+	entries, err := h.QueryChanges(ctx, time.Time{}, time.Now(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]models.ChangelogEntry, 0)
+	for _, entry := range entries {
+		if strings.EqualFold(entry.ObjectID, objectID) {
+			matches = append(matches, entry)
+		}
+	}
+	return matches, nil
+}
+
+// GetEntry returns the changelog entry with the given ID. A unique prefix
+// of an ID is accepted, like an abbreviated git commit hash.
+func (h *HistoryManager) GetEntry(ctx context.Context, id string) (*models.ChangelogEntry, error) {
+	if id == "" {
+		return nil, fmt.Errorf("changelog entry ID is required")
+	}
+
+	entries, err := h.QueryChanges(ctx, time.Time{}, time.Now(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var found *models.ChangelogEntry
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+		if strings.HasPrefix(entries[i].ID, id) {
+			if found != nil {
+				return nil, fmt.Errorf("changelog entry ID %s is ambiguous", id)
+			}
+			found = &entries[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("changelog entry %s not found", id)
+	}
+	return found, nil
+}
+
+// BuildObjectHistory turns an object's changelog entries, oldest first, into
+// its lifecycle with field-level before and after values. It returns nil
+// when there are no entries.
+func BuildObjectHistory(entries []models.ChangelogEntry) *ObjectHistory {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	last := entries[len(entries)-1]
+	history := &ObjectHistory{
+		ObjectType: last.ObjectType,
+		ObjectID:   last.ObjectID,
+		URN:        models.ObjectURN(last.ObjectType, last.ObjectID),
+		Exists:     last.ChangeType != models.ChangeTypeRemoved,
+		Events:     make([]ObjectEvent, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		before := decodeObject(entry.ObjectType, entry.Before)
+		after := decodeObject(entry.ObjectType, entry.After)
+
+		var fields []models.FieldChange
+		switch {
+		case before != nil && after != nil:
+			fields = models.DetectFieldChanges(before, after)
+		case after != nil:
+			fields = models.DetectFieldChanges(zeroObject(entry.ObjectType), after)
+		case before != nil:
+			fields = models.DetectFieldChanges(before, zeroObject(entry.ObjectType))
+		}
+		// A missing list and an empty one are the same to a reader
+		changed := make([]models.FieldChange, 0, len(fields))
+		for _, field := range fields {
+			if !isEmptyJSON(field.OldValue) || !isEmptyJSON(field.NewValue) {
+				changed = append(changed, field)
+			}
+		}
+
+		history.Events = append(history.Events, ObjectEvent{
+			EntryID:    entry.EntryID(),
+			Timestamp:  entry.Timestamp,
+			ChangeType: entry.ChangeType,
+			SnapshotID: entry.SnapshotID,
+			Note:       entry.Note,
+			Fields:     changed,
+		})
+	}
+	return history
+}
+
+// decodeObject decodes a changelog object state into its model, or returns
+// nil for an empty state or an unknown object type.
+func decodeObject(objectType string, data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	object := zeroObject(objectType)
+	if object == nil || json.Unmarshal(data, object) != nil {
+		return nil
+	}
+	return object
+}
+
+// zeroObject returns an empty model of an object type, or nil if unknown.
+func zeroObject(objectType string) interface{} {
+	switch objectType {
+	case "route":
+		return &models.RouteObject{}
+	case "contact":
+		return &models.Contact{}
+	}
+	return nil
+}
+
+// isEmptyJSON reports whether a JSON value is null or an empty string,
+// list, or object.
+func isEmptyJSON(value json.RawMessage) bool {
+	switch string(value) {
+	case "", "null", `""`, "[]", "{}":
+		return true
+	}
+	return false
+}
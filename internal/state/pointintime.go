@@ -0,0 +1,98 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// RouteAtTime is a route's recorded state at a point in the past.
+type RouteAtTime struct {
+	// At is the point in time that was resolved
+	At time.Time `json:"at"`
+
+	// SnapshotID is the latest snapshot at or before At that could contain
+	// the route, empty if the state came from the changelog alone
+	SnapshotID string `json:"snapshot_id,omitempty"`
+
+	// Replayed is the number of changelog entries applied after the snapshot
+	Replayed int `json:"replayed"`
+
+	// Route is the route's state, nil if it did not exist at At
+	Route *models.RouteObject `json:"route"`
+}
+
+// RouteAt resolves the state of the route with the given ID at a point in
+// time. It starts from the latest route snapshot taken at or before at that
+// is full scope or contains the route, then replays the route's changelog
+// entries recorded after that snapshot up to at. It fails if neither
+// snapshots nor the changelog record the route before at.
+func RouteAt(ctx context.Context, mgr Manager, history *HistoryManager, id string, at time.Time) (*RouteAtTime, error) {
+	result := &RouteAtTime{At: at}
+	known := false
+
+	snapshots, err := mgr.ListSnapshots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	// Snapshots are sorted newest first
+	var since time.Time
+	for _, meta := range snapshots {
+		if meta.Type != models.SnapshotTypeRoute || meta.Timestamp.After(at) {
+			continue
+		}
+
+		// Listings omit routes, so each candidate is loaded
+		snapshot, err := mgr.LoadSnapshot(ctx, meta.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load snapshot %s: %w", meta.ID, err)
+		}
+		if snapshot.Routes == nil {
+			continue
+		}
+		route, inSnapshot := snapshot.Routes.ByID()[id]
+		if !inSnapshot && !snapshot.IsFullScope() {
+			continue
+		}
+		if inSnapshot {
+			result.Route = route
+		}
+		result.SnapshotID = snapshot.ID
+		since = snapshot.Timestamp
+		known = true
+		break
+	}
+
+	entries, err := history.QueryChanges(ctx, since, at, "route")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changelog: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.ObjectID != id || (known && !entry.Timestamp.After(since)) {
+			continue
+		}
+
+		switch entry.ChangeType {
+		case models.ChangeTypeRemoved:
+			result.Route = nil
+		default:
+			var route models.RouteObject
+			if err := json.Unmarshal(entry.After, &route); err != nil {
+				return nil, fmt.Errorf("failed to parse changelog entry for %s at %s: %w", id, entry.Timestamp.Format(time.RFC3339), err)
+			}
+			result.Route = &route
+		}
+		result.Replayed++
+		known = true
+	}
+
+	if !known {
+		return nil, fmt.Errorf("no snapshot or changelog entry records %s at or before %s", id, at.Format(time.RFC3339))
+	}
+	return result, nil
+}
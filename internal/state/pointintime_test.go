@@ -0,0 +1,77 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRouteAt(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	mgr, err := NewFileManager(dir, logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer mgr.Close()
+	history := NewHistoryManager(dir, logger)
+
+	start := time.Now().Add(-72 * time.Hour).UTC()
+	original := models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"v1"}, MntBy: []string{"MAINT-TEST"}, Source: "RADB"}
+	modified := original
+	modified.Descr = []string{"v2"}
+
+	snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "")
+	snapshot.Timestamp = start
+	snapshot.Routes = models.NewRouteList([]models.RouteObject{original})
+	if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+		t.Fatalf("SaveSnapshot() failed: %v", err)
+	}
+
+	changes := &models.ChangeSet{Changes: []models.Change{
+		{Type: models.ChangeTypeModified, ObjectType: "route", ObjectID: original.ID(), Timestamp: start.Add(24 * time.Hour), Before: original, After: modified},
+		{Type: models.ChangeTypeRemoved, ObjectType: "route", ObjectID: original.ID(), Timestamp: start.Add(48 * time.Hour), Before: modified},
+	}}
+	if err := history.AppendChanges(ctx, changes); err != nil {
+		t.Fatalf("AppendChanges() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		descr    string // empty when the route should not exist
+		replayed int
+	}{
+		{"from snapshot", start.Add(time.Hour), "v1", 0},
+		{"after modification", start.Add(30 * time.Hour), "v2", 1},
+		{"after removal", start.Add(50 * time.Hour), "", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RouteAt(ctx, mgr, history, original.ID(), tt.at)
+			if err != nil {
+				t.Fatalf("RouteAt() failed: %v", err)
+			}
+			if got.SnapshotID != snapshot.ID || got.Replayed != tt.replayed {
+				t.Errorf("RouteAt() used %s + %d entries, want %s + %d", got.SnapshotID, got.Replayed, snapshot.ID, tt.replayed)
+			}
+			switch {
+			case tt.descr == "" && got.Route != nil:
+				t.Errorf("RouteAt() = %+v, want no route", got.Route)
+			case tt.descr != "" && (got.Route == nil || got.Route.Descr[0] != tt.descr):
+				t.Errorf("RouteAt() = %+v, want descr %s", got.Route, tt.descr)
+			}
+		})
+	}
+
+	if _, err := RouteAt(ctx, mgr, history, original.ID(), start.Add(-time.Hour)); err == nil {
+		t.Error("RouteAt() before any history succeeded")
+	}
+}
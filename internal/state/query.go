@@ -0,0 +1,93 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// SnapshotQuery selects snapshots by their metadata. Zero fields match every
+// snapshot.
+type SnapshotQuery struct {
+	Type         models.SnapshotType
+	Scope        string    // Captured with exactly this filter scope, if set
+	Since        time.Time // Taken at or after
+	Until        time.Time // Taken at or before
+	NoteContains string    // Case-insensitive substring of the note
+	Tags         []string  // Every tag must be present
+	MinItems     int       // At least this many routes and contacts
+	MaxItems     int       // At most this many routes and contacts
+	Limit        int       // Return only the newest matches
+}
+
+// Validate checks the query for contradictory bounds.
+func (q SnapshotQuery) Validate() error {
+	if q.MinItems < 0 || q.MaxItems < 0 || q.Limit < 0 {
+		return fmt.Errorf("item bounds and limit must not be negative")
+	}
+	if q.MaxItems > 0 && q.MinItems > q.MaxItems {
+		return fmt.Errorf("min items %d is above max items %d", q.MinItems, q.MaxItems)
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && q.Since.After(q.Until) {
+		return fmt.Errorf("since %s is after until %s", q.Since.Format(time.RFC3339), q.Until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Matches reports whether a listed snapshot satisfies the query.
+func (q SnapshotQuery) Matches(snapshot *models.Snapshot) bool {
+	if q.Type != "" && snapshot.Type != q.Type {
+		return false
+	}
+	if q.Scope != "" && snapshot.Scope() != q.Scope {
+		return false
+	}
+	if !q.Since.IsZero() && snapshot.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && snapshot.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.NoteContains != "" && !strings.Contains(strings.ToLower(snapshot.Note), strings.ToLower(q.NoteContains)) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !snapshot.HasTag(tag) {
+			return false
+		}
+	}
+
+	items := snapshot.ItemCount()
+	if items < q.MinItems || (q.MaxItems > 0 && items > q.MaxItems) {
+		return false
+	}
+	return true
+}
+
+// querySnapshots filters mgr's catalog listing, so no snapshot data is
+// read. Matches are returned newest first.
+func querySnapshots(ctx context.Context, mgr Manager, query SnapshotQuery) ([]models.Snapshot, error) {
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid snapshot query: %w", err)
+	}
+
+	snapshots, err := mgr.ListSnapshots(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]models.Snapshot, 0)
+	for i := range snapshots {
+		if !query.Matches(&snapshots[i]) {
+			continue
+		}
+		matches = append(matches, snapshots[i])
+		if query.Limit > 0 && len(matches) == query.Limit {
+			break
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,419 @@
+// Package statetest provides a conformance suite for state.Manager implementations.
+// Alternative storage backends should pass RunManagerTests before being wired into the CLI.
+package statetest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/bss/radb-client/internal/state"
+)
+
+// Factory returns a fresh, empty Manager for a single subtest.
+// Implementations should register any teardown with t.Cleanup.
+type Factory func(t *testing.T) state.Manager
+
+// RunManagerTests exercises the behavior every state.Manager implementation must provide.
+func RunManagerTests(t *testing.T, newManager Factory) {
+	t.Run("SaveAndLoad", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		snapshot := routeSnapshot("save and load", testRoute("192.0.2.0/24", "AS64500"))
+		if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+			t.Fatalf("SaveSnapshot() failed: %v", err)
+		}
+
+		if snapshot.Checksum == "" {
+			t.Error("SaveSnapshot() should compute the checksum")
+		}
+
+		loaded, err := mgr.LoadSnapshot(ctx, snapshot.ID)
+		if err != nil {
+			t.Fatalf("LoadSnapshot() failed: %v", err)
+		}
+
+		if loaded.ID != snapshot.ID || loaded.Checksum != snapshot.Checksum {
+			t.Errorf("Loaded snapshot %s/%s does not match saved %s/%s",
+				loaded.ID, loaded.Checksum, snapshot.ID, snapshot.Checksum)
+		}
+
+		if loaded.Routes == nil || len(loaded.Routes.Routes) != 1 {
+			t.Error("Routes not loaded correctly")
+		}
+	})
+
+	t.Run("LoadMissing", func(t *testing.T) {
+		mgr := newManager(t)
+
+		if _, err := mgr.LoadSnapshot(context.Background(), "route-missing"); err == nil {
+			t.Error("Expected error loading a missing snapshot")
+		}
+	})
+
+	t.Run("RejectInvalid", func(t *testing.T) {
+		mgr := newManager(t)
+
+		// A route snapshot without routes fails validation
+		snapshot := models.NewSnapshot(models.SnapshotTypeRoute, "invalid")
+		if err := mgr.SaveSnapshot(context.Background(), snapshot); err == nil {
+			t.Error("Expected error saving an invalid snapshot")
+		}
+	})
+
+	t.Run("ListNewestFirst", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		var ids []string
+		for i := 0; i < 3; i++ {
+			snapshot := routeSnapshot("list")
+			if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+				t.Fatalf("SaveSnapshot() failed: %v", err)
+			}
+			ids = append(ids, snapshot.ID)
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		snapshots, err := mgr.ListSnapshots(ctx)
+		if err != nil {
+			t.Fatalf("ListSnapshots() failed: %v", err)
+		}
+
+		if len(snapshots) != 3 {
+			t.Fatalf("Expected 3 snapshots, got %d", len(snapshots))
+		}
+
+		if snapshots[0].ID != ids[2] || snapshots[2].ID != ids[0] {
+			t.Errorf("Snapshots not sorted newest first: %s, %s, %s",
+				snapshots[0].ID, snapshots[1].ID, snapshots[2].ID)
+		}
+	})
+
+	t.Run("ListMetadataOnly", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		kept := routeSnapshot("kept", testRoute("192.0.2.0/24", "AS64500"), testRoute("198.51.100.0/24", "AS64500"))
+		if err := mgr.SaveSnapshot(ctx, kept); err != nil {
+			t.Fatal(err)
+		}
+		deleted := routeSnapshot("deleted", testRoute("203.0.113.0/24", "AS64500"))
+		if err := mgr.SaveSnapshot(ctx, deleted); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := mgr.TagSnapshot(ctx, kept.ID, []string{"baseline"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := mgr.DeleteSnapshot(ctx, deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		snapshots, err := mgr.ListSnapshots(ctx)
+		if err != nil {
+			t.Fatalf("ListSnapshots() failed: %v", err)
+		}
+		if len(snapshots) != 1 || snapshots[0].ID != kept.ID {
+			t.Fatalf("Expected only %s to be listed, got %d snapshots", kept.ID, len(snapshots))
+		}
+
+		listed := snapshots[0]
+		if listed.Routes != nil {
+			t.Error("Listed snapshots should not carry routes")
+		}
+		if listed.ItemCount() != 2 || listed.Note != "kept" || listed.Checksum != kept.Checksum {
+			t.Errorf("Listed snapshot = %d items, note %q, checksum %q; want 2, %q, %q",
+				listed.ItemCount(), listed.Note, listed.Checksum, "kept", kept.Checksum)
+		}
+		if !listed.HasTag("baseline") {
+			t.Errorf("Listed snapshot tags = %v, want baseline", listed.Tags)
+		}
+	})
+
+	t.Run("QuerySnapshots", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		old := routeSnapshot("Before migration", testRoute("192.0.2.0/24", "AS64500"))
+		old.Timestamp = time.Now().Add(-30 * 24 * time.Hour)
+		large := routeSnapshot("Migration step 1", testRoute("192.0.2.0/24", "AS64500"), testRoute("198.51.100.0/24", "AS64500"))
+		small := routeSnapshot("migration step 2", testRoute("203.0.113.0/24", "AS64500"))
+		contacts := models.NewSnapshot(models.SnapshotTypeContact, "Migration contacts")
+		contacts.Contacts = models.NewContactList([]models.Contact{{ID: "C1", Name: "NOC", Email: "noc@example.com"}})
+		for _, snapshot := range []*models.Snapshot{old, large, small, contacts} {
+			if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		if _, err := mgr.TagSnapshot(ctx, small.ID, []string{"migration"}, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		ids := func(query state.SnapshotQuery) []string {
+			t.Helper()
+			snapshots, err := mgr.QuerySnapshots(ctx, query)
+			if err != nil {
+				t.Fatalf("QuerySnapshots(%+v) failed: %v", query, err)
+			}
+			ids := make([]string, 0, len(snapshots))
+			for _, snapshot := range snapshots {
+				ids = append(ids, snapshot.ID)
+			}
+			return ids
+		}
+
+		week := time.Now().Add(-7 * 24 * time.Hour)
+		tests := []struct {
+			name  string
+			query state.SnapshotQuery
+			want  []string
+		}{
+			{"all", state.SnapshotQuery{}, []string{contacts.ID, small.ID, large.ID, old.ID}},
+			{"type and since", state.SnapshotQuery{Type: models.SnapshotTypeRoute, Since: week}, []string{small.ID, large.ID}},
+			{"note", state.SnapshotQuery{NoteContains: "MIGRATION STEP"}, []string{small.ID, large.ID}},
+			{"min items", state.SnapshotQuery{Type: models.SnapshotTypeRoute, MinItems: 2}, []string{large.ID}},
+			{"until", state.SnapshotQuery{Until: week}, []string{old.ID}},
+			{"tag", state.SnapshotQuery{Tags: []string{"migration"}}, []string{small.ID}},
+			{"limit", state.SnapshotQuery{Type: models.SnapshotTypeRoute, Limit: 1}, []string{small.ID}},
+		}
+		for _, tt := range tests {
+			if got := ids(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s: QuerySnapshots() = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+
+		if _, err := mgr.QuerySnapshots(ctx, state.SnapshotQuery{MinItems: 5, MaxItems: 1}); err == nil {
+			t.Error("Expected an error for min items above max items")
+		}
+	})
+
+	t.Run("GetLatestSnapshot", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		if _, err := mgr.GetLatestSnapshot(ctx, models.SnapshotTypeRoute); err == nil {
+			t.Error("Expected error when no snapshots exist")
+		}
+
+		older := routeSnapshot("older")
+		if err := mgr.SaveSnapshot(ctx, older); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		newer := routeSnapshot("newer")
+		if err := mgr.SaveSnapshot(ctx, newer); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		scoped := models.NewScopedSnapshot(models.SnapshotTypeRoute, "scoped", map[string]string{"origin": "AS64500"})
+		scoped.Routes = models.NewRouteList([]models.RouteObject{})
+		if err := mgr.SaveSnapshot(ctx, scoped); err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := mgr.GetLatestSnapshot(ctx, models.SnapshotTypeRoute)
+		if err != nil {
+			t.Fatalf("GetLatestSnapshot() failed: %v", err)
+		}
+
+		if latest.ID != newer.ID {
+			t.Errorf("Expected latest full snapshot %s, got %s", newer.ID, latest.ID)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		snapshot := routeSnapshot("to delete")
+		if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := mgr.DeleteSnapshot(ctx, snapshot.ID); err != nil {
+			t.Fatalf("DeleteSnapshot() failed: %v", err)
+		}
+
+		if _, err := mgr.LoadSnapshot(ctx, snapshot.ID); err == nil {
+			t.Error("Expected error loading deleted snapshot")
+		}
+
+		if err := mgr.DeleteSnapshot(ctx, snapshot.ID); err == nil {
+			t.Error("Expected error deleting a missing snapshot")
+		}
+	})
+
+	t.Run("DeduplicateUnchanged", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		var saved []*models.Snapshot
+		for i := 0; i < 3; i++ {
+			snapshot := routeSnapshot("unchanged", testRoute("192.0.2.0/24", "AS64500"))
+			if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+				t.Fatal(err)
+			}
+			saved = append(saved, snapshot)
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if saved[0].AliasOf != "" || saved[1].AliasOf != saved[0].ID || saved[2].AliasOf != saved[0].ID {
+			t.Fatalf("Unchanged snapshots should alias the first, got %q, %q, %q",
+				saved[0].AliasOf, saved[1].AliasOf, saved[2].AliasOf)
+		}
+
+		// Deleting the data-holding snapshot must keep its aliases loadable
+		if err := mgr.DeleteSnapshot(ctx, saved[0].ID); err != nil {
+			t.Fatalf("DeleteSnapshot() failed: %v", err)
+		}
+		for _, snapshot := range saved[1:] {
+			loaded, err := mgr.LoadSnapshot(ctx, snapshot.ID)
+			if err != nil {
+				t.Fatalf("LoadSnapshot(%s) failed: %v", snapshot.ID, err)
+			}
+			if loaded.Routes == nil || len(loaded.Routes.Routes) != 1 {
+				t.Errorf("Snapshot %s lost its routes", snapshot.ID)
+			}
+		}
+
+		snapshots, err := mgr.ListSnapshots(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, snapshot := range snapshots {
+			if snapshot.ItemCount() != 1 {
+				t.Errorf("Listed snapshot %s has %d items, want 1", snapshot.ID, snapshot.ItemCount())
+			}
+		}
+
+		changed := routeSnapshot("changed", testRoute("198.51.100.0/24", "AS64500"))
+		if err := mgr.SaveSnapshot(ctx, changed); err != nil {
+			t.Fatal(err)
+		}
+		if changed.AliasOf != "" {
+			t.Errorf("Changed snapshot should store its own data, aliases %s", changed.AliasOf)
+		}
+	})
+
+	t.Run("TagsProtectFromCleanup", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		var ids []string
+		for i := 0; i < 3; i++ {
+			snapshot := routeSnapshot("tags")
+			if err := mgr.SaveSnapshot(ctx, snapshot); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, snapshot.ID)
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		tags, err := mgr.TagSnapshot(ctx, ids[0], []string{"baseline", "pre-migration"}, nil)
+		if err != nil {
+			t.Fatalf("TagSnapshot() failed: %v", err)
+		}
+		if tags, err = mgr.TagSnapshot(ctx, ids[0], nil, []string{"pre-migration"}); err != nil || len(tags) != 1 || tags[0] != "baseline" {
+			t.Fatalf("TagSnapshot() = %v, %v; want [baseline]", tags, err)
+		}
+
+		if _, err := mgr.TagSnapshot(ctx, "route-missing", []string{"baseline"}, nil); err == nil {
+			t.Error("Expected error tagging a missing snapshot")
+		}
+
+		result, err := mgr.Cleanup(ctx, state.CleanupOptions{KeepCount: 1})
+		if err != nil {
+			t.Fatalf("Cleanup() failed: %v", err)
+		}
+		if result.Deleted != 1 || len(result.ProtectedIDs) != 1 || result.ProtectedIDs[0] != ids[0] {
+			t.Errorf("Expected 1 deleted and %s protected, got %d and %v", ids[0], result.Deleted, result.ProtectedIDs)
+		}
+
+		loaded, err := mgr.LoadSnapshot(ctx, ids[0])
+		if err != nil {
+			t.Fatalf("Tagged snapshot was not kept: %v", err)
+		}
+		if !loaded.HasTag("baseline") {
+			t.Errorf("Loaded snapshot tags = %v, want baseline", loaded.Tags)
+		}
+	})
+
+	t.Run("ComputeChanges", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		modified := testRoute("192.0.2.0/24", "AS64500")
+		modified.Descr = []string{"changed"}
+
+		from := routeSnapshot("from", testRoute("192.0.2.0/24", "AS64500"), testRoute("198.51.100.0/24", "AS64501"))
+		to := routeSnapshot("to", modified, testRoute("203.0.113.0/24", "AS64502"))
+
+		changeset, err := mgr.ComputeChanges(ctx, from, to)
+		if err != nil {
+			t.Fatalf("ComputeChanges() failed: %v", err)
+		}
+
+		want := map[models.ChangeType]int{
+			models.ChangeTypeAdded:    1,
+			models.ChangeTypeRemoved:  1,
+			models.ChangeTypeModified: 1,
+		}
+		for changeType, count := range want {
+			if changeset.Summary[changeType] != count {
+				t.Errorf("Expected %d %s changes, got %d", count, changeType, changeset.Summary[changeType])
+			}
+		}
+	})
+
+	t.Run("CleanupDryRun", func(t *testing.T) {
+		mgr := newManager(t)
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			if err := mgr.SaveSnapshot(ctx, routeSnapshot("cleanup")); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		result, err := mgr.Cleanup(ctx, state.CleanupOptions{KeepCount: 1, DryRun: true})
+		if err != nil {
+			t.Fatalf("Cleanup() failed: %v", err)
+		}
+
+		if result.Deleted != 2 || result.Kept != 1 {
+			t.Errorf("Expected 2 deleted and 1 kept, got %d and %d", result.Deleted, result.Kept)
+		}
+
+		snapshots, err := mgr.ListSnapshots(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(snapshots) != 3 {
+			t.Errorf("Dry run must not delete snapshots, %d remain", len(snapshots))
+		}
+	})
+}
+
+// routeSnapshot builds a route snapshot containing the given routes.
+func routeSnapshot(note string, routes ...models.RouteObject) *models.Snapshot {
+	snapshot := models.NewSnapshot(models.SnapshotTypeRoute, note)
+	snapshot.Routes = models.NewRouteList(routes)
+	return snapshot
+}
+
+// testRoute builds a minimal valid route object.
+func testRoute(prefix, origin string) models.RouteObject {
+	return models.RouteObject{
+		Route:  prefix,
+		Origin: origin,
+		MntBy:  []string{"MAINT-TEST"},
+		Source: "RADB",
+	}
+}
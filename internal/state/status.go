@@ -0,0 +1,112 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// Status files within the state directory.
+const (
+	clientStatusFile = "client-status.json"
+	daemonStatusFile = "daemon-status.json"
+	daemonStateFile  = "daemon-state.json"
+)
+
+// SaveClientStatus records the API client status observed by the last command.
+func SaveClientStatus(stateDir string, status *models.ClientStatus) error {
+	return writeStatusFile(filepath.Join(stateDir, clientStatusFile), status)
+}
+
+// LoadClientStatus returns the last recorded API client status, or nil if none was recorded.
+func LoadClientStatus(stateDir string) (*models.ClientStatus, error) {
+	var status models.ClientStatus
+	found, err := readStatusFile(filepath.Join(stateDir, clientStatusFile), &status)
+	if !found || err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SaveDaemonStatus records the heartbeat of a running daemon.
+func SaveDaemonStatus(stateDir string, status *models.DaemonStatus) error {
+	return writeStatusFile(filepath.Join(stateDir, daemonStatusFile), status)
+}
+
+// LoadDaemonStatus returns the last daemon heartbeat, or nil if no daemon has run.
+func LoadDaemonStatus(stateDir string) (*models.DaemonStatus, error) {
+	var status models.DaemonStatus
+	found, err := readStatusFile(filepath.Join(stateDir, daemonStatusFile), &status)
+	if !found || err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SaveDaemonState records the runtime state a daemon resumes from after a
+// restart.
+func SaveDaemonState(stateDir string, daemonState *models.DaemonState) error {
+	return writeStatusFile(filepath.Join(stateDir, daemonStateFile), daemonState)
+}
+
+// LoadDaemonState returns the runtime state recorded by the last daemon, or
+// nil if none was recorded.
+func LoadDaemonState(stateDir string) (*models.DaemonState, error) {
+	var daemonState models.DaemonState
+	found, err := readStatusFile(filepath.Join(stateDir, daemonStateFile), &daemonState)
+	if !found || err != nil {
+		return nil, err
+	}
+	return &daemonState, nil
+}
+
+// isStateFile reports whether name is a state file rather than a snapshot.
+func isStateFile(name string) bool {
+	switch name {
+	case annotationsFile, clientStatusFile, daemonStatusFile, daemonStateFile, dedupIndexFile, snapshotCatalogFile:
+		return true
+	}
+	return false
+}
+
+// writeStatusFile saves a status record atomically.
+func writeStatusFile(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write status: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save status: %w", err)
+	}
+
+	return nil
+}
+
+// readStatusFile loads a status record. found is false if the file does not exist.
+func readStatusFile(path string, v interface{}) (found bool, err error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read status: %w", err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to parse status: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestStatusFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	daemonStatus, err := LoadDaemonStatus(tmpDir)
+	if err != nil || daemonStatus != nil {
+		t.Fatalf("LoadDaemonStatus() = %+v, %v; want nil before any daemon ran", daemonStatus, err)
+	}
+
+	now := time.Now()
+	if err := SaveDaemonStatus(tmpDir, &models.DaemonStatus{PID: 42, Command: "daemon", StartedAt: now, HeartbeatAt: now}); err != nil {
+		t.Fatalf("SaveDaemonStatus() failed: %v", err)
+	}
+	if err := SaveClientStatus(tmpDir, &models.ClientStatus{Requests: 3, LastError: "GET /RADB/route: 503"}); err != nil {
+		t.Fatalf("SaveClientStatus() failed: %v", err)
+	}
+	if err := SaveDaemonState(tmpDir, &models.DaemonState{Scopes: map[string]*models.CheckState{"": {LastSnapshotID: "route-1"}}}); err != nil {
+		t.Fatalf("SaveDaemonState() failed: %v", err)
+	}
+
+	daemonStatus, err = LoadDaemonStatus(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadDaemonStatus() failed: %v", err)
+	}
+	if daemonStatus.PID != 42 || !daemonStatus.Alive(now) {
+		t.Errorf("daemon status = %+v, want live pid 42", daemonStatus)
+	}
+	if daemonStatus.Alive(now.Add(time.Hour)) {
+		t.Error("daemon without a recent heartbeat reported alive")
+	}
+
+	clientStatus, err := LoadClientStatus(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadClientStatus() failed: %v", err)
+	}
+	if clientStatus.Requests != 3 || clientStatus.LastError == "" {
+		t.Errorf("client status = %+v, want 3 requests and an error", clientStatus)
+	}
+
+	daemonState, err := LoadDaemonState(tmpDir)
+	if err != nil || daemonState == nil || daemonState.Scopes[""].LastSnapshotID != "route-1" {
+		t.Errorf("LoadDaemonState() = %+v, %v; want the saved snapshot ID", daemonState, err)
+	}
+
+	// Status files must not be mistaken for snapshots
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	fm, err := NewFileManager(tmpDir, logger)
+	if err != nil {
+		t.Fatalf("NewFileManager() failed: %v", err)
+	}
+	defer fm.Close()
+
+	snapshots, err := fm.ListSnapshots(context.Background())
+	if err != nil {
+		t.Fatalf("ListSnapshots() failed: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("ListSnapshots() returned %d entries, want 0", len(snapshots))
+	}
+}
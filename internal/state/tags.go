@@ -0,0 +1,45 @@
+package state
+
+import (
+	"context"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// retagSnapshot updates the tags of a stored snapshot record and returns
+// the resulting tags. Tags are not covered by the checksum, and an alias
+// record stays an alias.
+func retagSnapshot(ctx context.Context, store documentStore, id string, add, remove []string) ([]string, error) {
+	snapshot, err := readRecord(ctx, store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.RemoveTags(remove...)
+	snapshot.AddTags(add...)
+
+	if err := writeRecord(ctx, store, snapshot); err != nil {
+		return nil, err
+	}
+
+	if err := updateCatalog(ctx, store, func(catalog *snapshotCatalog) {
+		if entry, ok := catalog.Snapshots[id]; ok {
+			entry.Tags = snapshot.Tags
+			catalog.Snapshots[id] = entry
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return snapshot.Tags, nil
+}
+
+// tagged returns the IDs of the tagged snapshots.
+func tagged(snapshots []models.Snapshot) map[string]bool {
+	ids := make(map[string]bool)
+	for _, snap := range snapshots {
+		if len(snap.Tags) > 0 {
+			ids[snap.ID] = true
+		}
+	}
+	return ids
+}
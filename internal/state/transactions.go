@@ -0,0 +1,125 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// transactionsDir is the directory of command transactions within the state directory.
+const transactionsDir = "transactions"
+
+// TransactionStore persists the transaction logs of multi-step commands,
+// one file per command. A transaction is removed once it commits or is
+// rolled back, so any left behind belong to interrupted commands.
+type TransactionStore struct {
+	dir    string
+	logger *logrus.Logger
+}
+
+// NewTransactionStore creates a transaction store in the given state directory.
+func NewTransactionStore(stateDir string, logger *logrus.Logger) *TransactionStore {
+	return &TransactionStore{
+		dir:    filepath.Join(stateDir, transactionsDir),
+		logger: logger,
+	}
+}
+
+// Save writes a transaction atomically.
+func (s *TransactionStore) Save(tx *models.Transaction) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create transaction directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	path := s.path(tx.ID)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write transaction: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to save transaction: %w", err)
+	}
+
+	s.logger.Debugf("Saved transaction %s", tx.ID)
+	return nil
+}
+
+// Load reads a transaction by ID.
+func (s *TransactionStore) Load(id string) (*models.Transaction, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid transaction ID: %q", id)
+	}
+
+	data, err := os.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("transaction not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction: %w", err)
+	}
+
+	var tx models.Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to parse transaction %s: %w", id, err)
+	}
+	return &tx, nil
+}
+
+// List returns all transactions, most recently updated first.
+func (s *TransactionStore) List() ([]*models.Transaction, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction directory: %w", err)
+	}
+
+	var transactions []*models.Transaction
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		tx, err := s.Load(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			s.logger.Warnf("Skipping transaction %s: %v", entry.Name(), err)
+			continue
+		}
+		transactions = append(transactions, tx)
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].UpdatedAt.After(transactions[j].UpdatedAt)
+	})
+
+	return transactions, nil
+}
+
+// Delete removes a transaction.
+func (s *TransactionStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("transaction not found: %s", id)
+		}
+		return fmt.Errorf("failed to delete transaction: %w", err)
+	}
+	return nil
+}
+
+// path returns the file path of a transaction.
+func (s *TransactionStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bss/radb-client/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+func TestTransactionStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	store := NewTransactionStore(tmpDir, logger)
+
+	transactions, err := store.List()
+	if err != nil || len(transactions) != 0 {
+		t.Fatalf("List() = %v, %v; want no transactions", transactions, err)
+	}
+
+	before := &models.RouteObject{Route: "192.0.2.0/24", Origin: "AS64500", Descr: []string{"old"}, Source: "RADB"}
+	after := *before
+	after.Descr = []string{"new"}
+
+	tx := models.NewRouteTransaction(models.ChangeTypeModified, before, &after)
+	if tx.NextStep() != models.StepSnapshot {
+		t.Fatalf("NextStep() = %q, want %q", tx.NextStep(), models.StepSnapshot)
+	}
+	tx.CompleteStep(models.StepSnapshot)
+	tx.FailStep(models.StepWrite, errors.New("status 503"))
+
+	if err := store.Save(tx); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	loaded, err := store.Load(tx.ID)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loaded.ObjectID != before.ID() || loaded.Status != models.TransactionPending {
+		t.Fatalf("loaded transaction = %+v, want pending %s", loaded, before.ID())
+	}
+	if loaded.NextStep() != models.StepWrite || loaded.Steps[1].Error != "status 503" {
+		t.Errorf("loaded steps = %+v, want write pending after a 503", loaded.Steps)
+	}
+
+	change := loaded.Change(true)
+	if change.Type != models.ChangeTypeModified || change.Before.(*models.RouteObject).Descr[0] != "new" {
+		t.Errorf("reverse change = %+v, want modified from the new description", change)
+	}
+	if fields, _ := change.Details["field_changes"].([]string); len(fields) != 1 || fields[0] != "Descr" {
+		t.Errorf("field changes = %v, want [Descr]", change.Details["field_changes"])
+	}
+
+	create := models.NewRouteTransaction(models.ChangeTypeAdded, nil, &after)
+	if !create.StepDone(models.StepSnapshot) || create.NextStep() != models.StepWrite {
+		t.Errorf("create steps = %+v, want no snapshot step", create.Steps)
+	}
+	if reverse := create.Change(true); reverse.Type != models.ChangeTypeRemoved || reverse.After != nil {
+		t.Errorf("reverse of create = %+v, want removal", reverse)
+	}
+
+	if _, err := store.Load("../annotations"); err == nil {
+		t.Error("Load() accepted a path outside the transaction directory")
+	}
+
+	if err := store.Delete(tx.ID); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if transactions, _ := store.List(); len(transactions) != 0 {
+		t.Errorf("List() after Delete() = %v, want none", transactions)
+	}
+}
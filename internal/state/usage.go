@@ -0,0 +1,126 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+// usageFile holds telemetry records, one JSON object per line.
+const usageFile = "usage.jsonl"
+
+// RecordUsage appends a command usage record to the state directory.
+func RecordUsage(stateDir string, record *models.UsageRecord) error {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage record: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(stateDir, usageFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open usage file: %w", err)
+	}
+	defer f.Close()
+
+	// A single write keeps concurrent appends from interleaving
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write usage record: %w", err)
+	}
+	return nil
+}
+
+// LoadUsage returns the usage records started at or after since. A zero
+// since returns every record. Unreadable lines are skipped.
+func LoadUsage(stateDir string, since time.Time) ([]models.UsageRecord, error) {
+	f, err := os.Open(filepath.Join(stateDir, usageFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage file: %w", err)
+	}
+	defer f.Close()
+
+	var records []models.UsageRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record models.UsageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if record.StartedAt.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage file: %w", err)
+	}
+	return records, nil
+}
+
+// ClearUsage deletes all usage records.
+func ClearUsage(stateDir string) error {
+	if err := os.Remove(filepath.Join(stateDir, usageFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove usage file: %w", err)
+	}
+	return nil
+}
+
+// SummarizeUsage aggregates usage records per command, most run first.
+func SummarizeUsage(records []models.UsageRecord) []models.UsageSummary {
+	durations := make(map[string][]int64)
+	summaries := make(map[string]*models.UsageSummary)
+	for _, record := range records {
+		summary := summaries[record.Command]
+		if summary == nil {
+			summary = &models.UsageSummary{Command: record.Command}
+			summaries[record.Command] = summary
+		}
+
+		summary.Runs++
+		switch record.Result {
+		case models.UsageResultError:
+			summary.Errors++
+		case models.UsageResultInterrupted:
+			summary.Interrupted++
+		}
+		if record.StartedAt.After(summary.LastRunAt) {
+			summary.LastRunAt = record.StartedAt
+		}
+		durations[record.Command] = append(durations[record.Command], record.DurationMs)
+	}
+
+	result := make([]models.UsageSummary, 0, len(summaries))
+	for command, summary := range summaries {
+		ds := durations[command]
+		sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+
+		var total int64
+		for _, d := range ds {
+			total += d
+		}
+		summary.MeanMs = total / int64(len(ds))
+		summary.P95Ms = ds[(len(ds)*95+99)/100-1]
+		summary.MaxMs = ds[len(ds)-1]
+		result = append(result, *summary)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Runs != result[j].Runs {
+			return result[i].Runs > result[j].Runs
+		}
+		return result[i].Command < result[j].Command
+	})
+	return result
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bss/radb-client/internal/models"
+)
+
+func TestUsageRecords(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	records, err := LoadUsage(tmpDir, time.Time{})
+	if err != nil || records != nil {
+		t.Fatalf("LoadUsage() = %v, %v; want nothing before any command ran", records, err)
+	}
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i, record := range []models.UsageRecord{
+		{Command: "route list", StartedAt: start, DurationMs: 100, Result: models.UsageResultOK},
+		{Command: "route list", StartedAt: start.Add(time.Hour), DurationMs: 300, Result: models.UsageResultError},
+		{Command: "route diff", StartedAt: start.Add(2 * time.Hour), DurationMs: 50, Result: models.UsageResultOK},
+		{Command: "route list", StartedAt: start.Add(3 * time.Hour), DurationMs: 200, Result: models.UsageResultInterrupted},
+	} {
+		if err := RecordUsage(tmpDir, &record); err != nil {
+			t.Fatalf("RecordUsage(%d) failed: %v", i, err)
+		}
+	}
+
+	// A damaged line does not hide the others
+	f, err := os.OpenFile(filepath.Join(tmpDir, usageFile), os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{truncated\n")
+	f.Close()
+
+	records, err = LoadUsage(tmpDir, time.Time{})
+	if err != nil {
+		t.Fatalf("LoadUsage() failed: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("LoadUsage() returned %d records, want 4", len(records))
+	}
+
+	summaries := SummarizeUsage(records)
+	if len(summaries) != 2 || summaries[0].Command != "route list" {
+		t.Fatalf("SummarizeUsage() = %+v, want route list first", summaries)
+	}
+	list := summaries[0]
+	if list.Runs != 3 || list.Errors != 1 || list.Interrupted != 1 {
+		t.Errorf("route list counts = %+v, want 3 runs, 1 error, 1 interrupted", list)
+	}
+	if list.MeanMs != 200 || list.P95Ms != 300 || list.MaxMs != 300 {
+		t.Errorf("route list durations = %+v, want mean 200, p95 300, max 300", list)
+	}
+	if !list.LastRunAt.Equal(start.Add(3 * time.Hour)) {
+		t.Errorf("route list last run = %v, want the latest record", list.LastRunAt)
+	}
+
+	recent, err := LoadUsage(tmpDir, start.Add(90*time.Minute))
+	if err != nil || len(recent) != 2 {
+		t.Errorf("LoadUsage(since) = %d records, %v; want 2", len(recent), err)
+	}
+
+	if err := ClearUsage(tmpDir); err != nil {
+		t.Fatalf("ClearUsage() failed: %v", err)
+	}
+	if records, _ := LoadUsage(tmpDir, time.Time{}); len(records) != 0 {
+		t.Errorf("LoadUsage() after ClearUsage() = %d records, want 0", len(records))
+	}
+}
@@ -82,15 +82,15 @@ Build Information:
 // IsPreRelease returns true if this is a pre-release version
 func IsPreRelease() bool {
 	return GitCommit == "dev" ||
-		   contains(Version, "-pre") ||
-		   contains(Version, "-alpha") ||
-		   contains(Version, "-beta") ||
-		   contains(Version, "-rc")
+		contains(Version, "-pre") ||
+		contains(Version, "-alpha") ||
+		contains(Version, "-beta") ||
+		contains(Version, "-rc")
 }
 
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) &&
-		   (s == substr ||
-		    (len(s) > len(substr) &&
-		     s[len(s)-len(substr):] == substr))
+		(s == substr ||
+			(len(s) > len(substr) &&
+				s[len(s)-len(substr):] == substr))
 }
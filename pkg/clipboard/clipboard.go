@@ -0,0 +1,67 @@
+// Package clipboard places text on the system clipboard using the
+// platform's clipboard command, so no cgo or display libraries are needed.
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard command is installed.
+var ErrUnavailable = errors.New("no clipboard command found (install wl-clipboard, xclip, or xsel)")
+
+// command is a clipboard command that reads the text from stdin.
+type command struct {
+	name string
+	args []string
+}
+
+// commands returns the clipboard commands to try on an operating system,
+// most preferred first.
+func commands(goos string, getenv func(string) string) []command {
+	switch goos {
+	case "darwin":
+		return []command{{name: "pbcopy"}}
+	case "windows":
+		return []command{{name: "clip.exe"}}
+	}
+
+	var cmds []command
+	if getenv("WAYLAND_DISPLAY") != "" {
+		cmds = append(cmds, command{name: "wl-copy"})
+	}
+	cmds = append(cmds,
+		command{name: "xclip", args: []string{"-selection", "clipboard", "-in"}},
+		command{name: "xsel", args: []string{"--clipboard", "--input"}},
+	)
+	// WSL can reach the Windows clipboard without an X server
+	if getenv("WSL_DISTRO_NAME") != "" {
+		cmds = append(cmds, command{name: "clip.exe"})
+	}
+	return append(cmds, command{name: "termux-clipboard-set"})
+}
+
+// Write places text on the system clipboard with the first clipboard
+// command installed.
+func Write(text string) error {
+	for _, c := range commands(runtime.GOOS, os.Getenv) {
+		path, err := exec.LookPath(c.name)
+		if err != nil {
+			continue
+		}
+
+		// Output is discarded rather than captured: xclip keeps running in
+		// the background to serve the selection and would hold a pipe open
+		cmd := exec.Command(path, c.args...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %w", c.name, err)
+		}
+		return nil
+	}
+	return ErrUnavailable
+}
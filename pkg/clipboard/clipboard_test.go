@@ -0,0 +1,33 @@
+package clipboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want string
+	}{
+		{"macOS", "darwin", nil, "pbcopy"},
+		{"Windows", "windows", nil, "clip.exe"},
+		{"X11", "linux", nil, "xclip xsel termux-clipboard-set"},
+		{"Wayland", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, "wl-copy xclip xsel termux-clipboard-set"},
+		{"WSL", "linux", map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}, "xclip xsel clip.exe termux-clipboard-set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, c := range commands(tt.goos, func(key string) string { return tt.env[key] }) {
+				names = append(names, c.name)
+			}
+			if got := strings.Join(names, " "); got != tt.want {
+				t.Errorf("commands(%s) = %s, want %s", tt.goos, got, tt.want)
+			}
+		})
+	}
+}
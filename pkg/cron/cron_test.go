@@ -0,0 +1,76 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2025, 6, 4, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 6, 4, 10, 15, 0, 0, time.UTC)},
+		{"* * * * *", time.Date(2025, 6, 4, 10, 8, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, 6, 5, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 6, 4, 11, 0, 0, 0, time.UTC)},
+		{"0 3 * * sun", time.Date(2025, 6, 8, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2025, 6, 8, 3, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2025, 6, 5, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"5/20 10 * * *", time.Date(2025, 6, 4, 10, 25, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches
+		{"0 0 1 * fri", time.Date(2025, 6, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := schedule.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next() = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	schedule, err := Parse("0 0 30 feb *")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want the zero time for a date that never occurs", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"10-5 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@fortnightly",
+		"@every soon",
+		"@every 10ms",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
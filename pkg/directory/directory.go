@@ -0,0 +1,28 @@
+// Package directory converts contact records to and from the formats used by
+// corporate directories: vCard 3.0 (RFC 2426) and LDIF (RFC 2849).
+package directory
+
+import "strings"
+
+// Person is a directory entry for a person: the subset of attributes that
+// vCard, LDIF, and RADb contacts have in common.
+type Person struct {
+	UID          string
+	Name         string
+	Email        string
+	Phone        string
+	Organization string
+	Role         string
+	Address      []string
+}
+
+// splitName splits a full name into given and family names. The last word
+// is taken as the family name.
+func splitName(name string) (given, family string) {
+	name = strings.TrimSpace(name)
+	i := strings.LastIndex(name, " ")
+	if i < 0 {
+		return "", name
+	}
+	return strings.TrimSpace(name[:i]), name[i+1:]
+}
@@ -0,0 +1,132 @@
+package directory
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVCardRoundTrip(t *testing.T) {
+	people := []Person{
+		{
+			UID:          "CONTACT-1",
+			Name:         "Alice Example",
+			Email:        "alice@example.com",
+			Phone:        "+1 555 0100",
+			Organization: "Example, Inc.",
+			Role:         "tech",
+			Address:      []string{"1 Main St; Suite 2", "Springfield"},
+		},
+		{
+			Name:  "Zoë Müller-" + strings.Repeat("Lange", 20),
+			Email: "zoe@example.com",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteVCards(&buf, people); err != nil {
+		t.Fatalf("WriteVCards() failed: %v", err)
+	}
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > vcardLineLimit+1 {
+			t.Errorf("line not folded (%d octets): %q", len(line), line)
+		}
+	}
+
+	got, err := ReadVCards(&buf)
+	if err != nil {
+		t.Fatalf("ReadVCards() failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, people) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", got, people)
+	}
+}
+
+func TestReadVCardsFromDirectoryExport(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"N:Example;Bob;;;",
+		"item1.EMAIL;TYPE=home:bob@home.example",
+		"EMAIL;TYPE=work,pref:bob@example.com",
+		"TEL;VALUE=uri;TYPE=work:tel:+1-555-0101",
+		"ADR;TYPE=work:;;100 Network Way;Anytown;CA;90210;USA",
+		"NOTE:ignored",
+		"END:VCARD",
+		"",
+	}, "\n")
+
+	got, err := ReadVCards(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadVCards() failed: %v", err)
+	}
+
+	want := []Person{{
+		Name:    "Bob Example",
+		Email:   "bob@example.com",
+		Phone:   "+1-555-0101",
+		Address: []string{"100 Network Way", "Anytown", "CA", "90210", "USA"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadVCardsMalformed(t *testing.T) {
+	inputs := map[string]string{
+		"unterminated": "BEGIN:VCARD\nFN:Alice\n",
+		"no colon":     "BEGIN:VCARD\nFN Alice\nEND:VCARD\n",
+		"nested":       "BEGIN:VCARD\nBEGIN:VCARD\n",
+	}
+	for name, input := range inputs {
+		if _, err := ReadVCards(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWriteLDIF(t *testing.T) {
+	people := []Person{
+		{UID: "CONTACT-1", Name: "Alice Example", Email: "alice@example.com", Organization: "Example, Inc.", Role: "tech", Address: []string{"1 Main St", "Springfield"}},
+		{Name: "Zoë Müller", Email: "zoe@example.com"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLDIF(&buf, people, "ou=radb,dc=example,dc=com"); err != nil {
+		t.Fatalf("WriteLDIF() failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"version: 1\n",
+		"dn: cn=Alice Example,ou=radb,dc=example,dc=com\n",
+		"objectClass: inetOrgPerson\n",
+		"sn: Example\n",
+		"uid: CONTACT-1\n",
+		"o: Example, Inc.\n",
+		"postalAddress: 1 Main St$Springfield\n",
+		"employeeType: tech\n",
+		"cn:: Wm/DqyBNw7xsbGVy\n", // non-ASCII values are base64-encoded
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestEscapeDNValue(t *testing.T) {
+	tests := map[string]string{
+		"Alice Example":   "Alice Example",
+		"Example, Inc.":   `Example\, Inc.`,
+		"#hash":           `\#hash`,
+		" leading":        `\ leading`,
+		`a+b="c"<d>;e\\f`: `a\+b\=\"c\"\<d\>\;e\\\\f`,
+	}
+	for in, want := range tests {
+		if got := escapeDNValue(in); got != want {
+			t.Errorf("escapeDNValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
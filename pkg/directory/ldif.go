@@ -0,0 +1,146 @@
+package directory
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ldifLineLimit is the maximum line length before folding.
+const ldifLineLimit = 76
+
+// WriteLDIF writes people as inetOrgPerson entries under baseDN, e.g.
+// "ou=radb,dc=example,dc=com". Each entry's RDN is its common name.
+func WriteLDIF(w io.Writer, people []Person, baseDN string) error {
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.WriteString("version: 1\n"); err != nil {
+		return err
+	}
+
+	for _, p := range people {
+		_, family := splitName(p.Name)
+		if family == "" {
+			family = p.Name
+		}
+
+		dn := "cn=" + escapeDNValue(p.Name)
+		if baseDN != "" {
+			dn += "," + baseDN
+		}
+
+		attrs := [][2]string{
+			{"dn", dn},
+			{"objectClass", "top"},
+			{"objectClass", "person"},
+			{"objectClass", "organizationalPerson"},
+			{"objectClass", "inetOrgPerson"},
+			{"cn", p.Name},
+			{"sn", family},
+		}
+		if p.UID != "" {
+			attrs = append(attrs, [2]string{"uid", p.UID})
+		}
+		if p.Email != "" {
+			attrs = append(attrs, [2]string{"mail", p.Email})
+		}
+		if p.Phone != "" {
+			attrs = append(attrs, [2]string{"telephoneNumber", p.Phone})
+		}
+		if p.Organization != "" {
+			attrs = append(attrs, [2]string{"o", p.Organization})
+		}
+		if len(p.Address) > 0 {
+			attrs = append(attrs, [2]string{"postalAddress", escapePostalAddress(p.Address)})
+		}
+		if p.Role != "" {
+			attrs = append(attrs, [2]string{"employeeType", p.Role})
+		}
+
+		bw.WriteString("\n")
+		for _, attr := range attrs {
+			if _, err := bw.WriteString(foldLDIF(ldifAttribute(attr[0], attr[1])) + "\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ldifAttribute formats one attribute line, base64-encoding values that are
+// not safe strings as defined by RFC 2849.
+func ldifAttribute(name, value string) string {
+	if isSafeLDIFString(value) {
+		return fmt.Sprintf("%s: %s", name, value)
+	}
+	return fmt.Sprintf("%s:: %s", name, base64.StdEncoding.EncodeToString([]byte(value)))
+}
+
+// isSafeLDIFString reports whether a value can be written without base64.
+func isSafeLDIFString(value string) bool {
+	if value == "" {
+		return true
+	}
+	switch value[0] {
+	case ' ', ':', '<':
+		return false
+	}
+	if strings.HasSuffix(value, " ") || !utf8.ValidString(value) {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c == 0 || c == '\n' || c == '\r' || c > 127 {
+			return false
+		}
+	}
+	return true
+}
+
+// foldLDIF folds a line longer than ldifLineLimit, continuing with a space.
+func foldLDIF(line string) string {
+	if len(line) <= ldifLineLimit {
+		return line
+	}
+
+	var b strings.Builder
+	b.WriteString(line[:ldifLineLimit])
+	for rest := line[ldifLineLimit:]; rest != ""; {
+		n := min(len(rest), ldifLineLimit-1)
+		b.WriteString("\n ")
+		b.WriteString(rest[:n])
+		rest = rest[n:]
+	}
+	return b.String()
+}
+
+// escapeDNValue escapes an attribute value for use in a DN (RFC 4514).
+func escapeDNValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case strings.IndexByte(`,+"\<>;=`, c) >= 0,
+			(c == '#' || c == ' ') && i == 0,
+			c == ' ' && i == len(value)-1:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// escapePostalAddress joins address lines with "$" (RFC 4517), escaping
+// literal "$" and "\" characters.
+func escapePostalAddress(lines []string) string {
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = strings.NewReplacer(`\`, `\5C`, `$`, `\24`).Replace(line)
+	}
+	return strings.Join(escaped, "$")
+}
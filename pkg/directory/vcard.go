@@ -0,0 +1,304 @@
+package directory
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// vcardLineLimit is the maximum line length in octets before folding.
+const vcardLineLimit = 75
+
+// WriteVCards writes people as vCard 3.0 records.
+func WriteVCards(w io.Writer, people []Person) error {
+	bw := bufio.NewWriter(w)
+
+	for _, p := range people {
+		given, family := splitName(p.Name)
+
+		lines := []string{
+			"BEGIN:VCARD",
+			"VERSION:3.0",
+		}
+		if p.UID != "" {
+			lines = append(lines, "UID:"+escapeVCard(p.UID))
+		}
+		lines = append(lines,
+			"FN:"+escapeVCard(p.Name),
+			"N:"+escapeVCard(family)+";"+escapeVCard(given)+";;;",
+		)
+		if p.Organization != "" {
+			lines = append(lines, "ORG:"+escapeVCard(p.Organization))
+		}
+		if p.Email != "" {
+			lines = append(lines, "EMAIL;TYPE=INTERNET:"+escapeVCard(p.Email))
+		}
+		if p.Phone != "" {
+			lines = append(lines, "TEL;TYPE=WORK,VOICE:"+escapeVCard(p.Phone))
+		}
+		if len(p.Address) > 0 {
+			lines = append(lines, "ADR;TYPE=WORK:;;"+escapeVCard(strings.Join(p.Address, "\n"))+";;;;")
+		}
+		if p.Role != "" {
+			lines = append(lines, "ROLE:"+escapeVCard(p.Role))
+		}
+		lines = append(lines, "END:VCARD")
+
+		for _, line := range lines {
+			if _, err := bw.WriteString(foldLine(line) + "\r\n"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadVCards parses vCard 3.0 or 4.0 records. Properties other than those in
+// Person are ignored. Records without a name fall back to their email address.
+func ReadVCards(r io.Reader) ([]Person, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		people  []Person
+		current *Person
+	)
+
+	for n, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		name, params, value, ok := parseContentLine(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: malformed vCard line %q", n+1, line)
+		}
+
+		switch name {
+		case "BEGIN":
+			if !strings.EqualFold(value, "VCARD") {
+				continue
+			}
+			if current != nil {
+				return nil, fmt.Errorf("line %d: nested BEGIN:VCARD", n+1)
+			}
+			current = &Person{}
+			continue
+		case "END":
+			if !strings.EqualFold(value, "VCARD") {
+				continue
+			}
+			if current == nil {
+				return nil, fmt.Errorf("line %d: END:VCARD without BEGIN", n+1)
+			}
+			if current.Name == "" {
+				current.Name = current.Email
+			}
+			people = append(people, *current)
+			current = nil
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			current.UID = unescapeVCard(value)
+		case "FN":
+			current.Name = unescapeVCard(value)
+		case "N":
+			// Only used when FN is missing (FN is required, but not always present)
+			if current.Name == "" {
+				parts := splitVCardValue(value, ';')
+				var given, family string
+				if len(parts) > 0 {
+					family = parts[0]
+				}
+				if len(parts) > 1 {
+					given = parts[1]
+				}
+				current.Name = strings.TrimSpace(given + " " + family)
+			}
+		case "ORG":
+			current.Organization = splitVCardValue(value, ';')[0]
+		case "EMAIL":
+			if current.Email == "" || isPreferred(params) {
+				current.Email = unescapeVCard(value)
+			}
+		case "TEL":
+			if current.Phone == "" || isPreferred(params) {
+				current.Phone = strings.TrimPrefix(unescapeVCard(value), "tel:")
+			}
+		case "ADR":
+			if len(current.Address) == 0 || isPreferred(params) {
+				current.Address = addressLines(splitVCardValue(value, ';'))
+			}
+		case "ROLE":
+			current.Role = unescapeVCard(value)
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("unterminated vCard record")
+	}
+
+	return people, nil
+}
+
+// unfoldLines reads content lines, joining folded continuation lines.
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vCard: %w", err)
+	}
+
+	return lines, nil
+}
+
+// parseContentLine splits "group.NAME;PARAM=x:value" into its upper-cased
+// name, parameters, and value.
+func parseContentLine(line string) (name string, params []string, value string, ok bool) {
+	// The value starts at the first colon outside a quoted parameter value
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	name = strings.ToUpper(parts[0])
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name, parts[1:], line[colon+1:], true
+}
+
+// isPreferred reports whether parameters mark a property as preferred.
+func isPreferred(params []string) bool {
+	for _, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		switch {
+		case strings.EqualFold(key, "PREF"):
+			return true
+		case strings.EqualFold(key, "TYPE"):
+			for _, t := range strings.Split(value, ",") {
+				if strings.EqualFold(strings.Trim(t, `"`), "pref") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// addressLines converts the structured ADR components (post office box,
+// extended address, street, locality, region, postal code, country) to lines.
+func addressLines(parts []string) []string {
+	var lines []string
+	for _, part := range parts {
+		for _, line := range strings.Split(part, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+// splitVCardValue splits a structured value on an unescaped separator and
+// unescapes each component.
+func splitVCardValue(value string, sep byte) []string {
+	var (
+		parts   []string
+		current strings.Builder
+	)
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			current.WriteByte(value[i])
+			current.WriteByte(value[i+1])
+			i++
+		case value[i] == sep:
+			parts = append(parts, unescapeVCard(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+	return append(parts, unescapeVCard(current.String()))
+}
+
+// escapeVCard escapes a text value.
+func escapeVCard(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		"\n", `\n`,
+		",", `\,`,
+		";", `\;`,
+	).Replace(s)
+}
+
+// unescapeVCard reverses escapeVCard.
+func unescapeVCard(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// foldLine folds a content line to at most vcardLineLimit octets per line,
+// without splitting UTF-8 sequences.
+func foldLine(line string) string {
+	if len(line) <= vcardLineLimit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > vcardLineLimit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package grpcwire
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Invoke calls method on the server at target (such as
+// "http://127.0.0.1:8080") and passes each response message to recv, which
+// serves unary and server-streaming calls alike. header carries metadata
+// such as authorization. client must speak HTTP/2; for cleartext servers
+// use a transport with only unencrypted HTTP/2 enabled. The call's status
+// is returned as a *Status error unless it is OK.
+func Invoke(ctx context.Context, client *http.Client, target, method string, header http.Header, req []byte, recv func(msg []byte) error) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, target+method, bytes.NewReader(frame(req)))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		httpReq.Header[key] = values
+	}
+	httpReq.Header.Set("Content-Type", ContentType)
+	httpReq.Header.Set("TE", "trailers")
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Errorf(Unavailable, "unexpected HTTP status %s", resp.Status)
+	}
+
+	for {
+		msg, err := ReadMessage(resp.Body)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := recv(msg); err != nil {
+			return err
+		}
+	}
+
+	// A status sent without messages may arrive in the headers
+	code := resp.Trailer.Get("Grpc-Status")
+	message := resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code = resp.Header.Get("Grpc-Status")
+		message = resp.Header.Get("Grpc-Message")
+	}
+	n, err := strconv.Atoi(code)
+	if err != nil {
+		return Errorf(Internal, "missing or invalid grpc-status %q", code)
+	}
+	if Code(n) == OK {
+		return nil
+	}
+	if unescaped, err := url.PathUnescape(message); err == nil {
+		message = unescaped
+	}
+	return &Status{Code: Code(n), Message: message}
+}
+
+// NewH2CClient returns a client that speaks HTTP/2 without TLS, as gRPC
+// clients do with plaintext servers.
+func NewH2CClient() *http.Client {
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Client{Transport: &http.Transport{Protocols: protocols}}
+}
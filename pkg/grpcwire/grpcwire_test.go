@@ -0,0 +1,134 @@
+package grpcwire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMessageRoundTrip(t *testing.T) {
+	at := time.Date(2025, 6, 4, 10, 7, 30, 500, time.UTC)
+
+	var msg []byte
+	msg = AppendString(msg, 1, "route-1")
+	msg = AppendString(msg, 2, "") // omitted
+	msg = AppendInt(msg, 3, 300)
+	msg = AppendInt(msg, 4, -1)
+	msg = AppendBool(msg, 5, true)
+	msg = AppendStrings(msg, 6, []string{"a", ""})
+	msg = AppendTimestamp(msg, 7, at)
+	msg = AppendMapEntry(msg, 8, "added", 2)
+
+	fields, err := Parse(msg)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	got := make(map[int][]Field)
+	for _, field := range fields {
+		got[field.Number] = append(got[field.Number], field)
+	}
+	if len(got[1]) != 1 || got[1][0].String() != "route-1" {
+		t.Errorf("field 1 = %+v, want route-1", got[1])
+	}
+	if len(got[2]) != 0 {
+		t.Errorf("empty string field was encoded: %+v", got[2])
+	}
+	if got[3][0].Int() != 300 || got[4][0].Int() != -1 || got[5][0].Varint != 1 {
+		t.Errorf("varint fields = %+v %+v %+v", got[3], got[4], got[5])
+	}
+	if len(got[6]) != 2 || got[6][0].String() != "a" || got[6][1].String() != "" {
+		t.Errorf("repeated field = %+v, want a and the empty string", got[6])
+	}
+	if ts, err := ParseTimestamp(got[7][0].Bytes); err != nil || !ts.Equal(at) {
+		t.Errorf("timestamp = %v, %v; want %v", ts, err, at)
+	}
+
+	if _, err := Parse(msg[:len(msg)-1]); err == nil {
+		t.Error("Parse() accepted a truncated message")
+	}
+}
+
+func newTestServer(t *testing.T) string {
+	t.Helper()
+
+	server := NewServer()
+	server.HandleUnary("/test.Echo/Echo", func(ctx context.Context, req []byte) ([]byte, error) {
+		return req, nil
+	})
+	server.HandleStream("/test.Echo/Count", func(ctx context.Context, req []byte, send func([]byte) error) error {
+		fields, err := Parse(req)
+		if err != nil || len(fields) != 1 {
+			return Errorf(InvalidArgument, "want one field")
+		}
+		for i := uint64(1); i <= fields[0].Varint; i++ {
+			if err := send(AppendUint(nil, 1, i)); err != nil {
+				return err
+			}
+		}
+		return Errorf(NotFound, "no more after %d", fields[0].Varint)
+	})
+
+	ts := httptest.NewUnstartedServer(server)
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+func TestInvoke(t *testing.T) {
+	url := newTestServer(t)
+	client := NewH2CClient()
+	ctx := context.Background()
+
+	var echoed []byte
+	req := AppendString(nil, 1, "hello")
+	err := Invoke(ctx, client, url, "/test.Echo/Echo", nil, req, func(msg []byte) error {
+		echoed = msg
+		return nil
+	})
+	if err != nil || string(echoed) != string(req) {
+		t.Errorf("Echo = %q, %v; want %q", echoed, err, req)
+	}
+
+	var counted []uint64
+	err = Invoke(ctx, client, url, "/test.Echo/Count", nil, AppendUint(nil, 1, 3), func(msg []byte) error {
+		fields, err := Parse(msg)
+		if err != nil {
+			return err
+		}
+		counted = append(counted, fields[0].Varint)
+		return nil
+	})
+	if fmt.Sprint(counted) != "[1 2 3]" {
+		t.Errorf("Count streamed %v, want [1 2 3]", counted)
+	}
+	var status *Status
+	if !errors.As(err, &status) || status.Code != NotFound || status.Message != "no more after 3" {
+		t.Errorf("Count status = %v, want NotFound with its message", err)
+	}
+
+	err = Invoke(ctx, client, url, "/test.Echo/Missing", nil, nil, func([]byte) error { return nil })
+	if !errors.As(err, &status) || status.Code != Unimplemented {
+		t.Errorf("unknown method status = %v, want Unimplemented", err)
+	}
+}
+
+func TestServerRejectsHTTP1(t *testing.T) {
+	ts := httptest.NewServer(NewServer())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/test.Echo/Echo", ContentType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("HTTP/1.1 call = %d, want 415", resp.StatusCode)
+	}
+}
@@ -0,0 +1,200 @@
+package grpcwire
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Code is a gRPC status code.
+type Code int
+
+// Status codes, numbered as in gRPC.
+const (
+	OK                Code = 0
+	Canceled          Code = 1
+	Unknown           Code = 2
+	InvalidArgument   Code = 3
+	NotFound          Code = 5
+	ResourceExhausted Code = 8
+	Unimplemented     Code = 12
+	Internal          Code = 13
+	Unavailable       Code = 14
+	Unauthenticated   Code = 16
+)
+
+// Status is an error carrying a gRPC status code.
+type Status struct {
+	Code    Code
+	Message string
+}
+
+// Error implements error.
+func (s *Status) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", s.Code, s.Message)
+}
+
+// Errorf returns a Status error.
+func Errorf(code Code, format string, args ...interface{}) error {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// StatusOf returns the status an error carries, or Unknown for plain errors.
+func StatusOf(err error) *Status {
+	if err == nil {
+		return &Status{Code: OK}
+	}
+	var status *Status
+	if errors.As(err, &status) {
+		return status
+	}
+	return &Status{Code: Unknown, Message: err.Error()}
+}
+
+// maxMessageSize bounds the size of received messages.
+const maxMessageSize = 4 << 20
+
+// ContentType is the content type of gRPC requests and responses.
+const ContentType = "application/grpc"
+
+// UnaryFunc answers a request message with a response message.
+type UnaryFunc func(ctx context.Context, req []byte) ([]byte, error)
+
+// StreamFunc answers a request message with any number of response
+// messages, passed to send in order.
+type StreamFunc func(ctx context.Context, req []byte, send func(msg []byte) error) error
+
+// Server dispatches gRPC calls by full method name, such as
+// "/radb.daemon.v1.DaemonState/GetSnapshot". It is an http.Handler and
+// must be served over HTTP/2.
+type Server struct {
+	unary  map[string]UnaryFunc
+	stream map[string]StreamFunc
+}
+
+// NewServer creates a server with no methods.
+func NewServer() *Server {
+	return &Server{
+		unary:  make(map[string]UnaryFunc),
+		stream: make(map[string]StreamFunc),
+	}
+}
+
+// HandleUnary registers a unary method.
+func (s *Server) HandleUnary(method string, fn UnaryFunc) {
+	s.unary[method] = fn
+}
+
+// HandleStream registers a server-streaming method.
+func (s *Server) HandleStream(method string, fn StreamFunc) {
+	s.stream[method] = fn
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ProtoMajor != 2 || !strings.HasPrefix(r.Header.Get("Content-Type"), ContentType) {
+		http.Error(w, "gRPC requires POST over HTTP/2 with content type application/grpc", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+
+	err := s.serve(w, r)
+	if errors.Is(err, context.Canceled) {
+		err = &Status{Code: Canceled, Message: err.Error()}
+	}
+	setStatus(w.Header(), StatusOf(err))
+}
+
+// WriteStatus answers a call with a status and no messages, for handlers
+// that reject calls before dispatching them to a Server.
+func WriteStatus(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", ContentType)
+	setStatus(w.Header(), StatusOf(err))
+	w.WriteHeader(http.StatusOK)
+}
+
+// setStatus sets the grpc-status and grpc-message fields.
+func setStatus(header http.Header, status *Status) {
+	header.Set("Grpc-Status", strconv.Itoa(int(status.Code)))
+	if status.Message != "" {
+		header.Set("Grpc-Message", url.PathEscape(status.Message))
+	}
+}
+
+// serve reads the request message and runs the method.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) error {
+	unary, isUnary := s.unary[r.URL.Path]
+	stream, isStream := s.stream[r.URL.Path]
+	if !isUnary && !isStream {
+		return Errorf(Unimplemented, "unknown method %s", r.URL.Path)
+	}
+
+	req, err := ReadMessage(r.Body)
+	var status *Status
+	if errors.As(err, &status) {
+		return status
+	}
+	if err != nil {
+		return Errorf(InvalidArgument, "failed to read request: %v", err)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	send := func(msg []byte) error {
+		if _, err := w.Write(frame(msg)); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	if isStream {
+		return stream(r.Context(), req, send)
+	}
+	resp, err := unary(r.Context(), req)
+	if err != nil {
+		return err
+	}
+	return send(resp)
+}
+
+// frame prefixes a message with the uncompressed flag and its length.
+func frame(msg []byte) []byte {
+	b := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(b[1:], uint32(len(msg)))
+	return append(b, msg...)
+}
+
+// ReadMessage reads one length-prefixed message. It returns io.EOF when
+// the stream ends between messages.
+func ReadMessage(r io.Reader) ([]byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errTruncated
+		}
+		return nil, err
+	}
+	if header[0] != 0 {
+		return nil, Errorf(Unimplemented, "compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxMessageSize {
+		return nil, Errorf(ResourceExhausted, "message of %d bytes exceeds %d", size, maxMessageSize)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, errTruncated
+	}
+	return msg, nil
+}
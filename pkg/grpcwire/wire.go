@@ -0,0 +1,203 @@
+// Package grpcwire implements enough of gRPC and the protocol buffer wire
+// format to serve and call services whose messages are encoded by hand:
+// unary and server-streaming calls over HTTP/2, without compression.
+//
+// Messages are built with the Append functions, which follow proto3 and
+// omit fields holding their zero value, and read with Parse.
+package grpcwire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// WireType is the encoding of a field's value.
+type WireType int
+
+// Wire types used by proto3.
+const (
+	Varint  WireType = 0
+	Fixed64 WireType = 1
+	Bytes   WireType = 2
+	Fixed32 WireType = 5
+)
+
+// Field is a decoded field. Varint holds the value of varint and fixed
+// fields, and Bytes that of length-delimited ones.
+type Field struct {
+	Number int
+	Type   WireType
+	Varint uint64
+	Bytes  []byte
+}
+
+// String returns a length-delimited field as a string.
+func (f Field) String() string {
+	return string(f.Bytes)
+}
+
+// Int returns a varint field as a signed integer.
+func (f Field) Int() int64 {
+	return int64(f.Varint)
+}
+
+// AppendTag appends a field's key.
+func AppendTag(b []byte, number int, wireType WireType) []byte {
+	return binary.AppendUvarint(b, uint64(number)<<3|uint64(wireType))
+}
+
+// AppendUint appends a varint field, omitted when zero.
+func AppendUint(b []byte, number int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = AppendTag(b, number, Varint)
+	return binary.AppendUvarint(b, v)
+}
+
+// AppendInt appends an int32 or int64 field, omitted when zero. Negative
+// values take ten bytes, as in protobuf.
+func AppendInt(b []byte, number int, v int64) []byte {
+	return AppendUint(b, number, uint64(v))
+}
+
+// AppendBool appends a bool field, omitted when false.
+func AppendBool(b []byte, number int, v bool) []byte {
+	if !v {
+		return b
+	}
+	return AppendUint(b, number, 1)
+}
+
+// AppendBytes appends a bytes field, omitted when empty.
+func AppendBytes(b []byte, number int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = AppendTag(b, number, Bytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendString appends a string field, omitted when empty.
+func AppendString(b []byte, number int, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = AppendTag(b, number, Bytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// AppendStrings appends a repeated string field, including empty elements.
+func AppendStrings(b []byte, number int, values []string) []byte {
+	for _, v := range values {
+		b = AppendTag(b, number, Bytes)
+		b = binary.AppendUvarint(b, uint64(len(v)))
+		b = append(b, v...)
+	}
+	return b
+}
+
+// AppendMessage appends an embedded message field. Unlike the other
+// Append functions it writes empty messages, which differ from absent ones.
+func AppendMessage(b []byte, number int, msg []byte) []byte {
+	b = AppendTag(b, number, Bytes)
+	b = binary.AppendUvarint(b, uint64(len(msg)))
+	return append(b, msg...)
+}
+
+// AppendTimestamp appends a google.protobuf.Timestamp field, omitted for
+// the zero time.
+func AppendTimestamp(b []byte, number int, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	var msg []byte
+	msg = AppendInt(msg, 1, t.Unix())
+	msg = AppendInt(msg, 2, int64(t.Nanosecond()))
+	return AppendMessage(b, number, msg)
+}
+
+// AppendMapEntry appends an entry of a map<string, int32> or
+// map<string, int64> field.
+func AppendMapEntry(b []byte, number int, key string, value int64) []byte {
+	var entry []byte
+	entry = AppendString(entry, 1, key)
+	entry = AppendInt(entry, 2, value)
+	return AppendMessage(b, number, entry)
+}
+
+// errTruncated reports a message that ends inside a field.
+var errTruncated = errors.New("truncated message")
+
+// Parse decodes the fields of a message in order. Repeated fields appear
+// once per element; groups are not supported.
+func Parse(b []byte) ([]Field, error) {
+	var fields []Field
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		b = b[n:]
+
+		field := Field{Number: int(key >> 3), Type: WireType(key & 7)}
+		if field.Number <= 0 || key>>3 > math.MaxInt32 {
+			return nil, fmt.Errorf("invalid field number %d", key>>3)
+		}
+
+		switch field.Type {
+		case Varint:
+			field.Varint, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errTruncated
+			}
+			b = b[n:]
+		case Fixed64:
+			if len(b) < 8 {
+				return nil, errTruncated
+			}
+			field.Varint = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case Fixed32:
+			if len(b) < 4 {
+				return nil, errTruncated
+			}
+			field.Varint = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case Bytes:
+			size, n := binary.Uvarint(b)
+			if n <= 0 || size > uint64(len(b)-n) {
+				return nil, errTruncated
+			}
+			field.Bytes = b[n : n+int(size)]
+			b = b[n+int(size):]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d for field %d", field.Type, field.Number)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// ParseTimestamp decodes a google.protobuf.Timestamp message.
+func ParseTimestamp(b []byte) (time.Time, error) {
+	fields, err := Parse(b)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var seconds, nanos int64
+	for _, field := range fields {
+		switch field.Number {
+		case 1:
+			seconds = field.Int()
+		case 2:
+			nanos = field.Int()
+		}
+	}
+	return time.Unix(seconds, nanos), nil
+}
@@ -0,0 +1,49 @@
+package keyring
+
+import "fmt"
+
+// Backend selects where a Store keeps credentials.
+type Backend string
+
+const (
+	// BackendAuto uses the system keyring when one is detected and the
+	// encrypted file otherwise
+	BackendAuto Backend = "auto"
+
+	// BackendSystem always tries the system keyring first
+	BackendSystem Backend = "system"
+
+	// BackendFile never touches the system keyring
+	BackendFile Backend = "file"
+)
+
+// ParseBackend parses a backend name. An empty name means BackendAuto.
+func ParseBackend(name string) (Backend, error) {
+	switch Backend(name) {
+	case "", BackendAuto:
+		return BackendAuto, nil
+	case BackendSystem, BackendFile:
+		return Backend(name), nil
+	default:
+		return "", fmt.Errorf("unknown keyring backend %q (want auto, system, or file)", name)
+	}
+}
+
+// useSystemKeyring decides whether a store with the given backend should
+// use the system keyring, and why. Builds without the system keyring never
+// use it; auto only uses it when a keyring service is reachable, so headless
+// hosts skip the probe instead of waiting on D-Bus timeouts.
+func useSystemKeyring(backend Backend) (bool, string) {
+	if !systemKeyringCompiled {
+		return false, "built without system keyring support"
+	}
+
+	switch backend {
+	case BackendFile:
+		return false, "keyring backend set to file"
+	case BackendSystem:
+		return true, "keyring backend set to system"
+	default:
+		return systemKeyringDetected()
+	}
+}
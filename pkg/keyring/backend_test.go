@@ -0,0 +1,47 @@
+package keyring
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Backend
+		wantErr bool
+	}{
+		{"", BackendAuto, false},
+		{"auto", BackendAuto, false},
+		{"system", BackendSystem, false},
+		{"file", BackendFile, false},
+		{"kwallet", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBackend(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBackend(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFileBackendSkipsSystemKeyring(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	store, err := NewStoreWithBackend(logger, filepath.Join(t.TempDir(), "credentials.enc"), BackendFile)
+	if err != nil {
+		t.Fatalf("NewStoreWithBackend() failed: %v", err)
+	}
+	defer store.Close()
+
+	if store.useSystem {
+		t.Fatalf("file backend uses the system keyring: %s", store.Backend())
+	}
+	if _, err := store.Get("user", "password"); err != ErrNotFound {
+		t.Errorf("Get() on an empty store = %v, want ErrNotFound", err)
+	}
+}
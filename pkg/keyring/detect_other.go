@@ -0,0 +1,9 @@
+//go:build !(linux || freebsd || openbsd || netbsd || dragonfly)
+
+package keyring
+
+// systemKeyringDetected reports whether the system keyring can be reached.
+// macOS and Windows always provide one.
+func systemKeyringDetected() (bool, string) {
+	return true, "system keyring available"
+}
@@ -0,0 +1,25 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// systemKeyringDetected reports whether a Secret Service keyring can be
+// reached. On these platforms the keyring lives behind the D-Bus session bus;
+// without one, every keyring call waits for a D-Bus timeout before failing.
+func systemKeyringDetected() (bool, string) {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+		return true, "D-Bus session bus available"
+	}
+
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		if info, err := os.Stat(filepath.Join(runtimeDir, "bus")); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return true, "D-Bus session bus available"
+		}
+	}
+
+	return false, "no D-Bus session bus for the system keyring"
+}
@@ -33,10 +33,10 @@ const (
 
 // credentialStore represents the encrypted credential file structure
 type credentialStore struct {
-	Version int                         `json:"version"`
-	Salt    []byte                      `json:"salt"`
-	Nonce   []byte                      `json:"nonce"`
-	Data    []byte                      `json:"data"` // Encrypted JSON
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"` // Encrypted JSON
 }
 
 // credentialData is the structure of the decrypted data
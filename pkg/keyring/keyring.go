@@ -7,7 +7,6 @@ import (
 	"fmt"
 
 	"github.com/sirupsen/logrus"
-	"github.com/zalando/go-keyring"
 )
 
 const (
@@ -27,13 +26,21 @@ var (
 // It attempts to use the system keyring first, falling back to encrypted
 // file storage if the keyring is unavailable.
 type Store struct {
-	fallback *FileFallback
-	logger   *logrus.Logger
+	fallback     *FileFallback
+	useSystem    bool
+	systemReason string
+	logger       *logrus.Logger
 }
 
 // NewStore creates a new credential store.
 // It attempts to detect keyring availability and initializes the fallback if needed.
 func NewStore(logger *logrus.Logger, fallbackPath string) (*Store, error) {
+	return NewStoreWithBackend(logger, fallbackPath, BackendAuto)
+}
+
+// NewStoreWithBackend creates a credential store using the given backend.
+// Stores that will not use the system keyring never probe it.
+func NewStoreWithBackend(logger *logrus.Logger, fallbackPath string, backend Backend) (*Store, error) {
 	if logger == nil {
 		logger = logrus.New()
 	}
@@ -44,24 +51,41 @@ func NewStore(logger *logrus.Logger, fallbackPath string) (*Store, error) {
 		return nil, fmt.Errorf("failed to initialize fallback storage: %w", err)
 	}
 
+	useSystem, reason := useSystemKeyring(backend)
+	if !useSystem {
+		logger.Debugf("Skipping system keyring (%s), using encrypted file", reason)
+	}
+
 	return &Store{
-		fallback: fallback,
-		logger:   logger,
+		fallback:     fallback,
+		useSystem:    useSystem,
+		systemReason: reason,
+		logger:       logger,
 	}, nil
 }
 
+// Backend describes where the store keeps credentials and why.
+func (s *Store) Backend() string {
+	if s.useSystem {
+		return fmt.Sprintf("system keyring with encrypted file fallback (%s)", s.systemReason)
+	}
+	return fmt.Sprintf("encrypted file (%s)", s.systemReason)
+}
+
 // Set stores a credential with the given key.
 // It attempts to use the system keyring first, falling back to encrypted file storage.
 func (s *Store) Set(user, key, value string) error {
 	// Try system keyring first
-	err := keyring.Set(ServiceName, fmt.Sprintf("%s:%s", user, key), value)
-	if err == nil {
-		s.logger.Debugf("Stored credential %s for user %s in system keyring", key, user)
-		return nil
-	}
+	if s.useSystem {
+		err := systemSet(ServiceName, fmt.Sprintf("%s:%s", user, key), value)
+		if err == nil {
+			s.logger.Debugf("Stored credential %s for user %s in system keyring", key, user)
+			return nil
+		}
 
-	// Log keyring failure and fall back
-	s.logger.Debugf("System keyring unavailable (%v), using encrypted file fallback", err)
+		// Log keyring failure and fall back
+		s.logger.Debugf("System keyring unavailable (%v), using encrypted file fallback", err)
+	}
 
 	// Use encrypted file fallback
 	if err := s.fallback.Set(user, key, value); err != nil {
@@ -76,10 +100,14 @@ func (s *Store) Set(user, key, value string) error {
 // It checks the system keyring first, then falls back to encrypted file storage.
 func (s *Store) Get(user, key string) (string, error) {
 	// Try system keyring first
-	value, err := keyring.Get(ServiceName, fmt.Sprintf("%s:%s", user, key))
-	if err == nil {
-		s.logger.Debugf("Retrieved credential %s for user %s from system keyring", key, user)
-		return value, nil
+	err := ErrKeyringUnavailable
+	if s.useSystem {
+		var value string
+		value, err = systemGet(ServiceName, fmt.Sprintf("%s:%s", user, key))
+		if err == nil {
+			s.logger.Debugf("Retrieved credential %s for user %s from system keyring", key, user)
+			return value, nil
+		}
 	}
 
 	// If not found in keyring, try fallback
@@ -103,9 +131,10 @@ func (s *Store) Delete(user, key string) error {
 	var errs []error
 
 	// Delete from keyring (ignore errors if not present)
-	err := keyring.Delete(ServiceName, fmt.Sprintf("%s:%s", user, key))
-	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
-		errs = append(errs, fmt.Errorf("keyring delete failed: %w", err))
+	if s.useSystem {
+		if err := systemDelete(ServiceName, fmt.Sprintf("%s:%s", user, key)); err != nil {
+			errs = append(errs, fmt.Errorf("keyring delete failed: %w", err))
+		}
 	}
 
 	// Delete from fallback
@@ -124,7 +153,7 @@ func (s *Store) Delete(user, key string) error {
 // DeleteAll removes all credentials for a user.
 func (s *Store) DeleteAll(user string) error {
 	// Common credential keys
-	keys := []string{"password", "api_key", "crypted_password"}
+	keys := []string{"password", "api_key", "crypted_password", "signing_key"}
 
 	var errs []error
 	for _, key := range keys {
@@ -0,0 +1,29 @@
+//go:build !nokeyring
+
+package keyring
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// systemKeyringCompiled reports whether this build includes the system keyring.
+// Build with -tags nokeyring to leave it out on appliances that have none.
+const systemKeyringCompiled = true
+
+func systemSet(service, user, value string) error {
+	return keyring.Set(service, user, value)
+}
+
+func systemGet(service, user string) (string, error) {
+	return keyring.Get(service, user)
+}
+
+// systemDelete removes a secret. A missing secret is not an error.
+func systemDelete(service, user string) error {
+	if err := keyring.Delete(service, user); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
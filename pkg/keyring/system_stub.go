@@ -0,0 +1,18 @@
+//go:build nokeyring
+
+package keyring
+
+// systemKeyringCompiled reports whether this build includes the system keyring.
+const systemKeyringCompiled = false
+
+func systemSet(service, user, value string) error {
+	return ErrKeyringUnavailable
+}
+
+func systemGet(service, user string) (string, error) {
+	return "", ErrKeyringUnavailable
+}
+
+func systemDelete(service, user string) error {
+	return nil
+}
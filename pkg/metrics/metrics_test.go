@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounter("test_requests_total", "Requests sent.", "method", "status")
+	latency := reg.NewHistogram("test_latency_seconds", "Request latency.", []float64{0.1, 1})
+	reg.NewCounter("test_waits_total", "Waits.")
+	rate := reg.NewGauge("test_rate", "Request rate.", "class")
+
+	requests.Inc("GET", "200")
+	requests.Inc("GET", "200")
+	requests.Add(3, "POST", "500")
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(2)
+	rate.Set(60, "read")
+	rate.Set(30, "read")
+
+	if got := requests.Value("GET", "200"); got != 2 {
+		t.Errorf("Value(GET, 200) = %v, want 2", got)
+	}
+	if got := rate.Value("read"); got != 30 {
+		t.Errorf("Value(read) = %v, want 30", got)
+	}
+	if got := latency.Count(); got != 3 {
+		t.Errorf("Count() = %d, want 3", got)
+	}
+
+	var out strings.Builder
+	if err := reg.WriteText(&out); err != nil {
+		t.Fatalf("WriteText() failed: %v", err)
+	}
+
+	want := `# HELP test_latency_seconds Request latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{le="0.1"} 1
+test_latency_seconds_bucket{le="1"} 2
+test_latency_seconds_bucket{le="+Inf"} 3
+test_latency_seconds_sum 2.55
+test_latency_seconds_count 3
+# HELP test_rate Request rate.
+# TYPE test_rate gauge
+test_rate{class="read"} 30
+# HELP test_requests_total Requests sent.
+# TYPE test_requests_total counter
+test_requests_total{method="GET",status="200"} 2
+test_requests_total{method="POST",status="500"} 3
+# HELP test_waits_total Waits.
+# TYPE test_waits_total counter
+`
+	if out.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounter("test_total", "Test.").Inc()
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "test_total 1\n") {
+		t.Errorf("body = %q, want test_total 1", rec.Body.String())
+	}
+}
+
+func TestRegistryRejectsDuplicates(t *testing.T) {
+	reg := NewRegistry()
+	reg.NewCounter("test_total", "Test.")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a name twice did not panic")
+		}
+	}()
+	reg.NewHistogram("test_total", "Test.", nil)
+}
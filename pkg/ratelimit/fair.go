@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+)
+
+// jobKey is the context key of the job a request belongs to.
+type jobKey struct{}
+
+// WithJob returns a context whose requests are scheduled as part of job.
+func WithJob(ctx context.Context, job string) context.Context {
+	return context.WithValue(ctx, jobKey{}, job)
+}
+
+// WithDefaultJob returns ctx unchanged if it already names a job, or a
+// context naming job otherwise.
+func WithDefaultJob(ctx context.Context, job string) context.Context {
+	if JobFromContext(ctx) != "" {
+		return ctx
+	}
+	return WithJob(ctx, job)
+}
+
+// JobFromContext returns the job named by ctx, or "" for none.
+func JobFromContext(ctx context.Context) string {
+	job, _ := ctx.Value(jobKey{}).(string)
+	return job
+}
+
+// FairQueue shares a limiter's request budget between jobs by weighted fair
+// queuing. Waiting requests are admitted to the limiter one at a time in
+// virtual time order: each request of a job advances the job's virtual time
+// by 1/weight, so a job with many queued requests cannot starve one with
+// few, and a job of weight 2 gets twice the share of a job of weight 1
+// while both are waiting. Requests of one job are admitted in order.
+type FairQueue struct {
+	mu      sync.Mutex
+	weights map[string]float64
+	finish  map[string]float64 // Virtual finish time of each job's last request
+	clock   float64            // Virtual time of the last admitted request
+	waiting []*fairWaiter
+	busy    bool // A request holds the turn
+}
+
+// fairWaiter is a request waiting for its turn.
+type fairWaiter struct {
+	job   string
+	tag   float64
+	ready chan struct{}
+}
+
+// NewFairQueue creates a fair queue. Jobs without a weight, or with a
+// weight of zero or less, have weight 1.
+func NewFairQueue(weights map[string]float64) *FairQueue {
+	q := &FairQueue{
+		weights: make(map[string]float64, len(weights)),
+		finish:  make(map[string]float64),
+	}
+	for job, weight := range weights {
+		q.weights[job] = weight
+	}
+	return q
+}
+
+// Weight returns a job's weight.
+func (q *FairQueue) Weight(job string) float64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.weight(job)
+}
+
+// weight returns a job's weight. The caller must hold q.mu.
+func (q *FairQueue) weight(job string) float64 {
+	if weight := q.weights[job]; weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+// Wait waits for the request's turn among the queued requests of every job
+// and then calls wait, typically a limiter's Wait, before letting the next
+// request through. The job is taken from ctx.
+func (q *FairQueue) Wait(ctx context.Context, wait func(context.Context) error) error {
+	job := JobFromContext(ctx)
+
+	q.mu.Lock()
+	start := max(q.clock, q.finish[job])
+	w := &fairWaiter{job: job, tag: start + 1/q.weight(job), ready: make(chan struct{})}
+	q.finish[job] = w.tag
+	q.waiting = append(q.waiting, w)
+	q.dispatch()
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		q.mu.Lock()
+		select {
+		case <-w.ready:
+			// The turn arrived as the context ended; pass it on
+			q.release(w)
+		default:
+			q.remove(w)
+		}
+		q.mu.Unlock()
+		return ctx.Err()
+	}
+
+	err := wait(ctx)
+
+	q.mu.Lock()
+	q.release(w)
+	q.mu.Unlock()
+	return err
+}
+
+// Len returns the number of requests waiting for their turn.
+func (q *FairQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiting)
+}
+
+// dispatch hands the turn to the waiting request with the earliest virtual
+// finish time if no request holds it. The caller must hold q.mu.
+func (q *FairQueue) dispatch() {
+	if q.busy || len(q.waiting) == 0 {
+		return
+	}
+
+	next := 0
+	for i, w := range q.waiting {
+		if w.tag < q.waiting[next].tag {
+			next = i
+		}
+	}
+	w := q.waiting[next]
+	q.waiting = append(q.waiting[:next], q.waiting[next+1:]...)
+	q.busy = true
+	close(w.ready)
+}
+
+// release ends w's turn and hands it to the next request. The caller must
+// hold q.mu.
+func (q *FairQueue) release(w *fairWaiter) {
+	q.clock = max(q.clock, w.tag-1/q.weight(w.job))
+	q.busy = false
+	q.dispatch()
+}
+
+// remove drops a request that gave up before its turn. The caller must
+// hold q.mu.
+func (q *FairQueue) remove(w *fairWaiter) {
+	for i, waiting := range q.waiting {
+		if waiting == w {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			break
+		}
+	}
+	// Don't charge the job for a request that was never made
+	if q.finish[w.job] == w.tag {
+		q.finish[w.job] = w.tag - 1/q.weight(w.job)
+	}
+}
@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// admissionOrder queues requests behind one that holds the turn, releases
+// it, and returns the jobs in the order they were admitted. Requests are
+// queued one at a time, in the order given.
+func admissionOrder(t *testing.T, q *FairQueue, jobs []string) []string {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	record := func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, JobFromContext(ctx))
+		return nil
+	}
+
+	gate := make(chan struct{})
+	held := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		q.Wait(WithJob(context.Background(), "holder"), func(context.Context) error {
+			close(held)
+			<-gate
+			return nil
+		})
+	}()
+	<-held
+
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.Wait(WithJob(context.Background(), job), record); err != nil {
+				t.Errorf("Wait() failed: %v", err)
+			}
+		}()
+		waitForQueue(t, q, i+1)
+	}
+
+	close(gate)
+	wg.Wait()
+	return order
+}
+
+// waitForQueue waits until n requests are queued.
+func waitForQueue(t *testing.T, q *FairQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Len() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue length = %d, want %d", q.Len(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFairQueueInterleavesJobs(t *testing.T) {
+	q := NewFairQueue(nil)
+	jobs := []string{"sync", "sync", "sync", "sync", "watch", "watch"}
+
+	got := admissionOrder(t, q, jobs)
+	want := []string{"sync", "watch", "sync", "watch", "sync", "sync"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("admission order = %v, want %v", got, want)
+	}
+}
+
+func TestFairQueueWeights(t *testing.T) {
+	q := NewFairQueue(map[string]float64{"watch": 4})
+	if q.Weight("watch") != 4 || q.Weight("sync") != 1 {
+		t.Fatalf("weights = %v/%v, want 4/1", q.Weight("watch"), q.Weight("sync"))
+	}
+	jobs := []string{"sync", "sync", "sync", "watch", "watch", "watch"}
+
+	got := admissionOrder(t, q, jobs)
+	want := []string{"watch", "watch", "watch", "sync", "sync", "sync"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("admission order = %v, want %v", got, want)
+	}
+}
+
+func TestFairQueueCancel(t *testing.T) {
+	q := NewFairQueue(nil)
+
+	gate := make(chan struct{})
+	held := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- q.Wait(context.Background(), func(context.Context) error {
+			close(held)
+			<-gate
+			return nil
+		})
+	}()
+	<-held
+
+	ctx, cancel := context.WithCancel(WithJob(context.Background(), "impatient"))
+	cancelled := make(chan error)
+	go func() {
+		cancelled <- q.Wait(ctx, func(context.Context) error {
+			t.Error("cancelled request was admitted")
+			return nil
+		})
+	}()
+	waitForQueue(t, q, 1)
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() = %v, want context.Canceled", err)
+	}
+	if q.Len() != 0 {
+		t.Errorf("cancelled request still queued")
+	}
+
+	close(gate)
+	if err := <-done; err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+
+	// The queue keeps admitting requests
+	if err := q.Wait(context.Background(), func(context.Context) error { return nil }); err != nil {
+		t.Errorf("Wait() after cancellation failed: %v", err)
+	}
+}
@@ -17,13 +17,21 @@ type Limiter struct {
 // New creates a new rate limiter with the specified requests per minute.
 // Default is 60 requests per minute (1 per second).
 func New(requestsPerMinute int) *Limiter {
+	return NewWithBurst(requestsPerMinute, 0)
+}
+
+// NewWithBurst creates a new rate limiter that allows up to burst requests
+// back to back. A burst of zero or less allows 10% of the per-minute rate.
+func NewWithBurst(requestsPerMinute, burst int) *Limiter {
 	if requestsPerMinute <= 0 {
 		requestsPerMinute = 60
 	}
 
 	// Convert requests per minute to requests per second
 	rps := float64(requestsPerMinute) / 60.0
-	burst := requestsPerMinute / 10 // Allow 10% burst capacity
+	if burst <= 0 {
+		burst = requestsPerMinute / 10 // Allow 10% burst capacity
+	}
 
 	if burst < 1 {
 		burst = 1
@@ -40,6 +48,16 @@ func (l *Limiter) Wait(ctx context.Context) error {
 	return l.limiter.Wait(ctx)
 }
 
+// RequestsPerMinute returns the current rate limit.
+func (l *Limiter) RequestsPerMinute() int {
+	return int(float64(l.limiter.Limit())*60 + 0.5)
+}
+
+// Burst returns the number of requests allowed back to back.
+func (l *Limiter) Burst() int {
+	return l.limiter.Burst()
+}
+
 // Allow reports whether an event may happen now.
 // Use this for non-blocking checks.
 func (l *Limiter) Allow() bool {
@@ -80,7 +98,9 @@ func (l *Limiter) WaitN(ctx context.Context, n int) error {
 // AdaptiveLimiter is a rate limiter that adjusts its rate based on API responses.
 type AdaptiveLimiter struct {
 	limiter         *Limiter
+	fair            *FairQueue
 	baseRate        int
+	burst           int // Fixed burst, or 0 to scale with the rate
 	currentRate     int
 	mu              sync.Mutex
 	consecutiveOK   int
@@ -89,24 +109,46 @@ type AdaptiveLimiter struct {
 
 // NewAdaptive creates a new adaptive rate limiter.
 func NewAdaptive(baseRequestsPerMinute int) *AdaptiveLimiter {
+	return NewAdaptiveWithBurst(baseRequestsPerMinute, 0)
+}
+
+// NewAdaptiveWithBurst creates an adaptive rate limiter that allows up to
+// burst requests back to back. A burst of zero or less allows 10% of the
+// base rate.
+func NewAdaptiveWithBurst(baseRequestsPerMinute, burst int) *AdaptiveLimiter {
 	if baseRequestsPerMinute <= 0 {
 		baseRequestsPerMinute = 60
 	}
 
 	return &AdaptiveLimiter{
-		limiter:     New(baseRequestsPerMinute),
+		limiter:     NewWithBurst(baseRequestsPerMinute, burst),
+		fair:        NewFairQueue(nil),
 		baseRate:    baseRequestsPerMinute,
+		burst:       max(burst, 0),
 		currentRate: baseRequestsPerMinute,
 	}
 }
 
-// Wait blocks until the limiter permits an event.
+// Wait blocks until the limiter permits an event. Concurrent waiters are
+// admitted fairly between the jobs named by their contexts; see FairQueue.
 func (al *AdaptiveLimiter) Wait(ctx context.Context) error {
-	return al.limiter.Wait(ctx)
+	al.mu.Lock()
+	fair := al.fair
+	al.mu.Unlock()
+	return fair.Wait(ctx, al.limiter.Wait)
+}
+
+// SetJobWeights sets the weights of jobs sharing the limiter. Jobs without
+// a weight have weight 1.
+func (al *AdaptiveLimiter) SetJobWeights(weights map[string]float64) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.fair = NewFairQueue(weights)
 }
 
 // RecordSuccess records a successful API call.
-// After several consecutive successes, the rate may be increased.
+// After several consecutive successes, a reduced rate is increased again,
+// but never above the base rate.
 func (al *AdaptiveLimiter) RecordSuccess() {
 	al.mu.Lock()
 	defer al.mu.Unlock()
@@ -115,13 +157,13 @@ func (al *AdaptiveLimiter) RecordSuccess() {
 	al.consecutiveWait = 0
 
 	// After 10 consecutive successes, try increasing the rate by 10%
-	if al.consecutiveOK >= 10 && al.currentRate < al.baseRate*2 {
-		newRate := int(float64(al.currentRate) * 1.1)
-		if newRate > al.baseRate*2 {
-			newRate = al.baseRate * 2
+	if al.consecutiveOK >= 10 && al.currentRate < al.baseRate {
+		newRate := max(int(float64(al.currentRate)*1.1), al.currentRate+1)
+		if newRate > al.baseRate {
+			newRate = al.baseRate
 		}
 		al.currentRate = newRate
-		al.limiter.SetRate(newRate)
+		al.setRate(newRate)
 		al.consecutiveOK = 0
 	}
 }
@@ -135,14 +177,12 @@ func (al *AdaptiveLimiter) RecordRateLimit() {
 	al.consecutiveWait++
 	al.consecutiveOK = 0
 
-	// Immediately reduce rate by 50%
-	newRate := al.currentRate / 2
-	if newRate < al.baseRate/4 {
-		newRate = al.baseRate / 4 // Never go below 25% of base rate
-	}
+	// Immediately reduce rate by 50%, never below 25% of the base rate or
+	// one request per minute
+	newRate := max(al.currentRate/2, al.baseRate/4, 1)
 
 	al.currentRate = newRate
-	al.limiter.SetRate(newRate)
+	al.setRate(newRate)
 }
 
 // GetCurrentRate returns the current rate in requests per minute.
@@ -152,6 +192,26 @@ func (al *AdaptiveLimiter) GetCurrentRate() int {
 	return al.currentRate
 }
 
+// setRate changes the underlying rate, keeping a fixed burst. Rates below
+// one request per minute are raised to one, since the limiter would treat
+// them as unset. The caller must hold al.mu.
+func (al *AdaptiveLimiter) setRate(requestsPerMinute int) {
+	al.limiter.SetRate(max(requestsPerMinute, 1))
+	if al.burst > 0 {
+		al.limiter.limiter.SetBurst(al.burst)
+	}
+}
+
+// BaseRate returns the configured rate in requests per minute.
+func (al *AdaptiveLimiter) BaseRate() int {
+	return al.baseRate
+}
+
+// Burst returns the number of requests allowed back to back.
+func (al *AdaptiveLimiter) Burst() int {
+	return al.limiter.Burst()
+}
+
 // Reset resets the adaptive limiter to its base rate.
 func (al *AdaptiveLimiter) Reset() {
 	al.mu.Lock()
@@ -160,5 +220,5 @@ func (al *AdaptiveLimiter) Reset() {
 	al.currentRate = al.baseRate
 	al.consecutiveOK = 0
 	al.consecutiveWait = 0
-	al.limiter.SetRate(al.baseRate)
+	al.setRate(al.baseRate)
 }
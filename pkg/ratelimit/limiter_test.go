@@ -70,14 +70,12 @@ func TestAdaptiveLimiter(t *testing.T) {
 		t.Errorf("Expected initial rate of 60, got %d", limiter.GetCurrentRate())
 	}
 
-	// Record success multiple times
+	// Successes never raise the rate above the base rate
 	for i := 0; i < 20; i++ {
 		limiter.RecordSuccess()
 	}
-
-	// Rate should have increased
-	if limiter.GetCurrentRate() <= 60 {
-		t.Errorf("Expected rate to increase after successes")
+	if limiter.GetCurrentRate() != 60 {
+		t.Errorf("Expected rate to stay at 60 after successes, got %d", limiter.GetCurrentRate())
 	}
 
 	// Record rate limit
@@ -88,6 +86,14 @@ func TestAdaptiveLimiter(t *testing.T) {
 		t.Errorf("Expected rate to decrease after rate limit")
 	}
 
+	// Rate should recover after successes
+	for i := 0; i < 10; i++ {
+		limiter.RecordSuccess()
+	}
+	if limiter.GetCurrentRate() <= 30 {
+		t.Errorf("Expected rate to increase after successes, got %d", limiter.GetCurrentRate())
+	}
+
 	// Reset should restore base rate
 	limiter.Reset()
 	if limiter.GetCurrentRate() != 60 {
@@ -95,6 +101,56 @@ func TestAdaptiveLimiter(t *testing.T) {
 	}
 }
 
+func TestAdaptiveLimiterRecoveryCeiling(t *testing.T) {
+	for _, base := range []int{3, 60, 6000} {
+		limiter := NewAdaptive(base)
+		limiter.RecordRateLimit()
+		limiter.RecordRateLimit()
+
+		for i := 0; i < 1000; i++ {
+			limiter.RecordSuccess()
+			if rate := limiter.GetCurrentRate(); rate > base {
+				t.Fatalf("base %d: rate recovered to %d, above the base rate", base, rate)
+			}
+		}
+		if rate := limiter.GetCurrentRate(); rate != base {
+			t.Errorf("base %d: rate recovered to %d, want the base rate", base, rate)
+		}
+	}
+}
+
+func TestAdaptiveLimiterLowRateFloor(t *testing.T) {
+	tests := []struct {
+		base  int
+		floor int
+	}{
+		{1, 1},
+		{2, 1},
+		{3, 1},
+		{4, 1},
+		{8, 2},
+		{60, 15},
+	}
+
+	for _, tt := range tests {
+		limiter := NewAdaptive(tt.base)
+		for i := 0; i < 5; i++ {
+			limiter.RecordRateLimit()
+
+			current := limiter.GetCurrentRate()
+			if current < tt.floor {
+				t.Fatalf("base %d: rate dropped to %d after %d rate limits, want at least %d", tt.base, current, i+1, tt.floor)
+			}
+			if actual := limiter.limiter.RequestsPerMinute(); actual != current || actual > tt.base {
+				t.Fatalf("base %d: limiter runs at %d/min after %d rate limits, want %d", tt.base, actual, i+1, current)
+			}
+		}
+		if current := limiter.GetCurrentRate(); current != tt.floor {
+			t.Errorf("base %d: rate settled at %d, want %d", tt.base, current, tt.floor)
+		}
+	}
+}
+
 func TestSetRate(t *testing.T) {
 	limiter := New(60)
 
@@ -107,3 +163,44 @@ func TestSetRate(t *testing.T) {
 		t.Fatalf("Wait failed after SetRate: %v", err)
 	}
 }
+
+func TestNewWithBurst(t *testing.T) {
+	limiter := NewWithBurst(30, 3)
+	if limiter.RequestsPerMinute() != 30 || limiter.Burst() != 3 {
+		t.Errorf("NewWithBurst(30, 3) = %d/min burst %d", limiter.RequestsPerMinute(), limiter.Burst())
+	}
+
+	// The burst is available immediately, then requests are limited
+	for i := 0; i < 3; i++ {
+		if !limiter.Allow() {
+			t.Fatalf("request %d of burst was not allowed", i+1)
+		}
+	}
+	if limiter.Allow() {
+		t.Error("request after burst was allowed")
+	}
+
+	if got := NewWithBurst(600, 0).Burst(); got != 60 {
+		t.Errorf("default burst = %d, want 60", got)
+	}
+}
+
+func TestNewAdaptiveWithBurst(t *testing.T) {
+	limiter := NewAdaptiveWithBurst(120, 3)
+	if limiter.BaseRate() != 120 || limiter.Burst() != 3 {
+		t.Fatalf("NewAdaptiveWithBurst(120, 3) = rate %d, burst %d", limiter.BaseRate(), limiter.Burst())
+	}
+
+	// A configured burst survives rate changes
+	limiter.RecordRateLimit()
+	if limiter.GetCurrentRate() != 60 || limiter.Burst() != 3 {
+		t.Errorf("after RecordRateLimit() rate = %d, burst = %d; want 60, 3", limiter.GetCurrentRate(), limiter.Burst())
+	}
+
+	// Without one, the burst scales with the rate
+	scaled := NewAdaptive(600)
+	scaled.RecordRateLimit()
+	if scaled.Burst() != 30 {
+		t.Errorf("scaled burst after RecordRateLimit() = %d, want 30", scaled.Burst())
+	}
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package systemd
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// JournalConnected reports whether f is the stream systemd connected to
+// the journal, as named by JOURNAL_STREAM. Output redirected elsewhere,
+// such as to a file by the unit, is not.
+func JournalConnected(f *os.File) bool {
+	stream := os.Getenv("JOURNAL_STREAM")
+	if stream == "" {
+		return false
+	}
+
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(int(f.Fd()), &stat); err != nil {
+		return false
+	}
+	return stream == fmt.Sprintf("%d:%d", stat.Dev, stat.Ino)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package systemd
+
+import "os"
+
+// JournalConnected reports whether f is connected to the journal, which
+// only exists on Linux.
+func JournalConnected(f *os.File) bool {
+	return false
+}
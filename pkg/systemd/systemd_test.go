@@ -0,0 +1,104 @@
+package systemd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Fatalf("Notify() without a socket = %v, %v; want a no-op", sent, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	if sent, err := Notify(Ready, Status("Checking\nroutes")); !sent || err != nil {
+		t.Fatalf("Notify() = %v, %v", sent, err)
+	}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(buf[:n]), "READY=1\nSTATUS=Checking routes"; got != want {
+		t.Errorf("notification = %q, want %q", got, want)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_PID", "")
+	t.Setenv("WATCHDOG_USEC", "")
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("WatchdogInterval() without a watchdog = %v, want 0", got)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	if got := WatchdogInterval(); got != 30*time.Second {
+		t.Errorf("WatchdogInterval() = %v, want 30s", got)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("WatchdogInterval() for another process = %v, want 0", got)
+	}
+}
+
+func TestJournalFormatter(t *testing.T) {
+	formatter := &JournalFormatter{Identifier: "radb-client"}
+	entry := &logrus.Entry{
+		Level:   logrus.WarnLevel,
+		Message: "Check failed",
+		Data:    logrus.Fields{"snapshot-id": "route-1", "_pid": 1, "2fa": true, "error": "line one\nline two"},
+	}
+
+	data, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want bytes.Buffer
+	want.WriteString("MESSAGE=Check failed\nPRIORITY=4\nSYSLOG_IDENTIFIER=radb-client\n")
+	want.WriteString("FIELD_2FA=true\nPID=1\n")
+	want.WriteString("ERROR\n")
+	binary.Write(&want, binary.LittleEndian, uint64(len("line one\nline two")))
+	want.WriteString("line one\nline two\n")
+	want.WriteString("SNAPSHOT_ID=route-1\n")
+
+	if !bytes.Equal(data, want.Bytes()) {
+		t.Errorf("Format() = %q, want %q", data, want.Bytes())
+	}
+}
+
+func TestJournalConnected(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("JOURNAL_STREAM", "")
+	if JournalConnected(f) {
+		t.Error("JournalConnected() without JOURNAL_STREAM = true")
+	}
+
+	t.Setenv("JOURNAL_STREAM", "0:0")
+	if JournalConnected(f) {
+		t.Error("JournalConnected() for another stream = true")
+	}
+}
@@ -0,0 +1,175 @@
+// Package textdiff computes line-based unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines shown around a change.
+const DefaultContext = 3
+
+// op is the kind of an edit script line.
+type op byte
+
+const (
+	opEqual  op = ' '
+	opDelete op = '-'
+	opInsert op = '+'
+)
+
+// edit is one line of an edit script.
+type edit struct {
+	op   op
+	line string
+}
+
+// Unified returns the unified diff of two texts with context lines of
+// context around each change, or "" if the texts are equal. fromName and
+// toName label the --- and +++ lines; both are omitted when empty.
+func Unified(fromName, toName, from, to string, context int) string {
+	a, b := splitLines(from), splitLines(to)
+	edits := diffLines(a, b)
+
+	changed := false
+	for _, e := range edits {
+		if e.op != opEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var out strings.Builder
+	if fromName != "" || toName != "" {
+		fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	}
+	for _, h := range hunks(edits, context) {
+		out.WriteString(h)
+	}
+	return out.String()
+}
+
+// splitLines splits text into lines without their newlines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns an edit script turning a into b, from the longest
+// common subsequence of their lines. Deletions precede insertions within a
+// change. Objects diffed here are small, so the quadratic table is fine.
+func diffLines(a, b []string) []edit {
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, edit{opEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{opDelete, a[i]})
+			i++
+		default:
+			edits = append(edits, edit{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, edit{opDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, edit{opInsert, b[j]})
+	}
+	return edits
+}
+
+// hunks groups an edit script into unified diff hunks with context lines
+// of context. Changes closer than twice the context share a hunk.
+func hunks(edits []edit, context int) []string {
+	var result []string
+
+	for start := 0; start < len(edits); {
+		// Find the next change
+		first := start
+		for first < len(edits) && edits[first].op == opEqual {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+
+		// Extend the hunk while the gap to the next change is small
+		last := first
+		for k := first; k < len(edits); k++ {
+			if edits[k].op != opEqual {
+				last = k
+			} else if k-last > 2*context {
+				break
+			}
+		}
+
+		from := max(first-context, 0)
+		to := min(last+context+1, len(edits))
+
+		// Line numbers of the hunk in each text
+		aStart, bStart := 1, 1
+		for _, e := range edits[:from] {
+			if e.op != opInsert {
+				aStart++
+			}
+			if e.op != opDelete {
+				bStart++
+			}
+		}
+		var aLen, bLen int
+		var body strings.Builder
+		for _, e := range edits[from:to] {
+			if e.op != opInsert {
+				aLen++
+			}
+			if e.op != opDelete {
+				bLen++
+			}
+			fmt.Fprintf(&body, "%c%s\n", e.op, e.line)
+		}
+
+		result = append(result, fmt.Sprintf("@@ -%s +%s @@\n%s", hunkRange(aStart, aLen), hunkRange(bStart, bLen), body.String()))
+		start = to
+	}
+
+	return result
+}
+
+// hunkRange formats a hunk's start and length as in GNU diff: the length is
+// omitted when it is 1, and an empty range starts at the line before it.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, length)
+	}
+}
@@ -0,0 +1,51 @@
+package textdiff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		context  int
+		want     string
+	}{
+		{
+			name: "equal",
+			from: "route: 192.0.2.0/24\norigin: AS64500\n",
+			to:   "route: 192.0.2.0/24\norigin: AS64500\n",
+			want: "",
+		},
+		{
+			name:    "changed line",
+			from:    "route: 192.0.2.0/24\norigin: AS64500\ndescr: Old\nmnt-by: MAINT-A\nsource: RADB\n",
+			to:      "route: 192.0.2.0/24\norigin: AS64500\ndescr: New\nmnt-by: MAINT-A\nsource: RADB\n",
+			context: 1,
+			want: "--- before\n+++ after\n" +
+				"@@ -2,3 +2,3 @@\n origin: AS64500\n-descr: Old\n+descr: New\n mnt-by: MAINT-A\n",
+		},
+		{
+			name:    "separate hunks",
+			from:    "a\nb\nc\nd\ne\nf\ng\n",
+			to:      "A\nb\nc\nd\ne\nf\ng\nh\n",
+			context: 1,
+			want: "--- before\n+++ after\n" +
+				"@@ -1,2 +1,2 @@\n-a\n+A\n b\n" +
+				"@@ -7 +7,2 @@\n g\n+h\n",
+		},
+		{
+			name:    "from empty",
+			from:    "",
+			to:      "a\n",
+			context: 3,
+			want:    "--- before\n+++ after\n@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("before", "after", tt.from, tt.to, tt.context); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestDisabledTracing(t *testing.T) {
+	ctx := context.Background()
+	spanCtx, span := Start(ctx, "noop")
+	if span != nil || spanCtx != ctx {
+		t.Fatal("Start() created a span with tracing disabled")
+	}
+
+	// Nil spans are safe to use
+	span.SetAttributes(String("k", "v"))
+	span.EndErr(errors.New("ignored"))
+
+	header := http.Header{}
+	Inject(spanCtx, header)
+	if header.Get("traceparent") != "" {
+		t.Error("Inject() set traceparent with tracing disabled")
+	}
+}
+
+func TestTracerExport(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []otlpRequest
+		auth     string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("export sent to %s, want /v1/traces", r.URL.Path)
+		}
+		var req otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid export body: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, req)
+		auth = r.Header.Get("Authorization")
+		mu.Unlock()
+	}))
+	defer collector.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	tracer := NewTracer("radb-test", collector.URL, map[string]string{"Authorization": "Bearer token"}, logger)
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	ctx, parent := Start(context.Background(), "command", String("command", "route list"))
+	childCtx, child := StartKind(ctx, "GET /route", KindClient, Int("http.status_code", 200))
+
+	header := http.Header{}
+	Inject(childCtx, header)
+	if header.Get("traceparent") != child.TraceParent() {
+		t.Errorf("traceparent = %q, want %q", header.Get("traceparent"), child.TraceParent())
+	}
+
+	child.EndErr(errors.New("boom"))
+	parent.End()
+	parent.End()
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 || auth != "Bearer token" {
+		t.Fatalf("got %d export requests (auth %q), want 1 with the configured header", len(requests), auth)
+	}
+
+	resource := requests[0].ResourceSpans[0]
+	if resource.Resource.Attributes[0].Value["stringValue"] != "radb-test" {
+		t.Errorf("service.name = %v", resource.Resource.Attributes[0].Value)
+	}
+	spans := resource.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2 (End is idempotent)", len(spans))
+	}
+
+	exportedChild, exportedParent := spans[0], spans[1]
+	if exportedChild.TraceID != exportedParent.TraceID || exportedChild.ParentSpanID != exportedParent.SpanID {
+		t.Errorf("child %+v is not linked to parent %+v", exportedChild, exportedParent)
+	}
+	if exportedParent.ParentSpanID != "" {
+		t.Errorf("root span has parent %s", exportedParent.ParentSpanID)
+	}
+	if exportedChild.Kind != KindClient || exportedChild.Status.Code != otlpStatusError || exportedChild.Status.Message != "boom" {
+		t.Errorf("child kind/status = %d/%+v, want client and error", exportedChild.Kind, exportedChild.Status)
+	}
+	if exportedChild.Attributes[0].Value["intValue"] != "200" {
+		t.Errorf("child attributes = %+v", exportedChild.Attributes)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	SetTracer(&Tracer{})
+	defer SetTracer(nil)
+
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	_, span := Start(Extract(context.Background(), header), "webhook")
+	if got := span.TraceParent()[3:35]; got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the caller's", got)
+	}
+	if span.parentID != [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7} {
+		t.Errorf("parent ID = %x, want the caller's span", span.parentID)
+	}
+
+	for _, bad := range []string{"", "garbage", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "00-zz-00f067aa0ba902b7-01"} {
+		header.Set("traceparent", bad)
+		ctx := context.Background()
+		if Extract(ctx, header) != ctx {
+			t.Errorf("Extract(%q) accepted an invalid header", bad)
+		}
+	}
+}
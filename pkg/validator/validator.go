@@ -3,6 +3,7 @@
 package validator
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net"
@@ -53,14 +54,16 @@ func ValidatePath(path string) error {
 		return fmt.Errorf("%w: contains null byte", ErrInvalidPath)
 	}
 
+	// Check for path traversal attempts before cleaning resolves them away
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part == ".." {
+			return fmt.Errorf("%w: path contains '..'", ErrPathTraversal)
+		}
+	}
+
 	// Clean the path
 	cleaned := filepath.Clean(path)
 
-	// Check for path traversal attempts
-	if strings.Contains(cleaned, "..") {
-		return fmt.Errorf("%w: path contains '..'", ErrPathTraversal)
-	}
-
 	// Ensure absolute paths don't escape expected boundaries
 	if filepath.IsAbs(cleaned) {
 		// Additional checks could be added here for specific allowed directories
@@ -129,6 +132,88 @@ func ValidatePrefix(prefix string) error {
 	return ValidateIPPrefix(prefix)
 }
 
+// PrefixMode is a set of flags relaxing prefix validation. The zero mode
+// accepts only CIDR network addresses, as required when writing objects.
+type PrefixMode uint8
+
+const (
+	// AllowHostBits accepts CIDR notation with host bits set, such as 192.0.2.1/24
+	AllowHostBits PrefixMode = 1 << iota
+
+	// AllowAddress accepts a bare IPv4 or IPv6 address
+	AllowAddress
+
+	// AllowRange accepts an address range such as "192.0.2.0 - 192.0.2.255"
+	AllowRange
+)
+
+// LookupMode accepts every format the API understands for exact-match lookups.
+const LookupMode = AllowHostBits | AllowAddress | AllowRange
+
+// ValidatePrefixMode validates an IPv4 or IPv6 prefix, also accepting the
+// formats enabled by mode.
+func ValidatePrefixMode(prefix string, mode PrefixMode) error {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return fmt.Errorf("%w: empty prefix", ErrInvalidPrefix)
+	}
+
+	switch {
+	case strings.Contains(prefix, "/"):
+		if mode&AllowHostBits == 0 {
+			return ValidateIPPrefix(prefix)
+		}
+		if _, _, err := net.ParseCIDR(prefix); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPrefix, err)
+		}
+		return nil
+
+	case strings.Contains(prefix, "-"):
+		if mode&AllowRange == 0 {
+			return fmt.Errorf("%w: address ranges are not accepted here, use CIDR notation", ErrInvalidPrefix)
+		}
+		return validateRange(prefix)
+
+	default:
+		if mode&AllowAddress == 0 {
+			return fmt.Errorf("%w: %s is not in CIDR notation", ErrInvalidPrefix, prefix)
+		}
+		if net.ParseIP(prefix) == nil {
+			return fmt.Errorf("%w: invalid address %s", ErrInvalidPrefix, prefix)
+		}
+		return nil
+	}
+}
+
+// ValidateLookupPrefix validates a prefix used to look up existing objects.
+// It accepts CIDR notation with or without host bits, bare addresses, and ranges.
+func ValidateLookupPrefix(prefix string) error {
+	return ValidatePrefixMode(prefix, LookupMode)
+}
+
+// validateRange validates an address range whose ends are in the same
+// address family and in order.
+func validateRange(prefix string) error {
+	parts := strings.SplitN(prefix, "-", 2)
+	first := net.ParseIP(strings.TrimSpace(parts[0]))
+	last := net.ParseIP(strings.TrimSpace(parts[1]))
+	if first == nil || last == nil {
+		return fmt.Errorf("%w: invalid address range %s", ErrInvalidPrefix, prefix)
+	}
+
+	if (first.To4() == nil) != (last.To4() == nil) {
+		return fmt.Errorf("%w: range %s mixes IPv4 and IPv6", ErrInvalidPrefix, prefix)
+	}
+	if first.To4() != nil {
+		first, last = first.To4(), last.To4()
+	}
+	if bytes.Compare(first, last) > 0 {
+		return fmt.Errorf("%w: range %s ends before it starts", ErrInvalidPrefix, prefix)
+	}
+
+	return nil
+}
+
 // ValidateEmail validates an email address using basic regex.
 func ValidateEmail(email string) error {
 	if email == "" {
@@ -190,12 +275,12 @@ func ValidateSource(source string) error {
 
 	// Currently only RADB is supported, but this allows for future expansion
 	validSources := map[string]bool{
-		"RADB":      true,
-		"RIPE":      false, // Future support
-		"ARIN":      false, // Future support
-		"APNIC":     false, // Future support
-		"AFRINIC":   false, // Future support
-		"LACNIC":    false, // Future support
+		"RADB":    true,
+		"RIPE":    false, // Future support
+		"ARIN":    false, // Future support
+		"APNIC":   false, // Future support
+		"AFRINIC": false, // Future support
+		"LACNIC":  false, // Future support
 	}
 
 	upper := strings.ToUpper(source)
@@ -76,6 +76,41 @@ func TestValidateIPPrefix(t *testing.T) {
 	}
 }
 
+func TestValidatePrefixMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		mode    PrefixMode
+		wantErr bool
+	}{
+		{"strict CIDR", "192.0.2.0/24", 0, false},
+		{"strict host bits", "192.0.2.1/24", 0, true},
+		{"strict address", "192.0.2.1", 0, true},
+		{"strict range", "192.0.2.0 - 192.0.2.255", 0, true},
+		{"host bits allowed", "192.0.2.1/24", AllowHostBits, false},
+		{"IPv6 host bits allowed", "2001:db8::1/32", AllowHostBits, false},
+		{"address allowed", "192.0.2.1", AllowAddress, false},
+		{"IPv6 address allowed", "2001:db8::1", AllowAddress, false},
+		{"invalid address", "192.0.2.256", AllowAddress, true},
+		{"range allowed", "192.0.2.0 - 192.0.2.255", AllowRange, false},
+		{"IPv6 range", "2001:db8::-2001:db8::ff", AllowRange, false},
+		{"range out of order", "192.0.2.255 - 192.0.2.0", AllowRange, true},
+		{"range mixes families", "192.0.2.0 - 2001:db8::", AllowRange, true},
+		{"address with range mode only", "192.0.2.1", AllowRange, true},
+		{"lookup empty", "", LookupMode, true},
+		{"lookup invalid CIDR", "192.0.2.0/33", LookupMode, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePrefixMode(tt.prefix, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePrefixMode(%q, %d) error = %v, wantErr %v", tt.prefix, tt.mode, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateEmail(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -422,7 +422,9 @@ After=network-online.target
 Wants=network-online.target
 
 [Service]
-Type=simple
+Type=notify
+NotifyAccess=main
+WatchdogSec=10min
 User=${INSTALL_USER}
 Group=${INSTALL_USER}
 